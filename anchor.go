@@ -0,0 +1,53 @@
+package gofpdf
+
+import (
+	"fmt"
+)
+
+// anchorType is a position on a page recorded by Anchor()
+type anchorType struct {
+	page int
+	x, y float64
+}
+
+// Anchor records the current page and position under the name nameStr, so
+// that later content can be placed relative to content drawn earlier without
+// the position having to be passed around. For example, calling
+// Anchor("table-end") after the last row of a table lets a totals box be
+// placed directly beneath the table with GoToAnchor(), even after other pages
+// have been added. Recording an anchor with an existing name replaces it.
+func (f *Fpdf) Anchor(nameStr string) {
+	if f.err != nil {
+		return
+	}
+	if f.anchors == nil {
+		f.anchors = make(map[string]anchorType)
+	}
+	f.anchors[nameStr] = anchorType{page: f.page, x: f.x, y: f.y}
+}
+
+// GetAnchor returns the page number and position recorded under nameStr with
+// Anchor(). ok is false if no such anchor has been recorded.
+func (f *Fpdf) GetAnchor(nameStr string) (page int, x, y float64, ok bool) {
+	a, ok := f.anchors[nameStr]
+	return a.page, a.x, a.y, ok
+}
+
+// GoToAnchor makes the page on which the anchor nameStr was recorded the
+// current page, as SetPage() does, and sets the current position to the
+// anchor's position moved by dx horizontally and dy vertically. An error is
+// set if no such anchor has been recorded. Content drawn on a page revisited
+// in this way is added to that page; call SetPage() with PageCount() to
+// return to the last page.
+func (f *Fpdf) GoToAnchor(nameStr string, dx, dy float64) {
+	if f.err != nil {
+		return
+	}
+	a, ok := f.anchors[nameStr]
+	if !ok {
+		f.err = fmt.Errorf("anchor %s has not been recorded", nameStr)
+		return
+	}
+	f.SetPage(a.page)
+	f.x, f.y = a.x+dx, a.y+dy
+}
@@ -0,0 +1,139 @@
+package gofpdf
+
+import (
+	"bytes"
+	"encoding/ascii85"
+	"encoding/hex"
+	"regexp"
+	"strconv"
+)
+
+// Encodings of SetASCIIStreams
+const (
+	// ASCIIStreamsNone leaves the data of streams in binary form
+	ASCIIStreamsNone = ""
+	// ASCIIStreams85 encodes the data of streams with ASCII base-85, which
+	// enlarges it by a quarter
+	ASCIIStreams85 = "ASCII85"
+	// ASCIIStreamsHex encodes the data of streams with two hexadecimal digits
+	// per byte, which doubles its size
+	ASCIIStreamsHex = "ASCIIHex"
+)
+
+// asciiLineLen is the length of the lines of encoded stream data
+const asciiLineLen = 76
+
+// SetASCIIStreams selects an encoding of the data of the streams of the
+// document, such as page content, fonts and images, that uses printable
+// ASCII characters only: ASCIIStreams85, ASCIIStreamsHex, or
+// ASCIIStreamsNone (the default) for binary data. The encoded data is broken
+// into lines of 76 characters, and strings outside of content streams that
+// contain other characters, such as titles and bookmarks in UTF-16, are
+// written in hexadecimal, so that the document consists of text that
+// survives transports such as email bodies and can be inspected with a text
+// editor. The encoding is applied after compression (see SetCompression()),
+// so that turning compression off as well makes page content readable.
+//
+// Encryption (see SetProtection()) produces binary data that is not
+// encoded. Image and font files that would be streamed (see
+// SetFileStreamThreshold()) are read into memory to be encoded. The encoding
+// applies to the streams written after it is set; in a streaming document
+// (see NewStreaming()) it should be set before the first page is finished.
+func (f *Fpdf) SetASCIIStreams(encodingStr string) {
+	switch encodingStr {
+	case ASCIIStreamsNone, ASCIIStreams85, ASCIIStreamsHex:
+		f.asciiStreams = encodingStr
+	default:
+		f.SetErrorf("unknown stream encoding %s", encodingStr)
+	}
+}
+
+var (
+	asciiLengthRe = regexp.MustCompile(`/Length \d+`)
+	asciiFilterRe = regexp.MustCompile(`/Filter\s*(?:\[\s*([^\]]*)\]|(/[A-Za-z0-9]+))`)
+	asciiParmsRe  = regexp.MustCompile(`/DecodeParms\s*(<<[^<>]*>>)`)
+)
+
+// asciiEncodeStream returns b encoded as selected with SetASCIIStreams(),
+// and updates the length and filters in the dictionary of the current
+// object, which is the last content of the buffer
+func (f *Fpdf) asciiEncodeStream(b []byte) []byte {
+	var enc []byte
+	var filterStr string
+	switch f.asciiStreams {
+	case ASCIIStreams85:
+		enc = make([]byte, ascii85.MaxEncodedLen(len(b)))
+		enc = asciiWrap(enc[:ascii85.Encode(enc, b)], "~>")
+		filterStr = "/ASCII85Decode"
+	case ASCIIStreamsHex:
+		enc = make([]byte, hex.EncodedLen(len(b)))
+		hex.Encode(enc, b)
+		enc = asciiWrap(bytes.ToUpper(enc), ">")
+		filterStr = "/ASCIIHexDecode"
+	default:
+		return b
+	}
+	buf := f.buffer.Bytes()
+	pos := bytes.LastIndex(buf, []byte(sprintf("%d 0 obj\n", f.n)))
+	if pos < 0 {
+		f.SetErrorf("dictionary of stream object %d not found", f.n)
+		return b
+	}
+	dict := buf[pos:]
+	if loc := asciiLengthRe.FindIndex(dict); loc != nil {
+		dict = bytes.Join([][]byte{dict[:loc[0]], []byte("/Length " + strconv.Itoa(len(enc))), dict[loc[1]:]}, nil)
+	}
+	if m := asciiFilterRe.FindSubmatchIndex(dict); m != nil {
+		// The new filter is applied last, so it is decoded first
+		var list []byte
+		if m[2] >= 0 {
+			list = dict[m[2]:m[3]]
+		} else {
+			list = dict[m[4]:m[5]]
+		}
+		filters := []byte("/Filter [" + filterStr + " " + string(list) + "]")
+		dict = bytes.Join([][]byte{dict[:m[0]], filters, dict[m[1]:]}, nil)
+		dict = asciiParmsRe.ReplaceAll(dict, []byte("/DecodeParms [null $1]"))
+	} else if j := bytes.Index(dict, []byte("<<")); j >= 0 {
+		dict = bytes.Join([][]byte{dict[:j+2], []byte("/Filter " + filterStr + " "), dict[j+2:]}, nil)
+	}
+	f.buffer.Truncate(pos)
+	f.buffer.Write(dict)
+	return enc
+}
+
+// asciiWrap breaks the encoded data enc into lines and appends the
+// end-of-data marker eodStr
+func asciiWrap(enc []byte, eodStr string) []byte {
+	var b bytes.Buffer
+	for len(enc) > asciiLineLen {
+		b.Write(enc[:asciiLineLen])
+		b.WriteByte('\n')
+		enc = enc[asciiLineLen:]
+	}
+	b.Write(enc)
+	b.WriteString(eodStr)
+	return b.Bytes()
+}
+
+// asciiReadSections returns the content of the file sections of a stream
+// that is to be encoded rather than copied when the document is output
+func asciiReadSections(sections []fileSectionType) ([]byte, error) {
+	var b bytes.Buffer
+	for _, sec := range sections {
+		if err := copyFileSection(&b, sec, make([]byte, fileStreamChunkSize)); err != nil {
+			return nil, err
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// asciiString reports whether s consists of printable ASCII characters
+func asciiString(s string) bool {
+	for j := 0; j < len(s); j++ {
+		if s[j] < 0x20 || s[j] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"strings"
+	"time"
 )
 
 // Attachment defines a content to be included in the pdf, in one
@@ -21,6 +22,16 @@ type Attachment struct {
 	// and might be modified by the pdf reader.
 	Description string
 
+	// ModTime is the modification time of the content; the zero value
+	// omits it
+	ModTime time.Time
+
+	// PortfolioValues holds the values of the fields of the attachment when
+	// the document is a portfolio, by field key: strings for fields of kind
+	// PortfolioText, int or float64 values for PortfolioNumber and time.Time
+	// values for PortfolioDate. See SetPortfolio().
+	PortfolioValues map[string]interface{}
+
 	objectNumber int // filled when content is included
 }
 
@@ -35,15 +46,24 @@ func checksum(data []byte) string {
 }
 
 // Writes a compressed file like object as ``/EmbeddedFile``. Compressing is
-// done with deflate. Includes length, compressed length and MD5 checksum.
-func (f *Fpdf) writeCompressedFileObject(content []byte) {
+// done with deflate. Includes length, compressed length and MD5 checksum,
+// and the modification time unless modTime is zero.
+func (f *Fpdf) writeCompressedFileObject(content []byte, modTime time.Time) {
 	lenUncompressed := len(content)
 	sum := checksum(content)
 	compressed := sliceCompress(content)
 	lenCompressed := len(compressed)
 	f.newobj()
-	f.outf("<< /Type /EmbeddedFile /Length %d /Filter /FlateDecode /Params << /CheckSum <%s> /Size %d >> >>\n",
-		lenCompressed, sum, lenUncompressed)
+	modStr := ""
+	if !modTime.IsZero() {
+		modStr = " /ModDate " + f.textstring("D:"+modTime.Format("20060102150405"))
+	}
+	f.outf("<< /Type /EmbeddedFile /Length %d /Filter /FlateDecode /Params << /CheckSum <%s> /Size %d%s >> >>\n",
+		lenCompressed, sum, lenUncompressed, modStr)
+	if f.protect.encrypted && f.protect.efOnly {
+		// Encrypted with the embedded file filter; see SetProtectAttachmentsOnly()
+		f.protect.rc4(uint32(f.n), &compressed)
+	}
 	f.putstream(compressed)
 	f.out("endobj")
 }
@@ -55,13 +75,14 @@ func (f *Fpdf) embed(a *Attachment) {
 	}
 	oldState := f.state
 	f.state = 1 // we write file content in the main buffer
-	f.writeCompressedFileObject(a.Content)
+	f.writeCompressedFileObject(a.Content, a.ModTime)
 	streamID := f.n
 	f.newobj()
-	f.outf("<< /Type /Filespec /F () /UF %s /EF << /F %d 0 R >> /Desc %s\n>>",
+	f.outf("<< /Type /Filespec /F () /UF %s /EF << /F %d 0 R >> /Desc %s%s\n>>",
 		f.textstring(utf8toutf16(a.Filename)),
 		streamID,
-		f.textstring(utf8toutf16(a.Description)))
+		f.textstring(utf8toutf16(a.Description)),
+		f.portfolioItem(a))
 	f.out("endobj")
 	a.objectNumber = f.n
 	f.state = oldState
@@ -92,6 +113,9 @@ func (f Fpdf) getEmbeddedFiles() string {
 	for i, as := range f.attachments {
 		names[i] = fmt.Sprintf("(Attachement%d) %d 0 R ", i+1, as.objectNumber)
 	}
+	if f.printTicketObj > 0 {
+		names = append(names, fmt.Sprintf("(JobTicket) %d 0 R ", f.printTicketObj))
+	}
 	nameTree := fmt.Sprintf("<< /Names [\n %s \n] >>", strings.Join(names, "\n"))
 	return nameTree
 }
@@ -0,0 +1,242 @@
+package gofpdf
+
+import (
+	"fmt"
+	"math"
+)
+
+// code128Patterns holds the widths, in modules, of the alternating bars and
+// spaces of the symbols of Code 128, indexed by symbol value; the last entry
+// is the stop pattern
+var code128Patterns = [...]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+// Code sets and special symbol values of Code 128
+const (
+	code128SetA = iota
+	code128SetB
+	code128SetC
+	code128StartA = 103
+	code128Stop   = 106
+)
+
+// code128Values returns the symbol values that encode codeStr in Code 128,
+// from the start symbol to the stop symbol. Runs of digits are encoded in
+// pairs with code set C; other characters use code set B, or code set A for
+// control characters.
+func code128Values(codeStr string) (vals []int, err error) {
+	if codeStr == "" {
+		return nil, fmt.Errorf("Code 128 barcode is empty")
+	}
+	digits := func(i int) (n int) {
+		for i+n < len(codeStr) && codeStr[i+n] >= '0' && codeStr[i+n] <= '9' {
+			n++
+		}
+		return
+	}
+	set := -1
+	use := func(next int) {
+		switch {
+		case set < 0:
+			vals = append(vals, code128StartA+next)
+		case next == code128SetA:
+			vals = append(vals, 101)
+		case next == code128SetB:
+			vals = append(vals, 100)
+		default:
+			vals = append(vals, 99)
+		}
+		set = next
+	}
+	for i := 0; i < len(codeStr); {
+		c := codeStr[i]
+		if c > 127 {
+			return nil, fmt.Errorf("character %q cannot be encoded in a Code 128 barcode", c)
+		}
+		// An odd run of digits begins with a digit of code set A or B
+		if n := digits(i); n >= 4 && n%2 == 0 || n == 2 && len(codeStr) == 2 {
+			if set != code128SetC {
+				use(code128SetC)
+			}
+			for ; n > 0; n -= 2 {
+				vals = append(vals, int(codeStr[i]-'0')*10+int(codeStr[i+1]-'0'))
+				i += 2
+			}
+			continue
+		}
+		next := set
+		switch {
+		case c < 32:
+			next = code128SetA
+		case c >= 96:
+			next = code128SetB
+		case set != code128SetA:
+			next = code128SetB
+		}
+		if next != set {
+			use(next)
+		}
+		if c < 32 {
+			vals = append(vals, int(c)+64)
+		} else {
+			vals = append(vals, int(c)-32)
+		}
+		i++
+	}
+	sum := vals[0]
+	for j := 1; j < len(vals); j++ {
+		sum += j * vals[j]
+	}
+	vals = append(vals, sum%103, code128Stop)
+	return
+}
+
+// Barcode128 draws a Code 128 barcode that encodes the ASCII characters of
+// codeStr. The bars fill a rectangle of width w and height h whose upper left
+// corner is at (x, y); the symbol needs a quiet zone of at least ten times
+// the width of its narrowest bar on either side, which is left to the
+// caller, as is any human-readable text. The code set that encodes the
+// characters most compactly is selected automatically, so that runs of
+// digits, such as those of tracking numbers, take half the width. The bars
+// are drawn with rectangles in the current fill color. See also
+// BarcodeEAN13() and QRCode().
+func (f *Fpdf) Barcode128(x, y, w, h float64, codeStr string) {
+	if f.err != nil {
+		return
+	}
+	vals, err := code128Values(codeStr)
+	if err != nil {
+		f.err = err
+		return
+	}
+	var modules []bool
+	for _, v := range vals {
+		for j, r := range code128Patterns[v] {
+			for k := '0'; k < r; k++ {
+				modules = append(modules, j%2 == 0)
+			}
+		}
+	}
+	var b fmtBuffer
+	f.barcodeModules(&b, x, y, w/float64(len(modules)), h, modules)
+	f.outf("%sf", b.String())
+}
+
+// ean13Codes holds the left-hand odd parity patterns of the digits of EAN-13;
+// the even parity and right-hand patterns are derived from them
+var ean13Codes = [10]string{
+	"0001101", "0011001", "0010011", "0111101", "0100011",
+	"0110001", "0101111", "0111011", "0110111", "0001011",
+}
+
+// ean13Parity holds the parity patterns, one letter per digit of the left
+// half, that encode the first digit of an EAN-13 number
+var ean13Parity = [10]string{
+	"OOOOOO", "OOEOEE", "OOEEOE", "OOEEEO", "OEOOEE",
+	"OEEOOE", "OEEEOO", "OEOEOE", "OEOEEO", "OEEOEO",
+}
+
+// ean13Modules returns the modules of the EAN-13 barcode of codeStr, which
+// consists of 12 digits or of 13 digits including the check digit
+func ean13Modules(codeStr string) (modules []bool, err error) {
+	if len(codeStr) != 12 && len(codeStr) != 13 {
+		return nil, fmt.Errorf("EAN-13 number %s does not have 12 or 13 digits", codeStr)
+	}
+	sum := 0
+	for j := 0; j < len(codeStr); j++ {
+		c := codeStr[j]
+		if c < '0' || c > '9' {
+			return nil, fmt.Errorf("EAN-13 number %s contains characters other than digits", codeStr)
+		}
+		if j < 12 {
+			sum += int(c-'0') * (1 + 2*(j%2))
+		}
+	}
+	check := byte('0' + (10-sum%10)%10)
+	if len(codeStr) == 13 && codeStr[12] != check {
+		return nil, fmt.Errorf("EAN-13 number %s has an invalid check digit", codeStr)
+	}
+	codeStr = codeStr[:12] + string(check)
+	pattern := "101"
+	parity := ean13Parity[codeStr[0]-'0']
+	for j := 1; j < 13; j++ {
+		code := []byte(ean13Codes[codeStr[j]-'0'])
+		if j > 6 || parity[j-1] == 'E' {
+			// Right-hand patterns are the complements of the left-hand ones
+			for k := range code {
+				code[k] ^= 1
+			}
+		}
+		if j <= 6 && parity[j-1] == 'E' {
+			// Even parity patterns are the right-hand ones reversed
+			for k, l := 0, len(code)-1; k < l; k, l = k+1, l-1 {
+				code[k], code[l] = code[l], code[k]
+			}
+		}
+		pattern += string(code)
+		if j == 6 {
+			pattern += "01010"
+		}
+	}
+	pattern += "101"
+	modules = make([]bool, len(pattern))
+	for j := range pattern {
+		modules[j] = pattern[j] == '1'
+	}
+	return
+}
+
+// BarcodeEAN13 draws an EAN-13 barcode for the number codeStr, which
+// consists of 12 digits, to which the check digit is added, or of 13 digits,
+// the last being a valid check digit. The bars fill a rectangle of width w
+// and height h whose upper left corner is at (x, y); the symbol is 95
+// modules wide, and needs a quiet zone of 11 modules on its left and 7 on its
+// right. The human-readable digits are not printed. The bars are drawn with
+// rectangles in the current fill color. See also Barcode128().
+func (f *Fpdf) BarcodeEAN13(x, y, w, h float64, codeStr string) {
+	if f.err != nil {
+		return
+	}
+	modules, err := ean13Modules(codeStr)
+	if err != nil {
+		f.err = err
+		return
+	}
+	var b fmtBuffer
+	f.barcodeModules(&b, x, y, w/float64(len(modules)), h, modules)
+	f.outf("%sf", b.String())
+}
+
+// barcodeModules writes to b the rectangles that cover the dark modules of a
+// row of modules of width modWd and height h beginning at (x, y). Adjacent
+// dark modules are joined, and the edges of the rectangles are rounded to
+// the precision of the output so that adjacent rows meet without gaps.
+func (f *Fpdf) barcodeModules(b *fmtBuffer, x, y, modWd, h float64, modules []bool) {
+	edge := func(v float64) float64 {
+		return math.Round(v*f.k*100) / 100
+	}
+	top, bottom := edge(f.h-y), edge(f.h-y-h)
+	for j := 0; j < len(modules); j++ {
+		if !modules[j] {
+			continue
+		}
+		k := j
+		for k < len(modules) && modules[k] {
+			k++
+		}
+		left, right := edge(x+float64(j)*modWd), edge(x+float64(k)*modWd)
+		b.printf("%.2f %.2f %.2f %.2f re ", left, bottom, right-left, top-bottom)
+		j = k
+	}
+}
@@ -1,6 +1,7 @@
 package gofpdf
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -197,3 +198,81 @@ func BenchmarkSplitTextASCII(b *testing.B) {
 		_ = pdf.SplitText(text, 60)
 	}
 }
+
+// benchmarkParagraph is a paragraph of plain text used by the long document
+// benchmarks
+const benchmarkParagraph = "Lorem ipsum dolor sit amet, consectetur adipisicing elit, sed do " +
+	"eiusmod tempor incididunt ut labore et dolore magna aliqua. Ut enim ad minim veniam, " +
+	"quis nostrud exercitation ullamco laboris nisi ut aliquip ex ea commodo consequat. " +
+	"Duis aute irure dolor in reprehenderit in voluptate velit esse cillum dolore eu " +
+	"fugiat nulla pariatur. Excepteur sint occaecat cupidatat non proident, sunt in culpa " +
+	"qui officia deserunt mollit anim id est laborum.\n"
+
+// benchmarkLongDocument generates a document of about 20 pages of justified
+// text in the specified font
+func benchmarkLongDocument(b *testing.B, utf8 bool) {
+	text := strings.Repeat(benchmarkParagraph, 8)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		pdf := New("P", "mm", "A4", "")
+		if utf8 {
+			pdf.AddUTF8Font("DejaVuSans", "", "font/DejaVuSansCondensed.ttf")
+			pdf.SetFont("DejaVuSans", "", 11)
+		} else {
+			pdf.SetFont("Times", "", 11)
+		}
+		pdf.AddPage()
+		for j := 0; j < 20; j++ {
+			pdf.MultiCell(0, 5, text, "", "J", false)
+		}
+		if pdf.Err() {
+			b.Fatal(pdf.Error())
+		}
+	}
+}
+
+// BenchmarkMultiCellLongDocument benchmarks MultiCell with a long document in
+// a UTF-8 font
+func BenchmarkMultiCellLongDocument(b *testing.B) {
+	benchmarkLongDocument(b, true)
+}
+
+// BenchmarkMultiCellLongDocumentCore benchmarks MultiCell with a long document
+// in a core font
+func BenchmarkMultiCellLongDocumentCore(b *testing.B) {
+	benchmarkLongDocument(b, false)
+}
+
+// BenchmarkSplitTextLong benchmarks SplitText with several paragraphs of text
+// in a UTF-8 font
+func BenchmarkSplitTextLong(b *testing.B) {
+	pdf := New("P", "mm", "A4", "")
+	pdf.AddUTF8Font("DejaVuSans", "", "font/DejaVuSansCondensed.ttf")
+	pdf.SetFont("DejaVuSans", "", 11)
+	text := strings.Repeat(benchmarkParagraph, 8)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = pdf.SplitText(text, 120)
+	}
+}
+
+// BenchmarkCellFormatRepeated benchmarks CellFormat with the same short
+// content printed many times, as in large tables of numbers
+func BenchmarkCellFormatRepeated(b *testing.B) {
+	pdf := New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.AddUTF8Font("DejaVuSans", "", "font/DejaVuSansCondensed.ttf")
+	pdf.SetFont("DejaVuSans", "", 10)
+	cells := []string{"0.00", "1,234.56", "n/a", "0.00", "Total"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pdf.SetXY(10, 10)
+		for _, str := range cells {
+			pdf.CellFormat(30, 6, str, "", 0, "R", false, 0, "")
+		}
+	}
+}
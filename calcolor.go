@@ -0,0 +1,186 @@
+package gofpdf
+
+import (
+	"math"
+	"sort"
+)
+
+// Resource names of the calibrated color spaces
+const (
+	calSpaceRGB = "CalRGB"
+	calSpaceLab = "Lab"
+)
+
+// calLabSpaceStr is the CIE L*a*b* color space with the D50 white point
+const calLabSpaceStr = "[/Lab <</WhitePoint [0.9642 1 0.8249] /Range [-128 127 -128 127]>>]"
+
+// SetDrawCalRGBColor defines the color used for all drawing operations
+// (lines, rectangles and cell borders) in a calibrated RGB color space. The
+// components range from 0 to 255 like those of SetDrawColor(), but rather
+// than the device-dependent colors of the output device, they specify colors
+// of the CalRGB color space of PDF with the white point (D65), gamma (2.2)
+// and primaries of sRGB, so that they are reproduced consistently by color
+// managed viewers and printers without an ICC profile. GetDrawColor()
+// returns the components.
+func (f *Fpdf) SetDrawCalRGBColor(r, g, b int) {
+	f.color.draw = f.calRGBColorValue(r, g, b, true)
+	if f.page > 0 {
+		f.out(f.color.draw.str)
+	}
+}
+
+// SetFillCalRGBColor defines the color used for all filling operations
+// (filled rectangles and cell backgrounds) in a calibrated RGB color space.
+// See SetDrawCalRGBColor() for the components.
+func (f *Fpdf) SetFillCalRGBColor(r, g, b int) {
+	f.color.fill = f.calRGBColorValue(r, g, b, false)
+	f.colorFlag = f.color.fill.str != f.color.text.str
+	f.textClrExplicit = false
+	if f.page > 0 {
+		f.out(f.color.fill.str)
+	}
+}
+
+// SetTextCalRGBColor defines the color used for text in a calibrated RGB
+// color space. See SetDrawCalRGBColor() for the components.
+func (f *Fpdf) SetTextCalRGBColor(r, g, b int) {
+	f.color.text = f.calRGBColorValue(r, g, b, false)
+	f.colorFlag = f.color.fill.str != f.color.text.str
+	f.textClrExplicit = true
+}
+
+// SetDrawLabColor defines the color used for all drawing operations (lines,
+// rectangles and cell borders) in the CIE L*a*b* color space with the D50
+// white point of print workflows. l is the lightness from 0 (black) to 100
+// (white), a ranges from green (-128) to red (127) and b from blue (-128) to
+// yellow (127); values are quietly bounded to these ranges. Lab colors are
+// device independent and are converted by the viewer or printer to the
+// colors of the output device.
+func (f *Fpdf) SetDrawLabColor(l, a, b float64) {
+	f.color.draw = f.calColorValue(calSpaceLab, true, labBound(l, a, b)...)
+	if f.page > 0 {
+		f.out(f.color.draw.str)
+	}
+}
+
+// SetFillLabColor defines the color used for all filling operations (filled
+// rectangles and cell backgrounds) in the CIE L*a*b* color space. See
+// SetDrawLabColor() for the components.
+func (f *Fpdf) SetFillLabColor(l, a, b float64) {
+	f.color.fill = f.calColorValue(calSpaceLab, false, labBound(l, a, b)...)
+	f.colorFlag = f.color.fill.str != f.color.text.str
+	f.textClrExplicit = false
+	if f.page > 0 {
+		f.out(f.color.fill.str)
+	}
+}
+
+// SetTextLabColor defines the color used for text in the CIE L*a*b* color
+// space. See SetDrawLabColor() for the components.
+func (f *Fpdf) SetTextLabColor(l, a, b float64) {
+	f.color.text = f.calColorValue(calSpaceLab, false, labBound(l, a, b)...)
+	f.colorFlag = f.color.fill.str != f.color.text.str
+	f.textClrExplicit = true
+}
+
+// labBound returns the Lab components bounded to their ranges
+func labBound(l, a, b float64) []float64 {
+	bound := func(v, lo, hi float64) float64 {
+		return math.Max(lo, math.Min(hi, v))
+	}
+	return []float64{bound(l, 0, 100), bound(a, -128, 127), bound(b, -128, 127)}
+}
+
+// calRGBColorValue returns the color of the RGB components in the
+// calibrated RGB color space, which retains the components for GetDrawColor()
+// and the like
+func (f *Fpdf) calRGBColorValue(r, g, b int, stroke bool) (clr colorType) {
+	rgb := rgbColorValue(r, g, b, "", "")
+	clr = f.calColorValue(calSpaceRGB, stroke, rgb.r, rgb.g, rgb.b)
+	clr.r, clr.g, clr.b = rgb.r, rgb.g, rgb.b
+	clr.ir, clr.ig, clr.ib = rgb.ir, rgb.ig, rgb.ib
+	return
+}
+
+// calColorValue returns the color of the components comps in the calibrated
+// color space spaceStr, selected for stroking or for filling, and marks the
+// color space as used
+func (f *Fpdf) calColorValue(spaceStr string, stroke bool, comps ...float64) (clr colorType) {
+	if f.calSpaces == nil {
+		f.calSpaces = make(map[string]int)
+	}
+	if _, ok := f.calSpaces[spaceStr]; !ok {
+		f.calSpaces[spaceStr] = 0
+	}
+	clr.mode = colorModeCal
+	if stroke {
+		clr.str = sprintf("/CS%s CS %.3f %.3f %.3f SCN", spaceStr, comps[0], comps[1], comps[2])
+	} else {
+		clr.str = sprintf("/CS%s cs %.3f %.3f %.3f scn", spaceStr, comps[0], comps[1], comps[2])
+	}
+	return
+}
+
+// calSpaceNames returns the names of the calibrated color spaces in use in
+// alphabetical order
+func (f *Fpdf) calSpaceNames() (list []string) {
+	for nameStr := range f.calSpaces {
+		list = append(list, nameStr)
+	}
+	sort.Strings(list)
+	return
+}
+
+// putCalColorSpaces writes the calibrated color spaces that are in use
+func (f *Fpdf) putCalColorSpaces() {
+	for _, nameStr := range f.calSpaceNames() {
+		f.newobj()
+		switch nameStr {
+		case calSpaceRGB:
+			// White point, gamma and primaries of sRGB
+			f.out("[/CalRGB <</WhitePoint [0.9505 1 1.089] /Gamma [2.2 2.2 2.2]")
+			f.out("/Matrix [0.4124 0.2126 0.0193 0.3576 0.7152 0.1192 0.1805 0.0722 0.9505]>>]")
+		case calSpaceLab:
+			f.out(calLabSpaceStr)
+		}
+		f.out("endobj")
+		f.calSpaces[nameStr] = f.n
+	}
+}
+
+// calColorPutResourceDict writes the entries of the calibrated color spaces
+// in the color space resource dictionary
+func (f *Fpdf) calColorPutResourceDict() {
+	for _, nameStr := range f.calSpaceNames() {
+		f.outf("/CS%s %d 0 R", nameStr, f.calSpaces[nameStr])
+	}
+}
+
+// srgbLab returns the Lab components, relative to the D50 white point, of
+// the sRGB color with the components r, g and b, which range from 0 to 1
+func srgbLab(r, g, b float64) (l, a, bb float64) {
+	linear := func(c float64) float64 {
+		if c <= 0.04045 {
+			return c / 12.92
+		}
+		return math.Pow((c+0.055)/1.055, 2.4)
+	}
+	r, g, b = linear(r), linear(g), linear(b)
+	// XYZ relative to D65, adapted to D50 with the Bradford transform
+	x65 := 0.4124*r + 0.3576*g + 0.1805*b
+	y65 := 0.2126*r + 0.7152*g + 0.0722*b
+	z65 := 0.0193*r + 0.1192*g + 0.9505*b
+	x := 1.0478112*x65 + 0.0228866*y65 - 0.0501270*z65
+	y := 0.0295424*x65 + 0.9904844*y65 - 0.0170491*z65
+	z := -0.0092345*x65 + 0.0150436*y65 + 0.7521316*z65
+	fn := func(t float64) float64 {
+		const d = 6.0 / 29
+		if t > d*d*d {
+			return math.Cbrt(t)
+		}
+		return t/(3*d*d) + 4.0/29
+	}
+	fx, fy, fz := fn(x/0.9642), fn(y), fn(z/0.8249)
+	comps := labBound(116*fy-16, 500*(fx-fy), 200*(fy-fz))
+	return comps[0], comps[1], comps[2]
+}
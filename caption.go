@@ -0,0 +1,198 @@
+package gofpdf
+
+import (
+	"strings"
+)
+
+// ImageCaptionType describes the captions printed with images that are given
+// a caption in their ImageOptions. See SetImageCaptionStyle().
+type ImageCaptionType struct {
+	// Position of the caption: "B" below the image (the default) or "T"
+	// above it
+	PosStr string
+	// Format of the number that precedes the caption text, containing one %d
+	// verb, for example "Figure %d: "; empty for no number
+	NumberFmt string
+	// Font family, style and size in points; an empty family or zero size
+	// selects the family or size in effect when the image is printed
+	FontFamily, FontStyle string
+	FontSize              float64
+	// Alignment of the caption within the width of the image: "L", "C" (the
+	// default) or "R"
+	AlignStr string
+	// Height of the caption lines, zero for 1.25 times the font size, and the
+	// space between the image and the caption, zero for half the font size,
+	// in user units
+	LineHt, Gap float64
+}
+
+// SetImageCaptionStyle sets the style of the captions printed by
+// ImageOptions() for images with a non-empty Caption option. The caption is
+// wrapped to the width of the image. By default, captions are printed below
+// the image, centered, in the current font and preceded by "Figure N: ",
+// where N counts the captioned images of the document.
+//
+// An image placed in flowing mode is kept on the same page as its caption if
+// possible, and the current position is advanced below both. Otherwise the
+// current position is not changed. The current font is restored after the
+// caption has been printed.
+func (f *Fpdf) SetImageCaptionStyle(st ImageCaptionType) {
+	f.captionStyle = st
+}
+
+// GetImageCaptionStyle returns the style of image captions. See
+// SetImageCaptionStyle().
+func (f *Fpdf) GetImageCaptionStyle() ImageCaptionType {
+	return f.captionStyle
+}
+
+// imageOutCaptioned places an image, like imageOut(), together with the
+// caption specified in options
+func (f *Fpdf) imageOutCaptioned(info *ImageInfoType, x, y, w, h float64, options ImageOptions, flow bool, link int, linkStr string) {
+	st := f.captionStyle
+	w, h = f.imageSize(info, w, h)
+	if !options.AllowNegativePosition && x < 0 {
+		x = f.x
+	}
+	f.captionCount++
+	txtStr := options.Caption
+	if st.NumberFmt != "" {
+		txtStr = sprintf(st.NumberFmt, f.captionCount) + txtStr
+	}
+	var lines []string
+	var lineHt, gap float64
+	f.captionFont(func() {
+		lines = f.SplitText(txtStr, w)
+		lineHt, gap = st.LineHt, st.Gap
+		if lineHt <= 0 {
+			lineHt = 1.25 * f.fontSize
+		}
+		if gap <= 0 {
+			gap = f.fontSize / 2
+		}
+	})
+	if f.err != nil {
+		return
+	}
+	capHt := float64(len(lines))*lineHt + gap
+	top := strings.ToUpper(st.PosStr) == "T"
+	if !flow {
+		f.imageOut(info, x, y, w, h, options, false, link, linkStr)
+		if top {
+			f.captionOut(lines, x, y-capHt, w, lineHt)
+		} else {
+			f.captionOut(lines, x, y+h+gap, w, lineHt)
+		}
+		return
+	}
+	// Keep the caption on the same page as the image
+	brk := f.y+h+capHt > f.pageBreakTrigger && options.FlowBreak == ImageFlowBreak
+	if top {
+		// The image must not be moved away from a caption above it, so the
+		// page is broken before the caption if the image would not stay on
+		// the page below it
+		f.y += capHt
+		brk = !f.imageFlowFits(h, options)
+		f.y -= capHt
+	}
+	if brk && f.y > f.tMargin && !f.inHeader && !f.inFooter && f.acceptPageBreakFor(PageBreakImage, f.y, h+capHt) {
+		x2 := f.x
+		f.AddPageFormat(f.curOrientation, f.curPageSize)
+		if f.err != nil {
+			return
+		}
+		f.x = x2
+	}
+	if top {
+		f.captionOut(lines, x, f.y, w, lineHt)
+		f.y += capHt
+		if !f.imageFlowFits(h, options) {
+			// The page break has been declined or would not help, so the
+			// image stays below its caption
+			f.imageOut(info, x, f.y, w, h, options, false, link, linkStr)
+			f.y += h
+			return
+		}
+	}
+	f.imageOut(info, x, y, w, h, options, true, link, linkStr)
+	if !top {
+		f.captionOut(lines, x, f.y+gap, w, lineHt)
+		f.y += capHt
+	}
+}
+
+// imageFlowFits returns true if an image of height h placed in flowing mode
+// at the current position stays on the current page, scaled or split as
+// specified in options if necessary
+func (f *Fpdf) imageFlowFits(h float64, options ImageOptions) bool {
+	switch {
+	case f.y+h <= f.pageBreakTrigger || f.inHeader || f.inFooter || options.FlowBreak == ImageFlowSplit:
+		return true
+	case options.FlowBreak == ImageFlowScale:
+		s := (f.pageBreakTrigger - f.y) / h
+		return s > 0 && s >= options.MinScale
+	}
+	return false
+}
+
+// captionFont calls fnc with the caption font selected and then restores the
+// current font
+func (f *Fpdf) captionFont(fnc func()) {
+	st := f.captionStyle
+	familyStr, styleStr, ptSize := f.fontFamily, f.fontStyleStr(), f.fontSizePt
+	capFamilyStr, capSize := st.FontFamily, st.FontSize
+	if capFamilyStr == "" {
+		capFamilyStr = familyStr
+	}
+	if capFamilyStr == "" {
+		capFamilyStr = "Helvetica"
+	}
+	if capSize <= 0 {
+		capSize = ptSize
+	}
+	f.SetFont(capFamilyStr, st.FontStyle, capSize)
+	fnc()
+	if familyStr != "" {
+		f.SetFont(familyStr, styleStr, ptSize)
+	}
+}
+
+// captionOut prints the lines of a caption in a box of width w with its upper
+// left corner at (x, y), without changing the current position or breaking
+// the page
+func (f *Fpdf) captionOut(lines []string, x, y, w, lineHt float64) {
+	alignStr := strings.ToUpper(f.captionStyle.AlignStr)
+	if alignStr == "" {
+		alignStr = "C"
+	}
+	curX, curY := f.x, f.y
+	auto := f.autoPageBreak
+	f.autoPageBreak = false
+	f.captionFont(func() {
+		for j, lineStr := range lines {
+			f.SetXY(x, y+float64(j)*lineHt)
+			f.CellFormat(w, lineHt, lineStr, "", 0, alignStr, false, 0, "")
+		}
+	})
+	f.autoPageBreak = auto
+	f.x, f.y = curX, curY
+}
+
+// altTextBegin begins a marked-content sequence that carries the alternate
+// description altStr, if it is not empty
+func (f *Fpdf) altTextBegin(altStr string) {
+	if altStr == "" {
+		return
+	}
+	if !isASCII(altStr) {
+		altStr = utf8toutf16(altStr)
+	}
+	f.outf("/Figure <</Alt (%s)>> BDC", f.escape(altStr))
+}
+
+// altTextEnd ends the marked-content sequence begun by altTextBegin()
+func (f *Fpdf) altTextEnd(altStr string) {
+	if altStr != "" {
+		f.out("EMC")
+	}
+}
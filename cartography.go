@@ -0,0 +1,267 @@
+package gofpdf
+
+import (
+	"math"
+	"strconv"
+)
+
+// ScaleBarType assists with the drawing of a map scale bar. Create a value
+// with NewScaleBar(), adjust its exported fields as needed and call Draw().
+type ScaleBarType struct {
+	// Number of ground units, such as meters or miles, represented by one
+	// page unit
+	GroundPerUnit float64
+	// Name of the ground unit, printed after the length of the bar
+	UnitStr string
+	// Number of alternately filled segments
+	Segments int
+	// Height of the bar in page units
+	Ht float64
+	// Color of the bar, its frame and its labels
+	Clr RGBType
+	// Label height in points
+	TextSize float64
+}
+
+// NewScaleBar returns a variable of type ScaleBarType for a map at a scale
+// of groundPerUnit ground units per page unit. It is initialized to draw a
+// black bar of four segments with 7 point labels.
+func NewScaleBar(groundPerUnit float64, unitStr string) (sb ScaleBarType) {
+	sb.GroundPerUnit = groundPerUnit
+	sb.UnitStr = unitStr
+	sb.Segments = 4
+	sb.TextSize = 7
+	return
+}
+
+// niceLength returns the largest number of the form 1, 2 or 5 times a power
+// of ten that does not exceed max
+func niceLength(max float64) float64 {
+	if max <= 0 {
+		return 0
+	}
+	pow := math.Pow(10, math.Floor(math.Log10(max)))
+	for _, m := range []float64{5, 2, 1} {
+		if m*pow <= max {
+			return m * pow
+		}
+	}
+	return pow
+}
+
+// Draw draws the scale bar with its upper left corner at (x, y). The bar
+// represents the longest round ground distance, such as 2, 50 or 500 units,
+// that fits within maxWd page units. Labels are printed below the bar at
+// each segment boundary. The page width of the bar is returned. The drawing
+// state in effect beforehand is restored.
+func (sb ScaleBarType) Draw(pdf *Fpdf, x, y, maxWd float64) (wd float64) {
+	if sb.GroundPerUnit <= 0 || pdf.err != nil {
+		return
+	}
+	segments := sb.Segments
+	if segments < 1 {
+		segments = 1
+	}
+	ground := niceLength(maxWd * sb.GroundPerUnit)
+	wd = ground / sb.GroundPerUnit
+	ht := sb.Ht
+	if ht == 0 {
+		ht = wd / 40
+	}
+	st := StateGet(pdf)
+	pdf.SetDrawColor(sb.Clr.R, sb.Clr.G, sb.Clr.B)
+	pdf.SetFillColor(sb.Clr.R, sb.Clr.G, sb.Clr.B)
+	pdf.SetTextColor(sb.Clr.R, sb.Clr.G, sb.Clr.B)
+	pdf.SetLineWidth(ht / 8)
+	segWd := wd / float64(segments)
+	for j := 0; j < segments; j++ {
+		styleStr := "D"
+		if j%2 == 0 {
+			styleStr = "FD"
+		}
+		pdf.Rect(x+float64(j)*segWd, y, segWd, ht, styleStr)
+	}
+	textSz := pdf.PointToUnitConvert(sb.TextSize)
+	pdf.SetFontUnitSize(textSz)
+	for j := 0; j <= segments; j++ {
+		val := ground * float64(j) / float64(segments)
+		str := strconv.FormatFloat(val, 'f', -1, 64)
+		if j == segments && sb.UnitStr != "" {
+			str += " " + sb.UnitStr
+		}
+		sx := x + float64(j)*segWd - pdf.GetStringWidth(strconv.FormatFloat(val, 'f', -1, 64))/2
+		pdf.Text(sx, y+ht+1.2*textSz, str)
+	}
+	st.Put(pdf)
+	return
+}
+
+// NorthArrow draws a north arrow of height size centered horizontally on x
+// with its tip at y, followed below by the letter "N". The arrow is split
+// lengthwise into a filled and an outlined half. rotateDeg turns the arrow
+// counter-clockwise around its center, for maps in which north is not
+// straight up. The current draw and fill colors are used, and the current
+// font family is used for the letter.
+func NorthArrow(pdf *Fpdf, x, y, size, rotateDeg float64) {
+	if pdf.err != nil {
+		return
+	}
+	lineWd := pdf.GetLineWidth()
+	pdf.SetLineWidth(size / 60)
+	cy := y + size/2
+	pdf.TransformBegin()
+	pdf.TransformRotate(rotateDeg, x, cy)
+	tip := PointType{X: x, Y: y}
+	notch := PointType{X: x, Y: y + size*0.7}
+	pdf.Polygon([]PointType{tip, {X: x - size/4, Y: y + size*0.85}, notch}, "FD")
+	pdf.Polygon([]PointType{tip, {X: x + size/4, Y: y + size*0.85}, notch}, "D")
+	if pdf.fontFamily != "" {
+		_, ptSize := pdf.GetFontSize()
+		pdf.SetFontSize(size * pdf.k * 0.3)
+		pdf.Text(x-pdf.GetStringWidth("N")/2, y+size*1.2, "N")
+		pdf.SetFontSize(ptSize)
+	}
+	pdf.TransformEnd()
+	pdf.SetLineWidth(lineWd)
+}
+
+// Legend symbol styles recognized by LegendItemType
+const (
+	// LegendBox is a filled square, for areas
+	LegendBox = "box"
+	// LegendLine is a short horizontal line, for routes and boundaries
+	LegendLine = "line"
+	// LegendDot is a filled circle, for point features
+	LegendDot = "dot"
+)
+
+// LegendItemType is one entry of a map legend.
+type LegendItemType struct {
+	Label string
+	// LegendBox, LegendLine or LegendDot
+	Style string
+	Clr   RGBType
+	// Width of a LegendLine symbol's line in page units; zero selects a
+	// tenth of the symbol size
+	LineWd float64
+}
+
+// LegendType assists with the drawing of a boxed map legend. Create a value
+// with NewLegend(), append items and call Draw().
+type LegendType struct {
+	// Optional heading printed in bold above the items
+	Title string
+	Items []LegendItemType
+	// Label height in points
+	TextSize float64
+	// Space between the frame and its contents in page units; zero selects
+	// half the label height
+	Padding float64
+	// Paint the background and draw a frame around the legend
+	Fill, Border bool
+	// Colors of the labels, background and frame
+	ClrText, ClrBackground, ClrBorder RGBType
+}
+
+// NewLegend returns a variable of type LegendType that is initialized to
+// draw 8 point black labels on a white background with a gray frame.
+func NewLegend(titleStr string) (lg LegendType) {
+	lg.Title = titleStr
+	lg.TextSize = 8
+	lg.Fill = true
+	lg.Border = true
+	lg.ClrBackground = RGBType{255, 255, 255}
+	lg.ClrBorder = RGBType{128, 128, 128}
+	return
+}
+
+// Add appends an item to the legend.
+func (lg *LegendType) Add(labelStr, styleStr string, clr RGBType) {
+	lg.Items = append(lg.Items, LegendItemType{Label: labelStr, Style: styleStr, Clr: clr})
+}
+
+// Draw draws the legend with its upper left corner at (x, y) using the
+// current font family, or Helvetica if no font has been set, and returns its
+// width and height. The drawing state in effect beforehand is restored.
+func (lg LegendType) Draw(pdf *Fpdf, x, y float64) (wd, ht float64) {
+	if pdf.err != nil {
+		return
+	}
+	st := StateGet(pdf)
+	familyStr, styleStr := pdf.fontFamily, pdf.fontStyleStr()
+	labelFamilyStr := familyStr
+	if labelFamilyStr == "" {
+		labelFamilyStr = "Helvetica"
+		pdf.SetFont(labelFamilyStr, "", lg.TextSize)
+	}
+	textSz := pdf.PointToUnitConvert(lg.TextSize)
+	lineHt := 1.5 * textSz
+	pad := lg.Padding
+	if pad == 0 {
+		pad = textSz / 2
+	}
+	symWd := 1.5 * textSz
+	pdf.SetFontUnitSize(textSz)
+
+	// Measure
+	wd = 0
+	for _, item := range lg.Items {
+		wd = math.Max(wd, symWd+textSz/2+pdf.GetStringWidth(item.Label))
+	}
+	ht = float64(len(lg.Items)) * lineHt
+	if lg.Title != "" {
+		pdf.SetFont(labelFamilyStr, "B", lg.TextSize)
+		wd = math.Max(wd, pdf.GetStringWidth(lg.Title))
+		ht += lineHt
+	}
+	wd += 2 * pad
+	ht += 2 * pad
+
+	styleRect := ""
+	if lg.Fill {
+		pdf.SetFillColor(lg.ClrBackground.R, lg.ClrBackground.G, lg.ClrBackground.B)
+		styleRect = "F"
+	}
+	if lg.Border {
+		pdf.SetDrawColor(lg.ClrBorder.R, lg.ClrBorder.G, lg.ClrBorder.B)
+		styleRect += "D"
+	}
+	if styleRect != "" {
+		pdf.Rect(x, y, wd, ht, styleRect)
+	}
+	pdf.SetTextColor(lg.ClrText.R, lg.ClrText.G, lg.ClrText.B)
+	ly := y + pad
+	baseline := func() float64 {
+		return ly + 0.5*lineHt + 0.35*textSz
+	}
+	if lg.Title != "" {
+		pdf.Text(x+pad, baseline(), lg.Title)
+		ly += lineHt
+		pdf.SetFont(labelFamilyStr, "", lg.TextSize)
+	}
+	for _, item := range lg.Items {
+		cx, cy := x+pad+symWd/2, ly+lineHt/2
+		pdf.SetDrawColor(item.Clr.R, item.Clr.G, item.Clr.B)
+		pdf.SetFillColor(item.Clr.R, item.Clr.G, item.Clr.B)
+		switch item.Style {
+		case LegendLine:
+			lineWd := item.LineWd
+			if lineWd == 0 {
+				lineWd = symWd / 10
+			}
+			pdf.SetLineWidth(lineWd)
+			pdf.Line(cx-symWd/2, cy, cx+symWd/2, cy)
+		case LegendDot:
+			pdf.Circle(cx, cy, textSz/3, "F")
+		default:
+			pdf.Rect(cx-symWd/2, cy-textSz/2, symWd, textSz, "F")
+		}
+		pdf.Text(x+pad+symWd+textSz/2, baseline(), item.Label)
+		ly += lineHt
+	}
+	if familyStr != "" {
+		pdf.SetFont(familyStr, styleStr, pdf.fontSizePt)
+	}
+	st.Put(pdf)
+	return
+}
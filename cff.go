@@ -0,0 +1,579 @@
+package gofpdf
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// CFF operators used when a font with PostScript outlines is embedded.
+// Two-byte operators are numbered 1200 plus their second byte.
+const (
+	cffOpCharset      = 15
+	cffOpCharStrings  = 17
+	cffOpPrivate      = 18
+	cffOpSubrs        = 19
+	cffOpFontMatrix   = 1207
+	cffOpCharstrType  = 1206
+	cffOpROS          = 1230
+	cffOpCIDCount     = 1234
+	cffOpFDArray      = 1236
+	cffOpFDSelect     = 1237
+	cffOpFontName     = 1238
+	cffStdStringCount = 391
+)
+
+// cffTopDrop lists the Top DICT operators that are not copied to the subset:
+// those that refer to strings, identify the original font, or locate data
+// that is rewritten
+var cffTopDrop = map[int]bool{0: true, 1: true, 2: true, 3: true, 4: true,
+	13: true, 14: true, cffOpCharset: true, 16: true, cffOpCharStrings: true,
+	cffOpPrivate: true, 1200: true, 1220: true, 1221: true, 1222: true, 1223: true,
+	cffOpROS: true, cffOpCIDCount: true, 1235: true, cffOpFDArray: true,
+	cffOpFDSelect: true, cffOpFontName: true}
+
+// cffDictEntry is an operator of a CFF DICT with its encoded operands
+type cffDictEntry struct {
+	op       int
+	operands []byte
+	nums     []int // integer operands; real operands are recorded as zero
+}
+
+// cffPrivateType is a Private DICT and its local subroutines
+type cffPrivateType struct {
+	dict  []cffDictEntry
+	subrs []byte // encoded INDEX, or nil
+}
+
+// cffFontType holds the parts of a CFF font program that are needed to write
+// a subset of it
+type cffFontType struct {
+	name        []byte
+	top         []cffDictEntry
+	gsubrs      []byte // encoded INDEX
+	charStrings [][]byte
+	fontDicts   [][]cffDictEntry // font DICTs of a CID-keyed font
+	privates    []cffPrivateType // one for each font DICT
+	fdSelect    []int            // font DICT of each glyph of a CID-keyed font
+}
+
+// parseCFF parses the CFF font program in data
+func parseCFF(data []byte) (cff *cffFontType, err error) {
+	if len(data) < 4 || data[0] != 1 {
+		return nil, fmt.Errorf("unsupported CFF version")
+	}
+	pos := int(data[2])
+	var names, tops [][]byte
+	if names, pos, err = cffReadIndex(data, pos); err != nil {
+		return
+	}
+	if tops, pos, err = cffReadIndex(data, pos); err != nil {
+		return
+	}
+	if len(names) != 1 || len(tops) != 1 {
+		return nil, fmt.Errorf("CFF table does not hold exactly one font")
+	}
+	if _, pos, err = cffReadIndex(data, pos); err != nil { // String INDEX
+		return
+	}
+	gsubrStart := pos
+	if _, pos, err = cffReadIndex(data, pos); err != nil {
+		return
+	}
+	cff = &cffFontType{name: names[0], gsubrs: data[gsubrStart:pos]}
+	if cff.top, err = cffParseDict(tops[0]); err != nil {
+		return nil, err
+	}
+	if tp, ok := cffDictInt(cff.top, cffOpCharstrType, 0); ok && tp != 2 {
+		return nil, fmt.Errorf("CFF charstring type %d is not supported", tp)
+	}
+	off, ok := cffDictInt(cff.top, cffOpCharStrings, 0)
+	if !ok {
+		return nil, fmt.Errorf("CFF font has no charstrings")
+	}
+	if cff.charStrings, _, err = cffReadIndex(data, off); err != nil {
+		return nil, err
+	}
+	if _, cid := cffDictInt(cff.top, cffOpROS, 0); !cid {
+		var priv cffPrivateType
+		if priv, err = cffReadPrivate(data, cff.top); err == nil {
+			cff.privates = []cffPrivateType{priv}
+		}
+		return
+	}
+	// CID-keyed font
+	if off, ok = cffDictInt(cff.top, cffOpFDArray, 0); !ok {
+		return nil, fmt.Errorf("CID-keyed CFF font has no FDArray")
+	}
+	var fds [][]byte
+	if fds, _, err = cffReadIndex(data, off); err != nil {
+		return nil, err
+	}
+	for _, fd := range fds {
+		var dict []cffDictEntry
+		var priv cffPrivateType
+		if dict, err = cffParseDict(fd); err != nil {
+			return nil, err
+		}
+		if priv, err = cffReadPrivate(data, dict); err != nil {
+			return nil, err
+		}
+		cff.fontDicts = append(cff.fontDicts, dict)
+		cff.privates = append(cff.privates, priv)
+	}
+	if off, ok = cffDictInt(cff.top, cffOpFDSelect, 0); !ok {
+		return nil, fmt.Errorf("CID-keyed CFF font has no FDSelect")
+	}
+	cff.fdSelect, err = cffReadFDSelect(data, off, len(cff.charStrings), len(fds))
+	return
+}
+
+// cffReadPrivate reads the Private DICT, and its local subroutines, located
+// by the Private operator of dict
+func cffReadPrivate(data []byte, dict []cffDictEntry) (priv cffPrivateType, err error) {
+	size, ok1 := cffDictInt(dict, cffOpPrivate, 0)
+	off, ok2 := cffDictInt(dict, cffOpPrivate, 1)
+	if !ok1 || !ok2 {
+		return // The Private DICT is optional
+	}
+	if off < 0 || size < 0 || off+size > len(data) {
+		return priv, fmt.Errorf("CFF Private DICT is out of range")
+	}
+	if priv.dict, err = cffParseDict(data[off : off+size]); err != nil {
+		return
+	}
+	if subrs, ok := cffDictInt(priv.dict, cffOpSubrs, 0); ok {
+		var end int
+		if _, end, err = cffReadIndex(data, off+subrs); err == nil {
+			priv.subrs = data[off+subrs : end]
+		}
+	}
+	return
+}
+
+// cffReadFDSelect returns the font DICT of each of the count glyphs
+func cffReadFDSelect(data []byte, pos, count, fdCount int) (list []int, err error) {
+	bad := fmt.Errorf("CFF FDSelect is malformed")
+	if pos < 0 || pos >= len(data) {
+		return nil, bad
+	}
+	list = make([]int, count)
+	switch data[pos] {
+	case 0:
+		if pos+1+count > len(data) {
+			return nil, bad
+		}
+		for j := range list {
+			list[j] = int(data[pos+1+j])
+		}
+	case 3:
+		if pos+3 > len(data) {
+			return nil, bad
+		}
+		n := int(data[pos+1])<<8 | int(data[pos+2])
+		rng := pos + 3
+		if rng+3*n+2 > len(data) {
+			return nil, bad
+		}
+		for j := 0; j < n; j++ {
+			p := rng + 3*j
+			first := int(data[p])<<8 | int(data[p+1])
+			last := int(data[p+3])<<8 | int(data[p+4]) // next first or sentinel
+			for g := first; g < last && g < count; g++ {
+				list[g] = int(data[p+2])
+			}
+		}
+	default:
+		return nil, fmt.Errorf("CFF FDSelect format %d is not supported", data[pos])
+	}
+	for _, fd := range list {
+		if fd >= fdCount {
+			return nil, bad
+		}
+	}
+	return
+}
+
+// cffReadIndex returns the items of the INDEX that begins at pos of data, and
+// the position following it
+func cffReadIndex(data []byte, pos int) (items [][]byte, end int, err error) {
+	bad := fmt.Errorf("CFF INDEX at offset %d is malformed", pos)
+	if pos < 0 || pos+2 > len(data) {
+		return nil, 0, bad
+	}
+	count := int(data[pos])<<8 | int(data[pos+1])
+	if count == 0 {
+		return nil, pos + 2, nil
+	}
+	if pos+3 > len(data) {
+		return nil, 0, bad
+	}
+	offSize := int(data[pos+2])
+	offStart := pos + 3
+	if offSize < 1 || offSize > 4 || offStart+(count+1)*offSize > len(data) {
+		return nil, 0, bad
+	}
+	offset := func(j int) (v int) {
+		for _, b := range data[offStart+j*offSize : offStart+(j+1)*offSize] {
+			v = v<<8 | int(b)
+		}
+		return
+	}
+	base := offStart + (count+1)*offSize - 1
+	items = make([][]byte, count)
+	for j := range items {
+		start, stop := base+offset(j), base+offset(j+1)
+		if start <= base || stop < start || stop > len(data) {
+			return nil, 0, bad
+		}
+		items[j] = data[start:stop]
+	}
+	return items, base + offset(count), nil
+}
+
+// cffWriteIndex returns the encoded INDEX of items
+func cffWriteIndex(items [][]byte) []byte {
+	if len(items) == 0 {
+		return []byte{0, 0}
+	}
+	size := 1
+	for _, item := range items {
+		size += len(item)
+	}
+	offSize := 1
+	for size >= 1<<(8*uint(offSize)) {
+		offSize++
+	}
+	var b bytes.Buffer
+	b.Write([]byte{byte(len(items) >> 8), byte(len(items)), byte(offSize)})
+	putOffset := func(v int) {
+		for k := offSize - 1; k >= 0; k-- {
+			b.WriteByte(byte(v >> (8 * uint(k))))
+		}
+	}
+	off := 1
+	putOffset(off)
+	for _, item := range items {
+		off += len(item)
+		putOffset(off)
+	}
+	for _, item := range items {
+		b.Write(item)
+	}
+	return b.Bytes()
+}
+
+// cffParseDict returns the entries of the encoded DICT data
+func cffParseDict(data []byte) (list []cffDictEntry, err error) {
+	var entry cffDictEntry
+	start := 0
+	for pos := 0; pos < len(data); {
+		b0 := int(data[pos])
+		switch {
+		case b0 <= 21:
+			entry.op = b0
+			pos++
+			if b0 == 12 {
+				if pos >= len(data) {
+					return nil, fmt.Errorf("CFF DICT is truncated")
+				}
+				entry.op = 1200 + int(data[pos])
+				pos++
+			}
+			entry.operands = data[start : pos-1-entry.op/1200]
+			list = append(list, entry)
+			entry = cffDictEntry{}
+			start = pos
+			continue
+		case b0 == 28 && pos+3 <= len(data):
+			entry.nums = append(entry.nums, int(int16(uint16(data[pos+1])<<8|uint16(data[pos+2]))))
+			pos += 3
+		case b0 == 29 && pos+5 <= len(data):
+			v := uint32(data[pos+1])<<24 | uint32(data[pos+2])<<16 | uint32(data[pos+3])<<8 | uint32(data[pos+4])
+			entry.nums = append(entry.nums, int(int32(v)))
+			pos += 5
+		case b0 == 30:
+			pos++
+			for pos < len(data) && data[pos]&0x0f != 0x0f && data[pos]&0xf0 != 0xf0 {
+				pos++
+			}
+			pos++
+			entry.nums = append(entry.nums, 0)
+		case b0 >= 32 && b0 <= 246:
+			entry.nums = append(entry.nums, b0-139)
+			pos++
+		case b0 >= 247 && b0 <= 250 && pos+2 <= len(data):
+			entry.nums = append(entry.nums, (b0-247)*256+int(data[pos+1])+108)
+			pos += 2
+		case b0 >= 251 && b0 <= 254 && pos+2 <= len(data):
+			entry.nums = append(entry.nums, -(b0-251)*256-int(data[pos+1])-108)
+			pos += 2
+		default:
+			return nil, fmt.Errorf("CFF DICT is malformed")
+		}
+	}
+	return
+}
+
+// cffDictInt returns operand k of operator op of dict
+func cffDictInt(dict []cffDictEntry, op, k int) (int, bool) {
+	for _, entry := range dict {
+		if entry.op == op && k < len(entry.nums) {
+			return entry.nums[k], true
+		}
+	}
+	return 0, false
+}
+
+// cffInt returns the five byte encoding of v. Offsets are always encoded in
+// this form so that the size of the DICTs that hold them does not depend on
+// their values.
+func cffInt(v int) []byte {
+	return []byte{29, byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)}
+}
+
+// cffWriteDict returns the encoding of dict
+func cffWriteDict(dict []cffDictEntry) []byte {
+	var b bytes.Buffer
+	for _, entry := range dict {
+		b.Write(entry.operands)
+		if entry.op >= 1200 {
+			b.WriteByte(12)
+			b.WriteByte(byte(entry.op - 1200))
+		} else {
+			b.WriteByte(byte(entry.op))
+		}
+	}
+	return b.Bytes()
+}
+
+// cffEntry returns a DICT entry for op with the integer operands vals
+func cffEntry(op int, vals ...int) cffDictEntry {
+	var b []byte
+	for _, v := range vals {
+		b = append(b, cffInt(v)...)
+	}
+	return cffDictEntry{op: op, operands: b, nums: vals}
+}
+
+// subset returns a CID-keyed CFF font program holding the glyphs gids of cff,
+// in that order, identified by the CIDs cids. The first glyph must be the
+// .notdef glyph, with CID 0. Global and local subroutines are copied whole.
+func (cff *cffFontType) subset(gids, cids []int) []byte {
+	// Font DICTs of the subset, each with its Private DICT
+	fdMap := make(map[int]int)
+	var fdList []int
+	newFD := make([]int, len(gids))
+	for j, gid := range gids {
+		fd := 0
+		if cff.fdSelect != nil {
+			fd = cff.fdSelect[gid]
+		}
+		n, ok := fdMap[fd]
+		if !ok {
+			n = len(fdList)
+			fdMap[fd] = n
+			fdList = append(fdList, fd)
+		}
+		newFD[j] = n
+	}
+	var fontDicts [][]cffDictEntry
+	for _, fd := range fdList {
+		var dict []cffDictEntry
+		if cff.fontDicts != nil {
+			for _, entry := range cff.fontDicts[fd] {
+				if entry.op != cffOpPrivate && entry.op != cffOpFontName {
+					dict = append(dict, entry)
+				}
+			}
+		} else {
+			// The font matrix of a name-keyed font applies to its glyphs
+			for _, entry := range cff.top {
+				if entry.op == cffOpFontMatrix {
+					dict = append(dict, entry)
+				}
+			}
+		}
+		fontDicts = append(fontDicts, dict)
+	}
+	privates := make([][]byte, len(fdList))
+	for j, fd := range fdList {
+		var dict []cffDictEntry
+		if fd < len(cff.privates) {
+			for _, entry := range cff.privates[fd].dict {
+				if entry.op != cffOpSubrs {
+					dict = append(dict, entry)
+				}
+			}
+			if subrs := cff.privates[fd].subrs; subrs != nil {
+				size := len(cffWriteDict(dict)) + len(cffInt(0)) + 1
+				dict = append(dict, cffEntry(cffOpSubrs, size))
+				privates[j] = append(cffWriteDict(dict), subrs...)
+				continue
+			}
+		}
+		privates[j] = cffWriteDict(dict)
+	}
+
+	// Fixed-size parts
+	var charset bytes.Buffer
+	charset.WriteByte(0)
+	maxCID := 0
+	for _, cid := range cids[1:] {
+		charset.Write([]byte{byte(cid >> 8), byte(cid)})
+		if cid > maxCID {
+			maxCID = cid
+		}
+	}
+	var fdSelect bytes.Buffer
+	var ranges [][2]int
+	for j, fd := range newFD {
+		if j == 0 || fd != newFD[j-1] {
+			ranges = append(ranges, [2]int{j, fd})
+		}
+	}
+	fdSelect.Write([]byte{3, byte(len(ranges) >> 8), byte(len(ranges))})
+	for _, rng := range ranges {
+		fdSelect.Write([]byte{byte(rng[0] >> 8), byte(rng[0]), byte(rng[1])})
+	}
+	fdSelect.Write([]byte{byte(len(gids) >> 8), byte(len(gids))})
+	charStrings := make([][]byte, len(gids))
+	for j, gid := range gids {
+		charStrings[j] = cff.charStrings[gid]
+	}
+	charStringIndex := cffWriteIndex(charStrings)
+	nameIndex := cffWriteIndex([][]byte{cff.name})
+	stringIndex := cffWriteIndex([][]byte{[]byte("Adobe"), []byte("Identity")})
+
+	// The Top DICT and font DICTs hold offsets; since these are encoded with
+	// a fixed size, the DICTs are built twice, first to measure them
+	topDict := func(charsetOff, fdSelectOff, charStringsOff, fdArrayOff int) []byte {
+		dict := []cffDictEntry{cffEntry(cffOpROS, cffStdStringCount, cffStdStringCount+1, 0)}
+		for _, entry := range cff.top {
+			if !cffTopDrop[entry.op] && !(cff.fontDicts == nil && entry.op == cffOpFontMatrix) {
+				dict = append(dict, entry)
+			}
+		}
+		dict = append(dict, cffEntry(cffOpCIDCount, maxCID+1), cffEntry(cffOpCharset, charsetOff),
+			cffEntry(cffOpFDSelect, fdSelectOff), cffEntry(cffOpCharStrings, charStringsOff),
+			cffEntry(cffOpFDArray, fdArrayOff))
+		return cffWriteIndex([][]byte{cffWriteDict(dict)})
+	}
+	fdArray := func(privateOff int) []byte {
+		items := make([][]byte, len(fontDicts))
+		for j, dict := range fontDicts {
+			items[j] = cffWriteDict(append(dict[:len(dict):len(dict)],
+				cffEntry(cffOpPrivate, len(privates[j]), privateOff)))
+			privateOff += len(privates[j])
+		}
+		return cffWriteIndex(items)
+	}
+	header := []byte{1, 0, 4, 4}
+	charsetOff := len(header) + len(nameIndex) + len(topDict(0, 0, 0, 0)) +
+		len(stringIndex) + len(cff.gsubrs)
+	fdSelectOff := charsetOff + charset.Len()
+	charStringsOff := fdSelectOff + fdSelect.Len()
+	fdArrayOff := charStringsOff + len(charStringIndex)
+	privateOff := fdArrayOff + len(fdArray(0))
+
+	var b bytes.Buffer
+	b.Write(header)
+	b.Write(nameIndex)
+	b.Write(topDict(charsetOff, fdSelectOff, charStringsOff, fdArrayOff))
+	b.Write(stringIndex)
+	b.Write(cff.gsubrs)
+	b.Write(charset.Bytes())
+	b.Write(fdSelect.Bytes())
+	b.Write(charStringIndex)
+	b.Write(fdArray(privateOff))
+	for _, priv := range privates {
+		b.Write(priv)
+	}
+	return b.Bytes()
+}
+
+// generateCutCFF returns a CID-keyed CFF font program with the glyphs of the
+// runes in usedRunes, which maps CIDs to runes, or with all of the glyphs of
+// the font if full is true. Glyphs not used in the document are then given
+// CIDs that are not in use. The ToUnicode CMap and the highest CID are
+// recorded as they are by GenerateCutFont().
+func (utf *utf8FontFile) generateCutCFF(usedRunes map[int]int, full bool) []byte {
+	cff, err := parseCFF(utf.getTableData("CFF "))
+	if err != nil {
+		utf.setErrorf("%s", err)
+		return nil
+	}
+	if utf.generateCMAP() == nil {
+		return nil
+	}
+	cidList := make([]int, 0, len(usedRunes))
+	for cid := range usedRunes {
+		if cid > 0 {
+			cidList = append(cidList, cid)
+		}
+	}
+	sort.Ints(cidList)
+	gids, cids := []int{0}, []int{0}
+	cidToUnicode := make(map[int]int)
+	cidToGlyph := make(map[int]int)
+	usedGlyphs := make(map[int]bool)
+	maxCID := 0
+	for _, cid := range cidList {
+		cidToUnicode[cid] = usedRunes[cid]
+		maxCID = max(maxCID, cid)
+		gid, ok := utf.charSymbolDictionary[usedRunes[cid]]
+		if !ok || gid <= 0 || gid >= len(cff.charStrings) {
+			continue
+		}
+		cidToGlyph[cid] = len(gids)
+		gids = append(gids, gid)
+		cids = append(cids, cid)
+		usedGlyphs[gid] = true
+	}
+	shapedList := make([]int, 0, len(utf.glyphCIDs))
+	for cid := range utf.glyphCIDs {
+		shapedList = append(shapedList, cid)
+	}
+	sort.Ints(shapedList)
+	for _, cid := range shapedList {
+		maxCID = max(maxCID, cid)
+		gid := utf.glyphCIDs[cid].gid
+		if gid <= 0 || gid >= len(cff.charStrings) {
+			continue
+		}
+		cidToGlyph[cid] = len(gids)
+		gids = append(gids, gid)
+		cids = append(cids, cid)
+		usedGlyphs[gid] = true
+	}
+	if full {
+		free := 1
+		for gid := 1; gid < len(cff.charStrings); gid++ {
+			if usedGlyphs[gid] {
+				continue
+			}
+			for used := utf.cidUsed(usedRunes, free); used; used = utf.cidUsed(usedRunes, free) {
+				free++
+			}
+			if free > 0xffff {
+				utf.setErrorf("font has too many glyphs to be embedded completely")
+				return nil
+			}
+			gids = append(gids, gid)
+			cids = append(cids, free)
+			free++
+		}
+	}
+	utf.ToUnicodeCMap = generateToUnicodeCMapText(cidToUnicode, utf.glyphText())
+	utf.CodeSymbolDictionary = cidToGlyph
+	utf.LastRune = maxCID
+	return cff.subset(gids, cids)
+}
+
+// cidUsed reports whether cid is the CID of a rune of usedRunes or of a glyph
+// substituted by text shaping
+func (utf *utf8FontFile) cidUsed(usedRunes map[int]int, cid int) bool {
+	_, used := usedRunes[cid]
+	_, shaped := utf.glyphCIDs[cid]
+	return used || shaped
+}
@@ -0,0 +1,96 @@
+package gofpdf
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/asn1"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Object identifiers of the cryptographic message syntax (RFC 5652)
+var (
+	oidData          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidSignedData    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidContentType   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+	oidMessageDigest = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	oidSigningTime   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 5}
+	oidSHA256        = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidRSA           = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+	oidECDSASHA256   = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+)
+
+// cmsSignedData returns the DER encoding of a detached CMS SignedData
+// structure that holds the signature by sig, made at tm, of the content with
+// the SHA-256 digest digest
+func cmsSignedData(digest []byte, sig DigitalSignatureType, tm time.Time) ([]byte, error) {
+	var sigAlg []byte
+	switch sig.Signer.Public().(type) {
+	case *rsa.PublicKey:
+		sigAlg = derSeq(derValue(oidRSA), derValue(asn1.NullRawValue))
+	case *ecdsa.PublicKey:
+		sigAlg = derSeq(derValue(oidECDSASHA256))
+	default:
+		return nil, fmt.Errorf("unsupported signature key type %T", sig.Signer.Public())
+	}
+	digestAlg := derSeq(derValue(oidSHA256))
+	// The elements of a set are ordered by their encodings
+	attrs := [][]byte{
+		derSeq(derValue(oidContentType), derSet(derValue(oidData))),
+		derSeq(derValue(oidSigningTime), derSet(derValue(tm.UTC()))),
+		derSeq(derValue(oidMessageDigest), derSet(derValue(digest))),
+	}
+	sort.Slice(attrs, func(a, b int) bool { return bytes.Compare(attrs[a], attrs[b]) < 0 })
+	attrSet := derSet(attrs...)
+	h := sha256.Sum256(attrSet)
+	signature, err := sig.Signer.Sign(rand.Reader, h[:], crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+	// The signed attributes are signed as a set and included with an
+	// implicit tag
+	signedAttrs := append([]byte{0xa0}, attrSet[1:]...)
+	cert := sig.Certificates[0]
+	signerInfo := derSeq(derValue(1), derSeq(cert.RawIssuer, derValue(cert.SerialNumber)),
+		digestAlg, signedAttrs, sigAlg, derValue(signature))
+	var certs [][]byte
+	for _, c := range sig.Certificates {
+		certs = append(certs, c.Raw)
+	}
+	signedData := derSeq(derValue(1), derSet(digestAlg), derSeq(derValue(oidData)),
+		derTagged(0, certs...), derSet(signerInfo))
+	return derSeq(derValue(oidSignedData), derTagged(0, signedData)), nil
+}
+
+// derValue returns the DER encoding of v
+func derValue(v interface{}) []byte {
+	b, _ := asn1.Marshal(v)
+	return b
+}
+
+// derConstructed returns the DER encoding of the constructed value of class
+// and tag made up of the encodings parts
+func derConstructed(class, tag int, parts ...[]byte) []byte {
+	return derValue(asn1.RawValue{Class: class, Tag: tag, IsCompound: true, Bytes: bytes.Join(parts, nil)})
+}
+
+// derSeq returns the DER encoding of the sequence of the encodings parts
+func derSeq(parts ...[]byte) []byte {
+	return derConstructed(asn1.ClassUniversal, asn1.TagSequence, parts...)
+}
+
+// derSet returns the DER encoding of the set of the encodings parts
+func derSet(parts ...[]byte) []byte {
+	return derConstructed(asn1.ClassUniversal, asn1.TagSet, parts...)
+}
+
+// derTagged returns the DER encoding of the parts with the context-specific
+// tag
+func derTagged(tag int, parts ...[]byte) []byte {
+	return derConstructed(asn1.ClassContextSpecific, tag, parts...)
+}
@@ -0,0 +1,235 @@
+package gofpdf
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// CodeTokenType is a run of source code text that is printed in one color.
+type CodeTokenType struct {
+	Str string
+	Clr RGBType
+}
+
+// CodeTokenFncType defines a callback that splits one line of source code
+// into colored tokens for syntax highlighting. Tabs in line have already been
+// expanded to spaces. The tokens, joined together, should reproduce line.
+type CodeTokenFncType func(line string) []CodeTokenType
+
+// CodeBlockType assists with the rendering of source code listings. Create a
+// value with NewCodeBlock(), adjust its exported fields as needed and call
+// Render() to print a listing.
+type CodeBlockType struct {
+	// Font family and size in points; the family should be monospaced
+	FontFamily string
+	FontSize   float64
+	// Line height in user units; zero selects 1.3 times the font size
+	LineHt float64
+	// Number of columns between tab stops
+	TabSize int
+	// Space between the block edge and the text in user units; zero selects
+	// half the font size
+	Padding float64
+	// Print line numbers, beginning with FirstLine, in a gutter at the left
+	LineNumbers bool
+	FirstLine   int
+	// Paint the background and draw a frame around the block
+	Fill, Border bool
+	// Colors of the text, background, frame and line numbers
+	ClrText, ClrBackground, ClrBorder, ClrLineNumber RGBType
+	// Optional syntax highlighting callback; nil prints all text in ClrText
+	Tokenize CodeTokenFncType
+}
+
+// NewCodeBlock returns a variable of type CodeBlockType that is initialized
+// to print 9 point Courier on a light gray background with a frame and line
+// numbers.
+func NewCodeBlock() (cb CodeBlockType) {
+	cb.FontFamily = "Courier"
+	cb.FontSize = 9
+	cb.TabSize = 4
+	cb.LineNumbers = true
+	cb.FirstLine = 1
+	cb.Fill = true
+	cb.Border = true
+	cb.ClrText = RGBType{32, 32, 32}
+	cb.ClrBackground = RGBType{245, 245, 245}
+	cb.ClrBorder = RGBType{192, 192, 192}
+	cb.ClrLineNumber = RGBType{140, 140, 140}
+	return
+}
+
+// expandTabs replaces each tab in line with enough spaces to reach the next
+// tab stop.
+func expandTabs(line string, tabSize int) string {
+	if tabSize < 1 || !strings.Contains(line, "\t") {
+		return line
+	}
+	var b strings.Builder
+	col := 0
+	for _, r := range line {
+		if r == '\t' {
+			n := tabSize - col%tabSize
+			b.WriteString(strings.Repeat(" ", n))
+			col += n
+		} else {
+			b.WriteRune(r)
+			col++
+		}
+	}
+	return b.String()
+}
+
+// codeLineType is one printed line of a code block. A long source line is
+// wrapped onto several printed lines, only the first of which is numbered.
+type codeLineType struct {
+	num    int
+	cont   bool
+	tokens []CodeTokenType
+}
+
+// wrap splits the tokens of one source line into printed lines no wider than
+// wd. The current font is used for measurement.
+func (cb CodeBlockType) wrap(pdf *Fpdf, num int, tokens []CodeTokenType, wd float64) (list []codeLineType) {
+	line := codeLineType{num: num}
+	lineWd := 0.0
+	for _, tok := range tokens {
+		start := 0
+		for pos, r := range tok.Str {
+			rWd := pdf.GetStringWidth(string(r))
+			if lineWd+rWd > wd && lineWd > 0 {
+				if pos > start {
+					line.tokens = append(line.tokens, CodeTokenType{tok.Str[start:pos], tok.Clr})
+				}
+				list = append(list, line)
+				line = codeLineType{num: num, cont: true}
+				lineWd = 0
+				start = pos
+			}
+			lineWd += rWd
+		}
+		if start < len(tok.Str) {
+			line.tokens = append(line.tokens, CodeTokenType{tok.Str[start:], tok.Clr})
+		}
+	}
+	return append(list, line)
+}
+
+// Render prints the source code in src as a block that begins at the current
+// vertical position, with its left edge at x and a width of w. A width of zero
+// extends the block to the right margin. Lines that do not fit are wrapped and
+// the block is continued on a new page when the automatic page break
+// threshold is reached. Upon return, the current position is placed at the
+// left margin just below the block, and the font, colors and line width that
+// were in effect are restored.
+func (cb CodeBlockType) Render(pdf *Fpdf, x, w float64, src string) {
+	if pdf.err != nil {
+		return
+	}
+	if w == 0 {
+		w = pdf.w - pdf.rMargin - x
+	}
+	st := StateGet(pdf)
+	familyStr, styleStr, ptSize := pdf.fontFamily, pdf.fontStyleStr(), pdf.fontSizePt
+
+	pdf.SetFont(cb.FontFamily, "", cb.FontSize)
+	fontHt := cb.FontSize / pdf.k
+	lineHt := cb.LineHt
+	if lineHt == 0 {
+		lineHt = 1.3 * fontHt
+	}
+	pad := cb.Padding
+	if pad == 0 {
+		pad = fontHt / 2
+	}
+
+	src = strings.Replace(src, "\r", "", -1)
+	src = strings.TrimRight(src, "\n")
+	srcLines := strings.Split(src, "\n")
+
+	gutter := 0.0
+	if cb.LineNumbers {
+		digits := len(strconv.Itoa(cb.FirstLine + len(srcLines) - 1))
+		gutter = pdf.GetStringWidth(strings.Repeat("0", digits)) + pad
+	}
+	textX := x + pad + gutter
+	textWd := math.Max(w-2*pad-gutter, pdf.GetStringWidth("0"))
+
+	var lines []codeLineType
+	for j, str := range srcLines {
+		str = expandTabs(str, cb.TabSize)
+		var tokens []CodeTokenType
+		if cb.Tokenize != nil {
+			tokens = cb.Tokenize(str)
+		} else {
+			tokens = []CodeTokenType{{str, cb.ClrText}}
+		}
+		lines = append(lines, cb.wrap(pdf, cb.FirstLine+j, tokens, textWd)...)
+	}
+
+	// frame draws the border of the block segment from y0 to y1
+	frame := func(y0, y1 float64) {
+		if cb.Border {
+			pdf.SetDrawColor(cb.ClrBorder.R, cb.ClrBorder.G, cb.ClrBorder.B)
+			pdf.Rect(x, y0, w, y1-y0, "D")
+		}
+	}
+	fill := func(y0, ht float64) {
+		if cb.Fill {
+			pdf.SetFillColor(cb.ClrBackground.R, cb.ClrBackground.G, cb.ClrBackground.B)
+			pdf.Rect(x, y0, w, ht, "F")
+		}
+	}
+
+	y := pdf.y
+	top := y
+	fill(y, pad)
+	y += pad
+	for _, line := range lines {
+		if y+lineHt > pdf.pageBreakTrigger && !pdf.inHeader && !pdf.inFooter && pdf.acceptPageBreakFor(PageBreakBlock, y, lineHt) {
+			frame(top, y)
+			pdf.AddPageFormat(pdf.curOrientation, pdf.curPageSize)
+			if pdf.err != nil {
+				return
+			}
+			pdf.SetFont(cb.FontFamily, "", cb.FontSize)
+			y = pdf.y
+			top = y
+		}
+		fill(y, lineHt)
+		baseY := y + 0.5*lineHt + 0.3*fontHt
+		if cb.LineNumbers && !line.cont {
+			numStr := strconv.Itoa(line.num)
+			pdf.SetTextColor(cb.ClrLineNumber.R, cb.ClrLineNumber.G, cb.ClrLineNumber.B)
+			pdf.Text(textX-pad-pdf.GetStringWidth(numStr), baseY, numStr)
+		}
+		tx := textX
+		for _, tok := range line.tokens {
+			pdf.SetTextColor(tok.Clr.R, tok.Clr.G, tok.Clr.B)
+			pdf.Text(tx, baseY, tok.Str)
+			tx += pdf.GetStringWidth(tok.Str)
+		}
+		y += lineHt
+	}
+	// The bottom padding and edge of the frame are kept above the page
+	// break threshold as well
+	if y+pad > pdf.pageBreakTrigger && !pdf.inHeader && !pdf.inFooter && pdf.acceptPageBreakFor(PageBreakBlock, y, pad) {
+		frame(top, y)
+		pdf.AddPageFormat(pdf.curOrientation, pdf.curPageSize)
+		if pdf.err != nil {
+			return
+		}
+		y = pdf.y
+		top = y
+	}
+	fill(y, pad)
+	y += pad
+	frame(top, y)
+
+	if familyStr != "" {
+		pdf.SetFont(familyStr, styleStr, ptSize)
+	}
+	st.Put(pdf)
+	pdf.SetXY(pdf.lMargin, y)
+}
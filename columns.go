@@ -0,0 +1,328 @@
+package gofpdf
+
+import (
+	"math"
+)
+
+// ColumnText prints txtStr in cols columns of equal width that span the
+// space between the left and right margins, separated by gutter. Text is
+// wrapped to the column width and flows down each column in turn, beginning
+// at the current vertical position. When the columns of a page are full and
+// the automatic page break is accepted, printing continues in the columns of
+// a new page. On the last page the remaining lines are balanced, as in a
+// newspaper, so that the columns end at approximately equal heights rather
+// than filling the leftmost columns first.
+//
+// h is the line height. alignStr is "L" (the default), "C" or "R". Upon
+// return, the current position is at the left margin just below the longest
+// column. ColumnText is a shorthand for SetColumns(), BalanceColumns() and
+// the cells that print the lines; it ends any columns set with SetColumns().
+func (f *Fpdf) ColumnText(cols int, gutter, h float64, txtStr, alignStr string) {
+	f.SetColumns(cols, gutter)
+	if f.err != nil {
+		return
+	}
+	lines := f.SplitText(txtStr, f.w-f.lMargin-f.rMargin)
+	// put prints the first n lines, one cell each
+	put := func(n int) {
+		for _, str := range lines[:n] {
+			f.CellFormat(0, h, str, "", 2, alignStr, false, 0, "")
+		}
+	}
+	fresh := false
+	for len(lines) > 0 && f.err == nil {
+		c := f.columns
+		if c == nil {
+			put(len(lines))
+			break
+		}
+		// The lines that fit in the columns of the current page, which
+		// begin in the first column
+		capacity := c.n * int(math.Floor((f.pageBreakTrigger-f.y)/h))
+		if len(lines) <= capacity {
+			f.BalanceColumns(func() { put(len(lines)) })
+			break
+		}
+		if capacity < 1 && !fresh && !f.inHeader && !f.inFooter {
+			// No line fits in any of the columns, so the page break is
+			// decided as in the last column
+			col := c.col
+			c.col = c.n - 1
+			accept := f.acceptPageBreakFor(PageBreakBlock, f.y, h)
+			c.col = col
+			if accept {
+				f.AddPageFormat(f.curOrientation, f.curPageSize)
+				fresh = true
+				continue
+			}
+		}
+		if capacity < 1 {
+			// Make progress even if a line is taller than the page or the
+			// page break is declined
+			capacity = 1
+		}
+		put(capacity)
+		lines = lines[capacity:]
+		fresh = false
+	}
+	f.SetColumns(0, 0)
+}
+
+// columnsType is the state of the columns set with SetColumns()
+type columnsType struct {
+	n                int     // number of columns
+	gutter, wd       float64 // space between columns and width of each
+	col              int     // current column, from 0
+	top, bottom      float64 // top of the columns on the current page and lowest position reached in them
+	lMargin, rMargin float64 // margins of the page
+	balance          bool    // content is being balanced by BalanceColumns()
+	overflow         float64 // height of the content that did not fit in the last column while balancing
+}
+
+// SetColumns divides the space between the left and right margins into n
+// columns of equal width, separated by gutter, into which subsequent content
+// flows. Content begins in the first column at the current vertical
+// position. When a column is full, where content would otherwise break the
+// page, it continues at the top of the next column; when the last column of
+// a page is full, the page break is decided as usual and content continues
+// in the first column of the new page, below its header. ColumnBreak() moves
+// to the next column explicitly.
+//
+// The columns are realized by setting the left and right margins to those of
+// the current column, so cells of width zero, MultiCell(), Write() and
+// related methods fit their text to the column. The header and footer are
+// printed with the margins of the page.
+//
+// Calling SetColumns with n less than 2 ends the columns, restoring the
+// margins and placing the current position at the left margin below the
+// longest column. A new set of columns likewise begins below the longest
+// column of the previous set.
+func (f *Fpdf) SetColumns(n int, gutter float64) {
+	if f.err != nil {
+		return
+	}
+	y := f.y
+	if c := f.columns; c != nil {
+		f.lMargin, f.rMargin = c.lMargin, c.rMargin
+		y = math.Max(y, c.bottom)
+		f.columns = nil
+	}
+	f.SetXY(f.lMargin, y)
+	if n < 2 {
+		return
+	}
+	wd := (f.w - f.lMargin - f.rMargin - float64(n-1)*gutter) / float64(n)
+	if wd <= 0 {
+		f.SetErrorf("%d columns with a gutter of %.2f do not fit between the margins", n, gutter)
+		return
+	}
+	f.columns = &columnsType{n: n, gutter: gutter, wd: wd, top: y, bottom: y,
+		lMargin: f.lMargin, rMargin: f.rMargin}
+	f.columnSet(0)
+}
+
+// ColumnBreak moves the current position to the top of the next column set
+// with SetColumns(). In the last column, or if no columns are set, a new
+// page is begun.
+func (f *Fpdf) ColumnBreak() {
+	if f.err != nil {
+		return
+	}
+	if c := f.columns; c != nil && c.col < c.n-1 {
+		c.bottom = math.Max(c.bottom, f.y)
+		f.columnSet(c.col + 1)
+		return
+	}
+	f.AddPageFormat(f.curOrientation, f.curPageSize)
+}
+
+// BalanceColumns prints the content produced by fnc in the columns set with
+// SetColumns(), so that the columns end at approximately equal heights, as
+// in a newspaper, rather than filling the leftmost columns first. The
+// content begins in the first column below the longest column printed so
+// far, and upon return the current position is in the first column below
+// the balanced columns, where further content, such as another set of
+// balanced columns, can follow.
+//
+// Balancing requires the height of the content, so fnc is called more than
+// once: the output of all but the last call is discarded, together with the
+// bookmarks, anchors, named destinations, index marks and counter values it
+// records. fnc should
+// therefore only print content, and should leave the current position below
+// it, as MultiCell() and Ln() do. It must not add a page or call
+// ColumnBreak(). Content that does not fit in the columns of the current
+// page is not balanced, but flows to the columns of the following pages as
+// usual. If no columns are set, fnc is simply called.
+func (f *Fpdf) BalanceColumns(fnc func()) {
+	c := f.columns
+	if f.err != nil {
+		return
+	}
+	if c == nil || f.page == 0 {
+		fnc()
+		return
+	}
+	f.columnsRestart()
+	page, buf, trigger := f.page, f.pages[f.page], f.pageBreakTrigger
+	pos, linkCount, attachCount := buf.Len(), len(f.pageLinks[page]), len(f.pageAttachments[page])
+	elemCount, fieldCount, overflowCount := len(f.elements), len(f.formFields), len(f.overflows)
+	st, marks := f.drawState(), f.saveMarks()
+	// run calls fnc with the bottom of the columns at limit and reports
+	// whether the content fits. Content that does not fit in the last column
+	// extends below it.
+	run := func(limit float64) bool {
+		c.balance, c.overflow = true, 0
+		f.pageBreakTrigger = limit
+		fnc()
+		c.balance, f.pageBreakTrigger = false, trigger
+		if f.err == nil && f.page != page {
+			f.SetErrorf("balanced column content adds a page")
+		}
+		return c.overflow == 0
+	}
+	// discard removes the output of a call of fnc
+	discard := func() {
+		buf.Truncate(pos)
+		f.pageLinks[page] = f.pageLinks[page][:linkCount]
+		f.pageAttachments[page] = f.pageAttachments[page][:attachCount]
+		f.elements = f.elements[:elemCount]
+		f.formFields = f.formFields[:fieldCount]
+		f.overflows = f.overflows[:overflowCount]
+		f.restoreMarks(marks)
+		f.setDrawState(st)
+		c.col = 0
+	}
+
+	// The height of the content in a single column
+	n := c.n
+	c.n = 1
+	run(math.MaxFloat64)
+	c.n = n
+	ht := f.y - st.y
+	discard()
+	// Columns of height ht/n are too short unless the content divides
+	// evenly; each attempt that leaves content over lengthens the columns
+	// by the height of the content that did not fit
+	for limit := st.y + ht/float64(n); limit < trigger && f.err == nil; limit += c.overflow {
+		if run(limit) {
+			f.columnsRestart()
+			return
+		}
+		discard()
+		if c.overflow <= 0 {
+			break
+		}
+	}
+	if f.err != nil {
+		return
+	}
+	fnc()
+	f.columnsRestart()
+}
+
+// markState holds the bookmarks, section marks, anchors, named
+// destinations, index marks and counters of a document, so that those
+// recorded by content that is discarded can be removed
+type markState struct {
+	outlineCount, sectionCount, indexCount int
+	anchors                                map[string]anchorType
+	namedDests                             map[string]intLinkType
+	counters                               map[string]int
+	counterRefs                            map[string]counterRefType
+	counterFwdRefs                         map[string]string
+}
+
+// saveMarks returns the current bookmarks, section marks, anchors, named
+// destinations, index marks and counters
+func (f *Fpdf) saveMarks() markState {
+	return markState{
+		outlineCount:   len(f.outlines),
+		sectionCount:   len(f.sectionMarks),
+		indexCount:     len(f.indexMarks),
+		anchors:        f.anchors,
+		namedDests:     f.namedDests,
+		counters:       f.counters,
+		counterRefs:    f.counterRefs,
+		counterFwdRefs: f.counterFwdRefs,
+	}.copy()
+}
+
+// restoreMarks removes the bookmarks, section marks, anchors, named
+// destinations, index marks and counter values recorded since m was saved by
+// saveMarks(). m is left unchanged, so it can be restored again.
+func (f *Fpdf) restoreMarks(m markState) {
+	m = m.copy()
+	f.outlines = f.outlines[:m.outlineCount]
+	f.sectionMarks = f.sectionMarks[:m.sectionCount]
+	f.indexMarks = f.indexMarks[:m.indexCount]
+	f.anchors, f.namedDests = m.anchors, m.namedDests
+	f.counters, f.counterRefs, f.counterFwdRefs = m.counters, m.counterRefs, m.counterFwdRefs
+}
+
+// copy returns m with copies of its maps
+func (m markState) copy() markState {
+	anchors := make(map[string]anchorType, len(m.anchors))
+	for key, val := range m.anchors {
+		anchors[key] = val
+	}
+	namedDests := make(map[string]intLinkType, len(m.namedDests))
+	for key, val := range m.namedDests {
+		namedDests[key] = val
+	}
+	counters := make(map[string]int, len(m.counters))
+	for key, val := range m.counters {
+		counters[key] = val
+	}
+	counterRefs := make(map[string]counterRefType, len(m.counterRefs))
+	for key, val := range m.counterRefs {
+		counterRefs[key] = val
+	}
+	counterFwdRefs := make(map[string]string, len(m.counterFwdRefs))
+	for key, val := range m.counterFwdRefs {
+		counterFwdRefs[key] = val
+	}
+	m.anchors, m.namedDests, m.counters = anchors, namedDests, counters
+	m.counterRefs, m.counterFwdRefs = counterRefs, counterFwdRefs
+	return m
+}
+
+// columnSet moves the current position to the top of column col
+func (f *Fpdf) columnSet(col int) {
+	c := f.columns
+	c.col = col
+	f.lMargin = c.lMargin + float64(col)*(c.wd+c.gutter)
+	f.rMargin = f.w - f.lMargin - c.wd
+	f.x, f.y = f.lMargin, c.top
+}
+
+// columnsRestart begins the columns anew in the first column below the
+// longest column
+func (f *Fpdf) columnsRestart() {
+	c := f.columns
+	c.top = math.Max(c.bottom, f.y)
+	c.bottom = c.top
+	f.columnSet(0)
+}
+
+// columnPageBreak handles content that has met the page break condition in
+// column mode. If the content moves to the next column, or extends below
+// the last column while its content is being balanced, it returns true
+// along with the decision to decline the page break.
+func (f *Fpdf) columnPageBreak() (accept, handled bool) {
+	c := f.columns
+	if c == nil || f.inHeader || f.inFooter {
+		return
+	}
+	if c.col < c.n-1 {
+		c.bottom = math.Max(c.bottom, f.y)
+		f.columnSet(c.col + 1)
+		return false, true
+	}
+	if c.balance {
+		if c.overflow == 0 {
+			c.overflow = f.pageBreakCtx.Height
+		}
+		return false, true
+	}
+	return
+}
@@ -0,0 +1,98 @@
+package gofpdf
+
+// ContinuationFncType defines a function that prints a continuation marker
+// where content is split across pages. txtStr is the marker text and w is the
+// width of the content being split. When next is true, the marker goes at the
+// bottom of the page that is being left and the current position is just
+// below its last line, with room for a marker of height NextHt above the
+// automatic page break threshold. When next is false, the marker goes at the
+// top of the new page; the current position is where the content would resume, and the
+// function should leave it below the marker so that the content does not
+// overwrite it. Automatic page breaks are disabled while the function runs,
+// and the font in effect beforehand is restored when it returns.
+type ContinuationFncType func(txtStr string, w float64, next bool)
+
+// ContinuationMarkersType specifies the markers printed when MultiCell()
+// breaks its content across pages.
+type ContinuationMarkersType struct {
+	// Marker printed below the last line on the page that is being left; an
+	// empty string omits it
+	NextStr string
+	// Height of the marker printed by Fnc below the last line on a page; zero
+	// selects the height of the default marker. The page is broken early
+	// enough to leave room for it above the page break threshold.
+	NextHt float64
+	// Marker printed above the first line on the new page; an empty string
+	// omits it
+	PrevStr string
+	// Optional function that prints the markers; nil prints them right- and
+	// left-aligned, respectively, in a smaller italic version of the current
+	// font
+	Fnc ContinuationFncType
+}
+
+// NewContinuationMarkers returns a value of type ContinuationMarkersType with
+// the markers "(continued)" and "(continued from previous page)" and the
+// default appearance.
+func NewContinuationMarkers() *ContinuationMarkersType {
+	return &ContinuationMarkersType{
+		NextStr: "(continued)",
+		PrevStr: "(continued from previous page)",
+	}
+}
+
+// SetContinuationMarkers specifies the markers that are printed when the
+// content of MultiCell() is split by an automatic page break. A nil value,
+// the default, prints no markers.
+func (f *Fpdf) SetContinuationMarkers(markers *ContinuationMarkersType) {
+	f.contMarkers = markers
+}
+
+// putContinuation prints the continuation marker that precedes (next is true)
+// or follows an automatic page break in split content of width w
+func (f *Fpdf) putContinuation(w float64, next bool) {
+	cm := f.contMarkers
+	if cm == nil || !f.inMultiCell {
+		return
+	}
+	txtStr := cm.PrevStr
+	if next {
+		txtStr = cm.NextStr
+	}
+	if txtStr == "" {
+		return
+	}
+	if w == 0 {
+		w = f.w - f.rMargin - f.x
+	}
+	familyStr, styleStr, ptSize := f.fontFamily, f.fontStyleStr(), f.fontSizePt
+	inHeader := f.inHeader
+	f.inHeader = true
+	if cm.Fnc != nil {
+		cm.Fnc(txtStr, w, next)
+	} else {
+		f.SetFont(familyStr, "I", 0.8*ptSize)
+		ht := 1.2 * f.fontSize
+		if next {
+			f.CellFormat(w, ht, txtStr, "", 0, "R", false, 0, "")
+		} else {
+			f.CellFormat(w, ht, txtStr, "", 2, "L", false, 0, "")
+		}
+	}
+	f.inHeader = inHeader
+	f.SetFont(familyStr, styleStr, ptSize)
+}
+
+// contReserve returns the height kept free below a line printed by
+// MultiCell() for the marker that follows it if the page is broken after it
+func (f *Fpdf) contReserve() float64 {
+	cm := f.contMarkers
+	if cm == nil || !f.inMultiCell || !f.contMore || cm.NextStr == "" {
+		return 0
+	}
+	if cm.NextHt > 0 {
+		return cm.NextHt
+	}
+	// The height of the default marker
+	return 1.2 * 0.8 * f.fontSize
+}
@@ -0,0 +1,59 @@
+package gofpdf
+
+import (
+	"math"
+)
+
+// relativeLuminance returns the relative luminance, from 0 for black to 1
+// for white, of a color given in sRGB components (0 - 255)
+func relativeLuminance(r, g, b int) float64 {
+	lin := func(c int) float64 {
+		v := math.Max(0, math.Min(255, float64(c))) / 255
+		if v <= 0.03928 {
+			return v / 12.92
+		}
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+	return 0.2126*lin(r) + 0.7152*lin(g) + 0.0722*lin(b)
+}
+
+// ContrastTextColor returns black or white, whichever is more readable on a
+// background of the color specified by r, g and b (0 - 255). The choice is
+// the one with the greater contrast ratio as defined by the Web Content
+// Accessibility Guidelines.
+func ContrastTextColor(r, g, b int) (tr, tg, tb int) {
+	l := relativeLuminance(r, g, b)
+	if (l+0.05)/0.05 >= 1.05/(l+0.05) {
+		return 0, 0, 0
+	}
+	return 255, 255, 255
+}
+
+// SetAutoTextContrast turns automatic text contrast on or off. When it is on,
+// the text of cells whose background is filled is printed in black or white,
+// whichever is more readable on the fill color, as determined by
+// ContrastTextColor(). This keeps text readable when fill colors are chosen
+// by data, for example in conditional formatting. The text color set with
+// SetTextColor() or SetTextSpotColor() is used instead if it has been set
+// since the most recent call to SetFillColor(). Spot color fills are not
+// adjusted. Automatic contrast is off by default.
+func (f *Fpdf) SetAutoTextContrast(on bool) {
+	f.autoContrast = on
+}
+
+// GetAutoTextContrast reports whether automatic text contrast, set with
+// SetAutoTextContrast(), is on.
+func (f *Fpdf) GetAutoTextContrast() bool {
+	return f.autoContrast
+}
+
+// cellTextColor returns the operator string of the color to print cell text
+// in, and whether it differs from the fill color and therefore needs to be
+// set
+func (f *Fpdf) cellTextColor(fill bool) (clrStr string, set bool) {
+	if fill && f.autoContrast && !f.textClrExplicit && f.color.fill.mode == colorModeRGB {
+		r, g, b := ContrastTextColor(f.color.fill.ir, f.color.fill.ig, f.color.fill.ib)
+		return rgbColorValue(r, g, b, "g", "rg").str, true
+	}
+	return f.color.text.str, f.colorFlag
+}
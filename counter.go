@@ -0,0 +1,125 @@
+package gofpdf
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// counterRefType is the value of a counter and the page on which it was
+// recorded under a label by StepCounter()
+type counterRefType struct {
+	value, page int
+}
+
+// NewCounter defines a counter named nameStr, such as "figure" or "table",
+// with a value of zero. Counters number the elements of a document so that
+// they can be referred to by label with RefCounter() and RefCounterPage().
+// Defining an existing counter resets it to zero.
+func (f *Fpdf) NewCounter(nameStr string) {
+	if f.counters == nil {
+		f.counters = make(map[string]int)
+	}
+	f.counters[nameStr] = 0
+}
+
+// StepCounter increments the counter nameStr and returns its new value, for
+// example to print "Figure 3" in a caption. If labelStr is not empty, the
+// value and the current page are recorded under labelStr for references made
+// with RefCounter() and RefCounterPage(). An error is set if the counter has
+// not been defined with NewCounter() or if labelStr has already been used.
+func (f *Fpdf) StepCounter(nameStr, labelStr string) int {
+	if f.err != nil {
+		return 0
+	}
+	value, ok := f.counters[nameStr]
+	if !ok {
+		f.err = fmt.Errorf("counter %s has not been defined", nameStr)
+		return 0
+	}
+	value++
+	f.counters[nameStr] = value
+	if labelStr != "" {
+		if _, ok = f.counterRefs[labelStr]; ok {
+			f.err = fmt.Errorf("counter label %s has already been used", labelStr)
+			return 0
+		}
+		if f.counterRefs == nil {
+			f.counterRefs = make(map[string]counterRefType)
+		}
+		f.counterRefs[labelStr] = counterRefType{value: value, page: f.page}
+	}
+	return value
+}
+
+// RefCounter returns the counter value recorded under labelStr by
+// StepCounter(), as text to be printed, for example in "see Figure 3". If
+// the label has not been recorded yet, a placeholder is returned instead and
+// replaced by the value when the document is closed, like the aliases of
+// RegisterAlias(); this allows references to elements that follow. Since
+// the width of the placeholder is used when text containing it is laid out,
+// forward references are best printed where a difference in width does not
+// matter. An error is set when the document is closed if a label that was
+// referred to has not been recorded.
+func (f *Fpdf) RefCounter(labelStr string) string {
+	if ref, ok := f.counterRefs[labelStr]; ok {
+		return strconv.Itoa(ref.value)
+	}
+	return f.counterPlaceholder(labelStr, false)
+}
+
+// RefCounterPage returns the number of the page on which the counter value
+// recorded under labelStr was stepped, for example to print "on page 12". It
+// is otherwise like RefCounter().
+func (f *Fpdf) RefCounterPage(labelStr string) string {
+	if ref, ok := f.counterRefs[labelStr]; ok {
+		return strconv.Itoa(ref.page)
+	}
+	return f.counterPlaceholder(labelStr, true)
+}
+
+// counterPlaceholder returns the placeholder of a forward reference to the
+// value, or if page is true the page, recorded under labelStr
+func (f *Fpdf) counterPlaceholder(labelStr string, page bool) string {
+	key := labelStr
+	if page {
+		key = "\x00" + labelStr
+	}
+	aliasStr, ok := f.counterFwdRefs[key]
+	if !ok {
+		if f.counterFwdRefs == nil {
+			f.counterFwdRefs = make(map[string]string)
+		}
+		aliasStr = "{ref" + strconv.Itoa(len(f.counterFwdRefs)+1) + "}"
+		f.counterFwdRefs[key] = aliasStr
+	}
+	return aliasStr
+}
+
+// resolveCounterRefs registers the values of forward references made with
+// RefCounter() and RefCounterPage() as aliases
+func (f *Fpdf) resolveCounterRefs() {
+	keys := make([]string, 0, len(f.counterFwdRefs))
+	for key := range f.counterFwdRefs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		labelStr, page := key, false
+		if labelStr[0] == 0 {
+			labelStr, page = labelStr[1:], true
+		}
+		ref, ok := f.counterRefs[labelStr]
+		if !ok {
+			if f.err == nil {
+				f.err = fmt.Errorf("counter label %s is referred to but has not been recorded", labelStr)
+			}
+			continue
+		}
+		value := ref.value
+		if page {
+			value = ref.page
+		}
+		f.RegisterAlias(f.counterFwdRefs[key], strconv.Itoa(value))
+	}
+}
@@ -0,0 +1,105 @@
+package gofpdf
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// DebugCharsType specifies the characters that SetDebugChars() makes
+// visible
+type DebugCharsType struct {
+	// Whether characters that have no glyph in the current UTF-8 font are
+	// printed as their code point in hexadecimal between brackets, such as
+	// [1F600]. Variation selectors and joiners, which modify the characters
+	// they accompany rather than being printed, are not shown.
+	Missing bool
+	// Whether control characters are printed as symbols: a pilcrow (¶) at the
+	// end of each line ended by a line feed, an arrow (→) for a tab, or a
+	// guillemet (») if the font lacks the arrow or is not a UTF-8 font, and
+	// the code of any other control character in hexadecimal between
+	// brackets, such as [1B]
+	Control bool
+}
+
+// SetDebugChars turns on a proofing aid that prints characters that would
+// otherwise be invisible, so that unexpected characters in the data of a
+// document show up in proofs rather than as blanks, missing text or
+// unexplained line breaks. It applies to the text printed by Text(),
+// CellFormat(), MultiCell(), Write() and the methods based on them, and to
+// the lines returned by SplitText(); the substituted text is measured and
+// wrapped like any other. Line feeds keep breaking lines where they do so
+// normally. Pass a zero DebugCharsType to turn the aid off. See also
+// SetLayoutGuides().
+func (f *Fpdf) SetDebugChars(dc DebugCharsType) {
+	f.debugChars = dc
+}
+
+// GetDebugChars returns the settings of SetDebugChars().
+func (f *Fpdf) GetDebugChars() DebugCharsType {
+	return f.debugChars
+}
+
+// debugCharsText returns s with the characters selected by SetDebugChars()
+// made visible for the current font. If breaks is true, line feeds follow
+// their pilcrows so that they still break lines.
+func (f *Fpdf) debugCharsText(s string, breaks bool) string {
+	dc := f.debugChars
+	if !dc.Missing && !dc.Control {
+		return s
+	}
+	lf := "¶"
+	if breaks {
+		lf = "¶\n"
+	}
+	var b strings.Builder
+	if !f.isCurrentUTF8 {
+		// Text in fonts other than UTF-8 fonts is encoded in code page 1252
+		if !dc.Control {
+			return s
+		}
+		lf = strings.Replace(lf, "¶", "\xb6", 1)
+		for j := 0; j < len(s); j++ {
+			c := s[j]
+			switch {
+			case c == '\n':
+				b.WriteString(lf)
+			case c == '\t':
+				b.WriteString("\xbb")
+			case c < 0x20 || c == 0x7f:
+				fmt.Fprintf(&b, "[%02X]", c)
+			default:
+				b.WriteByte(c)
+			}
+		}
+		return b.String()
+	}
+	var cmap map[int]int
+	if f.currentFont.utf8File != nil {
+		cmap = f.currentFont.utf8File.charSymbolDictionary
+	}
+	has := func(r rune) bool {
+		_, ok := cmap[int(r)]
+		return ok || cmap == nil
+	}
+	for _, r := range s {
+		switch {
+		case dc.Control && r == '\n':
+			b.WriteString(lf)
+		case dc.Control && r == '\t':
+			if has('→') {
+				b.WriteRune('→')
+			} else {
+				b.WriteRune('»')
+			}
+		case dc.Control && unicode.IsControl(r):
+			fmt.Fprintf(&b, "[%02X]", r)
+		case dc.Missing && !has(r) && !unicode.IsControl(r) && r != ' ' &&
+			!unicode.Is(unicode.Variation_Selector, r) && r != '\u200c' && r != '\u200d':
+			fmt.Fprintf(&b, "[%04X]", r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
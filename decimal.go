@@ -0,0 +1,60 @@
+package gofpdf
+
+import (
+	"strings"
+)
+
+// SetDecimalTab sets the decimal tab used by CellFormat() and MultiCell()
+// when alignStr includes "D". Such text is positioned so that its decimal
+// separator, the last occurrence of sepStr, begins fracWd user units to the
+// left of the cell's right margin, and numbers with different numbers of
+// digits line up on the separator down a column, as on a decimal tab stop.
+// Text without a separator, such as a whole number, ends where the separator
+// would begin. An empty sepStr selects ".". Use the decimal separator of the
+// locale in which amounts are written, for example the DecimalSep field of
+// LocaleType, and the width returned by GetDecimalFracWidth() for the values
+// of the column. A negative fracWd is taken as zero; the default is zero.
+func (f *Fpdf) SetDecimalTab(sepStr string, fracWd float64) {
+	if sepStr == "" {
+		sepStr = "."
+	}
+	if fracWd < 0 {
+		fracWd = 0
+	}
+	f.decimalSep, f.decimalTab = sepStr, fracWd
+}
+
+// GetDecimalTab returns the decimal separator and the width reserved to the
+// right of it for text aligned with "D". See SetDecimalTab().
+func (f *Fpdf) GetDecimalTab() (sepStr string, fracWd float64) {
+	return f.decimalSep, f.decimalTab
+}
+
+// GetDecimalFracWidth returns the greatest width, in the current font, of the
+// parts of the strings in strList that begin with their last occurrence of
+// sepStr, such as ".50" in "1,234.50" or ",50 €" in "1.234,50 €". This is the
+// width to pass to SetDecimalTab() so that the values of a column fit within
+// it. An empty sepStr selects ".".
+func (f *Fpdf) GetDecimalFracWidth(sepStr string, strList ...string) (wd float64) {
+	if sepStr == "" {
+		sepStr = "."
+	}
+	for _, str := range strList {
+		if pos := strings.LastIndex(str, sepStr); pos >= 0 {
+			if w := f.GetStringWidth(str[pos:]); w > wd {
+				wd = w
+			}
+		}
+	}
+	return
+}
+
+// decimalDx returns the offset from the left edge of a cell of width w at
+// which txtStr begins when it is aligned on the decimal tab
+func (f *Fpdf) decimalDx(w float64, txtStr string) float64 {
+	intStr := txtStr
+	if pos := strings.LastIndex(txtStr, f.decimalSep); pos >= 0 {
+		intStr = txtStr[:pos]
+	}
+	return w - f.cMargin - f.decimalTab - f.GetStringWidth(intStr)
+}
@@ -31,6 +31,11 @@ const (
 	cnFpdfVersion = "1.7"
 )
 
+type printStateType struct {
+	dictStr string
+	objNum  int
+}
+
 type blendModeType struct {
 	strokeStr, fillStr, modeStr string
 	objNum                      int
@@ -38,11 +43,19 @@ type blendModeType struct {
 
 type gradientType struct {
 	tp                int // 2: linear, 3: radial
-	clr1Str, clr2Str  string
+	clr1, clr2        colorType
 	x1, y1, x2, y2, r float64
+	stops             []gradientStopType // colors at positions along the gradient, if any
 	objNum            int
 }
 
+// gradientStopType is a color of a gradient at a position from 0 to 1 along
+// the gradient
+type gradientStopType struct {
+	offset float64
+	clr    colorType
+}
+
 const (
 	// OrientationPortrait represents the portrait orientation.
 	OrientationPortrait = "portrait"
@@ -122,6 +135,7 @@ const (
 	colorModeRGB colorMode = iota
 	colorModeSpot
 	colorModeCMYK
+	colorModeCal
 )
 
 type colorType struct {
@@ -130,6 +144,7 @@ type colorType struct {
 	mode       colorMode
 	spotStr    string // name of current spot color
 	gray       bool
+	none       bool // filling or stroking turned off
 	str        string
 }
 
@@ -165,20 +180,21 @@ func (p PointType) XY() (float64, float64) {
 // Changes to this structure should be reflected in its GobEncode and GobDecode
 // methods.
 type ImageInfoType struct {
-	data  []byte  // Raw image data
-	smask []byte  // Soft Mask, an 8bit per-pixel transparency mask
-	n     int     // Image object number
-	w     float64 // Width
-	h     float64 // Height
-	cs    string  // Color space
-	pal   []byte  // Image color palette
-	bpc   int     // Bits Per Component
-	f     string  // Image filter
-	dp    string  // DecodeParms
-	trns  []int   // Transparency mask
-	scale float64 // Document scale factor
-	dpi   float64 // Dots-per-inch found from image file (png only)
-	i     string  // SHA-1 checksum of the above values.
+	data     []byte            // Raw image data
+	smask    []byte            // Soft Mask, an 8bit per-pixel transparency mask
+	n        int               // Image object number
+	w        float64           // Width
+	h        float64           // Height
+	cs       string            // Color space
+	pal      []byte            // Image color palette
+	bpc      int               // Bits Per Component
+	f        string            // Image filter
+	dp       string            // DecodeParms
+	trns     []int             // Transparency mask
+	scale    float64           // Document scale factor
+	dpi      float64           // Dots-per-inch found from image file (png only)
+	i        string            // SHA-1 checksum of the above values.
+	sections []fileSectionType // Image data streamed from a file, if data is nil
 }
 
 func generateImageID(info *ImageInfoType) (string, error) {
@@ -188,6 +204,9 @@ func generateImageID(info *ImageInfoType) (string, error) {
 
 // GobEncode encodes the receiving image to a byte slice.
 func (info *ImageInfoType) GobEncode() (buf []byte, err error) {
+	if len(info.sections) > 0 {
+		return nil, fmt.Errorf("streamed image cannot be encoded")
+	}
 	fields := []interface{}{info.data, info.smask, info.n, info.w, info.h, info.cs,
 		info.pal, info.bpc, info.f, info.dp, info.trns, info.scale, info.dpi}
 	w := new(bytes.Buffer)
@@ -273,6 +292,8 @@ type linkType struct {
 	x, y, wd, ht float64
 	link         int    // Auto-generated internal link ID or...
 	linkStr      string // ...application-provided external link string
+	destStr      string // named destination, see LinkNamed() and LinkRemote()
+	fileStr      string // document of a remote destination
 }
 
 type intLinkType struct {
@@ -283,6 +304,7 @@ type intLinkType struct {
 // outlineType is used for a sidebar outline of bookmarks
 type outlineType struct {
 	text                                   string
+	str                                    string // text as passed to Bookmark()
 	level, parent, first, last, next, prev int
 	y                                      float64
 	p                                      int
@@ -310,9 +332,45 @@ type FontLoader interface {
 	Open(name string) (io.Reader, error)
 }
 
+// PageEventHandler receives notification of page events. See
+// SetPageEventHandler().
+//
+// OnPageStart is called when a page has been added and its header, if any,
+// has been printed. OnPageEnd is called when the footer of a page, if any, has
+// been printed and the page is about to be closed. Both are passed the
+// one-based page number and the size of the page in the unit of measure
+// specified in New().
+type PageEventHandler interface {
+	OnPageStart(pageNum int, size SizeType)
+	OnPageEnd(pageNum int, size SizeType)
+}
+
+// PageEventFuncs is a convenience implementation of PageEventHandler that
+// calls the function fields that are not nil.
+type PageEventFuncs struct {
+	Start func(pageNum int, size SizeType)
+	End   func(pageNum int, size SizeType)
+}
+
+// OnPageStart calls Start if it is not nil.
+func (pe PageEventFuncs) OnPageStart(pageNum int, size SizeType) {
+	if pe.Start != nil {
+		pe.Start(pageNum, size)
+	}
+}
+
+// OnPageEnd calls End if it is not nil.
+func (pe PageEventFuncs) OnPageEnd(pageNum int, size SizeType) {
+	if pe.End != nil {
+		pe.End(pageNum, size)
+	}
+}
+
 // Pdf defines the interface used for various methods. It is implemented by the
 // main FPDF instance as well as templates.
 type Pdf interface {
+	AddCheckBox(nameStr string, x, y, size float64, checked bool, opt FormFieldType)
+	AddComboBox(nameStr string, x, y, w, h float64, options []string, valueStr string, opt FormFieldType)
 	AddFont(familyStr, styleStr, fileStr string)
 	AddFontFromBytes(familyStr, styleStr string, jsonFileBytes, zFileBytes []byte)
 	AddFontFromReader(familyStr, styleStr string, r io.Reader)
@@ -320,27 +378,41 @@ type Pdf interface {
 	AddLink() int
 	AddPage()
 	AddPageFormat(orientationStr string, size SizeType)
+	AddRadioGroup(nameStr string, buttons []FormRadioType, valueStr string, opt FormFieldType)
+	AddSection(nameStr string, fnc func(), needs ...string)
+	AddSignatureField(nameStr string, x, y, w, h float64, sb *SignatureBlockType)
 	AddSpotColor(nameStr string, c, m, y, k byte)
+	AddTOCPage(titleStr string, styles ...TOCLevelType)
+	AddTextField(nameStr string, x, y, w, h float64, valueStr string, opt FormFieldType)
 	AliasNbPages(aliasStr string)
+	Anchor(nameStr string)
 	ArcTo(x, y, rx, ry, degRotate, degStart, degEnd float64)
 	Arc(x, y, rx, ry, degRotate, degStart, degEnd float64, styleStr string)
+	BalanceColumns(fnc func())
+	Barcode128(x, y, w, h float64, codeStr string)
+	BarcodeEAN13(x, y, w, h float64, codeStr string)
 	BeginLayer(id int)
 	Beziergon(points []PointType, styleStr string)
 	Bookmark(txtStr string, level int, y float64)
 	CellFormat(w, h float64, txtStr, borderStr string, ln int, alignStr string, fill bool, link int, linkStr string)
 	Cellf(w, h float64, fmtStr string, args ...interface{})
 	Cell(w, h float64, txtStr string)
+	CellSpans(w, h float64, runs []TextRunType, borderStr, alignStr string, fill bool)
 	Circle(x, y, r float64, styleStr string)
 	ClearError()
+	ClearFloats()
 	ClipCircle(x, y, r float64, outline bool)
 	ClipEllipse(x, y, rx, ry float64, outline bool)
 	ClipEnd()
+	ClipGlyphOutline(g GlyphOutlineType, x, y, sizeUnit float64, outline bool)
 	ClipPolygon(points []PointType, outline bool)
 	ClipRect(x, y, w, h float64, outline bool)
 	ClipRoundedRect(x, y, w, h, r float64, outline bool)
 	ClipText(x, y float64, txtStr string, outline bool)
 	Close()
 	ClosePath()
+	ColumnBreak()
+	ColumnText(cols int, gutter, h float64, txtStr, alignStr string)
 	CreateTemplateCustom(corner PointType, size SizeType, fn func(*Tpl)) Template
 	CreateTemplate(fn func(*Tpl)) Template
 	CurveBezierCubicTo(cx0, cy0, cx1, cy1, x, y float64)
@@ -348,82 +420,165 @@ type Pdf interface {
 	CurveCubic(x0, y0, cx0, cy0, x1, y1, cx1, cy1 float64, styleStr string)
 	CurveTo(cx, cy, x, y float64)
 	Curve(x0, y0, cx, cy, x1, y1 float64, styleStr string)
+	DefineFrame(nameStr string, x, y, w, h float64, nextStr string)
+	DrawGlyphOutline(g GlyphOutlineType, x, y, sizeUnit float64, styleStr string)
 	DrawPath(styleStr string)
+	ElementManifest() (list []ElementType)
 	Ellipse(x, y, rx, ry, degRotate float64, styleStr string)
 	EndLayer()
 	Err() bool
 	Error() error
+	FlowText(w float64, runs []TextRunType, para ParagraphType)
+	FormCheckbox(x, y, size float64, checked bool, labelStr string, st FormStyleType)
+	FormDateField(x, y, h float64, layoutStr, valueStr, captionStr string, st FormStyleType)
+	FormRadio(x, y, size float64, selected bool, labelStr string, st FormStyleType)
+	FormSignatureLine(x, y, w float64, captionStr string, st FormStyleType)
+	FormValues() map[string]string
+	GenerateIndex(titleStr string, columns int)
+	GenerateSeparationPreviews(sp SeparationPreviewType)
+	GenerateVisualIndex(titleStr string, columns int)
 	GetAlpha() (alpha float64, blendModeStr string)
+	GetAnchor(nameStr string) (page int, x, y float64, ok bool)
 	GetAutoPageBreak() (auto bool, margin float64)
+	GetAutoTextContrast() bool
 	GetCellMargin() float64
+	GetCompactOutput() bool
 	GetConversionRatio() float64
+	GetDebugChars() DebugCharsType
+	GetDecimalFracWidth(sepStr string, strList ...string) (wd float64)
+	GetDecimalTab() (sepStr string, fracWd float64)
 	GetDrawColor() (int, int, int)
 	GetDrawSpotColor() (name string, c, m, y, k byte)
+	GetFileStreamThreshold() int64
+	GetFillAlpha() float64
 	GetFillColor() (int, int, int)
 	GetFillSpotColor() (name string, c, m, y, k byte)
+	GetFloatPrecision() int
+	GetFontCIDMap(familyStr, styleStr string) map[rune]int
 	GetFontDesc(familyStr, styleStr string) FontDescType
 	GetFontSize() (ptSize, unitSize float64)
+	GetGlyphOutline(familyStr, styleStr string, r rune) (outline GlyphOutlineType, ok bool)
+	GetImageCaptionStyle() ImageCaptionType
 	GetImageInfo(imageStr string) (info *ImageInfoType)
+	GetLineBreakLanguage() string
+	GetLineHeight() float64
 	GetLineWidth() float64
 	GetMargins() (left, top, right, bottom float64)
+	GetPageContentSharing() bool
 	GetPageSizeStr(sizeStr string) (size SizeType)
 	GetPageSize() (width, height float64)
+	GetPrintTicket() (pt PrintTicketType, ok bool)
+	GetRegisteredFonts() (list []RegisteredFontType)
+	GetRegisteredImages() (list []RegisteredImageType)
+	GetRegisteredTemplates() (list []RegisteredTemplateType)
+	GetRTLMirroring() bool
 	GetStringWidth(s string) float64
+	GetStrokeAlpha() float64
+	GetTextAsPaths() bool
 	GetTextColor() (int, int, int)
 	GetTextSpotColor() (name string, c, m, y, k byte)
 	GetX() float64
 	GetXY() (float64, float64)
 	GetY() float64
+	GoToAnchor(nameStr string, dx, dy float64)
 	HTMLBasicNew() (html HTMLBasicType)
+	HTMLNew() (h HTMLType)
 	Image(imageNameStr string, x, y, w, h float64, flow bool, tp string, link int, linkStr string)
+	ImageFloat(imageNameStr, sideStr string, w, h, gap float64, options ImageOptions)
 	ImageOptions(imageNameStr string, x, y, w, h float64, flow bool, options ImageOptions, link int, linkStr string)
 	ImageTypeFromMime(mimeStr string) (tp string)
 	LinearGradient(x, y, w, h float64, r1, g1, b1, r2, g2, b2 int, x1, y1, x2, y2 float64)
 	LineTo(x, y float64)
 	Line(x1, y1, x2, y2 float64)
+	LoadSystemFont(familyStr, styleStr string)
+	LinkNamed(x, y, w, h float64, nameStr string)
+	LinkRemote(x, y, w, h float64, fileStr, destStr string)
 	LinkString(x, y, w, h float64, linkStr string)
 	Link(x, y, w, h float64, link int)
+	ListItem(indent, h float64, bulletStr, txtStr, alignStr string)
 	Ln(h float64)
+	MarkIndexEntry(termStr string)
+	MarkSection(txtStr string, level int)
+	MeasureText(familyStr, styleStr string, size float64, s string) float64
+	MoveRel(dx, dy float64)
 	MoveTo(x, y float64)
+	MultiCellDropCap(w, h float64, txtStr, alignStr string, dc DropCapType)
 	MultiCell(w, h float64, txtStr, borderStr, alignStr string, fill bool)
+	NewCounter(nameStr string)
+	NewTable(columns []TableColumnType) (t *TableType)
 	Ok() bool
 	OpenLayerPane()
 	OutputAndClose(w io.WriteCloser) error
+	OutputElementManifest(w io.Writer) error
+	OutputFDF(w io.Writer, fileStr string) error
 	OutputFileAndClose(fileStr string) error
 	Output(w io.Writer) error
+	OutputPartial(w io.Writer) error
+	OutputXFDF(w io.Writer, fileStr string) error
+	OverflowReport() []OverflowType
 	PageCount() int
 	PageNo() int
 	PageSize(pageNum int) (wd, ht float64, unitStr string)
+	PlayMacro(nameStr string, x, y, scale float64)
 	PointConvert(pt float64) (u float64)
 	PointToUnitConvert(pt float64) (u float64)
 	Polygon(points []PointType, styleStr string)
+	PopXY()
+	PushXY()
+	PourText(frameStr string, lineHt float64, alignStr string, runs []TextRunType) (rest []TextRunType)
+	QRCode(x, y, size float64, contentStr, levelStr string)
 	RadialGradient(x, y, w, h float64, r1, g1, b1, r2, g2, b2 int, x1, y1, x2, y2, r float64)
 	RawWriteBuf(r io.Reader)
 	RawWriteStr(str string)
+	RecordElement(nameStr, kindStr string, x, y, w, h float64)
+	RecordMacro(nameStr string, fn func())
 	Rect(x, y, w, h float64, styleStr string)
+	RefCounter(labelStr string) string
+	RefCounterPage(labelStr string) string
 	RegisterAlias(alias, replacement string)
 	RegisterImage(fileStr, tp string) (info *ImageInfoType)
 	RegisterImageOptions(fileStr string, options ImageOptions) (info *ImageInfoType)
+	RegisterImagesOptions(fileList []string, options ImageOptions, workers int) (infoList []*ImageInfoType)
 	RegisterImageOptionsReader(imgName string, options ImageOptions, r io.Reader) (info *ImageInfoType)
 	RegisterImageReader(imgName, tp string, r io.Reader) (info *ImageInfoType)
+	RenderSections()
+	ScrubMetadata()
+	SetAcceptPageBreakContextFunc(fnc func(ctx PageBreakContextType) bool)
 	SetAcceptPageBreakFunc(fnc func() bool)
 	SetAlpha(alpha float64, blendModeStr string)
+	SetASCIIStreams(encodingStr string)
 	SetAuthor(authorStr string, isUTF8 bool)
 	SetAutoPageBreak(auto bool, margin float64)
+	SetAutoTextContrast(on bool)
 	SetCatalogSort(flag bool)
 	SetCellMargin(margin float64)
+	SetColumns(n int, gutter float64)
+	SetCompactOutput(compact bool)
 	SetCompression(compress bool)
+	SetContinuationMarkers(markers *ContinuationMarkersType)
 	SetCreationDate(tm time.Time)
 	SetCreator(creatorStr string, isUTF8 bool)
 	SetDashPattern(dashArray []float64, dashPhase float64)
+	SetDebugChars(dc DebugCharsType)
+	SetDecimalTab(sepStr string, fracWd float64)
 	SetDisplayMode(zoomStr, layoutStr string)
+	SetDrawCalRGBColor(r, g, b int)
 	SetDrawColor(r, g, b int)
+	SetDrawLabColor(l, a, b float64)
+	SetDrawNone()
 	SetDrawSpotColor(nameStr string, tint byte)
 	SetError(err error)
 	SetErrorf(fmtStr string, args ...interface{})
+	SetFileStreamThreshold(size int64)
+	SetFillAlpha(alpha float64)
+	SetFillCalRGBColor(r, g, b int)
 	SetFillColor(r, g, b int)
+	SetFillLabColor(l, a, b float64)
+	SetFillNone()
 	SetFillSpotColor(nameStr string, tint byte)
+	SetFloatPrecision(decimals int)
 	SetFont(familyStr, styleStr string, size float64)
+	SetFontEmbedding(familyStr, styleStr string, embed FontEmbeddingType)
 	SetFontLoader(loader FontLoader)
 	SetFontLocation(fontDirStr string)
 	SetFontSize(size float64)
@@ -431,35 +586,72 @@ type Pdf interface {
 	SetFontUnitSize(size float64)
 	SetFooterFunc(fnc func())
 	SetFooterFuncLpi(fnc func(lastPage bool))
+	SetFormValues(values map[string]string)
+	SetGradientInterpolation(spaceStr string)
+	SetHalftone(ht *HalftoneType)
 	SetHeaderFunc(fnc func())
 	SetHeaderFuncMode(fnc func(), homeMode bool)
 	SetHomeXY()
+	SetImageCaptionStyle(st ImageCaptionType)
 	SetJavascript(script string)
+	SetKerning(kerning bool)
 	SetKeywords(keywordsStr string, isUTF8 bool)
+	SetLayoutGuides(guides *LayoutGuidesType)
 	SetLeftMargin(margin float64)
+	SetLineBreakLanguage(langStr string)
 	SetLineCapStyle(styleStr string)
+	SetLineHeight(ht float64)
+	SetLineHeightFactor(factor float64)
 	SetLineJoinStyle(styleStr string)
 	SetLineWidth(width float64)
 	SetLink(link int, y float64, page int)
 	SetMargins(left, top, right float64)
+	SetNamedDest(nameStr string)
+	SetOverflowReport(report bool)
 	SetPageBoxRec(t string, pb PageBox)
 	SetPageBox(t string, x, y, wd, ht float64)
+	SetPageEventHandler(handler PageEventHandler)
 	SetPage(pageNum int)
+	SetPageContentSharing(share bool)
+	SetPagePieceInfo(appStr string, data []byte)
+	SetPDFX(pdfx PDFXType)
+	SetPieceInfo(appStr string, data []byte)
+	SetPortfolio(pf PortfolioType)
+	SetPrintTicket(pt PrintTicketType)
+	SetProducer(producerStr string, isUTF8 bool)
+	SetProtectAttachmentsOnly(on bool)
 	SetProtection(actionFlag byte, userPassStr, ownerPassStr string)
 	SetRightMargin(margin float64)
+	SetRTLMirroring(on bool)
+	SetRunningHeadFunc(maxLevel int, fnc func(head RunningHeadType))
+	SetStrokeAlpha(alpha float64)
 	SetSubject(subjectStr string, isUTF8 bool)
+	SetSystemFontDirs(dirs ...string)
+	SetTextAsPaths(on bool)
+	SetTextCalRGBColor(r, g, b int)
 	SetTextColor(r, g, b int)
+	SetTextShaping(shaping bool)
+	SetTextLabColor(l, a, b float64)
 	SetTextSpotColor(nameStr string, tint byte)
 	SetTitle(titleStr string, isUTF8 bool)
 	SetTopMargin(margin float64)
+	SetTransferFunction(points []PointType)
 	SetUnderlineThickness(thickness float64)
 	SetXmpMetadata(xmpStream []byte)
 	SetX(x float64)
 	SetXY(x, y float64)
 	SetY(y float64)
+	ShapeTextEllipse(x, y, rx, ry, h float64, txtStr, alignStr string) (rest string)
+	ShapeText(points []PointType, h float64, txtStr, alignStr string) (rest string)
+	SpaceLeft() float64
+	SignatureBlock(x, y, w, h float64, sb SignatureBlockType)
 	SplitLines(txt []byte, w float64) [][]byte
+	Stamp(nameStr string, x, y float64)
+	StampOptions(st StampType, x, y float64)
+	StepCounter(nameStr, labelStr string) int
 	String() string
 	SVGBasicWrite(sb *SVGBasicType, scale float64)
+	SVGWrite(img *SVGType, scale float64)
 	Text(x, y float64, txtStr string)
 	TransformBegin()
 	TransformEnd()
@@ -483,11 +675,15 @@ type Pdf interface {
 	UnitToPointConvert(u float64) (pt float64)
 	UseTemplateScaled(t Template, corner PointType, size SizeType)
 	UseTemplate(t Template)
+	WillFit(h float64) bool
 	WriteAligned(width, lineHeight float64, textStr, alignStr string)
 	Writef(h float64, fmtStr string, args ...interface{})
+	WithAlpha(alpha float64, blendModeStr string, fnc func())
+	WithAutoPageBreak(auto bool, margin float64, fnc func())
 	Write(h float64, txtStr string)
 	WriteLinkID(h float64, displayStr string, linkID int)
 	WriteLinkString(h float64, displayStr, targetStr string)
+	WriteSpans(h float64, runs []TextRunType)
 }
 
 // PageBox defines the coordinates and extent of the various page box types
@@ -500,11 +696,13 @@ type PageBox struct {
 type Fpdf struct {
 	isCurrentUTF8    bool                       // is current font used in utf-8 mode
 	isRTL            bool                       // is is right to left mode enabled
+	rtlNoMirror      bool                       // RTL mode leaves layout defaults unmirrored
 	page             int                        // current page number
 	n                int                        // current object number
 	offsets          []int                      // array of object offsets
 	templates        map[string]Template        // templates used in this document
 	templateObjects  map[string]int             // template object IDs within this document
+	macros           map[string]macroType       // graphics snippets recorded with RecordMacro()
 	importedObjs     map[string][]byte          // imported template objects (gofpdi)
 	importedObjPos   map[string]map[int]string  // imported template objects hashes and their positions (gofpdi)
 	importedTplObjs  map[string]string          // imported template names and IDs (hashed) (gofpdi)
@@ -513,6 +711,9 @@ type Fpdf struct {
 	pages            []*bytes.Buffer            // slice[page] of page content; 1-based
 	state            int                        // current document state
 	compress         bool                       // compression flag
+	asciiStreams     string                     // ASCII encoding of stream data, empty for binary
+	shareContent     bool                       // share identical page content streams
+	compact          bool                       // output object streams and a cross-reference stream
 	k                float64                    // scale factor (number of points in user unit)
 	defOrientation   string                     // default orientation
 	curOrientation   string                     // current orientation
@@ -532,11 +733,18 @@ type Fpdf struct {
 	cMargin          float64                    // cell margin
 	x, y             float64                    // current position in user unit
 	lasth            float64                    // height of last printed cell
+	lineHeight       float64                    // line height in user units, zero for lineHeightFactor
+	lineHeightFactor float64                    // line height as a multiple of the font size
 	lineWidth        float64                    // line width in user unit
 	fontpath         string                     // path containing fonts
 	fontLoader       FontLoader                 // used to load font files from arbitrary locations
+	sysFontDirs      []string                   // directories searched by LoadSystemFont()
+	sysFonts         map[string]sysFontType     // index of fonts in sysFontDirs, built when first needed
 	coreFonts        map[string]bool            // array of core font names
 	fonts            map[string]fontDefType     // array of used fonts
+	fontsSelected    map[string]bool            // fonts selected in page content, by index
+	measureFonts     map[string]fontDefType     // core fonts loaded only to be measured by MeasureText()
+	fontPending      bool                       // selection of current font deferred until text is shown
 	fontFiles        map[string]fontFileType    // array of font files
 	diffs            []string                   // array of encoding differences
 	fontFamily       string                     // current font family
@@ -547,16 +755,49 @@ type Fpdf struct {
 	fontSizePt       float64                    // current font size in points
 	fontSize         float64                    // current font size in user unit
 	ws               float64                    // word spacing
+	xyStack          []PointType                // positions saved by PushXY()
+	anchors          map[string]anchorType      // named positions recorded by Anchor()
+	namedDests       map[string]intLinkType     // destinations set with SetNamedDest()
+	elements         []elementType              // placed elements recorded with RecordElement()
+	captionStyle     ImageCaptionType           // style of image captions
+	captionCount     int                        // number of captioned images
+	counters         map[string]int             // values of the counters defined with NewCounter()
+	counterRefs      map[string]counterRefType  // counter values and pages keyed by label
+	counterFwdRefs   map[string]string          // placeholders of forward references keyed by label
+	floatPrecision   int                        // maximum decimal places of numbers in page content, -1 for default
+	decimalSep       string                     // decimal separator of text aligned with "D"
+	decimalTab       float64                    // width reserved to the right of the decimal separator
+	textAsPaths      bool                       // draw text in UTF-8 fonts as filled paths
+	textShaping      bool                       // shape text in UTF-8 fonts with their layout tables
+	kerning          bool                       // kern text in UTF-8 fonts with their kerning pairs
+	shapeCache       map[string][]shapedGlyph   // shaped glyphs of short text strings keyed by font and text
+	textCache        map[string]string          // encodings of short text strings keyed by font and text
+	widthCache       map[string]int             // widths of short text strings keyed by font and text
 	images           map[string]*ImageInfoType  // array of used images
+	imageSources     map[string]imageSourceType // images placed with a resolution limit, by file name
+	streamThreshold  int64                      // size at and above which image and font files are streamed
+	fileStreams      []fileStreamType           // file content inserted into the output
+	streamedLen      int64                      // combined size of the file content in fileStreams
+	stream           *streamType                // output stream of NewStreaming()
+	pageObjs         []int                      // object numbers of the written pages
 	aliasMap         map[string]string          // map of alias->replacement
 	pageLinks        [][]linkType               // pageLinks[page][link], both 1-based
 	links            []intLinkType              // array of internal links
 	attachments      []Attachment               // slice of content to embed globally
 	pageAttachments  [][]annotationAttach       // 1-based array of annotation for file attachments (per page)
+	formFields       []formFieldType            // interactive form fields
+	formValues       map[string]string          // field values set with SetFormValues()
+	formFontObj      int                        // object number of the first font of the interactive form
 	outlines         []outlineType              // array of outlines
 	outlineRoot      int                        // root of outlines
 	autoPageBreak    bool                       // automatic page breaking
 	acceptPageBreak  func() bool                // returns true to accept page break
+	pageBreakCtx     PageBreakContextType       // content that has triggered the pending page break
+	cellBreakKind    string                     // kind of page break reported by CellFormat(), if not PageBreakCell
+	columns          *columnsType               // columns set with SetColumns(), or nil
+	toc              *tocType                   // table of contents requested with AddTOCPage(), or nil
+	overflowReport   bool                       // record the overflows of content
+	overflows        []OverflowType             // overflows recorded since the report was turned on
 	pageBreakTrigger float64                    // threshold used to trigger page breaks
 	inHeader         bool                       // flag set when processing header
 	headerFnc        func()                     // function provided by app and called to write header
@@ -564,10 +805,40 @@ type Fpdf struct {
 	inFooter         bool                       // flag set when processing footer
 	footerFnc        func()                     // function provided by app and called to write footer
 	footerFncLpi     func(bool)                 // function provided by app and called to write footer with last page flag
+	pageEvents       PageEventHandler           // receives page start and end notifications
+	frames           map[string]frameType       // named regions for PourText
+	sections         []sectionType              // sections registered with AddSection()
+	floats           []floatType                // areas of floated images that text flows around
+	contMarkers      *ContinuationMarkersType   // markers printed where MultiCell content is split
+	inMultiCell      bool                       // flag set while MultiCell is printing lines
+	contMore         bool                       // more lines of MultiCell follow the current one
+	sectionMarks     []sectionMarkType          // section titles marked on current page
+	runningHeadTop   string                     // section in effect at top of current page
+	runningHeadLevel int                        // deepest section level reported to running head function
+	runningHeadFnc   func(RunningHeadType)      // function provided by app and called to write running head
+	indexMarks       []indexMarkType            // index terms and the pages on which they were marked
+	indexPending     bool                       // print index when document is closed
+	indexTitle       string                     // heading of index
+	indexColumns     int                        // number of columns in which index is set
+	visualIndex      visualIndexType            // contact sheet of pages printed at start of document
+	sepPreview       *SeparationPreviewType     // separation previews requested, nil if none
+	lineBreakLang    string                     // language of hyphenation and segmentation dictionaries
 	zoomMode         string                     // zoom display mode
 	layoutMode       string                     // layout display mode
 	xmp              []byte                     // XMP metadata
+	xmpObj           int                        // object number of XMP metadata stream
+	pdfx             PDFXType                   // PDF/X conformance settings
+	printTicket      *PrintTicketType           // print job settings
+	printTicketObj   int                        // object number of JDF job ticket file specification
+	portfolio        *PortfolioType             // presentation of the attachments as a portfolio
+	pieceInfo        map[int]map[string][]byte  // private data of applications by page, 0 for the document
+	rgbUsed          bool                       // flag set when a device RGB color other than gray is set
+	calSpaces        map[string]int             // calibrated color spaces in use and their object numbers
+	autoContrast     bool                       // print filled cell text in black or white by fill luminance
+	textClrExplicit  bool                       // text color has been set since the fill color
+	outputIntentObj  int                        // object number of PDF/X output intent
 	producer         string                     // producer
+	scrubMetadata    bool                       // leave producer and dates out of document information
 	title            string                     // title
 	subject          string                     // subject
 	author           string                     // author
@@ -584,14 +855,23 @@ type Fpdf struct {
 	dashPhase        float64                    // dash phase
 	blendList        []blendModeType            // slice[idx] of alpha transparency modes, 1-based
 	blendMap         map[string]int             // map into blendList
+	halftoneStr      string                     // halftone entry of the print state
+	transferStr      string                     // transfer function entry of the print state
+	printStates      []printStateType           // graphics states of halftones and transfer functions
+	printStateMap    map[string]int             // map into printStates, 1-based
 	blendMode        string                     // current blend mode
 	alpha            float64                    // current transpacency
+	strokeAlpha      float64                    // current stroke opacity
+	fillAlpha        float64                    // current fill opacity
 	gradientList     []gradientType             // slice[idx] of gradient records
+	gradientSpace    string                     // color space in which gradients are interpolated, see SetGradientInterpolation()
 	clipNest         int                        // Number of active clipping contexts
 	transformNest    int                        // Number of active transformation contexts
 	err              error                      // Set if error occurs during life cycle of instance
 	protect          protectType                // document protection structure
 	layer            layerRecType               // manages optional layers in document
+	layoutGuides     layoutGuidesRecType        // debugging guides drawn on each page
+	debugChars       DebugCharsType             // invisible characters made visible
 	catalogSort      bool                       // sort resource catalogs in document
 	nJs              int                        // JavaScript object number
 	javascript       *string                    // JavaScript code to include in the PDF
@@ -600,8 +880,9 @@ type Fpdf struct {
 		// Composite values of colors
 		draw, fill, text colorType
 	}
-	spotColorMap           map[string]spotColorType // Map of named ink-based colors
-	userUnderlineThickness float64                  // A custom user underline thickness multiplier.
+	spotColorMap           map[string]spotColorType     // Map of named ink-based colors
+	userUnderlineThickness float64                      // A custom user underline thickness multiplier.
+	fontEmbedding          map[string]FontEmbeddingType // embedding of UTF-8 fonts by font key
 }
 
 type encType struct {
@@ -697,24 +978,26 @@ type FontDescType struct {
 }
 
 type fontDefType struct {
-	Tp           string        // "Core", "TrueType", ...
-	Name         string        // "Courier-Bold", ...
-	Desc         FontDescType  // Font descriptor
-	Up           int           // Underline position
-	Ut           int           // Underline thickness
-	Cw           map[int]int   // Character width by ordinal
-	Enc          string        // "cp1252", ...
-	Diff         string        // Differences from reference encoding
-	File         string        // "Redressed.z"
-	Size1, Size2 int           // Type1 values
-	OriginalSize int           // Size of uncompressed font file
-	N            int           // Set by font loader
-	DiffN        int           // Position of diff in app array, set by font loader
-	i            string        // 1-based position in font list, set by font loader, not this program
-	utf8File     *utf8FontFile // UTF-8 font
-	usedRunes    map[int]int   // CID -> rune mapping for glyph subsetting
-	runeToCID    map[int]int   // rune -> CID mapping for encoding
-	nextCID      int           // next assignable CID value (Type0 fonts)
+	Tp           string               // "Core", "TrueType", ...
+	Name         string               // "Courier-Bold", ...
+	Desc         FontDescType         // Font descriptor
+	Up           int                  // Underline position
+	Ut           int                  // Underline thickness
+	Cw           map[int]int          // Character width by ordinal
+	Enc          string               // "cp1252", ...
+	Diff         string               // Differences from reference encoding
+	File         string               // "Redressed.z"
+	Size1, Size2 int                  // Type1 values
+	OriginalSize int                  // Size of uncompressed font file
+	N            int                  // Set by font loader
+	DiffN        int                  // Position of diff in app array, set by font loader
+	i            string               // 1-based position in font list, set by font loader, not this program
+	utf8File     *utf8FontFile        // UTF-8 font
+	usedRunes    map[int]int          // CID -> rune mapping for glyph subsetting
+	runeToCID    map[int]int          // rune -> CID mapping for encoding
+	nextCID      int                  // next CID for a rune outside the BMP (Type0 fonts)
+	glyphCIDs    map[int]int          // GID -> CID of glyphs substituted by text shaping
+	cidGlyphs    map[int]glyphCIDType // CID -> glyph substituted by text shaping
 }
 
 // UnmarshalJSON implements custom JSON unmarshaling for fontDefType
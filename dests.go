@@ -0,0 +1,103 @@
+package gofpdf
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SetNamedDest records the current page and vertical position as the
+// destination named nameStr. Links made with LinkNamed() jump to it, and other
+// documents can link to it by name with LinkRemote(), as can URLs that end in
+// "#nameddest=" followed by the name. Unlike links made with AddLink(), which
+// are bound to the destination set with SetLink(), a named destination is
+// looked up by the viewer when the link is followed, so links to it can be
+// placed before it is set. It moves with its page if the pages are
+// rearranged, for example by RenderSections(). Setting a destination with an
+// existing name replaces it.
+func (f *Fpdf) SetNamedDest(nameStr string) {
+	if f.err != nil {
+		return
+	}
+	if nameStr == "" {
+		f.err = fmt.Errorf("named destination requires a name")
+		return
+	}
+	if f.page == 0 {
+		f.err = fmt.Errorf("named destination %s cannot be set before the first page", nameStr)
+		return
+	}
+	if f.namedDests == nil {
+		f.namedDests = make(map[string]intLinkType)
+	}
+	f.namedDests[nameStr] = intLinkType{page: f.page, y: f.y}
+}
+
+// LinkNamed puts a link to the destination named nameStr, set with
+// SetNamedDest(), on a rectangular area of the current page. The area is of
+// width w and height h and its upper left corner is at (x, y). The
+// destination may be set before or after the link; a link to a name that is
+// never set does nothing.
+func (f *Fpdf) LinkNamed(x, y, w, h float64, nameStr string) {
+	if f.err != nil {
+		return
+	}
+	f.newLink(x, y, w, h, 0, "")
+	f.pageLinks[f.page][len(f.pageLinks[f.page])-1].destStr = nameStr
+}
+
+// LinkRemote puts a link to another PDF document on a rectangular area of the
+// current page, positioned as for LinkNamed(). fileStr is the file
+// specification of the document, typically a path relative to the linking
+// document, such as "manual.pdf" or "../specs/part2.pdf". destStr is the name
+// of a destination in that document, such as one set with SetNamedDest(), or
+// empty to open the document at its first page.
+func (f *Fpdf) LinkRemote(x, y, w, h float64, fileStr, destStr string) {
+	if f.err != nil {
+		return
+	}
+	if fileStr == "" {
+		f.err = fmt.Errorf("remote link requires a file")
+		return
+	}
+	f.newLink(x, y, w, h, 0, "")
+	pl := &f.pageLinks[f.page][len(f.pageLinks[f.page])-1]
+	pl.fileStr, pl.destStr = fileStr, destStr
+}
+
+// destAnnotStr returns the destination or action of the link annotation of
+// pl if it links to a named or remote destination, and false otherwise
+func (f *Fpdf) destAnnotStr(pl linkType) (string, bool) {
+	if pl.fileStr != "" {
+		destStr := "[0 /Fit]"
+		if pl.destStr != "" {
+			destStr = f.textstring(pl.destStr)
+		}
+		return sprintf("/A <</S /GoToR /F %s /D %s>>", f.textstring(pl.fileStr), destStr), true
+	}
+	if pl.destStr != "" {
+		return sprintf("/Dest %s", f.textstring(pl.destStr)), true
+	}
+	return "", false
+}
+
+// destPutNames writes the name tree of the named destinations in the name
+// dictionary of the catalog
+func (f *Fpdf) destPutNames() {
+	if len(f.namedDests) == 0 {
+		return
+	}
+	list := make([]string, 0, len(f.namedDests))
+	for nameStr := range f.namedDests {
+		list = append(list, nameStr)
+	}
+	// Keys of a name tree are in the order of their bytes
+	sort.Strings(list)
+	var names fmtBuffer
+	for j, nameStr := range list {
+		if j > 0 {
+			names.printf(" ")
+		}
+		names.printf("%s %s", f.textstring(nameStr), f.destStr(f.namedDests[nameStr]))
+	}
+	f.outf("/Dests <</Names [%s]>>", names.String())
+}
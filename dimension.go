@@ -0,0 +1,201 @@
+package gofpdf
+
+import (
+	"math"
+)
+
+// DimensionType assists with the annotation of technical drawings. It draws
+// linear, radial and angular dimensions complete with arrowheads, extension
+// lines and measurement text. Create a value with NewDimension(), adjust its
+// exported fields as needed and call Linear(), Radial() or Angular().
+type DimensionType struct {
+	// Color and thickness of lines and arrowheads
+	Clr    RGBType
+	LineWd float64
+	// Length and width of arrowheads in page units
+	ArrowLen, ArrowWd float64
+	// Gap between a measured point and its extension line, and distance the
+	// extension line continues beyond the dimension line, in page units
+	ExtGap, ExtOver float64
+	// Font of the measurement text; an empty family selects the current
+	// family. FontSize is in points.
+	FontFamily, FontStyle string
+	FontSize              float64
+	// Measured lengths are multiplied by Scale, formatted by Format with
+	// Precision decimal places and followed by UnitStr. Format defaults to
+	// fixed-point formatting.
+	Scale     float64
+	Precision int
+	UnitStr   string
+	Format    TickFormatFncType
+}
+
+// NewDimension returns a variable of type DimensionType that is initialized
+// to draw thin black dimensions with 8 point Helvetica text, measured in the
+// page units established in New() and shown with one decimal place. size is
+// the length of the arrowheads in page units; the other clearances are
+// derived from it.
+func NewDimension(size float64) (dim DimensionType) {
+	dim.LineWd = size / 12
+	dim.ArrowLen = size
+	dim.ArrowWd = size / 3
+	dim.ExtGap = size / 2
+	dim.ExtOver = size / 2
+	dim.FontFamily = "Helvetica"
+	dim.FontSize = 8
+	dim.Scale = 1
+	dim.Precision = 1
+	return
+}
+
+// text returns txtStr or, if it is empty, the formatted measurement val
+func (dim DimensionType) text(val float64, unitStr, txtStr string) string {
+	if txtStr != "" {
+		return txtStr
+	}
+	format := dim.Format
+	if format == nil {
+		format = defaultFormatter
+	}
+	return format(val, dim.Precision) + unitStr
+}
+
+// begin sets up the drawing state and returns the state to be restored
+func (dim DimensionType) begin(pdf *Fpdf) (st StateType, familyStr, styleStr string, ptSize float64) {
+	st = StateGet(pdf)
+	familyStr, styleStr, ptSize = pdf.fontFamily, pdf.fontStyleStr(), pdf.fontSizePt
+	pdf.SetDrawColor(dim.Clr.R, dim.Clr.G, dim.Clr.B)
+	pdf.SetFillColor(dim.Clr.R, dim.Clr.G, dim.Clr.B)
+	pdf.SetTextColor(dim.Clr.R, dim.Clr.G, dim.Clr.B)
+	pdf.SetLineWidth(dim.LineWd)
+	fontFamily := dim.FontFamily
+	if fontFamily == "" {
+		fontFamily = familyStr
+	}
+	pdf.SetFont(fontFamily, dim.FontStyle, dim.FontSize)
+	return
+}
+
+// end restores the drawing state saved by begin
+func (dim DimensionType) end(pdf *Fpdf, st StateType, familyStr, styleStr string, ptSize float64) {
+	if familyStr != "" {
+		pdf.SetFont(familyStr, styleStr, ptSize)
+	}
+	st.Put(pdf)
+}
+
+// arrow draws an arrowhead with its tip at (x, y) pointing in the direction
+// (ux, uy), which must be a unit vector
+func (dim DimensionType) arrow(pdf *Fpdf, x, y, ux, uy float64) {
+	bx, by := x-ux*dim.ArrowLen, y-uy*dim.ArrowLen
+	nx, ny := -uy*dim.ArrowWd/2, ux*dim.ArrowWd/2
+	pdf.Polygon([]PointType{{x, y}, {bx + nx, by + ny}, {bx - nx, by - ny}}, "F")
+}
+
+// label prints txtStr centered above the point (x, y), parallel to the
+// direction (ux, uy) and turned so that it is never upside down
+func (dim DimensionType) label(pdf *Fpdf, x, y, ux, uy float64, txtStr string) {
+	angle := math.Atan2(-uy, ux) * 180 / math.Pi
+	if angle > 90.001 {
+		angle -= 180
+	} else if angle < -90.001 {
+		angle += 180
+	}
+	pdf.TransformBegin()
+	pdf.TransformRotate(angle, x, y)
+	pdf.Text(x-pdf.GetStringWidth(txtStr)/2, y-dim.ExtGap/2, txtStr)
+	pdf.TransformEnd()
+}
+
+// Linear draws a dimension for the distance between the points (x1, y1) and
+// (x2, y2). The dimension line is drawn parallel to the line joining the
+// points, offset from it by offset page units; a positive offset places it
+// to the left of the line when travelling from the first point to the second
+// as seen on the page, so that a horizontal dimension measured from left to
+// right is drawn above the points. Extension lines lead from the points to
+// the dimension line. txtStr is printed centered above the dimension line;
+// if it is empty, the measured distance is printed instead.
+func (dim DimensionType) Linear(pdf *Fpdf, x1, y1, x2, y2, offset float64, txtStr string) {
+	dx, dy := x2-x1, y2-y1
+	length := math.Hypot(dx, dy)
+	if length == 0 || pdf.err != nil {
+		return
+	}
+	ux, uy := dx/length, dy/length
+	nx, ny := uy, -ux
+	st, familyStr, styleStr, ptSize := dim.begin(pdf)
+	if offset != 0 {
+		sign := math.Copysign(1, offset)
+		for _, pt := range []PointType{{x1, y1}, {x2, y2}} {
+			pdf.Line(pt.X+nx*dim.ExtGap*sign, pt.Y+ny*dim.ExtGap*sign,
+				pt.X+nx*(offset+dim.ExtOver*sign), pt.Y+ny*(offset+dim.ExtOver*sign))
+		}
+	}
+	ax, ay := x1+nx*offset, y1+ny*offset
+	bx, by := x2+nx*offset, y2+ny*offset
+	pdf.Line(ax, ay, bx, by)
+	dim.arrow(pdf, ax, ay, -ux, -uy)
+	dim.arrow(pdf, bx, by, ux, uy)
+	dim.label(pdf, (ax+bx)/2, (ay+by)/2, ux, uy, dim.text(length*dim.Scale, dim.UnitStr, txtStr))
+	dim.end(pdf, st, familyStr, styleStr, ptSize)
+}
+
+// Radial draws a dimension for the radius r of a circle centered at
+// (x, y). The leader runs from the center to the circumference at angleDeg
+// degrees, measured counter-clockwise from the 3 o'clock position, and ends
+// in an arrowhead. txtStr is printed along the leader; if it is empty, the
+// measured radius is printed, preceded by "R".
+func (dim DimensionType) Radial(pdf *Fpdf, x, y, r, angleDeg float64, txtStr string) {
+	if r <= 0 || pdf.err != nil {
+		return
+	}
+	a := angleDeg * math.Pi / 180
+	ux, uy := math.Cos(a), -math.Sin(a)
+	st, familyStr, styleStr, ptSize := dim.begin(pdf)
+	px, py := x+ux*r, y+uy*r
+	pdf.Line(x, y, px, py)
+	dim.arrow(pdf, px, py, ux, uy)
+	if txtStr == "" {
+		txtStr = "R" + dim.text(r*dim.Scale, dim.UnitStr, "")
+	}
+	dim.label(pdf, x+ux*r/2, y+uy*r/2, ux, uy, txtStr)
+	dim.end(pdf, st, familyStr, styleStr, ptSize)
+}
+
+// Angular draws a dimension for the angle between two rays that emanate
+// from (x, y) at startDeg and endDeg degrees, measured counter-clockwise
+// from the 3 o'clock position. The dimension is an arc of radius r, swept
+// counter-clockwise from startDeg to endDeg, with arrowheads at both ends.
+// txtStr is printed horizontally just outside the middle of the arc; if it
+// is empty, the measured angle in degrees is printed. The unit string and
+// scale factor do not apply to angles.
+func (dim DimensionType) Angular(pdf *Fpdf, x, y, r, startDeg, endDeg float64, txtStr string) {
+	if r <= 0 || pdf.err != nil {
+		return
+	}
+	for endDeg < startDeg {
+		endDeg += 360
+	}
+	st, familyStr, styleStr, ptSize := dim.begin(pdf)
+	pdf.Arc(x, y, r, r, 0, startDeg, endDeg, "D")
+	a0, a1 := startDeg*math.Pi/180, endDeg*math.Pi/180
+	dim.arrow(pdf, x+r*math.Cos(a0), y-r*math.Sin(a0), math.Sin(a0), math.Cos(a0))
+	dim.arrow(pdf, x+r*math.Cos(a1), y-r*math.Sin(a1), -math.Sin(a1), -math.Cos(a1))
+	if txtStr == "" {
+		degStr := "\xb0"
+		if pdf.isCurrentUTF8 {
+			degStr = "°"
+		}
+		txtStr = dim.text(endDeg-startDeg, degStr, "")
+	}
+	mid := (a0 + a1) / 2
+	ht := pdf.fontSize
+	tx := x + (r+dim.ExtGap+ht/2)*math.Cos(mid)
+	ty := y - (r+dim.ExtGap+ht/2)*math.Sin(mid)
+	wd := pdf.GetStringWidth(txtStr)
+	// Keep the text clear of the arc by moving it outward by up to half its
+	// width, depending on the direction
+	tx += math.Cos(mid) * wd / 2
+	pdf.Text(tx-wd/2, ty+0.35*ht, txtStr)
+	dim.end(pdf, st, familyStr, styleStr, ptSize)
+}
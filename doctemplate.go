@@ -0,0 +1,147 @@
+package gofpdf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// ParseLength converts a length written with a unit suffix, such as "12pt",
+// "1.5cm", "20mm" or "0.5in", to the unit unitStr, which is one of the units
+// accepted by New(). A number without a suffix is taken to be in unitStr
+// already.
+func ParseLength(lengthStr, unitStr string) (val float64, err error) {
+	to, ok := unitScale(unitStr)
+	if !ok {
+		return 0, fmt.Errorf("incorrect unit %s", unitStr)
+	}
+	s := strings.TrimSpace(lengthStr)
+	numStr := strings.TrimRight(s, "abcdefghijklmnopqrstuvwxyz")
+	from := to
+	if suffix := strings.TrimSpace(s[len(numStr):]); suffix != "" {
+		if from, ok = unitScale(suffix); !ok {
+			return 0, fmt.Errorf("unknown unit in length %q", lengthStr)
+		}
+	}
+	val, err = strconv.ParseFloat(strings.TrimSpace(numStr), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid length %q", lengthStr)
+	}
+	return val * from / to, nil
+}
+
+// docTextEscaper escapes text for use within a JSON string of a document
+// description
+var docTextEscaper = strings.NewReplacer("{", "{{$lbrace}}")
+
+// DocTemplateFuncs returns functions that assist text/template templates in
+// producing document descriptions for ParseDocument() and RenderDocument().
+// This lets an existing templating setup target PDF without a renderer of its
+// own: the template writes the JSON description, and values from the
+// template's data are inserted with these functions so that they cannot break
+// the JSON syntax or be mistaken for bindings. unitStr is the unit of the
+// document, as passed to New(), and styles are the styles that the template
+// may refer to; if styles is nil, style names are not checked. The functions
+// are:
+//
+//	pdftext v        v as text, escaped for use inside a JSON string
+//	pdfjson v        v encoded as a JSON value, including quotes for strings
+//	pdflength s      the length s, such as "12pt" or "2cm", in document units
+//	pdfcolor s       s, in the form "#rgb", "#rrggbb" or "r,g,b", as "#rrggbb"
+//	pdfstyle names   the first of names that is a defined style
+//	pdfstyles        styles encoded as a JSON object
+//
+// Functions that fail stop the execution of the template with an error.
+func DocTemplateFuncs(unitStr string, styles map[string]DocStyleType) template.FuncMap {
+	return template.FuncMap{
+		"pdftext": func(v interface{}) (string, error) {
+			buf, err := json.Marshal(fmt.Sprint(v))
+			if err != nil {
+				return "", err
+			}
+			return docTextEscaper.Replace(string(buf[1 : len(buf)-1])), nil
+		},
+		"pdfjson": func(v interface{}) (string, error) {
+			buf, err := json.Marshal(v)
+			if err != nil {
+				return "", err
+			}
+			if str, ok := v.(string); ok {
+				buf, err = json.Marshal(docTextEscaper.Replace(str))
+			}
+			return string(buf), err
+		},
+		"pdflength": func(s string) (float64, error) {
+			return ParseLength(s, unitStr)
+		},
+		"pdfcolor": parseTemplateColor,
+		"pdfstyle": func(names ...string) (string, error) {
+			for _, name := range names {
+				if _, ok := styles[name]; ok || styles == nil {
+					return name, nil
+				}
+			}
+			return "", fmt.Errorf("none of the styles %s has been defined", strings.Join(names, ", "))
+		},
+		"pdfstyles": func() (string, error) {
+			buf, err := json.Marshal(styles)
+			return string(buf), err
+		},
+	}
+}
+
+// parseTemplateColor normalizes a color for a document description
+func parseTemplateColor(s string) (string, error) {
+	var r, g, b uint64
+	var err error
+	s = strings.TrimSpace(s)
+	switch {
+	case strings.HasPrefix(s, "#") && len(s) == 4:
+		var n uint64
+		n, err = strconv.ParseUint(s[1:], 16, 16)
+		r, g, b = (n>>8)*17, (n>>4&0xf)*17, (n&0xf)*17
+	case strings.HasPrefix(s, "#") && len(s) == 7:
+		var n uint64
+		n, err = strconv.ParseUint(s[1:], 16, 32)
+		r, g, b = n>>16, n>>8&0xff, n&0xff
+	default:
+		list := strings.Split(s, ",")
+		if len(list) != 3 {
+			return "", fmt.Errorf("invalid color %q", s)
+		}
+		vals := make([]uint64, 3)
+		for j, str := range list {
+			if vals[j], err = strconv.ParseUint(strings.TrimSpace(str), 10, 8); err != nil {
+				break
+			}
+		}
+		r, g, b = vals[0], vals[1], vals[2]
+	}
+	if err != nil {
+		return "", fmt.Errorf("invalid color %q", s)
+	}
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b), nil
+}
+
+// RenderDocumentTemplate executes tmpl with data to produce a JSON document
+// description, which is then parsed and rendered by RenderDocument() with
+// the same data. The template would typically use the functions returned by
+// DocTemplateFuncs(). Errors are reported by the returned instance's Error()
+// method.
+func RenderDocumentTemplate(tmpl *template.Template, data interface{}) (f *Fpdf) {
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, data)
+	var doc DocType
+	if err == nil {
+		doc, err = ParseDocument(&buf)
+	}
+	if err != nil {
+		f = New("", "", "", "")
+		f.SetErrorf("unable to render document template: %s", err)
+		return
+	}
+	return RenderDocument(doc, data)
+}
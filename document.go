@@ -0,0 +1,678 @@
+package gofpdf
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DocType is a declarative description of a document that is rendered by
+// RenderDocument(). It is normally decoded from JSON with ParseDocument(), so
+// that documents can be designed by people who do not write Go. Because the
+// fields carry JSON tags, descriptions in other formats such as YAML can be
+// used by decoding them into this type with a suitable package.
+//
+// Text and image names in the description may contain bindings of the form
+// {{path}}, where path is a sequence of map keys and array indexes separated
+// by periods, such as {{customer.name}} or {{items.0.price}}. Bindings are
+// replaced with values from the data passed to RenderDocument(). The special
+// bindings {{$page}} and {{$pages}} are replaced with the current page number
+// and the total number of pages, and {{$lbrace}} is replaced with a left
+// brace so that literal text containing "{{" can be written.
+//
+// A binding may name a format after a vertical bar, as in
+// {{total|currency:EUR}}, to write its value as the document's locale
+// requires: number:N writes a number with N decimal places, currency:CODE
+// writes an amount in the currency with the ISO 4217 code CODE, and
+// date:STYLE writes a date, given in the data as text of the form
+// "2006-01-02" or in RFC 3339 format, in the short, medium or long style of
+// the locale. See LocaleType.
+type DocType struct {
+	// Arguments passed to New(); empty values select the defaults
+	Orientation string `json:"orientation"`
+	Unit        string `json:"unit"`
+	Size        string `json:"size"`
+	FontDir     string `json:"fontDir"`
+	// Language tag of the locale of formatted values, such as "de-DE"; see
+	// GetLocale(). Empty selects "en-US".
+	Locale string `json:"locale"`
+	// Whether the document is written from right to left, as in Arabic or
+	// Hebrew; see RTL(). Text is then aligned on the right unless its style
+	// specifies otherwise, and the columns of tables are laid out from right
+	// to left.
+	RTL bool `json:"rtl"`
+	// Left, top and right page margins; omitted values keep the defaults
+	Margins []float64 `json:"margins"`
+	// Fonts to be loaded in addition to the core fonts
+	Fonts []DocFontType `json:"fonts"`
+	// Named styles referred to by blocks. The style named "default" applies
+	// to blocks that do not name one and supplies the values that other
+	// styles leave unset.
+	Styles map[string]DocStyleType `json:"styles"`
+	// Blocks printed at the top and bottom of every page
+	Header []DocBlockType `json:"header"`
+	Footer []DocBlockType `json:"footer"`
+	// Each page begins with a page break and continues on further pages as
+	// its content requires
+	Pages []DocPageType `json:"pages"`
+}
+
+// DocFontType describes a font file loaded by RenderDocument(). File is
+// relative to the document's font directory. If UTF8 is true, File is a
+// TrueType font loaded with AddUTF8Font(); otherwise it is a font definition
+// file loaded with AddFont().
+type DocFontType struct {
+	Family string `json:"family"`
+	Style  string `json:"style"`
+	File   string `json:"file"`
+	UTF8   bool   `json:"utf8"`
+}
+
+// DocStyleType describes the appearance of a block. Colors are given in the
+// form "#rrggbb". Empty or zero fields take their value from the "default"
+// style.
+type DocStyleType struct {
+	FontFamily string  `json:"fontFamily"`
+	FontStyle  string  `json:"fontStyle"`
+	FontSize   float64 `json:"fontSize"`
+	Color      string  `json:"color"`
+	// Background color; blocks are filled only if it is set
+	Fill string `json:"fill"`
+	// Color of borders and lines
+	Draw string `json:"draw"`
+	// "L", "C", "R" or "J"
+	Align string `json:"align"`
+	// Line height in page units; zero selects 1.25 times the font size
+	LineHt float64 `json:"lineHeight"`
+	// Border specification as used by CellFormat()
+	Border string `json:"border"`
+	// TransformUpper, TransformLower or TransformTitle to change the case of
+	// the text when it is printed, following the rules of the document's
+	// locale; see TransformText(). The data is not modified.
+	Transform string `json:"transform"`
+}
+
+// DocPageType is a sequence of blocks that begins on a new page.
+type DocPageType struct {
+	Orientation string         `json:"orientation"`
+	Size        string         `json:"size"`
+	Blocks      []DocBlockType `json:"blocks"`
+}
+
+// Block types recognized by RenderDocument()
+const (
+	// DocText is a paragraph of wrapped text
+	DocText = "text"
+	// DocImage is an image
+	DocImage = "image"
+	// DocTable is a table with a header row
+	DocTable = "table"
+	// DocLine is a horizontal rule across the block width
+	DocLine = "line"
+	// DocSpace is vertical space of height H
+	DocSpace = "space"
+	// DocPageBreak begins a new page
+	DocPageBreak = "pagebreak"
+)
+
+// DocBlockType is one element of the content of a page. Blocks are printed
+// one below the other, beginning at the current position, unless X or Y is
+// given; a negative Y is measured from the bottom of the page.
+type DocBlockType struct {
+	// DocText, DocImage, DocTable, DocLine, DocSpace or DocPageBreak
+	Type  string   `json:"type"`
+	Style string   `json:"style"`
+	X     *float64 `json:"x"`
+	Y     *float64 `json:"y"`
+	// Width of the block, zero extending it to the right margin, and height
+	// of images and spaces
+	W float64 `json:"w"`
+	H float64 `json:"h"`
+	// Content of a text block
+	Text string `json:"text"`
+	// Name of the file of an image block
+	Image string `json:"image"`
+	// Columns of a table block
+	Columns []DocColumnType `json:"columns"`
+	// Binding of the array that supplies the rows of a table, such as
+	// "{{items}}"; each element is the data from which the fields of its row
+	// are taken
+	Rows string `json:"rows"`
+	// Style of the header row of a table; empty selects the block's style in
+	// bold
+	HeaderStyle string `json:"headerStyle"`
+}
+
+// DocColumnType is one column of a table block. Field is the path of the
+// column's value within the data of each row, for example "name" or
+// "price.net". Format, if not empty, is applied to the values of the column
+// as to a binding, for example "currency:EUR"; see DocType. Align takes the
+// values of the alignStr argument of CellFormat(); with "D", the values of
+// the column are aligned on the decimal separator of the document's locale.
+type DocColumnType struct {
+	Header string `json:"header"`
+	Field  string `json:"field"`
+	// Width of the column; columns without a width share the remaining space
+	W      float64 `json:"w"`
+	Align  string  `json:"align"`
+	Format string  `json:"format"`
+}
+
+// ParseDocument decodes a JSON document description read from r.
+func ParseDocument(r io.Reader) (doc DocType, err error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	err = dec.Decode(&doc)
+	if err != nil {
+		err = fmt.Errorf("unable to parse document description: %s", err)
+	}
+	return
+}
+
+// docRendererType holds the state of a document being rendered
+type docRendererType struct {
+	f      *Fpdf
+	data   interface{}
+	tr     func(string) string
+	styles map[string]DocStyleType
+	locale LocaleType
+}
+
+// RenderDocument renders doc, replacing its bindings with values taken from
+// data, and returns the resulting PDF ready for output. data may be any value
+// that can be encoded as JSON; map keys and the JSON names of struct fields
+// are used in binding paths. Any problem encountered, such as a binding that
+// does not match the data, is reported by the returned instance's Error()
+// method.
+func RenderDocument(doc DocType, data interface{}) (f *Fpdf) {
+	f = New(doc.Orientation, doc.Unit, doc.Size, doc.FontDir)
+	r := docRendererType{f: f, styles: doc.Styles}
+	localeStr := doc.Locale
+	if localeStr == "" {
+		localeStr = "en-US"
+	}
+	var ok bool
+	if r.locale, ok = GetLocale(localeStr); !ok {
+		f.SetErrorf("locale %s has not been registered", doc.Locale)
+		return
+	}
+	// Normalize the data to the generic form produced by the JSON decoder
+	buf, err := json.Marshal(data)
+	if err == nil {
+		err = json.Unmarshal(buf, &r.data)
+	}
+	if err != nil {
+		f.SetErrorf("unable to use document data: %s", err)
+		return
+	}
+	if len(doc.Margins) > 0 {
+		left, top, right, _ := f.GetMargins()
+		for j, val := range doc.Margins {
+			switch j {
+			case 0:
+				left = val
+			case 1:
+				top = val
+			case 2:
+				right = val
+			}
+		}
+		f.SetMargins(left, top, right)
+	}
+	for _, fnt := range doc.Fonts {
+		if fnt.UTF8 {
+			f.AddUTF8Font(fnt.Family, fnt.Style, fnt.File)
+		} else {
+			f.AddFont(fnt.Family, fnt.Style, fnt.File)
+		}
+	}
+	r.tr = f.UnicodeTranslatorFromDescriptor("")
+	if doc.RTL {
+		f.RTL()
+	}
+	f.AliasNbPages("{{$pages}}")
+	if len(doc.Header) > 0 {
+		f.SetHeaderFunc(func() { r.blocks(doc.Header) })
+	}
+	if len(doc.Footer) > 0 {
+		f.SetFooterFunc(func() { r.blocks(doc.Footer) })
+	}
+	for _, page := range doc.Pages {
+		if f.err != nil {
+			break
+		}
+		size := f.defPageSize
+		if page.Size != "" {
+			size = f.GetPageSizeStr(page.Size)
+		}
+		f.AddPageFormat(page.Orientation, size)
+		r.blocks(page.Blocks)
+	}
+	return
+}
+
+// docBindingRe matches a binding
+var docBindingRe = regexp.MustCompile(`{{\s*([^}\s|]+)\s*(?:\|\s*([^}\s]+)\s*)?}}`)
+
+// lookup returns the value found at path within val
+func (r *docRendererType) lookup(val interface{}, path string) (interface{}, bool) {
+	for _, key := range strings.Split(path, ".") {
+		switch v := val.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if val, ok = v[key]; !ok {
+				return nil, false
+			}
+		case []interface{}:
+			n, err := strconv.Atoi(key)
+			if err != nil || n < 0 || n >= len(v) {
+				return nil, false
+			}
+			val = v[n]
+		default:
+			return nil, false
+		}
+	}
+	return val, true
+}
+
+// format returns the text form of a bound value
+func (r *docRendererType) format(val interface{}) string {
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	buf, _ := json.Marshal(val)
+	return string(buf)
+}
+
+// formatAs returns the text form of a bound value in the format formatStr,
+// such as "currency:EUR"; an empty format selects the plain text form
+func (r *docRendererType) formatAs(val interface{}, formatStr string) string {
+	if formatStr == "" {
+		return r.format(val)
+	}
+	kindStr, argStr := formatStr, ""
+	if pos := strings.Index(formatStr, ":"); pos >= 0 {
+		kindStr, argStr = formatStr[:pos], formatStr[pos+1:]
+	}
+	if kindStr == "date" {
+		str, _ := val.(string)
+		tm, err := time.Parse(time.RFC3339, str)
+		if err != nil {
+			tm, err = time.Parse("2006-01-02", str)
+		}
+		if err != nil {
+			r.f.SetErrorf("document value %s is not a date", r.format(val))
+			return ""
+		}
+		if argStr == "" {
+			argStr = "medium"
+		}
+		return r.locale.FormatDate(tm, argStr)
+	}
+	var num float64
+	switch v := val.(type) {
+	case float64:
+		num = v
+	case string:
+		var err error
+		if num, err = strconv.ParseFloat(v, 64); err != nil {
+			r.f.SetErrorf("document value %s is not a number", v)
+			return ""
+		}
+	default:
+		r.f.SetErrorf("document value %s is not a number", r.format(val))
+		return ""
+	}
+	switch kindStr {
+	case "number":
+		decimals := -1
+		if argStr != "" {
+			var err error
+			if decimals, err = strconv.Atoi(argStr); err != nil {
+				r.f.SetErrorf("invalid number format %s", formatStr)
+				return ""
+			}
+		}
+		return r.locale.FormatNumber(num, decimals)
+	case "currency":
+		return r.locale.FormatCurrency(num, argStr)
+	}
+	r.f.SetErrorf("unknown format %s", formatStr)
+	return ""
+}
+
+// bind replaces the bindings in s with values from data
+func (r *docRendererType) bind(s string, data interface{}) string {
+	return docBindingRe.ReplaceAllStringFunc(s, func(match string) string {
+		sub := docBindingRe.FindStringSubmatch(match)
+		path := sub[1]
+		switch path {
+		case "$page":
+			return strconv.Itoa(r.f.PageNo())
+		case "$pages":
+			// Replaced by AliasNbPages() when the document is closed
+			return match
+		case "$lbrace":
+			return "{"
+		}
+		val, ok := r.lookup(data, path)
+		if !ok {
+			if r.f.err == nil {
+				r.f.SetErrorf("document binding %s does not match the data", path)
+			}
+			return ""
+		}
+		return r.formatAs(val, sub[2])
+	})
+}
+
+// text prepares bound text for the current font in style st
+func (r *docRendererType) text(s string, data interface{}, st DocStyleType) string {
+	return r.transform(r.bind(s, data), st)
+}
+
+// transform changes the case of s as st requires and converts it for the
+// current font
+func (r *docRendererType) transform(s string, st DocStyleType) string {
+	if st.Transform != "" {
+		// Leave the alias of the page count intact
+		list := strings.Split(s, "{{$pages}}")
+		for j, str := range list {
+			var err error
+			if list[j], err = TransformText(str, st.Transform, r.locale.Tag); err != nil {
+				r.f.SetError(err)
+			}
+		}
+		s = strings.Join(list, "{{$pages}}")
+	}
+	if !r.f.isCurrentUTF8 {
+		s = r.tr(s)
+	}
+	return s
+}
+
+// style returns the style named nameStr merged with the default style
+func (r *docRendererType) style(nameStr string) (st DocStyleType) {
+	def := r.styles["default"]
+	if nameStr != "" {
+		var ok bool
+		if st, ok = r.styles[nameStr]; !ok && r.f.err == nil {
+			r.f.SetErrorf("document style %s has not been defined", nameStr)
+		}
+	} else {
+		st = def
+	}
+	str := func(s *string, defStr, fallback string) {
+		if *s == "" {
+			*s = defStr
+		}
+		if *s == "" {
+			*s = fallback
+		}
+	}
+	str(&st.FontFamily, def.FontFamily, "Helvetica")
+	str(&st.FontStyle, def.FontStyle, "")
+	str(&st.Color, def.Color, "#000000")
+	str(&st.Fill, def.Fill, "")
+	str(&st.Draw, def.Draw, "#000000")
+	str(&st.Align, def.Align, "L")
+	str(&st.Border, def.Border, "")
+	str(&st.Transform, def.Transform, "")
+	if st.FontSize == 0 {
+		st.FontSize = def.FontSize
+	}
+	if st.FontSize == 0 {
+		st.FontSize = 11
+	}
+	if st.LineHt == 0 {
+		st.LineHt = def.LineHt
+	}
+	return
+}
+
+// color parses a color of the form "#rrggbb"
+func (r *docRendererType) color(s string) (clr RGBType) {
+	if len(s) != 7 || s[0] != '#' {
+		r.f.SetErrorf("invalid document color %q", s)
+		return
+	}
+	n, err := strconv.ParseUint(s[1:], 16, 32)
+	if err != nil {
+		r.f.SetErrorf("invalid document color %q", s)
+		return
+	}
+	return RGBType{int(n >> 16), int(n >> 8 & 0xff), int(n & 0xff)}
+}
+
+// apply sets the font and colors of st and returns its line height
+func (r *docRendererType) apply(st DocStyleType) (lineHt float64) {
+	f := r.f
+	f.SetFont(st.FontFamily, st.FontStyle, st.FontSize)
+	clr := r.color(st.Color)
+	f.SetTextColor(clr.R, clr.G, clr.B)
+	clr = r.color(st.Draw)
+	f.SetDrawColor(clr.R, clr.G, clr.B)
+	if st.Fill != "" {
+		clr = r.color(st.Fill)
+		f.SetFillColor(clr.R, clr.G, clr.B)
+	}
+	lineHt = st.LineHt
+	if lineHt == 0 {
+		lineHt = 1.25 * f.fontSize
+	}
+	return
+}
+
+// blocks renders a list of blocks
+func (r *docRendererType) blocks(list []DocBlockType) {
+	f := r.f
+	for _, blk := range list {
+		if f.err != nil {
+			return
+		}
+		if blk.X != nil {
+			f.SetX(*blk.X)
+		} else {
+			f.SetX(f.lMargin)
+		}
+		if blk.Y != nil {
+			f.SetY(*blk.Y)
+			if blk.X != nil {
+				f.SetX(*blk.X)
+			}
+		}
+		w := blk.W
+		if w == 0 {
+			w = f.w - f.rMargin - f.x
+		}
+		st := r.style(blk.Style)
+		switch blk.Type {
+		case DocText, "":
+			lineHt := r.apply(st)
+			f.MultiCell(w, lineHt, r.text(blk.Text, r.data, st), st.Border, st.Align, st.Fill != "")
+		case DocImage:
+			x := f.x
+			if blk.X == nil {
+				// Let the image flow with the text
+				x = -1
+			}
+			f.ImageOptions(r.bind(blk.Image, r.data), x, -1, blk.W, blk.H, blk.Y == nil,
+				ImageOptions{ReadDpi: true}, 0, "")
+		case DocTable:
+			r.table(blk, st, w)
+		case DocLine:
+			r.apply(st)
+			f.Line(f.x, f.y, f.x+w, f.y)
+		case DocSpace:
+			f.Ln(blk.H)
+		case DocPageBreak:
+			f.AddPageFormat(f.curOrientation, f.curPageSize)
+		default:
+			f.SetErrorf("unknown document block type %s", blk.Type)
+		}
+	}
+}
+
+// table renders a table block
+func (r *docRendererType) table(blk DocBlockType, st DocStyleType, w float64) {
+	f := r.f
+	var rows []interface{}
+	if blk.Rows != "" {
+		m := docBindingRe.FindStringSubmatch(blk.Rows)
+		if m == nil {
+			f.SetErrorf("table rows %s are not a binding", blk.Rows)
+			return
+		}
+		val, ok := r.lookup(r.data, m[1])
+		if !ok {
+			f.SetErrorf("document binding %s does not match the data", m[1])
+			return
+		}
+		if rows, ok = val.([]interface{}); !ok {
+			f.SetErrorf("document binding %s is not an array", m[1])
+			return
+		}
+	}
+	if len(blk.Columns) == 0 {
+		f.SetErrorf("table has no columns")
+		return
+	}
+	// Distribute the remaining width among columns that have none
+	widths := make([]float64, len(blk.Columns))
+	rest, count := w, 0
+	for j, col := range blk.Columns {
+		widths[j] = col.W
+		rest -= col.W
+		if col.W == 0 {
+			count++
+		}
+	}
+	for j := range widths {
+		if widths[j] == 0 {
+			widths[j] = rest / float64(count)
+		}
+	}
+	border := st.Border
+	if border == "" {
+		border = "1"
+	}
+	hst := st
+	if blk.HeaderStyle != "" {
+		hst = r.style(blk.HeaderStyle)
+	} else {
+		hst.FontStyle = "B"
+	}
+	// Columns are laid out in the order of the writing direction
+	order := make([]int, len(blk.Columns))
+	for j := range order {
+		order[j] = j
+		if f.rtlMirrored() {
+			order[j] = len(order) - 1 - j
+		}
+	}
+	x := f.x
+	header := func() {
+		lineHt := r.apply(hst)
+		f.SetX(x)
+		for _, j := range order {
+			f.CellFormat(widths[j], lineHt, r.text(blk.Columns[j].Header, r.data, hst), border, 0, "C", hst.Fill != "", 0, "")
+		}
+		f.Ln(lineHt)
+	}
+	header()
+	lineHt := r.apply(st)
+	cellText := func(val interface{}, col DocColumnType) string {
+		return r.transform(r.formatAs(val, col.Format), st)
+	}
+	// Columns aligned on the decimal separator reserve the width of their
+	// widest fraction
+	aligns := make([]string, len(blk.Columns))
+	fracWds := make([]float64, len(blk.Columns))
+	for j, col := range blk.Columns {
+		aligns[j] = col.Align
+		if aligns[j] == "" {
+			aligns[j] = st.Align
+		}
+		if !strings.Contains(aligns[j], "D") {
+			continue
+		}
+		var list []string
+		for _, row := range rows {
+			if val, ok := r.lookup(row, col.Field); ok {
+				list = append(list, cellText(val, col))
+			}
+		}
+		fracWds[j] = f.GetDecimalFracWidth(r.locale.DecimalSep, list...)
+	}
+	defer f.SetDecimalTab(f.GetDecimalTab())
+	for _, row := range rows {
+		if f.err != nil {
+			return
+		}
+		// Measure the row so that it is kept together on one page
+		cells := make([]string, len(blk.Columns))
+		lines := 1
+		for j, col := range blk.Columns {
+			val, ok := r.lookup(row, col.Field)
+			if !ok {
+				f.SetErrorf("table field %s does not match the data", col.Field)
+				return
+			}
+			cells[j] = cellText(val, col)
+			if n := f.multiCellLineCount(cells[j], widths[j]); n > lines {
+				lines = n
+			}
+		}
+		rowHt := float64(lines) * lineHt
+		if f.y+rowHt > f.pageBreakTrigger && !f.inHeader && !f.inFooter && f.acceptPageBreakFor(PageBreakBlock, f.y, rowHt) {
+			f.AddPageFormat(f.curOrientation, f.curPageSize)
+			header()
+			r.apply(st)
+		}
+		y := f.y
+		cx := x
+		for _, j := range order {
+			alignStr := aligns[j]
+			if strings.Contains(alignStr, "D") {
+				f.SetDecimalTab(r.locale.DecimalSep, fracWds[j])
+			}
+			if st.Fill != "" {
+				f.Rect(cx, y, widths[j], rowHt, "F")
+			}
+			if border == "1" {
+				f.Rect(cx, y, widths[j], rowHt, "D")
+			} else {
+				// Only the sides that are requested
+				right, bottom := cx+widths[j], y+rowHt
+				if strings.Contains(border, "L") {
+					f.Line(cx, y, cx, bottom)
+				}
+				if strings.Contains(border, "T") {
+					f.Line(cx, y, right, y)
+				}
+				if strings.Contains(border, "R") {
+					f.Line(right, y, right, bottom)
+				}
+				if strings.Contains(border, "B") {
+					f.Line(cx, bottom, right, bottom)
+				}
+			}
+			f.SetXY(cx, y)
+			f.MultiCell(widths[j], lineHt, cells[j], "", alignStr, false)
+			cx += widths[j]
+		}
+		f.SetXY(x, y+rowHt)
+	}
+}
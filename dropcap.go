@@ -0,0 +1,152 @@
+package gofpdf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DropCapType specifies the appearance of a drop cap, the enlarged initial
+// letter of a paragraph printed with MultiCellDropCap().
+type DropCapType struct {
+	// Number of lines of body text spanned by the drop cap
+	Lines int
+	// Font family and style of the drop cap; an empty family selects the
+	// current family. The font must use the same encoding as the current
+	// font, since the letter is taken from text meant for it.
+	FontFamily, FontStyle string
+	// Space between the drop cap and the text beside it in user units; zero
+	// selects half the current font size
+	Gap float64
+	// Color of the drop cap
+	Clr RGBType
+}
+
+// NewDropCap returns a variable of type DropCapType that is initialized to
+// print a black drop cap three lines high in the current font family.
+func NewDropCap() (dc DropCapType) {
+	dc.Lines = 3
+	return
+}
+
+// fontCapHeight returns the height of capital letters of the current font
+// in user units
+func (f *Fpdf) fontCapHeight() float64 {
+	capHt := f.currentFont.Desc.CapHeight
+	if capHt == 0 {
+		// The metrics of the core fonts do not include a cap height; 700 is
+		// close to that of each of them
+		capHt = 700
+	}
+	return float64(capHt) * f.fontSize / 1000
+}
+
+// MultiCellDropCap prints txtStr as a paragraph, like MultiCell(), with its
+// first letter enlarged into a drop cap that spans dc.Lines lines. The top of
+// the drop cap is aligned with the capitals of the first line and its
+// baseline with that of the last line it spans. The lines beside the drop cap
+// are shortened to make room for it and the remaining lines use the full
+// width w. A width of zero extends the paragraph to the right margin. h is
+// the line height and alignStr is "L", "C", "R" or "J" (the default). Borders
+// and fill are not supported.
+//
+// If the lines spanned by the drop cap do not fit on the current page, the
+// paragraph is started on a new page. Upon return, the current position is
+// at the left margin below the paragraph or the drop cap, whichever is lower,
+// and the font and text color in effect beforehand are restored.
+func (f *Fpdf) MultiCellDropCap(w, h float64, txtStr, alignStr string, dc DropCapType) {
+	if f.err != nil {
+		return
+	}
+	if f.currentFont.Name == "" {
+		f.err = fmt.Errorf("font has not been set; unable to render text")
+		return
+	}
+	if alignStr == "" {
+		alignStr = "J"
+	}
+	if dc.Lines < 1 {
+		dc.Lines = 1
+	}
+	txtStr = strings.TrimLeft(strings.Replace(txtStr, "\r", "", -1), " \n")
+	if txtStr == "" {
+		return
+	}
+	x := f.x
+	if w == 0 {
+		w = f.w - f.rMargin - x
+	}
+	if f.y+float64(dc.Lines)*h > f.pageBreakTrigger && !f.inHeader && !f.inFooter && f.acceptPageBreakFor(PageBreakBlock, f.y, float64(dc.Lines)*h) {
+		f.AddPageFormat(f.curOrientation, f.curPageSize)
+		if f.err != nil {
+			return
+		}
+		f.x = x
+	}
+	var capStr string
+	if f.isCurrentUTF8 {
+		capStr = graphemeClusters(txtStr)[0]
+	} else {
+		capStr = txtStr[:1]
+	}
+	bodyStr := txtStr[len(capStr):]
+
+	familyStr, styleStr, ptSize := f.fontFamily, f.fontStyleStr(), f.fontSizePt
+	bodyCapHt := f.fontCapHeight()
+	r, g, b := f.GetTextColor()
+	top := f.y
+	// Baselines as placed by CellFormat()
+	baseY := func(line int) float64 {
+		return top + float64(line)*h + 0.5*h + 0.3*f.fontSize
+	}
+	firstBase, lastBase := baseY(0), baseY(dc.Lines-1)
+
+	// Size the drop cap so that its capitals span from the cap height of
+	// the first line to the baseline of the last
+	capFamily := dc.FontFamily
+	if capFamily == "" {
+		capFamily = familyStr
+	}
+	f.SetFont(capFamily, dc.FontStyle, ptSize)
+	scale := (lastBase - firstBase + bodyCapHt) / f.fontCapHeight()
+	f.SetFont(capFamily, dc.FontStyle, ptSize*scale)
+	capWd := f.GetStringWidth(capStr)
+	f.SetTextColor(dc.Clr.R, dc.Clr.G, dc.Clr.B)
+	f.Text(x+f.cMargin, lastBase, capStr)
+	f.SetTextColor(r, g, b)
+	f.SetFont(familyStr, styleStr, ptSize)
+	if f.err != nil {
+		return
+	}
+
+	gap := dc.Gap
+	if gap == 0 {
+		gap = f.fontSize / 2
+	}
+	indent := capWd + gap
+	// The lines beside the drop cap
+	breaks := f.SplitTextBreaks(bodyStr, w-indent)
+	n := dc.Lines
+	if n > len(breaks) {
+		n = len(breaks)
+	}
+	for j := 0; j < n; j++ {
+		f.y = top + float64(j)*h
+		f.putLine(x+indent, w-indent, h, bodyStr, breaks[j], "", alignStr, false)
+	}
+	y := top + float64(n)*h
+	// The remainder of the paragraph at full width
+	if n > 0 && n < len(breaks) {
+		restStr := bodyStr[breaks[n-1].End:]
+		if strings.HasPrefix(restStr, "\n") {
+			restStr = restStr[1:]
+		} else {
+			restStr = strings.TrimLeft(restStr, " ")
+		}
+		f.SetXY(x, y)
+		f.MultiCell(w, h, restStr, "", alignStr, false)
+		y = f.y
+	} else if capBottom := top + float64(dc.Lines)*h; capBottom > y {
+		y = capBottom
+	}
+	f.SetXY(f.lMargin, y)
+}
@@ -0,0 +1,127 @@
+package gofpdf
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+)
+
+// Kinds of elements recorded automatically in the element manifest
+const (
+	// ElementTable is a table laid out with NewTable() whose Element field
+	// is set
+	ElementTable = "table"
+	// ElementImage is an image placed with ImageOptions() whose Element
+	// option is set
+	ElementImage = "image"
+	// ElementSignature is a block printed by SignatureBlock() whose Element
+	// field is set
+	ElementSignature = "signature"
+)
+
+// ElementType describes where a named element of the document has been
+// placed. It is an entry of the manifest returned by ElementManifest().
+type ElementType struct {
+	// Name given to the element by the application
+	Name string `json:"name"`
+	// One of ElementTable, ElementImage and ElementSignature, or the kind
+	// passed to RecordElement()
+	Kind string `json:"kind"`
+	// Number of the page, beginning with 1
+	Page int `json:"page"`
+	// Position of the upper left corner and size of the element in the units
+	// passed to New(), measured from the upper left corner of the page
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	W float64 `json:"w"`
+	H float64 `json:"h"`
+	// The same rectangle in points in the default coordinate system of PDF,
+	// as the lower left and upper right corners [llx lly urx ury] measured
+	// from the lower left corner of the media box. This is the form of the
+	// rectangles of annotations and form fields.
+	Rect [4]float64 `json:"rect"`
+	// Size of the media box of the page in points
+	PageWd float64 `json:"pageWidth"`
+	PageHt float64 `json:"pageHeight"`
+}
+
+// elementType is a rectangle recorded with RecordElement()
+type elementType struct {
+	nameStr, kindStr string
+	page             int
+	x, y, w, h       float64
+}
+
+// RecordElement records that the element nameStr of kind kindStr occupies
+// the rectangle of width w and height h with its upper left corner at (x, y)
+// on the current page, so that its position can be passed to other systems
+// with ElementManifest(). For example, an e-signature platform can place its
+// fields over the signature lines of a contract. Rectangles recorded under
+// the same name and kind on the same page are merged into the rectangle that
+// encloses them, so an element drawn in several steps can be recorded step
+// by step; an element that continues on other pages has an entry for each
+// page.
+//
+// Tables, images and signature blocks record themselves if their Element
+// field or option is set. The rectangle is recorded as given: it is not
+// affected by transformations such as TransformRotate().
+func (f *Fpdf) RecordElement(nameStr, kindStr string, x, y, w, h float64) {
+	if f.err != nil || f.page == 0 || nameStr == "" {
+		return
+	}
+	for j := len(f.elements) - 1; j >= 0; j-- {
+		e := &f.elements[j]
+		if e.nameStr == nameStr && e.kindStr == kindStr && e.page == f.page {
+			x0, y0 := math.Min(e.x, x), math.Min(e.y, y)
+			e.w = math.Max(e.x+e.w, x+w) - x0
+			e.h = math.Max(e.y+e.h, y+h) - y0
+			e.x, e.y = x0, y0
+			return
+		}
+	}
+	f.elements = append(f.elements, elementType{nameStr: nameStr, kindStr: kindStr,
+		page: f.page, x: x, y: y, w: w, h: h})
+}
+
+// ElementManifest returns the elements recorded with RecordElement() and by
+// the tables, images and signature blocks that name themselves, in the order
+// in which they were first recorded, or nil if there are none. The page
+// numbers are final once the document has been closed, since a visual index
+// (see GenerateVisualIndex()) or a reordering of sections may still move the
+// pages until then.
+func (f *Fpdf) ElementManifest() (list []ElementType) {
+	pt := func(v float64) float64 { return math.Round(v*100) / 100 }
+	for _, e := range f.elements {
+		sz := f.visualIndexPageSize(e.page)
+		llx, ury := e.x*f.k, sz.Ht-e.y*f.k
+		list = append(list, ElementType{
+			Name:   e.nameStr,
+			Kind:   e.kindStr,
+			Page:   e.page,
+			X:      e.x,
+			Y:      e.y,
+			W:      e.w,
+			H:      e.h,
+			Rect:   [4]float64{pt(llx), pt(ury - e.h*f.k), pt(llx + e.w*f.k), pt(ury)},
+			PageWd: sz.Wd,
+			PageHt: sz.Ht,
+		})
+	}
+	return
+}
+
+// OutputElementManifest writes the manifest returned by ElementManifest()
+// to w as a JSON array. It is normally called after the document has been
+// output. An empty manifest is written as an empty array.
+func (f *Fpdf) OutputElementManifest(w io.Writer) error {
+	if f.err != nil {
+		return f.err
+	}
+	list := f.ElementManifest()
+	if list == nil {
+		list = []ElementType{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(list)
+}
@@ -0,0 +1,231 @@
+package extract
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/headlands-org/gofpdf"
+)
+
+var reDate = regexp.MustCompile(`^D:(\d{4,14})(Z|[+-]\d{2}(?:'?\d{2})?)?`)
+
+// AttachmentAnnotation is a file that is attached to a page of a document
+// with a file attachment annotation, such as one added with the
+// AddAttachmentAnnotation() method of gofpdf
+type AttachmentAnnotation struct {
+	gofpdf.Attachment
+
+	// X, Y, W and H locate the annotation on its page in points, measured
+	// from the upper left corner of the page like the coordinates of gofpdf;
+	// see the PointToUnitConvert() method of gofpdf for their conversion to
+	// the unit of measure of a document
+	X, Y, W, H float64
+}
+
+// Attachments returns the files that are embedded in the document in
+// pdfBytes as attachments of the document as a whole, such as those set with
+// the SetAttachments() method of gofpdf, in the order of their names.
+//
+// Pages that are imported from a document, for example with the
+// contrib/gofpdi package, leave its attachments behind. With Attachments()
+// and AttachmentAnnotations(), a workflow that merges documents can carry
+// the attachments of its sources through to the merged document, with
+// SetAttachments() and AddAttachmentAnnotation(), or strip them deliberately
+// by leaving them out.
+func Attachments(pdfBytes []byte) ([]gofpdf.Attachment, error) {
+	doc, err := parse(pdfBytes)
+	if err != nil {
+		return nil, err
+	}
+	var list []gofpdf.Attachment
+	names := doc.resolveDict(doc.resolveDict(doc.catalog, "Names"), "EmbeddedFiles")
+	doc.nameTree(names, 0, func(n int) {
+		list = append(list, doc.attachment(doc.objs[n].dict))
+	})
+	return list, nil
+}
+
+// AttachmentAnnotations returns the files that are attached with annotations
+// to the page specified by the one-based page number of the document in
+// pdfBytes, in the order of the annotations of the page. See Attachments()
+// for the use of attachments when documents are merged.
+func AttachmentAnnotations(pdfBytes []byte, page int) ([]AttachmentAnnotation, error) {
+	doc, err := parse(pdfBytes)
+	if err != nil {
+		return nil, err
+	}
+	if page < 1 || page > len(doc.pages) {
+		return nil, fmt.Errorf("page %d out of range (document has %d pages)", page, len(doc.pages))
+	}
+	pageDict := doc.objs[doc.pages[page-1]].dict
+	box := doc.mediaBox(pageDict)
+	var list []AttachmentAnnotation
+	for _, dict := range doc.array(pageDict, "Annots") {
+		if string(value(dict, "Subtype")) != "/FileAttachment" {
+			continue
+		}
+		var an AttachmentAnnotation
+		if fs := doc.resolveDict(dict, "FS"); fs != nil {
+			an.Attachment = doc.attachment(fs)
+		}
+		if rect := numbers(value(dict, "Rect")); len(rect) == 4 {
+			an.X = math.Min(rect[0], rect[2]) - box[0]
+			an.Y = box[3] - math.Max(rect[1], rect[3])
+			an.W = math.Abs(rect[2] - rect[0])
+			an.H = math.Abs(rect[3] - rect[1])
+		}
+		list = append(list, an)
+	}
+	return list, nil
+}
+
+// nameTree calls fn with the object numbers of the values of the name tree
+// node, in the order of their names
+func (doc *document) nameTree(node []byte, depth int, fn func(n int)) {
+	if node == nil || depth > 32 {
+		return
+	}
+	for _, kid := range doc.refs(node, "Kids") {
+		doc.nameTree(doc.objs[kid].dict, depth+1, fn)
+	}
+	arr, _ := lex(value(node, "Names"), 0)
+	items := arr.items
+	for j := 0; j+3 < len(items); j++ {
+		// A name is followed by the reference "n g R"
+		if items[j].kind == tokString && items[j+1].kind == tokNumber &&
+			items[j+2].kind == tokNumber && items[j+3].str == "R" {
+			n, _ := strconv.Atoi(items[j+1].str)
+			fn(n)
+			j += 3
+		}
+	}
+}
+
+// attachment returns the attachment described by the file specification
+// dictionary fs
+func (doc *document) attachment(fs []byte) (a gofpdf.Attachment) {
+	a.Filename = textString(value(fs, "UF"))
+	if a.Filename == "" {
+		a.Filename = textString(value(fs, "F"))
+	}
+	a.Description = textString(value(fs, "Desc"))
+	if refs := doc.refs(value(fs, "EF"), "F"); len(refs) > 0 {
+		obj := doc.objs[refs[0]]
+		a.Content = obj.stream
+		if a.Content == nil {
+			a.Content = []byte{}
+		}
+		a.ModTime = parseDate(textString(value(value(obj.dict, "Params"), "ModDate")))
+	}
+	return
+}
+
+// array returns the dictionaries of the array associated with key in dict,
+// following indirect references to the array and to its elements
+func (doc *document) array(dict []byte, key string) (list [][]byte) {
+	val := value(dict, key)
+	if m := reRef.FindSubmatch(val); m != nil && !bytes.HasPrefix(val, []byte("[")) {
+		n, _ := strconv.Atoi(string(m[1]))
+		val = doc.objs[n].dict
+	}
+	if !bytes.HasPrefix(val, []byte("[")) {
+		return
+	}
+	for pos := skipSpace(val, 1); pos < len(val) && val[pos] != ']'; pos = skipSpace(val, pos) {
+		end := skipValue(val, pos)
+		if end <= pos {
+			break
+		}
+		item := val[pos:end]
+		if m := reRef.FindSubmatch(item); m != nil && !bytes.HasPrefix(item, []byte("<<")) {
+			n, _ := strconv.Atoi(string(m[1]))
+			item = doc.objs[n].dict
+		}
+		list = append(list, item)
+		pos = end
+	}
+	return
+}
+
+// mediaBox returns the media box of the page dictionary dict, which may be
+// inherited from the nodes of the page tree above it
+func (doc *document) mediaBox(dict []byte) []float64 {
+	for depth := 0; dict != nil && depth < 32; depth++ {
+		if box := numbers(value(dict, "MediaBox")); len(box) == 4 {
+			return box
+		}
+		refs := doc.refs(dict, "Parent")
+		if len(refs) == 0 {
+			break
+		}
+		dict = doc.objs[refs[0]].dict
+	}
+	return []float64{0, 0, 612, 792}
+}
+
+// numbers returns the numbers of the array val
+func numbers(val []byte) (list []float64) {
+	arr, _ := lex(val, 0)
+	for _, item := range arr.items {
+		if item.kind == tokNumber {
+			v, _ := strconv.ParseFloat(item.str, 64)
+			list = append(list, v)
+		}
+	}
+	return
+}
+
+// textString returns the text string val, which is encoded in UTF-16 if it
+// begins with a byte order mark and in PDFDocEncoding otherwise
+func textString(val []byte) string {
+	tok, _ := lex(val, 0)
+	if tok.kind != tokString {
+		return ""
+	}
+	b := tok.data
+	if bytes.HasPrefix(b, []byte{0xFE, 0xFF}) {
+		return utf16String(b[2:])
+	}
+	runes := make([]rune, len(b))
+	for j, c := range b {
+		runes[j] = rune(c)
+	}
+	return string(runes)
+}
+
+// parseDate returns the time of the date string dateStr, or the zero time if
+// it is not a date. A date without a time zone is taken to be local time, as
+// gofpdf writes it.
+func parseDate(dateStr string) time.Time {
+	m := reDate.FindStringSubmatch(dateStr)
+	if m == nil || len(m[1])%2 == 1 {
+		return time.Time{}
+	}
+	layout := "20060102150405"[:len(m[1])]
+	loc := time.Local
+	if zone := m[2]; zone == "Z" {
+		loc = time.UTC
+	} else if zone != "" {
+		zone = strings.Replace(zone, "'", "", -1)
+		offset, _ := strconv.Atoi(zone[1:3])
+		offset *= 3600
+		if len(zone) == 5 {
+			mm, _ := strconv.Atoi(zone[3:5])
+			offset += mm * 60
+		}
+		if zone[0] == '-' {
+			offset = -offset
+		}
+		loc = time.FixedZone("", offset)
+	}
+	tm, err := time.ParseInLocation(layout, m[1], loc)
+	if err != nil {
+		return time.Time{}
+	}
+	return tm
+}
@@ -0,0 +1,549 @@
+// Package extract recovers the text printed on the pages of documents that
+// have been generated by gofpdf. It understands the encodings that gofpdf
+// itself produces: the WinAnsi (cp1252) encoding of the core fonts, the
+// difference encodings of fonts made with makefont, and the subset CID
+// encoding of UTF-8 fonts. It also recovers the attachments of documents,
+// which merge workflows can carry through. It is not a general purpose PDF
+// parser; its intended use is in tests that need to make assertions about
+// the text of a generated document, for example
+//
+//	var buf bytes.Buffer
+//	err := pdf.Output(&buf)
+//	...
+//	txt, err := extract.ExtractPageText(buf.Bytes(), 1)
+//	if !strings.Contains(txt, "Page 1 of 2") {
+//		...
+//	}
+//
+// Encrypted documents are not supported.
+package extract
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/ascii85"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/headlands-org/gofpdf"
+)
+
+var (
+	reObj       = regexp.MustCompile(`(?m)^(\d+) 0 obj\b`)
+	reRef       = regexp.MustCompile(`(\d+) 0 R`)
+	reNameRef   = regexp.MustCompile(`/([^\s/<>\[\]()]+)\s+(\d+) 0 R`)
+	reLength    = regexp.MustCompile(`/Length (\d+)`)
+	reEncrypt   = regexp.MustCompile(`/Encrypt\s+\d+ 0 R`)
+	reCatalog   = regexp.MustCompile(`/Type\s*/Catalog\b`)
+	reObjStm    = regexp.MustCompile(`/Type\s*/ObjStm\b`)
+	rePagesNode = regexp.MustCompile(`/Type\s*/Pages\b`)
+	reBaseFont  = regexp.MustCompile(`/BaseFont\s*/([^\s/<>\[\]]+)`)
+	reName      = regexp.MustCompile(`/[^\s/<>\[\]()]+`)
+)
+
+// object is an indirect object of a document: its dictionary (or other
+// value) and, if it has one, its decoded stream
+type object struct {
+	dict   []byte
+	stream []byte
+}
+
+// document holds the objects of a parsed PDF
+type document struct {
+	objs    map[int]object
+	catalog []byte
+	pages   []int
+	fonts   map[int]*fontType
+}
+
+// ExtractPageText returns the text printed on the page specified by the
+// one-based page number of the document in pdfBytes. Each string that is
+// shown on the page, typically the contents of one cell or one line of a
+// MultiCell, is reported on a line of its own, in the order in which it was
+// printed. Text in templates placed on the page is included.
+func ExtractPageText(pdfBytes []byte, page int) (string, error) {
+	doc, err := parse(pdfBytes)
+	if err != nil {
+		return "", err
+	}
+	if page < 1 || page > len(doc.pages) {
+		return "", fmt.Errorf("page %d out of range (document has %d pages)", page, len(doc.pages))
+	}
+	var lines []string
+	obj := doc.objs[doc.pages[page-1]]
+	resources := doc.resolveDict(obj.dict, "Resources")
+	for _, n := range doc.refs(obj.dict, "Contents") {
+		lines, err = doc.content(doc.objs[n].stream, resources, lines, 0)
+		if err != nil {
+			return "", err
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// PageCount returns the number of pages in the document in pdfBytes.
+func PageCount(pdfBytes []byte) (int, error) {
+	doc, err := parse(pdfBytes)
+	if err != nil {
+		return 0, err
+	}
+	return len(doc.pages), nil
+}
+
+// PieceInfo returns the private data that the application appStr has
+// attached to the page specified by the one-based page number of the
+// document in pdfBytes, or to the document itself if page is zero, with
+// SetPagePieceInfo() or SetPieceInfo(). Nil is returned if the application
+// has attached no data.
+func PieceInfo(pdfBytes []byte, page int, appStr string) ([]byte, error) {
+	doc, err := parse(pdfBytes)
+	if err != nil {
+		return nil, err
+	}
+	dict := doc.catalog
+	if page != 0 {
+		if page < 1 || page > len(doc.pages) {
+			return nil, fmt.Errorf("page %d out of range (document has %d pages)", page, len(doc.pages))
+		}
+		dict = doc.objs[doc.pages[page-1]].dict
+	}
+	val := value(value(value(dict, "PieceInfo"), appStr), "Private")
+	if !bytes.HasPrefix(val, []byte("(")) {
+		return nil, nil
+	}
+	tok, _ := lexString(val, 1)
+	return tok.data, nil
+}
+
+func parse(pdfBytes []byte) (doc *document, err error) {
+	if !bytes.HasPrefix(pdfBytes, []byte("%PDF-")) {
+		return nil, errors.New("not a PDF document")
+	}
+	if reEncrypt.Match(pdfBytes) {
+		return nil, errors.New("encrypted documents are not supported")
+	}
+	doc = &document{objs: make(map[int]object), fonts: make(map[int]*fontType)}
+	pos := 0
+	for {
+		loc := reObj.FindSubmatchIndex(pdfBytes[pos:])
+		if loc == nil {
+			break
+		}
+		n, _ := strconv.Atoi(string(pdfBytes[pos+loc[2] : pos+loc[3]]))
+		start := pos + loc[1]
+		end := skipValue(pdfBytes, start)
+		obj := object{dict: pdfBytes[start:end]}
+		pos = end
+		rest := bytes.TrimLeft(pdfBytes[end:], " \r\n")
+		if bytes.HasPrefix(rest, []byte("stream")) {
+			dataStart := len(pdfBytes) - len(rest) + len("stream")
+			if bytes.HasPrefix(pdfBytes[dataStart:], []byte("\r\n")) {
+				dataStart += 2
+			} else if dataStart < len(pdfBytes) && pdfBytes[dataStart] == '\n' {
+				dataStart++
+			}
+			m := reLength.FindSubmatch(obj.dict)
+			if m == nil {
+				return nil, fmt.Errorf("object %d: stream without length", n)
+			}
+			length, _ := strconv.Atoi(string(m[1]))
+			if dataStart+length > len(pdfBytes) {
+				return nil, fmt.Errorf("object %d: truncated stream", n)
+			}
+			obj.stream, err = decodeStream(obj.dict, pdfBytes[dataStart:dataStart+length])
+			if err != nil {
+				return nil, fmt.Errorf("object %d: %s", n, err)
+			}
+			pos = dataStart + length
+		}
+		doc.objs[n] = obj
+	}
+	var stms []object
+	for _, obj := range doc.objs {
+		if reObjStm.Match(obj.dict) {
+			stms = append(stms, obj)
+		}
+	}
+	for _, obj := range stms {
+		doc.unpack(obj)
+	}
+	for _, obj := range doc.objs {
+		if reCatalog.Match(obj.dict) {
+			doc.catalog = obj.dict
+			for _, n := range doc.refs(obj.dict, "Pages") {
+				doc.collectPages(n, 0)
+			}
+			break
+		}
+	}
+	if len(doc.pages) == 0 {
+		return nil, errors.New("no pages found")
+	}
+	return doc, nil
+}
+
+// unpack adds the objects of the object stream stm, such as those of a
+// document output with the SetCompactOutput() method of gofpdf, to doc
+func (doc *document) unpack(stm object) {
+	first, _ := strconv.Atoi(string(value(stm.dict, "First")))
+	if first <= 0 || first > len(stm.stream) {
+		return
+	}
+	// The header lists pairs of object numbers and offsets
+	hdr := numbers([]byte("[" + string(stm.stream[:first]) + "]"))
+	for j := 0; j+1 < len(hdr); j += 2 {
+		start := first + int(hdr[j+1])
+		end := len(stm.stream)
+		if j+3 < len(hdr) {
+			end = first + int(hdr[j+3])
+		}
+		if start > end || end > len(stm.stream) {
+			return
+		}
+		n := int(hdr[j])
+		if _, ok := doc.objs[n]; !ok {
+			doc.objs[n] = object{dict: bytes.TrimSpace(stm.stream[start:end])}
+		}
+	}
+}
+
+// decodeStream applies the filters of the stream dictionary dict to data in
+// order. Decoding stops at a filter that is not implemented, such as the
+// DCTDecode filter of JPEG images, since such streams do not hold text.
+func decodeStream(dict, data []byte) (out []byte, err error) {
+	out = data
+	for _, name := range reName.FindAll(value(dict, "Filter"), -1) {
+		switch string(name) {
+		case "/FlateDecode":
+			out, err = inflate(out)
+		case "/ASCII85Decode":
+			if j := bytes.Index(out, []byte("~>")); j >= 0 {
+				out = out[:j]
+			}
+			dst := make([]byte, 4*len(out)/5+4)
+			var n int
+			n, _, err = ascii85.Decode(dst, out, true)
+			out = dst[:n]
+		case "/ASCIIHexDecode":
+			if j := bytes.IndexByte(out, '>'); j >= 0 {
+				out = out[:j]
+			}
+			out = bytes.Join(bytes.Fields(out), nil)
+			if len(out)%2 == 1 {
+				out = append(out, '0')
+			}
+			dst := make([]byte, len(out)/2)
+			_, err = hex.Decode(dst, out)
+			out = dst
+		default:
+			return
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+func inflate(data []byte) ([]byte, error) {
+	r, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+// collectPages appends the pages below the page tree node n in document order
+func (doc *document) collectPages(n, depth int) {
+	obj, ok := doc.objs[n]
+	if !ok || depth > 32 {
+		return
+	}
+	if rePagesNode.Match(obj.dict) {
+		for _, kid := range doc.refs(obj.dict, "Kids") {
+			doc.collectPages(kid, depth+1)
+		}
+	} else {
+		doc.pages = append(doc.pages, n)
+	}
+}
+
+// value returns the value associated with key in dict, or nil if dict does
+// not contain key
+func value(dict []byte, key string) []byte {
+	pat := []byte("/" + key)
+	for pos := 0; ; {
+		j := bytes.Index(dict[pos:], pat)
+		if j < 0 {
+			return nil
+		}
+		start := pos + j + len(pat)
+		if start < len(dict) && isRegular(dict[start]) {
+			// Key is a prefix of a longer name
+			pos = start
+			continue
+		}
+		start = skipSpace(dict, start)
+		return dict[start:skipValue(dict, start)]
+	}
+}
+
+// refs returns the object numbers referenced by the value of key in dict,
+// which may be a single reference or an array of them
+func (doc *document) refs(dict []byte, key string) (list []int) {
+	for _, m := range reRef.FindAllSubmatch(value(dict, key), -1) {
+		n, _ := strconv.Atoi(string(m[1]))
+		list = append(list, n)
+	}
+	return
+}
+
+// resolveDict returns the dictionary associated with key in dict, following
+// an indirect reference if necessary
+func (doc *document) resolveDict(dict []byte, key string) []byte {
+	val := value(dict, key)
+	if m := reRef.FindSubmatch(val); m != nil && !bytes.HasPrefix(val, []byte("<<")) {
+		n, _ := strconv.Atoi(string(m[1]))
+		return doc.objs[n].dict
+	}
+	return val
+}
+
+// named returns the object number of the entry called name in the
+// subdictionary key of the resource dictionary res
+func (doc *document) named(res []byte, key, name string) (int, bool) {
+	for _, m := range reNameRef.FindAllSubmatch(doc.resolveDict(res, key), -1) {
+		if string(m[1]) == name {
+			n, _ := strconv.Atoi(string(m[2]))
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// fontType decodes the strings shown with one font
+type fontType struct {
+	cid   bool
+	codes map[int]string
+}
+
+// font returns the decoder for the font object n
+func (doc *document) font(n int) *fontType {
+	if ft, ok := doc.fonts[n]; ok {
+		return ft
+	}
+	dict := doc.objs[n].dict
+	ft := &fontType{codes: make(map[int]string)}
+	if bytes.Contains(dict, []byte("/Type0")) {
+		ft.cid = true
+		for _, m := range doc.refs(dict, "ToUnicode") {
+			parseCMap(doc.objs[m].stream, ft.codes)
+		}
+	} else {
+		var names [256]string
+		baseFont := ""
+		if m := reBaseFont.FindSubmatch(dict); m != nil {
+			baseFont = string(m[1])
+		}
+		switch baseFont {
+		case "Symbol":
+			builtinNames(&names, gofpdf.SymbolGlyphNames(), gofpdf.SymbolGlyph)
+		case "ZapfDingbats":
+			builtinNames(&names, gofpdf.ZapfGlyphNames(), gofpdf.ZapfGlyph)
+		}
+		enc := value(dict, "Encoding")
+		if m := reRef.FindSubmatch(enc); m != nil {
+			n, _ := strconv.Atoi(string(m[1]))
+			enc = doc.objs[n].dict
+		}
+		if baseFont != "Symbol" && baseFont != "ZapfDingbats" {
+			for code := 0; code < 256; code++ {
+				ft.codes[code] = string(winAnsiRunes[code])
+			}
+		}
+		applyDifferences(&names, value(enc, "Differences"))
+		for code, name := range names {
+			if name == "" {
+				continue
+			}
+			if r, ok := glyphRunes[name]; ok {
+				ft.codes[code] = string(r)
+			} else {
+				ft.codes[code] = string('�')
+			}
+		}
+	}
+	doc.fonts[n] = ft
+	return ft
+}
+
+// builtinNames assigns the glyph names of the built-in encoding of a symbolic
+// core font
+func builtinNames(names *[256]string, list []string, glyph func(string) string) {
+	for _, name := range list {
+		names[glyph(name)[0]] = name
+	}
+}
+
+// fillNames assigns glyph names to consecutive codes beginning at the code
+// given at the start of each group in list
+func fillNames(names *[256]string, list []string) {
+	code := 0
+	for _, item := range list {
+		if n, err := strconv.Atoi(item); err == nil {
+			code = n
+		} else if code < 256 {
+			names[code] = item
+			code++
+		}
+	}
+}
+
+// applyDifferences applies the contents of a /Differences array
+func applyDifferences(names *[256]string, diff []byte) {
+	var list []string
+	for _, field := range strings.Fields(strings.Trim(string(diff), "[]")) {
+		list = append(list, strings.TrimPrefix(field, "/"))
+	}
+	fillNames(names, list)
+}
+
+// parseCMap reads the bfchar and bfrange mappings of a ToUnicode CMap
+func parseCMap(cmap []byte, codes map[int]string) {
+	toks := tokenize(cmap)
+	for j := 0; j < len(toks); j++ {
+		switch toks[j].str {
+		case "beginbfchar":
+			for j++; j+1 < len(toks) && toks[j].str != "endbfchar"; j += 2 {
+				codes[hexInt(toks[j].data)] = utf16String(toks[j+1].data)
+			}
+		case "beginbfrange":
+			for j++; j+2 < len(toks) && toks[j].str != "endbfrange"; j += 3 {
+				lo, hi := hexInt(toks[j].data), hexInt(toks[j+1].data)
+				dst := toks[j+2]
+				if dst.kind == tokArray {
+					for k, item := range dst.items {
+						codes[lo+k] = utf16String(item.data)
+					}
+					continue
+				}
+				base := []rune(utf16String(dst.data))
+				if len(base) == 0 {
+					continue
+				}
+				prefix, last := string(base[:len(base)-1]), base[len(base)-1]
+				for code := lo; code <= hi && code-lo < 0x10000; code++ {
+					codes[code] = prefix + string(last+rune(code-lo))
+				}
+			}
+		}
+	}
+}
+
+func hexInt(b []byte) (n int) {
+	for _, c := range b {
+		n = n<<8 | int(c)
+	}
+	return
+}
+
+func utf16String(b []byte) string {
+	u := make([]uint16, len(b)/2)
+	for j := range u {
+		u[j] = uint16(b[2*j])<<8 | uint16(b[2*j+1])
+	}
+	return string(utf16.Decode(u))
+}
+
+// decode converts a string shown with ft to UTF-8
+func (ft *fontType) decode(b []byte) string {
+	var s strings.Builder
+	if ft.cid {
+		for j := 0; j+1 < len(b); j += 2 {
+			s.WriteString(ft.codes[int(b[j])<<8|int(b[j+1])])
+		}
+	} else {
+		for _, c := range b {
+			s.WriteString(ft.codes[int(c)])
+		}
+	}
+	return s.String()
+}
+
+// content interprets a content stream, appending the strings it shows to
+// lines. res is the resource dictionary in effect.
+func (doc *document) content(stream, res []byte, lines []string, depth int) ([]string, error) {
+	var (
+		ft    *fontType
+		stack []*fontType
+		args  []token
+	)
+	show := func(strs ...token) error {
+		if ft == nil {
+			return errors.New("text shown without a font")
+		}
+		var s strings.Builder
+		for _, tok := range strs {
+			if tok.kind == tokString {
+				s.WriteString(ft.decode(tok.data))
+			}
+		}
+		lines = append(lines, s.String())
+		return nil
+	}
+	var err error
+	for _, tok := range tokenize(stream) {
+		if tok.kind != tokOperator {
+			args = append(args, tok)
+			continue
+		}
+		switch tok.str {
+		case "q":
+			stack = append(stack, ft)
+		case "Q":
+			if len(stack) > 0 {
+				ft = stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+			}
+		case "Tf":
+			if len(args) >= 2 && args[len(args)-2].kind == tokName {
+				if n, ok := doc.named(res, "Font", args[len(args)-2].str); ok {
+					ft = doc.font(n)
+				}
+			}
+		case "Tj", "'", "\"":
+			if len(args) > 0 {
+				err = show(args[len(args)-1])
+			}
+		case "TJ":
+			if len(args) > 0 {
+				err = show(args[len(args)-1].items...)
+			}
+		case "Do":
+			if len(args) > 0 && args[len(args)-1].kind == tokName && depth < 8 {
+				if n, ok := doc.named(res, "XObject", args[len(args)-1].str); ok {
+					obj := doc.objs[n]
+					if bytes.Contains(obj.dict, []byte("/Form")) {
+						formRes := doc.resolveDict(obj.dict, "Resources")
+						if formRes == nil {
+							formRes = res
+						}
+						lines, err = doc.content(obj.stream, formRes, lines, depth+1)
+					}
+				}
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		args = args[:0]
+	}
+	return lines, nil
+}
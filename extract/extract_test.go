@@ -0,0 +1,269 @@
+package extract_test
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/headlands-org/gofpdf"
+	"github.com/headlands-org/gofpdf/extract"
+	"github.com/headlands-org/gofpdf/internal/example"
+)
+
+func output(t *testing.T, pdf *gofpdf.Fpdf) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatalf("unexpected error generating document: %s", err)
+	}
+	return buf.Bytes()
+}
+
+func pageText(t *testing.T, doc []byte, page int) string {
+	t.Helper()
+	txt, err := extract.ExtractPageText(doc, page)
+	if err != nil {
+		t.Fatalf("unexpected error extracting page %d: %s", page, err)
+	}
+	return txt
+}
+
+func TestExtractCoreFonts(t *testing.T) {
+	for _, compress := range []bool{true, false} {
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		pdf.SetCompression(compress)
+		tr := pdf.UnicodeTranslatorFromDescriptor("")
+		pdf.SetFooterFunc(func() {
+			pdf.SetY(-15)
+			pdf.SetFont("Times", "I", 8)
+			pdf.CellFormat(0, 10, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+		})
+		pdf.AliasNbPages("")
+		pdf.AddPage()
+		pdf.SetFont("Helvetica", "B", 16)
+		pdf.Cell(40, 10, tr("Grüße (aus) Köln \\ €5"))
+		pdf.AddPage()
+		pdf.SetFont("Courier", "", 12)
+		pdf.Cell(40, 10, "Second page")
+		doc := output(t, pdf)
+
+		count, err := extract.PageCount(doc)
+		if err != nil || count != 2 {
+			t.Fatalf("expected 2 pages, got %d (%v)", count, err)
+		}
+		txt := pageText(t, doc, 1)
+		if txt != "Grüße (aus) Köln \\ €5\nPage 1 of 2" {
+			t.Errorf("compress %v: unexpected text on page 1: %q", compress, txt)
+		}
+		txt = pageText(t, doc, 2)
+		if txt != "Second page\nPage 2 of 2" {
+			t.Errorf("compress %v: unexpected text on page 2: %q", compress, txt)
+		}
+		if _, err = extract.ExtractPageText(doc, 3); err == nil {
+			t.Errorf("expected error for page out of range")
+		}
+	}
+}
+
+func TestExtractEncodings(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica-1251", "", 12)
+	tr := pdf.UnicodeTranslatorFromDescriptor("cp1251")
+	pdf.Cell(0, 10, tr("Съешь же ещё этих"))
+	pdf.Ln(10)
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.SetFont("dejavu", "", 12)
+	pdf.MultiCell(0, 6, "Θέλει αρετή και τόλμη", "", "J", false)
+	pdf.SetFont("StandardSymbol", "", 12)
+	pdf.Cell(0, 10, gofpdf.SymbolGlyph("alpha")+gofpdf.SymbolGlyph("infinity"))
+	doc := output(t, pdf)
+	txt := pageText(t, doc, 1)
+	for _, str := range []string{"Съешь же ещё этих", "Θέλει αρετή και τόλμη", "α∞"} {
+		if !strings.Contains(txt, str) {
+			t.Errorf("expected %q in %q", str, txt)
+		}
+	}
+}
+
+func TestExtractASCIIStreams(t *testing.T) {
+	for _, encStr := range []string{gofpdf.ASCIIStreams85, gofpdf.ASCIIStreamsHex} {
+		for _, compress := range []bool{true, false} {
+			pdf := gofpdf.New("P", "mm", "A4", "")
+			pdf.SetASCIIStreams(encStr)
+			pdf.SetCompression(compress)
+			pdf.SetTitle("Übersicht", true)
+			pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+			pdf.AddPage()
+			pdf.SetFont("Helvetica", "", 12)
+			pdf.Cell(0, 10, "Core font")
+			pdf.Ln(10)
+			pdf.SetFont("dejavu", "", 12)
+			pdf.Bookmark("Größe", 0, -1)
+			pdf.Cell(0, 10, "Θέλει αρετή")
+			for j, str := range []string{"logo-rgb.png", "logo.jpg", "logo.gif"} {
+				pdf.Image(example.ImageFile(str), 10+float64(j)*40, 40, 30, 0, false, "", 0, "")
+			}
+			pdf.SetAttachments([]gofpdf.Attachment{{Content: []byte{0, 1, 2, 255}, Filename: "data.bin"}})
+			doc := output(t, pdf)
+			for j, c := range doc {
+				if (c < 0x20 || c > 0x7e) && c != '\n' && c != '\r' {
+					t.Fatalf("%s, compress %v: byte %#x at offset %d", encStr, compress, c, j)
+				}
+			}
+			if txt := pageText(t, doc, 1); txt != "Core font\nΘέλει αρετή" {
+				t.Errorf("%s, compress %v: unexpected text %q", encStr, compress, txt)
+			}
+		}
+	}
+}
+
+func TestExtractCompactOutput(t *testing.T) {
+	generate := func(compact, compress bool, encStr string) []byte {
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		pdf.SetCompactOutput(compact)
+		pdf.SetCompression(compress)
+		pdf.SetASCIIStreams(encStr)
+		pdf.SetFont("Helvetica", "", 12)
+		for j := 1; j <= 3; j++ {
+			pdf.AddPage()
+			pdf.Bookmark(fmt.Sprintf("Page %d", j), 0, -1)
+			pdf.Cell(0, 10, fmt.Sprintf("Page %d", j))
+		}
+		pdf.Image(example.ImageFile("logo.png"), 10, 30, 30, 0, false, "", 0, "")
+		pdf.SetAttachments([]gofpdf.Attachment{{Content: []byte("data"), Filename: "data.txt"}})
+		return output(t, pdf)
+	}
+	classic := generate(false, true, "")
+	for _, encStr := range []string{"", gofpdf.ASCIIStreams85} {
+		for _, compress := range []bool{true, false} {
+			doc := generate(true, compress, encStr)
+			if !bytes.HasPrefix(doc, []byte("%PDF-1.5")) {
+				t.Errorf("%q, compress %v: document begins with %q", encStr, compress, doc[:8])
+			}
+			if !bytes.Contains(doc, []byte("/Type /XRef")) || bytes.Contains(doc, []byte("\nxref\n")) {
+				t.Errorf("%q, compress %v: no cross-reference stream", encStr, compress)
+			}
+			if encStr == "" && compress && len(doc) >= len(classic) {
+				t.Errorf("compact document of %d bytes, expected less than %d", len(doc), len(classic))
+			}
+			if count, err := extract.PageCount(doc); err != nil || count != 3 {
+				t.Fatalf("%q, compress %v: expected 3 pages, got %d (%v)", encStr, compress, count, err)
+			}
+			if txt := pageText(t, doc, 2); txt != "Page 2" {
+				t.Errorf("%q, compress %v: unexpected text %q", encStr, compress, txt)
+			}
+			if list, err := extract.Attachments(doc); err != nil || len(list) != 1 || string(list[0].Content) != "data" {
+				t.Errorf("%q, compress %v: unexpected attachments %v (%v)", encStr, compress, list, err)
+			}
+		}
+	}
+}
+
+func TestExtractTemplate(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "", 12)
+	tpl := pdf.CreateTemplate(func(tpl *gofpdf.Tpl) {
+		tpl.SetFont("Helvetica", "", 12)
+		tpl.Text(10, 10, "From a template")
+	})
+	pdf.AddPage()
+	pdf.UseTemplate(tpl)
+	doc := output(t, pdf)
+	if txt := pageText(t, doc, 1); txt != "From a template" {
+		t.Errorf("unexpected text %q", txt)
+	}
+}
+
+func TestExtractPieceInfo(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.SetPieceInfo("com.example.catalog", []byte(`{"run": 42}`))
+	for _, id := range []string{"A-1", "B-2"} {
+		pdf.AddPage()
+		pdf.Cell(40, 10, "Record "+id)
+		pdf.SetPagePieceInfo("com.example.catalog", []byte(`{"id": "`+id+`", "note": "(a\\b)"}`))
+	}
+	doc := output(t, pdf)
+	data, err := extract.PieceInfo(doc, 0, "com.example.catalog")
+	if err != nil || string(data) != `{"run": 42}` {
+		t.Errorf("unexpected document data %q (%v)", data, err)
+	}
+	data, err = extract.PieceInfo(doc, 2, "com.example.catalog")
+	if err != nil || string(data) != `{"id": "B-2", "note": "(a\\b)"}` {
+		t.Errorf("unexpected page data %q (%v)", data, err)
+	}
+	if data, err = extract.PieceInfo(doc, 1, "com.example.other"); err != nil || data != nil {
+		t.Errorf("expected no data for another application, got %q (%v)", data, err)
+	}
+	pdf = gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetPagePieceInfo("com.example.catalog", []byte("{not json"))
+	if !pdf.Err() {
+		t.Errorf("expected error for invalid data")
+	}
+}
+
+func TestExtractAttachments(t *testing.T) {
+	modTime := time.Date(2024, 3, 15, 9, 30, 0, 0, time.Local)
+	for _, encStr := range []string{gofpdf.ASCIIStreamsNone, gofpdf.ASCIIStreamsHex} {
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		pdf.SetASCIIStreams(encStr)
+		pdf.SetAttachments([]gofpdf.Attachment{
+			{Content: []byte("a,b\n1,2\n"), Filename: "data.csv", Description: "Raw data"},
+			{Content: []byte("Grüße"), Filename: "grüße.txt", ModTime: modTime},
+		})
+		pdf.AddPage()
+		pdf.AddPage()
+		pdf.SetFont("Helvetica", "", 12)
+		pdf.Cell(40, 10, "Notes")
+		pdf.AddAttachmentAnnotation(&gofpdf.Attachment{Content: []byte("note"), Filename: "note.txt",
+			Description: "A (short) note"}, 20, 30, 40, 10)
+		doc := output(t, pdf)
+
+		list, err := extract.Attachments(doc)
+		if err != nil || len(list) != 2 {
+			t.Fatalf("%q: expected 2 attachments, got %d (%v)", encStr, len(list), err)
+		}
+		if a := list[0]; a.Filename != "data.csv" || a.Description != "Raw data" ||
+			string(a.Content) != "a,b\n1,2\n" || !a.ModTime.IsZero() {
+			t.Errorf("%q: unexpected first attachment %+v", encStr, a)
+		}
+		if a := list[1]; a.Filename != "grüße.txt" || string(a.Content) != "Grüße" || !a.ModTime.Equal(modTime) {
+			t.Errorf("%q: unexpected second attachment %+v", encStr, a)
+		}
+		if annots, err := extract.AttachmentAnnotations(doc, 1); err != nil || len(annots) != 0 {
+			t.Errorf("%q: expected no annotations on page 1, got %d (%v)", encStr, len(annots), err)
+		}
+		annots, err := extract.AttachmentAnnotations(doc, 2)
+		if err != nil || len(annots) != 1 {
+			t.Fatalf("%q: expected 1 annotation on page 2, got %d (%v)", encStr, len(annots), err)
+		}
+		an := annots[0]
+		if an.Filename != "note.txt" || an.Description != "A (short) note" || string(an.Content) != "note" {
+			t.Errorf("%q: unexpected annotation %+v", encStr, an.Attachment)
+		}
+		x, y := pdf.PointToUnitConvert(an.X), pdf.PointToUnitConvert(an.Y)
+		w, h := pdf.PointToUnitConvert(an.W), pdf.PointToUnitConvert(an.H)
+		if math.Abs(x-20) > 0.01 || math.Abs(y-30) > 0.01 || math.Abs(w-40) > 0.01 || math.Abs(h-10) > 0.01 {
+			t.Errorf("%q: unexpected annotation rectangle %.2f %.2f %.2f %.2f", encStr, x, y, w, h)
+		}
+	}
+}
+
+func TestExtractErrors(t *testing.T) {
+	if _, err := extract.ExtractPageText([]byte("hello"), 1); err == nil {
+		t.Errorf("expected error for non-PDF input")
+	}
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetProtection(gofpdf.CnProtectPrint, "", "secret")
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.AddPage()
+	pdf.Cell(40, 10, "Hidden")
+	if _, err := extract.ExtractPageText(output(t, pdf), 1); err == nil {
+		t.Errorf("expected error for encrypted document")
+	}
+}
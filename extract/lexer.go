@@ -0,0 +1,239 @@
+package extract
+
+import (
+	"strconv"
+)
+
+// Kinds of content stream tokens
+const (
+	tokNumber = iota
+	tokString
+	tokName
+	tokArray
+	tokDict
+	tokOperator
+)
+
+// token is a lexical element of a content stream or CMap. Strings, including
+// hexadecimal ones, are held unescaped in data; names (without the leading
+// slash) and operators are held in str.
+type token struct {
+	kind  int
+	str   string
+	data  []byte
+	items []token
+}
+
+func isSpace(c byte) bool {
+	switch c {
+	case ' ', '\t', '\r', '\n', '\f', 0:
+		return true
+	}
+	return false
+}
+
+func isDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+func isRegular(c byte) bool {
+	return !isSpace(c) && !isDelimiter(c)
+}
+
+func skipSpace(b []byte, pos int) int {
+	for pos < len(b) {
+		if isSpace(b[pos]) {
+			pos++
+		} else if b[pos] == '%' {
+			for pos < len(b) && b[pos] != '\n' && b[pos] != '\r' {
+				pos++
+			}
+		} else {
+			break
+		}
+	}
+	return pos
+}
+
+// skipValue returns the position just past the object that begins at pos.
+// For an indirect object body this stops at the keyword that follows the
+// value, typically "stream" or "endobj".
+func skipValue(b []byte, pos int) int {
+	tok, end := lex(b, skipSpace(b, pos))
+	if tok.kind == tokNumber {
+		// Accept "n g R" as an indirect reference
+		gen, end2 := lex(b, skipSpace(b, end))
+		if gen.kind == tokNumber {
+			if r, end3 := lex(b, skipSpace(b, end2)); r.kind == tokOperator && r.str == "R" {
+				return end3
+			}
+		}
+	}
+	return end
+}
+
+// tokenize splits b into tokens
+func tokenize(b []byte) (list []token) {
+	pos := skipSpace(b, 0)
+	for pos < len(b) {
+		tok, end := lex(b, pos)
+		if end <= pos {
+			end = pos + 1
+		} else {
+			list = append(list, tok)
+		}
+		pos = skipSpace(b, end)
+	}
+	return
+}
+
+// lex reads the token that begins at pos and returns it along with the
+// position just past it
+func lex(b []byte, pos int) (tok token, end int) {
+	if pos >= len(b) {
+		return token{}, pos
+	}
+	switch c := b[pos]; {
+	case c == '(':
+		return lexString(b, pos+1)
+	case c == '<' && pos+1 < len(b) && b[pos+1] == '<':
+		tok.kind = tokDict
+		pos += 2
+		for {
+			pos = skipSpace(b, pos)
+			if pos >= len(b) {
+				return tok, pos
+			}
+			if b[pos] == '>' && pos+1 < len(b) && b[pos+1] == '>' {
+				return tok, pos + 2
+			}
+			var item token
+			item, end = lex(b, pos)
+			if end <= pos {
+				end = pos + 1
+			}
+			tok.items = append(tok.items, item)
+			pos = end
+		}
+	case c == '<':
+		return lexHex(b, pos+1)
+	case c == '[':
+		tok.kind = tokArray
+		pos++
+		for {
+			pos = skipSpace(b, pos)
+			if pos >= len(b) {
+				return tok, pos
+			}
+			if b[pos] == ']' {
+				return tok, pos + 1
+			}
+			var item token
+			item, end = lex(b, pos)
+			if end <= pos {
+				end = pos + 1
+			}
+			tok.items = append(tok.items, item)
+			pos = end
+		}
+	case c == '/':
+		end = pos + 1
+		for end < len(b) && isRegular(b[end]) {
+			end++
+		}
+		return token{kind: tokName, str: string(b[pos+1 : end])}, end
+	case isDelimiter(c):
+		return token{kind: tokOperator, str: string(c)}, pos + 1
+	}
+	end = pos
+	for end < len(b) && isRegular(b[end]) {
+		end++
+	}
+	str := string(b[pos:end])
+	if _, err := strconv.ParseFloat(str, 64); err == nil {
+		return token{kind: tokNumber, str: str}, end
+	}
+	return token{kind: tokOperator, str: str}, end
+}
+
+// lexString reads a literal string whose opening parenthesis precedes pos
+func lexString(b []byte, pos int) (tok token, end int) {
+	tok.kind = tokString
+	depth := 1
+	for pos < len(b) {
+		c := b[pos]
+		pos++
+		switch c {
+		case '\\':
+			if pos >= len(b) {
+				break
+			}
+			c = b[pos]
+			pos++
+			switch c {
+			case 'n':
+				tok.data = append(tok.data, '\n')
+			case 'r':
+				tok.data = append(tok.data, '\r')
+			case 't':
+				tok.data = append(tok.data, '\t')
+			case 'b':
+				tok.data = append(tok.data, '\b')
+			case 'f':
+				tok.data = append(tok.data, '\f')
+			case '\r':
+				if pos < len(b) && b[pos] == '\n' {
+					pos++
+				}
+			case '\n':
+			default:
+				if c >= '0' && c <= '7' {
+					n := int(c - '0')
+					for k := 0; k < 2 && pos < len(b) && b[pos] >= '0' && b[pos] <= '7'; k++ {
+						n = n*8 + int(b[pos]-'0')
+						pos++
+					}
+					tok.data = append(tok.data, byte(n))
+				} else {
+					tok.data = append(tok.data, c)
+				}
+			}
+		case '(':
+			depth++
+			tok.data = append(tok.data, c)
+		case ')':
+			depth--
+			if depth == 0 {
+				return tok, pos
+			}
+			tok.data = append(tok.data, c)
+		default:
+			tok.data = append(tok.data, c)
+		}
+	}
+	return tok, pos
+}
+
+// lexHex reads a hexadecimal string whose opening bracket precedes pos
+func lexHex(b []byte, pos int) (tok token, end int) {
+	tok.kind = tokString
+	var digits []byte
+	for pos < len(b) && b[pos] != '>' {
+		if !isSpace(b[pos]) {
+			digits = append(digits, b[pos])
+		}
+		pos++
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	for j := 0; j < len(digits); j += 2 {
+		n, _ := strconv.ParseUint(string(digits[j:j+2]), 16, 8)
+		tok.data = append(tok.data, byte(n))
+	}
+	return tok, pos + 1
+}
@@ -0,0 +1,284 @@
+package gofpdf
+
+import (
+	"bytes"
+	"crypto/rc4"
+	"crypto/sha1"
+	"encoding/binary"
+	"fmt"
+	"image/jpeg"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// fileStreamChunkSize is the size of the chunks in which streamed files are
+// copied to the output
+const fileStreamChunkSize = 64 * 1024
+
+// fileSectionType identifies a range of bytes in a file
+type fileSectionType struct {
+	fileStr   string
+	pos, size int64
+}
+
+// fileStreamType records file content that is copied to the output, at
+// position pos of the document buffer, when the document is output
+type fileStreamType struct {
+	pos      int
+	n        int // Object number, used for encryption
+	sections []fileSectionType
+}
+
+// SetFileStreamThreshold specifies the size in bytes at and above which image
+// and font files are streamed. A streamed file is not read into memory when
+// it is registered; only the information needed to lay out the document is
+// read, and the content of the file is copied to the output in chunks when
+// the document is output. This bounds the memory used by documents with very
+// large images, such as posters. A threshold of zero, the default, disables
+// streaming.
+//
+// Streaming applies to JPEG images and to PNG images without an alpha
+// channel that are registered by file name with Image(), ImageOptions() or
+// RegisterImageOptions(), and to fonts added with AddFont() that are read from
+// the font directory. Images read from an io.Reader, PNG images with an alpha
+// channel and GIF images are always read into memory. A streamed file must
+// remain unchanged until the document has been output. Streamed images cannot
+// be serialized with GobEncode(), so neither can templates that use them.
+func (f *Fpdf) SetFileStreamThreshold(size int64) {
+	f.streamThreshold = size
+}
+
+// GetFileStreamThreshold returns the size in bytes at and above which image
+// and font files are streamed. See SetFileStreamThreshold().
+func (f *Fpdf) GetFileStreamThreshold() int64 {
+	return f.streamThreshold
+}
+
+// outputLen returns the length of the document output so far, including the
+// content of streamed files and the output already written by a streaming
+// document
+func (f *Fpdf) outputLen() int {
+	n := f.buffer.Len() + int(f.streamedLen)
+	if f.stream != nil {
+		n += f.stream.written
+	}
+	return n
+}
+
+// fileStreamed reports whether the file fileStr, the size of which is
+// returned, is to be streamed
+func (f *Fpdf) fileStreamed(fileStr string) (size int64, ok bool) {
+	if f.streamThreshold <= 0 {
+		return
+	}
+	fi, err := os.Stat(fileStr)
+	if err != nil || !fi.Mode().IsRegular() {
+		return
+	}
+	size = fi.Size()
+	ok = size >= f.streamThreshold
+	return
+}
+
+// registerStreamedImage registers the image in the open file fl for
+// streaming. ok is false if the image is not to be streamed, in which case
+// the file position is restored.
+func (f *Fpdf) registerStreamedImage(fileStr, tp string, readDpi bool, fl *os.File) (info *ImageInfoType, ok bool) {
+	size, ok := f.fileStreamed(fileStr)
+	if !ok {
+		return
+	}
+	switch strings.ToLower(tp) {
+	case "jpg", "jpeg":
+		info = f.parsejpgconfig(fl)
+		info.sections = []fileSectionType{{fileStr, 0, size}}
+	case "png":
+		var buf *bytes.Buffer
+		buf, info = f.scanpng(fileStr, fl)
+		if f.err == nil && buf == nil {
+			_, f.err = fl.Seek(0, io.SeekStart)
+			return nil, false
+		}
+		if f.err == nil {
+			sections := info.sections
+			info = f.parsepngstream(buf, readDpi)
+			info.sections = sections
+		}
+	default:
+		return nil, false
+	}
+	if f.err != nil {
+		return
+	}
+	info.i = fmt.Sprintf("%x", sha1.Sum([]byte(sprintf("%v %v %v %s %v %d %s %s %v %v",
+		info.sections, info.w, info.h, info.cs, info.pal, info.bpc, info.f, info.dp, info.trns, info.dpi))))
+	return
+}
+
+// parsejpgconfig extracts info from the header of the JPEG data read from r
+func (f *Fpdf) parsejpgconfig(r io.Reader) (info *ImageInfoType) {
+	info = f.newImageInfo()
+	config, err := jpeg.DecodeConfig(r)
+	if err != nil {
+		f.err = err
+		return
+	}
+	info.w = float64(config.Width)
+	info.h = float64(config.Height)
+	info.f = "DCTDecode"
+	info.bpc = 8
+	info.cs, f.err = jpegColorSpace(config.ColorModel)
+	return
+}
+
+// scanpng reads the chunks of the PNG file fl other than the image data into
+// buf and records the position of the image data in info.sections. buf is nil
+// if the image has an alpha channel, since its image data must then be
+// decoded.
+func (f *Fpdf) scanpng(fileStr string, fl *os.File) (buf *bytes.Buffer, info *ImageInfoType) {
+	info = f.newImageInfo()
+	var hdr [8]byte
+	if _, f.err = io.ReadFull(fl, hdr[:]); f.err != nil {
+		return
+	}
+	buf = new(bytes.Buffer)
+	buf.Write(hdr[:])
+	pos := int64(len(hdr))
+	for f.err == nil {
+		if _, f.err = io.ReadFull(fl, hdr[:]); f.err != nil {
+			break
+		}
+		n := int64(binary.BigEndian.Uint32(hdr[:4]))
+		typeStr := string(hdr[4:])
+		if typeStr == "IDAT" {
+			info.sections = append(info.sections, fileSectionType{fileStr, pos + 8, n})
+			_, f.err = fl.Seek(n+4, io.SeekCurrent)
+		} else {
+			buf.Write(hdr[:])
+			_, f.err = io.CopyN(buf, fl, n+4)
+			if typeStr == "IHDR" && n >= 10 && buf.Bytes()[25] >= 4 {
+				return nil, info
+			}
+			if typeStr == "IEND" {
+				break
+			}
+		}
+		pos += n + 12
+	}
+	if f.err != nil {
+		f.err = fmt.Errorf("error reading PNG file %s: %s", fileStr, f.err)
+	}
+	return
+}
+
+// fontFileSections returns the sections of the font file fileStr that make up
+// its embedded stream, or nil if the file is not to be streamed
+func (f *Fpdf) fontFileSections(fileStr string, info fontFileType) []fileSectionType {
+	if info.embedded || f.fontLoader != nil {
+		return nil
+	}
+	fileStr = path.Join(f.fontpath, fileStr)
+	size, ok := f.fileStreamed(fileStr)
+	if !ok {
+		return nil
+	}
+	if fileStr[len(fileStr)-2:] == ".z" || info.length2 == 0 {
+		return []fileSectionType{{fileStr, 0, size}}
+	}
+	// Type1 font: skip the segment headers
+	return []fileSectionType{
+		{fileStr, 6, info.length1 - 6},
+		{fileStr, info.length1 + 12, info.length2 - info.length1 - 12},
+	}
+}
+
+// putfilestream writes a stream, the content of which is copied from the
+// specified file sections when the document is output
+func (f *Fpdf) putfilestream(sections []fileSectionType) {
+	if f.asciiStreams != "" {
+		data, err := asciiReadSections(sections)
+		if err != nil {
+			f.err = err
+			return
+		}
+		f.putstream(data)
+		return
+	}
+	f.out("stream")
+	f.fileStreams = append(f.fileStreams, fileStreamType{f.buffer.Len(), f.n, sections})
+	f.streamedLen += sectionsLen(sections)
+	f.out("")
+	f.out("endstream")
+}
+
+// sectionsLen returns the combined size of sections
+func sectionsLen(sections []fileSectionType) (size int64) {
+	for _, sec := range sections {
+		size += sec.size
+	}
+	return
+}
+
+// writeOutput writes the document buffer to w, inserting the content of
+// streamed files
+func (f *Fpdf) writeOutput(w io.Writer) (err error) {
+	if len(f.fileStreams) == 0 {
+		_, err = f.buffer.WriteTo(w)
+		return
+	}
+	b := f.buffer.Bytes()
+	pos := 0
+	chunk := make([]byte, fileStreamChunkSize)
+	for _, fs := range f.fileStreams {
+		if _, err = w.Write(b[pos:fs.pos]); err != nil {
+			return
+		}
+		pos = fs.pos
+		dst := w
+		if f.protect.content() {
+			c, _ := rc4.NewCipher(f.protect.objectKey(uint32(fs.n)))
+			dst = &rc4Writer{w: w, c: c}
+		}
+		for _, sec := range fs.sections {
+			if err = copyFileSection(dst, sec, chunk); err != nil {
+				return
+			}
+		}
+	}
+	_, err = w.Write(b[pos:])
+	f.buffer.Reset()
+	f.fileStreams = nil
+	return
+}
+
+// copyFileSection copies the file section sec to w using buffer chunk
+func copyFileSection(w io.Writer, sec fileSectionType, chunk []byte) error {
+	fl, err := os.Open(sec.fileStr)
+	if err != nil {
+		return err
+	}
+	defer fl.Close()
+	n, err := io.CopyBuffer(w, io.NewSectionReader(fl, sec.pos, sec.size), chunk)
+	if err == nil && n != sec.size {
+		err = fmt.Errorf("streamed file %s has changed since it was registered", sec.fileStr)
+	}
+	return err
+}
+
+// rc4Writer encrypts the data written to w
+type rc4Writer struct {
+	w   io.Writer
+	c   *rc4.Cipher
+	buf []byte
+}
+
+func (rw *rc4Writer) Write(p []byte) (int, error) {
+	if cap(rw.buf) < len(p) {
+		rw.buf = make([]byte, len(p))
+	}
+	b := rw.buf[:len(p)]
+	rw.c.XORKeyStream(b, p)
+	return rw.w.Write(b)
+}
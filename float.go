@@ -0,0 +1,191 @@
+package gofpdf
+
+import (
+	"math"
+	"strings"
+)
+
+// floatType is the area occupied by a floated image, including its gap
+type floatType struct {
+	page           int
+	x0, y0, x1, y1 float64
+	left           bool
+}
+
+// ImageFloat prints an image against the left (sideStr "L") or right
+// (sideStr "R") margin, with its top at the current vertical position, and
+// lets subsequent text flow around it. Until ClearFloats() is called or the
+// page ends, lines printed by Write() and MultiCell() that would overlap the
+// image are shortened to leave gap units between the text and the image;
+// where floats leave too little room for text, the lines are moved below
+// them. If the image does not fit between the current position and the
+// automatic page break threshold, it is placed at the top of a new page.
+//
+// The image, w, h and options arguments have the same meaning as in
+// ImageOptions(). The current position is not changed, so text continues
+// beside the image.
+func (f *Fpdf) ImageFloat(imageNameStr, sideStr string, w, h, gap float64, options ImageOptions) {
+	if f.err != nil {
+		return
+	}
+	info := f.RegisterImageOptions(imageNameStr, options)
+	if f.err != nil {
+		return
+	}
+	w, h = f.imageSize(info, w, h)
+	if f.y+h > f.pageBreakTrigger && !f.inHeader && !f.inFooter && f.acceptPageBreakFor(PageBreakImage, f.y, h) {
+		x := f.x
+		f.AddPageFormat(f.curOrientation, f.curPageSize)
+		if f.err != nil {
+			return
+		}
+		f.x = x
+	}
+	fl := floatType{page: f.page, y0: f.y, y1: f.y + h + gap}
+	var x float64
+	if strings.ToUpper(sideStr) == "R" {
+		x = f.w - f.rMargin - w
+		fl.x0, fl.x1 = x-gap, f.w-f.rMargin
+	} else {
+		x = f.lMargin
+		fl.x0, fl.x1, fl.left = x, x+w+gap, true
+	}
+	f.imageOut(info, x, f.y, w, h, options, false, 0, "")
+	// Floats of earlier pages no longer apply
+	list := f.floats[:0]
+	for _, prev := range f.floats {
+		if prev.page == f.page {
+			list = append(list, prev)
+		}
+	}
+	f.floats = append(list, fl)
+}
+
+// ClearFloats moves the current position below all images placed with
+// ImageFloat() on the current page, if it is not already there, and ends the
+// flow of text around them.
+func (f *Fpdf) ClearFloats() {
+	for _, fl := range f.floats {
+		if fl.page == f.page && fl.y1 > f.y {
+			f.y = fl.y1
+		}
+	}
+	f.x = f.lMargin
+	f.floats = f.floats[:0]
+}
+
+// floatSpan returns the part of the horizontal interval x0 to x1 that is
+// not covered by floats in the band from the current vertical position to h
+// units below it. If the floats leave too little room, the current position
+// is moved down below the floats that are in the way. The interval is
+// returned unchanged when no floats apply, including when the line will be
+// moved to a new page by an automatic page break.
+func (f *Fpdf) floatSpan(x0, x1, h float64) (float64, float64) {
+	if len(f.floats) == 0 {
+		return x0, x1
+	}
+	minWd := 2*f.cMargin + f.fontSize
+	for {
+		if f.y+h > f.pageBreakTrigger {
+			return x0, x1
+		}
+		lx, rx := x0, x1
+		clearY := math.Inf(1)
+		for _, fl := range f.floats {
+			if fl.page != f.page || fl.y1 <= f.y || fl.y0 >= f.y+h || fl.x1 <= x0 || fl.x0 >= x1 {
+				continue
+			}
+			if fl.left {
+				lx = math.Max(lx, fl.x1)
+			} else {
+				rx = math.Min(rx, fl.x0)
+			}
+			clearY = math.Min(clearY, fl.y1)
+		}
+		if rx-lx >= minWd || math.IsInf(clearY, 1) {
+			return lx, rx
+		}
+		f.y = clearY
+	}
+}
+
+// floatsApply reports whether a float on the current page extends below
+// the current vertical position and overlaps the interval x0 to x1
+func (f *Fpdf) floatsApply(x0, x1 float64) bool {
+	for _, fl := range f.floats {
+		if fl.page == f.page && fl.y1 > f.y && fl.x1 > x0 && fl.x0 < x1 {
+			return true
+		}
+	}
+	return false
+}
+
+// putLine prints the line br of the text txtStr, as wrapped by
+// SplitTextBreaks(), in a cell at (x, current vertical position) that is w
+// wide and h high, with the border borderStr and the background painted if
+// fill is true. A line that ends within a hyphenated word is followed by a
+// hyphen. If alignStr is "J", the line is stretched to the width of the cell
+// unless it is the last line of its paragraph. The current position is moved
+// to the start of the next line.
+func (f *Fpdf) putLine(x, w, h float64, txtStr string, br LineBreakType, borderStr, alignStr string, fill bool) {
+	lineStr := txtStr[br.Start:br.End]
+	if br.Hyphen {
+		lineStr += "-"
+	}
+	last := !br.Hyphen && (br.End >= len(txtStr) || txtStr[br.End] == '\n')
+	if alignStr == "J" {
+		alignStr = "L"
+		if ns := strings.Count(lineStr, " "); ns > 0 && !last {
+			f.ws = (w - 2*f.cMargin - br.Width) / float64(ns)
+			f.outf("%.3f Tw", f.ws*f.k)
+		}
+	}
+	f.x = x
+	f.contMore = br.End < len(txtStr)
+	f.CellFormat(w, h, lineStr, borderStr, 2, alignStr, fill, 0, "")
+	if f.ws > 0 {
+		f.ws = 0
+		f.out("0 Tw")
+	}
+}
+
+// multiCellFloats prints the lines of a MultiCell() paragraph that lie
+// beside floats and returns the text that remains to be printed at full
+// width. x and w describe the horizontal extent of the paragraph. The first
+// line is printed with the border b and the others with b2, with a bottom
+// border added to the last line of the paragraph if bottom is true; printed
+// reports whether any line was printed.
+func (f *Fpdf) multiCellFloats(x, w, h float64, txtStr, b, b2 string, bottom bool, alignStr string, fill bool) (rest string, printed bool) {
+	for txtStr != "" && f.err == nil && f.floatsApply(x, x+w) {
+		if f.y+h > f.pageBreakTrigger {
+			break
+		}
+		lx, rx := f.floatSpan(x, x+w, h)
+		var br LineBreakType
+		if !strings.HasPrefix(txtStr, "\n") {
+			breaks := f.SplitTextBreaks(txtStr, rx-lx)
+			if len(breaks) == 0 {
+				return "", printed
+			}
+			br = breaks[0]
+		}
+		lineStr := txtStr
+		txtStr = txtStr[br.End:]
+		if strings.HasPrefix(txtStr, "\n") {
+			txtStr = txtStr[1:]
+		} else {
+			txtStr = strings.TrimLeft(txtStr, " ")
+		}
+		borderStr := b
+		if printed {
+			borderStr = b2
+		}
+		if txtStr == "" && bottom {
+			borderStr += "B"
+		}
+		f.putLine(lx, rx-lx, h, lineStr, br, borderStr, alignStr, fill)
+		printed = true
+	}
+	f.x = x
+	return txtStr, printed
+}
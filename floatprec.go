@@ -0,0 +1,117 @@
+package gofpdf
+
+import (
+	"strconv"
+	"strings"
+)
+
+// SetFloatPrecision sets the maximum number of decimal places of the
+// coordinates, lengths and other real numbers that are written to page
+// content, and drops trailing zeros from them. By default, gofpdf writes
+// these numbers with a fixed two, three or five decimal places; since one
+// point is 1/72 inch, two decimal places are more than enough for most
+// documents, and fewer digits noticeably shrink the content of dense vector
+// drawings such as plots with thousands of points. The precision applies to
+// page content written from then on by the drawing, text and image methods;
+// it never increases the number of decimal places of a value. The
+// coefficients of transformation matrices and scale factors keep their
+// default precision, since rounding them would skew or distort what they
+// transform. Pass a negative value to restore the default formatting.
+func (f *Fpdf) SetFloatPrecision(decimals int) {
+	if decimals < 0 {
+		decimals = -1
+	}
+	f.floatPrecision = decimals
+}
+
+// GetFloatPrecision returns the maximum number of decimal places of real
+// numbers written to page content, or -1 if the default formatting is in
+// effect. See SetFloatPrecision().
+func (f *Fpdf) GetFloatPrecision() int {
+	return f.floatPrecision
+}
+
+// contentf formats page content like sprintf, applying the precision set with
+// SetFloatPrecision() to float64 arguments that are formatted with a verb of
+// the form %.Nf
+func (f *Fpdf) contentf(fmtStr string, args ...interface{}) string {
+	if f.floatPrecision >= 0 && f.state == 2 {
+		fmtStr, args = floatPrecisionArgs(fmtStr, args, f.floatPrecision)
+	}
+	return sprintf(fmtStr, args...)
+}
+
+// floatPrecisionArgs returns fmtStr and args with each float64 argument
+// formatted with %.Nf replaced by a string argument formatted with %s that
+// holds the value with at most decimals decimal places and without trailing
+// zeros. Other verbs are left unchanged.
+func floatPrecisionArgs(fmtStr string, args []interface{}, decimals int) (string, []interface{}) {
+	var b strings.Builder
+	newArgs := make([]interface{}, len(args))
+	copy(newArgs, args)
+	argPos := 0
+	for j := 0; j < len(fmtStr); j++ {
+		c := fmtStr[j]
+		b.WriteByte(c)
+		if c != '%' || j+1 >= len(fmtStr) {
+			continue
+		}
+		if fmtStr[j+1] == '%' {
+			b.WriteByte('%')
+			j++
+			continue
+		}
+		// Find the end of the verb
+		end := j + 1
+		for end < len(fmtStr) && strings.IndexByte("+-# 0123456789.", fmtStr[end]) >= 0 {
+			end++
+		}
+		if end >= len(fmtStr) {
+			b.WriteString(fmtStr[j+1:])
+			break
+		}
+		spec := fmtStr[j+1 : end]
+		if fmtStr[end] == 'f' && len(spec) > 1 && spec[0] == '.' && argPos < len(args) {
+			if n, err := strconv.Atoi(spec[1:]); err == nil {
+				if v, ok := args[argPos].(float64); ok {
+					if n > decimals {
+						n = decimals
+					}
+					newArgs[argPos] = trimFloat(v, n)
+					b.WriteByte('s')
+					argPos++
+					j = end
+					continue
+				}
+			}
+		}
+		b.WriteString(fmtStr[j+1 : end+1])
+		argPos++
+		j = end
+	}
+	return b.String(), newArgs
+}
+
+// trimFloat formats v with the specified number of decimal places and removes
+// trailing zeros and, if nothing follows it, the decimal point
+func trimFloat(v float64, decimals int) string {
+	s := strconv.FormatFloat(v, 'f', decimals, 64)
+	if strings.IndexByte(s, '.') >= 0 {
+		s = strings.TrimRight(s, "0")
+		s = strings.TrimSuffix(s, ".")
+	}
+	if s == "-0" {
+		s = "0"
+	}
+	return s
+}
+
+// fixedf formats operands of page content like sprintf for an argument of
+// outf() or contentf() formatted with %s, so that the precision set with
+// SetFloatPrecision() does not reduce their decimal places. It is used for
+// the coefficients of transformation matrices and for scale factors, which
+// rounding would skew or distort, while the coordinates that accompany them
+// are rounded.
+func fixedf(fmtStr string, args ...interface{}) string {
+	return sprintf(fmtStr, args...)
+}
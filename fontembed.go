@@ -0,0 +1,115 @@
+package gofpdf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Embedding modes of FontEmbeddingType
+const (
+	// FontEmbedSubset embeds only the glyphs used in the document. This is
+	// the default.
+	FontEmbedSubset = iota
+	// FontEmbedFull embeds the complete font file, so that the document can
+	// be edited with the font
+	FontEmbedFull
+	// FontEmbedNone embeds only the font's metrics. The document displays as
+	// intended only where the font is installed; elsewhere the viewer
+	// substitutes a font of its choice.
+	FontEmbedNone
+)
+
+// FontEmbeddingType specifies how a font added with AddUTF8Font() or a
+// related method is embedded in the document. See SetFontEmbedding().
+type FontEmbeddingType struct {
+	// FontEmbedSubset, FontEmbedFull or FontEmbedNone
+	Mode int
+	// With FontEmbedSubset, the complete font is embedded instead if more
+	// than this fraction (0 - 1) of its glyphs is used, since a subset then
+	// saves little space. Zero always embeds a subset.
+	SubsetThreshold float64
+	// Must be true with FontEmbedNone, acknowledging that the document will
+	// not display correctly where the font is not installed
+	AcknowledgeNoEmbed bool
+	// Embed the font even though the embedding permissions (fsType) recorded
+	// in the font prohibit it. This should be set only if the font's license
+	// has been checked and permits embedding.
+	IgnoreRestrictions bool
+}
+
+// SetFontEmbedding specifies how the UTF-8 font identified by familyStr and
+// styleStr is embedded in the document. By default, a subset containing the
+// glyphs that are used is embedded. The font may be added before or after
+// this method is called. Fonts added with AddFont() and related methods are
+// always embedded completely, and the core fonts are never embedded.
+//
+// The embedding permissions in the OS/2 table of the font are honored when
+// the document is output. A font with the restricted license or bitmap
+// embedding only permission causes an error unless embed.Mode is
+// FontEmbedNone or embed.IgnoreRestrictions is true. A font that may not be
+// subset is embedded completely.
+func (f *Fpdf) SetFontEmbedding(familyStr, styleStr string, embed FontEmbeddingType) {
+	if f.err != nil {
+		return
+	}
+	familyStr = fontFamilyEscape(familyStr)
+	nameStr := strings.TrimSpace(familyStr + " " + styleStr)
+	switch embed.Mode {
+	case FontEmbedSubset, FontEmbedFull:
+	case FontEmbedNone:
+		if !embed.AcknowledgeNoEmbed {
+			f.err = fmt.Errorf("font %s: omitting the font file requires AcknowledgeNoEmbed", nameStr)
+			return
+		}
+	default:
+		f.err = fmt.Errorf("font %s: unknown embedding mode %d", nameStr, embed.Mode)
+		return
+	}
+	if embed.SubsetThreshold < 0 || embed.SubsetThreshold > 1 {
+		f.err = fmt.Errorf("font %s: subset threshold %.2f is not between 0 and 1",
+			nameStr, embed.SubsetThreshold)
+		return
+	}
+	if f.fontEmbedding == nil {
+		f.fontEmbedding = make(map[string]FontEmbeddingType)
+	}
+	f.fontEmbedding[getFontKey(familyStr, styleStr)] = embed
+}
+
+// fontEmbedMode returns the embedding mode of the UTF-8 font registered
+// under key, applying the subset threshold and the font's embedding
+// permissions. An error is set if the permissions are violated.
+func (f *Fpdf) fontEmbedMode(key string, font fontDefType) int {
+	embed := f.fontEmbedding[key]
+	if embed.Mode == FontEmbedNone || len(font.usedRunes)+len(font.cidGlyphs) == 0 {
+		// No glyphs are needed if no text has been shown in the font, for
+		// example because it has been drawn with SetTextAsPaths()
+		return FontEmbedNone
+	}
+	utf := font.utf8File
+	if !embed.IgnoreRestrictions {
+		reasonStr := ""
+		switch {
+		case utf.fsType&0x000f == 0x0002:
+			reasonStr = "its license is restricted"
+		case utf.fsType&0x0200 != 0:
+			reasonStr = "only bitmaps may be embedded"
+		}
+		if reasonStr != "" {
+			familyStr, styleStr := splitFontKey(key)
+			f.err = fmt.Errorf("font %s (%s) may not be embedded: %s; "+
+				"use FontEmbedNone, or IgnoreRestrictions if the license permits embedding",
+				strings.TrimSpace(familyStr+" "+styleStr), utf.postScriptName, reasonStr)
+			return FontEmbedNone
+		}
+		if utf.fsType&0x0100 != 0 {
+			// Subsetting is not permitted
+			return FontEmbedFull
+		}
+	}
+	if embed.Mode == FontEmbedSubset && embed.SubsetThreshold > 0 && utf.numSymbols > 0 &&
+		float64(len(font.usedRunes)+len(font.cidGlyphs)) > embed.SubsetThreshold*float64(utf.numSymbols) {
+		return FontEmbedFull
+	}
+	return embed.Mode
+}
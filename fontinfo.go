@@ -0,0 +1,222 @@
+package gofpdf
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FontError is the error set by SetFont() when the requested font cannot be
+// selected. It can be retrieved from Error() with a type assertion.
+type FontError struct {
+	// Family and style as requested, with the family in lower case and the
+	// style in upper case, and without underline and strike-out flags
+	Family, Style string
+	// Explanation of the problem
+	Reason string
+	// Family and style combinations, in the form accepted by SetFont() and
+	// separated by a space, that are available and might have been intended
+	Suggestions []string
+}
+
+// Error implements the error interface.
+func (e *FontError) Error() string {
+	str := "undefined font: " + e.Family
+	if e.Style != "" {
+		str += " " + e.Style
+	}
+	str += fmt.Sprintf(" (%s)", e.Reason)
+	if len(e.Suggestions) > 0 {
+		str += "; did you mean " + strings.Join(e.Suggestions, ", ") + "?"
+	}
+	return str
+}
+
+// RegisteredFontType describes a font that can be selected with SetFont().
+type RegisteredFontType struct {
+	// Family in lower case and style ("", "B", "I" or "BI")
+	Family, Style string
+	// "Core" for the standard fonts, or the type of an added font, such as
+	// "TrueType", "Type1" or "UTF8"
+	Type string
+	// PostScript name of the font, or for UTF-8 fonts the key under which it
+	// is registered; empty for core fonts that have not been used yet
+	Name string
+	// Size in bytes of the font file that is embedded, or of the complete
+	// font file from which a subset is embedded; zero for core fonts
+	FileSize int
+	// Number of distinct characters used so far with a UTF-8 font, and thus
+	// included in its embedded subset; zero for other fonts, which are not
+	// subset
+	SubsetGlyphs int
+}
+
+// coreFontStyles are the styles in which a font family can be available.
+// Symbol, ZapfDingbats and the Cyrillic and Greek metric packs of Helvetica
+// are available only in the regular style.
+var coreFontStyles = []string{"", "B", "I", "BI"}
+
+// splitFontKey returns the family and style of the font key built by
+// getFontKey(). The family is in lower case and the style in upper case.
+func splitFontKey(key string) (familyStr, styleStr string) {
+	n := len(key)
+	for n > 0 && (key[n-1] == 'B' || key[n-1] == 'I') {
+		n--
+	}
+	return key[:n], key[n:]
+}
+
+// GetRegisteredFonts returns the fonts that can be selected with SetFont():
+// the fonts added with AddFont(), AddUTF8Font() and related methods, and the
+// core fonts. The list is sorted by family and style.
+func (f *Fpdf) GetRegisteredFonts() (list []RegisteredFontType) {
+	seen := make(map[string]bool)
+	for key, def := range f.fonts {
+		fnt := RegisteredFontType{Type: def.Tp, Name: def.Name, FileSize: def.OriginalSize}
+		fnt.Family, fnt.Style = splitFontKey(key)
+		if def.utf8File != nil {
+			if def.utf8File.fileReader != nil {
+				fnt.FileSize = len(def.utf8File.fileReader.array)
+			}
+			fnt.SubsetGlyphs = len(def.usedRunes) + len(def.cidGlyphs)
+		}
+		list = append(list, fnt)
+		seen[key] = true
+	}
+	for familyStr := range f.coreFonts {
+		for _, styleStr := range coreFontStyles {
+			// Styles without metrics of their own cannot be selected
+			if _, ok := embeddedFontList[familyStr+styleStr]; ok && !seen[familyStr+styleStr] {
+				list = append(list, RegisteredFontType{Family: familyStr, Style: styleStr, Type: "Core"})
+			}
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Family != list[j].Family {
+			return list[i].Family < list[j].Family
+		}
+		return list[i].Style < list[j].Style
+	})
+	return
+}
+
+// GetFontCIDMap returns the character identifiers (CIDs) with which the
+// runes printed so far in the UTF-8 font identified by familyStr and styleStr
+// are encoded in the document. A rune of the Basic Multilingual Plane (up to
+// U+FFFF) is always encoded as its own code point, so the CIDs do not change
+// when unrelated text is added or removed. Runes beyond U+FFFF are assigned
+// CIDs from the range U+D800 - U+DFFF in order of first use. nil is returned
+// for fonts that are not UTF-8 fonts. The returned map is a copy.
+func (f *Fpdf) GetFontCIDMap(familyStr, styleStr string) map[rune]int {
+	def, ok := f.fonts[getFontKey(fontFamilyEscape(familyStr), styleStr)]
+	if !ok || def.utf8File == nil {
+		return nil
+	}
+	cidMap := make(map[rune]int, len(def.runeToCID))
+	for r, cid := range def.runeToCID {
+		cidMap[rune(r)] = cid
+	}
+	return cidMap
+}
+
+// fontStyleKey returns the upper case style flags styleStr, from which the
+// underline and strike-out flags have been removed, in the form used in font
+// keys. An error is set if other flags are present.
+func (f *Fpdf) fontStyleKey(familyStr, styleStr string) string {
+	key, ok := styleKey(styleStr)
+	if !ok {
+		f.fontError(familyStr, styleStr, "style flags other than B, I, U and S are not recognized")
+	}
+	return key
+}
+
+// styleKey returns the upper case style flags styleStr, without underline
+// and strike-out flags, in the form used in font keys, and false if other
+// flags are present
+func styleKey(styleStr string) (string, bool) {
+	if strings.Trim(styleStr, "BI") != "" {
+		return "", false
+	}
+	bold, italic := strings.Contains(styleStr, "B"), strings.Contains(styleStr, "I")
+	switch {
+	case bold && italic:
+		return "BI", true
+	case bold:
+		return "B", true
+	case italic:
+		return "I", true
+	}
+	return "", true
+}
+
+// fontError sets a FontError explaining why the lower case family and upper
+// case style cannot be selected
+func (f *Fpdf) fontError(familyStr, styleStr, reasonStr string) {
+	e := &FontError{Family: familyStr, Style: styleStr, Reason: reasonStr}
+	fonts := f.GetRegisteredFonts()
+	name := func(fnt RegisteredFontType) string {
+		return strings.TrimSpace(fnt.Family + " " + fnt.Style)
+	}
+	// Other styles of the same family
+	for _, fnt := range fonts {
+		if fnt.Family == familyStr && fnt.Style != styleStr {
+			e.Suggestions = append(e.Suggestions, name(fnt))
+		}
+	}
+	if len(e.Suggestions) == 0 {
+		// Families with similar names in the requested style, or else in any
+		// style
+		for _, pass := range []bool{true, false} {
+			for _, fnt := range fonts {
+				if (!pass || fnt.Style == styleStr) && fnt.Family != familyStr &&
+					similarNames(fnt.Family, familyStr) {
+					e.Suggestions = append(e.Suggestions, name(fnt))
+				}
+			}
+			if len(e.Suggestions) > 0 {
+				break
+			}
+		}
+	}
+	f.err = e
+}
+
+// similarNames reports whether a and b differ by at most two single
+// character edits, or one is a prefix of the other
+func similarNames(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	if strings.HasPrefix(a, b) || strings.HasPrefix(b, a) {
+		return true
+	}
+	// Levenshtein distance
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)] <= 2
+}
+
+// min3 returns the smallest of its arguments
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,413 @@
+package gofpdf
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// SetFormValues sets the values of the interactive form fields named by the
+// keys of values, such as the values read from a data file with ParseFDF()
+// or ParseXFDF(). The values apply to the fields that have been added and
+// to fields added later with the same names, replacing the initial values
+// passed to AddTextField() and the like; names of fields that the document
+// does not have are ignored. The value of a check box is "Yes" if it is
+// checked and "Off" otherwise, the value of a radio group is the Value of
+// the selected button or "Off" if none is selected, and the value of a combo
+// box must be one of its options unless it is editable. An empty value
+// clears a field. Signature fields take no values; they are filled in by
+// SignPDF().
+func (f *Fpdf) SetFormValues(values map[string]string) {
+	if f.err != nil {
+		return
+	}
+	if f.formValues == nil {
+		f.formValues = make(map[string]string)
+	}
+	names := make([]string, 0, len(values))
+	for nameStr := range values {
+		names = append(names, nameStr)
+	}
+	sort.Strings(names)
+	for _, nameStr := range names {
+		f.formValues[nameStr] = values[nameStr]
+		if j := f.formFieldIndex(nameStr); j >= 0 {
+			f.setFormValue(&f.formFields[j], values[nameStr])
+		}
+	}
+}
+
+// FormValues returns the current values of the interactive form fields of
+// the document by name, in the form accepted by SetFormValues().
+func (f *Fpdf) FormValues() map[string]string {
+	values := make(map[string]string, len(f.formFields))
+	for _, fld := range f.formFields {
+		if fld.kindStr != "Sig" {
+			values[fld.nameStr] = fld.valueStr
+		}
+	}
+	return values
+}
+
+// setFormValue sets the value of fld to valueStr if the field can take it
+func (f *Fpdf) setFormValue(fld *formFieldType, valueStr string) {
+	if fld.kindStr == "Btn" && valueStr == "" {
+		valueStr = "Off"
+	}
+	ok := true
+	switch {
+	case fld.radio:
+		ok = valueStr == "Off"
+		for _, wdg := range fld.widgets {
+			ok = ok || wdg.onStr == valueStr
+		}
+	case fld.kindStr == "Btn":
+		ok = valueStr == "Off" || valueStr == formCheckOnStr
+	case fld.kindStr == "Sig":
+		ok = valueStr == ""
+	case fld.kindStr == "Ch" && fld.flags&formFlagEdit == 0:
+		ok = valueStr == ""
+		for _, str := range fld.options {
+			ok = ok || str == valueStr
+		}
+	}
+	if !ok {
+		f.SetErrorf("form field %s cannot take the value %s", fld.nameStr, valueStr)
+		return
+	}
+	fld.valueStr = valueStr
+}
+
+// OutputFDF writes the values of the interactive form fields of the
+// document to w in Forms Data Format (FDF), which PDF readers and form
+// tools import to fill in the form. fileStr, if not empty, is the name of
+// the PDF file that the data belongs to.
+func (f *Fpdf) OutputFDF(w io.Writer, fileStr string) error {
+	if f.err != nil {
+		return f.err
+	}
+	var s fmtBuffer
+	s.printf("%%FDF-1.2\n%%\xe2\xe3\xcf\xd3\n1 0 obj\n<</FDF <<")
+	if fileStr != "" {
+		s.printf("/F %s ", fdfString(fileStr))
+	}
+	s.printf("/Fields [")
+	for _, fld := range f.formFields {
+		if fld.kindStr == "Sig" {
+			continue
+		}
+		s.printf("\n<</T %s /V ", fdfString(fld.nameStr))
+		if fld.kindStr == "Btn" {
+			s.printf("/%s>>", formName(fld.valueStr))
+		} else {
+			s.printf("%s>>", fdfString(fld.valueStr))
+		}
+	}
+	s.printf("]>>>>\nendobj\ntrailer\n<</Root 1 0 R>>\n%%%%EOF\n")
+	_, err := io.WriteString(w, s.String())
+	return err
+}
+
+// xfdfType is the root element of an XFDF file
+type xfdfType struct {
+	XMLName xml.Name        `xml:"xfdf"`
+	Xmlns   string          `xml:"xmlns,attr,omitempty"`
+	F       *xfdfFileType   `xml:"f"`
+	Fields  []xfdfFieldType `xml:"fields>field"`
+}
+
+// xfdfFileType names the PDF file of an XFDF file
+type xfdfFileType struct {
+	Href string `xml:"href,attr"`
+}
+
+// xfdfFieldType is a field of an XFDF file, which may contain the fields
+// whose names continue its name
+type xfdfFieldType struct {
+	Name   string          `xml:"name,attr"`
+	Values []string        `xml:"value"`
+	Fields []xfdfFieldType `xml:"field"`
+}
+
+// OutputXFDF writes the values of the interactive form fields of the
+// document to w in XML Forms Data Format (XFDF), the XML counterpart of FDF.
+// fileStr, if not empty, is the name of the PDF file that the data belongs
+// to.
+func (f *Fpdf) OutputXFDF(w io.Writer, fileStr string) error {
+	if f.err != nil {
+		return f.err
+	}
+	doc := xfdfType{Xmlns: "http://ns.adobe.com/xfdf/"}
+	if fileStr != "" {
+		doc.F = &xfdfFileType{Href: fileStr}
+	}
+	for _, fld := range f.formFields {
+		if fld.kindStr == "Sig" {
+			continue
+		}
+		doc.Fields = append(doc.Fields, xfdfFieldType{Name: fld.nameStr, Values: []string{fld.valueStr}})
+	}
+	buf, err := xml.MarshalIndent(doc, "", "  ")
+	if err == nil {
+		_, err = io.WriteString(w, xml.Header+string(buf)+"\n")
+	}
+	return err
+}
+
+// ParseXFDF reads the field values of an XFDF file from r, returning them
+// by field name in the form accepted by SetFormValues(). The names of nested
+// fields are joined with periods. Of a field with several values, such as a
+// list box with multiple selections, the first value is returned.
+func ParseXFDF(r io.Reader) (values map[string]string, err error) {
+	var doc xfdfType
+	if err = xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("unable to parse XFDF data: %s", err)
+	}
+	values = make(map[string]string)
+	var walk func(list []xfdfFieldType, prefixStr string)
+	walk = func(list []xfdfFieldType, prefixStr string) {
+		for _, fld := range list {
+			nameStr := prefixStr + fld.Name
+			if len(fld.Values) > 0 {
+				values[nameStr] = fld.Values[0]
+			}
+			walk(fld.Fields, nameStr+".")
+		}
+	}
+	walk(doc.Fields, "")
+	return
+}
+
+// ParseFDF reads the field values of an FDF file from r, returning them by
+// field name in the form accepted by SetFormValues(). The names of nested
+// fields are joined with periods. Of a field with several values, the first
+// value is returned. Only fields whose dictionaries are given directly in
+// the /Fields array of the file, as form tools write them, are read.
+func ParseFDF(r io.Reader) (values map[string]string, err error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return
+	}
+	pos := bytes.Index(data, []byte("/Fields"))
+	if !bytes.HasPrefix(data, []byte("%FDF-")) || pos < 0 {
+		return nil, fmt.Errorf("unable to parse FDF data: no fields found")
+	}
+	p := fdfParserType{data: data, pos: pos + len("/Fields")}
+	fields, ok := p.value().([]interface{})
+	if !ok || p.err != nil {
+		return nil, fmt.Errorf("unable to parse FDF data: invalid field array")
+	}
+	values = make(map[string]string)
+	var walk func(list []interface{}, prefixStr string)
+	walk = func(list []interface{}, prefixStr string) {
+		for _, item := range list {
+			dict, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			nameStr, _ := dict["T"].(string)
+			nameStr = prefixStr + nameStr
+			v := dict["V"]
+			if arr, ok := v.([]interface{}); ok && len(arr) > 0 {
+				v = arr[0]
+			}
+			switch v := v.(type) {
+			case string:
+				values[nameStr] = v
+			case fdfNameType:
+				values[nameStr] = string(v)
+			}
+			if kids, ok := dict["Kids"].([]interface{}); ok {
+				walk(kids, nameStr+".")
+			}
+		}
+	}
+	walk(fields, "")
+	return
+}
+
+// fdfString returns str as a PDF text string, in UTF-16 if it is not ASCII
+func fdfString(str string) string {
+	if asciiString(str) {
+		return "(" + strings.NewReplacer("\\", "\\\\", "(", "\\(", ")", "\\)", "\r", "\\r").Replace(str) + ")"
+	}
+	return "<" + strings.ToUpper(hex.EncodeToString([]byte(utf8toutf16(str)))) + ">"
+}
+
+// fdfNameType is a name object read from an FDF file
+type fdfNameType string
+
+// fdfParserType reads the objects of an FDF file. Strings are returned as
+// UTF-8 strings, names as fdfNameType, arrays as []interface{} and
+// dictionaries as map[string]interface{}; numbers, keywords and object
+// references are returned as nil.
+type fdfParserType struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+// value returns the object at the current position and moves past it
+func (p *fdfParserType) value() interface{} {
+	start, end := contentToken(p.data, p.pos)
+	if start == end {
+		p.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	tok := p.data[start:end]
+	switch {
+	case tok[0] == '(':
+		p.pos = end
+		if len(tok) < 2 || tok[len(tok)-1] != ')' {
+			p.err = fmt.Errorf("unterminated string")
+			return nil
+		}
+		return fdfText(fdfLiteral(tok[1 : len(tok)-1]))
+	case bytes.HasPrefix(tok, []byte("<<")):
+		p.pos = start + 2
+		dict := make(map[string]interface{})
+		for p.err == nil {
+			start, end = contentToken(p.data, p.pos)
+			if start == end || p.data[start] != '/' {
+				break
+			}
+			p.pos = end
+			dict[string(p.data[start+1:end])] = p.value()
+		}
+		if start < end && bytes.HasPrefix(p.data[start:], []byte(">>")) {
+			p.pos = start + 2
+		} else if p.err == nil {
+			p.err = fmt.Errorf("unterminated dictionary")
+		}
+		return dict
+	case tok[0] == '<':
+		p.pos = end
+		if len(tok) < 2 || tok[len(tok)-1] != '>' {
+			p.err = fmt.Errorf("unterminated hexadecimal string")
+			return nil
+		}
+		b, _ := hex.DecodeString(string(bytes.Map(func(r rune) rune {
+			if strings.ContainsRune(" \t\r\n\f", r) {
+				return -1
+			}
+			return r
+		}, tok[1:len(tok)-1])))
+		return fdfText(b)
+	case tok[0] == '[':
+		p.pos = start + 1
+		var list []interface{}
+		for p.err == nil {
+			start, end = contentToken(p.data, p.pos)
+			if start == end || p.data[start] == ']' {
+				break
+			}
+			list = append(list, p.value())
+		}
+		if start < end {
+			p.pos = start + 1
+		} else if p.err == nil {
+			p.err = fmt.Errorf("unterminated array")
+		}
+		return list
+	case tok[0] == '/':
+		p.pos = end
+		return fdfNameType(fdfNameDecode(tok[1:]))
+	}
+	p.pos = end
+	if _, err := strconv.Atoi(string(tok)); err == nil {
+		// Skip the rest of an object reference
+		_, genEnd := contentToken(p.data, p.pos)
+		rStart, rEnd := contentToken(p.data, genEnd)
+		if string(p.data[rStart:rEnd]) == "R" {
+			p.pos = rEnd
+		}
+	}
+	return nil
+}
+
+// fdfLiteral returns the bytes of the literal string with content b, with
+// its escape sequences resolved
+func fdfLiteral(b []byte) []byte {
+	var out []byte
+	for j := 0; j < len(b); j++ {
+		c := b[j]
+		if c != '\\' || j+1 == len(b) {
+			out = append(out, c)
+			continue
+		}
+		j++
+		switch c = b[j]; c {
+		case 'n':
+			out = append(out, '\n')
+		case 'r':
+			out = append(out, '\r')
+		case 't':
+			out = append(out, '\t')
+		case 'b':
+			out = append(out, '\b')
+		case 'f':
+			out = append(out, '\f')
+		case '\r':
+			// Line continuation
+			if j+1 < len(b) && b[j+1] == '\n' {
+				j++
+			}
+		case '\n':
+		default:
+			if c >= '0' && c <= '7' {
+				k := j
+				for k < len(b) && k < j+3 && b[k] >= '0' && b[k] <= '7' {
+					k++
+				}
+				v, _ := strconv.ParseUint(string(b[j:k]), 8, 8)
+				out = append(out, byte(v))
+				j = k - 1
+			} else {
+				out = append(out, c)
+			}
+		}
+	}
+	return out
+}
+
+// fdfText returns the text string b as UTF-8: it is UTF-16 if it begins
+// with a byte order mark and is otherwise taken as Latin-1, which
+// PDFDocEncoding matches for the characters of forms
+func fdfText(b []byte) string {
+	if len(b) >= 2 && b[0] == 0xfe && b[1] == 0xff {
+		units := make([]uint16, 0, len(b)/2)
+		for j := 2; j+1 < len(b); j += 2 {
+			units = append(units, uint16(b[j])<<8|uint16(b[j+1]))
+		}
+		return string(utf16.Decode(units))
+	}
+	runes := make([]rune, len(b))
+	for j, c := range b {
+		runes[j] = rune(c)
+	}
+	return string(runes)
+}
+
+// fdfNameDecode returns the name written as b, with its hexadecimal codes
+// resolved
+func fdfNameDecode(b []byte) string {
+	var out []byte
+	for j := 0; j < len(b); j++ {
+		if b[j] == '#' && j+2 < len(b) {
+			if v, err := strconv.ParseUint(string(b[j+1:j+3]), 16, 8); err == nil {
+				out = append(out, byte(v))
+				j += 2
+				continue
+			}
+		}
+		out = append(out, b[j])
+	}
+	return string(out)
+}
@@ -0,0 +1,592 @@
+package gofpdf
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// FormFieldType specifies the appearance and behavior of an interactive form
+// field added with AddTextField(), AddCheckBox(), AddRadioGroup() or
+// AddComboBox(). Create a value with NewFormField() and adjust its fields as
+// needed. Fields that do not apply to a kind of field are ignored.
+type FormFieldType struct {
+	// Text shown by most readers when the pointer rests on the field
+	Tooltip string
+	// The value cannot be changed by the reader
+	ReadOnly bool
+	// A value is needed before the form can be submitted
+	Required bool
+	// Font size in points of the value of text fields and combo boxes; zero
+	// lets the reader fit the text to the field
+	FontSize float64
+	// Color of the value, check mark or radio button dot
+	ClrText RGBType
+	// Width of the frame in points, zero for none, and its color
+	BorderWidth float64
+	ClrBorder   RGBType
+	// Paint the background of the field with ClrFill
+	Fill    bool
+	ClrFill RGBType
+	// Text field: the value may have several lines; it is masked, as for a
+	// password; the maximum number of characters, zero for no limit
+	Multiline, Password bool
+	MaxLen              int
+	// Text field: the format in which readers that run JavaScript show the
+	// value, and the values they accept; see FormFormatType
+	Format FormFormatType
+	// Combo box: the reader may enter a value that is not in the list
+	Editable bool
+	// Check box: the mark shown when the box is checked, FormMarkCheck (the
+	// default), FormMarkCross or FormMarkFill, as drawn by FormCheckbox()
+	Mark int
+}
+
+// FormRadioType is one button of a radio group added with AddRadioGroup().
+// The button is a circle of diameter Size with its upper left corner at (X,
+// Y) on the current page. Value is the value of the group when the button is
+// selected.
+type FormRadioType struct {
+	X, Y, Size float64
+	Value      string
+}
+
+// formWidgetType is the annotation of a form field on a page
+type formWidgetType struct {
+	page       int
+	x, y, w, h float64 // lower left corner and size in points
+	onStr      string  // state of a selected check box or radio button
+	obj        int     // object numbers assigned when the document is output
+	apOn       int     // appearance of a selected button, or of a text field or combo box
+	apOff      int     // appearance of a button that is not selected
+}
+
+// formFieldType is an interactive form field
+type formFieldType struct {
+	kindStr  string // Tx, Btn, Ch or Sig
+	nameStr  string
+	valueStr string
+	flags    int
+	radio    bool
+	options  []string
+	opt      FormFieldType
+	obj      int // object number of the field of a radio group
+	widgets  []formWidgetType
+}
+
+// Form field flags
+const (
+	formFlagReadOnly  = 1 << 0
+	formFlagRequired  = 1 << 1
+	formFlagMultiline = 1 << 12
+	formFlagPassword  = 1 << 13
+	formFlagNoToggle  = 1 << 14
+	formFlagRadio     = 1 << 15
+	formFlagCombo     = 1 << 17
+	formFlagEdit      = 1 << 18
+)
+
+const (
+	formCheckOnStr     = "Yes" // state of a checked check box
+	formRadioMarkStr   = "l"   // filled circle in ZapfDingbats
+	formBezierArcRatio = 0.5523
+)
+
+// formCheckMarks holds the ZapfDingbats characters that readers show for the
+// marks of check boxes when they regenerate their appearance
+var formCheckMarks = map[int]string{FormMarkCheck: "4", FormMarkCross: "8", FormMarkFill: "n"}
+
+// NewFormField returns a variable of type FormFieldType that is initialized
+// for fields with a thin black frame on a white background, with black text
+// that is fitted to the field.
+func NewFormField() (opt FormFieldType) {
+	opt.BorderWidth = 1
+	opt.Fill = true
+	opt.ClrFill = RGBType{255, 255, 255}
+	return
+}
+
+// AddTextField adds an interactive text field named nameStr, with its upper
+// left corner at (x, y) on the current page, that readers of the document can
+// fill in. valueStr is the initial value. Field names must be unique within
+// the document and may not contain periods. The field is not printed by this
+// method at the current position and does not move it; labels are printed
+// with the usual text methods.
+//
+// The fields of a document make up its interactive form (AcroForm). Readers
+// regenerate the appearance of text fields and combo boxes from their values;
+// the appearance written by gofpdf, used by readers that do not, shows only
+// characters of the Windows-1252 code page.
+func (f *Fpdf) AddTextField(nameStr string, x, y, w, h float64, valueStr string, opt FormFieldType) {
+	fld := f.newFormField(nameStr, "Tx", valueStr, opt)
+	if fld == nil {
+		return
+	}
+	if f.err = formFormatCheck(nameStr, opt.Format); f.err != nil {
+		return
+	}
+	if opt.Multiline {
+		fld.flags |= formFlagMultiline
+	}
+	if opt.Password {
+		fld.flags |= formFlagPassword
+	}
+	fld.widgets = []formWidgetType{f.formWidget(x, y, w, h, "")}
+	f.addFormField(fld)
+}
+
+// AddCheckBox adds an interactive check box named nameStr, a square of the
+// specified size with its upper left corner at (x, y) on the current page.
+// The box is initially checked if checked is true. The value of a checked box
+// is "Yes". See AddTextField() for the naming of fields. The mark of a checked
+// box, opt.Mark, is drawn as by FormCheckbox(), which draws a static box for
+// forms that are filled in by hand.
+func (f *Fpdf) AddCheckBox(nameStr string, x, y, size float64, checked bool, opt FormFieldType) {
+	valueStr := "Off"
+	if checked {
+		valueStr = formCheckOnStr
+	}
+	fld := f.newFormField(nameStr, "Btn", valueStr, opt)
+	if fld == nil {
+		return
+	}
+	if _, ok := formCheckMarks[opt.Mark]; !ok {
+		f.err = fmt.Errorf("unknown checkbox mark %d", opt.Mark)
+		return
+	}
+	fld.widgets = []formWidgetType{f.formWidget(x, y, size, size, formCheckOnStr)}
+	f.addFormField(fld)
+}
+
+// AddRadioGroup adds an interactive group of radio buttons named nameStr, of
+// which at most one is selected, on the current page. The value of the group
+// is the Value of the selected button; valueStr is the initial value, or
+// empty if no button is selected initially. See AddTextField() for the naming
+// of fields.
+func (f *Fpdf) AddRadioGroup(nameStr string, buttons []FormRadioType, valueStr string, opt FormFieldType) {
+	fld := f.newFormField(nameStr, "Btn", valueStr, opt)
+	if fld == nil {
+		return
+	}
+	if len(buttons) == 0 {
+		f.err = fmt.Errorf("radio group %s has no buttons", nameStr)
+		return
+	}
+	found := valueStr == ""
+	seen := make(map[string]bool)
+	for _, btn := range buttons {
+		if btn.Value == "" || seen[btn.Value] {
+			f.err = fmt.Errorf("radio group %s: button values must be unique and not empty", nameStr)
+			return
+		}
+		seen[btn.Value] = true
+		found = found || btn.Value == valueStr
+		fld.widgets = append(fld.widgets, f.formWidget(btn.X, btn.Y, btn.Size, btn.Size, btn.Value))
+	}
+	if !found {
+		f.err = fmt.Errorf("radio group %s has no button with the value %s", nameStr, valueStr)
+		return
+	}
+	if valueStr == "" {
+		fld.valueStr = "Off"
+	}
+	fld.radio = true
+	fld.flags |= formFlagRadio | formFlagNoToggle
+	f.addFormField(fld)
+}
+
+// AddComboBox adds an interactive combo box named nameStr, with its upper
+// left corner at (x, y) on the current page, from which readers choose one of
+// options. valueStr is the initial value; unless opt.Editable is true, it
+// must be empty or one of options. See AddTextField() for the naming of
+// fields.
+func (f *Fpdf) AddComboBox(nameStr string, x, y, w, h float64, options []string, valueStr string, opt FormFieldType) {
+	fld := f.newFormField(nameStr, "Ch", valueStr, opt)
+	if fld == nil {
+		return
+	}
+	found := valueStr == "" || opt.Editable
+	for _, str := range options {
+		found = found || str == valueStr
+	}
+	if !found {
+		f.err = fmt.Errorf("combo box %s has no option %s", nameStr, valueStr)
+		return
+	}
+	fld.options = append([]string(nil), options...)
+	fld.flags |= formFlagCombo
+	if opt.Editable {
+		fld.flags |= formFlagEdit
+	}
+	fld.widgets = []formWidgetType{f.formWidget(x, y, w, h, "")}
+	f.addFormField(fld)
+}
+
+// newFormField returns a form field with the settings common to all kinds of
+// fields, or nil if the field cannot be added
+func (f *Fpdf) newFormField(nameStr, kindStr, valueStr string, opt FormFieldType) *formFieldType {
+	if f.err != nil {
+		return nil
+	}
+	switch {
+	case f.page == 0:
+		f.err = fmt.Errorf("form field %s: no page has been added", nameStr)
+	case nameStr == "" || strings.Contains(nameStr, "."):
+		f.err = fmt.Errorf("form field name %q is empty or contains a period", nameStr)
+	case f.formFieldIndex(nameStr) >= 0:
+		f.err = fmt.Errorf("form field %s has already been added", nameStr)
+	}
+	if f.err != nil {
+		return nil
+	}
+	fld := &formFieldType{kindStr: kindStr, nameStr: nameStr, valueStr: valueStr, opt: opt}
+	if opt.ReadOnly {
+		fld.flags |= formFlagReadOnly
+	}
+	if opt.Required {
+		fld.flags |= formFlagRequired
+	}
+	return fld
+}
+
+// addFormField appends fld to the fields of the document, with the value set
+// for it with SetFormValues(), if any
+func (f *Fpdf) addFormField(fld *formFieldType) {
+	if valueStr, ok := f.formValues[fld.nameStr]; ok {
+		f.setFormValue(fld, valueStr)
+		if f.err != nil {
+			return
+		}
+	}
+	f.formFields = append(f.formFields, *fld)
+}
+
+// formFieldIndex returns the index of the field named nameStr, or -1
+func (f *Fpdf) formFieldIndex(nameStr string) int {
+	for j, fld := range f.formFields {
+		if fld.nameStr == nameStr {
+			return j
+		}
+	}
+	return -1
+}
+
+// formWidget returns a widget on the current page with its upper left corner
+// at (x, y)
+func (f *Fpdf) formWidget(x, y, w, h float64, onStr string) formWidgetType {
+	return formWidgetType{page: f.page, x: x * f.k, y: f.hPt - (y+h)*f.k,
+		w: w * f.k, h: h * f.k, onStr: onStr}
+}
+
+// formNumberObjects assigns object numbers, from first on, to the fields,
+// widgets, appearance streams and fonts of the form in the order in which
+// putFormFields() writes them, and returns the widget object numbers of each
+// page
+func (f *Fpdf) formNumberObjects(first int) map[int][]int {
+	n := first
+	annots := make(map[int][]int)
+	for j := range f.formFields {
+		fld := &f.formFields[j]
+		if fld.radio {
+			fld.obj = n
+			n++
+		}
+		for k := range fld.widgets {
+			wdg := &fld.widgets[k]
+			wdg.obj = n
+			n++
+			annots[wdg.page] = append(annots[wdg.page], wdg.obj)
+		}
+	}
+	for j := range f.formFields {
+		for k := range f.formFields[j].widgets {
+			wdg := &f.formFields[j].widgets[k]
+			wdg.apOn = n
+			n++
+			if wdg.onStr != "" {
+				wdg.apOff = n
+				n++
+			}
+		}
+	}
+	f.formFontObj = n
+	return annots
+}
+
+// putFormFields writes the objects numbered by formNumberObjects(). pageObjs
+// holds the object numbers of the pages.
+func (f *Fpdf) putFormFields(pageObjs []int) {
+	if len(f.formFields) == 0 {
+		return
+	}
+	for _, fld := range f.formFields {
+		if fld.radio {
+			f.newobj()
+			var kids fmtBuffer
+			for _, wdg := range fld.widgets {
+				kids.printf("%d 0 R ", wdg.obj)
+			}
+			f.outf("<</FT /Btn /T %s /Ff %d /V /%s /Kids [%s]%s>>", f.textstring(utf8toutf16(fld.nameStr)),
+				fld.flags, formName(fld.valueStr), strings.TrimSpace(kids.String()), f.formTooltip(fld))
+			f.out("endobj")
+		}
+		for _, wdg := range fld.widgets {
+			f.newobj()
+			var s fmtBuffer
+			s.printf("<</Type /Annot /Subtype /Widget /Rect [%.2f %.2f %.2f %.2f] /P %d 0 R /F 4",
+				wdg.x, wdg.y, wdg.x+wdg.w, wdg.y+wdg.h, pageObjs[wdg.page])
+			if fld.opt.BorderWidth > 0 {
+				s.printf(" /BS <</W %.2f /S /S>>", fld.opt.BorderWidth)
+			} else {
+				s.printf(" /Border [0 0 0]")
+			}
+			s.printf(" /MK <<%s%s", formColor("BC", fld.opt.BorderWidth > 0, fld.opt.ClrBorder),
+				formColor("BG", fld.opt.Fill, fld.opt.ClrFill))
+			if wdg.onStr != "" {
+				markStr := formCheckMarks[fld.opt.Mark]
+				if fld.radio {
+					markStr = formRadioMarkStr
+				}
+				state := "Off"
+				if fld.valueStr == wdg.onStr {
+					state = wdg.onStr
+				}
+				s.printf(" /CA (%s)>> /AS /%s /AP <</N <</%s %d 0 R /Off %d 0 R>>>>", markStr,
+					formName(state), formName(wdg.onStr), wdg.apOn, wdg.apOff)
+			} else {
+				s.printf(">> /AP <</N %d 0 R>>", wdg.apOn)
+			}
+			if fld.radio {
+				s.printf(" /Parent %d 0 R>>", fld.obj)
+			} else {
+				s.printf(" %s>>", f.formFieldEntries(fld))
+			}
+			f.out(s.String())
+			f.out("endobj")
+		}
+	}
+	tr := f.UnicodeTranslatorFromDescriptor("")
+	for _, fld := range f.formFields {
+		for _, wdg := range fld.widgets {
+			if wdg.onStr == "" {
+				f.putFormAppearance(wdg, fld, f.formTextAppearance(wdg, fld, tr))
+			} else {
+				f.putFormAppearance(wdg, fld, f.formMarkAppearance(wdg, fld))
+				f.putFormAppearance(wdg, fld, "")
+			}
+		}
+	}
+	if f.n+1 != f.formFontObj {
+		f.err = fmt.Errorf("form field objects are misnumbered")
+		return
+	}
+	f.newobj()
+	f.out("<</Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding>>")
+	f.out("endobj")
+	f.newobj()
+	f.out("<</Type /Font /Subtype /Type1 /BaseFont /ZapfDingbats>>")
+	f.out("endobj")
+}
+
+// formFieldEntries returns the field dictionary entries of fld, which is not
+// a radio group
+func (f *Fpdf) formFieldEntries(fld formFieldType) string {
+	var s fmtBuffer
+	s.printf("/FT /%s /T %s", fld.kindStr, f.textstring(utf8toutf16(fld.nameStr)))
+	if fld.flags != 0 {
+		s.printf(" /Ff %d", fld.flags)
+	}
+	switch fld.kindStr {
+	case "Btn":
+		s.printf(" /V /%s", formName(fld.valueStr))
+	case "Sig":
+		// The value is the signature, added by SignPDF()
+	default:
+		s.printf(" /V %s /DA (%s)", f.textstring(utf8toutf16(fld.valueStr)), formDA(fld.opt))
+		if fld.opt.MaxLen > 0 && fld.kindStr == "Tx" {
+			s.printf(" /MaxLen %d", fld.opt.MaxLen)
+		}
+		if len(fld.options) > 0 {
+			s.printf(" /Opt [")
+			for j, str := range fld.options {
+				if j > 0 {
+					s.printf(" ")
+				}
+				s.printf("%s", f.textstring(utf8toutf16(str)))
+			}
+			s.printf("]")
+		}
+	}
+	s.printf("%s%s", f.formTooltip(fld), f.formActions(fld))
+	return s.String()
+}
+
+// formTooltip returns the tooltip entry of fld, if it has one
+func (f *Fpdf) formTooltip(fld formFieldType) string {
+	if fld.opt.Tooltip == "" {
+		return ""
+	}
+	return " /TU " + f.textstring(utf8toutf16(fld.opt.Tooltip))
+}
+
+// putFormAppearance writes an appearance stream for the widget wdg of fld: its
+// background and frame, round for radio buttons, followed by contentStr
+func (f *Fpdf) putFormAppearance(wdg formWidgetType, fld formFieldType, contentStr string) {
+	var s fmtBuffer
+	opt, round := fld.opt, fld.radio
+	cx, cy, r := wdg.w/2, wdg.h/2, math.Min(wdg.w, wdg.h)/2
+	if opt.Fill {
+		s.printf("%.3f %.3f %.3f rg ", float64(opt.ClrFill.R)/255, float64(opt.ClrFill.G)/255,
+			float64(opt.ClrFill.B)/255)
+		if round {
+			s.printf("%s f ", formCircle(cx, cy, r))
+		} else {
+			s.printf("0 0 %.2f %.2f re f ", wdg.w, wdg.h)
+		}
+	}
+	if bw := opt.BorderWidth; bw > 0 {
+		s.printf("%.3f %.3f %.3f RG %.2f w ", float64(opt.ClrBorder.R)/255, float64(opt.ClrBorder.G)/255,
+			float64(opt.ClrBorder.B)/255, bw)
+		if round {
+			s.printf("%s S ", formCircle(cx, cy, r-bw/2))
+		} else {
+			s.printf("%.2f %.2f %.2f %.2f re S ", bw/2, bw/2, wdg.w-bw, wdg.h-bw)
+		}
+	}
+	s.printf("%s", contentStr)
+	data := []byte(strings.TrimSpace(s.String()))
+	f.newobj()
+	f.outf("<</Type /XObject /Subtype /Form /BBox [0 0 %.2f %.2f] "+
+		"/Resources <</Font <</Helv %d 0 R /ZaDb %d 0 R>>>> /Length %d>>",
+		wdg.w, wdg.h, f.formFontObj, f.formFontObj+1, len(data))
+	f.putstream(data)
+	f.out("endobj")
+}
+
+// formTextAppearance returns the content of the appearance of the text field
+// or combo box fld, showing its value in Helvetica converted with tr
+func (f *Fpdf) formTextAppearance(wdg formWidgetType, fld formFieldType, tr func(string) string) string {
+	valueStr := fld.valueStr
+	if fld.opt.Password {
+		valueStr = strings.Repeat("*", len([]rune(valueStr)))
+	}
+	if valueStr == "" {
+		return ""
+	}
+	size := fld.opt.FontSize
+	if size <= 0 {
+		size = math.Min(12, 0.7*wdg.h)
+	}
+	pad := 2 + fld.opt.BorderWidth
+	var s fmtBuffer
+	s.printf("/Tx BMC q %.2f %.2f %.2f %.2f re W n BT /Helv %.2f Tf %s ", pad/2, pad/2,
+		wdg.w-pad, wdg.h-pad, size, formRGB(fld.opt.ClrText, "rg"))
+	lines := []string{valueStr}
+	y := (wdg.h - 0.7*size) / 2
+	if fld.opt.Multiline {
+		lines = strings.Split(valueStr, "\n")
+		y = wdg.h - pad - 0.9*size
+	}
+	s.printf("%.2f %.2f Td %.2f TL", pad, y, 1.15*size)
+	for j, lineStr := range lines {
+		if j > 0 {
+			s.printf(" T*")
+		}
+		s.printf(" (%s) Tj", f.escape(tr(lineStr)))
+	}
+	s.printf(" ET Q EMC")
+	return s.String()
+}
+
+// formMarkAppearance returns the content of the appearance of the selected
+// check box or radio button wdg of fld
+func (f *Fpdf) formMarkAppearance(wdg formWidgetType, fld formFieldType) string {
+	if fld.radio {
+		r := math.Min(wdg.w, wdg.h) / 4
+		return formRGB(fld.opt.ClrText, "rg") + " " + formCircle(wdg.w/2, wdg.h/2, r) + " f"
+	}
+	size := math.Min(wdg.w, wdg.h)
+	x, y := (wdg.w-size)/2, (wdg.h-size)/2
+	pt := func(fx, fy float64) string {
+		return fmt.Sprintf("%.2f %.2f", x+fx*size, y+(1-fy)*size)
+	}
+	markStr, _ := formMarkPath(fld.opt.Mark, size, formRGB(fld.opt.ClrText, "RG"),
+		formRGB(fld.opt.ClrText, "rg"), pt)
+	return markStr
+}
+
+// formPutCatalog writes the interactive form entry of the document catalog
+func (f *Fpdf) formPutCatalog() {
+	if len(f.formFields) == 0 {
+		return
+	}
+	var fields fmtBuffer
+	for _, fld := range f.formFields {
+		if fld.radio {
+			fields.printf("%d 0 R ", fld.obj)
+		} else {
+			fields.printf("%d 0 R ", fld.widgets[0].obj)
+		}
+	}
+	sigStr := ""
+	for _, fld := range f.formFields {
+		if fld.kindStr == "Sig" {
+			// The document contains signature fields
+			sigStr = " /SigFlags 1"
+			break
+		}
+	}
+	f.outf("/AcroForm <</Fields [%s] /NeedAppearances true "+
+		"/DR <</Font <</Helv %d 0 R /ZaDb %d 0 R>>>> /DA (/Helv 0 Tf 0 g)%s>>",
+		strings.TrimSpace(fields.String()), f.formFontObj, f.formFontObj+1, sigStr)
+}
+
+// formDA returns the default appearance string of a field with options opt
+func formDA(opt FormFieldType) string {
+	return fmt.Sprintf("/Helv %.2f Tf %s", opt.FontSize, formRGB(opt.ClrText, "rg"))
+}
+
+// formRGB returns the operator opStr that sets the color clr
+func formRGB(clr RGBType, opStr string) string {
+	return fmt.Sprintf("%.3f %.3f %.3f %s", float64(clr.R)/255, float64(clr.G)/255,
+		float64(clr.B)/255, opStr)
+}
+
+// formColor returns the appearance characteristics entry keyStr with the
+// color clr, or an empty string if set is false
+func formColor(keyStr string, set bool, clr RGBType) string {
+	if !set {
+		return ""
+	}
+	return fmt.Sprintf("/%s [%.3f %.3f %.3f]", keyStr, float64(clr.R)/255,
+		float64(clr.G)/255, float64(clr.B)/255)
+}
+
+// formCircle returns a path that approximates the circle of radius r
+// centered on (cx, cy) with four Bézier curves
+func formCircle(cx, cy, r float64) string {
+	k := formBezierArcRatio * r
+	return fmt.Sprintf("%.2f %.2f m %.2f %.2f %.2f %.2f %.2f %.2f c %.2f %.2f %.2f %.2f %.2f %.2f c "+
+		"%.2f %.2f %.2f %.2f %.2f %.2f c %.2f %.2f %.2f %.2f %.2f %.2f c",
+		cx+r, cy,
+		cx+r, cy+k, cx+k, cy+r, cx, cy+r,
+		cx-k, cy+r, cx-r, cy+k, cx-r, cy,
+		cx-r, cy-k, cx-k, cy-r, cx, cy-r,
+		cx+k, cy-r, cx+r, cy-k, cx+r, cy)
+}
+
+// formName returns str encoded as a PDF name, without the leading slash.
+// Characters other than letters, digits and a few punctuation characters are
+// written as hexadecimal codes.
+func formName(str string) string {
+	var s strings.Builder
+	for j := 0; j < len(str); j++ {
+		c := str[j]
+		if c > 0x20 && c < 0x7f && !strings.ContainsRune("#%()<>[]{}/", rune(c)) {
+			s.WriteByte(c)
+		} else {
+			fmt.Fprintf(&s, "#%02X", c)
+		}
+	}
+	return s.String()
+}
@@ -0,0 +1,182 @@
+package gofpdf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Kinds of formats of text fields
+const (
+	// FormFormatNumber formats the value as a number with a fixed number of
+	// decimals and an optional currency symbol
+	FormFormatNumber = iota + 1
+	// FormFormatPercent multiplies the value by 100 and appends a percent sign
+	FormFormatPercent
+	// FormFormatDate restricts the value to dates written with a mask
+	FormFormatDate
+	// FormFormatSpecial restricts the value to a zip code, phone number or
+	// social security number
+	FormFormatSpecial
+)
+
+// Digit separators of number and percent formats
+const (
+	FormSepComma      = 0 // 1,234.56
+	FormSepNone       = 1 // 1234.56
+	FormSepDot        = 2 // 1.234,56
+	FormSepNoneComma  = 3 // 1234,56
+	formSepStyleCount = 4
+)
+
+// Special formats
+const (
+	FormSpecialZip   = 0 // 12345
+	FormSpecialZip4  = 1 // 12345-6789
+	FormSpecialPhone = 2 // (123) 456-7890
+	FormSpecialSSN   = 3 // 123-45-6789
+	formSpecialCount = 4
+)
+
+// formScriptActionStr is the format of a JavaScript action
+const formScriptActionStr = "<</S /JavaScript /JS %s>>"
+
+// FormFormatType specifies how readers that run JavaScript format, restrict
+// and validate the value of a text field. The actions call the functions
+// built into Acrobat for this purpose, such as AFNumber_Format(), which most
+// other readers that support forms also provide; readers without them leave
+// the value as entered. Assign a FormFormatType to the Format field of
+// FormFieldType. The zero value leaves the value unformatted.
+type FormFormatType struct {
+	// One of FormFormatNumber, FormFormatPercent, FormFormatDate and
+	// FormFormatSpecial, or zero for none
+	Kind int
+	// Number and percent: the number of decimals and the digit separators,
+	// one of FormSepComma, FormSepNone, FormSepDot and FormSepNoneComma
+	Decimals int
+	SepStyle int
+	// Number: the currency symbol, if any, written before the number or, if
+	// CurrencyAfter is true, after it
+	Currency      string
+	CurrencyAfter bool
+	// Number: negative numbers are shown in red
+	NegativeRed bool
+	// Date: the mask of the date, such as "mm/dd/yyyy" or "d-mmm-yy". Masks
+	// are made up of d, dd, m, mm, mmm, mmmm, yy, yyyy, H, HH, h, hh, MM, ss
+	// and tt along with separators.
+	Mask string
+	// Special: one of FormSpecialZip, FormSpecialZip4, FormSpecialPhone and
+	// FormSpecialSSN
+	Special int
+	// Number and percent: the value must be at least Min if MinSet is true
+	// and at most Max if MaxSet is true. Percent limits apply to the value
+	// before it is multiplied by 100.
+	MinSet, MaxSet bool
+	Min, Max       float64
+}
+
+// formFormatCheck returns an error if the format ft of the text field
+// nameStr is invalid
+func formFormatCheck(nameStr string, ft FormFormatType) error {
+	number := ft.Kind == FormFormatNumber || ft.Kind == FormFormatPercent
+	switch {
+	case ft.Kind < 0 || ft.Kind > FormFormatSpecial:
+		return fmt.Errorf("text field %s has unknown format %d", nameStr, ft.Kind)
+	case number && (ft.Decimals < 0 || ft.Decimals > 10):
+		return fmt.Errorf("text field %s: %d decimals are not supported", nameStr, ft.Decimals)
+	case number && (ft.SepStyle < 0 || ft.SepStyle >= formSepStyleCount):
+		return fmt.Errorf("text field %s has unknown separator style %d", nameStr, ft.SepStyle)
+	case ft.Kind == FormFormatDate && ft.Mask == "":
+		return fmt.Errorf("text field %s: date format requires a mask", nameStr)
+	case ft.Kind == FormFormatSpecial && (ft.Special < 0 || ft.Special >= formSpecialCount):
+		return fmt.Errorf("text field %s has unknown special format %d", nameStr, ft.Special)
+	case (ft.MinSet || ft.MaxSet) && !number:
+		return fmt.Errorf("text field %s: range requires a number or percent format", nameStr)
+	case ft.MinSet && ft.MaxSet && ft.Min > ft.Max:
+		return fmt.Errorf("text field %s: minimum %g exceeds maximum %g", nameStr, ft.Min, ft.Max)
+	}
+	return nil
+}
+
+// formFormatScripts returns the JavaScript of the keystroke, format and
+// validate actions of a field with the format ft. Scripts that do not apply
+// are empty.
+func formFormatScripts(ft FormFormatType) (keyStr, fmtStr, validStr string) {
+	switch ft.Kind {
+	case FormFormatNumber:
+		negStyle := 0
+		if ft.NegativeRed {
+			negStyle = 1
+		}
+		argStr := fmt.Sprintf("%d, %d, %d, 0, %s, %t", ft.Decimals, ft.SepStyle, negStyle,
+			jsQuote(ft.Currency), !ft.CurrencyAfter)
+		keyStr = "AFNumber_Keystroke(" + argStr + ");"
+		fmtStr = "AFNumber_Format(" + argStr + ");"
+	case FormFormatPercent:
+		argStr := fmt.Sprintf("%d, %d", ft.Decimals, ft.SepStyle)
+		keyStr = "AFPercent_Keystroke(" + argStr + ");"
+		fmtStr = "AFPercent_Format(" + argStr + ");"
+	case FormFormatDate:
+		argStr := jsQuote(ft.Mask)
+		keyStr = "AFDate_KeystrokeEx(" + argStr + ");"
+		fmtStr = "AFDate_FormatEx(" + argStr + ");"
+	case FormFormatSpecial:
+		keyStr = fmt.Sprintf("AFSpecial_Keystroke(%d);", ft.Special)
+		fmtStr = fmt.Sprintf("AFSpecial_Format(%d);", ft.Special)
+	}
+	if ft.MinSet || ft.MaxSet {
+		validStr = fmt.Sprintf("AFRange_Validate(%t, %s, %t, %s);", ft.MinSet,
+			strconv.FormatFloat(ft.Min, 'f', -1, 64), ft.MaxSet, strconv.FormatFloat(ft.Max, 'f', -1, 64))
+	}
+	return
+}
+
+// formActions returns the additional-actions entry of the text field fld, or
+// an empty string if its value is not formatted
+func (f *Fpdf) formActions(fld formFieldType) string {
+	if fld.kindStr != "Tx" || fld.opt.Format.Kind == 0 {
+		return ""
+	}
+	keyStr, fmtStr, validStr := formFormatScripts(fld.opt.Format)
+	var s fmtBuffer
+	s.printf(" /AA <</K "+formScriptActionStr+" /F "+formScriptActionStr, f.textstring(keyStr),
+		f.textstring(fmtStr))
+	if validStr != "" {
+		s.printf(" /V "+formScriptActionStr, f.textstring(validStr))
+	}
+	s.printf(">>")
+	return s.String()
+}
+
+// formScripted reports whether any field of the form runs JavaScript
+func (f *Fpdf) formScripted() bool {
+	for _, fld := range f.formFields {
+		if fld.kindStr == "Tx" && fld.opt.Format.Kind != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// jsQuote returns str as a JavaScript string literal made up of ASCII
+// characters
+func jsQuote(str string) string {
+	var s strings.Builder
+	s.WriteByte('"')
+	for _, r := range str {
+		switch {
+		case r == '"' || r == '\\':
+			s.WriteByte('\\')
+			s.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			for _, c := range utf16.Encode([]rune{r}) {
+				fmt.Fprintf(&s, "\\u%04x", c)
+			}
+		default:
+			s.WriteRune(r)
+		}
+	}
+	s.WriteByte('"')
+	return s.String()
+}
@@ -0,0 +1,241 @@
+package gofpdf
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Marks drawn by FormCheckbox() in a checked box
+const (
+	// FormMarkCheck is a check mark
+	FormMarkCheck = iota
+	// FormMarkCross is a diagonal cross
+	FormMarkCross
+	// FormMarkFill is a filled square
+	FormMarkFill
+)
+
+// FormStyleType specifies the appearance of the form elements drawn by
+// FormCheckbox(), FormRadio(), FormSignatureLine() and FormDateField(). These
+// elements look like the fields of a form but are static drawings rather than
+// interactive fields; they are meant for forms that are printed and filled in
+// by hand, or that are completed by the application when the document is
+// generated. Create a value with NewFormStyle() and adjust its fields as
+// needed.
+type FormStyleType struct {
+	// Width of frames and lines in user units; zero selects the current line
+	// width
+	LineWidth float64
+	// Colors of frames and lines, of marks and filled-in values, of labels and
+	// captions, and of the placeholders of empty date fields
+	ClrLine, ClrMark, ClrLabel, ClrHint RGBType
+	// Paint the background of boxes and circles with ClrFill
+	Fill    bool
+	ClrFill RGBType
+	// Mark drawn in a checked box: FormMarkCheck, FormMarkCross or
+	// FormMarkFill
+	Mark int
+	// Space between a box or circle and its label in user units; zero selects
+	// half the size of the box
+	Gap float64
+	// Size of captions relative to the current font size; zero selects 0.75
+	CaptionScale float64
+}
+
+// NewFormStyle returns a variable of type FormStyleType that is initialized
+// to draw black frames on a white background, check marks, black labels and
+// gray placeholders.
+func NewFormStyle() (st FormStyleType) {
+	st.ClrLine = RGBType{0, 0, 0}
+	st.ClrMark = RGBType{0, 0, 0}
+	st.ClrLabel = RGBType{0, 0, 0}
+	st.ClrHint = RGBType{160, 160, 160}
+	st.Fill = true
+	st.ClrFill = RGBType{255, 255, 255}
+	st.Mark = FormMarkCheck
+	st.CaptionScale = 0.75
+	return
+}
+
+// formBegin sets the line width and colors of st and returns the state to be
+// restored when the element is complete
+func (f *Fpdf) formBegin(st FormStyleType) StateType {
+	state := StateGet(f)
+	if st.LineWidth > 0 {
+		f.SetLineWidth(st.LineWidth)
+	}
+	f.SetDrawColor(st.ClrLine.R, st.ClrLine.G, st.ClrLine.B)
+	f.SetFillColor(st.ClrFill.R, st.ClrFill.G, st.ClrFill.B)
+	return state
+}
+
+// formLabel prints labelStr in the current font, vertically centered on a box
+// of height size at (x, y)
+func (f *Fpdf) formLabel(x, y, size float64, labelStr string, st FormStyleType) {
+	if labelStr == "" {
+		return
+	}
+	gap := st.Gap
+	if gap == 0 {
+		gap = size / 2
+	}
+	f.SetTextColor(st.ClrLabel.R, st.ClrLabel.G, st.ClrLabel.B)
+	f.Text(x+size+gap, y+0.5*size+0.3*f.fontSize, labelStr)
+}
+
+// formCaption prints captionStr in a reduced font size below the line at
+// vertical position y, beginning at x
+func (f *Fpdf) formCaption(x, y float64, captionStr string, st FormStyleType) {
+	if captionStr == "" {
+		return
+	}
+	scale := st.CaptionScale
+	if scale == 0 {
+		scale = 0.75
+	}
+	_, size := f.GetFontSize()
+	f.SetFontUnitSize(size * scale)
+	f.SetTextColor(st.ClrLabel.R, st.ClrLabel.G, st.ClrLabel.B)
+	f.Text(x, y+1.1*f.fontSize, captionStr)
+}
+
+// FormCheckbox draws a square box of the specified size with its upper left
+// corner at (x, y), marked as specified by st.Mark if checked is true. A
+// non-empty labelStr is printed to the right of the box in the current font.
+// The current position, colors and line width are not changed. The box is a
+// static drawing; AddCheckBox() adds a check box that the reader of the
+// document can check, with the same marks.
+func (f *Fpdf) FormCheckbox(x, y, size float64, checked bool, labelStr string, st FormStyleType) {
+	if f.err != nil {
+		return
+	}
+	state := f.formBegin(st)
+	f.Rect(x, y, size, size, strIf(st.Fill, "FD", "D"))
+	if checked {
+		k, h := f.k, f.h
+		// pt returns the page coordinates of a point given as fractions of
+		// the box size
+		pt := func(fx, fy float64) string {
+			return sprintf("%.2f %.2f", (x+fx*size)*k, (h-(y+fy*size))*k)
+		}
+		stroke := rgbColorValue(st.ClrMark.R, st.ClrMark.G, st.ClrMark.B, "G", "RG").str
+		fill := rgbColorValue(st.ClrMark.R, st.ClrMark.G, st.ClrMark.B, "g", "rg").str
+		markStr, ok := formMarkPath(st.Mark, size*k, stroke, fill, pt)
+		if !ok {
+			state.Put(f)
+			f.err = fmt.Errorf("unknown checkbox mark %d", st.Mark)
+			return
+		}
+		f.out(markStr)
+	}
+	f.formLabel(x, y, size, labelStr, st)
+	state.Put(f)
+}
+
+// formMarkPath returns the operators that draw the mark of a checked box of
+// the specified size, in points, with the colors set by stroke and fill. pt
+// returns the coordinates of a point given as fractions of the box size from
+// its upper left corner. The mark is drawn in the same way by FormCheckbox()
+// and in the appearance of check boxes added with AddCheckBox(). False is
+// returned if mark is unknown.
+func formMarkPath(mark int, size float64, stroke, fill string, pt func(fx, fy float64) string) (string, bool) {
+	switch mark {
+	case FormMarkCheck:
+		return sprintf("q %.2f w 1 J 1 j %s %s m %s l %s l S Q", 0.12*size, stroke,
+			pt(0.22, 0.52), pt(0.42, 0.74), pt(0.78, 0.26)), true
+	case FormMarkCross:
+		return sprintf("q %.2f w 1 J %s %s m %s l %s m %s l S Q", 0.1*size, stroke,
+			pt(0.25, 0.25), pt(0.75, 0.75), pt(0.25, 0.75), pt(0.75, 0.25)), true
+	case FormMarkFill:
+		return sprintf("q %s %s %.2f %.2f re f Q", fill, pt(0.25, 0.75), 0.5*size, 0.5*size), true
+	}
+	return "", false
+}
+
+// FormRadio draws a circle of diameter size with the upper left corner of its
+// bounding square at (x, y), with a dot in its center if selected is true. A
+// non-empty labelStr is printed to the right of the circle in the current
+// font. The current position, colors and line width are not changed.
+func (f *Fpdf) FormRadio(x, y, size float64, selected bool, labelStr string, st FormStyleType) {
+	if f.err != nil {
+		return
+	}
+	state := f.formBegin(st)
+	r := size / 2
+	f.Circle(x+r, y+r, r, strIf(st.Fill, "FD", "D"))
+	if selected {
+		f.SetFillColor(st.ClrMark.R, st.ClrMark.G, st.ClrMark.B)
+		f.Circle(x+r, y+r, r/2, "F")
+	}
+	f.formLabel(x, y, size, labelStr, st)
+	state.Put(f)
+}
+
+// FormSignatureLine draws a line of width w at vertical position y, beginning
+// at x, on which a signature can be written. A non-empty captionStr, such as
+// "Signature" or the name of the signatory, is printed below the line in a
+// font reduced by st.CaptionScale. The current position, colors, line width
+// and font size are not changed.
+func (f *Fpdf) FormSignatureLine(x, y, w float64, captionStr string, st FormStyleType) {
+	if f.err != nil {
+		return
+	}
+	state := f.formBegin(st)
+	f.Line(x, y, x+w, y)
+	f.formCaption(x, y, captionStr, st)
+	state.Put(f)
+}
+
+// FormDateField draws a row of boxes of height h, with its upper left corner
+// at (x, y), in which a date can be written one character per box. layoutStr
+// describes the field: each letter or digit, such as those of "DD.MM.YYYY",
+// is a box, and other characters are separators that are printed between the
+// boxes. Each box is 0.75 times as wide as it is high.
+//
+// If valueStr is empty, the letters of layoutStr are shown in the boxes as
+// placeholders in st.ClrHint. Otherwise the letters and digits of valueStr,
+// such as "24.12.2025", are filled into the boxes in st.ClrMark. A non-empty
+// captionStr is printed below the boxes in a font reduced by st.CaptionScale.
+// The current position, colors, line width and font size are not changed.
+func (f *Fpdf) FormDateField(x, y, h float64, layoutStr, valueStr, captionStr string, st FormStyleType) {
+	if f.err != nil {
+		return
+	}
+	state := f.formBegin(st)
+	var values []rune
+	for _, r := range valueStr {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			values = append(values, r)
+		}
+	}
+	boxWd := 0.75 * h
+	baseY := y + 0.5*h + 0.3*f.fontSize
+	x0, j := x, 0
+	for _, r := range layoutStr {
+		str := string(r)
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			f.SetTextColor(st.ClrLine.R, st.ClrLine.G, st.ClrLine.B)
+			wd := f.GetStringWidth(str)
+			f.Text(x+f.cMargin/2, baseY, str)
+			x += wd + f.cMargin
+			continue
+		}
+		f.Rect(x, y, boxWd, h, strIf(st.Fill, "FD", "D"))
+		clr := st.ClrHint
+		if len(values) > 0 {
+			clr, str = st.ClrMark, ""
+			if j < len(values) {
+				str = string(values[j])
+			}
+		} else {
+			str = strings.ToUpper(str)
+		}
+		j++
+		f.SetTextColor(clr.R, clr.G, clr.B)
+		f.Text(x+(boxWd-f.GetStringWidth(str))/2, baseY, str)
+		x += boxWd
+	}
+	f.formCaption(x0, y+h, captionStr, st)
+	state.Put(f)
+}
@@ -0,0 +1,566 @@
+package gofpdf
+
+import (
+	"errors"
+	"math"
+	"unicode"
+)
+
+// Math formula layout. Formulas are written in a small subset of LaTeX math
+// notation and are laid out as a tree of boxes, each of which knows its width,
+// its height above the baseline and its depth below it. Text is printed with
+// the Times and Symbol core fonts.
+
+// formulaBox is a laid out piece of a formula. Dimensions are in user units.
+type formulaBox struct {
+	wd, asc, dsc float64
+	draw         func(x, y float64) // y is the baseline
+}
+
+// Kinds of formula symbols; binary operators and relations are surrounded
+// with space
+const (
+	fmlOrd = iota
+	fmlBin
+	fmlRel
+	fmlLarge
+)
+
+type formulaSymType struct {
+	glyph string
+	kind  int
+}
+
+// formulaSymbols maps LaTeX command names to Symbol font glyph names
+var formulaSymbols = map[string]formulaSymType{
+	"alpha": {"alpha", fmlOrd}, "beta": {"beta", fmlOrd}, "gamma": {"gamma", fmlOrd},
+	"delta": {"delta", fmlOrd}, "epsilon": {"epsilon", fmlOrd}, "zeta": {"zeta", fmlOrd},
+	"eta": {"eta", fmlOrd}, "theta": {"theta", fmlOrd}, "vartheta": {"theta1", fmlOrd},
+	"iota": {"iota", fmlOrd}, "kappa": {"kappa", fmlOrd}, "lambda": {"lambda", fmlOrd},
+	"mu": {"mu", fmlOrd}, "nu": {"nu", fmlOrd}, "xi": {"xi", fmlOrd}, "pi": {"pi", fmlOrd},
+	"varpi": {"omega1", fmlOrd}, "rho": {"rho", fmlOrd}, "sigma": {"sigma", fmlOrd},
+	"varsigma": {"sigma1", fmlOrd}, "tau": {"tau", fmlOrd}, "upsilon": {"upsilon", fmlOrd},
+	"phi": {"phi1", fmlOrd}, "varphi": {"phi", fmlOrd}, "chi": {"chi", fmlOrd},
+	"psi": {"psi", fmlOrd}, "omega": {"omega", fmlOrd}, "Gamma": {"Gamma", fmlOrd},
+	"Delta": {"Delta", fmlOrd}, "Theta": {"Theta", fmlOrd}, "Lambda": {"Lambda", fmlOrd},
+	"Xi": {"Xi", fmlOrd}, "Pi": {"Pi", fmlOrd}, "Sigma": {"Sigma", fmlOrd},
+	"Upsilon": {"Upsilon1", fmlOrd}, "Phi": {"Phi", fmlOrd}, "Psi": {"Psi", fmlOrd},
+	"Omega": {"Omega", fmlOrd}, "infty": {"infinity", fmlOrd}, "partial": {"partialdiff", fmlOrd},
+	"nabla": {"gradient", fmlOrd}, "forall": {"universal", fmlOrd}, "exists": {"existential", fmlOrd},
+	"emptyset": {"emptyset", fmlOrd}, "neg": {"logicalnot", fmlOrd}, "lnot": {"logicalnot", fmlOrd},
+	"aleph": {"aleph", fmlOrd}, "Re": {"Rfraktur", fmlOrd}, "Im": {"Ifraktur", fmlOrd},
+	"wp": {"weierstrass", fmlOrd}, "angle": {"angle", fmlOrd}, "prime": {"minute", fmlOrd},
+	"ldots": {"ellipsis", fmlOrd}, "cdots": {"ellipsis", fmlOrd}, "therefore": {"therefore", fmlOrd},
+	"times": {"multiply", fmlBin}, "pm": {"plusminus", fmlBin}, "cdot": {"dotmath", fmlBin},
+	"div": {"divide", fmlBin}, "cap": {"intersection", fmlBin}, "cup": {"union", fmlBin},
+	"oplus": {"circleplus", fmlBin}, "otimes": {"circlemultiply", fmlBin}, "wedge": {"logicaland", fmlBin},
+	"land": {"logicaland", fmlBin}, "vee": {"logicalor", fmlBin}, "lor": {"logicalor", fmlBin},
+	"le": {"lessequal", fmlRel}, "leq": {"lessequal", fmlRel}, "ge": {"greaterequal", fmlRel},
+	"geq": {"greaterequal", fmlRel}, "ne": {"notequal", fmlRel}, "neq": {"notequal", fmlRel},
+	"approx": {"approxequal", fmlRel}, "equiv": {"equivalence", fmlRel}, "sim": {"similar", fmlRel},
+	"cong": {"congruent", fmlRel}, "propto": {"proportional", fmlRel}, "in": {"element", fmlRel},
+	"notin": {"notelement", fmlRel}, "subset": {"propersubset", fmlRel}, "supset": {"propersuperset", fmlRel},
+	"subseteq": {"reflexsubset", fmlRel}, "supseteq": {"reflexsuperset", fmlRel}, "perp": {"perpendicular", fmlRel},
+	"to": {"arrowright", fmlRel}, "rightarrow": {"arrowright", fmlRel}, "leftarrow": {"arrowleft", fmlRel},
+	"leftrightarrow": {"arrowboth", fmlRel}, "Rightarrow": {"arrowdblright", fmlRel},
+	"Leftarrow": {"arrowdblleft", fmlRel}, "Leftrightarrow": {"arrowdblboth", fmlRel},
+	"sum": {"summation", fmlLarge}, "prod": {"product", fmlLarge}, "int": {"integral", fmlLarge},
+}
+
+// formulaFunctions lists the LaTeX commands that print a function name
+var formulaFunctions = map[string]bool{
+	"sin": true, "cos": true, "tan": true, "cot": true, "sec": true, "csc": true,
+	"arcsin": true, "arccos": true, "arctan": true, "sinh": true, "cosh": true,
+	"tanh": true, "log": true, "ln": true, "exp": true, "lim": true, "max": true,
+	"min": true, "sup": true, "inf": true, "det": true, "gcd": true,
+}
+
+// formulaOperators maps operator characters to Symbol font glyph names
+var formulaOperators = map[rune]formulaSymType{
+	'+': {"plus", fmlBin}, '-': {"minus", fmlBin}, '*': {"asteriskmath", fmlBin},
+	'=': {"equal", fmlRel}, '<': {"less", fmlRel}, '>': {"greater", fmlRel},
+}
+
+type formulaParser struct {
+	f   *Fpdf
+	src []rune
+	pos int
+	err error
+}
+
+// em returns the size of one em, in user units, for a font of size points
+func (p *formulaParser) em(size float64) float64 {
+	return size / p.f.k
+}
+
+// text returns a box that prints str in the specified core font
+func (p *formulaParser) text(str, familyStr, styleStr string, size float64) formulaBox {
+	f := p.f
+	em := p.em(size)
+	return formulaBox{
+		wd:  f.MeasureText(familyStr, styleStr, size, str),
+		asc: 0.72 * em,
+		dsc: 0.22 * em,
+		draw: func(x, y float64) {
+			f.SetFont(familyStr, styleStr, size)
+			f.Text(x, y, str)
+		},
+	}
+}
+
+// formulaSpace returns an empty box of the specified width
+func formulaSpace(wd float64) formulaBox {
+	return formulaBox{wd: wd, draw: func(x, y float64) {}}
+}
+
+// formulaPad adds horizontal space on both sides of box b
+func formulaPad(b formulaBox, wd float64) formulaBox {
+	draw := b.draw
+	b.draw = func(x, y float64) { draw(x+wd, y) }
+	b.wd += 2 * wd
+	return b
+}
+
+// formulaHList lays out boxes side by side
+func formulaHList(list []formulaBox) (b formulaBox) {
+	for _, item := range list {
+		b.wd += item.wd
+		b.asc = math.Max(b.asc, item.asc)
+		b.dsc = math.Max(b.dsc, item.dsc)
+	}
+	b.draw = func(x, y float64) {
+		for _, item := range list {
+			item.draw(x, y)
+			x += item.wd
+		}
+	}
+	return
+}
+
+// symbol returns a box for the named Symbol font glyph
+func (p *formulaParser) symbol(sym formulaSymType, size float64) formulaBox {
+	em := p.em(size)
+	switch sym.kind {
+	case fmlBin:
+		return formulaPad(p.text(SymbolGlyph(sym.glyph), "StandardSymbol", "", size), 0.2*em)
+	case fmlRel:
+		return formulaPad(p.text(SymbolGlyph(sym.glyph), "StandardSymbol", "", size), 0.27*em)
+	case fmlLarge:
+		// Print large operators bigger and centered on the math axis
+		b := p.text(SymbolGlyph(sym.glyph), "StandardSymbol", "", 1.4*size)
+		shift := 0.2 * em
+		draw := b.draw
+		b.draw = func(x, y float64) { draw(x, y+shift) }
+		b.asc -= shift
+		b.dsc += shift
+		return formulaPad(b, 0.1*em)
+	}
+	return p.text(SymbolGlyph(sym.glyph), "StandardSymbol", "", size)
+}
+
+func formulaScriptSize(size float64) float64 {
+	return math.Max(0.7*size, 4)
+}
+
+// fraction stacks num over den, separated by a rule
+func (p *formulaParser) fraction(num, den formulaBox, size float64) formulaBox {
+	f := p.f
+	em := p.em(size)
+	axis := 0.25 * em
+	t := 0.05 * em
+	gap := 0.1 * em
+	wd := math.Max(num.wd, den.wd) + 0.2*em
+	numShift := axis + t/2 + gap + num.dsc
+	denShift := den.asc + gap + t/2 - axis
+	return formulaBox{
+		wd:  wd,
+		asc: numShift + num.asc,
+		dsc: denShift + den.dsc,
+		draw: func(x, y float64) {
+			num.draw(x+(wd-num.wd)/2, y-numShift)
+			den.draw(x+(wd-den.wd)/2, y+denShift)
+			f.SetLineWidth(t)
+			f.Line(x+0.05*em, y-axis, x+wd-0.05*em, y-axis)
+		},
+	}
+}
+
+// radical places a radical sign and overbar around b, with the root index
+// index, if not nil, raised above the sign
+func (p *formulaParser) radical(b formulaBox, index *formulaBox, size float64) formulaBox {
+	f := p.f
+	em := p.em(size)
+	t := 0.05 * em
+	gap := 0.12 * em
+	signWd := 0.5 * em
+	ht := b.asc + gap + t
+	// The index ends over the short stroke of the sign, which is moved right
+	// if the index is wider than that stroke
+	var dx, raise float64
+	asc := ht + t
+	if index != nil {
+		dx = math.Max(index.wd-0.3*em, 0)
+		raise = 0.6*(ht+b.dsc) - b.dsc
+		asc = math.Max(asc, raise+index.asc)
+	}
+	return formulaBox{
+		wd:  dx + signWd + b.wd + 0.1*em,
+		asc: asc,
+		dsc: b.dsc,
+		draw: func(x, y float64) {
+			if index != nil {
+				index.draw(x+dx+0.3*em-index.wd, y-raise)
+			}
+			x += dx
+			bottom := y + b.dsc
+			top := y - ht
+			f.SetLineWidth(t)
+			f.MoveTo(x+0.05*em, bottom-0.45*(bottom-top))
+			f.LineTo(x+0.15*em, bottom-0.52*(bottom-top))
+			f.LineTo(x+0.27*em, bottom)
+			f.LineTo(x+signWd, top)
+			f.LineTo(x+signWd+b.wd+0.1*em, top)
+			f.DrawPath("D")
+			b.draw(x+signWd, y)
+		},
+	}
+}
+
+// scripts attaches a superscript and/or subscript to base
+func (p *formulaParser) scripts(base formulaBox, sup, sub *formulaBox, size float64) formulaBox {
+	em := p.em(size)
+	b := formulaBox{wd: base.wd, asc: base.asc, dsc: base.dsc}
+	var supShift, subShift, wd float64
+	if sup != nil {
+		supShift = math.Max(base.asc-0.35*em, 0.4*em)
+		b.asc = math.Max(b.asc, supShift+sup.asc)
+		wd = sup.wd
+	}
+	if sub != nil {
+		subShift = math.Max(base.dsc, 0.2*em)
+		b.dsc = math.Max(b.dsc, subShift+sub.dsc)
+		wd = math.Max(wd, sub.wd)
+	}
+	b.wd += wd + 0.05*em
+	b.draw = func(x, y float64) {
+		base.draw(x, y)
+		if sup != nil {
+			sup.draw(x+base.wd, y-supShift)
+		}
+		if sub != nil {
+			sub.draw(x+base.wd, y+subShift)
+		}
+	}
+	return b
+}
+
+func (p *formulaParser) fail(msg string) formulaBox {
+	if p.err == nil {
+		p.err = errors.New(msg)
+	}
+	p.pos = len(p.src)
+	return formulaSpace(0)
+}
+
+func (p *formulaParser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+// list parses atoms up to the end of the formula or, if group is true, up to
+// and including the closing brace
+func (p *formulaParser) list(size float64, group bool) formulaBox {
+	var boxes []formulaBox
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			if group {
+				p.fail("missing }")
+			}
+			break
+		}
+		r := p.src[p.pos]
+		if r == '}' {
+			if !group {
+				return p.fail("unexpected }")
+			}
+			p.pos++
+			break
+		}
+		var b formulaBox
+		if r == '^' || r == '_' {
+			b = formulaSpace(0)
+		} else {
+			b = p.atom(size)
+		}
+		boxes = append(boxes, p.attach(b, size))
+		if p.err != nil {
+			break
+		}
+	}
+	return formulaHList(boxes)
+}
+
+// attach parses any scripts that follow an atom
+func (p *formulaParser) attach(base formulaBox, size float64) formulaBox {
+	var sup, sub *formulaBox
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			break
+		}
+		r := p.src[p.pos]
+		if r != '^' && r != '_' {
+			break
+		}
+		p.pos++
+		b := p.arg(formulaScriptSize(size))
+		if r == '^' {
+			if sup != nil {
+				p.fail("double superscript")
+			}
+			sup = &b
+		} else {
+			if sub != nil {
+				p.fail("double subscript")
+			}
+			sub = &b
+		}
+	}
+	if sup == nil && sub == nil {
+		return base
+	}
+	return p.scripts(base, sup, sub, size)
+}
+
+// arg parses a command argument or script, either a braced group or a single
+// atom
+func (p *formulaParser) arg(size float64) formulaBox {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return p.fail("missing argument")
+	}
+	return p.atom(size)
+}
+
+// optArg parses an optional command argument in brackets, such as the index
+// of \sqrt[3]{x}, and returns nil if there is none. Brackets within braces
+// belong to the argument.
+func (p *formulaParser) optArg(size float64) *formulaBox {
+	p.skipSpace()
+	if p.pos >= len(p.src) || p.src[p.pos] != '[' {
+		return nil
+	}
+	depth := 0
+	for j := p.pos + 1; j < len(p.src); j++ {
+		switch p.src[j] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ']':
+			if depth > 0 {
+				continue
+			}
+			sub := formulaParser{f: p.f, src: p.src[p.pos+1 : j]}
+			b := sub.list(size, false)
+			if sub.err != nil {
+				p.fail(sub.err.Error())
+				return nil
+			}
+			p.pos = j + 1
+			return &b
+		}
+	}
+	p.fail("missing ]")
+	return nil
+}
+
+// raw returns the text of a braced group without interpreting it
+func (p *formulaParser) raw() string {
+	p.skipSpace()
+	if p.pos >= len(p.src) || p.src[p.pos] != '{' {
+		p.fail("missing {")
+		return ""
+	}
+	start := p.pos + 1
+	for p.pos = start; p.pos < len(p.src); p.pos++ {
+		if p.src[p.pos] == '}' {
+			p.pos++
+			return string(p.src[start : p.pos-1])
+		}
+	}
+	p.fail("missing }")
+	return ""
+}
+
+func (p *formulaParser) atom(size float64) formulaBox {
+	em := p.em(size)
+	r := p.src[p.pos]
+	p.pos++
+	switch {
+	case r == '{':
+		return p.list(size, true)
+	case r == '\\':
+		return p.command(size)
+	case unicode.IsLetter(r):
+		return p.text(string(r), "Times", "I", size)
+	case unicode.IsDigit(r) || r == '.':
+		start := p.pos - 1
+		for p.pos < len(p.src) && (unicode.IsDigit(p.src[p.pos]) || p.src[p.pos] == '.') {
+			p.pos++
+		}
+		return p.text(string(p.src[start:p.pos]), "Times", "", size)
+	case r == ',':
+		return formulaHList([]formulaBox{p.text(",", "Times", "", size), formulaSpace(0.17 * em)})
+	case r == '^' || r == '_' || r == '}':
+		return p.fail("unexpected " + string(r))
+	}
+	if sym, ok := formulaOperators[r]; ok {
+		return p.symbol(sym, size)
+	}
+	return p.text(string(r), "Times", "", size)
+}
+
+func (p *formulaParser) command(size float64) formulaBox {
+	em := p.em(size)
+	if p.pos >= len(p.src) {
+		return p.fail("incomplete command")
+	}
+	start := p.pos
+	if unicode.IsLetter(p.src[p.pos]) {
+		for p.pos < len(p.src) && unicode.IsLetter(p.src[p.pos]) {
+			p.pos++
+		}
+	} else {
+		p.pos++
+	}
+	name := string(p.src[start:p.pos])
+	switch name {
+	case "frac":
+		sub := math.Max(0.85*size, 4)
+		num := p.arg(sub)
+		den := p.arg(sub)
+		return p.fraction(num, den, size)
+	case "sqrt":
+		index := p.optArg(formulaScriptSize(formulaScriptSize(size)))
+		if p.err != nil {
+			return formulaSpace(0)
+		}
+		return p.radical(p.arg(size), index, size)
+	case "text", "mathrm":
+		return p.text(p.raw(), "Times", "", size)
+	case ",":
+		return formulaSpace(0.17 * em)
+	case ":", ">":
+		return formulaSpace(0.22 * em)
+	case ";":
+		return formulaSpace(0.28 * em)
+	case " ":
+		return formulaSpace(0.25 * em)
+	case "!":
+		return formulaSpace(-0.17 * em)
+	case "quad":
+		return formulaSpace(em)
+	case "qquad":
+		return formulaSpace(2 * em)
+	case "{", "}", "\\", "%", "$", "#", "&":
+		return p.text(name, "Times", "", size)
+	}
+	if sym, ok := formulaSymbols[name]; ok {
+		return p.symbol(sym, size)
+	}
+	if formulaFunctions[name] {
+		return formulaHList([]formulaBox{p.text(name, "Times", "", size), formulaSpace(0.17 * em)})
+	}
+	return p.fail("unknown command \\" + name)
+}
+
+// formula lays out texStr using the current font size
+func (f *Fpdf) formula(texStr string) (b formulaBox) {
+	p := formulaParser{f: f, src: []rune(texStr)}
+	b = p.list(f.fontSizePt, false)
+	if p.err != nil {
+		f.SetErrorf("formula %q: %s", texStr, p.err)
+	}
+	return
+}
+
+// FormulaSize returns the dimensions of the formula specified by texStr when
+// printed with the current font size. The formula's width, its height above
+// the baseline and its depth below the baseline are returned in the unit of
+// measure specified in New(). See Formula() for the supported notation.
+func (f *Fpdf) FormulaSize(texStr string) (wd, ascent, descent float64) {
+	if f.err != nil {
+		return
+	}
+	b := f.formula(texStr)
+	if f.err != nil {
+		return 0, 0, 0
+	}
+	return b.wd, b.asc, b.dsc
+}
+
+// Formula prints a mathematical formula with its left edge at x and its
+// baseline at y. The size of the formula follows the current font size and it
+// is printed in the current text color.
+//
+// texStr is written in a subset of LaTeX math notation. Letters are printed
+// in italics and numbers upright. Superscripts (x^2, e^{i\pi}), subscripts
+// (a_{n+1}), fractions (\frac{a}{b}), square roots (\sqrt{x}), roots with
+// an index (\sqrt[3]{x}) and braced groups are supported, as are Greek
+// letters (\alpha, \Omega), common operators and relations (\times, \pm,
+// \le, \ne, \approx, \in, \to), the large operators \sum, \prod and \int,
+// function names (\sin, \log, \lim), upright text (\text{...}) and the
+// spacing commands \, \; \quad and \qquad. Other notation, such as
+// environments, \left and \right, accents and font commands, is not
+// supported. The Greek letters and operators are printed with the Symbol
+// core font. An error is set if the notation cannot be parsed.
+//
+// Use FormulaSize() to position display equations and WriteFormula() to
+// include a formula in flowing text.
+func (f *Fpdf) Formula(x, y float64, texStr string) {
+	if f.err != nil {
+		return
+	}
+	b := f.formula(texStr)
+	if f.err != nil {
+		return
+	}
+	familyStr, styleStr, ptSize := f.fontFamily, f.fontStyleStr(), f.fontSizePt
+	lineWd := f.lineWidth
+	dr, dg, db := f.GetDrawColor()
+	f.SetDrawColor(f.GetTextColor())
+	b.draw(x, y)
+	f.SetDrawColor(dr, dg, db)
+	f.SetLineWidth(lineWd)
+	if familyStr != "" {
+		f.SetFont(familyStr, styleStr, ptSize)
+	}
+}
+
+// WriteFormula prints a formula at the current position in flowing text, in
+// the manner of Write(). h indicates the line height in the unit of measure
+// specified in New(). If the formula does not fit on the remainder of the
+// line, it is moved to the next one. Upon return, the current position is
+// placed just after the formula. See Formula() for the supported notation.
+func (f *Fpdf) WriteFormula(h float64, texStr string) {
+	wd, _, _ := f.FormulaSize(texStr)
+	if f.err != nil {
+		return
+	}
+	if f.x+wd > f.w-f.rMargin && f.x > f.lMargin {
+		f.Ln(h)
+	}
+	if f.y+h > f.pageBreakTrigger && !f.inHeader && !f.inFooter && f.acceptPageBreakFor(PageBreakBlock, f.y, h) {
+		x := f.x
+		f.AddPageFormat(f.curOrientation, f.curPageSize)
+		if f.err != nil {
+			return
+		}
+		f.x = x
+	}
+	f.Formula(f.x, f.y+0.5*h+0.3*f.fontSize, texStr)
+	f.x += wd
+}
@@ -23,7 +23,10 @@ package gofpdf
 
 import (
 	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
 	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"image"
@@ -40,6 +43,7 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 var gl struct {
@@ -82,6 +86,7 @@ func fpdfNew(orientationStr, unitStr, sizeStr, fontDirStr string, size SizeType)
 	f.defPageBoxes = make(map[string]PageBox)
 	f.state = 0
 	f.fonts = make(map[string]fontDefType)
+	f.fontsSelected = make(map[string]bool)
 	f.fontFiles = make(map[string]fontFileType)
 	f.diffs = make([]string, 0, 8)
 	f.templates = make(map[string]Template)
@@ -111,6 +116,10 @@ func fpdfNew(orientationStr, unitStr, sizeStr, fontDirStr string, size SizeType)
 	f.setTextColor(0, 0, 0)
 	f.colorFlag = false
 	f.ws = 0
+	f.lineHeightFactor = 1.25
+	f.floatPrecision = -1
+	f.decimalSep = "."
+	f.captionStyle.NumberFmt = "Figure %d: "
 	f.fontpath = fontDirStr
 	// Core fonts
 	f.coreFonts = map[string]bool{
@@ -119,18 +128,15 @@ func fpdfNew(orientationStr, unitStr, sizeStr, fontDirStr string, size SizeType)
 		"times":        true,
 		"symbol":       true,
 		"zapfdingbats": true,
+		// The Symbol font, since "symbol" is an alias of "zapfdingbats"
+		"standardsymbol": true,
+		// Cyrillic and Greek metric packs for the Helvetica core font
+		"helvetica-1251": true,
+		"helvetica-1253": true,
 	}
 	// Scale factor
-	switch unitStr {
-	case "pt", "point":
-		f.k = 1.0
-	case "mm":
-		f.k = 72.0 / 25.4
-	case "cm":
-		f.k = 72.0 / 2.54
-	case "in", "inch":
-		f.k = 72.0
-	default:
+	var ok bool
+	if f.k, ok = unitScale(unitStr); !ok {
 		f.err = fmt.Errorf("incorrect unit %s", unitStr)
 		return
 	}
@@ -198,6 +204,8 @@ func fpdfNew(orientationStr, unitStr, sizeStr, fontDirStr string, size SizeType)
 	f.blendMap = make(map[string]int)
 	f.blendMode = "Normal"
 	f.alpha = 1
+	f.strokeAlpha = 1
+	f.fillAlpha = 1
 	f.gradientList = make([]gradientType, 0, 8)
 	f.gradientList = append(f.gradientList, gradientType{}) // gradientList[0] is unused
 	// Set default PDF version number
@@ -238,7 +246,8 @@ func NewCustom(init *InitType) (f *Fpdf) {
 // be found. An empty string is replaced with ".". This argument only needs to
 // reference an actual directory if a font other than one of the core
 // fonts is used. The core fonts are "courier", "helvetica" (also called
-// "arial"), "times", and "zapfdingbats" (also called "symbol").
+// "arial"), "times", "zapfdingbats" (also called "symbol") and
+// "standardsymbol", which is the Symbol font.
 func New(orientationStr, unitStr, sizeStr, fontDirStr string) (f *Fpdf) {
 	return fpdfNew(orientationStr, unitStr, sizeStr, fontDirStr, SizeType{0, 0})
 }
@@ -396,9 +405,15 @@ func (f *Fpdf) SetPageBox(t string, x, y, wd, ht float64) {
 }
 
 // SetPage sets the current page to that of a valid page in the PDF document.
-// pageNum is one-based. The SetPage() example demonstrates this method.
+// pageNum is one-based. The SetPage() example demonstrates this method. An
+// error is set if the page has already been written to the output of a
+// document created with NewStreaming().
 func (f *Fpdf) SetPage(pageNum int) {
 	if (pageNum > 0) && (pageNum < len(f.pages)) {
+		if f.pages[pageNum] == nil {
+			f.SetErrorf("page %d has been written to the output stream", pageNum)
+			return
+		}
 		f.page = pageNum
 	}
 }
@@ -477,6 +492,31 @@ func (f *Fpdf) SetFooterFuncLpi(fnc func(lastPage bool)) {
 	f.footerFnc = nil
 }
 
+// SetPageEventHandler sets the handler that is notified when each page is
+// started and ended. Unlike the header and footer functions, which are meant
+// to render content, the handler is intended for bookkeeping tasks such as
+// recording which page an item appears on or deciding whether a page needs a
+// watermark. The handler may nonetheless draw on the page. Pass nil to remove
+// a previously set handler. See PageEventHandler for details.
+func (f *Fpdf) SetPageEventHandler(handler PageEventHandler) {
+	f.pageEvents = handler
+}
+
+// pageEvent notifies the page event handler, if any, of the start or end of
+// the current page
+func (f *Fpdf) pageEvent(start bool) {
+	if f.pageEvents == nil || f.err != nil {
+		return
+	}
+	var size SizeType
+	size.Wd, size.Ht, _ = f.PageSize(f.page)
+	if start {
+		f.pageEvents.OnPageStart(f.page, size)
+	} else {
+		f.pageEvents.OnPageEnd(f.page, size)
+	}
+}
+
 // SetTopMargin defines the top margin. The method can be called before
 // creating the first page.
 func (f *Fpdf) SetTopMargin(margin float64) {
@@ -508,6 +548,47 @@ func (f *Fpdf) SetAutoPageBreak(auto bool, margin float64) {
 	f.pageBreakTrigger = f.h - margin
 }
 
+// WithAutoPageBreak calls fnc with the automatic page breaking mode and
+// bottom margin set as by SetAutoPageBreak() and afterwards restores the mode
+// and margin that were in effect beforehand, even if fnc panics. This is
+// useful for content that is placed in a particular region of the page, such
+// as a box anchored to the footer, which would otherwise trigger an unwanted
+// page break or require the page breaking settings to be saved and restored
+// by hand. If a page break occurs within fnc, the restored margin applies to
+// the new page.
+func (f *Fpdf) WithAutoPageBreak(auto bool, margin float64, fnc func()) {
+	if f.err != nil {
+		return
+	}
+	prevAuto, prevMargin := f.autoPageBreak, f.bMargin
+	f.SetAutoPageBreak(auto, margin)
+	defer f.SetAutoPageBreak(prevAuto, prevMargin)
+	fnc()
+}
+
+// SpaceLeft returns the vertical distance from the current position to the
+// limit at which an automatic page break is triggered, that is, the space that
+// remains on the current page above the bottom margin. The bottom margin set
+// with SetAutoPageBreak() is the space reserved for the footer. The value is
+// the same whether or not automatic page breaks are enabled, and is negative
+// if the current position is already below the limit.
+func (f *Fpdf) SpaceLeft() float64 {
+	return f.pageBreakTrigger - f.y
+}
+
+// WillFit returns true if content of height h, starting at the current
+// position, can be printed without an automatic page break, that is, if it
+// ends above the bottom margin of the current page or if no page break would
+// take place because automatic page breaks are disabled or the current
+// position is in the header or footer. This is the test that Cell(),
+// MultiCell(), Image() and similar methods use to decide whether to break the
+// page, so calling it beforehand allows a block of content, such as a table
+// row, to be moved to the next page as a whole. Use SpaceLeft() to manage
+// page breaks explicitly when automatic page breaks are disabled.
+func (f *Fpdf) WillFit(h float64) bool {
+	return !f.autoPageBreak || f.inHeader || f.inFooter || f.y+h <= f.pageBreakTrigger
+}
+
 // SetDisplayMode sets advisory display directives for the document viewer.
 // Pages can be displayed entirely on screen, occupy the full width of the
 // window, use real size, be scaled by a specific zooming factor or use viewer
@@ -575,6 +656,20 @@ func (f *Fpdf) SetProducer(producerStr string, isUTF8 bool) {
 	f.producer = producerStr
 }
 
+// ScrubMetadata removes the producer and the creation and modification dates
+// from the document information, so that a document does not reveal the
+// software or the time that generated it. This suits documents generated on
+// behalf of people whose privacy matters, and makes the output of the same
+// content identical from run to run. The title, subject, author, keywords and
+// creator are still written if they are set, and so is metadata set with
+// SetXmpMetadata(). Page-piece dictionaries (see SetPieceInfo()) require a
+// date of last modification, which is the one set with SetModificationDate()
+// or the time of generation. Since PDF/X documents (see SetPDFX()) require
+// the dates, scrubbing them sets an error.
+func (f *Fpdf) ScrubMetadata() {
+	f.scrubMetadata = true
+}
+
 // SetTitle defines the title of the document. isUTF8 indicates if the string
 // is encoded in ISO-8859-1 (false) or UTF-8 (true).
 func (f *Fpdf) SetTitle(titleStr string, isUTF8 bool) {
@@ -648,6 +743,31 @@ func (f *Fpdf) LTR() {
 	f.isRTL = false
 }
 
+// SetRTLMirroring specifies whether layout defaults are mirrored in
+// right-to-left mode, which they are unless mirroring is turned off with this
+// method. When mirroring is in effect, text printed by CellFormat(),
+// MultiCell() and the methods based on them without an explicit horizontal
+// alignment is aligned on the right, ListItem() places bullets on the right
+// of the text, and the columns of the tables of RenderDocument() are laid out
+// from right to left. Documents that already arrange their layout for
+// right-to-left text can turn mirroring off to keep the left-to-right
+// defaults.
+func (f *Fpdf) SetRTLMirroring(on bool) {
+	f.rtlNoMirror = !on
+}
+
+// GetRTLMirroring returns true if layout defaults are mirrored in
+// right-to-left mode. See SetRTLMirroring().
+func (f *Fpdf) GetRTLMirroring() bool {
+	return !f.rtlNoMirror
+}
+
+// rtlMirrored returns true if right-to-left mode is enabled and layout
+// defaults are mirrored
+func (f *Fpdf) rtlMirrored() bool {
+	return f.isRTL && !f.rtlNoMirror
+}
+
 // open begins a document
 func (f *Fpdf) open() {
 	f.state = 1
@@ -656,7 +776,9 @@ func (f *Fpdf) open() {
 // Close terminates the PDF document. It is not necessary to call this method
 // explicitly because Output(), OutputAndClose() and OutputFileAndClose() do it
 // automatically. If the document contains no page, AddPage() is called to
-// prevent the generation of an invalid document.
+// prevent the generation of an invalid document. A document created with
+// NewStreaming() must be closed with this method, which writes the rest of
+// the document to its output stream; Error() reports whether it succeeded.
 func (f *Fpdf) Close() {
 	if f.err == nil {
 		if f.clipNest > 0 {
@@ -677,6 +799,19 @@ func (f *Fpdf) Close() {
 			return
 		}
 	}
+	if f.indexPending {
+		f.putIndex()
+		if f.err != nil {
+			return
+		}
+	}
+	if f.toc != nil {
+		f.putTOC()
+		if f.err != nil {
+			return
+		}
+	}
+	f.putRunningHead()
 	// Page footer
 	f.inFooter = true
 	if f.footerFnc != nil {
@@ -685,9 +820,19 @@ func (f *Fpdf) Close() {
 		f.footerFncLpi(true)
 	}
 	f.inFooter = false
+	f.pageEvent(false)
 
 	// Close page
 	f.endpage()
+	if f.sepPreview != nil {
+		f.putSeparationPreviews()
+		if f.err != nil {
+			return
+		}
+	}
+	if f.visualIndex.pending {
+		f.putVisualIndex()
+	}
 	// Close document
 	f.enddoc()
 	return
@@ -741,16 +886,24 @@ func (f *Fpdf) AddPageFormat(orientationStr string, size SizeType) {
 	tc := f.color.text
 	cf := f.colorFlag
 
+	if f.columns != nil {
+		// The header and footer are printed with the margins of the page
+		f.lMargin, f.rMargin = f.columns.lMargin, f.columns.rMargin
+	}
 	if f.page > 0 {
+		f.putRunningHead()
 		f.inFooter = true
 		// Page footer avoid double call on footer.
-		if f.footerFnc != nil {
+		if f.tocDefersFooter() {
+			// Printed once the table of contents is in place
+		} else if f.footerFnc != nil {
 			f.footerFnc()
 
 		} else if f.footerFncLpi != nil {
 			f.footerFncLpi(false) // not last page.
 		}
 		f.inFooter = false
+		f.pageEvent(false)
 		// Close page
 		f.endpage()
 	}
@@ -768,6 +921,10 @@ func (f *Fpdf) AddPageFormat(orientationStr string, size SizeType) {
 	if len(f.dashArray) > 0 {
 		f.outputDashPattern()
 	}
+	// Set halftone and transfer function
+	if f.halftoneStr != "" || f.transferStr != "" {
+		f.putPrintState()
+	}
 	// 	Set font
 	if familyStr != "" {
 		f.SetFont(familyStr, style, fontsize)
@@ -788,9 +945,10 @@ func (f *Fpdf) AddPageFormat(orientationStr string, size SizeType) {
 	f.colorFlag = cf
 	// 	Page header
 	if f.headerFnc != nil {
+		inHeader := f.inHeader
 		f.inHeader = true
 		f.headerFnc()
-		f.inHeader = false
+		f.inHeader = inHeader
 		if f.headerHomeMode {
 			f.SetHomeXY()
 		}
@@ -816,8 +974,15 @@ func (f *Fpdf) AddPageFormat(orientationStr string, size SizeType) {
 		f.color.fill = fc
 		f.out(fc.str)
 	}
+	f.color.draw.none = dc.none
+	f.color.fill.none = fc.none
 	f.color.text = tc
 	f.colorFlag = cf
+	if f.columns != nil {
+		f.columns.bottom = f.y
+		f.columnsRestart()
+	}
+	f.pageEvent(true)
 	return
 }
 
@@ -888,6 +1053,7 @@ func (f *Fpdf) SetDrawColor(r, g, b int) {
 
 func (f *Fpdf) setDrawColor(r, g, b int) {
 	f.color.draw = rgbColorValue(r, g, b, "G", "RG")
+	f.rgbUsed = f.rgbUsed || !f.color.draw.gray
 	if f.page > 0 {
 		f.out(f.color.draw.str)
 	}
@@ -900,6 +1066,16 @@ func (f *Fpdf) GetDrawColor() (int, int, int) {
 	return f.color.draw.ir, f.color.draw.ig, f.color.draw.ib
 }
 
+// SetDrawNone turns off the stroking of lines and outlines, so that shapes
+// drawn with a style that includes "D", such as "FD", are only filled and
+// lines, outlines and cell borders are left out, until a draw color is set
+// again with SetDrawColor() or SetDrawSpotColor(). GetDrawColor() continues
+// to return the most recently set draw color. See SetFillNone() for the
+// equivalent for filling.
+func (f *Fpdf) SetDrawNone() {
+	f.color.draw.none = true
+}
+
 // SetFillColor defines the color used for all filling operations (filled
 // rectangles and cell backgrounds). It is expressed in RGB components (0
 // -255). The method can be called before the first page is created and the
@@ -910,7 +1086,9 @@ func (f *Fpdf) SetFillColor(r, g, b int) {
 
 func (f *Fpdf) setFillColor(r, g, b int) {
 	f.color.fill = rgbColorValue(r, g, b, "g", "rg")
+	f.rgbUsed = f.rgbUsed || !f.color.fill.gray
 	f.colorFlag = f.color.fill.str != f.color.text.str
+	f.textClrExplicit = false
 	if f.page > 0 {
 		f.out(f.color.fill.str)
 	}
@@ -923,6 +1101,17 @@ func (f *Fpdf) GetFillColor() (int, int, int) {
 	return f.color.fill.ir, f.color.fill.ig, f.color.fill.ib
 }
 
+// SetFillNone turns off the filling of shapes, so that shapes drawn with a
+// style that includes "F", such as "FD", are only outlined and the
+// backgrounds of cells are left out, until a fill color is set again with
+// SetFillColor() or SetFillSpotColor(). This allows a routine that draws a
+// shape with a fixed style string to be used for outlined and filled shapes
+// alike. GetFillColor() continues to return the most recently set fill
+// color.
+func (f *Fpdf) SetFillNone() {
+	f.color.fill.none = true
+}
+
 // SetTextColor defines the color used for text. It is expressed in RGB
 // components (0 - 255). The method can be called before the first page is
 // created. The value is retained from page to page.
@@ -932,7 +1121,9 @@ func (f *Fpdf) SetTextColor(r, g, b int) {
 
 func (f *Fpdf) setTextColor(r, g, b int) {
 	f.color.text = rgbColorValue(r, g, b, "g", "rg")
+	f.rgbUsed = f.rgbUsed || !f.color.text.gray
 	f.colorFlag = f.color.fill.str != f.color.text.str
+	f.textClrExplicit = true
 }
 
 // GetTextColor returns the most recently set text color as RGB components (0 -
@@ -959,36 +1150,153 @@ func (f *Fpdf) GetStringSymbolWidth(s string) int {
 		return 0
 	}
 	w := 0
+	if glyphs, ok := f.shapeCurrent(s); ok {
+		return shapedWidth(glyphs)
+	}
 	if f.isCurrentUTF8 {
-		// Use grapheme clusters for correct emoji handling
-		clusters := graphemeClusters(s)
-		for _, cluster := range clusters {
-			for _, r := range cluster {
-				f.ensureCIDForRune(int(r))
+		// Look up the font once rather than for every rune
+		fontKey := getFontKey(f.fontFamily, f.fontStyle)
+		cacheKey := ""
+		if len(s) <= textCacheMaxLen {
+			cacheKey = fontKey + "\x00" + s
+			if wd, ok := f.widthCache[cacheKey]; ok {
+				return wd
 			}
-			clusterWidth := graphemeClusterWidth(cluster, &f.currentFont)
-			if clusterWidth > 0 {
-				// Skip width 65535 (marks missing glyphs in some fonts)
-				if clusterWidth != 65535 {
-					w += clusterWidth
+		}
+		font, ok := f.fonts[fontKey]
+		ok = ok && !f.textAsPaths
+		if ok {
+			for _, r := range s {
+				if f.err != nil {
+					break
 				}
-			} else if f.currentFont.Desc.MissingWidth != 0 {
-				w += f.currentFont.Desc.MissingWidth
-			} else {
-				w += 500
+				f.ensureCIDInternal(&font, fontKey, int(r))
+			}
+		}
+		w = fontStringWidth(&f.currentFont, s)
+		if ok {
+			f.fonts[fontKey] = font
+			f.currentFont = font
+			// Cache only widths whose runes have been assigned CIDs
+			if cacheKey != "" && f.err == nil {
+				if f.widthCache == nil || len(f.widthCache) >= textCacheMaxEntries {
+					f.widthCache = make(map[string]int)
+				}
+				f.widthCache[cacheKey] = w
 			}
 		}
 	} else {
+		w = fontStringWidth(&f.currentFont, s)
+	}
+	return w
+}
+
+// fontStringWidth returns the length of s in glyph units when printed with
+// font, without shaping
+func fontStringWidth(font *fontDefType, s string) int {
+	w := 0
+	if font.Tp != "UTF8" {
 		for _, ch := range []byte(s) {
 			if ch == 0 {
 				break
 			}
-			w += f.currentFont.Cw[int(ch)]
+			w += font.Cw[int(ch)]
+		}
+		return w
+	}
+	// Use grapheme clusters for correct emoji handling
+	for _, cluster := range graphemeClusters(s) {
+		clusterWidth := graphemeClusterWidth(cluster, font)
+		if clusterWidth > 0 {
+			// Skip width 65535 (marks missing glyphs in some fonts)
+			if clusterWidth != 65535 {
+				w += clusterWidth
+			}
+		} else if font.Desc.MissingWidth != 0 {
+			w += font.Desc.MissingWidth
+		} else {
+			w += 500
 		}
 	}
 	return w
 }
 
+// MeasureText returns the length of a string in user units when printed with
+// the font specified by familyStr, styleStr and size. Unlike GetStringWidth,
+// the current font is left unchanged, so layout code can compare candidate
+// fonts and sizes without calling SetFont back and forth. See SetFont for
+// details about the arguments; an empty familyStr or a size of zero selects
+// the current family or size. The width is computed as GetStringWidth
+// computes it once the font is selected, except that text shaping and
+// kerning are not applied.
+//
+// Measuring leaves the document unchanged: a core font that has not been
+// selected is not added to the document, and a font that is not available,
+// for which SetFont would set an error, has a width of zero.
+func (f *Fpdf) MeasureText(familyStr, styleStr string, size float64, s string) float64 {
+	if f.err != nil {
+		return 0
+	}
+	familyStr = fontFamilyEscape(familyStr)
+	if familyStr == "" {
+		familyStr = f.fontFamily
+	} else {
+		familyStr = strings.ToLower(familyStr)
+	}
+	styleStr = strings.ToUpper(styleStr)
+	styleStr = strings.Replace(styleStr, "U", "", -1)
+	styleStr = strings.Replace(styleStr, "S", "", -1)
+	styleStr, ok := styleKey(styleStr)
+	if !ok {
+		return 0
+	}
+	if size == 0.0 {
+		size = f.fontSizePt
+	}
+	font, ok := f.measureFont(familyStr, styleStr)
+	if !ok {
+		return 0
+	}
+	return float64(fontStringWidth(&font, s)) * size / f.k / 1000
+}
+
+// measureFont returns the font identified by the lower case family and upper
+// case style as resolveFont() would select it, and false if it is not
+// available. The metrics of a core font that has not been selected are kept
+// apart from the fonts of the document, so that they do not become page
+// resources.
+func (f *Fpdf) measureFont(familyStr, styleStr string) (fontDefType, bool) {
+	if font, ok := f.fonts[familyStr+styleStr]; ok {
+		return font, true
+	}
+	switch familyStr {
+	case "arial":
+		familyStr = "helvetica"
+	case "symbol":
+		familyStr = "zapfdingbats"
+	}
+	fontKey := familyStr + styleStr
+	if font, ok := f.fonts[fontKey]; ok {
+		return font, true
+	}
+	if font, ok := f.measureFonts[fontKey]; ok {
+		return font, true
+	}
+	str, ok := embeddedFontList[fontKey]
+	if !ok || !f.coreFonts[familyStr] {
+		return fontDefType{}, false
+	}
+	var font fontDefType
+	if err := json.Unmarshal([]byte(str), &font); err != nil {
+		return fontDefType{}, false
+	}
+	if f.measureFonts == nil {
+		f.measureFonts = make(map[string]fontDefType)
+	}
+	f.measureFonts[fontKey] = font
+	return font, true
+}
+
 // SetLineWidth defines the line width. By default, the value equals 0.2 mm.
 // The method can be called before the first page is created. The value is
 // retained from page to page.
@@ -1088,7 +1396,7 @@ func (f *Fpdf) outputDashPattern() {
 // Line draws a line between points (x1, y1) and (x2, y2) using the current
 // draw color, line width and cap style.
 func (f *Fpdf) Line(x1, y1, x2, y2 float64) {
-	f.outf("%.2f %.2f m %.2f %.2f l S", x1*f.k, (f.h-y1)*f.k, x2*f.k, (f.h-y2)*f.k)
+	f.outf("%.2f %.2f m %.2f %.2f l %s", x1*f.k, (f.h-y1)*f.k, x2*f.k, (f.h-y2)*f.k, f.paintOp("D"))
 }
 
 // fillDrawOp corrects path painting operators
@@ -1115,6 +1423,34 @@ func fillDrawOp(styleStr string) (opStr string) {
 	return
 }
 
+// paintOp returns the path painting operator for styleStr, leaving out the
+// filling or stroking of the path if it has been turned off with
+// SetFillNone() or SetDrawNone()
+func (f *Fpdf) paintOp(styleStr string) (opStr string) {
+	opStr = fillDrawOp(styleStr)
+	if f.color.fill.none {
+		switch opStr {
+		case "f", "F", "f*":
+			opStr = "n"
+		case "B", "B*":
+			opStr = "S"
+		case "b", "b*":
+			opStr = "s"
+		}
+	}
+	if f.color.draw.none {
+		switch opStr {
+		case "S", "s":
+			opStr = "n"
+		case "B", "b":
+			opStr = "f"
+		case "B*", "b*":
+			opStr = "f*"
+		}
+	}
+	return
+}
+
 // Rect outputs a rectangle of width w and height h with the upper left corner
 // positioned at point (x, y).
 //
@@ -1124,7 +1460,7 @@ func fillDrawOp(styleStr string) (opStr string) {
 // draw color and line width centered on the rectangle's perimeter. Filling
 // uses the current fill color.
 func (f *Fpdf) Rect(x, y, w, h float64, styleStr string) {
-	f.outf("%.2f %.2f %.2f %.2f re %s", x*f.k, (f.h-y)*f.k, w*f.k, -h*f.k, fillDrawOp(styleStr))
+	f.outf("%.2f %.2f %.2f %.2f re %s", x*f.k, (f.h-y)*f.k, w*f.k, -h*f.k, f.paintOp(styleStr))
 }
 
 // RoundedRect outputs a rectangle of width w and height h with the upper left
@@ -1161,7 +1497,7 @@ func (f *Fpdf) RoundedRect(x, y, w, h, r float64, corners string, stylestr strin
 // RoundedRect() example.
 func (f *Fpdf) RoundedRectExt(x, y, w, h, rTL, rTR, rBR, rBL float64, stylestr string) {
 	f.roundedRectPath(x, y, w, h, rTL, rTR, rBR, rBL)
-	f.out(fillDrawOp(stylestr))
+	f.out(f.paintOp(stylestr))
 }
 
 // Circle draws a circle centered on point (x, y) with radius r.
@@ -1273,7 +1609,7 @@ func (f *Fpdf) curve(cx0, cy0, cx1, cy1, x, y float64) {
 func (f *Fpdf) Curve(x0, y0, cx, cy, x1, y1 float64, styleStr string) {
 	f.point(x0, y0)
 	f.outf("%.5f %.5f %.5f %.5f v %s", cx*f.k, (f.h-cy)*f.k, x1*f.k, (f.h-y1)*f.k,
-		fillDrawOp(styleStr))
+		f.paintOp(styleStr))
 }
 
 // CurveCubic draws a single-segment cubic Bézier curve. This routine performs
@@ -1282,7 +1618,7 @@ func (f *Fpdf) Curve(x0, y0, cx, cy, x1, y1 float64, styleStr string) {
 func (f *Fpdf) CurveCubic(x0, y0, cx0, cy0, x1, y1, cx1, cy1 float64, styleStr string) {
 	// f.point(x0, y0)
 	// f.outf("%.5f %.5f %.5f %.5f %.5f %.5f c %s", cx0*f.k, (f.h-cy0)*f.k,
-	// cx1*f.k, (f.h-cy1)*f.k, x1*f.k, (f.h-y1)*f.k, fillDrawOp(styleStr))
+	// cx1*f.k, (f.h-cy1)*f.k, x1*f.k, (f.h-y1)*f.k, f.paintOp(styleStr))
 	f.CurveBezierCubic(x0, y0, cx0, cy0, cx1, cy1, x1, y1, styleStr)
 }
 
@@ -1305,7 +1641,7 @@ func (f *Fpdf) CurveCubic(x0, y0, cx0, cy0, x1, y1, cx1, cy1 float64, styleStr s
 func (f *Fpdf) CurveBezierCubic(x0, y0, cx0, cy0, cx1, cy1, x1, y1 float64, styleStr string) {
 	f.point(x0, y0)
 	f.outf("%.5f %.5f %.5f %.5f %.5f %.5f c %s", cx0*f.k, (f.h-cy0)*f.k,
-		cx1*f.k, (f.h-cy1)*f.k, x1*f.k, (f.h-y1)*f.k, fillDrawOp(styleStr))
+		cx1*f.k, (f.h-cy1)*f.k, x1*f.k, (f.h-y1)*f.k, f.paintOp(styleStr))
 }
 
 // Arc draws an elliptical arc centered at point (x, y). rx and ry specify its
@@ -1362,18 +1698,93 @@ func (f *Fpdf) SetAlpha(alpha float64, blendModeStr string) {
 		f.err = fmt.Errorf("unrecognized blend mode \"%s\"", blendModeStr)
 		return
 	}
-	if alpha < 0.0 || alpha > 1.0 {
-		f.err = fmt.Errorf("alpha value (0.0 - 1.0) is out of range: %.3f", alpha)
+	if !f.alphaInRange(alpha) {
 		return
 	}
 	f.alpha = alpha
-	f.blendMode = blendModeStr
-	alphaStr := sprintf("%.3f", alpha)
-	keyStr := sprintf("%s %s", alphaStr, blendModeStr)
+	f.strokeAlpha = alpha
+	f.fillAlpha = alpha
+	f.blendMode = bl.modeStr
+	f.putAlpha()
+}
+
+// WithAlpha calls fnc with the alpha blending channel set as by SetAlpha()
+// and afterwards restores the opacities and blend mode that were in effect
+// beforehand, even if fnc panics. This avoids the need to reset normal
+// rendering by hand after drawing translucent content. fnc is not called if
+// alpha or blendModeStr is invalid.
+func (f *Fpdf) WithAlpha(alpha float64, blendModeStr string, fnc func()) {
+	if f.err != nil {
+		return
+	}
+	prevAlpha, prevStroke, prevFill, prevMode := f.alpha, f.strokeAlpha, f.fillAlpha, f.blendMode
+	f.SetAlpha(alpha, blendModeStr)
+	if f.err != nil {
+		return
+	}
+	defer func() {
+		f.alpha, f.strokeAlpha, f.fillAlpha, f.blendMode = prevAlpha, prevStroke, prevFill, prevMode
+		f.putAlpha()
+	}()
+	fnc()
+}
+
+// SetStrokeAlpha sets the opacity of lines, outlines and stroked text
+// separately from that of fills. alpha ranges from 0.0 (fully transparent) to
+// 1.0 (fully opaque). The current blend mode and fill opacity are unchanged.
+// This allows, for example, a translucent area to be drawn with a solid
+// outline. SetAlpha() sets both opacities.
+func (f *Fpdf) SetStrokeAlpha(alpha float64) {
+	if f.err != nil || !f.alphaInRange(alpha) {
+		return
+	}
+	f.strokeAlpha = alpha
+	f.putAlpha()
+}
+
+// SetFillAlpha sets the opacity of fills, text and images separately from
+// that of lines and outlines. alpha ranges from 0.0 (fully transparent) to
+// 1.0 (fully opaque). The current blend mode and stroke opacity are
+// unchanged. SetAlpha() sets both opacities.
+func (f *Fpdf) SetFillAlpha(alpha float64) {
+	if f.err != nil || !f.alphaInRange(alpha) {
+		return
+	}
+	f.fillAlpha = alpha
+	f.putAlpha()
+}
+
+// GetStrokeAlpha returns the stroke opacity set with SetStrokeAlpha() or
+// SetAlpha().
+func (f *Fpdf) GetStrokeAlpha() float64 {
+	return f.strokeAlpha
+}
+
+// GetFillAlpha returns the fill opacity set with SetFillAlpha() or
+// SetAlpha().
+func (f *Fpdf) GetFillAlpha() float64 {
+	return f.fillAlpha
+}
+
+// alphaInRange sets an error if alpha is not a valid opacity
+func (f *Fpdf) alphaInRange(alpha float64) bool {
+	if alpha < 0.0 || alpha > 1.0 {
+		f.err = fmt.Errorf("alpha value (0.0 - 1.0) is out of range: %.3f", alpha)
+		return false
+	}
+	return true
+}
+
+// putAlpha selects the graphics state of the current opacities and blend
+// mode, registering it if it is new
+func (f *Fpdf) putAlpha() {
+	strokeStr := sprintf("%.3f", f.strokeAlpha)
+	fillStr := sprintf("%.3f", f.fillAlpha)
+	keyStr := sprintf("%s %s %s", strokeStr, fillStr, f.blendMode)
 	pos, ok := f.blendMap[keyStr]
 	if !ok {
 		pos = len(f.blendList) // at least 1
-		f.blendList = append(f.blendList, blendModeType{alphaStr, alphaStr, blendModeStr, 0})
+		f.blendList = append(f.blendList, blendModeType{strokeStr, fillStr, f.blendMode, 0})
 		f.blendMap[keyStr] = pos
 	}
 	f.outf("/GS%d gs", pos)
@@ -1395,8 +1806,19 @@ func (f *Fpdf) gradient(tp, r1, g1, b1, r2, g2, b2 int, x1, y1, x2, y2, r float6
 	pos := len(f.gradientList)
 	clr1 := rgbColorValue(r1, g1, b1, "", "")
 	clr2 := rgbColorValue(r2, g2, b2, "", "")
-	f.gradientList = append(f.gradientList, gradientType{tp, clr1.str, clr2.str,
-		x1, y1, x2, y2, r, 0})
+	f.gradientList = append(f.gradientList, gradientType{tp, clr1, clr2,
+		x1, y1, x2, y2, r, nil, 0})
+	f.outf("/Sh%d sh", pos)
+}
+
+// gradientStops paints a gradient through the colors of stops, which are in
+// the order of their offsets, with the gradient vector or circles of
+// gradient(). Beyond the first and last offsets, the colors of the first and
+// last stops are extended; the offsets must not all be equal.
+func (f *Fpdf) gradientStops(tp int, stops []gradientStopType, x1, y1, x2, y2, r float64) {
+	pos := len(f.gradientList)
+	f.gradientList = append(f.gradientList, gradientType{tp, stops[0].clr, stops[len(stops)-1].clr,
+		x1, y1, x2, y2, r, stops, 0})
 	f.outf("/Sh%d sh", pos)
 }
 
@@ -1446,6 +1868,61 @@ func (f *Fpdf) RadialGradient(x, y, w, h float64, r1, g1, b1, r2, g2, b2 int, x1
 	f.gradientClipEnd()
 }
 
+// Color spaces accepted by SetGradientInterpolation()
+const (
+	// GradientInterpolationAuto interpolates in Lab if the document has an
+	// output intent with an ICC profile, and in device RGB otherwise
+	GradientInterpolationAuto = ""
+	// GradientInterpolationDevice interpolates the RGB components as they
+	// are passed to the gradient methods
+	GradientInterpolationDevice = "DeviceRGB"
+	// GradientInterpolationLab interpolates in the CIE L*a*b* color space
+	GradientInterpolationLab = "Lab"
+)
+
+// SetGradientInterpolation selects the color space in which the colors of
+// the gradients of the document, those of LinearGradient(), RadialGradient()
+// and of SVG images, are blended. Blending the device RGB components gives
+// ramps that darken or shift in hue between distant colors and whose
+// appearance depends on the output device. In Lab the colors, taken to be
+// sRGB colors, are blended in a device-independent color space designed to
+// be perceptually uniform, which gives smoother ramps that the viewer or
+// printer converts to the colors of the output device with its color
+// management. spaceStr is one of GradientInterpolationAuto, the default,
+// which selects Lab for a document with an ICC output profile set with
+// SetPDFX(), GradientInterpolationDevice and GradientInterpolationLab. The
+// setting applies to all gradients of the document, including those drawn
+// before it is made.
+func (f *Fpdf) SetGradientInterpolation(spaceStr string) {
+	if f.err != nil {
+		return
+	}
+	switch spaceStr {
+	case GradientInterpolationAuto, GradientInterpolationDevice, GradientInterpolationLab:
+		f.gradientSpace = spaceStr
+	default:
+		f.err = fmt.Errorf("unsupported gradient interpolation space %s", spaceStr)
+	}
+}
+
+// gradientLab returns true if gradients are interpolated in Lab
+func (f *Fpdf) gradientLab() bool {
+	if f.gradientSpace == GradientInterpolationAuto {
+		return len(f.pdfx.Intent.Profile) > 0
+	}
+	return f.gradientSpace == GradientInterpolationLab
+}
+
+// gradientColorStr returns the components of the color of a gradient, in
+// Lab if lab is true
+func gradientColorStr(clr colorType, lab bool) string {
+	if lab {
+		l, a, b := srgbLab(clr.r, clr.g, clr.b)
+		return sprintf("%.3f %.3f %.3f", l, a, b)
+	}
+	return sprintf("%.3f %.3f %.3f", clr.r, clr.g, clr.b)
+}
+
 // ClipRect begins a rectangular clipping operation. The rectangle is of width
 // w and height h. Its upper left corner is positioned at point (x, y). outline
 // is true to draw a border with the current draw color and line width centered
@@ -1470,7 +1947,12 @@ func (f *Fpdf) ClipRect(x, y, w, h float64, outline bool) {
 // restore unclipped operations.
 func (f *Fpdf) ClipText(x, y float64, txtStr string, outline bool) {
 	f.clipNest++
+	f.outPendingFont()
 	var txt2 string
+	if glyphs, ok := f.shapeCurrent(txtStr); ok {
+		f.outf("q BT %.5f %.5f Td %d Tr %s ET", x*f.k, (f.h-y)*f.k, intIf(outline, 5, 7), f.shapedText(glyphs))
+		return
+	}
 	if f.isCurrentUTF8 {
 		txt2 = f.encodeCIDString(txtStr)
 	} else {
@@ -1615,8 +2097,8 @@ func (f *Fpdf) ClipPolygon(points []PointType, outline bool) {
 }
 
 // ClipEnd ends a clipping operation that was started with a call to
-// ClipRect(), ClipRoundedRect(), ClipText(), ClipEllipse(), ClipCircle() or
-// ClipPolygon(). Clipping operations can be nested. The document cannot be
+// ClipRect(), ClipRoundedRect(), ClipText(), ClipEllipse(), ClipCircle(),
+// ClipPolygon() or ClipGlyphOutline(). Clipping operations can be nested. The document cannot be
 // successfully output while a clipping operation is active.
 //
 // The ClipText() example demonstrates this method.
@@ -1660,6 +2142,10 @@ func (f *Fpdf) AddFont(familyStr, styleStr, fileStr string) {
 // utility. It is not necessary to call this function for the core PDF fonts
 // (courier, helvetica, times, zapfdingbats).
 //
+// The font may have TrueType outlines (usually .ttf files) or PostScript
+// outlines (OpenType .otf files with a CFF table). The glyphs of the latter
+// are embedded as a CID-keyed CFF font program.
+//
 // The JSON definition file (and the font file itself when embedding) must be
 // present in the font directory. If it is not found, the error "Could not
 // include font definition file" is set.
@@ -1713,7 +2199,7 @@ func (f *Fpdf) addFont(familyStr, styleStr, fileStr string, isUTF8 bool) {
 		utf8File := newUTF8Font(&reader)
 		err = utf8File.parseFile()
 		if err != nil {
-			f.SetError(err)
+			f.SetErrorf("unable to use font file %s: %s", fileStr, err)
 			return
 		}
 
@@ -1737,7 +2223,7 @@ func (f *Fpdf) addFont(familyStr, styleStr, fileStr string, isUTF8 bool) {
 			Cw:        utf8File.CharWidths,
 			usedRunes: make(map[int]int),
 			runeToCID: make(map[int]int),
-			nextCID:   1,
+			nextCID:   cidSupplementaryFirst,
 			File:      fileStr,
 			utf8File:  utf8File,
 		}
@@ -1765,6 +2251,11 @@ func (f *Fpdf) addFont(familyStr, styleStr, fileStr string, isUTF8 bool) {
 		fileStr = path.Join(f.fontpath, fileStr)
 		file, err := os.Open(fileStr)
 		if err != nil {
+			// Fall back to a built-in metric pack, if one matches
+			if str, ok := embeddedFontList[getFontKey(familyStr, styleStr)]; ok {
+				f.AddFontFromReader(familyStr, styleStr, strings.NewReader(str))
+				return
+			}
 			f.err = err
 			return
 		}
@@ -1847,7 +2338,7 @@ func (f *Fpdf) addFontFromBytes(familyStr, styleStr string, jsonFileBytes, zFile
 
 		err := utf8File.parseFile()
 		if err != nil {
-			fmt.Printf("get metrics Error: %e\n", err)
+			f.SetErrorf("unable to use font %s: %s", strings.TrimSpace(familyStr+" "+styleStr), err)
 			return
 		}
 		desc := FontDescType{
@@ -1871,7 +2362,7 @@ func (f *Fpdf) addFontFromBytes(familyStr, styleStr string, jsonFileBytes, zFile
 			utf8File:  utf8File,
 			usedRunes: make(map[int]int),
 			runeToCID: make(map[int]int),
-			nextCID:   1,
+			nextCID:   cidSupplementaryFirst,
 		}
 		def.i, _ = generateFontID(def)
 		f.fonts[fontkey] = def
@@ -2010,7 +2501,10 @@ func (f *Fpdf) GetFontDesc(familyStr, styleStr string) FontDescType {
 //
 // The font can be either a standard one or a font added via the AddFont()
 // method or AddFontFromReader() method. Standard fonts use the Windows
-// encoding cp1252 (Western Europe).
+// encoding cp1252 (Western Europe). The built-in families "Helvetica-1251"
+// (Cyrillic) and "Helvetica-1253" (Greek) are regular-style Helvetica with the
+// cp1251 and cp1253 encodings respectively; use them together with
+// UnicodeTranslatorFromDescriptor("cp1251") or ("cp1253").
 //
 // The method can be called before the first page is created and the font is
 // kept from page to page. If you just wish to change the current font size, it
@@ -2022,12 +2516,18 @@ func (f *Fpdf) GetFontDesc(familyStr, styleStr string) FontDescType {
 // familyStr specifies the font family. It can be either a name defined by
 // AddFont(), AddFontFromReader() or one of the standard families (case
 // insensitive): "Courier" for fixed-width, "Helvetica" or "Arial" for sans
-// serif, "Times" for serif, "Symbol" or "ZapfDingbats" for symbolic.
+// serif, "Times" for serif, "ZapfDingbats" (also called "Symbol") or
+// "StandardSymbol", which is the Symbol font, for symbolic.
 //
 // styleStr can be "B" (bold), "I" (italic), "U" (underscore), "S" (strike-out)
 // or any combination. The default value (specified with an empty string) is
 // regular. Bold and italic styles do not apply to Symbol and ZapfDingbats.
 //
+// If the family has not been added, or has not been added in the requested
+// style, or the style contains other flags, the error set is a *FontError
+// that lists similar fonts that are available. GetRegisteredFonts() lists
+// all of them.
+//
 // size is the font size measured in points. The default value is the current
 // size. If no size has been specified since the beginning of the document, the
 // value taken is 12.
@@ -2039,7 +2539,6 @@ func (f *Fpdf) SetFont(familyStr, styleStr string, size float64) {
 	}
 	// dbg("SetFont")
 	familyStr = fontFamilyEscape(familyStr)
-	var ok bool
 	if familyStr == "" {
 		familyStr = f.fontFamily
 	} else {
@@ -2054,28 +2553,52 @@ func (f *Fpdf) SetFont(familyStr, styleStr string, size float64) {
 	if f.strikeout {
 		styleStr = strings.Replace(styleStr, "S", "", -1)
 	}
-	if styleStr == "IB" {
-		styleStr = "BI"
+	if styleStr = f.fontStyleKey(familyStr, styleStr); f.err != nil {
+		return
 	}
 	if size == 0.0 {
 		size = f.fontSizePt
 	}
 
 	// Test if font is already loaded
-	fontKey := familyStr + styleStr
-	_, ok = f.fonts[fontKey]
+	fontKey, familyStr, styleStr := f.resolveFont(familyStr, styleStr)
+	if f.err != nil {
+		return
+	}
+	// Select it
+	f.fontFamily = familyStr
+	f.fontStyle = styleStr
+	f.fontSizePt = size
+	f.fontSize = size / f.k
+	f.currentFont = f.fonts[fontKey]
+	if f.currentFont.Tp == "UTF8" {
+		f.isCurrentUTF8 = true
+	} else {
+		f.isCurrentUTF8 = false
+	}
+	f.outFont()
+	return
+}
+
+// resolveFont returns the key of the font identified by the lower case family
+// and upper case style, loading the definition of a core font on first use.
+// The family and style that are actually selected are returned as well.
+func (f *Fpdf) resolveFont(familyStr, styleStr string) (fontKey, family, style string) {
+	fontKey = familyStr + styleStr
+	_, ok := f.fonts[fontKey]
 	if !ok {
 		// Test if one of the core fonts
-		if familyStr == "arial" {
+		switch familyStr {
+		case "arial":
 			familyStr = "helvetica"
+		case "symbol":
+			familyStr = "zapfdingbats"
 		}
 		_, ok = f.coreFonts[familyStr]
 		if ok {
-			if familyStr == "symbol" {
-				familyStr = "zapfdingbats"
-			}
-			if familyStr == "zapfdingbats" {
-				styleStr = ""
+			if _, ok = embeddedFontList[familyStr+styleStr]; !ok {
+				f.fontError(familyStr, styleStr, "the font has no bold or italic style")
+				return
 			}
 			fontKey = familyStr + styleStr
 			_, ok = f.fonts[fontKey]
@@ -2084,30 +2607,18 @@ func (f *Fpdf) SetFont(familyStr, styleStr string, size float64) {
 				if f.err == nil {
 					f.AddFontFromReader(familyStr, styleStr, rdr)
 				}
-				if f.err != nil {
-					return
-				}
 			}
 		} else {
-			f.err = fmt.Errorf("undefined font: %s %s", familyStr, styleStr)
-			return
+			reasonStr := "the family has not been added"
+			for _, style := range coreFontStyles {
+				if _, ok = f.fonts[familyStr+style]; ok {
+					reasonStr = "the style has not been added for the family"
+				}
+			}
+			f.fontError(familyStr, styleStr, reasonStr)
 		}
 	}
-	// Select it
-	f.fontFamily = familyStr
-	f.fontStyle = styleStr
-	f.fontSizePt = size
-	f.fontSize = size / f.k
-	f.currentFont = f.fonts[fontKey]
-	if f.currentFont.Tp == "UTF8" {
-		f.isCurrentUTF8 = true
-	} else {
-		f.isCurrentUTF8 = false
-	}
-	if f.page > 0 {
-		f.outf("BT /F%s %.2f Tf ET", f.currentFont.i, f.fontSizePt)
-	}
-	return
+	return fontKey, familyStr, styleStr
 }
 
 // SetFontStyle sets the style of the current font. See also SetFont()
@@ -2120,7 +2631,35 @@ func (f *Fpdf) SetFontStyle(styleStr string) {
 func (f *Fpdf) SetFontSize(size float64) {
 	f.fontSizePt = size
 	f.fontSize = size / f.k
+	f.outFont()
+}
+
+// fontOmitted returns true if font is a UTF-8 font that is neither selected
+// in the content of a page nor used to show text, and is therefore left out
+// of the document
+func (f *Fpdf) fontOmitted(font fontDefType) bool {
+	return font.Tp == "UTF8" && len(font.usedRunes)+len(font.cidGlyphs) == 0 && !f.fontsSelected[font.i]
+}
+
+// outFont selects the current font in the content of the current page. While
+// text in a UTF-8 font is drawn as paths, the selection is deferred until
+// text is shown in the font, so that a font in which no text is shown can be
+// left out of the document.
+func (f *Fpdf) outFont() {
 	if f.page > 0 {
+		f.fontPending = true
+		if !f.isCurrentUTF8 || !f.textAsPaths {
+			f.outPendingFont()
+		}
+	}
+}
+
+// outPendingFont selects the current font if its selection has been deferred
+// by outFont(). It is called before text is shown in the current font.
+func (f *Fpdf) outPendingFont() {
+	if f.fontPending {
+		f.fontPending = false
+		f.fontsSelected[f.currentFont.i] = true
 		f.outf("BT /F%s %.2f Tf ET", f.currentFont.i, f.fontSizePt)
 	}
 }
@@ -2130,9 +2669,7 @@ func (f *Fpdf) SetFontSize(size float64) {
 func (f *Fpdf) SetFontUnitSize(size float64) {
 	f.fontSizePt = size * f.k
 	f.fontSize = size
-	if f.page > 0 {
-		f.outf("BT /F%s %.2f Tf ET", f.currentFont.i, f.fontSizePt)
-	}
+	f.outFont()
 }
 
 // GetFontSize returns the size of the current font in points followed by the
@@ -2142,6 +2679,58 @@ func (f *Fpdf) GetFontSize() (ptSize, unitSize float64) {
 	return f.fontSizePt, f.fontSize
 }
 
+// SetLineHeight sets the height of lines of text in the unit of measure
+// specified in New(). The line height is used where a negative height is
+// passed to CellFormat(), MultiCell(), Write() and the methods based on
+// them, and by default by tables (see NewTable()), frames (see PourText())
+// and the HTML renderers (see HTMLNew() and HTMLBasicNew()), so that the
+// leading of a document can be changed in one place rather than with the
+// height of every call. Zero, the default, selects a line height in
+// proportion to the size of the font in effect; see SetLineHeightFactor().
+func (f *Fpdf) SetLineHeight(ht float64) {
+	f.lineHeight = ht
+}
+
+// SetLineHeightFactor sets the height of lines of text as a multiple of the
+// font size, 1.25 by default. It applies where no absolute line height has
+// been set with SetLineHeight(), which describes the use of the line height.
+func (f *Fpdf) SetLineHeightFactor(factor float64) {
+	if factor <= 0 {
+		f.SetErrorf("invalid line height factor %.2f", factor)
+		return
+	}
+	f.lineHeightFactor = factor
+}
+
+// GetLineHeight returns the height of lines of text in the current font in
+// the unit of measure specified in New(), as set with SetLineHeight() or
+// SetLineHeightFactor().
+func (f *Fpdf) GetLineHeight() float64 {
+	return f.lineHt(f.fontSize)
+}
+
+// lineHt returns the height of lines of text whose font size is fontHt in
+// user units
+func (f *Fpdf) lineHt(fontHt float64) float64 {
+	if f.lineHeight > 0 {
+		return f.lineHeight
+	}
+	return f.lineHeightFactor * fontHt
+}
+
+// fontStyleStr returns the style of the current font, including underlining
+// and strike-out, in the form accepted by SetFont().
+func (f *Fpdf) fontStyleStr() string {
+	styleStr := f.fontStyle
+	if f.underline {
+		styleStr += "U"
+	}
+	if f.strikeout {
+		styleStr += "S"
+	}
+	return styleStr
+}
+
 // AddLink creates a new internal link and returns its identifier. An internal
 // link is a clickable area which directs to another place within the document.
 // The identifier can then be passed to Cell(), Write(), Image() or Link(). The
@@ -2170,7 +2759,7 @@ func (f *Fpdf) newLink(x, y, w, h float64, link int, linkStr string) {
 	// f.pageLinks[f.page] = linkList
 	// }
 	f.pageLinks[f.page] = append(f.pageLinks[f.page],
-		linkType{x * f.k, f.hPt - y*f.k, w * f.k, h * f.k, link, linkStr})
+		linkType{x: x * f.k, y: f.hPt - y*f.k, wd: w * f.k, ht: h * f.k, link: link, linkStr: linkStr})
 }
 
 // Link puts a link on a rectangular area of the page. Text or image links are
@@ -2198,12 +2787,27 @@ func (f *Fpdf) Bookmark(txtStr string, level int, y float64) {
 	if y == -1 {
 		y = f.y
 	}
+	f.MarkSection(txtStr, level)
+	str := txtStr
 	if f.isCurrentUTF8 {
 		txtStr = utf8toutf16(txtStr)
 	}
-	f.outlines = append(f.outlines, outlineType{text: txtStr, level: level, y: y, p: f.PageNo(), prev: -1, last: -1, next: -1, first: -1})
+	f.outlines = append(f.outlines, outlineType{text: txtStr, str: str, level: level, y: y, p: f.PageNo(), prev: -1, last: -1, next: -1, first: -1})
 }
 
+// CIDs assigned to runes outside the Basic Multilingual Plane. They are taken
+// from the range of UTF-16 surrogates, which are not characters and
+// therefore never used as CIDs of their own.
+const (
+	cidSupplementaryFirst = 0xD800
+	cidSupplementaryLast  = 0xDFFF
+)
+
+// ensureCIDInternal returns the CID with which rune r is encoded in the
+// specified font, assigning it if r has not been used before. A rune of the
+// Basic Multilingual Plane is its own CID, so that the encoding of text does
+// not depend on what other text is printed in the font. Other runes are
+// assigned CIDs from the surrogate range in order of first use.
 func (f *Fpdf) ensureCIDInternal(font *fontDefType, fontKey string, r int) int {
 	if font.runeToCID == nil {
 		font.runeToCID = make(map[int]int)
@@ -2211,18 +2815,26 @@ func (f *Fpdf) ensureCIDInternal(font *fontDefType, fontKey string, r int) int {
 	if font.usedRunes == nil {
 		font.usedRunes = make(map[int]int)
 	}
-	if font.nextCID == 0 {
-		font.nextCID = 1
+	if font.nextCID < cidSupplementaryFirst {
+		font.nextCID = cidSupplementaryFirst
 	}
 	if cid, ok := font.runeToCID[r]; ok {
 		return cid
 	}
-	if font.nextCID > 0xFFFF {
-		f.err = fmt.Errorf("CID limit exceeded for font %s", fontKey)
+	cid := r
+	if r <= 0 || (r >= cidSupplementaryFirst && r <= cidSupplementaryLast) {
+		// Not a character; encode as .notdef
 		return 0
 	}
-	cid := font.nextCID
-	font.nextCID++
+	if r > 0xFFFF {
+		if font.nextCID > cidSupplementaryLast {
+			f.err = fmt.Errorf("CID limit exceeded for font %s: more than %d characters outside the Basic Multilingual Plane",
+				fontKey, cidSupplementaryLast-cidSupplementaryFirst+1)
+			return 0
+		}
+		cid = font.nextCID
+		font.nextCID++
+	}
 	font.runeToCID[r] = cid
 	font.usedRunes[cid] = r
 	return cid
@@ -2243,11 +2855,30 @@ func (f *Fpdf) ensureCIDForRune(r int) int {
 	return cid
 }
 
+// Limits of the caches of encoded text and text widths: the length in bytes
+// of the longest string that is cached, and the number of entries held at
+// once
+const (
+	textCacheMaxLen     = 64
+	textCacheMaxEntries = 4096
+)
+
+// encodeCIDString returns txt escaped for a PDF string and, if the current
+// font is a UTF-8 font, converted to two-byte CIDs. Encodings of short strings
+// are cached, since documents such as large tables print the same cell content
+// many times.
 func (f *Fpdf) encodeCIDString(txt string) string {
 	if !f.isCurrentUTF8 {
 		return f.escape(txt)
 	}
 	fontKey := getFontKey(f.fontFamily, f.fontStyle)
+	cacheKey := ""
+	if len(txt) <= textCacheMaxLen {
+		cacheKey = fontKey + "\x00" + txt
+		if str, ok := f.textCache[cacheKey]; ok {
+			return str
+		}
+	}
 	font, ok := f.fonts[fontKey]
 	if !ok {
 		return ""
@@ -2262,7 +2893,22 @@ func (f *Fpdf) encodeCIDString(txt string) string {
 	}
 	f.fonts[fontKey] = font
 	f.currentFont = font
-	return f.escape(string(b))
+	str := f.escape(string(b))
+	if cacheKey != "" && f.err == nil {
+		f.cacheText(cacheKey, str)
+	}
+	return str
+}
+
+// cacheText stores the encoding str of a short text string under key, which
+// combines the font key and the text. Since the CID of a rune never changes
+// once it has been assigned, an encoding remains valid for the life of the
+// document; the cache is emptied when it is full rather than tracking use.
+func (f *Fpdf) cacheText(key, str string) {
+	if f.textCache == nil || len(f.textCache) >= textCacheMaxEntries {
+		f.textCache = make(map[string]string)
+	}
+	f.textCache[key] = str
 }
 
 // Text prints a character string. The origin (x, y) is on the left of the
@@ -2271,21 +2917,37 @@ func (f *Fpdf) encodeCIDString(txt string) string {
 // or Write() which are the standard methods to print text.
 func (f *Fpdf) Text(x, y float64, txtStr string) {
 	var txt2 string
+	var glyphs []shapedGlyph
+	shaped := false
+	txtStr = f.debugCharsText(txtStr, false)
 	if f.isCurrentUTF8 {
 		if f.isRTL {
 			txtStr = reverseText(txtStr)
 			x -= f.GetStringWidth(txtStr)
 		}
-		txt2 = f.encodeCIDString(txtStr)
+		if glyphs, shaped = f.shapeCurrent(txtStr); !shaped && !f.textAsPaths {
+			txt2 = f.encodeCIDString(txtStr)
+		}
 	} else {
 		txt2 = f.escape(txtStr)
 	}
-	s := sprintf("BT %.2f %.2f Td (%s) Tj ET", x*f.k, (f.h-y)*f.k, txt2)
+	var s string
+	if shaped {
+		f.outPendingFont()
+		s = sprintf("BT %.2f %.2f Td %s ET", x*f.k, (f.h-y)*f.k, f.shapedText(glyphs))
+	} else if f.textAsPaths && f.isCurrentUTF8 {
+		var b fmtBuffer
+		f.textPath(&b, x, y, txtStr, 0)
+		s = b.String()
+	} else {
+		f.outPendingFont()
+		s = sprintf("BT %.2f %.2f Td (%s) Tj ET", x*f.k, (f.h-y)*f.k, txt2)
+	}
 	if f.underline && txtStr != "" {
-		s += " " + f.dounderline(x, y, txtStr)
+		s += " " + f.dounderline(x, y, txtStr, f.ws)
 	}
 	if f.strikeout && txtStr != "" {
-		s += " " + f.dostrikeout(x, y, txtStr)
+		s += " " + f.dostrikeout(x, y, txtStr, f.ws)
 	}
 	if f.colorFlag {
 		s = sprintf("q %s %s Q", f.color.text.str, s)
@@ -2326,7 +2988,8 @@ func (f *Fpdf) SetTextRenderingMode(mode int) {
 // called by the application.
 //
 // See the example for SetLeftMargin() to see how this function can be used to
-// manage multiple columns.
+// manage multiple columns. SetAcceptPageBreakContextFunc() provides the
+// function with a description of the content that triggers the break.
 func (f *Fpdf) SetAcceptPageBreakFunc(fnc func() bool) {
 	f.acceptPageBreak = fnc
 }
@@ -2345,7 +3008,8 @@ func (f *Fpdf) SetAcceptPageBreakFunc(fnc func() bool) {
 //
 // w and h specify the width and height of the cell. If w is 0, the cell
 // extends up to the right margin. Specifying 0 for h will result in no output,
-// but the current position will be advanced by w.
+// but the current position will be advanced by w. A negative h selects the
+// line height set with SetLineHeight().
 //
 // txtStr specifies the text to display.
 //
@@ -2360,9 +3024,11 @@ func (f *Fpdf) SetAcceptPageBreakFunc(fnc func() bool) {
 //
 // alignStr specifies how the text is to be positioned within the cell.
 // Horizontal alignment is controlled by including "L", "C" or "R" (left,
-// center, right) in alignStr. Vertical alignment is controlled by including
-// "T", "M", "B" or "A" (top, middle, bottom, baseline) in alignStr. The default
-// alignment is left middle.
+// center, right) in alignStr, or "D" to align the text on its decimal
+// separator as set with SetDecimalTab(). Vertical alignment is controlled by
+// including "T", "M", "B" or "A" (top, middle, bottom, baseline) in alignStr.
+// The default alignment is left middle, or right middle in right-to-left
+// mode unless mirroring has been turned off with SetRTLMirroring().
 //
 // fill is true to paint the cell background or false to leave it transparent.
 //
@@ -2382,9 +3048,14 @@ func (f *Fpdf) CellFormat(w, h float64, txtStr, borderStr string, ln int,
 		return
 	}
 
+	if h < 0 {
+		h = f.GetLineHeight()
+	}
 	borderStr = strings.ToUpper(borderStr)
+	txtStr = f.debugCharsText(txtStr, false)
 	k := f.k
-	if f.y+h > f.pageBreakTrigger && !f.inHeader && !f.inFooter && f.acceptPageBreak() {
+	reserve := f.contReserve()
+	if f.y+h+reserve > f.pageBreakTrigger && !f.inHeader && !f.inFooter && f.acceptPageBreakFor(f.cellBreakKindStr(), f.y, h+reserve) {
 		// Automatic page break
 		x := f.x
 		ws := f.ws
@@ -2393,19 +3064,32 @@ func (f *Fpdf) CellFormat(w, h float64, txtStr, borderStr string, ln int,
 			f.ws = 0
 			f.out("0 Tw")
 		}
+		f.putContinuation(w, true)
 		f.AddPageFormat(f.curOrientation, f.curPageSize)
 		if f.err != nil {
 			return
 		}
 		f.x = x
+		f.putContinuation(w, false)
+		f.x = x
 		if ws > 0 {
 			f.ws = ws
 			f.outf("%.3f Tw", ws*k)
 		}
 	}
+	f.layoutGuideMark()
 	if w == 0 {
 		w = f.w - f.rMargin - f.x
 	}
+	if f.overflowReport {
+		f.overflowCell(w, h, txtStr)
+	}
+	if f.color.fill.none {
+		fill = false
+	}
+	if f.color.draw.none {
+		borderStr = ""
+	}
 	var s fmtBuffer
 	if fill || borderStr == "1" {
 		var op string
@@ -2422,7 +3106,7 @@ func (f *Fpdf) CellFormat(w, h float64, txtStr, borderStr string, ln int,
 			op = "S"
 		}
 		/// dbg("(CellFormat) f.x %.2f f.k %.2f", f.x, f.k)
-		s.printf("%.2f %.2f %.2f %.2f re %s ", f.x*k, (f.h-f.y)*k, w*k, -h*k, op)
+		s.WriteString(f.contentf("%.2f %.2f %.2f %.2f re %s ", f.x*k, (f.h-f.y)*k, w*k, -h*k, op))
 	}
 	if len(borderStr) > 0 && borderStr != "1" {
 		// fmt.Printf("border is '%s', no fill\n", borderStr)
@@ -2433,26 +3117,30 @@ func (f *Fpdf) CellFormat(w, h float64, txtStr, borderStr string, ln int,
 		right := (x + w) * k
 		bottom := (f.h - (y + h)) * k
 		if strings.Contains(borderStr, "L") {
-			s.printf("%.2f %.2f m %.2f %.2f l S ", left, top, left, bottom)
+			s.WriteString(f.contentf("%.2f %.2f m %.2f %.2f l S ", left, top, left, bottom))
 		}
 		if strings.Contains(borderStr, "T") {
-			s.printf("%.2f %.2f m %.2f %.2f l S ", left, top, right, top)
+			s.WriteString(f.contentf("%.2f %.2f m %.2f %.2f l S ", left, top, right, top))
 		}
 		if strings.Contains(borderStr, "R") {
-			s.printf("%.2f %.2f m %.2f %.2f l S ", right, top, right, bottom)
+			s.WriteString(f.contentf("%.2f %.2f m %.2f %.2f l S ", right, top, right, bottom))
 		}
 		if strings.Contains(borderStr, "B") {
-			s.printf("%.2f %.2f m %.2f %.2f l S ", left, bottom, right, bottom)
+			s.WriteString(f.contentf("%.2f %.2f m %.2f %.2f l S ", left, bottom, right, bottom))
 		}
 	}
 	if len(txtStr) > 0 {
 		var dx, dy float64
 		// Horizontal alignment
 		switch {
+		case strings.Contains(alignStr, "D"):
+			dx = f.decimalDx(w, txtStr)
 		case strings.Contains(alignStr, "R"):
 			dx = w - f.cMargin - f.GetStringWidth(txtStr)
 		case strings.Contains(alignStr, "C"):
 			dx = (w - f.GetStringWidth(txtStr)) / 2
+		case f.rtlMirrored() && !strings.Contains(alignStr, "L") && alignStr != "J":
+			dx = w - f.cMargin - f.GetStringWidth(txtStr)
 		default:
 			dx = f.cMargin
 		}
@@ -2476,37 +3164,58 @@ func (f *Fpdf) CellFormat(w, h float64, txtStr, borderStr string, ln int,
 		default:
 			dy = 0
 		}
-		if f.colorFlag {
-			s.printf("q %s ", f.color.text.str)
+		textClrStr, textClrSet := f.cellTextColor(fill)
+		if textClrSet {
+			s.printf("q %s ", textClrStr)
 		}
-		//If multibyte, Tw has no effect - do word spacing using an adjustment before each space
-		if (f.ws != 0 || alignStr == "J") && f.isCurrentUTF8 { // && f.ws != 0
+		// Word spacing as printed, which underlines and strikeouts span
+		wordSpace := f.ws
+		// If multibyte, Tw has no effect - do word spacing using an adjustment
+		// after each space
+		if f.isCurrentUTF8 && (f.ws != 0 || alignStr == "J") {
+			wordSpace = f.wordSpacingUTF8(w, txtStr, alignStr)
+		}
+		if f.textAsPaths && f.isCurrentUTF8 {
 			if f.isRTL {
 				txtStr = reverseText(txtStr)
 			}
-			wmax := int(math.Ceil((w - 2*f.cMargin) * 1000 / f.fontSize))
-			space := f.encodeCIDString(" ")
-			strSize := f.GetStringSymbolWidth(txtStr)
-			s.printf("BT 0 Tw %.2f %.2f Td [", (f.x+dx)*k, (f.h-(f.y+.5*h+.3*f.fontSize))*k)
+			f.textPath(&s, f.x+dx, f.y+dy+.5*h+.3*f.fontSize, txtStr, wordSpace)
+		} else if (f.ws != 0 || alignStr == "J") && f.isCurrentUTF8 {
+			f.outPendingFont()
+			if f.isRTL {
+				txtStr = reverseText(txtStr)
+			}
+			space := f.encodeCIDString(" ")
+			shift := wordSpace * 1000 / f.fontSize
+			s.WriteString(f.contentf("BT 0 Tw %.2f %.2f Td [", (f.x+dx)*k, (f.h-(f.y+dy+.5*h+.3*f.fontSize))*k))
 			t := strings.Split(txtStr, " ")
-			shift := float64((wmax - strSize)) / float64(len(t)-1)
 			numt := len(t)
 			for i := 0; i < numt; i++ {
 				tx := t[i]
-				tx = "(" + f.encodeCIDString(tx) + ")"
+				if glyphs, ok := f.shapeCurrent(tx); ok {
+					// Shaped words are shown with operators of their own
+					tx = "] TJ " + f.shapedText(glyphs) + " ["
+				} else if tx != "" {
+					tx = "(" + f.encodeCIDString(tx) + ")"
+				}
 				s.printf("%s ", tx)
 				if (i + 1) < numt {
-					s.printf("%.3f(%s) ", -shift, space)
+					s.printf("(%s) %.3f ", space, -shift)
 				}
 			}
 			s.printf("] TJ ET")
 		} else {
+			f.outPendingFont()
 			var txt2 string
+			var glyphs []shapedGlyph
+			shaped := false
 			if f.isCurrentUTF8 {
 				if f.isRTL {
 					txtStr = reverseText(txtStr)
 				}
-				txt2 = f.encodeCIDString(txtStr)
+				if glyphs, shaped = f.shapeCurrent(txtStr); !shaped {
+					txt2 = f.encodeCIDString(txtStr)
+				}
 			} else {
 
 				txt2 = strings.Replace(txtStr, "\\", "\\\\", -1)
@@ -2515,17 +3224,21 @@ func (f *Fpdf) CellFormat(w, h float64, txtStr, borderStr string, ln int,
 			}
 			bt := (f.x + dx) * k
 			td := (f.h - (f.y + dy + .5*h + .3*f.fontSize)) * k
-			s.printf("BT %.2f %.2f Td (%s)Tj ET", bt, td, txt2)
+			if shaped {
+				s.WriteString(f.contentf("BT %.2f %.2f Td ", bt, td) + f.shapedText(glyphs) + " ET")
+			} else {
+				s.WriteString(f.contentf("BT %.2f %.2f Td (%s)Tj ET", bt, td, txt2))
+			}
 			//BT %.2F %.2F Td (%s) Tj ET',(f.x+dx)*k,(f.h-(f.y+.5*h+.3*f.FontSize))*k,txt2);
 		}
 
 		if f.underline {
-			s.printf(" %s", f.dounderline(f.x+dx, f.y+dy+.5*h+.3*f.fontSize, txtStr))
+			s.printf(" %s", f.dounderline(f.x+dx, f.y+dy+.5*h+.3*f.fontSize, txtStr, wordSpace))
 		}
 		if f.strikeout {
-			s.printf(" %s", f.dostrikeout(f.x+dx, f.y+dy+.5*h+.3*f.fontSize, txtStr))
+			s.printf(" %s", f.dostrikeout(f.x+dx, f.y+dy+.5*h+.3*f.fontSize, txtStr, wordSpace))
 		}
-		if f.colorFlag {
+		if textClrSet {
 			s.printf(" Q")
 		}
 		if link > 0 || len(linkStr) > 0 {
@@ -2637,10 +3350,16 @@ func (f *Fpdf) SplitLines(txt []byte, w float64) [][]byte {
 // The current position after calling MultiCell() is the beginning of the next
 // line, equivalent to calling CellFormat with ln equal to 1.
 //
+// If a language has been set with SetLineBreakLanguage(), lines are broken
+// where SplitText() breaks them, with words hyphenated and segmented using
+// the dictionaries of the language.
+//
 // w is the width of the cells. A value of zero indicates cells that reach to
 // the right margin.
 //
-// h indicates the line height of each cell in the unit of measure specified in New().
+// h indicates the line height of each cell in the unit of measure specified
+// in New(); a negative value selects the line height set with
+// SetLineHeight().
 //
 // Note: this method has a known bug that treats UTF-8 fonts differently than
 // non-UTF-8 fonts. With UTF-8 fonts, all trailing newlines in txtStr are
@@ -2655,18 +3374,157 @@ func (f *Fpdf) MultiCell(w, h float64, txtStr, borderStr, alignStr string, fill
 		return
 	}
 	// dbg("MultiCell")
+	prevKind := f.cellBreakKind
+	f.cellBreakKind = PageBreakMultiCell
+	defer func() { f.cellBreakKind = prevKind }()
+	if h < 0 {
+		h = f.GetLineHeight()
+	}
 	if alignStr == "" {
 		alignStr = "J"
 	}
-	cw := f.currentFont.Cw
+	txtStr = f.debugCharsText(txtStr, true)
 	if w == 0 {
 		w = f.w - f.rMargin - f.x
 	}
+	var b, b2 string
+	b = "0"
+	if len(borderStr) > 0 {
+		if borderStr == "1" {
+			borderStr = "LTRB"
+			b = "LRT"
+			b2 = "LR"
+		} else {
+			b2 = ""
+			if strings.Contains(borderStr, "L") {
+				b2 += "L"
+			}
+			if strings.Contains(borderStr, "R") {
+				b2 += "R"
+			}
+			if strings.Contains(borderStr, "T") {
+				b = b2 + "T"
+			} else {
+				b = b2
+			}
+		}
+	}
+	if f.floatsApply(f.x, f.x+w) {
+		// Lines beside floated images are shortened
+		var printed bool
+		txtStr, printed = f.multiCellFloats(f.x, w, h, strings.Replace(txtStr, "\r", "", -1),
+			b, b2, strings.Contains(borderStr, "B"), alignStr, fill)
+		if txtStr == "" || f.err != nil {
+			f.x = f.lMargin
+			return
+		}
+		if printed {
+			b = b2
+		}
+	}
 	wmax := int(math.Ceil((w - 2*f.cMargin) * 1000 / f.fontSize))
 	s := strings.Replace(txtStr, "\r", "", -1)
+	inMultiCell := f.inMultiCell
+	f.inMultiCell = true
+	defer func() { f.inMultiCell = inMultiCell }()
+
+	// dbg("[%s]\n", s)
+	if f.lineBreakLang != "" {
+		// Lines are broken as by SplitText(), which hyphenates and segments
+		// words with the dictionaries of the language
+		breaks := f.SplitTextBreaks(s, w)
+		if len(breaks) == 0 {
+			breaks = []LineBreakType{{}}
+		}
+		x := f.x
+		for k, br := range breaks {
+			if k == 1 {
+				b = b2
+			}
+			if k == len(breaks)-1 && strings.Contains(borderStr, "B") {
+				b += "B"
+			}
+			f.putLine(x, w, h, s, br, b, alignStr, fill)
+		}
+		f.x = f.lMargin
+		return
+	}
+	for k, ln := range f.multiCellLines(s, w) {
+		if k == 1 && len(borderStr) > 0 {
+			b = b2
+		}
+		lineAlignStr := alignStr
+		if ln.wordEnd {
+			// Lines of UTF-8 fonts are justified by CellFormat(), since
+			// Tw does not apply to their two-byte spaces
+			if alignStr == "J" && !f.isCurrentUTF8 {
+				if ln.ns > 1 {
+					f.ws = float64((wmax-ln.ls)/1000) * f.fontSize / float64(ln.ns-1)
+				} else {
+					f.ws = 0
+				}
+				f.outf("%.3f Tw", f.ws*f.k)
+			}
+		} else if f.ws > 0 {
+			f.ws = 0
+			f.out("0 Tw")
+		}
+		if ln.last && len(borderStr) > 0 && strings.Contains(borderStr, "B") {
+			b += "B"
+		}
+		if f.isCurrentUTF8 && alignStr == "J" && (ln.newline || ln.last) {
+			// The last line of a paragraph is not justified
+			switch {
+			case f.isRTL:
+				lineAlignStr = "R"
+			case ln.newline:
+				lineAlignStr = "L"
+			default:
+				lineAlignStr = ""
+			}
+		}
+		f.contMore = !ln.last
+		f.CellFormat(w, h, ln.str, b, 2, lineAlignStr, fill, 0, "")
+	}
+	f.x = f.lMargin
+}
+
+// multiCellLineCount returns the number of lines in which MultiCell() prints
+// txtStr in cells of width w with the current font, disregarding floats, so
+// that the height of the text can be found before it is printed
+func (f *Fpdf) multiCellLineCount(txtStr string, w float64) int {
+	s := strings.Replace(f.debugCharsText(txtStr, true), "\r", "", -1)
+	if f.lineBreakLang != "" {
+		if n := len(f.SplitTextBreaks(s, w)); n > 0 {
+			return n
+		}
+		return 1
+	}
+	return len(f.multiCellLines(s, w))
+}
+
+// multiCellLineType is a line of text as wrapped by MultiCell()
+type multiCellLineType struct {
+	str     string
+	newline bool // the line ends with an explicit line break
+	last    bool // the line ends the text
+	wordEnd bool // the line is broken automatically at a space
+	// Width of the line up to the space at which it is broken, in thousandths
+	// of the font size, and number of spaces up to and including that space,
+	// which are used to justify the line
+	ls, ns int
+}
+
+// multiCellLines returns the lines in which MultiCell() prints s, which must
+// not contain carriage returns, in cells of width w with the current font.
+// The last line is returned even if it is empty, so there is at least one.
+func (f *Fpdf) multiCellLines(s string, w float64) (lines []multiCellLineType) {
+	cw := f.currentFont.Cw
+	wmax := int(math.Ceil((w - 2*f.cMargin) * 1000 / f.fontSize))
 
 	// For UTF-8 fonts, use grapheme clusters; otherwise use byte-based processing
 	var clusters []string
+	var offsets, widths []int
 	var nb int
 	if f.isCurrentUTF8 {
 		clusters = graphemeClusters(s)
@@ -2676,6 +3534,21 @@ func (f *Fpdf) MultiCell(w, h float64, txtStr, borderStr, alignStr string, fill
 			nb--
 		}
 		clusters = clusters[0:nb]
+		// Byte offset and width of each cluster, so that lines are sliced
+		// from s and widths are not recalculated after backtracking
+		offsets = make([]int, nb+1)
+		widths = make([]int, nb)
+		for k, cluster := range clusters {
+			offsets[k+1] = offsets[k] + len(cluster)
+			for _, r := range cluster {
+				width, ok := cw[int(r)]
+				if !ok || width == 0 {
+					widths[k] += f.currentFont.Desc.MissingWidth
+				} else if width != 65535 {
+					widths[k] += width
+				}
+			}
+		}
 	} else {
 		nb = len(s)
 		bytes2 := []byte(s)
@@ -2691,36 +3564,19 @@ func (f *Fpdf) MultiCell(w, h float64, txtStr, borderStr, alignStr string, fill
 		s = s[0:nb]
 	}
 
-	// dbg("[%s]\n", s)
-	var b, b2 string
-	b = "0"
-	if len(borderStr) > 0 {
-		if borderStr == "1" {
-			borderStr = "LTRB"
-			b = "LRT"
-			b2 = "LR"
-		} else {
-			b2 = ""
-			if strings.Contains(borderStr, "L") {
-				b2 += "L"
-			}
-			if strings.Contains(borderStr, "R") {
-				b2 += "R"
-			}
-			if strings.Contains(borderStr, "T") {
-				b = b2 + "T"
-			} else {
-				b = b2
-			}
-		}
-	}
 	sep := -1
 	i := 0
 	j := 0
 	l := 0
 	ls := 0
 	ns := 0
-	nl := 1
+	// lineStr returns the text from the start of the line to cluster end
+	lineStr := func(end int) string {
+		if f.isCurrentUTF8 {
+			return s[offsets[j]:offsets[end]]
+		}
+		return s[j:end]
+	}
 	for i < nb {
 		// Get next character/cluster
 		var c rune
@@ -2730,22 +3586,12 @@ func (f *Fpdf) MultiCell(w, h float64, txtStr, borderStr, alignStr string, fill
 		if f.isCurrentUTF8 {
 			cluster = clusters[i]
 			// Check if cluster is a single rune for backward compatibility checks
-			runes := []rune(cluster)
-			if len(runes) == 1 {
-				c = runes[0]
-			} else {
+			var size int
+			c, size = utf8.DecodeRuneInString(cluster)
+			if size != len(cluster) {
 				c = 0 // Multi-rune cluster, not a simple character
 			}
-
-			// Calculate cluster width
-			for _, r := range cluster {
-				width, ok := cw[int(r)]
-				if !ok || width == 0 {
-					clusterWidth += f.currentFont.Desc.MissingWidth
-				} else if width != 65535 {
-					clusterWidth += width
-				}
-			}
+			clusterWidth = widths[i]
 		} else {
 			c = rune(s[i])
 			width, ok := cw[int(c)]
@@ -2758,38 +3604,12 @@ func (f *Fpdf) MultiCell(w, h float64, txtStr, borderStr, alignStr string, fill
 
 		if (f.isCurrentUTF8 && cluster == "\n") || (!f.isCurrentUTF8 && c == '\n') {
 			// Explicit line break
-			if f.ws > 0 {
-				f.ws = 0
-				f.out("0 Tw")
-			}
-
-			if f.isCurrentUTF8 {
-				newAlignStr := alignStr
-				if newAlignStr == "J" {
-					if f.isRTL {
-						newAlignStr = "R"
-					} else {
-						newAlignStr = "L"
-					}
-				}
-				// Join clusters into string
-				lineStr := ""
-				for k := j; k < i; k++ {
-					lineStr += clusters[k]
-				}
-				f.CellFormat(w, h, lineStr, b, 2, newAlignStr, fill, 0, "")
-			} else {
-				f.CellFormat(w, h, s[j:i], b, 2, alignStr, fill, 0, "")
-			}
+			lines = append(lines, multiCellLineType{str: lineStr(i), newline: true})
 			i++
 			sep = -1
 			j = i
 			l = 0
 			ns = 0
-			nl++
-			if len(borderStr) > 0 && nl == 2 {
-				b = b2
-			}
 			continue
 		}
 
@@ -2820,88 +3640,51 @@ func (f *Fpdf) MultiCell(w, h float64, txtStr, borderStr, alignStr string, fill
 				if i == j {
 					i++
 				}
-				if f.ws > 0 {
-					f.ws = 0
-					f.out("0 Tw")
-				}
-				if f.isCurrentUTF8 {
-					lineStr := ""
-					for k := j; k < i; k++ {
-						lineStr += clusters[k]
-					}
-					f.CellFormat(w, h, lineStr, b, 2, alignStr, fill, 0, "")
-				} else {
-					f.CellFormat(w, h, s[j:i], b, 2, alignStr, fill, 0, "")
-				}
+				lines = append(lines, multiCellLineType{str: lineStr(i)})
 			} else {
-				if alignStr == "J" {
-					if ns > 1 {
-						f.ws = float64((wmax-ls)/1000) * f.fontSize / float64(ns-1)
-					} else {
-						f.ws = 0
-					}
-					f.outf("%.3f Tw", f.ws*f.k)
-				}
-				if f.isCurrentUTF8 {
-					lineStr := ""
-					for k := j; k < sep; k++ {
-						lineStr += clusters[k]
-					}
-					f.CellFormat(w, h, lineStr, b, 2, alignStr, fill, 0, "")
-				} else {
-					f.CellFormat(w, h, s[j:sep], b, 2, alignStr, fill, 0, "")
-				}
+				lines = append(lines, multiCellLineType{str: lineStr(sep), wordEnd: true, ls: ls, ns: ns})
 				i = sep + 1
 			}
 			sep = -1
 			j = i
 			l = 0
 			ns = 0
-			nl++
-			if len(borderStr) > 0 && nl == 2 {
-				b = b2
-			}
 		} else {
 			i++
 		}
 	}
 	// Last chunk
-	if f.ws > 0 {
-		f.ws = 0
-		f.out("0 Tw")
-	}
-	if len(borderStr) > 0 && strings.Contains(borderStr, "B") {
-		b += "B"
-	}
-	if f.isCurrentUTF8 {
-		if alignStr == "J" {
-			if f.isRTL {
-				alignStr = "R"
-			} else {
-				alignStr = ""
-			}
-		}
-		lineStr := ""
-		for k := j; k < i; k++ {
-			lineStr += clusters[k]
-		}
-		f.CellFormat(w, h, lineStr, b, 2, alignStr, fill, 0, "")
-	} else {
-		f.CellFormat(w, h, s[j:i], b, 2, alignStr, fill, 0, "")
-	}
-	f.x = f.lMargin
+	return append(lines, multiCellLineType{str: lineStr(i), last: true})
 }
 
 // write outputs text in flowing mode
 func (f *Fpdf) write(h float64, txtStr string, link int, linkStr string) {
 	// dbg("Write")
+	prevKind := f.cellBreakKind
+	f.cellBreakKind = PageBreakWrite
+	defer func() { f.cellBreakKind = prevKind }()
+	if h < 0 {
+		h = f.GetLineHeight()
+	}
 	cw := f.currentFont.Cw
-	w := f.w - f.rMargin - f.x
+	lx, rx := f.floatSpan(f.lMargin, f.w-f.rMargin, h)
+	if lx > f.lMargin && f.x < lx {
+		f.x = lx
+	}
+	w := rx - f.x
 	wmax := (w - 2*f.cMargin) * 1000 / f.fontSize
-	s := strings.Replace(txtStr, "\r", "", -1)
+	s := strings.Replace(f.debugCharsText(txtStr, true), "\r", "", -1)
+	// nextLine begins a new line, beside any floated images
+	nextLine := func() {
+		lx, rx = f.floatSpan(f.lMargin, f.w-f.rMargin, h)
+		f.x = lx
+		w = rx - lx
+		wmax = (w - 2*f.cMargin) * 1000 / f.fontSize
+	}
 
 	// Use grapheme clusters for UTF-8 fonts
 	var clusters []string
+	var offsets []int
 	var nb int
 	if f.isCurrentUTF8 {
 		clusters = graphemeClusters(s)
@@ -2910,6 +3693,11 @@ func (f *Fpdf) write(h float64, txtStr string, link int, linkStr string) {
 			f.x += f.GetStringWidth(s)
 			return
 		}
+		// Byte offset of each cluster, so that lines are sliced from s
+		offsets = make([]int, nb+1)
+		for k, cluster := range clusters {
+			offsets[k+1] = offsets[k] + len(cluster)
+		}
 	} else {
 		nb = len(s)
 	}
@@ -2928,10 +3716,9 @@ func (f *Fpdf) write(h float64, txtStr string, link int, linkStr string) {
 		if f.isCurrentUTF8 {
 			cluster = clusters[i]
 			// Check if cluster is a single rune for backward compatibility
-			runes := []rune(cluster)
-			if len(runes) == 1 {
-				c = runes[0]
-			} else {
+			var size int
+			c, size = utf8.DecodeRuneInString(cluster)
+			if size != len(cluster) {
 				c = 0 // Multi-rune cluster
 			}
 
@@ -2947,10 +3734,7 @@ func (f *Fpdf) write(h float64, txtStr string, link int, linkStr string) {
 		if (f.isCurrentUTF8 && cluster == "\n") || (!f.isCurrentUTF8 && c == '\n') {
 			// Explicit line break
 			if f.isCurrentUTF8 {
-				lineStr := ""
-				for k := j; k < i; k++ {
-					lineStr += clusters[k]
-				}
+				lineStr := s[offsets[j]:offsets[i]]
 				f.CellFormat(w, h, lineStr, "", 2, "", false, link, linkStr)
 			} else {
 				f.CellFormat(w, h, s[j:i], "", 2, "", false, link, linkStr)
@@ -2959,11 +3743,7 @@ func (f *Fpdf) write(h float64, txtStr string, link int, linkStr string) {
 			sep = -1
 			j = i
 			l = 0.0
-			if nl == 1 {
-				f.x = f.lMargin
-				w = f.w - f.rMargin - f.x
-				wmax = (w - 2*f.cMargin) * 1000 / f.fontSize
-			}
+			nextLine()
 			nl++
 			continue
 		}
@@ -2978,12 +3758,10 @@ func (f *Fpdf) write(h float64, txtStr string, link int, linkStr string) {
 		if l > wmax {
 			// Automatic line break
 			if sep == -1 {
-				if f.x > f.lMargin {
+				if f.x > lx {
 					// Move to next line
-					f.x = f.lMargin
 					f.y += h
-					w = f.w - f.rMargin - f.x
-					wmax = (w - 2*f.cMargin) * 1000 / f.fontSize
+					nextLine()
 					i++
 					nl++
 					continue
@@ -2992,20 +3770,14 @@ func (f *Fpdf) write(h float64, txtStr string, link int, linkStr string) {
 					i++
 				}
 				if f.isCurrentUTF8 {
-					lineStr := ""
-					for k := j; k < i; k++ {
-						lineStr += clusters[k]
-					}
+					lineStr := s[offsets[j]:offsets[i]]
 					f.CellFormat(w, h, lineStr, "", 2, "", false, link, linkStr)
 				} else {
 					f.CellFormat(w, h, s[j:i], "", 2, "", false, link, linkStr)
 				}
 			} else {
 				if f.isCurrentUTF8 {
-					lineStr := ""
-					for k := j; k < sep; k++ {
-						lineStr += clusters[k]
-					}
+					lineStr := s[offsets[j]:offsets[sep]]
 					f.CellFormat(w, h, lineStr, "", 2, "", false, link, linkStr)
 				} else {
 					f.CellFormat(w, h, s[j:sep], "", 2, "", false, link, linkStr)
@@ -3015,11 +3787,7 @@ func (f *Fpdf) write(h float64, txtStr string, link int, linkStr string) {
 			sep = -1
 			j = i
 			l = 0.0
-			if nl == 1 {
-				f.x = f.lMargin
-				w = f.w - f.rMargin - f.x
-				wmax = (w - 2*f.cMargin) * 1000 / f.fontSize
-			}
+			nextLine()
 			nl++
 		} else {
 			i++
@@ -3028,10 +3796,7 @@ func (f *Fpdf) write(h float64, txtStr string, link int, linkStr string) {
 	// Last chunk
 	if i != j {
 		if f.isCurrentUTF8 {
-			lineStr := ""
-			for k := j; k < i; k++ {
-				lineStr += clusters[k]
-			}
+			lineStr := s[offsets[j]:offsets[i]]
 			f.CellFormat(l/1000*f.fontSize, h, lineStr, "", 0, "", false, link, linkStr)
 		} else {
 			f.CellFormat(l/1000*f.fontSize, h, s[j:], "", 0, "", false, link, linkStr)
@@ -3046,7 +3811,8 @@ func (f *Fpdf) write(h float64, txtStr string, link int, linkStr string) {
 //
 // It is possible to put a link on the text.
 //
-// h indicates the line height in the unit of measure specified in New().
+// h indicates the line height in the unit of measure specified in New(); a
+// negative value selects the line height set with SetLineHeight().
 func (f *Fpdf) Write(h float64, txtStr string) {
 	f.write(h, txtStr, 0, "")
 }
@@ -3155,7 +3921,9 @@ func (f *Fpdf) ImageTypeFromMime(mimeStr string) (tp string) {
 	return
 }
 
-func (f *Fpdf) imageOut(info *ImageInfoType, x, y, w, h float64, allowNegativeX, flow bool, link int, linkStr string) {
+// imageSize returns the size on the page of an image that is printed with
+// the width and height arguments w and h, as described in ImageOptions()
+func (f *Fpdf) imageSize(info *ImageInfoType, w, h float64) (float64, float64) {
 	// Automatic width and height calculation if needed
 	if w == 0 && h == 0 {
 		// Put image at 96 dpi
@@ -3184,9 +3952,28 @@ func (f *Fpdf) imageOut(info *ImageInfoType, x, y, w, h float64, allowNegativeX,
 	if h == 0 {
 		h = w * info.h / info.w
 	}
+	return w, h
+}
+
+func (f *Fpdf) imageOut(info *ImageInfoType, x, y, w, h float64, options ImageOptions, flow bool, link int, linkStr string) {
+	w, h = f.imageSize(info, w, h)
+	allowNegativeX := options.AllowNegativePosition
 	// Flowing mode
 	if flow {
-		if f.y+h > f.pageBreakTrigger && !f.inHeader && !f.inFooter && f.acceptPageBreak() {
+		brk := f.y+h > f.pageBreakTrigger && !f.inHeader && !f.inFooter
+		if brk && options.FlowBreak == ImageFlowScale {
+			if s := (f.pageBreakTrigger - f.y) / h; s > 0 && s >= options.MinScale {
+				w, h, brk = w*s, h*s, false
+			}
+		}
+		if brk && options.FlowBreak == ImageFlowSplit {
+			if !allowNegativeX && x < 0 {
+				x = f.x
+			}
+			f.imageOutSplit(info, x, w, h, options, link, linkStr)
+			return
+		}
+		if brk && f.acceptPageBreakFor(PageBreakImage, f.y, h) {
 			// Automatic page break
 			x2 := f.x
 			f.AddPageFormat(f.curOrientation, f.curPageSize)
@@ -3203,12 +3990,18 @@ func (f *Fpdf) imageOut(info *ImageInfoType, x, y, w, h float64, allowNegativeX,
 			x = f.x
 		}
 	}
+	if over := y + h - f.pageBreakTrigger; f.overflowReport && over > 0.001 {
+		f.overflow(OverflowMargin, PageBreakImage, x, y, over, "")
+	}
 	// dbg("h %.2f", h)
 	// q 85.04 0 0 NaN 28.35 NaN cm /I2 Do Q
+	f.altTextBegin(options.AltText)
 	f.outf("q %.5f 0 0 %.5f %.5f %.5f cm /I%s Do Q", w*f.k, h*f.k, x*f.k, (f.h-(y+h))*f.k, info.i)
+	f.altTextEnd(options.AltText)
 	if link > 0 || len(linkStr) > 0 {
 		f.newLink(x, y, w, h, link, linkStr)
 	}
+	f.RecordElement(options.Element, ElementImage, x, y, w, h)
 }
 
 // Image puts a JPEG, PNG or GIF image in the current page.
@@ -3263,11 +4056,20 @@ func (f *Fpdf) ImageOptions(imageNameStr string, x, y, w, h float64, flow bool,
 	if f.err != nil {
 		return
 	}
-	info := f.RegisterImageOptions(imageNameStr, options)
+	var info *ImageInfoType
+	if options.MaxDPI > 0 {
+		info, w, h = f.registerImageMaxDPI(imageNameStr, w, h, options)
+	} else {
+		info = f.RegisterImageOptions(imageNameStr, options)
+	}
 	if f.err != nil {
 		return
 	}
-	f.imageOut(info, x, y, w, h, options.AllowNegativePosition, flow, link, linkStr)
+	if options.Caption != "" {
+		f.imageOutCaptioned(info, x, y, w, h, options, flow, link, linkStr)
+		return
+	}
+	f.imageOut(info, x, y, w, h, options, flow, link, linkStr)
 	return
 }
 
@@ -3298,11 +4100,67 @@ func (f *Fpdf) RegisterImageReader(imgName, tp string, r io.Reader) (info *Image
 //
 // AllowNegativePosition can be set to true in order to prevent the default
 // coercion of negative x values to the current x position.
+//
+// FlowBreak selects what happens when an image placed in flowing mode does
+// not fit in the space that remains above the bottom margin of the page.
+// ImageFlowBreak, the default, moves the image to the next page.
+// ImageFlowScale reduces the image, maintaining its aspect ratio, so that it
+// fits on the current page; if MinScale is positive and the image would be
+// reduced to less than MinScale times its size, it is moved to the next page
+// instead. ImageFlowSplit prints the part of the image that fits on the
+// current page and continues it on the following pages, which is useful for
+// images taller than a page. In each case, page breaks are subject to the
+// function set with SetAcceptPageBreakFunc() or
+// SetAcceptPageBreakContextFunc().
+//
+// AltText is a textual description of the image for readers that cannot see
+// it, such as screen readers and text extraction tools. It is attached to the
+// image's marked content.
+//
+// Caption, if not empty, is printed with the image by ImageOptions() in the
+// style set with SetImageCaptionStyle(), preceded by the number of the image
+// among captioned images if the style calls for it.
+//
+// JPEGQuality, if positive, re-encodes the image as JPEG of that quality,
+// from 1 to 100, when it is registered, provided this makes it smaller. An
+// alpha channel is kept as a soft mask. CMYK images are left as they are.
+//
+// MaxDPI, if positive, limits the resolution of an image placed by
+// ImageOptions() to that many pixels per inch at the size it is printed. An
+// image of a higher resolution, such as a photo taken with a phone, is
+// downsampled and embedded as JPEG of quality JPEGQuality, or 75 if
+// JPEGQuality is not positive. An image placed at several sizes is embedded
+// once for each size that requires a distinct resolution, unless it has been
+// registered or placed at full resolution before, in which case that copy is
+// reused. MaxDPI applies to image files only; images registered from a
+// reader are used as registered.
+//
+// Element, if not empty, records the rectangle that the image occupies on
+// each page under that name as an element of kind ElementImage, so that its
+// position is listed by ElementManifest().
 type ImageOptions struct {
 	ImageType             string
 	ReadDpi               bool
 	AllowNegativePosition bool
-}
+	FlowBreak             int
+	MinScale              float64
+	AltText               string
+	Caption               string
+	JPEGQuality           int
+	MaxDPI                float64
+	Element               string
+}
+
+// Handling of images that do not fit on the page in flowing mode; see
+// ImageOptions
+const (
+	// ImageFlowBreak moves the image to the next page
+	ImageFlowBreak = iota
+	// ImageFlowScale reduces the image to fit on the current page
+	ImageFlowScale
+	// ImageFlowSplit divides the image across pages
+	ImageFlowSplit
+)
 
 // RegisterImageOptionsReader registers an image, reading it from Reader r, adding it
 // to the PDF file but not adding it to the page. Use Image() with the same
@@ -3325,30 +4183,57 @@ func (f *Fpdf) RegisterImageOptionsReader(imgName string, options ImageOptions,
 		f.err = fmt.Errorf("image type should be specified if reading from custom reader")
 		return
 	}
-	options.ImageType = strings.ToLower(options.ImageType)
-	if options.ImageType == "jpeg" {
-		options.ImageType = "jpg"
+	var data []byte
+	if options.JPEGQuality > 0 {
+		if data, f.err = ioutil.ReadAll(r); f.err != nil {
+			return
+		}
+		r = bytes.NewReader(data)
+	}
+	info = f.parseImage(options.ImageType, options.ReadDpi, r)
+	if f.err != nil {
+		return
+	}
+	if options.JPEGQuality > 0 {
+		if f.recompressImage(info, data, options.JPEGQuality); f.err != nil {
+			return
+		}
+	}
+	f.registerImageInfo(imgName, info)
+
+	return
+}
+
+// parseImage extracts info from the image of type tp read from r
+func (f *Fpdf) parseImage(tp string, readDpi bool, r io.Reader) (info *ImageInfoType) {
+	tp = strings.ToLower(tp)
+	if tp == "jpeg" {
+		tp = "jpg"
 	}
-	switch options.ImageType {
+	switch tp {
 	case "jpg":
 		info = f.parsejpg(r)
 	case "png":
-		info = f.parsepng(r, options.ReadDpi)
+		info = f.parsepng(r, readDpi)
 	case "gif":
 		info = f.parsegif(r)
 	default:
-		f.err = fmt.Errorf("unsupported image type: %s", options.ImageType)
-	}
-	if f.err != nil {
-		return
+		f.err = fmt.Errorf("unsupported image type: %s", tp)
 	}
+	return
+}
 
-	if info.i, f.err = generateImageID(info); f.err != nil {
-		return
+// imageFileType returns tp, or if it is empty, the type of the image file
+// fileStr as indicated by its extension
+func imageFileType(fileStr, tp string) (string, error) {
+	if tp != "" {
+		return tp, nil
 	}
-	f.images[imgName] = info
-
-	return
+	pos := strings.LastIndex(fileStr, ".")
+	if pos < 0 {
+		return "", fmt.Errorf("image file has no extension and no type was specified: %s", fileStr)
+	}
+	return fileStr[pos+1:], nil
 }
 
 // RegisterImage registers an image, adding it to the PDF file but not adding
@@ -3385,13 +4270,20 @@ func (f *Fpdf) RegisterImageOptions(fileStr string, options ImageOptions) (info
 	defer file.Close()
 
 	// First use of this image, get info
-	if options.ImageType == "" {
-		pos := strings.LastIndex(fileStr, ".")
-		if pos < 0 {
-			f.err = fmt.Errorf("image file has no extension and no type was specified: %s", fileStr)
+	if options.ImageType, f.err = imageFileType(fileStr, options.ImageType); f.err != nil {
+		return
+	}
+	// Images to be recompressed are decoded rather than streamed
+	if options.JPEGQuality <= 0 {
+		if info, ok = f.registerStreamedImage(fileStr, options.ImageType, options.ReadDpi, file); ok {
+			if f.err == nil {
+				f.images[fileStr] = info
+			}
+			return
+		}
+		if f.err != nil {
 			return
 		}
-		options.ImageType = fileStr[pos+1:]
 	}
 
 	return f.RegisterImageOptionsReader(fileStr, options, file)
@@ -3554,6 +4446,39 @@ func (f *Fpdf) SetXY(x, y float64) {
 	f.SetX(x)
 }
 
+// PushXY saves the current position on a stack, from which PopXY() restores
+// it. This lets functions that draw widgets and other compound elements move
+// freely and then return to where they began, without saving the position
+// themselves. Calls may be nested.
+func (f *Fpdf) PushXY() {
+	f.xyStack = append(f.xyStack, PointType{f.x, f.y})
+}
+
+// PopXY restores the current position most recently saved with PushXY() and
+// removes it from the stack. An error is set if the stack is empty.
+func (f *Fpdf) PopXY() {
+	if f.err != nil {
+		return
+	}
+	n := len(f.xyStack)
+	if n == 0 {
+		f.err = fmt.Errorf("PopXY called without a matching PushXY")
+		return
+	}
+	pt := f.xyStack[n-1]
+	f.xyStack = f.xyStack[:n-1]
+	f.x, f.y = pt.X, pt.Y
+}
+
+// MoveRel moves the current position by dx horizontally and dy vertically.
+// Unlike SetXY(), it does not interpret negative values as relative to the
+// right and bottom of the page, and the abscissa is not reset to the left
+// margin when only dy is non-zero.
+func (f *Fpdf) MoveRel(dx, dy float64) {
+	f.x += dx
+	f.y += dy
+}
+
 // SetProtection applies certain constraints on the finished PDF document.
 //
 // actionFlag is a bitflag that controls various document operations.
@@ -3573,12 +4498,34 @@ func (f *Fpdf) SetXY(x, y float64) {
 // string for this argument will be replaced with a random value, effectively
 // prohibiting full access to the document.
 func (f *Fpdf) SetProtection(actionFlag byte, userPassStr, ownerPassStr string) {
-	if f.err != nil {
+	if f.err != nil || f.streamStarted("setting protection") {
 		return
 	}
 	f.protect.setProtection(actionFlag, userPassStr, ownerPassStr)
 }
 
+// SetProtectAttachmentsOnly specifies whether the protection applied by
+// SetProtection() is restricted to the files embedded in the document with
+// SetAttachments() and AddAttachmentAnnotation(). If on is true, the pages of
+// the document remain viewable without a password, and the password is
+// requested when an attachment is opened. This supports documents whose
+// pages serve as a public cover sheet for a protected body, such as a PDF
+// file, carried as an attachment. This mode uses 128-bit keys and requires a
+// reader that supports PDF 1.6. It may be specified before or after
+// SetProtection().
+func (f *Fpdf) SetProtectAttachmentsOnly(on bool) {
+	if f.err != nil || f.streamStarted("setting protection") {
+		return
+	}
+	f.protect.efOnly = on
+	if on && f.pdfVersion < "1.6" {
+		f.pdfVersion = "1.6"
+	}
+	if f.protect.encrypted {
+		f.protect.setProtection(f.protect.actionFlag, f.protect.userPassStr, f.protect.ownerPassStr)
+	}
+}
+
 // OutputAndClose sends the PDF document to the writer specified by w. This
 // method will close both f and w, even if an error is detected and no document
 // is produced.
@@ -3609,16 +4556,21 @@ func (f *Fpdf) OutputFileAndClose(fileStr string) error {
 // Output sends the PDF document to the writer specified by w. No output will
 // take place if an error has occurred in the document generation process. w
 // remains open after this function returns. After returning, f is in a closed
-// state and its methods should not be called.
+// state and its methods should not be called. Documents created with
+// NewStreaming() are written by Close() instead.
 func (f *Fpdf) Output(w io.Writer) error {
 	if f.err != nil {
 		return f.err
 	}
+	if f.stream != nil {
+		f.SetErrorf("a streaming document is written by Close() rather than Output()")
+		return f.err
+	}
 	// dbg("Output")
 	if f.state < 3 {
 		f.Close()
 	}
-	_, err := f.buffer.WriteTo(w)
+	err := f.writeOutput(w)
 	if err != nil {
 		f.err = err
 	}
@@ -3702,7 +4654,11 @@ func (f *Fpdf) beginpage(orientationStr string, size SizeType) {
 
 func (f *Fpdf) endpage() {
 	f.EndLayer()
+	f.putLayoutGuides()
 	f.state = 1
+	if f.stream != nil {
+		f.streamPage(f.page)
+	}
 }
 
 // Load a font definition file from the given Reader
@@ -3741,11 +4697,14 @@ func (f *Fpdf) escape(s string) string {
 
 // textstring formats a text string
 func (f *Fpdf) textstring(s string) string {
-	if f.protect.encrypted {
+	if f.protect.content() {
 		b := []byte(s)
 		f.protect.rc4(uint32(f.n), &b)
 		s = string(b)
 	}
+	if f.asciiStreams != "" && !asciiString(s) {
+		return "<" + hex.EncodeToString([]byte(s)) + ">"
+	}
 	return "(" + f.escape(s) + ")"
 }
 
@@ -3765,19 +4724,34 @@ func (f *Fpdf) SetUnderlineThickness(thickness float64) {
 	f.userUnderlineThickness = thickness
 }
 
+// wordSpacingUTF8 returns the space in user units to add to each space of
+// txtStr, printed in the current UTF-8 font, so that a line aligned with
+// alignStr "J" fills the cell of width w. Other lines are printed with the
+// current word spacing.
+func (f *Fpdf) wordSpacingUTF8(w float64, txtStr, alignStr string) float64 {
+	if alignStr != "J" {
+		return f.ws
+	}
+	n := strings.Count(txtStr, " ")
+	if n == 0 {
+		return 0
+	}
+	return math.Max(0, (w-2*f.cMargin-f.GetStringWidth(txtStr))/float64(n))
+}
+
 // Underline text
-func (f *Fpdf) dounderline(x, y float64, txt string) string {
+func (f *Fpdf) dounderline(x, y float64, txt string, ws float64) string {
 	up := float64(f.currentFont.Up)
 	ut := float64(f.currentFont.Ut) * f.userUnderlineThickness
-	w := f.GetStringWidth(txt) + f.ws*float64(blankCount(txt))
+	w := f.GetStringWidth(txt) + ws*float64(blankCount(txt))
 	return sprintf("%.2f %.2f %.2f %.2f re f", x*f.k,
 		(f.h-(y-up/1000*f.fontSize))*f.k, w*f.k, -ut/1000*f.fontSizePt)
 }
 
-func (f *Fpdf) dostrikeout(x, y float64, txt string) string {
+func (f *Fpdf) dostrikeout(x, y float64, txt string, ws float64) string {
 	up := float64(f.currentFont.Up)
 	ut := float64(f.currentFont.Ut)
-	w := f.GetStringWidth(txt) + f.ws*float64(blankCount(txt))
+	w := f.GetStringWidth(txt) + ws*float64(blankCount(txt))
 	return sprintf("%.2f %.2f %.2f %.2f re f", x*f.k,
 		(f.h-(y+4*up/1000*f.fontSize))*f.k, w*f.k, -ut/1000*f.fontSizePt)
 }
@@ -3819,16 +4793,22 @@ func (f *Fpdf) parsejpg(r io.Reader) (info *ImageInfoType) {
 	info.h = float64(config.Height)
 	info.f = "DCTDecode"
 	info.bpc = 8
-	switch config.ColorModel {
+	info.cs, f.err = jpegColorSpace(config.ColorModel)
+	return
+}
+
+// jpegColorSpace returns the PDF color space of a JPEG image with color model
+// cm
+func jpegColorSpace(cm color.Model) (cs string, err error) {
+	switch cm {
 	case color.GrayModel:
-		info.cs = "DeviceGray"
+		cs = "DeviceGray"
 	case color.YCbCrModel:
-		info.cs = "DeviceRGB"
+		cs = "DeviceRGB"
 	case color.CMYKModel:
-		info.cs = "DeviceCMYK"
+		cs = "DeviceCMYK"
 	default:
-		f.err = fmt.Errorf("image JPEG buffer has unsupported color space (%v)", config.ColorModel)
-		return
+		err = fmt.Errorf("image JPEG buffer has unsupported color space (%v)", cm)
 	}
 	return
 }
@@ -3888,13 +4868,16 @@ func (f *Fpdf) newobj() {
 	for j := len(f.offsets); j <= f.n; j++ {
 		f.offsets = append(f.offsets, 0)
 	}
-	f.offsets[f.n] = f.buffer.Len()
+	f.offsets[f.n] = f.outputLen()
 	f.outf("%d 0 obj", f.n)
 }
 
 func (f *Fpdf) putstream(b []byte) {
 	// dbg("putstream")
-	if f.protect.encrypted {
+	if f.asciiStreams != "" {
+		b = f.asciiEncodeStream(b)
+	}
+	if f.protect.content() {
 		f.protect.rc4(uint32(f.n), &b)
 	}
 	f.out("stream")
@@ -3942,7 +4925,7 @@ func (f *Fpdf) RawWriteBuf(r io.Reader) {
 
 // outf adds a formatted line to the document
 func (f *Fpdf) outf(fmtStr string, args ...interface{}) {
-	f.out(sprintf(fmtStr, args...))
+	f.out(f.contentf(fmtStr, args...))
 }
 
 // SetDefaultCatalogSort sets the default value of the catalog sort flag that
@@ -4006,102 +4989,63 @@ func (f *Fpdf) RegisterAlias(alias, replacement string) {
 }
 
 func (f *Fpdf) replaceAliases() {
+	for n := 1; n <= f.page; n++ {
+		// Pages of a streaming document that have been written are nil
+		if f.pages[n] != nil {
+			f.replacePageAliases(n)
+		}
+	}
+}
+
+// replacePageAliases replaces the registered aliases in the content of page n
+func (f *Fpdf) replacePageAliases(n int) {
 	for mode := 0; mode < 2; mode++ {
 		for alias, replacement := range f.aliasMap {
 			if mode == 1 {
 				alias = utf8toutf16(alias, false)
 				replacement = utf8toutf16(replacement, false)
 			}
-			for n := 1; n <= f.page; n++ {
-				s := f.pages[n].String()
-				if strings.Contains(s, alias) {
-					s = strings.Replace(s, alias, replacement, -1)
-					f.pages[n].Truncate(0)
-					f.pages[n].WriteString(s)
-				}
+			s := f.pages[n].String()
+			if strings.Contains(s, alias) {
+				s = strings.Replace(s, alias, replacement, -1)
+				f.pages[n].Truncate(0)
+				f.pages[n].WriteString(s)
 			}
 		}
 	}
 }
 
 func (f *Fpdf) putpages() {
-	var wPt, hPt float64
-	var pageSize SizeType
-	var ok bool
 	nb := f.page
 	if len(f.aliasNbPagesStr) > 0 {
 		// Replace number of pages
 		f.RegisterAlias(f.aliasNbPagesStr, sprintf("%d", nb))
 	}
+	f.resolveCounterRefs()
 	f.replaceAliases()
-	if f.defOrientation == "P" {
-		wPt = f.defPageSize.Wd * f.k
-		hPt = f.defPageSize.Ht * f.k
+	wPt, hPt := f.defPageSizePt()
+	var contents map[[sha1.Size]byte]int
+	count := nb
+	if f.stream != nil {
+		contents = f.stream.contents
+		count -= f.stream.count
 	} else {
-		wPt = f.defPageSize.Ht * f.k
-		hPt = f.defPageSize.Wd * f.k
+		contents = make(map[[sha1.Size]byte]int)
 	}
-	pagesObjectNumbers := make([]int, nb+1) // 1-based
+	// Each page takes two objects; the form fields follow the pages
+	formAnnots := f.formNumberObjects(f.n + 2*count + 1)
 	for n := 1; n <= nb; n++ {
-		// Page
-		f.newobj()
-		pagesObjectNumbers[n] = f.n // save for /Kids
-		f.out("<</Type /Page")
-		f.out("/Parent 1 0 R")
-		pageSize, ok = f.pageSizes[n]
-		if ok {
-			f.outf("/MediaBox [0 0 %.2f %.2f]", pageSize.Wd, pageSize.Ht)
-		}
-		for t, pb := range f.pageBoxes[n] {
-			f.outf("/%s [%.2f %.2f %.2f %.2f]", t, pb.X, pb.Y, pb.Wd, pb.Ht)
-		}
-		f.out("/Resources 2 0 R")
-		// Links
-		if len(f.pageLinks[n])+len(f.pageAttachments[n]) > 0 {
-			var annots fmtBuffer
-			annots.printf("/Annots [")
-			for _, pl := range f.pageLinks[n] {
-				annots.printf("<</Type /Annot /Subtype /Link /Rect [%.2f %.2f %.2f %.2f] /Border [0 0 0] ",
-					pl.x, pl.y, pl.x+pl.wd, pl.y-pl.ht)
-				if pl.link == 0 {
-					annots.printf("/A <</S /URI /URI %s>>>>", f.textstring(pl.linkStr))
-				} else {
-					l := f.links[pl.link]
-					var sz SizeType
-					var h float64
-					sz, ok = f.pageSizes[l.page]
-					if ok {
-						h = sz.Ht
-					} else {
-						h = hPt
-					}
-					// dbg("h [%.2f], l.y [%.2f] f.k [%.2f]\n", h, l.y, f.k)
-					annots.printf("/Dest [%d 0 R /XYZ 0 %.2f null]>>", 1+2*l.page, h-l.y*f.k)
-				}
-			}
-			f.putAttachmentAnnotationLinks(&annots, n)
-			annots.printf("]")
-			f.out(annots.String())
-		}
-		if f.pdfVersion > "1.3" {
-			f.out("/Group <</Type /Group /S /Transparency /CS /DeviceRGB>>")
+		if f.pages[n] != nil {
+			f.putpage(n, formAnnots[n], contents)
 		}
-		f.outf("/Contents %d 0 R>>", f.n+1)
-		f.out("endobj")
-		// Page content
-		f.newobj()
-		if f.compress {
-			data := sliceCompress(f.pages[n].Bytes())
-			f.outf("<</Filter /FlateDecode /Length %d>>", len(data))
-			f.putstream(data)
-		} else {
-			f.outf("<</Length %d>>", f.pages[n].Len())
-			f.putstream(f.pages[n].Bytes())
-		}
-		f.out("endobj")
 	}
+	pagesObjectNumbers := make([]int, nb+1) // 1-based
+	for n := 1; n <= nb; n++ {
+		pagesObjectNumbers[n] = f.pageObj(n)
+	}
+	f.putFormFields(pagesObjectNumbers)
 	// Pages root
-	f.offsets[1] = f.buffer.Len()
+	f.offsets[1] = f.outputLen()
 	f.out("1 0 obj")
 	f.out("<</Type /Pages")
 	var kids fmtBuffer
@@ -4117,6 +5061,118 @@ func (f *Fpdf) putpages() {
 	f.out("endobj")
 }
 
+// defPageSizePt returns the width and height in points of pages of the
+// default size and orientation
+func (f *Fpdf) defPageSizePt() (wPt, hPt float64) {
+	if f.defOrientation == "P" {
+		return f.defPageSize.Wd * f.k, f.defPageSize.Ht * f.k
+	}
+	return f.defPageSize.Ht * f.k, f.defPageSize.Wd * f.k
+}
+
+// putpage writes the page object and content stream of page n. formAnnots
+// holds the object numbers of the form widgets of the page, and contents the
+// content streams that can be shared; see sharedPageContent().
+func (f *Fpdf) putpage(n int, formAnnots []int, contents map[[sha1.Size]byte]int) {
+	wPt, hPt := f.defPageSizePt()
+	// Page
+	f.newobj()
+	for len(f.pageObjs) <= n {
+		f.pageObjs = append(f.pageObjs, 0)
+	}
+	f.pageObjs[n] = f.n
+	f.out("<</Type /Page")
+	f.out("/Parent 1 0 R")
+	pageSize, ok := f.pageSizes[n]
+	if ok {
+		f.outf("/MediaBox [0 0 %.2f %.2f]", pageSize.Wd, pageSize.Ht)
+	}
+	for t, pb := range f.pageBoxes[n] {
+		f.outf("/%s [%.2f %.2f %.2f %.2f]", t, pb.X, pb.Y, pb.Wd, pb.Ht)
+	}
+	if ok {
+		f.pdfxPutBoxes(n, pageSize.Wd, pageSize.Ht)
+	} else {
+		f.pdfxPutBoxes(n, wPt, hPt)
+	}
+	f.out("/Resources 2 0 R")
+	f.pieceInfoPut(n)
+	// Links
+	if len(f.pageLinks[n])+len(f.pageAttachments[n])+len(formAnnots) > 0 {
+		var annots fmtBuffer
+		annots.printf("/Annots [")
+		for _, pl := range f.pageLinks[n] {
+			annots.printf("<</Type /Annot /Subtype /Link /Rect [%.2f %.2f %.2f %.2f] /Border [0 0 0] ",
+				pl.x, pl.y, pl.x+pl.wd, pl.y-pl.ht)
+			if destStr, ok := f.destAnnotStr(pl); ok {
+				annots.printf("%s>>", destStr)
+			} else if pl.link == 0 {
+				annots.printf("/A <</S /URI /URI %s>>>>", f.textstring(pl.linkStr))
+			} else if f.stream != nil {
+				// The page linked to may not have been written yet
+				annots.printf("/Dest /L%d>>", pl.link)
+			} else {
+				annots.printf("/Dest %s>>", f.linkDest(pl.link))
+			}
+		}
+		f.putAttachmentAnnotationLinks(&annots, n)
+		for _, obj := range formAnnots {
+			annots.printf("%d 0 R ", obj)
+		}
+		annots.printf("]")
+		f.out(annots.String())
+	}
+	if f.pdfVersion > "1.3" {
+		f.out("/Group <</Type /Group /S /Transparency /CS /DeviceRGB>>")
+	}
+	shared := f.sharedPageContent(n, f.n+1, contents)
+	if shared > 0 {
+		f.outf("/Contents %d 0 R>>", shared)
+		f.out("endobj")
+		// Keep the numbering of the objects of later pages
+		f.freeobj()
+		return
+	}
+	f.outf("/Contents %d 0 R>>", f.n+1)
+	f.out("endobj")
+	// Page content
+	f.newobj()
+	if f.compress {
+		data := sliceCompress(f.pages[n].Bytes())
+		f.outf("<</Filter /FlateDecode /Length %d>>", len(data))
+		f.putstream(data)
+	} else {
+		f.outf("<</Length %d>>", f.pages[n].Len())
+		f.putstream(f.pages[n].Bytes())
+	}
+	f.out("endobj")
+}
+
+// linkDest returns the destination array of the internal link identified
+// by link
+func (f *Fpdf) linkDest(link int) string {
+	return f.destStr(f.links[link])
+}
+
+// destStr returns the explicit destination of the page and position l
+func (f *Fpdf) destStr(l intLinkType) string {
+	h, ok := f.pageSizes[l.page]
+	if !ok {
+		_, h.Ht = f.defPageSizePt()
+	}
+	return sprintf("[%d 0 R /XYZ 0 %.2f null]", f.pageObj(l.page), h.Ht-l.y*f.k)
+}
+
+// pageObj returns the object number of the page object of page n. Pages that
+// have not been written yet are assumed to follow one another from object 3
+// on, each taking two objects.
+func (f *Fpdf) pageObj(n int) int {
+	if n < len(f.pageObjs) && f.pageObjs[n] > 0 {
+		return f.pageObjs[n]
+	}
+	return 1 + 2*n
+}
+
 func (f *Fpdf) putfonts() {
 	if f.err != nil {
 		return
@@ -4145,6 +5201,21 @@ func (f *Fpdf) putfonts() {
 				info.n = f.n
 				f.fontFiles[file] = info
 
+				if sections := f.fontFileSections(file, info); sections != nil {
+					f.outf("<</Length %d", sectionsLen(sections))
+					if file[len(file)-2:] == ".z" {
+						f.out("/Filter /FlateDecode")
+					}
+					f.outf("/Length1 %d", info.length1)
+					if info.length2 > 0 {
+						f.outf("/Length2 %d /Length3 0", info.length2)
+					}
+					f.out(">>")
+					f.putfilestream(sections)
+					f.out("endobj")
+					continue
+				}
+
 				var font []byte
 
 				if info.embedded {
@@ -4182,7 +5253,9 @@ func (f *Fpdf) putfonts() {
 		var font fontDefType
 		var key string
 		for key = range f.fonts {
-			keyList = append(keyList, key)
+			if !f.fontOmitted(f.fonts[key]) {
+				keyList = append(keyList, key)
+			}
 		}
 		if f.catalogSort {
 			sort.SliceStable(keyList, func(i, j int) bool { return keyList[i] < keyList[j] })
@@ -4201,7 +5274,9 @@ func (f *Fpdf) putfonts() {
 				f.out("<</Type /Font")
 				f.outf("/BaseFont /%s", name)
 				f.out("/Subtype /Type1")
-				if name != "Symbol" && name != "ZapfDingbats" {
+				if font.DiffN > 0 {
+					f.outf("/Encoding %d 0 R", nf+font.DiffN)
+				} else if name != "Symbol" && name != "ZapfDingbats" {
 					f.out("/Encoding /WinAnsiEncoding")
 				}
 				f.out(">>")
@@ -4264,22 +5339,70 @@ func (f *Fpdf) putfonts() {
 				for cid, runeVal := range font.usedRunes {
 					usedRunesCopy[cid] = runeVal
 				}
+				embedMode := f.fontEmbedMode(key, font)
+				if f.err != nil {
+					return
+				}
+				font.utf8File.glyphCIDs = font.cidGlyphs
 				utf8FontStream := font.utf8File.GenerateCutFont(usedRunesCopy)
+				if err := font.utf8File.err; err != nil {
+					f.SetErrorf("unable to embed font %s: %s", font.Name, err)
+					return
+				}
+				cidGlyphMap := font.utf8File.CodeSymbolDictionary
+				isCFF := font.utf8File.isCFF
+				switch embedMode {
+				case FontEmbedFull:
+					if isCFF {
+						// Glyphs are selected by CID, so the font is converted
+						// with all of its glyphs rather than embedded as is
+						utf8FontStream = font.utf8File.generateCutCFF(usedRunesCopy, true)
+						if err := font.utf8File.err; err != nil {
+							f.SetErrorf("unable to embed font %s: %s", font.Name, err)
+							return
+						}
+						break
+					}
+					// Map to the glyphs of the original font
+					utf8FontStream = font.utf8File.fileReader.array
+					cidGlyphMap = make(map[int]int, len(usedRunesCopy))
+					for cid, runeVal := range usedRunesCopy {
+						if glyph, ok := font.utf8File.charSymbolDictionary[runeVal]; ok && cid > 0 {
+							cidGlyphMap[cid] = glyph
+						}
+					}
+					for cid, g := range font.cidGlyphs {
+						cidGlyphMap[cid] = g.gid
+					}
+				case FontEmbedNone:
+					if name := font.utf8File.postScriptName; name != "" {
+						fontName = name
+					}
+				}
 				utf8FontSize := len(utf8FontStream)
 				compressedFontStream := sliceCompress(utf8FontStream)
-				cidGlyphMap := font.utf8File.CodeSymbolDictionary
 
 				f.newobj()
 				f.out(fmt.Sprintf("<</Type /Font\n/Subtype /Type0\n/BaseFont /%s\n/Encoding /Identity-H\n/DescendantFonts [%d 0 R]\n/ToUnicode %d 0 R>>\n"+"endobj", fontName, f.n+1, f.n+2))
 
+				// A CFF font program selects glyphs by CID itself, so it has no
+				// CIDToGIDMap
+				cidSubtype, ordering := "CIDFontType2", "UCS"
+				if isCFF {
+					cidSubtype, ordering = "CIDFontType0", "Identity"
+				}
 				f.newobj()
-				f.out("<</Type /Font\n/Subtype /CIDFontType2\n/BaseFont /" + fontName + "\n" +
+				f.out("<</Type /Font\n/Subtype /" + cidSubtype + "\n/BaseFont /" + fontName + "\n" +
 					"/CIDSystemInfo " + strconv.Itoa(f.n+2) + " 0 R\n/FontDescriptor " + strconv.Itoa(f.n+3) + " 0 R")
 				if font.Desc.MissingWidth != 0 {
 					f.out("/DW " + strconv.Itoa(font.Desc.MissingWidth) + "")
 				}
 				f.generateCIDFontMap(&font, font.utf8File.LastRune)
-				f.out("/CIDToGIDMap " + strconv.Itoa(f.n+4) + " 0 R>>")
+				if isCFF {
+					f.out(">>")
+				} else {
+					f.out("/CIDToGIDMap " + strconv.Itoa(f.n+4) + " 0 R>>")
+				}
 				f.out("endobj")
 
 				f.newobj()
@@ -4290,7 +5413,7 @@ func (f *Fpdf) putfonts() {
 
 				// CIDInfo
 				f.newobj()
-				f.out("<</Registry (Adobe)\n/Ordering (UCS)\n/Supplement 0>>")
+				f.out("<</Registry (Adobe)\n/Ordering (" + ordering + ")\n/Supplement 0>>")
 				f.out("endobj")
 
 				// Font descriptor
@@ -4308,11 +5431,30 @@ func (f *Fpdf) putfonts() {
 				s.printf(" /ItalicAngle %d", font.Desc.ItalicAngle)
 				s.printf(" /StemV %d", font.Desc.StemV)
 				s.printf(" /MissingWidth %d", font.Desc.MissingWidth)
-				s.printf("/FontFile2 %d 0 R", f.n+2)
+				if embedMode != FontEmbedNone {
+					if isCFF {
+						s.printf("/FontFile3 %d 0 R", f.n+1)
+					} else {
+						s.printf("/FontFile2 %d 0 R", f.n+2)
+					}
+				}
 				s.printf(">>")
 				f.out(s.String())
 				f.out("endobj")
 
+				if isCFF {
+					if embedMode != FontEmbedNone {
+						f.newobj()
+						f.out("<</Length " + strconv.Itoa(len(compressedFontStream)))
+						f.out("/Filter /FlateDecode")
+						f.out("/Subtype /CIDFontType0C")
+						f.out(">>")
+						f.putstream(compressedFontStream)
+						f.out("endobj")
+					}
+					continue
+				}
+
 				// Embed CIDToGIDMap
 				f.newobj()
 				maxCID := font.utf8File.LastRune
@@ -4331,13 +5473,15 @@ func (f *Fpdf) putfonts() {
 				f.out("endobj")
 
 				//Font file
-				f.newobj()
-				f.out("<</Length " + strconv.Itoa(len(compressedFontStream)))
-				f.out("/Filter /FlateDecode")
-				f.out("/Length1 " + strconv.Itoa(utf8FontSize))
-				f.out(">>")
-				f.putstream(compressedFontStream)
-				f.out("endobj")
+				if embedMode != FontEmbedNone {
+					f.newobj()
+					f.out("<</Length " + strconv.Itoa(len(compressedFontStream)))
+					f.out("/Filter /FlateDecode")
+					f.out("/Length1 " + strconv.Itoa(utf8FontSize))
+					f.out(">>")
+					f.putstream(compressedFontStream)
+					f.out("endobj")
+				}
 			default:
 				f.err = fmt.Errorf("unsupported font type: %s", tp)
 				return
@@ -4360,11 +5504,15 @@ func (f *Fpdf) generateCIDFontMap(font *fontDefType, maxCID int) {
 	// for each character
 	for cid := startCid; cid < cwLen; cid++ {
 		runeValue, okRune := font.usedRunes[cid]
-		if !okRune {
-			continue
-		}
 		width, ok := font.Cw[runeValue]
-		if !ok || width == 0x00 {
+		if g, isGlyph := font.cidGlyphs[cid]; isGlyph {
+			// Glyphs substituted by text shaping have widths of their own
+			okRune, ok, width = true, true, g.width
+			if width == 0 {
+				width = 65535
+			}
+		}
+		if !okRune || !ok || width == 0x00 {
 			continue
 		}
 		if width == 65535 {
@@ -4566,8 +5714,13 @@ func (f *Fpdf) putimage(info *ImageInfoType) {
 	if info.smask != nil {
 		f.outf("/SMask %d 0 R", f.n+1)
 	}
-	f.outf("/Length %d>>", len(info.data))
-	f.putstream(info.data)
+	if len(info.sections) > 0 {
+		f.outf("/Length %d>>", sectionsLen(info.sections))
+		f.putfilestream(info.sections)
+	} else {
+		f.outf("/Length %d>>", len(info.data))
+		f.putstream(info.data)
+	}
 	f.out("endobj")
 	// 	Soft mask
 	if len(info.smask) > 0 {
@@ -4576,7 +5729,7 @@ func (f *Fpdf) putimage(info *ImageInfoType) {
 			h:     info.h,
 			cs:    "DeviceGray",
 			bpc:   8,
-			f:     info.f,
+			f:     "FlateDecode",
 			dp:    sprintf("/Predictor 15 /Colors 1 /BitsPerComponent 8 /Columns %d", int(info.w)),
 			data:  info.smask,
 			scale: f.k,
@@ -4634,6 +5787,7 @@ func (f *Fpdf) putxobjectdict() {
 			f.outf("%s %d 0 R", tplName, f.importedTplIDs[objID])
 		}
 	}
+	f.visualIndexPutDict()
 }
 
 func (f *Fpdf) putresourcedict() {
@@ -4644,7 +5798,9 @@ func (f *Fpdf) putresourcedict() {
 		var font fontDefType
 		var key string
 		for key = range f.fonts {
-			keyList = append(keyList, key)
+			if !f.fontOmitted(f.fonts[key]) {
+				keyList = append(keyList, key)
+			}
 		}
 		if f.catalogSort {
 			sort.SliceStable(keyList, func(i, j int) bool { return f.fonts[keyList[i]].i < f.fonts[keyList[j]].i })
@@ -4659,11 +5815,14 @@ func (f *Fpdf) putresourcedict() {
 	f.putxobjectdict()
 	f.out(">>")
 	count := len(f.blendList)
-	if count > 1 {
+	if count > 1 || len(f.printStates) > 0 {
 		f.out("/ExtGState <<")
 		for j := 1; j < count; j++ {
 			f.outf("/GS%d %d 0 R", j, f.blendList[j].objNum)
 		}
+		for j, ps := range f.printStates {
+			f.outf("/PS%d %d 0 R", j+1, ps.objNum)
+		}
 		f.out(">>")
 	}
 	count = len(f.gradientList)
@@ -4693,17 +5852,28 @@ func (f *Fpdf) putBlendModes() {
 
 func (f *Fpdf) putGradients() {
 	count := len(f.gradientList)
+	lab := f.gradientLab()
+	spaceStr := "/DeviceRGB"
+	if lab {
+		spaceStr = calLabSpaceStr
+	}
 	for j := 1; j < count; j++ {
 		var f1 int
 		gr := f.gradientList[j]
-		if gr.tp == 2 || gr.tp == 3 {
+		if len(gr.stops) > 0 {
+			f.newobj()
+			f.out(gradientStitching(gr.stops, lab))
+			f.out("endobj")
+			f1 = f.n
+		} else if gr.tp == 2 || gr.tp == 3 {
 			f.newobj()
-			f.outf("<</FunctionType 2 /Domain [0.0 1.0] /C0 [%s] /C1 [%s] /N 1>>", gr.clr1Str, gr.clr2Str)
+			f.outf("<</FunctionType 2 /Domain [0.0 1.0] /C0 [%s] /C1 [%s] /N 1>>",
+				gradientColorStr(gr.clr1, lab), gradientColorStr(gr.clr2, lab))
 			f.out("endobj")
 			f1 = f.n
 		}
 		f.newobj()
-		f.outf("<</ShadingType %d /ColorSpace /DeviceRGB", gr.tp)
+		f.outf("<</ShadingType %d /ColorSpace %s", gr.tp, spaceStr)
 		if gr.tp == 2 {
 			f.outf("/Coords [%.5f %.5f %.5f %.5f] /Function %d 0 R /Extend [true true]>>",
 				gr.x1, gr.y1, gr.x2, gr.y2, f1)
@@ -4716,6 +5886,25 @@ func (f *Fpdf) putGradients() {
 	}
 }
 
+// gradientStitching returns a function that blends the colors of stops in
+// turn, each over the interval between its offset and that of the next stop,
+// in Lab if lab is true. Its domain spans the offsets, so that values outside
+// of it are given the color of the nearest stop.
+func gradientStitching(stops []gradientStopType, lab bool) string {
+	var fns, bounds, encode []string
+	for j := 1; j < len(stops); j++ {
+		fns = append(fns, sprintf("<</FunctionType 2 /Domain [0.0 1.0] /C0 [%s] /C1 [%s] /N 1>>",
+			gradientColorStr(stops[j-1].clr, lab), gradientColorStr(stops[j].clr, lab)))
+		encode = append(encode, "0 1")
+		if j < len(stops)-1 {
+			bounds = append(bounds, sprintf("%.5f", stops[j].offset))
+		}
+	}
+	return sprintf("<</FunctionType 3 /Domain [%.5f %.5f] /Functions [%s] /Bounds [%s] /Encode [%s]>>",
+		stops[0].offset, stops[len(stops)-1].offset, strings.Join(fns, " "),
+		strings.Join(bounds, " "), strings.Join(encode, " "))
+}
+
 func (f *Fpdf) putjavascript() {
 	if f.javascript == nil {
 		return
@@ -4741,8 +5930,10 @@ func (f *Fpdf) putresources() {
 	}
 	f.layerPutLayers()
 	f.putBlendModes()
+	f.putPrintStates()
 	f.putGradients()
 	f.putSpotColors()
+	f.putCalColorSpaces()
 	f.putfonts()
 	if f.err != nil {
 		return
@@ -4750,8 +5941,9 @@ func (f *Fpdf) putresources() {
 	f.putimages()
 	f.putTemplates()
 	f.putImportedTemplates() // gofpdi
+	f.visualIndexPutPages()
 	// 	Resource dictionary
-	f.offsets[2] = f.buffer.Len()
+	f.offsets[2] = f.outputLen()
 	f.out("2 0 obj")
 	f.out("<<")
 	f.putresourcedict()
@@ -4763,8 +5955,16 @@ func (f *Fpdf) putresources() {
 		f.protect.objNum = f.n
 		f.out("<<")
 		f.out("/Filter /Standard")
-		f.out("/V 1")
-		f.out("/R 2")
+		if f.protect.efOnly {
+			f.out("/V 4")
+			f.out("/R 4")
+			f.out("/Length 128")
+			f.out("/CF <</StdCF <</Type /CryptFilter /CFM /V2 /AuthEvent /EFOpen /Length 16>>>>")
+			f.out("/StmF /Identity /StrF /Identity /EFF /StdCF")
+		} else {
+			f.out("/V 1")
+			f.out("/R 2")
+		}
 		f.outf("/O (%s)", f.escape(string(f.protect.oValue)))
 		f.outf("/U (%s)", f.escape(string(f.protect.uValue)))
 		f.outf("/P %d", f.protect.pValue)
@@ -4783,7 +5983,7 @@ func timeOrNow(tm time.Time) time.Time {
 }
 
 func (f *Fpdf) putinfo() {
-	if len(f.producer) > 0 {
+	if len(f.producer) > 0 && !f.scrubMetadata {
 		f.outf("/Producer %s", f.textstring(f.producer))
 	}
 	if len(f.title) > 0 {
@@ -4801,10 +6001,13 @@ func (f *Fpdf) putinfo() {
 	if len(f.creator) > 0 {
 		f.outf("/Creator %s", f.textstring(f.creator))
 	}
-	creation := timeOrNow(f.creationDate)
-	f.outf("/CreationDate %s", f.textstring("D:"+creation.Format("20060102150405")))
-	mod := timeOrNow(f.modDate)
-	f.outf("/ModDate %s", f.textstring("D:"+mod.Format("20060102150405")))
+	if !f.scrubMetadata {
+		creation := timeOrNow(f.creationDate)
+		f.outf("/CreationDate %s", f.textstring("D:"+creation.Format("20060102150405")))
+		mod := timeOrNow(f.modDate)
+		f.outf("/ModDate %s", f.textstring("D:"+mod.Format("20060102150405")))
+	}
+	f.pdfxPutInfo()
 }
 
 func (f *Fpdf) putcatalog() {
@@ -4812,11 +6015,11 @@ func (f *Fpdf) putcatalog() {
 	f.out("/Pages 1 0 R")
 	switch f.zoomMode {
 	case "fullpage":
-		f.out("/OpenAction [3 0 R /Fit]")
+		f.outf("/OpenAction [%d 0 R /Fit]", f.pageObj(1))
 	case "fullwidth":
-		f.out("/OpenAction [3 0 R /FitH null]")
+		f.outf("/OpenAction [%d 0 R /FitH null]", f.pageObj(1))
 	case "real":
-		f.out("/OpenAction [3 0 R /XYZ null null 1]")
+		f.outf("/OpenAction [%d 0 R /XYZ null null 1]", f.pageObj(1))
 	}
 	// } 	else if !is_string($this->zoomMode))
 	// 		$this->out('/OpenAction [3 0 R /XYZ null null '.sprintf('%.2f',$this->zoomMode/100).']');
@@ -4842,6 +6045,14 @@ func (f *Fpdf) putcatalog() {
 	}
 	// Layers
 	f.layerPutCatalog()
+	f.formPutCatalog()
+	f.printTicketPutCatalog()
+	f.portfolioPutCatalog()
+	f.pieceInfoPut(0)
+	if f.xmpObj > 0 {
+		f.outf("/Metadata %d 0 R", f.xmpObj)
+	}
+	f.pdfxPutCatalog()
 	// Name dictionary :
 	//	-> Javascript
 	//	-> Embedded files
@@ -4852,23 +6063,34 @@ func (f *Fpdf) putcatalog() {
 	}
 	// Embedded files
 	f.outf("/EmbeddedFiles %s", f.getEmbeddedFiles())
+	f.destPutNames()
 	f.out(">>")
+	f.streamPutCatalog()
 }
 
 func (f *Fpdf) putheader() {
 	if len(f.blendMap) > 0 && f.pdfVersion < "1.4" {
 		f.pdfVersion = "1.4"
 	}
+	if f.compact && f.pdfVersion < "1.5" {
+		f.pdfVersion = "1.5"
+	}
 	f.outf("%%PDF-%s", f.pdfVersion)
 }
 
-func (f *Fpdf) puttrailer() {
+// puttrailer writes the entries of the trailer; root is the object number of
+// the catalog, which follows the document information
+func (f *Fpdf) puttrailer(root int) {
 	f.outf("/Size %d", f.n+1)
-	f.outf("/Root %d 0 R", f.n)
-	f.outf("/Info %d 0 R", f.n-1)
+	f.outf("/Root %d 0 R", root)
+	f.outf("/Info %d 0 R", root-1)
 	if f.protect.encrypted {
 		f.outf("/Encrypt %d 0 R", f.protect.objNum)
 		f.out("/ID [()()]")
+	} else if f.pdfx.Version != "" {
+		// PDF/X requires a file identifier
+		id := fmt.Sprintf("%x", md5.Sum(f.buffer.Bytes()))
+		f.outf("/ID [<%s><%s>]", id, id)
 	}
 }
 
@@ -4877,6 +6099,7 @@ func (f *Fpdf) putxmp() {
 		return
 	}
 	f.newobj()
+	f.xmpObj = f.n
 	f.outf("<< /Type /Metadata /Subtype /XML /Length %d >>", len(f.xmp))
 	f.putstream(f.xmp)
 	f.out("endobj")
@@ -4923,7 +6146,7 @@ func (f *Fpdf) putbookmarks() {
 			if o.last != -1 {
 				f.outf("/Last %d 0 R", n+o.last)
 			}
-			f.outf("/Dest [%d 0 R /XYZ 0 %.2f null]", 1+2*o.p, (f.h-o.y)*f.k)
+			f.outf("/Dest [%d 0 R /XYZ 0 %.2f null]", f.pageObj(o.p), (f.h-o.y)*f.k)
 			f.out("/Count 0>>")
 			f.out("endobj")
 		}
@@ -4940,10 +6163,17 @@ func (f *Fpdf) enddoc() {
 		return
 	}
 	f.layerEndDoc()
-	f.putheader()
+	f.pdfxCheck()
+	if f.err != nil {
+		return
+	}
+	if f.stream == nil || f.stream.version == "" {
+		f.putheader()
+	}
 	// Embedded files
 	f.putAttachments()
 	f.putAnnotationsAttachments()
+	f.printTicketPutAttachment()
 	f.putpages()
 	f.putresources()
 	if f.err != nil {
@@ -4953,6 +6183,7 @@ func (f *Fpdf) enddoc() {
 	f.putbookmarks()
 	// Metadata
 	f.putxmp()
+	f.pdfxPutOutputIntent()
 	// 	Info
 	f.newobj()
 	f.out("<<")
@@ -4965,24 +6196,48 @@ func (f *Fpdf) enddoc() {
 	f.putcatalog()
 	f.out(">>")
 	f.out("endobj")
-	// Cross-ref
-	o := f.buffer.Len()
+	if f.compact {
+		f.putxrefstream(f.n)
+	} else {
+		f.putxref()
+	}
+	f.state = 3
+	if f.stream != nil {
+		f.streamFlush()
+	}
+	return
+}
+
+// putxref writes the cross-reference table and the trailer
+func (f *Fpdf) putxref() {
+	o := f.outputLen()
 	f.out("xref")
 	f.outf("0 %d", f.n+1)
-	f.out("0000000000 65535 f ")
+	// Free objects form a list that begins with object 0
+	next := make([]int, f.n+1)
+	last := 0
+	for j := 1; j <= f.n; j++ {
+		if f.offsets[j] == 0 {
+			next[last] = j
+			last = j
+		}
+	}
+	f.outf("%010d 65535 f ", next[0])
 	for j := 1; j <= f.n; j++ {
-		f.outf("%010d 00000 n ", f.offsets[j])
+		if f.offsets[j] == 0 {
+			f.outf("%010d 00001 f ", next[j])
+		} else {
+			f.outf("%010d 00000 n ", f.offsets[j])
+		}
 	}
 	// Trailer
 	f.out("trailer")
 	f.out("<<")
-	f.puttrailer()
+	f.puttrailer(f.n)
 	f.out(">>")
 	f.out("startxref")
 	f.outf("%d", o)
 	f.out("%%EOF")
-	f.state = 3
-	return
 }
 
 // Path Drawing
@@ -5067,7 +6322,7 @@ func (f *Fpdf) ClosePath() {
 //
 // The MoveTo() example demonstrates this method.
 func (f *Fpdf) DrawPath(styleStr string) {
-	f.outf(fillDrawOp(styleStr))
+	f.outf(f.paintOp(styleStr))
 }
 
 // ArcTo draws an elliptical arc centered at point (x, y). rx and ry specify its
@@ -5106,9 +6361,8 @@ func (f *Fpdf) arc(x, y, rx, ry, degRotate, degStart, degEnd float64,
 	dtm := dt / 3
 	if degRotate != 0 {
 		a := -degRotate * math.Pi / 180
-		f.outf("q %.5f %.5f %.5f %.5f %.5f %.5f cm",
-			math.Cos(a), -1*math.Sin(a),
-			math.Sin(a), math.Cos(a), x, y)
+		f.outf("q %s %.5f %.5f cm",
+			fixedf("%.5f %.5f %.5f %.5f", math.Cos(a), -1*math.Sin(a), math.Sin(a), math.Cos(a)), x, y)
 		x = 0
 		y = 0
 	}
@@ -5150,7 +6404,7 @@ func (f *Fpdf) arc(x, y, rx, ry, degRotate, degStart, degEnd float64,
 		}
 	}
 	if !path {
-		f.out(fillDrawOp(styleStr))
+		f.out(f.paintOp(styleStr))
 	}
 	if degRotate != 0 {
 		f.out("Q")
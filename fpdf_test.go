@@ -19,17 +19,28 @@ package gofpdf_test
 import (
 	"bufio"
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"math"
+	"math/big"
 	"math/rand"
 	"net/http"
 	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"testing"
+	"text/template"
 	"time"
 
 	"github.com/headlands-org/gofpdf"
@@ -339,2572 +350,7370 @@ func ExampleFpdf_AddPage() {
 	// Successfully generated pdf/Fpdf_AddPage.pdf
 }
 
-// ExampleFpdf_MultiCell demonstrates word-wrapping, line justification and
-// page-breaking.
-func ExampleFpdf_MultiCell() {
+// ExampleFpdf_SetPageEventHandler demonstrates the use of page events to keep
+// track of the lines printed on each page and to mark landscape pages.
+func ExampleFpdf_SetPageEventHandler() {
 	pdf := gofpdf.New("P", "mm", "A4", "")
-	titleStr := "20000 Leagues Under the Seas"
-	pdf.SetTitle(titleStr, false)
-	pdf.SetAuthor("Jules Verne", false)
-	pdf.SetHeaderFunc(func() {
-		// Arial bold 15
-		pdf.SetFont("Arial", "B", 15)
-		// Calculate width of title and position
-		wd := pdf.GetStringWidth(titleStr) + 6
-		pdf.SetX((210 - wd) / 2)
-		// Colors of frame, background and text
-		pdf.SetDrawColor(0, 80, 180)
-		pdf.SetFillColor(230, 230, 0)
-		pdf.SetTextColor(220, 50, 50)
-		// Thickness of frame (1 mm)
-		pdf.SetLineWidth(1)
-		// Title
-		pdf.CellFormat(wd, 9, titleStr, "1", 1, "C", true, 0, "")
-		// Line break
-		pdf.Ln(10)
+	var next, first, count int
+	pdf.SetPageEventHandler(gofpdf.PageEventFuncs{
+		Start: func(pageNum int, size gofpdf.SizeType) {
+			first, count = next, 0
+			if size.Wd > size.Ht {
+				pdf.SetTextColor(200, 200, 200)
+				pdf.Text(10, 10, "Landscape page")
+				pdf.SetTextColor(0, 0, 0)
+			}
+		},
+		End: func(pageNum int, size gofpdf.SizeType) {
+			fmt.Printf("Page %d (%.0f x %.0f mm): %d lines beginning with line %d\n",
+				pageNum, size.Wd, size.Ht, count, first)
+		},
 	})
+	pdf.SetFont("Times", "", 12)
+	line := func(j int) {
+		next = j
+		pdf.CellFormat(0, 10, fmt.Sprintf("Printing line number %d", j), "", 1, "", false, 0, "")
+		count++
+	}
+	next = 1
+	pdf.AddPage()
+	for j := 1; j <= 30; j++ {
+		line(j)
+	}
+	next = 31
+	pdf.AddPageFormat("L", gofpdf.SizeType{Wd: 210, Ht: 297})
+	for j := 31; j <= 35; j++ {
+		line(j)
+	}
+	fileStr := example.Filename("Fpdf_SetPageEventHandler")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Page 1 (210 x 297 mm): 26 lines beginning with line 1
+	// Page 2 (210 x 297 mm): 4 lines beginning with line 27
+	// Page 3 (297 x 210 mm): 5 lines beginning with line 31
+	// Successfully generated pdf/Fpdf_SetPageEventHandler.pdf
+}
+
+// ExampleFpdf_GenerateIndex demonstrates a back-of-book index. Terms are
+// marked as the body of the document is written and the index itself is laid
+// out when the document is closed.
+func ExampleFpdf_GenerateIndex() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
 	pdf.SetFooterFunc(func() {
-		// Position at 1.5 cm from bottom
 		pdf.SetY(-15)
-		// Arial italic 8
-		pdf.SetFont("Arial", "I", 8)
-		// Text color in gray
-		pdf.SetTextColor(128, 128, 128)
-		// Page number
-		pdf.CellFormat(0, 10, fmt.Sprintf("Page %d", pdf.PageNo()),
-			"", 0, "C", false, 0, "")
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(0, 10, fmt.Sprintf("%d", pdf.PageNo()), "", 0, "C", false, 0, "")
 	})
-	chapterTitle := func(chapNum int, titleStr string) {
-		// 	// Arial 12
-		pdf.SetFont("Arial", "", 12)
-		// Background color
-		pdf.SetFillColor(200, 220, 255)
-		// Title
-		pdf.CellFormat(0, 6, fmt.Sprintf("Chapter %d : %s", chapNum, titleStr),
-			"", 1, "L", true, 0, "")
-		// Line break
-		pdf.Ln(4)
-	}
-	chapterBody := func(fileStr string) {
-		// Read text file
-		txtStr, err := ioutil.ReadFile(fileStr)
-		if err != nil {
-			pdf.SetError(err)
-		}
-		// Times 12
-		pdf.SetFont("Times", "", 12)
-		// Output justified text
-		pdf.MultiCell(0, 5, string(txtStr), "", "", false)
-		// Line break
-		pdf.Ln(-1)
-		// Mention in italics
-		pdf.SetFont("", "I", 0)
-		pdf.Cell(0, 5, "(end of excerpt)")
-	}
-	printChapter := func(chapNum int, titleStr, fileStr string) {
+	pdf.GenerateIndex("Index", 3)
+	terms := []string{"alignment", "Bookmarks", "cells", "colors!fill", "colors!text",
+		"fonts", "fonts!core", "fonts!embedding", "images", "links", "margins",
+		"Outline", "page breaks", "templates", "transformations", "UTF-8"}
+	for pg := 0; pg < 8; pg++ {
 		pdf.AddPage()
-		chapterTitle(chapNum, titleStr)
-		chapterBody(fileStr)
+		pdf.SetFont("Times", "", 12)
+		pdf.MultiCell(0, 5, lorem(), "", "J", false)
+		for j, term := range terms {
+			// Mark each term on a few pages, some of them consecutive
+			if (j+pg)%5 == 0 || (j*pg)%7 == 1 || (j%4 == 0 && pg > 4) {
+				pdf.MarkIndexEntry(term)
+			}
+		}
 	}
-	printChapter(1, "A RUNAWAY REEF", example.TextFile("20k_c1.txt"))
-	printChapter(2, "THE PROS AND CONS", example.TextFile("20k_c2.txt"))
-	fileStr := example.Filename("Fpdf_MultiCell")
+	pdf.SetFont("Helvetica", "", 10)
+	fileStr := example.Filename("Fpdf_GenerateIndex")
 	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_MultiCell.pdf
+	// Successfully generated pdf/Fpdf_GenerateIndex.pdf
 }
 
-// ExampleFpdf_SetLeftMargin demonstrates the generation of a PDF document that has multiple
-// columns. This is accomplished with the SetLeftMargin() and Cell() methods.
-func ExampleFpdf_SetLeftMargin() {
-	var y0 float64
-	var crrntCol int
+// ExampleFpdf_GenerateVisualIndex demonstrates a contact sheet of the pages
+// of a document. It is placed at the start of the document when the document
+// is closed; each thumbnail links to the page it shows.
+func ExampleFpdf_GenerateVisualIndex() {
 	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.SetDisplayMode("fullpage", "TwoColumnLeft")
-	titleStr := "20000 Leagues Under the Seas"
-	pdf.SetTitle(titleStr, false)
-	pdf.SetAuthor("Jules Verne", false)
-	setCol := func(col int) {
-		// Set position at a given column
-		crrntCol = col
-		x := 10.0 + float64(col)*65.0
-		pdf.SetLeftMargin(x)
-		pdf.SetX(x)
-	}
-	chapterTitle := func(chapNum int, titleStr string) {
-		// Arial 12
-		pdf.SetFont("Arial", "", 12)
-		// Background color
-		pdf.SetFillColor(200, 220, 255)
-		// Title
-		pdf.CellFormat(0, 6, fmt.Sprintf("Chapter %d : %s", chapNum, titleStr),
-			"", 1, "L", true, 0, "")
-		// Line break
-		pdf.Ln(4)
-		y0 = pdf.GetY()
-	}
-	chapterBody := func(fileStr string) {
-		// Read text file
-		txtStr, err := ioutil.ReadFile(fileStr)
-		if err != nil {
-			pdf.SetError(err)
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(0, 10, fmt.Sprintf("%d", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	pdf.GenerateVisualIndex("Pages at a glance", 4)
+	for pg := 1; pg <= 10; pg++ {
+		if pg == 7 {
+			pdf.AddPageFormat("L", pdf.GetPageSizeStr("A4"))
+		} else {
+			pdf.AddPage()
 		}
-		// Font
+		pdf.SetFont("Helvetica", "B", 20)
+		pdf.Bookmark(fmt.Sprintf("Chapter %d", pg), 0, 0)
+		pdf.CellFormat(0, 12, fmt.Sprintf("Chapter %d", pg), "", 1, "L", false, 0, "")
+		pdf.SetFillColor(40*pg%256, 120, 255-20*pg)
+		pdf.Rect(10, 30, 60+10*float64(pg), 40, "F")
+		if pg%3 == 0 {
+			pdf.ImageOptions(example.ImageFile("logo.png"), 120, 30, 40, 0, false,
+				gofpdf.ImageOptions{ReadDpi: true}, 0, "")
+		}
+		pdf.SetY(80)
 		pdf.SetFont("Times", "", 12)
-		// Output text in a 6 cm width column
-		pdf.MultiCell(60, 5, string(txtStr), "", "", false)
-		pdf.Ln(-1)
-		// Mention
-		pdf.SetFont("", "I", 0)
-		pdf.Cell(0, 5, "(end of excerpt)")
-		// Go back to first column
-		setCol(0)
-	}
-	printChapter := func(num int, titleStr, fileStr string) {
-		// Add chapter
-		pdf.AddPage()
-		chapterTitle(num, titleStr)
-		chapterBody(fileStr)
+		pdf.MultiCell(0, 5, lorem(), "", "J", false)
 	}
-	pdf.SetAcceptPageBreakFunc(func() bool {
-		// Method accepting or not automatic page break
-		if crrntCol < 2 {
-			// Go to next column
-			setCol(crrntCol + 1)
-			// Set ordinate to top
-			pdf.SetY(y0)
-			// Keep on page
-			return false
-		}
-		// Go back to first column
-		setCol(0)
-		// Page break
-		return true
-	})
-	pdf.SetHeaderFunc(func() {
-		// Arial bold 15
-		pdf.SetFont("Arial", "B", 15)
-		// Calculate width of title and position
-		wd := pdf.GetStringWidth(titleStr) + 6
-		pdf.SetX((210 - wd) / 2)
-		// Colors of frame, background and text
-		pdf.SetDrawColor(0, 80, 180)
-		pdf.SetFillColor(230, 230, 0)
-		pdf.SetTextColor(220, 50, 50)
-		// Thickness of frame (1 mm)
-		pdf.SetLineWidth(1)
-		// Title
-		pdf.CellFormat(wd, 9, titleStr, "1", 1, "C", true, 0, "")
-		// Line break
-		pdf.Ln(10)
-		// Save ordinate
-		y0 = pdf.GetY()
-	})
-	pdf.SetFooterFunc(func() {
-		// Position at 1.5 cm from bottom
-		pdf.SetY(-15)
-		// Arial italic 8
-		pdf.SetFont("Arial", "I", 8)
-		// Text color in gray
-		pdf.SetTextColor(128, 128, 128)
-		// Page number
-		pdf.CellFormat(0, 10, fmt.Sprintf("Page %d", pdf.PageNo()),
-			"", 0, "C", false, 0, "")
+	fileStr := example.Filename("Fpdf_GenerateVisualIndex")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_GenerateVisualIndex.pdf
+}
+
+// ExampleFpdf_SetRunningHeadFunc demonstrates dictionary-style running heads
+// that show the first and last entries on each page.
+func ExampleFpdf_SetRunningHeadFunc() {
+	pdf := gofpdf.New("P", "mm", "A5", "")
+	pdf.SetRunningHeadFunc(0, func(head gofpdf.RunningHeadType) {
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.SetXY(10, 8)
+		pdf.CellFormat(0, 6, head.First, "B", 0, "L", false, 0, "")
+		pdf.SetX(10)
+		pdf.CellFormat(0, 6, head.Last, "", 0, "R", false, 0, "")
+		fmt.Printf("Page %d: %s - %s\n", pdf.PageNo(), head.First, head.Last)
 	})
-	printChapter(1, "A RUNAWAY REEF", example.TextFile("20k_c1.txt"))
-	printChapter(2, "THE PROS AND CONS", example.TextFile("20k_c2.txt"))
-	fileStr := example.Filename("Fpdf_SetLeftMargin_multicolumn")
+	pdf.SetTopMargin(18)
+	pdf.AddPage()
+	words := strings.Fields("abacus banner cactus dapple eagle fable gadget habit " +
+		"icicle jacket kettle ladder magnet napkin oasis paddle quartz rabbit")
+	for j, word := range words {
+		pdf.SetFont("Times", "B", 12)
+		pdf.Bookmark(word, 0, -1)
+		pdf.CellFormat(0, 6, word, "", 1, "", false, 0, "")
+		pdf.SetFont("Times", "", 11)
+		if j == 9 {
+			// A long entry that leaves a page without a new section
+			pdf.MultiCell(0, 5, strings.Repeat(lorem()+" ", 8), "", "J", false)
+		} else {
+			pdf.MultiCell(0, 5, loremList()[0], "", "J", false)
+		}
+		pdf.Ln(2)
+	}
+	fileStr := example.Filename("Fpdf_SetRunningHeadFunc")
 	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_SetLeftMargin_multicolumn.pdf
+	// Page 1: abacus - jacket
+	// Page 2: jacket - jacket
+	// Page 3: kettle - quartz
+	// Page 4: rabbit - rabbit
+	// Successfully generated pdf/Fpdf_SetRunningHeadFunc.pdf
 }
 
-// ExampleFpdf_SplitLines_tables demonstrates word-wrapped table cells
-func ExampleFpdf_SplitLines_tables() {
-	const (
-		colCount = 3
-		colWd    = 60.0
-		marginH  = 15.0
-		lineHt   = 5.5
-		cellGap  = 2.0
-	)
-	// var colStrList [colCount]string
-	type cellType struct {
-		str  string
-		list [][]byte
-		ht   float64
+// ExampleFpdf_SetContinuationMarkers demonstrates the markers printed where
+// MultiCell() content is split by a page break, first with the default
+// appearance and then with a custom marker function.
+func ExampleFpdf_SetContinuationMarkers() {
+	pdf := gofpdf.New("P", "mm", "A6", "")
+	pdf.SetFont("Times", "", 10)
+	pdf.SetContinuationMarkers(gofpdf.NewContinuationMarkers())
+	pdf.AddPage()
+	pdf.MultiCell(0, 5, strings.Repeat(lorem()+" ", 3), "", "J", false)
+	markers := gofpdf.NewContinuationMarkers()
+	markers.NextStr = "Turn the page"
+	markers.PrevStr = "Continued"
+	markers.NextHt = 5
+	markers.Fnc = func(txtStr string, w float64, next bool) {
+		pdf.SetFont("Helvetica", "B", 7)
+		pdf.SetFillColor(220, 230, 250)
+		if next {
+			pdf.CellFormat(w, 5, txtStr+" >>", "", 0, "C", true, 0, "")
+		} else {
+			pdf.CellFormat(w, 5, "<< "+txtStr, "", 2, "C", true, 0, "")
+			pdf.Ln(1)
+		}
 	}
-	var (
-		cellList [colCount]cellType
-		cell     cellType
-	)
+	pdf.SetContinuationMarkers(markers)
+	pdf.AddPage()
+	pdf.MultiCell(0, 5, strings.Repeat(lorem()+" ", 3), "", "J", false)
+	fileStr := example.Filename("Fpdf_SetContinuationMarkers")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetContinuationMarkers.pdf
+}
 
-	pdf := gofpdf.New("P", "mm", "A4", "") // 210 x 297
-	header := [colCount]string{"Column A", "Column B", "Column C"}
-	alignList := [colCount]string{"L", "C", "R"}
-	strList := loremList()
-	pdf.SetMargins(marginH, 15, marginH)
-	pdf.SetFont("Arial", "", 14)
+// ExampleFpdf_ColumnText demonstrates text flowed through three columns. The
+// columns on the first page are filled completely and those on the second
+// page are balanced.
+func ExampleFpdf_ColumnText() {
+	pdf := gofpdf.New("P", "mm", "A5", "")
+	pdf.SetFont("Times", "", 10)
 	pdf.AddPage()
+	pdf.SetFont("Times", "B", 16)
+	pdf.CellFormat(0, 10, "Newsletter", "B", 1, "C", false, 0, "")
+	pdf.Ln(4)
+	pdf.SetFont("Times", "", 10)
+	pdf.ColumnText(3, 5, 4.5, strings.Repeat(lorem()+"\n", 9), "L")
+	pdf.SetDrawColor(128, 128, 128)
+	pdf.Line(pdf.GetX(), pdf.GetY()+2, 138, pdf.GetY()+2)
+	fmt.Printf("Text ends on page %d at %.1f mm\n", pdf.PageNo(), pdf.GetY())
+	fileStr := example.Filename("Fpdf_ColumnText")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Text ends on page 2 at 104.5 mm
+	// Successfully generated pdf/Fpdf_ColumnText.pdf
+}
 
-	// Headers
-	pdf.SetTextColor(224, 224, 224)
-	pdf.SetFillColor(64, 64, 64)
-	for colJ := 0; colJ < colCount; colJ++ {
-		pdf.CellFormat(colWd, 10, header[colJ], "1", 0, "CM", true, 0, "")
+// ExampleFpdf_SetLayoutGuides demonstrates the guides that help to check a
+// layout. The newsletter of the ColumnText() example is shown with its
+// margins, a baseline grid matching its line height, the edges of its
+// columns and a marker at the start of each line. The guides are drawn in a
+// layer that is not printed.
+func ExampleFpdf_SetLayoutGuides() {
+	pdf := gofpdf.New("P", "mm", "A5", "")
+	pdf.SetLayoutGuides(&gofpdf.LayoutGuidesType{
+		Margins:      true,
+		BaselineGrid: 4.5,
+		Columns:      3,
+		Gutter:       5,
+		Cursor:       true,
+	})
+	pdf.SetFont("Times", "", 10)
+	pdf.AddPage()
+	pdf.SetFont("Times", "B", 16)
+	pdf.CellFormat(0, 9, "Newsletter", "B", 1, "C", false, 0, "")
+	pdf.Ln(4.5)
+	pdf.SetFont("Times", "", 10)
+	pdf.ColumnText(3, 5, 4.5, strings.Repeat(lorem()+"\n", 4), "L")
+	fileStr := example.Filename("Fpdf_SetLayoutGuides")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetLayoutGuides.pdf
+}
+
+// ExampleFpdf_SetDebugChars demonstrates the proofing aid that makes
+// unexpected characters in data visible. The record contains a tab, an
+// escape character and a Chinese character that the font cannot print;
+// without the aid the first two would print as nothing and the last as a
+// blank. Lines broken by line feeds end with a pilcrow.
+func ExampleFpdf_SetDebugChars() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.SetFont("dejavu", "", 12)
+	pdf.AddPage()
+	recordStr := "Name:\tAna\x1b Ruiz \u4E2D\nCity:\tCórdoba"
+	pdf.MultiCell(0, 6, recordStr, "", "L", false)
+	pdf.SetDebugChars(gofpdf.DebugCharsType{Missing: true, Control: true})
+	pdf.Ln(6)
+	pdf.MultiCell(0, 6, recordStr, "", "L", false)
+	for _, lineStr := range pdf.SplitText(recordStr, 100) {
+		fmt.Println(lineStr)
 	}
-	pdf.Ln(-1)
-	pdf.SetTextColor(24, 24, 24)
-	pdf.SetFillColor(255, 255, 255)
+	fileStr := example.Filename("Fpdf_SetDebugChars")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Name:→Ana[1B] Ruiz [4E2D]¶
+	// City:→Córdoba
+	// Successfully generated pdf/Fpdf_SetDebugChars.pdf
+}
 
-	// Rows
-	y := pdf.GetY()
-	count := 0
-	for rowJ := 0; rowJ < 2; rowJ++ {
-		maxHt := lineHt
-		// Cell height calculation loop
-		for colJ := 0; colJ < colCount; colJ++ {
-			count++
-			if count > len(strList) {
-				count = 1
-			}
-			cell.str = strings.Join(strList[0:count], " ")
-			cell.list = pdf.SplitLines([]byte(cell.str), colWd-cellGap-cellGap)
-			cell.ht = float64(len(cell.list)) * lineHt
-			if cell.ht > maxHt {
-				maxHt = cell.ht
-			}
-			cellList[colJ] = cell
-		}
-		// Cell render loop
-		x := marginH
-		for colJ := 0; colJ < colCount; colJ++ {
-			pdf.Rect(x, y, colWd, maxHt+cellGap+cellGap, "D")
-			cell = cellList[colJ]
-			cellY := y + cellGap + (maxHt-cell.ht)/2
-			for splitJ := 0; splitJ < len(cell.list); splitJ++ {
-				pdf.SetXY(x+cellGap, cellY)
-				pdf.CellFormat(colWd-cellGap-cellGap, lineHt, string(cell.list[splitJ]), "", 0,
-					alignList[colJ], false, 0, "")
-				cellY += lineHt
-			}
-			x += colWd
-		}
-		y += maxHt + cellGap + cellGap
-	}
-
-	fileStr := example.Filename("Fpdf_SplitLines_tables")
+// ExampleFpdf_ShapeText demonstrates text wrapped to the varying width of
+// non-rectangular regions. Text that does not fit in the triangle is
+// continued in the ellipse.
+func ExampleFpdf_ShapeText() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Times", "", 11)
+	pdf.AddPage()
+	triangle := []gofpdf.PointType{{X: 105, Y: 20}, {X: 185, Y: 130}, {X: 25, Y: 130}}
+	pdf.SetDrawColor(180, 180, 180)
+	pdf.Polygon(triangle, "D")
+	rest := pdf.ShapeText(triangle, 5, strings.Repeat(lorem()+" ", 4), "J")
+	pdf.Ellipse(105, 200, 70, 55, 0, "D")
+	pdf.SetFont("Helvetica", "I", 10)
+	rest = pdf.ShapeTextEllipse(105, 200, 70, 55, 5, rest, "C")
+	fmt.Printf("Text left over: %v\n", rest != "")
+	fileStr := example.Filename("Fpdf_ShapeText")
 	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_SplitLines_tables.pdf
+	// Text left over: false
+	// Successfully generated pdf/Fpdf_ShapeText.pdf
 }
 
-// ExampleFpdf_CellFormat_tables demonstrates various table styles.
-func ExampleFpdf_CellFormat_tables() {
+// ExampleFpdf_MultiCellDropCap demonstrates paragraphs that begin with a drop
+// cap, using a core font and a UTF-8 font.
+func ExampleFpdf_MultiCellDropCap() {
 	pdf := gofpdf.New("P", "mm", "A4", "")
-	type countryType struct {
-		nameStr, capitalStr, areaStr, popStr string
-	}
-	countryList := make([]countryType, 0, 8)
-	header := []string{"Country", "Capital", "Area (sq km)", "Pop. (thousands)"}
-	loadData := func(fileStr string) {
-		fl, err := os.Open(fileStr)
-		if err == nil {
-			scanner := bufio.NewScanner(fl)
-			var c countryType
-			for scanner.Scan() {
-				// Austria;Vienna;83859;8075
-				lineStr := scanner.Text()
-				list := strings.Split(lineStr, ";")
-				if len(list) == 4 {
-					c.nameStr = list[0]
-					c.capitalStr = list[1]
-					c.areaStr = list[2]
-					c.popStr = list[3]
-					countryList = append(countryList, c)
-				} else {
-					err = fmt.Errorf("error tokenizing %s", lineStr)
-				}
-			}
-			fl.Close()
-			if len(countryList) == 0 {
-				err = fmt.Errorf("error loading data from %s", fileStr)
-			}
-		}
-		if err != nil {
-			pdf.SetError(err)
-		}
-	}
-	// Simple table
-	basicTable := func() {
-		left := (210.0 - 4*40) / 2
-		pdf.SetX(left)
-		for _, str := range header {
-			pdf.CellFormat(40, 7, str, "1", 0, "", false, 0, "")
-		}
-		pdf.Ln(-1)
-		for _, c := range countryList {
-			pdf.SetX(left)
-			pdf.CellFormat(40, 6, c.nameStr, "1", 0, "", false, 0, "")
-			pdf.CellFormat(40, 6, c.capitalStr, "1", 0, "", false, 0, "")
-			pdf.CellFormat(40, 6, c.areaStr, "1", 0, "", false, 0, "")
-			pdf.CellFormat(40, 6, c.popStr, "1", 0, "", false, 0, "")
-			pdf.Ln(-1)
-		}
-	}
-	// Better table
-	improvedTable := func() {
-		// Column widths
-		w := []float64{40.0, 35.0, 40.0, 45.0}
-		wSum := 0.0
-		for _, v := range w {
-			wSum += v
-		}
-		left := (210 - wSum) / 2
-		// 	Header
-		pdf.SetX(left)
-		for j, str := range header {
-			pdf.CellFormat(w[j], 7, str, "1", 0, "C", false, 0, "")
-		}
-		pdf.Ln(-1)
-		// Data
-		for _, c := range countryList {
-			pdf.SetX(left)
-			pdf.CellFormat(w[0], 6, c.nameStr, "LR", 0, "", false, 0, "")
-			pdf.CellFormat(w[1], 6, c.capitalStr, "LR", 0, "", false, 0, "")
-			pdf.CellFormat(w[2], 6, strDelimit(c.areaStr, ",", 3),
-				"LR", 0, "R", false, 0, "")
-			pdf.CellFormat(w[3], 6, strDelimit(c.popStr, ",", 3),
-				"LR", 0, "R", false, 0, "")
-			pdf.Ln(-1)
-		}
-		pdf.SetX(left)
-		pdf.CellFormat(wSum, 0, "", "T", 0, "", false, 0, "")
-	}
-	// Colored table
-	fancyTable := func() {
-		// Colors, line width and bold font
-		pdf.SetFillColor(255, 0, 0)
-		pdf.SetTextColor(255, 255, 255)
-		pdf.SetDrawColor(128, 0, 0)
-		pdf.SetLineWidth(.3)
-		pdf.SetFont("", "B", 0)
-		// 	Header
-		w := []float64{40, 35, 40, 45}
-		wSum := 0.0
-		for _, v := range w {
-			wSum += v
-		}
-		left := (210 - wSum) / 2
-		pdf.SetX(left)
-		for j, str := range header {
-			pdf.CellFormat(w[j], 7, str, "1", 0, "C", true, 0, "")
-		}
-		pdf.Ln(-1)
-		// Color and font restoration
-		pdf.SetFillColor(224, 235, 255)
-		pdf.SetTextColor(0, 0, 0)
-		pdf.SetFont("", "", 0)
-		// 	Data
-		fill := false
-		for _, c := range countryList {
-			pdf.SetX(left)
-			pdf.CellFormat(w[0], 6, c.nameStr, "LR", 0, "", fill, 0, "")
-			pdf.CellFormat(w[1], 6, c.capitalStr, "LR", 0, "", fill, 0, "")
-			pdf.CellFormat(w[2], 6, strDelimit(c.areaStr, ",", 3),
-				"LR", 0, "R", fill, 0, "")
-			pdf.CellFormat(w[3], 6, strDelimit(c.popStr, ",", 3),
-				"LR", 0, "R", fill, 0, "")
-			pdf.Ln(-1)
-			fill = !fill
-		}
-		pdf.SetX(left)
-		pdf.CellFormat(wSum, 0, "", "T", 0, "", false, 0, "")
-	}
-	loadData(example.TextFile("countries.txt"))
-	pdf.SetFont("Arial", "", 14)
-	pdf.AddPage()
-	basicTable()
-	pdf.AddPage()
-	improvedTable()
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
 	pdf.AddPage()
-	fancyTable()
-	fileStr := example.Filename("Fpdf_CellFormat_tables")
+	pdf.SetFont("Times", "", 12)
+	dc := gofpdf.NewDropCap()
+	dc.FontStyle = "B"
+	dc.Clr = gofpdf.RGBType{R: 150, G: 20, B: 20}
+	pdf.MultiCellDropCap(0, 5.5, strings.Repeat(lorem()+" ", 3), "J", dc)
+	pdf.Ln(5)
+	pdf.SetFont("dejavu", "", 11)
+	dc = gofpdf.NewDropCap()
+	dc.Lines = 2
+	pdf.MultiCellDropCap(100, 5, "Ωραίο είναι να γράφεις ελληνικά με ένα "+
+		"μεγάλο αρχικό γράμμα στην αρχή της παραγράφου, όπως στα παλιά βιβλία.", "L", dc)
+	fileStr := example.Filename("Fpdf_MultiCellDropCap")
 	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_CellFormat_tables.pdf
+	// Successfully generated pdf/Fpdf_MultiCellDropCap.pdf
 }
 
-// ExampleFpdf_HTMLBasicNew demonstrates internal and external links with and without basic
-// HTML.
-func ExampleFpdf_HTMLBasicNew() {
+// ExampleFpdf_ImageFloat demonstrates text that flows around floated images,
+// first with MultiCell() and then with Write().
+func ExampleFpdf_ImageFloat() {
 	pdf := gofpdf.New("P", "mm", "A4", "")
-	// First page: manual local link
 	pdf.AddPage()
-	pdf.SetFont("Helvetica", "", 20)
-	_, lineHt := pdf.GetFontSize()
-	pdf.Write(lineHt, "To find out what's new in this tutorial, click ")
-	pdf.SetFont("", "U", 0)
-	link := pdf.AddLink()
-	pdf.WriteLinkID(lineHt, "here", link)
-	pdf.SetFont("", "", 0)
-	// Second page: image link and basic HTML with link
-	pdf.AddPage()
-	pdf.SetLink(link, 0, -1)
-	pdf.Image(example.ImageFile("logo.png"), 10, 12, 30, 0, false, "", 0, "http://www.fpdf.org")
-	pdf.SetLeftMargin(45)
-	pdf.SetFontSize(14)
-	_, lineHt = pdf.GetFontSize()
-	htmlStr := `You can now easily print text mixing different styles: <b>bold</b>, ` +
-		`<i>italic</i>, <u>underlined</u>, or <b><i><u>all at once</u></i></b>!<br><br>` +
-		`<center>You can also center text.</center>` +
-		`<right>Or align it to the right.</right>` +
-		`You can also insert links on text, such as ` +
-		`<a href="http://www.fpdf.org">www.fpdf.org</a>, or on an image: click on the logo.`
-	html := pdf.HTMLBasicNew()
-	html.Write(lineHt, htmlStr)
-	fileStr := example.Filename("Fpdf_HTMLBasicNew")
+	pdf.SetFont("Times", "", 11)
+	pdf.ImageFloat(example.ImageFile("golang-gopher.png"), "L", 40, 0, 4, gofpdf.ImageOptions{})
+	pdf.MultiCell(0, 5, strings.Repeat(lorem()+" ", 4), "", "J", false)
+	pdf.ClearFloats()
+	pdf.Ln(5)
+	pdf.ImageFloat(example.ImageFile("logo.png"), "R", 35, 0, 4, gofpdf.ImageOptions{})
+	pdf.SetFont("Helvetica", "", 10)
+	for j := 0; j < 3; j++ {
+		pdf.Write(5, lorem()+" ")
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.Write(5, "Write() wraps around the logo as well. ")
+		pdf.SetFont("Helvetica", "", 10)
+	}
+	fileStr := example.Filename("Fpdf_ImageFloat")
 	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_HTMLBasicNew.pdf
+	// Successfully generated pdf/Fpdf_ImageFloat.pdf
 }
 
-// ExampleFpdf_AddFont demonstrates the use of a non-standard font.
-func ExampleFpdf_AddFont() {
-	pdf := gofpdf.New("P", "mm", "A4", example.FontDir())
-	pdf.AddFont("Calligrapher", "", "calligra.json")
+// ExampleFpdf_PourText demonstrates styled text poured through a chain of
+// frames: a wide introduction followed by two columns that continue on as
+// many pages as needed.
+func ExampleFpdf_PourText() {
+	pdf := gofpdf.New("P", "mm", "A5", "")
+	pdf.SetFont("Times", "", 10)
 	pdf.AddPage()
-	pdf.SetFont("Calligrapher", "", 35)
-	pdf.Cell(0, 10, "Enjoy new fonts with FPDF!")
-	fileStr := example.Filename("Fpdf_AddFont")
+	pdf.DefineFrame("intro", 10, 10, 128, 30, "left")
+	pdf.DefineFrame("left", 10, 45, 61, 150, "right")
+	pdf.DefineFrame("right", 77, 45, 61, 150, "left")
+	var runs []gofpdf.TextRunType
+	runs = append(runs, gofpdf.TextRunType{Str: "Frames and chains\n", FontFamily: "Helvetica",
+		FontStyle: "B", FontSize: 16, Clr: gofpdf.RGBType{R: 0, G: 60, B: 140}})
+	for j := 0; j < 10; j++ {
+		runs = append(runs,
+			gofpdf.TextRunType{Str: "Lorem ipsum ", FontStyle: "B"},
+			gofpdf.TextRunType{Str: "dolor sit amet, ", FontStyle: "I", Clr: gofpdf.RGBType{R: 150}},
+			gofpdf.TextRunType{Str: strings.Join(loremList()[1:], " ") + "\n"})
+	}
+	rest := pdf.PourText("intro", 0, "J", runs)
+	fmt.Printf("Pages: %d, text left over: %v\n", pdf.PageCount(), len(rest) > 0)
+	fileStr := example.Filename("Fpdf_PourText")
 	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_AddFont.pdf
+	// Pages: 2, text left over: false
+	// Successfully generated pdf/Fpdf_PourText.pdf
 }
 
-// ExampleFpdf_WriteAligned demonstrates how to align text with the Write function.
-func ExampleFpdf_WriteAligned() {
-	pdf := gofpdf.New("P", "mm", "A4", example.FontDir())
-	pdf.SetLeftMargin(50.0)
-	pdf.SetRightMargin(50.0)
-	pdf.AddPage()
-	pdf.SetFont("Helvetica", "", 12)
-	pdf.WriteAligned(0, 35, "This text is the default alignment, Left", "")
-	pdf.Ln(35)
-	pdf.WriteAligned(0, 35, "This text is aligned Left", "L")
-	pdf.Ln(35)
-	pdf.WriteAligned(0, 35, "This text is aligned Center", "C")
-	pdf.Ln(35)
-	pdf.WriteAligned(0, 35, "This text is aligned Right", "R")
-	pdf.Ln(35)
-	line := "This can by used to write justified text"
-	leftMargin, _, rightMargin, _ := pdf.GetMargins()
-	pageWidth, _ := pdf.GetPageSize()
-	pageWidth -= leftMargin + rightMargin
-	pdf.SetWordSpacing((pageWidth - pdf.GetStringWidth(line)) / float64(strings.Count(line, " ")))
-	pdf.WriteAligned(pageWidth, 35, line, "L")
-	fileStr := example.Filename("Fpdf_WriteAligned")
-	err := pdf.OutputFileAndClose(fileStr)
-	example.Summary(err, fileStr)
+// ExampleRenderDocument demonstrates the rendering of an invoice from a JSON
+// document description and data supplied by a Go program.
+func ExampleRenderDocument() {
+	descStr := `{
+	"margins": [20, 20, 20],
+	"styles": {
+		"default": {"fontFamily": "Helvetica", "fontSize": 10, "lineHeight": 5.5},
+		"title": {"fontSize": 20, "fontStyle": "B", "color": "#1f4e79"},
+		"head": {"fontStyle": "B", "color": "#ffffff", "fill": "#1f4e79", "draw": "#1f4e79"},
+		"small": {"fontSize": 8, "color": "#808080", "align": "C"}
+	},
+	"footer": [
+		{"type": "text", "style": "small", "y": -15, "text": "Page {{$page}} of {{$pages}}"}
+	],
+	"pages": [{
+		"blocks": [
+			{"type": "text", "style": "title", "text": "Invoice {{number}}"},
+			{"type": "line"},
+			{"type": "space", "h": 4},
+			{"type": "text", "text": "Bill to: {{customer.name}}\n{{customer.city}}"},
+			{"type": "space", "h": 6},
+			{"type": "table", "rows": "{{items}}", "headerStyle": "head", "columns": [
+				{"header": "Item", "field": "name"},
+				{"header": "Qty", "field": "qty", "w": 20, "align": "R"},
+				{"header": "Price", "field": "price", "w": 30, "align": "R"}
+			]},
+			{"type": "space", "h": 6},
+			{"type": "text", "style": "title", "text": "Total: {{total}} \u20ac"}
+		]
+	}]
+}`
+	type itemType struct {
+		Name  string  `json:"name"`
+		Qty   int     `json:"qty"`
+		Price float64 `json:"price"`
+	}
+	data := map[string]interface{}{
+		"number":   "2024-0117",
+		"customer": map[string]string{"name": "Ana Ruiz", "city": "Córdoba"},
+		"items": []itemType{
+			{"Hexagonal bolts, box of 100", 4, 12.5},
+			{"Washers, box of 200", 2, 6.75},
+			{"Thread sealing tape", 10, 1.2},
+		},
+		"total": "75.50",
+	}
+	doc, err := gofpdf.ParseDocument(strings.NewReader(descStr))
+	if err == nil {
+		pdf := gofpdf.RenderDocument(doc, data)
+		fileStr := example.Filename("RenderDocument")
+		err = pdf.OutputFileAndClose(fileStr)
+		example.Summary(err, fileStr)
+	} else {
+		fmt.Println(err)
+	}
 	// Output:
-	// Successfully generated pdf/Fpdf_WriteAligned.pdf
+	// Successfully generated pdf/RenderDocument.pdf
 }
 
-// ExampleFpdf_Image demonstrates how images are included in documents.
-func ExampleFpdf_Image() {
-	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.AddPage()
-	pdf.SetFont("Arial", "", 11)
-	pdf.Image(example.ImageFile("logo.png"), 10, 10, 30, 0, false, "", 0, "")
-	pdf.Text(50, 20, "logo.png")
-	pdf.Image(example.ImageFile("logo.gif"), 10, 40, 30, 0, false, "", 0, "")
-	pdf.Text(50, 50, "logo.gif")
-	pdf.Image(example.ImageFile("logo-gray.png"), 10, 70, 30, 0, false, "", 0, "")
-	pdf.Text(50, 80, "logo-gray.png")
-	pdf.Image(example.ImageFile("logo-rgb.png"), 10, 100, 30, 0, false, "", 0, "")
-	pdf.Text(50, 110, "logo-rgb.png")
-	pdf.Image(example.ImageFile("logo.jpg"), 10, 130, 30, 0, false, "", 0, "")
-	pdf.Text(50, 140, "logo.jpg")
-	fileStr := example.Filename("Fpdf_Image")
+// ExampleGetLocale demonstrates the formatting of numbers, amounts of money
+// and dates for readers in different countries.
+func ExampleGetLocale() {
+	tm := time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)
+	for _, tagStr := range []string{"en-US", "de-DE", "pt-BR", "ja"} {
+		loc, ok := gofpdf.GetLocale(tagStr)
+		if ok {
+			fmt.Printf("%s %q %q %q %q\n", loc.Tag, loc.FormatNumber(-1234567.891, 2),
+				loc.FormatCurrency(1234.5, "EUR"), loc.FormatCurrency(1234.5, "JPY"),
+				loc.FormatDate(tm, "long"))
+		}
+	}
+	// Output:
+	// en-US "-1,234,567.89" "€1,234.50" "¥1,235" "March 5, 2024"
+	// de-DE "-1.234.567,89" "1.234,50\u00a0€" "1.235\u00a0¥" "5. März 2024"
+	// pt-BR "-1.234.567,89" "€\u00a01.234,50" "¥\u00a01.235" "5 de março de 2024"
+	// ja-JP "-1,234,567.89" "€1,234.50" "¥1,235" "2024年3月5日"
+}
+
+// ExampleRenderDocument_locale demonstrates formatted bindings and table
+// columns in a document description for German readers.
+func ExampleRenderDocument_locale() {
+	descStr := `{
+	"locale": "de-DE",
+	"styles": {"default": {"fontFamily": "Helvetica", "fontSize": 11}},
+	"pages": [{"blocks": [
+		{"type": "text", "text": "Rechnungsdatum: {{date|date:long}}"},
+		{"type": "space", "h": 4},
+		{"type": "table", "rows": "{{items}}", "columns": [
+			{"header": "Artikel", "field": "name"},
+			{"header": "Menge", "field": "qty", "w": 25, "align": "R", "format": "number:0"},
+			{"header": "Preis", "field": "price", "w": 35, "align": "R", "format": "currency:EUR"}
+		]},
+		{"type": "space", "h": 4},
+		{"type": "text", "text": "Summe: {{total|currency:EUR}}"}
+	]}]
+}`
+	data := map[string]interface{}{
+		"date": "2024-03-05",
+		"items": []map[string]interface{}{
+			{"name": "Schrauben", "qty": 1200, "price": 84},
+			{"name": "Dichtband", "qty": 10, "price": 12.5},
+		},
+		"total": 96.5,
+	}
+	doc, err := gofpdf.ParseDocument(strings.NewReader(descStr))
+	if err == nil {
+		pdf := gofpdf.RenderDocument(doc, data)
+		fileStr := example.Filename("RenderDocument_locale")
+		err = pdf.OutputFileAndClose(fileStr)
+		example.Summary(err, fileStr)
+	} else {
+		fmt.Println(err)
+	}
+	// Output:
+	// Successfully generated pdf/RenderDocument_locale.pdf
+}
+
+// ExampleRenderDocument_rtl demonstrates a document written from right to
+// left. The columns of the table appear in the reverse of the order in which
+// they are described, so that the first column is on the right, and text is
+// aligned on the right without being given an alignment.
+func ExampleRenderDocument_rtl() {
+	descStr := `{
+	"rtl": true,
+	"fonts": [
+		{"family": "dejavu", "file": "DejaVuSansCondensed.ttf", "utf8": true},
+		{"family": "dejavu", "style": "B", "file": "DejaVuSansCondensed-Bold.ttf", "utf8": true}
+	],
+	"styles": {"default": {"fontFamily": "dejavu", "fontSize": 11}},
+	"pages": [{"blocks": [
+		{"type": "text", "text": "חשבונית מספר {{number}}"},
+		{"type": "space", "h": 4},
+		{"type": "table", "rows": "{{items}}", "columns": [
+			{"header": "פריט", "field": "name"},
+			{"header": "כמות", "field": "qty", "w": 25, "align": "L"},
+			{"header": "מחיר", "field": "price", "w": 35, "align": "L"}
+		]}
+	]}]
+}`
+	data := map[string]interface{}{
+		"number": "117",
+		"items": []map[string]interface{}{
+			{"name": "ברגים", "qty": 1200, "price": 84},
+			{"name": "סרט איטום", "qty": 10, "price": 12.5},
+		},
+	}
+	doc, err := gofpdf.ParseDocument(strings.NewReader(descStr))
+	if err == nil {
+		doc.FontDir = example.FontDir()
+		pdf := gofpdf.RenderDocument(doc, data)
+		fileStr := example.Filename("RenderDocument_rtl")
+		err = pdf.OutputFileAndClose(fileStr)
+		example.Summary(err, fileStr)
+	} else {
+		fmt.Println(err)
+	}
+	// Output:
+	// Successfully generated pdf/RenderDocument_rtl.pdf
+}
+
+// ExampleFpdf_ListItem demonstrates bulleted and numbered lists. The same
+// lists are printed in left-to-right mode and in right-to-left mode, where
+// the bullets are placed on the right of the text and the text is aligned on
+// the right. The last list is printed in right-to-left mode with mirroring
+// turned off.
+func ExampleFpdf_ListItem() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.SetFont("dejavu", "", 12)
+	pdf.AddPage()
+	// The first item of each list contains a nested numbered list
+	list := func(itemList, nestedList []string) {
+		for j, itemStr := range itemList {
+			pdf.ListItem(8, 6, "•", itemStr, "")
+			if j == 0 {
+				pdf.SetX(pdf.GetX() + 8)
+				for k, nestedStr := range nestedList {
+					pdf.ListItem(8, 6, fmt.Sprintf("%d.", k+1), nestedStr, "")
+				}
+				pdf.SetX(pdf.GetX() - 8)
+			}
+		}
+		pdf.Ln(6)
+	}
+	list([]string{"Bullets are printed in a column of their own",
+		"The lines of an item are aligned with one another, not with the bullet"},
+		[]string{"A nested item that is long enough to be wrapped onto a second line of the list",
+			"Another nested item"})
+	hebrewList := []string{"פריט ראשון ברשימה", "פריט שני ארוך מספיק כדי להימשך אל השורה הבאה של הרשימה, כך שהשורות שלו מיושרות זו עם זו"}
+	nestedList := []string{"פריט מקונן", "פריט מקונן נוסף"}
+	pdf.RTL()
+	list(hebrewList, nestedList)
+	pdf.SetRTLMirroring(false)
+	list(hebrewList, nestedList)
+	fileStr := example.Filename("Fpdf_ListItem")
 	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_Image.pdf
+	// Successfully generated pdf/Fpdf_ListItem.pdf
 }
 
-// ExampleFpdf_ImageOptions demonstrates how the AllowNegativePosition field of the
-// ImageOption struct can be used to affect horizontal image placement.
-func ExampleFpdf_ImageOptions() {
-	var opt gofpdf.ImageOptions
-
+// ExampleFpdf_NewTable demonstrates the table layout engine. The cells wrap
+// their text, the region names span the rows of their cities, every other row
+// is striped and the header is repeated at the top of the second page.
+func ExampleFpdf_NewTable() {
 	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "", 10)
 	pdf.AddPage()
-	pdf.SetFont("Arial", "", 11)
-	pdf.SetX(60)
-	opt.ImageType = "png"
-	pdf.ImageOptions(example.ImageFile("logo.png"), -10, 10, 30, 0, false, opt, 0, "")
-	opt.AllowNegativePosition = true
-	pdf.ImageOptions(example.ImageFile("logo.png"), -10, 50, 30, 0, false, opt, 0, "")
-	fileStr := example.Filename("Fpdf_ImageOptions")
+	pdf.SetY(200)
+	tbl := pdf.NewTable([]gofpdf.TableColumnType{
+		{Header: "Region", W: 35},
+		{Header: "City", W: 35},
+		{Header: "Population", W: 30, Align: "R"},
+		{Header: "Notes"},
+	})
+	regionList := []struct {
+		nameStr  string
+		cityList [][3]string
+	}{
+		{"North", [][3]string{
+			{"Alder", "12,400", "Market town on the river, known for its stone bridge and weekly fair"},
+			{"Birchwood", "3,150", ""},
+			{"Cedar Falls", "48,900", "Regional hospital and technical college"},
+		}},
+		{"Lakeside District with a long name", [][3]string{
+			{"Dunmore", "7,800", "Ferry terminal"},
+			{"Elmstead", "1,020", "Smallest municipality of the district"},
+		}},
+		{"South", [][3]string{
+			{"Fairhaven", "95,300", "Seaport, shipyards and the largest fish market on the coast; the old town is a protected historic site"},
+			{"Glenford", "22,750", "Vineyards"},
+			{"Holloway", "5,600", ""},
+			{"Ivybridge", "14,200", "Railway junction"},
+		}},
+	}
+	for j := 0; j < 3; j++ {
+		for _, region := range regionList {
+			for k, city := range region.cityList {
+				var cells []gofpdf.TableCellType
+				if k == 0 {
+					cells = append(cells, gofpdf.TableCellType{Str: region.nameStr, RowSpan: len(region.cityList)})
+				}
+				for _, str := range city {
+					cells = append(cells, gofpdf.TableCellType{Str: str})
+				}
+				tbl.RowCells(cells...)
+			}
+		}
+	}
+	tbl.End()
+	fileStr := example.Filename("Fpdf_NewTable")
 	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_ImageOptions.pdf
+	// Successfully generated pdf/Fpdf_NewTable.pdf
 }
 
-// ExampleFpdf_RegisterImageOptionsReader demonstrates how to load an image
-// from a io.Reader (in this case, a file) and register it with options.
-func ExampleFpdf_RegisterImageOptionsReader() {
-	var (
-		opt    gofpdf.ImageOptions
-		pdfStr string
-		fl     *os.File
-		err    error
-	)
+// ExampleFpdf_QRCode demonstrates the barcodes that are drawn with vector
+// rectangles: a shipping label carries a QR code with the address and
+// tracking link, a Code 128 barcode with the tracking number and an EAN-13
+// barcode for the article.
+func ExampleFpdf_QRCode() {
+	pdf := gofpdf.New("P", "mm", "A6", "")
+	pdf.SetMargins(8, 8, 8)
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.Cell(0, 8, "PRIORITY")
+	pdf.Ln(10)
+	pdf.SetFont("Helvetica", "", 10)
+	addrStr := "Ana Ruiz\n14 Harbour Road\nFairhaven FH2 9QT"
+	pdf.MultiCell(52, 5, addrStr, "", "L", false)
+	pdf.QRCode(68, 20, 28, "https://example.com/track/1Z999AA10123456784\n"+addrStr, "M")
+	trackStr := "1Z999AA10123456784"
+	pdf.Barcode128(10, 60, 85, 18, trackStr)
+	pdf.SetXY(10, 79)
+	pdf.CellFormat(85, 5, trackStr, "", 1, "C", false, 0, "")
+	pdf.BarcodeEAN13(10, 95, 40, 16, "400638133393")
+	pdf.SetXY(10, 112)
+	pdf.CellFormat(40, 5, "4006381333931", "", 1, "C", false, 0, "")
+	fileStr := example.Filename("Fpdf_QRCode")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_QRCode.pdf
+}
 
-	pdfStr = example.Filename("Fpdf_RegisterImageOptionsReader")
+// ExampleFpdf_SetDecimalTab demonstrates the alignment of numbers on their
+// decimal separator. Right alignment lines up the last digits of the values
+// in the first column, whether these are tenths or thousandths; the second
+// column aligns the same values on the decimal point. The third column aligns
+// amounts formatted for German readers on the decimal comma.
+func ExampleFpdf_SetDecimalTab() {
 	pdf := gofpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
 	pdf.AddPage()
-	pdf.SetFont("Arial", "", 11)
-	fl, err = os.Open(example.ImageFile("logo.png"))
+	pdf.SetFont("Helvetica", "", 12)
+	de, _ := gofpdf.GetLocale("de-DE")
+	values := []float64{1234.5, 7, 98.765, 0.25, 15000}
+	var plain, amounts []string
+	for _, v := range values {
+		plain = append(plain, strconv.FormatFloat(v, 'f', -1, 64))
+		amounts = append(amounts, tr(de.FormatCurrency(v, "EUR")))
+	}
+	pdf.SetFont("Helvetica", "B", 12)
+	for _, str := range []string{"Right", "Decimal", "Betrag"} {
+		pdf.CellFormat(40, 8, str, "1", 0, "C", false, 0, "")
+	}
+	pdf.Ln(-1)
+	pdf.SetFont("Helvetica", "", 12)
+	plainWd := pdf.GetDecimalFracWidth(".", plain...)
+	amountWd := pdf.GetDecimalFracWidth(de.DecimalSep, amounts...)
+	for j := range values {
+		pdf.CellFormat(40, 7, plain[j], "LR", 0, "R", false, 0, "")
+		pdf.SetDecimalTab(".", plainWd)
+		pdf.CellFormat(40, 7, plain[j], "LR", 0, "D", false, 0, "")
+		pdf.SetDecimalTab(de.DecimalSep, amountWd)
+		pdf.CellFormat(40, 7, amounts[j], "LR", 1, "D", false, 0, "")
+	}
+	pdf.CellFormat(120, 0, "", "T", 1, "", false, 0, "")
+	fileStr := example.Filename("Fpdf_SetDecimalTab")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetDecimalTab.pdf
+}
+
+// ExampleTransformText demonstrates the case transforms that document
+// styles apply to headings. The Turkish locale keeps the dot of the i, and
+// German ß is written as SS in capitals.
+func ExampleTransformText() {
+	for _, tst := range []struct{ s, transformStr, langStr string }{
+		{"istanbul'da ılık bir gün", gofpdf.TransformUpper, "tr-TR"},
+		{"istanbul'da ılık bir gün", gofpdf.TransformUpper, "en-US"},
+		{"İSTANBUL IRMAĞI", gofpdf.TransformLower, "tr"},
+		{"Straßenverkehr", gofpdf.TransformUpper, "de-DE"},
+		{"ΟΔΥΣΣΕΥΣ", gofpdf.TransformLower, "el"},
+		{"it's the PDF reference", gofpdf.TransformTitle, "en"},
+		{"ijsselmeer en ijmuiden", gofpdf.TransformTitle, "nl-NL"},
+	} {
+		str, err := gofpdf.TransformText(tst.s, tst.transformStr, tst.langStr)
+		if err != nil {
+			fmt.Println(err)
+		}
+		fmt.Println(str)
+	}
+	// Output:
+	// İSTANBUL'DA ILIK BİR GÜN
+	// ISTANBUL'DA ILIK BIR GÜN
+	// istanbul ırmağı
+	// STRASSENVERKEHR
+	// οδυσσευς
+	// It's The PDF Reference
+	// IJsselmeer En IJmuiden
+}
+
+// ExampleDocTemplateFuncs demonstrates the generation of a document
+// description with text/template. Values from the data are escaped by the
+// template functions, so the braces and quotes in the note are printed as
+// they are.
+func ExampleDocTemplateFuncs() {
+	styles := map[string]gofpdf.DocStyleType{
+		"default": {FontFamily: "Helvetica", FontSize: 11},
+		"heading": {FontSize: 16, FontStyle: "B"},
+		"urgent":  {FontStyle: "B", Color: "#c00000"},
+	}
+	const descStr = `{
+	"styles": {{pdfstyles}},
+	"pages": [{"blocks": [
+		{"type": "text", "style": "heading", "text": {{pdfjson .Title}}},
+		{"type": "space", "h": {{pdflength "8pt"}}},
+{{- range .Tasks}}
+		{"type": "text", "style": "{{pdfstyle .Priority "default"}}", "text": "- {{pdftext .Name}}"},
+{{- end}}
+		{"type": "space", "h": {{pdflength "0.25in"}}},
+		{"type": "text", "text": "Note: {{pdftext .Note}}"}
+	]}]
+}`
+	tmpl, err := template.New("tasks").Funcs(gofpdf.DocTemplateFuncs("mm", styles)).Parse(descStr)
 	if err == nil {
-		opt.ImageType = "png"
-		opt.AllowNegativePosition = true
-		_ = pdf.RegisterImageOptionsReader("logo", opt, fl)
-		fl.Close()
-		for x := -20.0; x <= 40.0; x += 5 {
-			pdf.ImageOptions("logo", x, x+30, 0, 0, false, opt, 0, "")
+		data := map[string]interface{}{
+			"Title": "Weekly tasks",
+			"Tasks": []map[string]string{
+				{"Name": "Renew \"Example.org\" domain", "Priority": "urgent"},
+				{"Name": "Order toner", "Priority": "normal"},
+			},
+			"Note": "Write {{placeholders}} in full.",
 		}
-		err = pdf.OutputFileAndClose(pdfStr)
+		pdf := gofpdf.RenderDocumentTemplate(tmpl, data)
+		fileStr := example.Filename("DocTemplateFuncs")
+		err = pdf.OutputFileAndClose(fileStr)
+		example.Summary(err, fileStr)
+	} else {
+		fmt.Println(err)
 	}
-	example.Summary(err, pdfStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_RegisterImageOptionsReader.pdf
+	// Successfully generated pdf/DocTemplateFuncs.pdf
 }
 
-// This example demonstrates Landscape mode with images.
-func ExampleFpdf_SetAcceptPageBreakFunc() {
-	var y0 float64
-	var crrntCol int
-	loremStr := lorem()
-	pdf := gofpdf.New("L", "mm", "A4", "")
-	const (
-		pageWd = 297.0 // A4 210.0 x 297.0
-		margin = 10.0
-		gutter = 4
-		colNum = 3
-		colWd  = (pageWd - 2*margin - (colNum-1)*gutter) / colNum
-	)
-	setCol := func(col int) {
-		crrntCol = col
-		x := margin + float64(col)*(colWd+gutter)
-		pdf.SetLeftMargin(x)
-		pdf.SetX(x)
+// ExampleFpdf_SetPDFX demonstrates the preparation of a PDF/X-1a document
+// for a commercial printer. The page includes a 3 mm bleed on each side;
+// the colored band extends into it so that no white edge remains after
+// trimming. A document that uses a core font is rejected when it is closed.
+func ExampleFpdf_SetPDFX() {
+	pdfx := gofpdf.PDFXType{
+		Version: gofpdf.PDFX1a,
+		Intent: gofpdf.OutputIntentType{
+			ConditionID: "FOGRA39",
+			Condition:   "Offset printing on coated paper",
+		},
+		Bleed: 3,
 	}
-	pdf.SetHeaderFunc(func() {
-		titleStr := "gofpdf"
-		pdf.SetFont("Helvetica", "B", 48)
-		wd := pdf.GetStringWidth(titleStr) + 6
-		pdf.SetX((pageWd - wd) / 2)
-		pdf.SetTextColor(128, 128, 160)
-		pdf.Write(12, titleStr[:2])
-		pdf.SetTextColor(128, 128, 128)
-		pdf.Write(12, titleStr[2:])
-		pdf.Ln(20)
-		y0 = pdf.GetY()
+	pdf := gofpdf.NewCustom(&gofpdf.InitType{
+		UnitStr:    "mm",
+		Size:       gofpdf.SizeType{Wd: 105 + 6, Ht: 148 + 6},
+		FontDirStr: example.FontDir(),
 	})
-	pdf.SetAcceptPageBreakFunc(func() bool {
-		if crrntCol < colNum-1 {
-			setCol(crrntCol + 1)
-			pdf.SetY(y0)
-			// Start new column, not new page
-			return false
-		}
-		setCol(0)
-		return true
+	pdf.SetPDFX(pdfx)
+	pdf.SetTitle("Postcard", false)
+	pdf.AddUTF8Font("dejavu", "", "DejaVuSansCondensed.ttf")
+	pdf.AddSpotColor("PANTONE 185 C", 0, 91, 76, 0)
+	pdf.SetMargins(13, 13, 13)
+	pdf.AddPage()
+	pdf.SetFillSpotColor("PANTONE 185 C", 100)
+	pdf.Rect(0, 0, 111, 40, "F")
+	pdf.SetFont("dejavu", "", 12)
+	pdf.SetY(50)
+	pdf.MultiCell(0, 6, "Greetings from the print shop. Every font in this "+
+		"document is embedded and all colors are gray or spot colors.", "", "L", false)
+	fileStr := example.Filename("Fpdf_SetPDFX")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+
+	pdf = gofpdf.New("P", "mm", "A6", "")
+	pdf.SetPDFX(pdfx)
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.Cell(0, 10, "Not embedded")
+	err = pdf.Output(ioutil.Discard)
+	fmt.Println(err)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetPDFX.pdf
+	// document does not conform to PDF/X-1a:2001: core font Helvetica is not embedded
+}
+
+// ExampleFpdf_SetPrintTicket demonstrates a document that is sent directly
+// to a production printer. The viewer's print dialog defaults to two-sided
+// printing of three collated copies, and a JDF job ticket attached to the
+// document describes the job and its media to the print server.
+func ExampleFpdf_SetPrintTicket() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle("Course handbook", false)
+	pdf.SetPrintTicket(gofpdf.PrintTicketType{
+		Copies:      3,
+		Duplex:      gofpdf.DuplexLongEdge,
+		Collate:     true,
+		MediaName:   "A4 uncoated",
+		MediaType:   "Paper",
+		MediaWeight: 90,
+		MediaColor:  "White",
+		NoScaling:   true,
+		JDF:         true,
+		FileStr:     "handbook.pdf",
 	})
+	pdf.SetFont("Helvetica", "", 14)
+	for j := 1; j <= 4; j++ {
+		pdf.AddPage()
+		pdf.Cell(0, 10, fmt.Sprintf("Chapter %d", j))
+	}
+	fileStr := example.Filename("Fpdf_SetPrintTicket")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetPrintTicket.pdf
+}
+
+// ExampleFpdf_SetAutoTextContrast demonstrates cells whose fill colors are
+// chosen by data and whose text color follows automatically. The last column
+// sets its text color explicitly after the fill color.
+func ExampleFpdf_SetAutoTextContrast() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "B", 11)
 	pdf.AddPage()
-	pdf.SetFont("Times", "", 12)
-	for j := 0; j < 20; j++ {
-		if j == 1 {
-			pdf.Image(example.ImageFile("fpdf.png"), -1, 0, colWd, 0, true, "", 0, "")
-		} else if j == 5 {
-			pdf.Image(example.ImageFile("golang-gopher.png"),
-				-1, 0, colWd, 0, true, "", 0, "")
+	pdf.SetAutoTextContrast(true)
+	palette := [][3]int{{255, 255, 204}, {255, 237, 160}, {254, 178, 76},
+		{253, 141, 60}, {240, 59, 32}, {189, 0, 38}, {37, 52, 148}, {8, 29, 88}}
+	for row := 0; row < 8; row++ {
+		for col := 0; col < 6; col++ {
+			val := (row*7 + col*5) % 8
+			clr := palette[val]
+			pdf.SetFillColor(clr[0], clr[1], clr[2])
+			if col == 5 {
+				pdf.SetTextColor(128, 128, 128)
+			}
+			pdf.CellFormat(25, 10, strconv.Itoa(val*12), "", 0, "C", true, 0, "")
 		}
-		pdf.MultiCell(colWd, 5, loremStr, "", "", false)
 		pdf.Ln(-1)
 	}
-	fileStr := example.Filename("Fpdf_SetAcceptPageBreakFunc_landscape")
+	fileStr := example.Filename("Fpdf_SetAutoTextContrast")
 	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_SetAcceptPageBreakFunc_landscape.pdf
+	// Successfully generated pdf/Fpdf_SetAutoTextContrast.pdf
 }
 
-// This example tests corner cases as reported by the gocov tool.
-func ExampleFpdf_SetKeywords() {
-	var err error
-	fileStr := example.Filename("Fpdf_SetKeywords")
-	err = gofpdf.MakeFont(example.FontFile("CalligrapherRegular.pfb"),
-		example.FontFile("cp1252.map"), example.FontDir(), nil, true)
-	if err == nil {
-		pdf := gofpdf.New("", "", "", "")
-		pdf.SetFontLocation(example.FontDir())
-		pdf.SetTitle("世界", true)
-		pdf.SetAuthor("世界", true)
-		pdf.SetSubject("世界", true)
-		pdf.SetCreator("世界", true)
-		pdf.SetKeywords("世界", true)
-		pdf.AddFont("Calligrapher", "", "CalligrapherRegular.json")
-		pdf.AddPage()
-		pdf.SetFont("Calligrapher", "", 16)
-		pdf.Writef(5, "\x95 %s \x95", pdf)
-		err = pdf.OutputFileAndClose(fileStr)
+// ExampleFpdf_GetRegisteredFonts demonstrates the discovery of available
+// fonts and the suggestions offered when a font cannot be selected.
+func ExampleFpdf_GetRegisteredFonts() {
+	pdf := gofpdf.New("P", "mm", "A4", example.FontDir())
+	pdf.AddUTF8Font("dejavu", "", "DejaVuSansCondensed.ttf")
+	pdf.AddUTF8Font("dejavu", "B", "DejaVuSansCondensed-Bold.ttf")
+	for _, fnt := range pdf.GetRegisteredFonts() {
+		if fnt.Type != "Core" {
+			fmt.Printf("%s %q %s\n", fnt.Family, fnt.Style, fnt.Type)
+		}
+	}
+	for _, req := range [][2]string{{"Helvetika", "B"}, {"DejaVu", "I"}, {"ZapfDingbats", "B"}} {
+		pdf.SetFont(req[0], req[1], 12)
+		if fontErr, ok := pdf.Error().(*gofpdf.FontError); ok {
+			fmt.Println(fontErr.Reason, fontErr.Suggestions)
+		}
+		pdf.ClearError()
+	}
+	// Output:
+	// dejavu "" UTF8
+	// dejavu "B" UTF8
+	// the family has not been added [helvetica B]
+	// the style has not been added for the family [dejavu dejavu B]
+	// the font has no bold or italic style [zapfdingbats]
+}
+
+// ExampleFpdf_GetRegisteredImages demonstrates the listing of the fonts,
+// images and templates that a document uses, as might be done when debugging
+// the size of a generated file.
+func ExampleFpdf_GetRegisteredImages() {
+	pdf := gofpdf.New("P", "mm", "A4", example.FontDir())
+	pdf.AddUTF8Font("dejavu", "", "DejaVuSansCondensed.ttf")
+	pdf.AddPage()
+	pdf.SetFont("dejavu", "", 12)
+	pdf.Cell(0, 10, "Grüße aus Köln")
+	pdf.ImageOptions(example.ImageFile("logo.png"), 10, 30, 30, 0, false,
+		gofpdf.ImageOptions{ReadDpi: true}, 0, "")
+	tpl := pdf.CreateTemplate(func(tpl *gofpdf.Tpl) {
+		tpl.SetFont("Helvetica", "B", 10)
+		tpl.Text(5, 5, "Stamp")
+	})
+	pdf.UseTemplate(tpl)
+	for _, fnt := range pdf.GetRegisteredFonts() {
+		if fnt.Type != "Core" {
+			fmt.Printf("font %s: %d bytes, %d glyphs used\n", fnt.Family, fnt.FileSize, fnt.SubsetGlyphs)
+		}
+	}
+	for _, img := range pdf.GetRegisteredImages() {
+		fmt.Printf("image %s: %dx%d pixels, %s\n", filepath.Base(img.Name), img.Width,
+			img.Height, img.ColorSpace)
+	}
+	for _, t := range pdf.GetRegisteredTemplates() {
+		fmt.Printf("template: %d page(s), %.0f x %.0f mm\n", t.Pages, t.Size.Wd, t.Size.Ht)
 	}
+	fileStr := example.Filename("Fpdf_GetRegisteredImages")
+	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_SetKeywords.pdf
+	// font dejavu: 680264 bytes, 13 glyphs used
+	// image logo.png: 104x71 pixels, Indexed
+	// template: 1 page(s), 210 x 297 mm
+	// Successfully generated pdf/Fpdf_GetRegisteredImages.pdf
 }
 
-// ExampleFpdf_Circle demonstrates the construction of various geometric figures,
-func ExampleFpdf_Circle() {
-	const (
-		thin  = 0.2
-		thick = 3.0
-	)
-	pdf := gofpdf.New("", "", "", "")
-	pdf.SetFont("Helvetica", "", 12)
-	pdf.SetFillColor(200, 200, 220)
+// ExampleFpdf_LoadSystemFont demonstrates loading installed fonts by their
+// family name. The search is confined to the example font directory so that
+// the result does not depend on the fonts installed on the system.
+func ExampleFpdf_LoadSystemFont() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetSystemFontDirs(example.FontDir())
+	pdf.LoadSystemFont("DejaVu Sans Condensed", "")
+	pdf.LoadSystemFont("DejaVu Sans Condensed", "BI")
 	pdf.AddPage()
+	pdf.SetFont("DejaVu Sans Condensed", "", 16)
+	pdf.Cell(0, 10, "Καλημέρα κόσμε")
+	pdf.Ln(10)
+	pdf.SetFont("DejaVu Sans Condensed", "BI", 16)
+	pdf.Cell(0, 10, "Здравствуй, мир")
+	pdf.LoadSystemFont("DejaVu Sans", "")
+	if fontErr, ok := pdf.Error().(*gofpdf.FontError); ok {
+		fmt.Printf("%s: %q\n", fontErr.Reason, fontErr.Suggestions)
+		pdf.ClearError()
+	}
+	fileStr := example.Filename("Fpdf_LoadSystemFont")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// not found in the system font directories: ["DejaVu Sans Condensed" "DejaVu Sans Condensed B" "DejaVu Sans Condensed BI" "DejaVu Sans Condensed I"]
+	// Successfully generated pdf/Fpdf_LoadSystemFont.pdf
+}
 
-	y := 15.0
-	pdf.Text(10, y, "Circles")
-	pdf.SetFillColor(200, 200, 220)
-	pdf.SetLineWidth(thin)
-	pdf.Circle(20, y+15, 10, "D")
-	pdf.Circle(45, y+15, 10, "F")
-	pdf.Circle(70, y+15, 10, "FD")
-	pdf.SetLineWidth(thick)
-	pdf.Circle(95, y+15, 10, "FD")
-	pdf.SetLineWidth(thin)
+// ExampleFpdf_SetFontEmbedding demonstrates the effect of the font embedding
+// modes on the size of a document.
+func ExampleFpdf_SetFontEmbedding() {
+	size := func(embed gofpdf.FontEmbeddingType) int {
+		pdf := gofpdf.New("P", "mm", "A4", example.FontDir())
+		pdf.SetFontEmbedding("dejavu", "", embed)
+		pdf.AddUTF8Font("dejavu", "", "DejaVuSansCondensed.ttf")
+		pdf.AddPage()
+		pdf.SetFont("dejavu", "", 14)
+		pdf.Cell(0, 10, "Font embedding")
+		var buf bytes.Buffer
+		err := pdf.Output(&buf)
+		if err != nil {
+			fmt.Println(err)
+		}
+		return buf.Len()
+	}
+	subset := size(gofpdf.FontEmbeddingType{})
+	full := size(gofpdf.FontEmbeddingType{Mode: gofpdf.FontEmbedFull})
+	none := size(gofpdf.FontEmbeddingType{Mode: gofpdf.FontEmbedNone, AcknowledgeNoEmbed: true})
+	fmt.Println(none < subset, subset < full/10)
+	pdf := gofpdf.New("P", "mm", "A4", example.FontDir())
+	pdf.SetFontEmbedding("dejavu", "", gofpdf.FontEmbeddingType{Mode: gofpdf.FontEmbedNone})
+	fmt.Println(pdf.Error())
+	// Output:
+	// true true
+	// font dejavu: omitting the font file requires AcknowledgeNoEmbed
+}
 
-	y += 40.0
-	pdf.Text(10, y, "Ellipses")
-	pdf.SetFillColor(220, 200, 200)
-	pdf.Ellipse(30, y+15, 20, 10, 0, "D")
-	pdf.Ellipse(75, y+15, 20, 10, 0, "F")
-	pdf.Ellipse(120, y+15, 20, 10, 0, "FD")
-	pdf.SetLineWidth(thick)
-	pdf.Ellipse(165, y+15, 20, 10, 0, "FD")
-	pdf.SetLineWidth(thin)
+// ExampleFpdf_AddUTF8FontFromBytes_error demonstrates that a font that
+// cannot be used is reported by the instance's error.
+func ExampleFpdf_AddUTF8FontFromBytes_error() {
+	buf, err := ioutil.ReadFile(example.ImageFile("logo.png"))
+	if err == nil {
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		pdf.AddUTF8FontFromBytes("logo", "", buf)
+		err = pdf.Error()
+	}
+	fmt.Println(err)
+	// Output:
+	// unable to use font logo: not a TrueType font: codeType=2303741511
+}
 
-	y += 40.0
-	pdf.Text(10, y, "Curves (quadratic)")
-	pdf.SetFillColor(220, 220, 200)
-	pdf.Curve(10, y+30, 15, y-20, 40, y+30, "D")
-	pdf.Curve(45, y+30, 50, y-20, 75, y+30, "F")
-	pdf.Curve(80, y+30, 85, y-20, 110, y+30, "FD")
-	pdf.SetLineWidth(thick)
-	pdf.Curve(115, y+30, 120, y-20, 145, y+30, "FD")
-	pdf.SetLineCapStyle("round")
-	pdf.Curve(150, y+30, 155, y-20, 180, y+30, "FD")
-	pdf.SetLineWidth(thin)
-	pdf.SetLineCapStyle("butt")
+// ExampleFpdf_WillFit demonstrates keeping each paragraph on a single page
+// by checking the remaining space before printing it.
+func ExampleFpdf_WillFit() {
+	const lineHt = 6
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Times", "", 12)
+	pdf.AddPage()
+	for j := 0; j < 12; j++ {
+		txtStr := fmt.Sprintf("%d. %s", j+1, strings.Repeat(lorem()+" ", j%3+1))
+		lines := pdf.SplitText(txtStr, 190)
+		ht := float64(len(lines)) * lineHt
+		if !pdf.WillFit(ht) {
+			pdf.AddPage()
+		}
+		pdf.MultiCell(190, lineHt, txtStr, "", "J", false)
+		pdf.Ln(lineHt)
+	}
+	fmt.Printf("%d pages, %.0f mm left on the last\n", pdf.PageCount(), pdf.SpaceLeft())
+	fileStr := example.Filename("Fpdf_WillFit")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// 3 pages, 3 mm left on the last
+	// Successfully generated pdf/Fpdf_WillFit.pdf
+}
 
-	y += 40.0
-	pdf.Text(10, y, "Curves (cubic)")
-	pdf.SetFillColor(220, 200, 220)
-	pdf.CurveBezierCubic(10, y+30, 15, y-20, 10, y+30, 40, y+30, "D")
-	pdf.CurveBezierCubic(45, y+30, 50, y-20, 45, y+30, 75, y+30, "F")
-	pdf.CurveBezierCubic(80, y+30, 85, y-20, 80, y+30, 110, y+30, "FD")
-	pdf.SetLineWidth(thick)
-	pdf.CurveBezierCubic(115, y+30, 120, y-20, 115, y+30, 145, y+30, "FD")
-	pdf.SetLineCapStyle("round")
-	pdf.CurveBezierCubic(150, y+30, 155, y-20, 150, y+30, 180, y+30, "FD")
-	pdf.SetLineWidth(thin)
-	pdf.SetLineCapStyle("butt")
+// ExampleFpdf_WithAutoPageBreak demonstrates printing a notice in the
+// bottom margin of the page without triggering a page break.
+func ExampleFpdf_WithAutoPageBreak() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Times", "", 12)
+	pdf.AddPage()
+	pdf.MultiCell(0, 6, lorem(), "", "J", false)
+	pdf.WithAutoPageBreak(false, 0, func() {
+		pdf.SetFillColor(230, 230, 230)
+		pdf.SetXY(10, 270)
+		pdf.MultiCell(0, 6, "This notice is printed in the bottom margin of the page, "+
+			"below the point at which an automatic page break is normally triggered.",
+			"1", "C", true)
+	})
+	auto, margin := pdf.GetAutoPageBreak()
+	fmt.Printf("%d page, auto page break %v, margin %.0f mm\n", pdf.PageCount(), auto, margin)
+	fileStr := example.Filename("Fpdf_WithAutoPageBreak")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// 1 page, auto page break true, margin 20 mm
+	// Successfully generated pdf/Fpdf_WithAutoPageBreak.pdf
+}
 
-	y += 40.0
-	pdf.Text(10, y, "Arcs")
-	pdf.SetFillColor(200, 220, 220)
-	pdf.SetLineWidth(thick)
-	pdf.Arc(45, y+35, 20, 10, 0, 0, 180, "FD")
-	pdf.SetLineWidth(thin)
-	pdf.Arc(45, y+35, 25, 15, 0, 90, 270, "D")
-	pdf.SetLineWidth(thick)
-	pdf.Arc(45, y+35, 30, 20, 0, 0, 360, "D")
-	pdf.SetLineCapStyle("round")
-	pdf.Arc(135, y+35, 20, 10, 135, 0, 180, "FD")
-	pdf.SetLineWidth(thin)
-	pdf.Arc(135, y+35, 25, 15, 135, 90, 270, "D")
-	pdf.SetLineWidth(thick)
-	pdf.Arc(135, y+35, 30, 20, 135, 0, 360, "D")
-	pdf.SetLineWidth(thin)
-	pdf.SetLineCapStyle("butt")
+// ExampleFpdf_SetAcceptPageBreakContextFunc demonstrates a two column layout
+// in which text that reaches the bottom of the first column continues in the
+// second, while an image that does not fit is moved to the next page.
+func ExampleFpdf_SetAcceptPageBreakContextFunc() {
+	const colWd, gutter = 90, 10
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Times", "", 12)
+	left, top, _, _ := pdf.GetMargins()
+	col := 0
+	setCol := func(n int) {
+		col = n
+		x := left + float64(n)*(colWd+gutter)
+		pdf.SetLeftMargin(x)
+		pdf.SetXY(x, top)
+	}
+	kinds := make(map[string]int)
+	pdf.SetAcceptPageBreakContextFunc(func(ctx gofpdf.PageBreakContextType) bool {
+		kinds[ctx.Kind]++
+		if ctx.Kind == gofpdf.PageBreakMultiCell && col == 0 {
+			setCol(1)
+			return false
+		}
+		setCol(0)
+		return true
+	})
+	pdf.AddPage()
+	for j := 0; j < 7; j++ {
+		pdf.MultiCell(colWd, 5, lorem(), "", "J", false)
+		pdf.Ln(5)
+		pdf.ImageOptions(example.ImageFile("logo.png"), -1, 0, colWd, 0, true,
+			gofpdf.ImageOptions{}, 0, "")
+		pdf.Ln(5)
+	}
+	fmt.Printf("%d pages; breaks in text %d, before images %d\n", pdf.PageCount(),
+		kinds[gofpdf.PageBreakMultiCell], kinds[gofpdf.PageBreakImage])
+	fileStr := example.Filename("Fpdf_SetAcceptPageBreakContextFunc")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// 3 pages; breaks in text 1, before images 2
+	// Successfully generated pdf/Fpdf_SetAcceptPageBreakContextFunc.pdf
+}
 
-	fileStr := example.Filename("Fpdf_Circle_figures")
+// ExampleFpdf_GetFontCIDMap demonstrates that the encoding of text printed in
+// a UTF-8 font does not depend on the other text in the document.
+func ExampleFpdf_GetFontCIDMap() {
+	cidOf := func(r rune, txtList ...string) int {
+		pdf := gofpdf.New("P", "mm", "A4", example.FontDir())
+		pdf.AddUTF8Font("dejavu", "", "DejaVuSansCondensed.ttf")
+		pdf.AddPage()
+		pdf.SetFont("dejavu", "", 14)
+		for _, txtStr := range txtList {
+			pdf.Cell(0, 10, txtStr)
+			pdf.Ln(-1)
+		}
+		return pdf.GetFontCIDMap("dejavu", "")[r]
+	}
+	fmt.Println(cidOf('Ж', "Жизнь"), cidOf('Ж', "Hello", "Жизнь"), cidOf('Ж', "Ωμέγα Жизнь"))
+	pdf := gofpdf.New("P", "mm", "A4", example.FontDir())
+	pdf.AddUTF8Font("dejavu", "", "DejaVuSansCondensed.ttf")
+	pdf.AddPage()
+	pdf.SetFont("dejavu", "", 14)
+	pdf.Write(8, "Text in the Basic Multilingual Plane, such as Жизнь, is encoded with "+
+		"the code points of its characters as CIDs, so that the document changes "+
+		"only where the text does.")
+	fileStr := example.Filename("Fpdf_GetFontCIDMap")
 	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_Circle_figures.pdf
+	// 1046 1046 1046
+	// Successfully generated pdf/Fpdf_GetFontCIDMap.pdf
 }
 
-// ExampleFpdf_SetAlpha demonstrates alpha transparency.
-func ExampleFpdf_SetAlpha() {
-	const (
-		gapX  = 10.0
-		gapY  = 9.0
-		rectW = 40.0
-		rectH = 58.0
-		pageW = 210
-		pageH = 297
-	)
-	modeList := []string{"Normal", "Multiply", "Screen", "Overlay",
-		"Darken", "Lighten", "ColorDodge", "ColorBurn", "HardLight", "SoftLight",
-		"Difference", "Exclusion", "Hue", "Saturation", "Color", "Luminosity"}
-	pdf := gofpdf.New("", "", "", "")
-	pdf.SetLineWidth(2)
-	pdf.SetAutoPageBreak(false, 0)
+// ExampleFpdf_GetGlyphOutline demonstrates the drawing of glyph outlines as
+// paths.
+func ExampleFpdf_GetGlyphOutline() {
+	pdf := gofpdf.New("P", "mm", "A4", example.FontDir())
+	pdf.AddUTF8Font("dejavu", "", "DejaVuSansCondensed.ttf")
 	pdf.AddPage()
-	pdf.SetFont("Helvetica", "", 18)
-	pdf.SetXY(0, gapY)
-	pdf.SetTextColor(0, 0, 0)
-	pdf.CellFormat(pageW, gapY, "Alpha Blending Modes", "", 0, "C", false, 0, "")
-	j := 0
-	y := 3 * gapY
-	for col := 0; col < 4; col++ {
-		x := gapX
-		for row := 0; row < 4; row++ {
-			pdf.Rect(x, y, rectW, rectH, "D")
-			pdf.SetFont("Helvetica", "B", 12)
-			pdf.SetFillColor(0, 0, 0)
-			pdf.SetTextColor(250, 250, 230)
-			pdf.SetXY(x, y+rectH-4)
-			pdf.CellFormat(rectW, 5, modeList[j], "", 0, "C", true, 0, "")
-			pdf.SetFont("Helvetica", "I", 150)
-			pdf.SetTextColor(80, 80, 120)
-			pdf.SetXY(x, y+2)
-			pdf.CellFormat(rectW, rectH, "A", "", 0, "C", false, 0, "")
-			pdf.SetAlpha(0.5, modeList[j])
-			pdf.Image(example.ImageFile("golang-gopher.png"),
-				x-gapX, y, rectW+2*gapX, 0, false, "", 0, "")
-			pdf.SetAlpha(1.0, "Normal")
-			x += rectW + gapX
-			j++
+	x, y, size := 20.0, 100.0, 80.0
+	pdf.SetLineWidth(0.5)
+	pdf.SetDrawColor(0, 0, 160)
+	pdf.SetFillColor(200, 220, 255)
+	for _, r := range "G&ß" {
+		glyph, ok := pdf.GetGlyphOutline("dejavu", "", r)
+		if ok {
+			fmt.Printf("%c: %d contours, width %.0f\n", r, len(glyph.Contours), glyph.Width)
+			pdf.DrawGlyphOutline(glyph, x, y, size, "FD")
+			x += glyph.Width * size / 1000
 		}
-		y += rectH + gapY
 	}
-	fileStr := example.Filename("Fpdf_SetAlpha_transparency")
+	// Letter-shaped clipping
+	glyph, _ := pdf.GetGlyphOutline("dejavu", "", 'W')
+	x, y, size = 20, 240, 150
+	pdf.ClipGlyphOutline(glyph, x, y, size, true)
+	pdf.LinearGradient(x, y-size, glyph.Width*size/1000, size, 250, 200, 0, 200, 0, 60, 0, 0, 0, 1)
+	pdf.ClipEnd()
+	fileStr := example.Filename("Fpdf_GetGlyphOutline")
 	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_SetAlpha_transparency.pdf
+	// G: 1 contours, width 697
+	// &: 2 contours, width 702
+	// ß: 1 contours, width 567
+	// Successfully generated pdf/Fpdf_GetGlyphOutline.pdf
 }
 
-// ExampleFpdf_LinearGradient deomstrates various gradients.
-func ExampleFpdf_LinearGradient() {
-	pdf := gofpdf.New("", "", "", "")
-	pdf.SetFont("Helvetica", "", 12)
+// ExampleFpdf_SetTextAsPaths demonstrates the drawing of text as filled
+// paths, which does not require the font to be embedded.
+func ExampleFpdf_SetTextAsPaths() {
+	pdf := gofpdf.New("P", "mm", "A4", example.FontDir())
+	pdf.AddUTF8Font("dejavu", "B", "DejaVuSansCondensed-Bold.ttf")
 	pdf.AddPage()
-	pdf.LinearGradient(0, 0, 210, 100, 250, 250, 255, 220, 220, 225, 0, 0, 0, .5)
-	pdf.LinearGradient(20, 25, 75, 75, 220, 220, 250, 80, 80, 220, 0, .2, 0, .8)
-	pdf.Rect(20, 25, 75, 75, "D")
-	pdf.LinearGradient(115, 25, 75, 75, 220, 220, 250, 80, 80, 220, 0, 0, 1, 1)
-	pdf.Rect(115, 25, 75, 75, "D")
-	pdf.RadialGradient(20, 120, 75, 75, 220, 220, 250, 80, 80, 220,
-		0.25, 0.75, 0.25, 0.75, 1)
-	pdf.Rect(20, 120, 75, 75, "D")
-	pdf.RadialGradient(115, 120, 75, 75, 220, 220, 250, 80, 80, 220,
-		0.25, 0.75, 0.75, 0.75, 0.75)
-	pdf.Rect(115, 120, 75, 75, "D")
-	fileStr := example.Filename("Fpdf_LinearGradient_gradient")
+	pdf.SetTextAsPaths(true)
+	pdf.SetFont("dejavu", "B", 28)
+	pdf.SetTextColor(0, 80, 160)
+	pdf.CellFormat(0, 14, "Vector heading – ÆØÅ", "B", 1, "C", false, 0, "")
+	pdf.Ln(4)
+	pdf.SetFont("dejavu", "B", 11)
+	pdf.SetTextColor(40, 40, 40)
+	pdf.MultiCell(0, 5.5, lorem(), "", "J", false)
+	pdf.Text(20, 150, "Placed with Text()")
+	pdf.SetTextAsPaths(false)
+	pdf.Ln(4)
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.MultiCell(0, 5.5, "Text that is not drawn as paths, like this paragraph, "+
+		"can be selected and searched.", "", "L", false)
+	for _, fnt := range pdf.GetRegisteredFonts() {
+		if fnt.Family == "dejavu" {
+			fmt.Printf("%s %s: %d glyphs embedded\n", fnt.Family, fnt.Style, fnt.SubsetGlyphs)
+		}
+	}
+	fileStr := example.Filename("Fpdf_SetTextAsPaths")
 	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_LinearGradient_gradient.pdf
+	// dejavu B: 0 glyphs embedded
+	// Successfully generated pdf/Fpdf_SetTextAsPaths.pdf
 }
 
-// ExampleFpdf_ClipText demonstrates clipping.
-func ExampleFpdf_ClipText() {
-	pdf := gofpdf.New("", "", "", "")
-	y := 10.0
+// ExampleFpdf_FormCheckbox demonstrates static form elements for a form that
+// is printed and completed by hand.
+func ExampleFpdf_FormCheckbox() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
 	pdf.AddPage()
-
-	pdf.SetFont("Helvetica", "", 24)
-	pdf.SetXY(0, y)
-	pdf.ClipText(10, y+12, "Clipping examples", false)
-	pdf.RadialGradient(10, y, 100, 20, 128, 128, 160, 32, 32, 48,
-		0.25, 0.5, 0.25, 0.5, 0.2)
-	pdf.ClipEnd()
-
-	y += 12
-	pdf.SetFont("Helvetica", "B", 120)
-	pdf.SetDrawColor(64, 80, 80)
-	pdf.SetLineWidth(.5)
-	pdf.ClipText(10, y+40, pdf.String(), true)
-	pdf.RadialGradient(10, y, 200, 50, 220, 220, 250, 80, 80, 220,
-		0.25, 0.5, 0.25, 0.5, 1)
-	pdf.ClipEnd()
-
-	y += 55
-	pdf.ClipRect(10, y, 105, 20, true)
-	pdf.SetFillColor(255, 255, 255)
-	pdf.Rect(10, y, 105, 20, "F")
-	pdf.ClipCircle(40, y+10, 15, false)
-	pdf.RadialGradient(25, y, 30, 30, 220, 250, 220, 40, 60, 40, 0.3,
-		0.85, 0.3, 0.85, 0.5)
-	pdf.ClipEnd()
-	pdf.ClipEllipse(80, y+10, 20, 15, false)
-	pdf.RadialGradient(60, y, 40, 30, 250, 220, 220, 60, 40, 40, 0.3,
-		0.85, 0.3, 0.85, 0.5)
-	pdf.ClipEnd()
-	pdf.ClipEnd()
-
-	y += 28
-	pdf.ClipEllipse(26, y+10, 16, 10, true)
-	pdf.Image(example.ImageFile("logo.jpg"), 10, y, 32, 0, false, "JPG", 0, "")
-	pdf.ClipEnd()
-
-	pdf.ClipCircle(60, y+10, 10, true)
-	pdf.RadialGradient(50, y, 20, 20, 220, 220, 250, 40, 40, 60, 0.3,
-		0.7, 0.3, 0.7, 0.5)
-	pdf.ClipEnd()
-
-	pdf.ClipPolygon([]gofpdf.PointType{{X: 80, Y: y + 20}, {X: 90, Y: y},
-		{X: 100, Y: y + 20}}, true)
-	pdf.LinearGradient(80, y, 20, 20, 250, 220, 250, 60, 40, 60, 0.5,
-		1, 0.5, 0.5)
-	pdf.ClipEnd()
-
-	y += 30
-	pdf.SetLineWidth(.1)
-	pdf.SetDrawColor(180, 180, 180)
-	pdf.ClipRoundedRect(10, y, 120, 20, 5, true)
-	pdf.RadialGradient(10, y, 120, 20, 255, 255, 255, 240, 240, 220,
-		0.25, 0.75, 0.25, 0.75, 0.5)
-	pdf.SetXY(5, y-5)
-	pdf.SetFont("Times", "", 12)
-	pdf.MultiCell(130, 5, lorem(), "", "", false)
-	pdf.ClipEnd()
-
-	y += 30
-	pdf.SetDrawColor(180, 100, 180)
-	pdf.ClipRoundedRectExt(10, y, 120, 20, 5, 10, 5, 10, true)
-	pdf.RadialGradient(10, y, 120, 20, 255, 255, 255, 240, 240, 220,
-		0.25, 0.75, 0.25, 0.75, 0.5)
-	pdf.SetXY(5, y-5)
-	pdf.SetFont("Times", "", 12)
-	pdf.MultiCell(130, 5, lorem(), "", "", false)
-	pdf.ClipEnd()
-
-	fileStr := example.Filename("Fpdf_ClipText")
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.Cell(0, 10, "Membership application")
+	pdf.Ln(16)
+	pdf.SetFont("Helvetica", "", 11)
+	st := gofpdf.NewFormStyle()
+	st.LineWidth = 0.3
+	pdf.Text(20, 40, "Membership type")
+	pdf.FormRadio(20, 44, 5, true, "Individual", st)
+	pdf.FormRadio(70, 44, 5, false, "Family", st)
+	pdf.FormRadio(120, 44, 5, false, "Student", st)
+	pdf.Text(20, 64, "Interests")
+	for j, str := range []string{"Newsletter", "Events", "Volunteering"} {
+		st.Mark = j
+		pdf.FormCheckbox(20+50*float64(j), 68, 5, j != 1, str, st)
+	}
+	st.Mark = gofpdf.FormMarkCheck
+	pdf.FormCheckbox(20, 78, 5, false, "I agree to the terms of membership", st)
+	pdf.FormDateField(20, 100, 8, "DD.MM.YYYY", "", "Date of birth", st)
+	pdf.FormDateField(110, 100, 8, "YYYY-MM-DD", "2025-03-01", "Start of membership", st)
+	pdf.FormSignatureLine(20, 140, 80, "Signature of applicant", st)
+	pdf.FormSignatureLine(110, 140, 80, "Date", st)
+	fileStr := example.Filename("Fpdf_FormCheckbox")
 	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_ClipText.pdf
+	// Successfully generated pdf/Fpdf_FormCheckbox.pdf
 }
 
-// ExampleFpdf_PageSize generates a PDF document with various page sizes.
-func ExampleFpdf_PageSize() {
-	pdf := gofpdf.NewCustom(&gofpdf.InitType{
-		UnitStr:    "in",
-		Size:       gofpdf.SizeType{Wd: 6, Ht: 6},
-		FontDirStr: example.FontDir(),
-	})
-	pdf.SetMargins(0.5, 1, 0.5)
-	pdf.SetFont("Times", "", 14)
-	pdf.AddPageFormat("L", gofpdf.SizeType{Wd: 3, Ht: 12})
-	pdf.SetXY(0.5, 1.5)
-	pdf.CellFormat(11, 0.2, "12 in x 3 in", "", 0, "C", false, 0, "")
-	pdf.AddPage() // Default size established in NewCustom()
-	pdf.SetXY(0.5, 3)
-	pdf.CellFormat(5, 0.2, "6 in x 6 in", "", 0, "C", false, 0, "")
-	pdf.AddPageFormat("P", gofpdf.SizeType{Wd: 3, Ht: 12})
-	pdf.SetXY(0.5, 6)
-	pdf.CellFormat(2, 0.2, "3 in x 12 in", "", 0, "C", false, 0, "")
-	for j := 0; j <= 3; j++ {
-		wd, ht, u := pdf.PageSize(j)
-		fmt.Printf("%d: %6.2f %s, %6.2f %s\n", j, wd, u, ht, u)
-	}
-	fileStr := example.Filename("Fpdf_PageSize")
+// ExampleFpdf_AddTextField demonstrates an interactive form that readers of
+// the document fill in with a PDF viewer. Labels are printed with the usual
+// text methods; the fields are placed beside them.
+func ExampleFpdf_AddTextField() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.Cell(0, 10, "Order form")
+	pdf.SetFont("Helvetica", "", 11)
+	opt := gofpdf.NewFormField()
+	opt.ClrBorder = gofpdf.RGBType{R: 90, G: 90, B: 90}
+	opt.ClrFill = gofpdf.RGBType{R: 235, G: 241, B: 255}
+	label := func(y float64, txtStr string) {
+		pdf.Text(20, y+5, txtStr)
+	}
+	label(30, "Name")
+	opt.Required = true
+	opt.Tooltip = "Your full name"
+	pdf.AddTextField("name", 60, 30, 120, 7, "", opt)
+	label(42, "Email")
+	opt.Tooltip = ""
+	pdf.AddTextField("email", 60, 42, 120, 7, "", opt)
+	opt.Required = false
+	label(54, "Country")
+	pdf.AddComboBox("country", 60, 54, 60, 7,
+		[]string{"Canada", "France", "Germany", "Japan", "United States"}, "Canada", opt)
+	label(66, "Size")
+	var buttons []gofpdf.FormRadioType
+	for j, str := range []string{"S", "M", "L", "XL"} {
+		x := 60 + 25*float64(j)
+		buttons = append(buttons, gofpdf.FormRadioType{X: x, Y: 66, Size: 5, Value: str})
+		pdf.Text(x+7, 70, str)
+	}
+	pdf.AddRadioGroup("size", buttons, "M", opt)
+	label(78, "Gift wrap")
+	pdf.AddCheckBox("gift", 60, 78, 5, false, opt)
+	label(90, "Comments")
+	opt.Multiline = true
+	opt.FontSize = 10
+	pdf.AddTextField("comments", 60, 90, 120, 30, "Please deliver after 5 pm.\nThank you!", opt)
+	fileStr := example.Filename("Fpdf_AddTextField")
 	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// 0:   6.00 in,   6.00 in
-	// 1:  12.00 in,   3.00 in
-	// 2:   6.00 in,   6.00 in
-	// 3:   3.00 in,  12.00 in
-	// Successfully generated pdf/Fpdf_PageSize.pdf
+	// Successfully generated pdf/Fpdf_AddTextField.pdf
 }
 
-// ExampleFpdf_Bookmark demonstrates the Bookmark method.
-func ExampleFpdf_Bookmark() {
+// ExampleFpdf_SignatureBlock demonstrates a signature block with a
+// handwritten signature captured by the jSignature web control.
+func ExampleFpdf_SignatureBlock() {
 	pdf := gofpdf.New("P", "mm", "A4", "")
 	pdf.AddPage()
-	pdf.SetFont("Arial", "", 15)
-	pdf.Bookmark("Page 1", 0, 0)
-	pdf.Bookmark("Paragraph 1", 1, -1)
-	pdf.Cell(0, 6, "Paragraph 1")
-	pdf.Ln(50)
-	pdf.Bookmark("Paragraph 2", 1, -1)
-	pdf.Cell(0, 6, "Paragraph 2")
-	pdf.AddPage()
-	pdf.Bookmark("Page 2", 0, 0)
-	pdf.Bookmark("Paragraph 3", 1, -1)
-	pdf.Cell(0, 6, "Paragraph 3")
-	fileStr := example.Filename("Fpdf_Bookmark")
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.MultiCell(0, 5, "The parties agree to the terms set out above.", "", "L", false)
+	sig, err := gofpdf.SVGBasicFileParse(example.ImageFile("signature.svg"))
+	if err != nil {
+		pdf.SetError(err)
+	}
+	sb := gofpdf.NewSignatureBlock()
+	sb.Signature = &sig
+	sb.SignatureLineWd = 0.3
+	sb.Name = "Jane Q. Public"
+	sb.Reason = "Approval of the contract"
+	sb.Location = "Springfield"
+	sb.Date = time.Date(2025, 3, 14, 9, 26, 0, 0, time.UTC)
+	pdf.SignatureBlock(20, 30, 170, 40, sb)
+	sb = gofpdf.NewSignatureBlock()
+	sb.ImageStr = example.ImageFile("logo.png")
+	sb.Name = "Witness"
+	sb.Border = false
+	pdf.SignatureBlock(20, 80, 170, 30, sb)
+	fileStr := example.Filename("Fpdf_SignatureBlock")
+	err = pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SignatureBlock.pdf
+}
+
+// ExampleFpdf_Stamp demonstrates the predefined rubber stamps and the
+// registration of a custom stamp.
+func ExampleFpdf_Stamp() {
+	received := gofpdf.NewStamp("RECEIVED", gofpdf.RGBType{R: 120, G: 0, B: 160})
+	received.FontSize = 24
+	received.Angle = -8
+	gofpdf.RegisterStamp("received", received)
+	fmt.Println(strings.Join(gofpdf.GetStampNames(), " "))
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Times", "", 12)
+	for j := 0; j < 2; j++ {
+		pdf.AddPage()
+		pdf.Stamp(gofpdf.StampDraft, 105, 148)
+		pdf.MultiCell(0, 5, lorem(), "", "J", false)
+	}
+	pdf.Stamp(gofpdf.StampPaid, 150, 40)
+	pdf.Stamp("Received", 60, 120)
+	pdf.Stamp("overdue", 60, 200)
+	fmt.Println(pdf.Error())
+	pdf.ClearError()
+	fileStr := example.Filename("Fpdf_Stamp")
 	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_Bookmark.pdf
+	// APPROVED CONFIDENTIAL COPY DRAFT PAID RECEIVED VOID
+	// stamp overdue has not been registered
+	// Successfully generated pdf/Fpdf_Stamp.pdf
 }
 
-// ExampleFpdf_TransformBegin demonstrates various transformations. It is adapted from an
-// example script by Moritz Wagner and Andreas Würmser.
-func ExampleFpdf_TransformBegin() {
-	const (
-		light = 200
-		dark  = 0
-	)
-	var refX, refY float64
-	var refStr string
+// ExampleFpdf_OutputPartial demonstrates the recovery of the pages that were
+// completed before an error occurred.
+func ExampleFpdf_OutputPartial() {
 	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.AddPage()
-	color := func(val int) {
-		pdf.SetDrawColor(val, val, val)
-		pdf.SetTextColor(val, val, val)
+	pdf.SetFont("Times", "", 12)
+	for j := 1; j <= 4; j++ {
+		pdf.AddPage()
+		pdf.MultiCell(0, 5, lorem(), "", "J", false)
+		if j == 3 {
+			// A record with a missing image spoils the third page
+			pdf.Image("missing.png", 10, 100, 50, 0, false, "", 0, "")
+		}
 	}
-	reference := func(str string, x, y float64, val int) {
-		color(val)
-		pdf.Rect(x, y, 40, 10, "D")
-		pdf.Text(x, y-1, str)
+	fileStr := example.Filename("Fpdf_OutputPartial")
+	fl, err := os.Create(fileStr)
+	if err == nil {
+		err = pdf.OutputPartial(fl)
+		fl.Close()
 	}
-	refDraw := func(str string, x, y float64) {
-		refStr = str
-		refX = x
-		refY = y
-		reference(str, x, y, light)
+	fmt.Println(err)
+	// Output:
+	// open missing.png: no such file or directory
+}
+
+// ExampleFpdf_SetFileStreamThreshold demonstrates the streaming of large
+// image and font files into the output. The document is generated with and
+// without streaming to show that the output has the same size; only the
+// memory used while generating it differs.
+func ExampleFpdf_SetFileStreamThreshold() {
+	generate := func(threshold int64, protect bool) []byte {
+		pdf := gofpdf.New("P", "mm", "A4", example.FontDir())
+		pdf.SetFileStreamThreshold(threshold)
+		if protect {
+			pdf.SetProtection(gofpdf.CnProtectPrint, "", "owner")
+		}
+		pdf.AddFont("Calligrapher", "", "calligra.json")
+		pdf.AddPage()
+		pdf.SetFont("Calligrapher", "", 24)
+		pdf.Cell(0, 12, "Images and fonts streamed from files")
+		pdf.Image(example.ImageFile("logo.jpg"), 10, 30, 60, 0, false, "", 0, "")
+		pdf.Image(example.ImageFile("logo.png"), 80, 30, 60, 0, false, "", 0, "")
+		pdf.Image(example.ImageFile("logo-rgb.png"), 150, 30, 40, 0, false, "", 0, "")
+		var buf bytes.Buffer
+		err := pdf.Output(&buf)
+		if err != nil {
+			fmt.Println(err)
+		}
+		return buf.Bytes()
 	}
-	refDupe := func() {
-		reference(refStr, refX, refY, dark)
+	for _, protect := range []bool{false, true} {
+		doc := generate(1, protect)
+		fmt.Printf("protected %v, same size %v\n", protect, len(doc) == len(generate(0, protect)))
 	}
+	fileStr := example.Filename("Fpdf_SetFileStreamThreshold")
+	err := ioutil.WriteFile(fileStr, generate(1, false), 0644)
+	example.Summary(err, fileStr)
+	// Output:
+	// protected false, same size true
+	// protected true, same size true
+	// Successfully generated pdf/Fpdf_SetFileStreamThreshold.pdf
+}
 
-	titleStr := "Transformations"
-	titlePt := 36.0
-	titleHt := pdf.PointConvert(titlePt)
-	pdf.SetFont("Helvetica", "", titlePt)
-	titleWd := pdf.GetStringWidth(titleStr)
-	titleX := (210 - titleWd) / 2
-	pdf.Text(titleX, 10+titleHt, titleStr)
-	pdf.TransformBegin()
-	pdf.TransformMirrorVertical(10 + titleHt + 0.5)
-	pdf.ClipText(titleX, 10+titleHt, titleStr, false)
-	// Remember that the transform will mirror the gradient box too
-	pdf.LinearGradient(titleX, 10, titleWd, titleHt+4, 120, 120, 120,
-		255, 255, 255, 0, 0, 0, 0.6)
-	pdf.ClipEnd()
-	pdf.TransformEnd()
-
+// ExampleNewStreaming demonstrates a long report that is written to its file
+// page by page as it is generated. Each page is released once it has been
+// written, so the memory used does not grow with the length of the report.
+// The links of the table of contents and the bookmarks refer to pages that
+// have not been written, or even begun, when they are made.
+func ExampleNewStreaming() {
+	fileStr := example.Filename("NewStreaming")
+	fl, err := os.Create(fileStr)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer fl.Close()
+	pdf := gofpdf.NewStreaming(fl, "P", "mm", "A4", "")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(0, 10, fmt.Sprintf("Page %d", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	const chapters = 20
+	links := make([]int, chapters)
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.Cell(0, 10, "Contents")
+	pdf.Ln(12)
 	pdf.SetFont("Helvetica", "", 12)
+	for j := range links {
+		links[j] = pdf.AddLink()
+		pdf.WriteLinkID(7, fmt.Sprintf("Chapter %d", j+1), links[j])
+		pdf.Ln(7)
+	}
+	txtStr := strings.Repeat("Streaming keeps the memory used by a document bounded. ", 200)
+	for j, link := range links {
+		pdf.AddPage()
+		pdf.SetLink(link, 0, -1)
+		pdf.SetFont("Helvetica", "B", 16)
+		pdf.Bookmark(fmt.Sprintf("Chapter %d", j+1), 0, 0)
+		pdf.Cell(0, 10, fmt.Sprintf("Chapter %d", j+1))
+		pdf.Ln(12)
+		pdf.SetFont("Times", "", 12)
+		pdf.MultiCell(0, 5, txtStr, "", "J", false)
+	}
+	fi, err := fl.Stat()
+	if err == nil {
+		fmt.Printf("written before closing: %v\n", fi.Size() > 0)
+	}
+	pdf.Close()
+	fmt.Printf("pages: %d\n", pdf.PageCount())
+	example.Summary(pdf.Error(), fileStr)
+	// Output:
+	// written before closing: true
+	// pages: 61
+	// Successfully generated pdf/NewStreaming.pdf
+}
 
-	// Scale by 150% centered by lower left corner of the rectangle
-	refDraw("Scale", 50, 60)
-	pdf.TransformBegin()
-	pdf.TransformScaleXY(150, 50, 70)
-	refDupe()
-	pdf.TransformEnd()
-
-	// Translate 7 to the right, 5 to the bottom
-	refDraw("Translate", 125, 60)
-	pdf.TransformBegin()
-	pdf.TransformTranslate(7, 5)
-	refDupe()
-	pdf.TransformEnd()
-
-	// Rotate 20 degrees counter-clockwise centered by the lower left corner of
-	// the rectangle
-	refDraw("Rotate", 50, 110)
-	pdf.TransformBegin()
-	pdf.TransformRotate(20, 50, 120)
-	refDupe()
-	pdf.TransformEnd()
-
-	// Skew 30 degrees along the x-axis centered by the lower left corner of the
-	// rectangle
-	refDraw("Skew", 125, 110)
-	pdf.TransformBegin()
-	pdf.TransformSkewX(30, 125, 110)
-	refDupe()
-	pdf.TransformEnd()
-
-	// Mirror horizontally with axis of reflection at left side of the rectangle
-	refDraw("Mirror horizontal", 50, 160)
-	pdf.TransformBegin()
-	pdf.TransformMirrorHorizontal(50)
-	refDupe()
-	pdf.TransformEnd()
-
-	// Mirror vertically with axis of reflection at bottom side of the rectangle
-	refDraw("Mirror vertical", 125, 160)
-	pdf.TransformBegin()
-	pdf.TransformMirrorVertical(170)
-	refDupe()
-	pdf.TransformEnd()
-
-	// Reflect against a point at the lower left point of rectangle
-	refDraw("Mirror point", 50, 210)
-	pdf.TransformBegin()
-	pdf.TransformMirrorPoint(50, 220)
-	refDupe()
-	pdf.TransformEnd()
-
-	// Mirror against a straight line described by a point and an angle
-	angle := -20.0
-	px := 120.0
-	py := 220.0
-	refDraw("Mirror line", 125, 210)
-	pdf.TransformBegin()
-	pdf.TransformRotate(angle, px, py)
-	pdf.Line(px-1, py-1, px+1, py+1)
-	pdf.Line(px-1, py+1, px+1, py-1)
-	pdf.Line(px-5, py, px+60, py)
-	pdf.TransformEnd()
-	pdf.TransformBegin()
-	pdf.TransformMirrorLine(angle, px, py)
-	refDupe()
-	pdf.TransformEnd()
-
-	fileStr := example.Filename("Fpdf_TransformBegin")
-	err := pdf.OutputFileAndClose(fileStr)
+// ExampleFpdf_SetFloatPrecision demonstrates the effect of the precision of
+// numbers on the size of a dense scatter plot. The same plot is generated with
+// the default formatting and with reduced precision; the page content is not
+// compressed so that the sizes can be compared directly.
+func ExampleFpdf_SetFloatPrecision() {
+	plot := func(decimals int) (pdf *gofpdf.Fpdf) {
+		rnd := rand.New(rand.NewSource(0))
+		pdf = gofpdf.New("L", "mm", "A4", "")
+		pdf.SetCompression(false)
+		pdf.SetFloatPrecision(decimals)
+		pdf.AddPage()
+		pdf.SetFont("Helvetica", "", 12)
+		pdf.CellFormat(0, 10, fmt.Sprintf("Scatter plot, precision %d", decimals), "B", 1, "C", false, 0, "")
+		pdf.SetLineWidth(0.1)
+		for j := 0; j < 1000; j++ {
+			pdf.Circle(20+rnd.Float64()*257, 30+rnd.Float64()*170, 0.5, "D")
+		}
+		return
+	}
+	for _, decimals := range []int{-1, 2, 1} {
+		var buf bytes.Buffer
+		err := plot(decimals).Output(&buf)
+		if err == nil {
+			fmt.Printf("precision %2d: %d bytes\n", decimals, buf.Len())
+		} else {
+			fmt.Println(err)
+		}
+	}
+	fileStr := example.Filename("Fpdf_SetFloatPrecision")
+	err := plot(2).OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_TransformBegin.pdf
+	// precision -1: 386908 bytes
+	// precision  2: 274315 bytes
+	// precision  1: 233258 bytes
+	// Successfully generated pdf/Fpdf_SetFloatPrecision.pdf
 }
 
-// ExampleFpdf_RegisterImage demonstrates Lawrence Kesteloot's image registration code.
-func ExampleFpdf_RegisterImage() {
-	const (
-		margin = 10
-		wd     = 210
-		ht     = 297
-	)
-	fileList := []string{
-		"logo-gray.png",
-		"logo.jpg",
-		"logo.png",
-		"logo-rgb.png",
-		"logo-progressive.jpg",
+// ExampleFpdf_SetPageContentSharing demonstrates the sharing of the content
+// of identical pages. Each chapter of the document is followed by the same
+// separator page, which is stored only once when sharing is on.
+func ExampleFpdf_SetPageContentSharing() {
+	generate := func(share bool) (pdf *gofpdf.Fpdf) {
+		pdf = gofpdf.New("P", "mm", "A4", "")
+		pdf.SetPageContentSharing(share)
+		for chapter := 1; chapter <= 4; chapter++ {
+			pdf.AddPage()
+			pdf.SetFont("Helvetica", "B", 16)
+			pdf.CellFormat(0, 12, fmt.Sprintf("Chapter %d", chapter), "", 1, "L", false, 0, "")
+			pdf.SetFont("Times", "", 12)
+			pdf.MultiCell(0, 5, lorem(), "", "J", false)
+			// Separator page
+			pdf.AddPage()
+			pdf.SetFont("Helvetica", "I", 10)
+			pdf.SetY(140)
+			pdf.CellFormat(0, 10, "This page is intentionally left blank", "", 0, "C", false, 0, "")
+			pdf.SetLineWidth(0.2)
+			for j := 0.0; j < 40; j++ {
+				pdf.Line(60+j*2, 160, 150-j*2, 200)
+			}
+		}
+		return
 	}
-	var infoPtr *gofpdf.ImageInfoType
-	var imageFileStr string
-	var imgWd, imgHt, lf, tp float64
-	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.AddPage()
-	pdf.SetMargins(10, 10, 10)
-	pdf.SetFont("Helvetica", "", 15)
-	for j, str := range fileList {
-		imageFileStr = example.ImageFile(str)
-		infoPtr = pdf.RegisterImage(imageFileStr, "")
-		imgWd, imgHt = infoPtr.Extent()
-		switch j {
-		case 0:
-			lf = margin
-			tp = margin
-		case 1:
-			lf = wd - margin - imgWd
-			tp = margin
-		case 2:
-			lf = (wd - imgWd) / 2.0
-			tp = (ht - imgHt) / 2.0
-		case 3:
-			lf = margin
-			tp = ht - imgHt - margin
-		case 4:
-			lf = wd - imgWd - margin
-			tp = ht - imgHt - margin
+	for _, share := range []bool{false, true} {
+		var buf bytes.Buffer
+		err := generate(share).Output(&buf)
+		if err == nil {
+			fmt.Printf("sharing %v: %d streams, %d bytes\n", share,
+				bytes.Count(buf.Bytes(), []byte("endstream")), buf.Len())
+		} else {
+			fmt.Println(err)
 		}
-		pdf.Image(imageFileStr, lf, tp, imgWd, imgHt, false, "", 0, "")
 	}
-	fileStr := example.Filename("Fpdf_RegisterImage")
-	// Test the image information retrieval method
-	infoShow := func(imageStr string) {
-		imageStr = example.ImageFile(imageStr)
-		info := pdf.GetImageInfo(imageStr)
-		if info != nil {
-			if info.Width() > 0.0 {
-				fmt.Printf("Image %s is registered\n", filepath.ToSlash(imageStr))
+	fileStr := example.Filename("Fpdf_SetPageContentSharing")
+	err := generate(true).OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// sharing false: 8 streams, 12617 bytes
+	// sharing true: 5 streams, 7549 bytes
+	// Successfully generated pdf/Fpdf_SetPageContentSharing.pdf
+}
+
+// ExampleFpdf_PushXY demonstrates a widget function that is written without
+// regard to where it is drawn. It draws a labelled rating relative to the
+// current position and leaves the position where it found it, so the caller
+// lays out the widgets with ordinary cells.
+func ExampleFpdf_PushXY() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 11)
+	// rating draws a row of stars, filled up to stars, at the current position
+	rating := func(stars int) {
+		pdf.PushXY()
+		defer pdf.PopXY()
+		pdf.MoveRel(2, 4)
+		for j := 0; j < 5; j++ {
+			x, y := pdf.GetXY()
+			if j < stars {
+				pdf.SetFillColor(240, 180, 0)
 			} else {
-				fmt.Printf("Incorrect information for image %s\n", filepath.ToSlash(imageStr))
+				pdf.SetFillColor(220, 220, 220)
 			}
-		} else {
-			fmt.Printf("Image %s is not registered\n", filepath.ToSlash(imageStr))
+			var pts []gofpdf.PointType
+			for k := 0; k < 10; k++ {
+				r := 2.5
+				if k%2 == 1 {
+					r = 1
+				}
+				a := math.Pi/2 + float64(k)*math.Pi/5
+				pts = append(pts, gofpdf.PointType{X: x + 2.5 + r*math.Cos(a), Y: y - r*math.Sin(a)})
+			}
+			pdf.Polygon(pts, "F")
+			pdf.MoveRel(6, 0)
 		}
 	}
-	infoShow(fileList[0])
-	infoShow("foo.png")
+	for _, item := range []struct {
+		name  string
+		stars int
+	}{{"Breakfast", 4}, {"Service", 5}, {"Location", 3}, {"Price", 2}} {
+		pdf.CellFormat(40, 8, item.name, "B", 0, "L", false, 0, "")
+		rating(item.stars)
+		pdf.CellFormat(35, 8, "", "B", 1, "L", false, 0, "")
+	}
+	fileStr := example.Filename("Fpdf_PushXY")
 	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Image image/logo-gray.png is registered
-	// Image image/foo.png is not registered
-	// Successfully generated pdf/Fpdf_RegisterImage.pdf
+	// Successfully generated pdf/Fpdf_PushXY.pdf
 }
 
-// ExampleFpdf_SplitLines demonstrates Bruno Michel's line splitting function.
-func ExampleFpdf_SplitLines() {
-	const (
-		fontPtSize = 18.0
-		wd         = 100.0
-	)
-	pdf := gofpdf.New("P", "mm", "A4", "") // A4 210.0 x 297.0
-	pdf.SetFont("Times", "", fontPtSize)
-	_, lineHt := pdf.GetFontSize()
+// ExampleFpdf_Anchor demonstrates placing content relative to content drawn
+// earlier. The end of a table that runs over two pages is recorded as an
+// anchor, a notes page is added, and the totals box is then drawn directly
+// beneath the last row of the table.
+func ExampleFpdf_Anchor() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "", 11)
 	pdf.AddPage()
-	pdf.SetMargins(10, 10, 10)
-	lines := pdf.SplitLines([]byte(lorem()), wd)
-	ht := float64(len(lines)) * lineHt
-	y := (297.0 - ht) / 2.0
-	pdf.SetDrawColor(128, 128, 128)
-	pdf.SetFillColor(255, 255, 210)
-	x := (210.0 - (wd + 40.0)) / 2.0
-	pdf.Rect(x, y-20.0, wd+40.0, ht+40.0, "FD")
-	pdf.SetY(y)
-	for _, line := range lines {
-		pdf.CellFormat(190.0, lineHt, string(line), "", 1, "C", false, 0, "")
-	}
-	fileStr := example.Filename("Fpdf_Splitlines")
+	var total float64
+	for j := 1; j <= 70; j++ {
+		amount := float64(j*37%100) + 0.25
+		total += amount
+		pdf.CellFormat(120, 6, fmt.Sprintf("Item %d", j), "B", 0, "L", false, 0, "")
+		pdf.CellFormat(40, 6, fmt.Sprintf("%.2f", amount), "B", 1, "R", false, 0, "")
+	}
+	pdf.Anchor("table-end")
+	pdf.AddPage()
+	pdf.MultiCell(0, 6, "Notes: amounts are shown in euros and exclude tax.", "", "L", false)
+	// Return to the end of the table to add the totals box
+	pdf.GoToAnchor("table-end", 80, 2)
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(40, 8, "Total", "LTB", 0, "L", false, 0, "")
+	pdf.CellFormat(40, 8, fmt.Sprintf("%.2f", total), "RTB", 1, "R", false, 0, "")
+	page, _, y, _ := pdf.GetAnchor("table-end")
+	fmt.Printf("table ends on page %d at %.1f mm\n", page, y)
+	fileStr := example.Filename("Fpdf_Anchor")
 	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_Splitlines.pdf
+	// table ends on page 2 at 166.0 mm
+	// Successfully generated pdf/Fpdf_Anchor.pdf
 }
 
-// ExampleFpdf_SVGBasicWrite demonstrates how to render a simple path-only SVG image of the
-// type generated by the jSignature web control.
-func ExampleFpdf_SVGBasicWrite() {
-	const (
-		fontPtSize = 16.0
-		wd         = 100.0
-		sigFileStr = "signature.svg"
-	)
-	var (
-		sig gofpdf.SVGBasicType
-		err error
-	)
-	pdf := gofpdf.New("P", "mm", "A4", "") // A4 210.0 x 297.0
-	pdf.SetFont("Times", "", fontPtSize)
-	lineHt := pdf.PointConvert(fontPtSize)
-	pdf.AddPage()
-	pdf.SetMargins(10, 10, 10)
-	htmlStr := `This example renders a simple ` +
-		`<a href="http://www.w3.org/TR/SVG/">SVG</a> (scalable vector graphics) ` +
-		`image that contains only basic path commands without any styling, ` +
-		`color fill, reflection or endpoint closures. In particular, the ` +
-		`type of vector graphic returned from a ` +
-		`<a href="http://willowsystems.github.io/jSignature/#/demo/">jSignature</a> ` +
-		`web control is supported and is used in this example.`
+// ExampleFpdf_HTMLBasicNew_outline demonstrates the automatic creation of a
+// document outline from the headings of basic HTML. The H3 heading that
+// follows an H1 heading directly is placed one level below it.
+func ExampleFpdf_HTMLBasicNew_outline() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "", 12)
 	html := pdf.HTMLBasicNew()
-	html.Write(lineHt, htmlStr)
-	sig, err = gofpdf.SVGBasicFileParse(example.ImageFile(sigFileStr))
-	if err == nil {
-		scale := 100 / sig.Wd
-		scaleY := 30 / sig.Ht
-		if scale > scaleY {
-			scale = scaleY
-		}
-		pdf.SetLineCapStyle("round")
-		pdf.SetLineWidth(0.25)
-		pdf.SetDrawColor(0, 0, 128)
-		pdf.SetXY((210.0-scale*sig.Wd)/2.0, pdf.GetY()+10)
-		pdf.SVGBasicWrite(&sig, scale)
-	} else {
-		pdf.SetError(err)
-	}
-	fileStr := example.Filename("Fpdf_SVGBasicWrite")
-	err = pdf.OutputFileAndClose(fileStr)
-	example.Summary(err, fileStr)
-	// Output:
-	// Successfully generated pdf/Fpdf_SVGBasicWrite.pdf
-}
-
-// ExampleFpdf_CellFormat_align demonstrates Stefan Schroeder's code to control vertical
-// alignment.
-func ExampleFpdf_CellFormat_align() {
-	type recType struct {
-		align, txt string
-	}
-	recList := []recType{
-		{"TL", "top left"},
-		{"TC", "top center"},
-		{"TR", "top right"},
-		{"LM", "middle left"},
-		{"CM", "middle center"},
-		{"RM", "middle right"},
-		{"BL", "bottom left"},
-		{"BC", "bottom center"},
-		{"BR", "bottom right"},
-	}
-	recListBaseline := []recType{
-		{"AL", "baseline left"},
-		{"AC", "baseline center"},
-		{"AR", "baseline right"},
-	}
-	var formatRect = func(pdf *gofpdf.Fpdf, recList []recType) {
-		linkStr := ""
-		for pageJ := 0; pageJ < 2; pageJ++ {
-			pdf.AddPage()
-			pdf.SetMargins(10, 10, 10)
-			pdf.SetAutoPageBreak(false, 0)
-			borderStr := "1"
-			for _, rec := range recList {
-				pdf.SetXY(20, 20)
-				pdf.CellFormat(170, 257, rec.txt, borderStr, 0, rec.align, false, 0, linkStr)
-				borderStr = ""
+	html.Outline = true
+	para := strings.Repeat("Lorem ipsum dolor sit amet, consectetur adipiscing elit. ", 30)
+	for ch := 1; ch <= 3; ch++ {
+		pdf.AddPage()
+		html.Write(6, fmt.Sprintf("<h1><b>Chapter %d</b></h1><br><br>", ch))
+		for sec := 1; sec <= 2; sec++ {
+			if ch == 3 {
+				html.Write(6, fmt.Sprintf("<h3><b>Note %d</b></h3><br>", sec))
+			} else {
+				html.Write(6, fmt.Sprintf("<h2><b>Section %d.%d</b></h2><br>", ch, sec))
 			}
-			linkStr = "https://github.com/headlands-org/gofpdf"
+			html.Write(6, para+"<br><br>")
 		}
 	}
-	pdf := gofpdf.New("P", "mm", "A4", "") // A4 210.0 x 297.0
-	pdf.SetFont("Helvetica", "", 16)
-	formatRect(pdf, recList)
-	formatRect(pdf, recListBaseline)
-	var fr fontResourceType
-	pdf.SetFontLoader(fr)
-	pdf.AddFont("Calligrapher", "", "calligra.json")
-	pdf.SetFont("Calligrapher", "", 16)
-	formatRect(pdf, recListBaseline)
-	fileStr := example.Filename("Fpdf_CellFormat_align")
+	fileStr := example.Filename("Fpdf_HTMLBasicNew_outline")
 	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Generalized font loader reading calligra.json
-	// Generalized font loader reading calligra.z
-	// Successfully generated pdf/Fpdf_CellFormat_align.pdf
+	// Successfully generated pdf/Fpdf_HTMLBasicNew_outline.pdf
 }
 
-// ExampleFpdf_CellFormat_codepageescape demonstrates the use of characters in the high range of the
-// Windows-1252 code page (gofdpf default). See the example for CellFormat (4)
-// for a way to do this automatically.
-func ExampleFpdf_CellFormat_codepageescape() {
-	pdf := gofpdf.New("P", "mm", "A4", "") // A4 210.0 x 297.0
-	fontSize := 16.0
-	pdf.SetFont("Helvetica", "", fontSize)
-	ht := pdf.PointConvert(fontSize)
-	write := func(str string) {
-		pdf.CellFormat(190, ht, str, "", 1, "C", false, 0, "")
-		pdf.Ln(ht)
-	}
+// ExampleFpdf_SetImageCaptionStyle demonstrates images with alternate
+// descriptions and numbered captions.
+func ExampleFpdf_SetImageCaptionStyle() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Times", "", 12)
 	pdf.AddPage()
-	htmlStr := `Until gofpdf supports UTF-8 encoded source text, source text needs ` +
-		`to be specified with all special characters escaped to match the code page ` +
-		`layout of the currently selected font. By default, gofdpf uses code page 1252.` +
-		` See <a href="http://en.wikipedia.org/wiki/Windows-1252">Wikipedia</a> for ` +
-		`a table of this layout.`
-	html := pdf.HTMLBasicNew()
-	html.Write(ht, htmlStr)
-	pdf.Ln(2 * ht)
-	write("Voix ambigu\xeb d'un c\x9cur qui au z\xe9phyr pr\xe9f\xe8re les jattes de kiwi.")
-	write("Falsches \xdcben von Xylophonmusik qu\xe4lt jeden gr\xf6\xdferen Zwerg.")
-	write("Heiz\xf6lr\xfccksto\xdfabd\xe4mpfung")
-	write("For\xe5rsj\xe6vnd\xf8gn / Efter\xe5rsj\xe6vnd\xf8gn")
-	fileStr := example.Filename("Fpdf_CellFormat_codepageescape")
+	pdf.MultiCell(0, 6, "The captions of the first two images are printed below them in "+
+		"the default style. Each image also carries an alternate description.", "", "L", false)
+	pdf.Ln(4)
+	pdf.ImageOptions(example.ImageFile("logo.png"), 60, -1, 90, 0, true, gofpdf.ImageOptions{
+		AltText: "The gofpdf logo",
+		Caption: "The logo in PNG format",
+	}, 0, "")
+	pdf.Ln(4)
+	pdf.ImageOptions(example.ImageFile("golang-gopher.png"), 80, -1, 50, 0, true, gofpdf.ImageOptions{
+		AltText: "The Go gopher",
+		Caption: "The Go gopher, with a caption long " +
+			"enough to wrap to the width of the image",
+	}, 0, "")
+	pdf.SetImageCaptionStyle(gofpdf.ImageCaptionType{
+		PosStr:    "T",
+		NumberFmt: "Fig. %d. ",
+		FontStyle: "I",
+		FontSize:  10,
+		AlignStr:  "L",
+	})
+	pdf.Ln(4)
+	pdf.ImageOptions(example.ImageFile("logo.jpg"), 60, -1, 90, 0, true, gofpdf.ImageOptions{
+		AltText: "The gofpdf logo",
+		Caption: "The logo in JPEG format, captioned above in italics",
+	}, 0, "")
+	fileStr := example.Filename("Fpdf_SetImageCaptionStyle")
 	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_CellFormat_codepageescape.pdf
+	// Successfully generated pdf/Fpdf_SetImageCaptionStyle.pdf
 }
 
-// ExampleFpdf_CellFormat_codepage demonstrates the automatic conversion of UTF-8 strings to an
-// 8-bit font encoding.
-func ExampleFpdf_CellFormat_codepage() {
-	pdf := gofpdf.New("P", "mm", "A4", example.FontDir()) // A4 210.0 x 297.0
-	// See documentation for details on how to generate fonts
-	pdf.AddFont("Helvetica-1251", "", "helvetica_1251.json")
-	pdf.AddFont("Helvetica-1253", "", "helvetica_1253.json")
-	fontSize := 16.0
-	pdf.SetFont("Helvetica", "", fontSize)
-	ht := pdf.PointConvert(fontSize)
-	tr := pdf.UnicodeTranslatorFromDescriptor("") // "" defaults to "cp1252"
-	write := func(str string) {
-		// pdf.CellFormat(190, ht, tr(str), "", 1, "C", false, 0, "")
-		pdf.MultiCell(190, ht, tr(str), "", "C", false)
-		pdf.Ln(ht)
+// ExampleFpdf_NewCounter demonstrates numbered figures and tables with
+// cross-references. The reference to the table is made before the table is
+// numbered, so its number and page are filled in when the document is
+// closed.
+func ExampleFpdf_NewCounter() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.NewCounter("figure")
+	pdf.NewCounter("table")
+	figure := func(labelStr, captionStr string) {
+		pdf.Rect(60, pdf.GetY(), 90, 50, "D")
+		pdf.SetY(pdf.GetY() + 52)
+		n := pdf.StepCounter("figure", labelStr)
+		pdf.CellFormat(0, 6, fmt.Sprintf("Figure %d: %s", n, captionStr), "", 1, "C", false, 0, "")
+		pdf.Ln(4)
 	}
 	pdf.AddPage()
-	str := `Gofpdf provides a translator that will convert any UTF-8 code point ` +
-		`that is present in the specified code page.`
-	pdf.MultiCell(190, ht, str, "", "L", false)
-	pdf.Ln(2 * ht)
-	write("Voix ambiguë d'un cœur qui au zéphyr préfère les jattes de kiwi.")
-	write("Falsches Üben von Xylophonmusik quält jeden größeren Zwerg.")
-	write("Heizölrückstoßabdämpfung")
-	write("Forårsjævndøgn / Efterårsjævndøgn")
-	write("À noite, vovô Kowalsky vê o ímã cair no pé do pingüim queixoso e vovó" +
-		"põe açúcar no chá de tâmaras do jabuti feliz.")
-	pdf.SetFont("Helvetica-1251", "", fontSize) // Name matches one specified in AddFont()
-	tr = pdf.UnicodeTranslatorFromDescriptor("cp1251")
-	write("Съешь же ещё этих мягких французских булок, да выпей чаю.")
-
-	pdf.SetFont("Helvetica-1253", "", fontSize)
-	tr = pdf.UnicodeTranslatorFromDescriptor("cp1253")
-	write("Θέλει αρετή και τόλμη η ελευθερία. (Ανδρέας Κάλβος)")
-
-	fileStr := example.Filename("Fpdf_CellFormat_codepage")
+	figure("fig-overview", "Overview")
+	pdf.MultiCell(0, 6, fmt.Sprintf("Figure %s shows an overview; the figures are summarized "+
+		"in Table %s on page %s.", pdf.RefCounter("fig-overview"), pdf.RefCounter("tab-summary"),
+		pdf.RefCounterPage("tab-summary")), "", "L", false)
+	pdf.Ln(4)
+	for j := 0; j < 4; j++ {
+		figure("", fmt.Sprintf("Detail %d", j+1))
+	}
+	n := pdf.StepCounter("table", "tab-summary")
+	pdf.CellFormat(0, 6, fmt.Sprintf("Table %d: Summary", n), "", 1, "C", false, 0, "")
+	pdf.CellFormat(95, 6, "Figures", "1", 0, "L", false, 0, "")
+	pdf.CellFormat(95, 6, "5", "1", 1, "R", false, 0, "")
+	fmt.Printf("table %s on page %s\n", pdf.RefCounter("tab-summary"), pdf.RefCounterPage("tab-summary"))
+	fileStr := example.Filename("Fpdf_NewCounter")
 	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_CellFormat_codepage.pdf
+	// table 1 on page 2
+	// Successfully generated pdf/Fpdf_NewCounter.pdf
 }
 
-// ExampleFpdf_SetProtection demonstrates password protection for documents.
-func ExampleFpdf_SetProtection() {
+// ExampleFpdf_AddSection demonstrates a report whose summary, bound first,
+// prints totals that are computed while the detail sections are rendered.
+func ExampleFpdf_AddSection() {
 	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.SetProtection(gofpdf.CnProtectPrint, "123", "abc")
-	pdf.AddPage()
-	pdf.SetFont("Arial", "", 12)
-	pdf.Write(10, "Password-protected.")
-	fileStr := example.Filename("Fpdf_SetProtection")
-	err := pdf.OutputFileAndClose(fileStr)
-	example.Summary(err, fileStr)
-	// Output:
-	// Successfully generated pdf/Fpdf_SetProtection.pdf
-}
-
-// ExampleFpdf_Polygon displays equilateral polygons in a demonstration of the Polygon
-// function.
-func ExampleFpdf_Polygon() {
-	const rowCount = 5
-	const colCount = 4
-	const ptSize = 36
-	var x, y, radius, gap, advance float64
-	var rgVal int
-	var pts []gofpdf.PointType
-	vertices := func(count int) (res []gofpdf.PointType) {
-		var pt gofpdf.PointType
-		res = make([]gofpdf.PointType, 0, count)
-		mlt := 2.0 * math.Pi / float64(count)
-		for j := 0; j < count; j++ {
-			pt.Y, pt.X = math.Sincos(float64(j) * mlt)
-			res = append(res, gofpdf.PointType{
-				X: x + radius*pt.X,
-				Y: y + radius*pt.Y})
-		}
-		return
-	}
-	pdf := gofpdf.New("P", "mm", "A4", "") // A4 210.0 x 297.0
-	pdf.AddPage()
-	pdf.SetFont("Helvetica", "", ptSize)
-	pdf.SetDrawColor(0, 80, 180)
-	gap = 12.0
-	pdf.SetY(gap)
-	pdf.CellFormat(190.0, gap, "Equilateral polygons", "", 1, "C", false, 0, "")
-	radius = (210.0 - float64(colCount+1)*gap) / (2.0 * float64(colCount))
-	advance = gap + 2.0*radius
-	y = 2*gap + pdf.PointConvert(ptSize) + radius
-	rgVal = 230
-	for row := 0; row < rowCount; row++ {
-		pdf.SetFillColor(rgVal, rgVal, 0)
-		rgVal -= 12
-		x = gap + radius
-		for col := 0; col < colCount; col++ {
-			pts = vertices(row*colCount + col + 3)
-			pdf.Polygon(pts, "FD")
-			x += advance
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.CellFormat(0, 10, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	regions := []string{"North", "South", "East"}
+	totals := make(map[string]float64)
+	var render []string
+	pdf.AddSection("summary", func() {
+		render = append(render, "summary")
+		pdf.Bookmark("Summary", 0, -1)
+		pdf.CellFormat(0, 10, "Summary", "", 1, "L", false, 0, "")
+		var sum float64
+		for _, region := range regions {
+			pdf.CellFormat(60, 7, region, "B", 0, "L", false, 0, "")
+			pdf.CellFormat(40, 7, fmt.Sprintf("%.2f", totals[region]), "B", 1, "R", false, 0, "")
+			sum += totals[region]
 		}
-		y += advance
+		pdf.CellFormat(60, 7, "All regions", "B", 0, "L", false, 0, "")
+		pdf.CellFormat(40, 7, fmt.Sprintf("%.2f", sum), "B", 1, "R", false, 0, "")
+	}, regions...)
+	for j, region := range regions {
+		region, rows := region, 30+25*j
+		pdf.AddSection(region, func() {
+			render = append(render, region)
+			pdf.Bookmark(region, 0, -1)
+			pdf.CellFormat(0, 10, region, "", 1, "L", false, 0, "")
+			for k := 1; k <= rows; k++ {
+				amount := float64(k*13%50) + 0.5
+				totals[region] += amount
+				pdf.CellFormat(60, 7, fmt.Sprintf("Order %d", k), "B", 0, "L", false, 0, "")
+				pdf.CellFormat(40, 7, fmt.Sprintf("%.2f", amount), "B", 1, "R", false, 0, "")
+			}
+		})
 	}
-	fileStr := example.Filename("Fpdf_Polygon")
+	pdf.RenderSections()
+	fmt.Println("rendered:", strings.Join(render, ", "))
+	fmt.Println("pages:", pdf.PageCount())
+	fileStr := example.Filename("Fpdf_AddSection")
 	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_Polygon.pdf
+	// rendered: North, South, East, summary
+	// pages: 7
+	// Successfully generated pdf/Fpdf_AddSection.pdf
 }
 
-// ExampleFpdf_AddLayer demonstrates document layers. The initial visibility of a layer
-// is specified with the second parameter to AddLayer(). The layer list
-// displayed by the document reader allows layer visibility to be controlled
-// interactively.
-func ExampleFpdf_AddLayer() {
-
+// ExampleFpdf_MultiCell demonstrates word-wrapping, line justification and
+// page-breaking.
+func ExampleFpdf_MultiCell() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	titleStr := "20000 Leagues Under the Seas"
+	pdf.SetTitle(titleStr, false)
+	pdf.SetAuthor("Jules Verne", false)
+	pdf.SetHeaderFunc(func() {
+		// Arial bold 15
+		pdf.SetFont("Arial", "B", 15)
+		// Calculate width of title and position
+		wd := pdf.GetStringWidth(titleStr) + 6
+		pdf.SetX((210 - wd) / 2)
+		// Colors of frame, background and text
+		pdf.SetDrawColor(0, 80, 180)
+		pdf.SetFillColor(230, 230, 0)
+		pdf.SetTextColor(220, 50, 50)
+		// Thickness of frame (1 mm)
+		pdf.SetLineWidth(1)
+		// Title
+		pdf.CellFormat(wd, 9, titleStr, "1", 1, "C", true, 0, "")
+		// Line break
+		pdf.Ln(10)
+	})
+	pdf.SetFooterFunc(func() {
+		// Position at 1.5 cm from bottom
+		pdf.SetY(-15)
+		// Arial italic 8
+		pdf.SetFont("Arial", "I", 8)
+		// Text color in gray
+		pdf.SetTextColor(128, 128, 128)
+		// Page number
+		pdf.CellFormat(0, 10, fmt.Sprintf("Page %d", pdf.PageNo()),
+			"", 0, "C", false, 0, "")
+	})
+	chapterTitle := func(chapNum int, titleStr string) {
+		// 	// Arial 12
+		pdf.SetFont("Arial", "", 12)
+		// Background color
+		pdf.SetFillColor(200, 220, 255)
+		// Title
+		pdf.CellFormat(0, 6, fmt.Sprintf("Chapter %d : %s", chapNum, titleStr),
+			"", 1, "L", true, 0, "")
+		// Line break
+		pdf.Ln(4)
+	}
+	chapterBody := func(fileStr string) {
+		// Read text file
+		txtStr, err := ioutil.ReadFile(fileStr)
+		if err != nil {
+			pdf.SetError(err)
+		}
+		// Times 12
+		pdf.SetFont("Times", "", 12)
+		// Output justified text
+		pdf.MultiCell(0, 5, string(txtStr), "", "", false)
+		// Line break
+		pdf.Ln(-1)
+		// Mention in italics
+		pdf.SetFont("", "I", 0)
+		pdf.Cell(0, 5, "(end of excerpt)")
+	}
+	printChapter := func(chapNum int, titleStr, fileStr string) {
+		pdf.AddPage()
+		chapterTitle(chapNum, titleStr)
+		chapterBody(fileStr)
+	}
+	printChapter(1, "A RUNAWAY REEF", example.TextFile("20k_c1.txt"))
+	printChapter(2, "THE PROS AND CONS", example.TextFile("20k_c2.txt"))
+	fileStr := example.Filename("Fpdf_MultiCell")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_MultiCell.pdf
+}
+
+// ExampleFpdf_SetLeftMargin demonstrates the generation of a PDF document that has multiple
+// columns. This is accomplished with the SetLeftMargin() and Cell() methods.
+func ExampleFpdf_SetLeftMargin() {
+	var y0 float64
+	var crrntCol int
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetDisplayMode("fullpage", "TwoColumnLeft")
+	titleStr := "20000 Leagues Under the Seas"
+	pdf.SetTitle(titleStr, false)
+	pdf.SetAuthor("Jules Verne", false)
+	setCol := func(col int) {
+		// Set position at a given column
+		crrntCol = col
+		x := 10.0 + float64(col)*65.0
+		pdf.SetLeftMargin(x)
+		pdf.SetX(x)
+	}
+	chapterTitle := func(chapNum int, titleStr string) {
+		// Arial 12
+		pdf.SetFont("Arial", "", 12)
+		// Background color
+		pdf.SetFillColor(200, 220, 255)
+		// Title
+		pdf.CellFormat(0, 6, fmt.Sprintf("Chapter %d : %s", chapNum, titleStr),
+			"", 1, "L", true, 0, "")
+		// Line break
+		pdf.Ln(4)
+		y0 = pdf.GetY()
+	}
+	chapterBody := func(fileStr string) {
+		// Read text file
+		txtStr, err := ioutil.ReadFile(fileStr)
+		if err != nil {
+			pdf.SetError(err)
+		}
+		// Font
+		pdf.SetFont("Times", "", 12)
+		// Output text in a 6 cm width column
+		pdf.MultiCell(60, 5, string(txtStr), "", "", false)
+		pdf.Ln(-1)
+		// Mention
+		pdf.SetFont("", "I", 0)
+		pdf.Cell(0, 5, "(end of excerpt)")
+		// Go back to first column
+		setCol(0)
+	}
+	printChapter := func(num int, titleStr, fileStr string) {
+		// Add chapter
+		pdf.AddPage()
+		chapterTitle(num, titleStr)
+		chapterBody(fileStr)
+	}
+	pdf.SetAcceptPageBreakFunc(func() bool {
+		// Method accepting or not automatic page break
+		if crrntCol < 2 {
+			// Go to next column
+			setCol(crrntCol + 1)
+			// Set ordinate to top
+			pdf.SetY(y0)
+			// Keep on page
+			return false
+		}
+		// Go back to first column
+		setCol(0)
+		// Page break
+		return true
+	})
+	pdf.SetHeaderFunc(func() {
+		// Arial bold 15
+		pdf.SetFont("Arial", "B", 15)
+		// Calculate width of title and position
+		wd := pdf.GetStringWidth(titleStr) + 6
+		pdf.SetX((210 - wd) / 2)
+		// Colors of frame, background and text
+		pdf.SetDrawColor(0, 80, 180)
+		pdf.SetFillColor(230, 230, 0)
+		pdf.SetTextColor(220, 50, 50)
+		// Thickness of frame (1 mm)
+		pdf.SetLineWidth(1)
+		// Title
+		pdf.CellFormat(wd, 9, titleStr, "1", 1, "C", true, 0, "")
+		// Line break
+		pdf.Ln(10)
+		// Save ordinate
+		y0 = pdf.GetY()
+	})
+	pdf.SetFooterFunc(func() {
+		// Position at 1.5 cm from bottom
+		pdf.SetY(-15)
+		// Arial italic 8
+		pdf.SetFont("Arial", "I", 8)
+		// Text color in gray
+		pdf.SetTextColor(128, 128, 128)
+		// Page number
+		pdf.CellFormat(0, 10, fmt.Sprintf("Page %d", pdf.PageNo()),
+			"", 0, "C", false, 0, "")
+	})
+	printChapter(1, "A RUNAWAY REEF", example.TextFile("20k_c1.txt"))
+	printChapter(2, "THE PROS AND CONS", example.TextFile("20k_c2.txt"))
+	fileStr := example.Filename("Fpdf_SetLeftMargin_multicolumn")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetLeftMargin_multicolumn.pdf
+}
+
+// ExampleFpdf_SplitLines_tables demonstrates word-wrapped table cells
+func ExampleFpdf_SplitLines_tables() {
+	const (
+		colCount = 3
+		colWd    = 60.0
+		marginH  = 15.0
+		lineHt   = 5.5
+		cellGap  = 2.0
+	)
+	// var colStrList [colCount]string
+	type cellType struct {
+		str  string
+		list [][]byte
+		ht   float64
+	}
+	var (
+		cellList [colCount]cellType
+		cell     cellType
+	)
+
+	pdf := gofpdf.New("P", "mm", "A4", "") // 210 x 297
+	header := [colCount]string{"Column A", "Column B", "Column C"}
+	alignList := [colCount]string{"L", "C", "R"}
+	strList := loremList()
+	pdf.SetMargins(marginH, 15, marginH)
+	pdf.SetFont("Arial", "", 14)
+	pdf.AddPage()
+
+	// Headers
+	pdf.SetTextColor(224, 224, 224)
+	pdf.SetFillColor(64, 64, 64)
+	for colJ := 0; colJ < colCount; colJ++ {
+		pdf.CellFormat(colWd, 10, header[colJ], "1", 0, "CM", true, 0, "")
+	}
+	pdf.Ln(-1)
+	pdf.SetTextColor(24, 24, 24)
+	pdf.SetFillColor(255, 255, 255)
+
+	// Rows
+	y := pdf.GetY()
+	count := 0
+	for rowJ := 0; rowJ < 2; rowJ++ {
+		maxHt := lineHt
+		// Cell height calculation loop
+		for colJ := 0; colJ < colCount; colJ++ {
+			count++
+			if count > len(strList) {
+				count = 1
+			}
+			cell.str = strings.Join(strList[0:count], " ")
+			cell.list = pdf.SplitLines([]byte(cell.str), colWd-cellGap-cellGap)
+			cell.ht = float64(len(cell.list)) * lineHt
+			if cell.ht > maxHt {
+				maxHt = cell.ht
+			}
+			cellList[colJ] = cell
+		}
+		// Cell render loop
+		x := marginH
+		for colJ := 0; colJ < colCount; colJ++ {
+			pdf.Rect(x, y, colWd, maxHt+cellGap+cellGap, "D")
+			cell = cellList[colJ]
+			cellY := y + cellGap + (maxHt-cell.ht)/2
+			for splitJ := 0; splitJ < len(cell.list); splitJ++ {
+				pdf.SetXY(x+cellGap, cellY)
+				pdf.CellFormat(colWd-cellGap-cellGap, lineHt, string(cell.list[splitJ]), "", 0,
+					alignList[colJ], false, 0, "")
+				cellY += lineHt
+			}
+			x += colWd
+		}
+		y += maxHt + cellGap + cellGap
+	}
+
+	fileStr := example.Filename("Fpdf_SplitLines_tables")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SplitLines_tables.pdf
+}
+
+// ExampleFpdf_CellFormat_tables demonstrates various table styles.
+func ExampleFpdf_CellFormat_tables() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	type countryType struct {
+		nameStr, capitalStr, areaStr, popStr string
+	}
+	countryList := make([]countryType, 0, 8)
+	header := []string{"Country", "Capital", "Area (sq km)", "Pop. (thousands)"}
+	loadData := func(fileStr string) {
+		fl, err := os.Open(fileStr)
+		if err == nil {
+			scanner := bufio.NewScanner(fl)
+			var c countryType
+			for scanner.Scan() {
+				// Austria;Vienna;83859;8075
+				lineStr := scanner.Text()
+				list := strings.Split(lineStr, ";")
+				if len(list) == 4 {
+					c.nameStr = list[0]
+					c.capitalStr = list[1]
+					c.areaStr = list[2]
+					c.popStr = list[3]
+					countryList = append(countryList, c)
+				} else {
+					err = fmt.Errorf("error tokenizing %s", lineStr)
+				}
+			}
+			fl.Close()
+			if len(countryList) == 0 {
+				err = fmt.Errorf("error loading data from %s", fileStr)
+			}
+		}
+		if err != nil {
+			pdf.SetError(err)
+		}
+	}
+	// Simple table
+	basicTable := func() {
+		left := (210.0 - 4*40) / 2
+		pdf.SetX(left)
+		for _, str := range header {
+			pdf.CellFormat(40, 7, str, "1", 0, "", false, 0, "")
+		}
+		pdf.Ln(-1)
+		for _, c := range countryList {
+			pdf.SetX(left)
+			pdf.CellFormat(40, 6, c.nameStr, "1", 0, "", false, 0, "")
+			pdf.CellFormat(40, 6, c.capitalStr, "1", 0, "", false, 0, "")
+			pdf.CellFormat(40, 6, c.areaStr, "1", 0, "", false, 0, "")
+			pdf.CellFormat(40, 6, c.popStr, "1", 0, "", false, 0, "")
+			pdf.Ln(-1)
+		}
+	}
+	// Better table
+	improvedTable := func() {
+		// Column widths
+		w := []float64{40.0, 35.0, 40.0, 45.0}
+		wSum := 0.0
+		for _, v := range w {
+			wSum += v
+		}
+		left := (210 - wSum) / 2
+		// 	Header
+		pdf.SetX(left)
+		for j, str := range header {
+			pdf.CellFormat(w[j], 7, str, "1", 0, "C", false, 0, "")
+		}
+		pdf.Ln(-1)
+		// Data
+		for _, c := range countryList {
+			pdf.SetX(left)
+			pdf.CellFormat(w[0], 6, c.nameStr, "LR", 0, "", false, 0, "")
+			pdf.CellFormat(w[1], 6, c.capitalStr, "LR", 0, "", false, 0, "")
+			pdf.CellFormat(w[2], 6, strDelimit(c.areaStr, ",", 3),
+				"LR", 0, "R", false, 0, "")
+			pdf.CellFormat(w[3], 6, strDelimit(c.popStr, ",", 3),
+				"LR", 0, "R", false, 0, "")
+			pdf.Ln(-1)
+		}
+		pdf.SetX(left)
+		pdf.CellFormat(wSum, 0, "", "T", 0, "", false, 0, "")
+	}
+	// Colored table
+	fancyTable := func() {
+		// Colors, line width and bold font
+		pdf.SetFillColor(255, 0, 0)
+		pdf.SetTextColor(255, 255, 255)
+		pdf.SetDrawColor(128, 0, 0)
+		pdf.SetLineWidth(.3)
+		pdf.SetFont("", "B", 0)
+		// 	Header
+		w := []float64{40, 35, 40, 45}
+		wSum := 0.0
+		for _, v := range w {
+			wSum += v
+		}
+		left := (210 - wSum) / 2
+		pdf.SetX(left)
+		for j, str := range header {
+			pdf.CellFormat(w[j], 7, str, "1", 0, "C", true, 0, "")
+		}
+		pdf.Ln(-1)
+		// Color and font restoration
+		pdf.SetFillColor(224, 235, 255)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.SetFont("", "", 0)
+		// 	Data
+		fill := false
+		for _, c := range countryList {
+			pdf.SetX(left)
+			pdf.CellFormat(w[0], 6, c.nameStr, "LR", 0, "", fill, 0, "")
+			pdf.CellFormat(w[1], 6, c.capitalStr, "LR", 0, "", fill, 0, "")
+			pdf.CellFormat(w[2], 6, strDelimit(c.areaStr, ",", 3),
+				"LR", 0, "R", fill, 0, "")
+			pdf.CellFormat(w[3], 6, strDelimit(c.popStr, ",", 3),
+				"LR", 0, "R", fill, 0, "")
+			pdf.Ln(-1)
+			fill = !fill
+		}
+		pdf.SetX(left)
+		pdf.CellFormat(wSum, 0, "", "T", 0, "", false, 0, "")
+	}
+	loadData(example.TextFile("countries.txt"))
+	pdf.SetFont("Arial", "", 14)
+	pdf.AddPage()
+	basicTable()
+	pdf.AddPage()
+	improvedTable()
+	pdf.AddPage()
+	fancyTable()
+	fileStr := example.Filename("Fpdf_CellFormat_tables")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_CellFormat_tables.pdf
+}
+
+// ExampleFpdf_HTMLBasicNew demonstrates internal and external links with and without basic
+// HTML.
+func ExampleFpdf_HTMLBasicNew() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	// First page: manual local link
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 20)
+	_, lineHt := pdf.GetFontSize()
+	pdf.Write(lineHt, "To find out what's new in this tutorial, click ")
+	pdf.SetFont("", "U", 0)
+	link := pdf.AddLink()
+	pdf.WriteLinkID(lineHt, "here", link)
+	pdf.SetFont("", "", 0)
+	// Second page: image link and basic HTML with link
+	pdf.AddPage()
+	pdf.SetLink(link, 0, -1)
+	pdf.Image(example.ImageFile("logo.png"), 10, 12, 30, 0, false, "", 0, "http://www.fpdf.org")
+	pdf.SetLeftMargin(45)
+	pdf.SetFontSize(14)
+	_, lineHt = pdf.GetFontSize()
+	htmlStr := `You can now easily print text mixing different styles: <b>bold</b>, ` +
+		`<i>italic</i>, <u>underlined</u>, or <b><i><u>all at once</u></i></b>!<br><br>` +
+		`<center>You can also center text.</center>` +
+		`<right>Or align it to the right.</right>` +
+		`You can also insert links on text, such as ` +
+		`<a href="http://www.fpdf.org">www.fpdf.org</a>, or on an image: click on the logo.`
+	html := pdf.HTMLBasicNew()
+	html.Write(lineHt, htmlStr)
+	fileStr := example.Filename("Fpdf_HTMLBasicNew")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_HTMLBasicNew.pdf
+}
+
+// ExampleFpdf_HTMLNew demonstrates the rendering of HTML content, such as an
+// article from a web publishing system, with headings, lists, a table, an
+// image and inline styles.
+func ExampleFpdf_HTMLNew() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Times", "", 11)
+	pdf.AddPage()
+	htmlStr := `<!DOCTYPE html>
+<html><head><title>Release notes</title></head><body>
+<h1>Release notes</h1>
+<p style="text-align: justify">This release of the <b>document service</b> brings
+<i>faster rendering</i>, <font color="#c00000">three fixes</font> and a new
+<span style="font-family: monospace">export</span> endpoint. Read the full
+announcement at <a href="https://github.com/headlands-org/gofpdf">the project
+page</a>.</p>
+<img src="` + example.ImageFile("logo.png") + `" width="120" align="center" alt="Logo">
+<h2>Highlights</h2>
+<ul>
+<li>Pages are laid out <u>twice as fast</u>
+<li>Tables repeat their header row
+  <ol type="a">
+  <li>on every page
+  <li>with cells that span rows
+  </ol>
+<li style="color: rgb(0, 96, 0)">Images scale to the text width
+</ul>
+<h2>Timetable</h2>
+<table>
+<tr><th width="25%">Phase</th><th>Work</th><th width="30mm">Weeks</th></tr>
+<tr><td rowspan="2">Design</td><td>Requirements &amp; review</td><td align="right">2</td></tr>
+<tr><td>Prototype</td><td align="right">3</td></tr>
+<tr><td>Delivery</td><td>Implementation,<br>tests and documentation</td><td align="right">6</td></tr>
+</table>
+<blockquote style="font-style: italic">Everything should be made as simple as
+possible, but not simpler.</blockquote>
+<hr>
+<p align="right" style="font-size: 80%">Last updated <s>May</s> June 2024</p>
+</body></html>`
+	html := pdf.HTMLNew()
+	html.Outline = true
+	html.Write(htmlStr)
+	fileStr := example.Filename("Fpdf_HTMLNew")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_HTMLNew.pdf
+}
+
+// ExampleFpdf_AddFont demonstrates the use of a non-standard font.
+func ExampleFpdf_AddFont() {
+	pdf := gofpdf.New("P", "mm", "A4", example.FontDir())
+	pdf.AddFont("Calligrapher", "", "calligra.json")
+	pdf.AddPage()
+	pdf.SetFont("Calligrapher", "", 35)
+	pdf.Cell(0, 10, "Enjoy new fonts with FPDF!")
+	fileStr := example.Filename("Fpdf_AddFont")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_AddFont.pdf
+}
+
+// ExampleFpdf_WriteAligned demonstrates how to align text with the Write function.
+func ExampleFpdf_WriteAligned() {
+	pdf := gofpdf.New("P", "mm", "A4", example.FontDir())
+	pdf.SetLeftMargin(50.0)
+	pdf.SetRightMargin(50.0)
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.WriteAligned(0, 35, "This text is the default alignment, Left", "")
+	pdf.Ln(35)
+	pdf.WriteAligned(0, 35, "This text is aligned Left", "L")
+	pdf.Ln(35)
+	pdf.WriteAligned(0, 35, "This text is aligned Center", "C")
+	pdf.Ln(35)
+	pdf.WriteAligned(0, 35, "This text is aligned Right", "R")
+	pdf.Ln(35)
+	line := "This can by used to write justified text"
+	leftMargin, _, rightMargin, _ := pdf.GetMargins()
+	pageWidth, _ := pdf.GetPageSize()
+	pageWidth -= leftMargin + rightMargin
+	pdf.SetWordSpacing((pageWidth - pdf.GetStringWidth(line)) / float64(strings.Count(line, " ")))
+	pdf.WriteAligned(pageWidth, 35, line, "L")
+	fileStr := example.Filename("Fpdf_WriteAligned")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_WriteAligned.pdf
+}
+
+// ExampleFpdf_Image demonstrates how images are included in documents.
+func ExampleFpdf_Image() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Arial", "", 11)
+	pdf.Image(example.ImageFile("logo.png"), 10, 10, 30, 0, false, "", 0, "")
+	pdf.Text(50, 20, "logo.png")
+	pdf.Image(example.ImageFile("logo.gif"), 10, 40, 30, 0, false, "", 0, "")
+	pdf.Text(50, 50, "logo.gif")
+	pdf.Image(example.ImageFile("logo-gray.png"), 10, 70, 30, 0, false, "", 0, "")
+	pdf.Text(50, 80, "logo-gray.png")
+	pdf.Image(example.ImageFile("logo-rgb.png"), 10, 100, 30, 0, false, "", 0, "")
+	pdf.Text(50, 110, "logo-rgb.png")
+	pdf.Image(example.ImageFile("logo.jpg"), 10, 130, 30, 0, false, "", 0, "")
+	pdf.Text(50, 140, "logo.jpg")
+	fileStr := example.Filename("Fpdf_Image")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_Image.pdf
+}
+
+// ExampleFpdf_ImageOptions demonstrates how the AllowNegativePosition field of the
+// ImageOption struct can be used to affect horizontal image placement.
+func ExampleFpdf_ImageOptions() {
+	var opt gofpdf.ImageOptions
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Arial", "", 11)
+	pdf.SetX(60)
+	opt.ImageType = "png"
+	pdf.ImageOptions(example.ImageFile("logo.png"), -10, 10, 30, 0, false, opt, 0, "")
+	opt.AllowNegativePosition = true
+	pdf.ImageOptions(example.ImageFile("logo.png"), -10, 50, 30, 0, false, opt, 0, "")
+	fileStr := example.Filename("Fpdf_ImageOptions")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_ImageOptions.pdf
+}
+
+// ExampleFpdf_ImageOptions_flowBreak demonstrates the handling of flowing
+// images that do not fit in the space remaining on the page.
+func ExampleFpdf_ImageOptions_flowBreak() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Arial", "", 11)
+	imgStr := example.ImageFile("logo.png")
+	show := func(label string, flowBreak int, minScale float64) {
+		pdf.AddPage()
+		pdf.MultiCell(0, 5, lorem(), "", "", false)
+		pdf.SetY(200)
+		pdf.Cell(0, 5, label)
+		pdf.Ln(8)
+		opt := gofpdf.ImageOptions{FlowBreak: flowBreak, MinScale: minScale}
+		pdf.ImageOptions(imgStr, -1, 0, 150, 0, true, opt, 0, "")
+		fmt.Printf("%s: page %d, y %.1f\n", label, pdf.PageNo(), pdf.GetY())
+	}
+	show("Break", gofpdf.ImageFlowBreak, 0)
+	show("Scale", gofpdf.ImageFlowScale, 0.5)
+	show("Scale with too small a result", gofpdf.ImageFlowScale, 0.9)
+	show("Split", gofpdf.ImageFlowSplit, 0)
+	fileStr := example.Filename("Fpdf_ImageOptions_flowBreak")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Break: page 2, y 112.4
+	// Scale: page 3, y 277.0
+	// Scale with too small a result: page 5, y 112.4
+	// Split: page 7, y 43.4
+	// Successfully generated pdf/Fpdf_ImageOptions_flowBreak.pdf
+}
+
+// ExampleFpdf_RegisterImageOptionsReader demonstrates how to load an image
+// from a io.Reader (in this case, a file) and register it with options.
+func ExampleFpdf_RegisterImageOptionsReader() {
+	var (
+		opt    gofpdf.ImageOptions
+		pdfStr string
+		fl     *os.File
+		err    error
+	)
+
+	pdfStr = example.Filename("Fpdf_RegisterImageOptionsReader")
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Arial", "", 11)
+	fl, err = os.Open(example.ImageFile("logo.png"))
+	if err == nil {
+		opt.ImageType = "png"
+		opt.AllowNegativePosition = true
+		_ = pdf.RegisterImageOptionsReader("logo", opt, fl)
+		fl.Close()
+		for x := -20.0; x <= 40.0; x += 5 {
+			pdf.ImageOptions("logo", x, x+30, 0, 0, false, opt, 0, "")
+		}
+		err = pdf.OutputFileAndClose(pdfStr)
+	}
+	example.Summary(err, pdfStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_RegisterImageOptionsReader.pdf
+}
+
+// This example demonstrates Landscape mode with images.
+func ExampleFpdf_SetAcceptPageBreakFunc() {
+	var y0 float64
+	var crrntCol int
+	loremStr := lorem()
+	pdf := gofpdf.New("L", "mm", "A4", "")
+	const (
+		pageWd = 297.0 // A4 210.0 x 297.0
+		margin = 10.0
+		gutter = 4
+		colNum = 3
+		colWd  = (pageWd - 2*margin - (colNum-1)*gutter) / colNum
+	)
+	setCol := func(col int) {
+		crrntCol = col
+		x := margin + float64(col)*(colWd+gutter)
+		pdf.SetLeftMargin(x)
+		pdf.SetX(x)
+	}
+	pdf.SetHeaderFunc(func() {
+		titleStr := "gofpdf"
+		pdf.SetFont("Helvetica", "B", 48)
+		wd := pdf.GetStringWidth(titleStr) + 6
+		pdf.SetX((pageWd - wd) / 2)
+		pdf.SetTextColor(128, 128, 160)
+		pdf.Write(12, titleStr[:2])
+		pdf.SetTextColor(128, 128, 128)
+		pdf.Write(12, titleStr[2:])
+		pdf.Ln(20)
+		y0 = pdf.GetY()
+	})
+	pdf.SetAcceptPageBreakFunc(func() bool {
+		if crrntCol < colNum-1 {
+			setCol(crrntCol + 1)
+			pdf.SetY(y0)
+			// Start new column, not new page
+			return false
+		}
+		setCol(0)
+		return true
+	})
+	pdf.AddPage()
+	pdf.SetFont("Times", "", 12)
+	for j := 0; j < 20; j++ {
+		if j == 1 {
+			pdf.Image(example.ImageFile("fpdf.png"), -1, 0, colWd, 0, true, "", 0, "")
+		} else if j == 5 {
+			pdf.Image(example.ImageFile("golang-gopher.png"),
+				-1, 0, colWd, 0, true, "", 0, "")
+		}
+		pdf.MultiCell(colWd, 5, loremStr, "", "", false)
+		pdf.Ln(-1)
+	}
+	fileStr := example.Filename("Fpdf_SetAcceptPageBreakFunc_landscape")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetAcceptPageBreakFunc_landscape.pdf
+}
+
+// This example tests corner cases as reported by the gocov tool.
+func ExampleFpdf_SetKeywords() {
+	var err error
+	fileStr := example.Filename("Fpdf_SetKeywords")
+	err = gofpdf.MakeFont(example.FontFile("CalligrapherRegular.pfb"),
+		example.FontFile("cp1252.map"), example.FontDir(), nil, true)
+	if err == nil {
+		pdf := gofpdf.New("", "", "", "")
+		pdf.SetFontLocation(example.FontDir())
+		pdf.SetTitle("世界", true)
+		pdf.SetAuthor("世界", true)
+		pdf.SetSubject("世界", true)
+		pdf.SetCreator("世界", true)
+		pdf.SetKeywords("世界", true)
+		pdf.AddFont("Calligrapher", "", "CalligrapherRegular.json")
+		pdf.AddPage()
+		pdf.SetFont("Calligrapher", "", 16)
+		pdf.Writef(5, "\x95 %s \x95", pdf)
+		err = pdf.OutputFileAndClose(fileStr)
+	}
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetKeywords.pdf
+}
+
+// ExampleFpdf_Circle demonstrates the construction of various geometric figures,
+func ExampleFpdf_Circle() {
+	const (
+		thin  = 0.2
+		thick = 3.0
+	)
+	pdf := gofpdf.New("", "", "", "")
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.SetFillColor(200, 200, 220)
+	pdf.AddPage()
+
+	y := 15.0
+	pdf.Text(10, y, "Circles")
+	pdf.SetFillColor(200, 200, 220)
+	pdf.SetLineWidth(thin)
+	pdf.Circle(20, y+15, 10, "D")
+	pdf.Circle(45, y+15, 10, "F")
+	pdf.Circle(70, y+15, 10, "FD")
+	pdf.SetLineWidth(thick)
+	pdf.Circle(95, y+15, 10, "FD")
+	pdf.SetLineWidth(thin)
+
+	y += 40.0
+	pdf.Text(10, y, "Ellipses")
+	pdf.SetFillColor(220, 200, 200)
+	pdf.Ellipse(30, y+15, 20, 10, 0, "D")
+	pdf.Ellipse(75, y+15, 20, 10, 0, "F")
+	pdf.Ellipse(120, y+15, 20, 10, 0, "FD")
+	pdf.SetLineWidth(thick)
+	pdf.Ellipse(165, y+15, 20, 10, 0, "FD")
+	pdf.SetLineWidth(thin)
+
+	y += 40.0
+	pdf.Text(10, y, "Curves (quadratic)")
+	pdf.SetFillColor(220, 220, 200)
+	pdf.Curve(10, y+30, 15, y-20, 40, y+30, "D")
+	pdf.Curve(45, y+30, 50, y-20, 75, y+30, "F")
+	pdf.Curve(80, y+30, 85, y-20, 110, y+30, "FD")
+	pdf.SetLineWidth(thick)
+	pdf.Curve(115, y+30, 120, y-20, 145, y+30, "FD")
+	pdf.SetLineCapStyle("round")
+	pdf.Curve(150, y+30, 155, y-20, 180, y+30, "FD")
+	pdf.SetLineWidth(thin)
+	pdf.SetLineCapStyle("butt")
+
+	y += 40.0
+	pdf.Text(10, y, "Curves (cubic)")
+	pdf.SetFillColor(220, 200, 220)
+	pdf.CurveBezierCubic(10, y+30, 15, y-20, 10, y+30, 40, y+30, "D")
+	pdf.CurveBezierCubic(45, y+30, 50, y-20, 45, y+30, 75, y+30, "F")
+	pdf.CurveBezierCubic(80, y+30, 85, y-20, 80, y+30, 110, y+30, "FD")
+	pdf.SetLineWidth(thick)
+	pdf.CurveBezierCubic(115, y+30, 120, y-20, 115, y+30, 145, y+30, "FD")
+	pdf.SetLineCapStyle("round")
+	pdf.CurveBezierCubic(150, y+30, 155, y-20, 150, y+30, 180, y+30, "FD")
+	pdf.SetLineWidth(thin)
+	pdf.SetLineCapStyle("butt")
+
+	y += 40.0
+	pdf.Text(10, y, "Arcs")
+	pdf.SetFillColor(200, 220, 220)
+	pdf.SetLineWidth(thick)
+	pdf.Arc(45, y+35, 20, 10, 0, 0, 180, "FD")
+	pdf.SetLineWidth(thin)
+	pdf.Arc(45, y+35, 25, 15, 0, 90, 270, "D")
+	pdf.SetLineWidth(thick)
+	pdf.Arc(45, y+35, 30, 20, 0, 0, 360, "D")
+	pdf.SetLineCapStyle("round")
+	pdf.Arc(135, y+35, 20, 10, 135, 0, 180, "FD")
+	pdf.SetLineWidth(thin)
+	pdf.Arc(135, y+35, 25, 15, 135, 90, 270, "D")
+	pdf.SetLineWidth(thick)
+	pdf.Arc(135, y+35, 30, 20, 135, 0, 360, "D")
+	pdf.SetLineWidth(thin)
+	pdf.SetLineCapStyle("butt")
+
+	fileStr := example.Filename("Fpdf_Circle_figures")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_Circle_figures.pdf
+}
+
+// ExampleFpdf_SetAlpha demonstrates alpha transparency.
+func ExampleFpdf_SetAlpha() {
+	const (
+		gapX  = 10.0
+		gapY  = 9.0
+		rectW = 40.0
+		rectH = 58.0
+		pageW = 210
+		pageH = 297
+	)
+	modeList := []string{"Normal", "Multiply", "Screen", "Overlay",
+		"Darken", "Lighten", "ColorDodge", "ColorBurn", "HardLight", "SoftLight",
+		"Difference", "Exclusion", "Hue", "Saturation", "Color", "Luminosity"}
+	pdf := gofpdf.New("", "", "", "")
+	pdf.SetLineWidth(2)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 18)
+	pdf.SetXY(0, gapY)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.CellFormat(pageW, gapY, "Alpha Blending Modes", "", 0, "C", false, 0, "")
+	j := 0
+	y := 3 * gapY
+	for col := 0; col < 4; col++ {
+		x := gapX
+		for row := 0; row < 4; row++ {
+			pdf.Rect(x, y, rectW, rectH, "D")
+			pdf.SetFont("Helvetica", "B", 12)
+			pdf.SetFillColor(0, 0, 0)
+			pdf.SetTextColor(250, 250, 230)
+			pdf.SetXY(x, y+rectH-4)
+			pdf.CellFormat(rectW, 5, modeList[j], "", 0, "C", true, 0, "")
+			pdf.SetFont("Helvetica", "I", 150)
+			pdf.SetTextColor(80, 80, 120)
+			pdf.SetXY(x, y+2)
+			pdf.CellFormat(rectW, rectH, "A", "", 0, "C", false, 0, "")
+			pdf.SetAlpha(0.5, modeList[j])
+			pdf.Image(example.ImageFile("golang-gopher.png"),
+				x-gapX, y, rectW+2*gapX, 0, false, "", 0, "")
+			pdf.SetAlpha(1.0, "Normal")
+			x += rectW + gapX
+			j++
+		}
+		y += rectH + gapY
+	}
+	fileStr := example.Filename("Fpdf_SetAlpha_transparency")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetAlpha_transparency.pdf
+}
+
+// ExampleFpdf_SetFillAlpha demonstrates translucent fills with solid
+// outlines, as used to overlay data series on a chart.
+func ExampleFpdf_SetFillAlpha() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.AddPage()
+	gr := gofpdf.NewGrid(20, 20, 170, 100)
+	gr.TickmarksExtentX(0, 1, 10)
+	gr.TickmarksExtentY(0, 10, 10)
+	gr.Grid(pdf)
+	series := []struct {
+		r, g, b int
+		vals    []float64
+	}{
+		{220, 50, 47, []float64{10, 35, 60, 72, 55, 40, 48, 70, 85, 62, 30}},
+		{38, 139, 210, []float64{5, 20, 28, 45, 80, 90, 70, 52, 40, 35, 20}},
+	}
+	pdf.SetLineWidth(0.6)
+	for _, s := range series {
+		pts := []gofpdf.PointType{{X: gr.X(0), Y: gr.Y(0)}}
+		for j, v := range s.vals {
+			pts = append(pts, gofpdf.PointType{X: gr.X(float64(j)), Y: gr.Y(v)})
+		}
+		pts = append(pts, gofpdf.PointType{X: gr.X(10), Y: gr.Y(0)})
+		pdf.SetFillColor(s.r, s.g, s.b)
+		pdf.SetDrawColor(s.r, s.g, s.b)
+		pdf.SetFillAlpha(0.35)
+		pdf.SetStrokeAlpha(1)
+		pdf.Polygon(pts, "FD")
+	}
+	pdf.SetAlpha(1, "Normal")
+	fileStr := example.Filename("Fpdf_SetFillAlpha")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetFillAlpha.pdf
+}
+
+// ExampleFpdf_WithAlpha demonstrates drawing with a blend mode that is
+// scoped to a function, so that the following content is rendered normally
+// without an explicit reset.
+func ExampleFpdf_WithAlpha() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "B", 36)
+	pdf.AddPage()
+	pdf.SetFillColor(255, 200, 0)
+	pdf.Rect(20, 20, 170, 40, "F")
+	pdf.WithAlpha(0.6, "Multiply", func() {
+		pdf.SetFillColor(0, 160, 230)
+		pdf.Circle(60, 40, 28, "F")
+		pdf.Circle(150, 40, 28, "F")
+	})
+	pdf.SetXY(20, 70)
+	pdf.Cell(0, 20, "Opaque again")
+	alpha, modeStr := pdf.GetAlpha()
+	fmt.Println(alpha, modeStr)
+	fileStr := example.Filename("Fpdf_WithAlpha")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// 1 Normal
+	// Successfully generated pdf/Fpdf_WithAlpha.pdf
+}
+
+// ExampleFpdf_LinearGradient deomstrates various gradients.
+func ExampleFpdf_LinearGradient() {
+	pdf := gofpdf.New("", "", "", "")
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.AddPage()
+	pdf.LinearGradient(0, 0, 210, 100, 250, 250, 255, 220, 220, 225, 0, 0, 0, .5)
+	pdf.LinearGradient(20, 25, 75, 75, 220, 220, 250, 80, 80, 220, 0, .2, 0, .8)
+	pdf.Rect(20, 25, 75, 75, "D")
+	pdf.LinearGradient(115, 25, 75, 75, 220, 220, 250, 80, 80, 220, 0, 0, 1, 1)
+	pdf.Rect(115, 25, 75, 75, "D")
+	pdf.RadialGradient(20, 120, 75, 75, 220, 220, 250, 80, 80, 220,
+		0.25, 0.75, 0.25, 0.75, 1)
+	pdf.Rect(20, 120, 75, 75, "D")
+	pdf.RadialGradient(115, 120, 75, 75, 220, 220, 250, 80, 80, 220,
+		0.25, 0.75, 0.75, 0.75, 0.75)
+	pdf.Rect(115, 120, 75, 75, "D")
+	fileStr := example.Filename("Fpdf_LinearGradient_gradient")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_LinearGradient_gradient.pdf
+}
+
+// ExampleFpdf_ClipText demonstrates clipping.
+func ExampleFpdf_ClipText() {
+	pdf := gofpdf.New("", "", "", "")
+	y := 10.0
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "", 24)
+	pdf.SetXY(0, y)
+	pdf.ClipText(10, y+12, "Clipping examples", false)
+	pdf.RadialGradient(10, y, 100, 20, 128, 128, 160, 32, 32, 48,
+		0.25, 0.5, 0.25, 0.5, 0.2)
+	pdf.ClipEnd()
+
+	y += 12
+	pdf.SetFont("Helvetica", "B", 120)
+	pdf.SetDrawColor(64, 80, 80)
+	pdf.SetLineWidth(.5)
+	pdf.ClipText(10, y+40, pdf.String(), true)
+	pdf.RadialGradient(10, y, 200, 50, 220, 220, 250, 80, 80, 220,
+		0.25, 0.5, 0.25, 0.5, 1)
+	pdf.ClipEnd()
+
+	y += 55
+	pdf.ClipRect(10, y, 105, 20, true)
+	pdf.SetFillColor(255, 255, 255)
+	pdf.Rect(10, y, 105, 20, "F")
+	pdf.ClipCircle(40, y+10, 15, false)
+	pdf.RadialGradient(25, y, 30, 30, 220, 250, 220, 40, 60, 40, 0.3,
+		0.85, 0.3, 0.85, 0.5)
+	pdf.ClipEnd()
+	pdf.ClipEllipse(80, y+10, 20, 15, false)
+	pdf.RadialGradient(60, y, 40, 30, 250, 220, 220, 60, 40, 40, 0.3,
+		0.85, 0.3, 0.85, 0.5)
+	pdf.ClipEnd()
+	pdf.ClipEnd()
+
+	y += 28
+	pdf.ClipEllipse(26, y+10, 16, 10, true)
+	pdf.Image(example.ImageFile("logo.jpg"), 10, y, 32, 0, false, "JPG", 0, "")
+	pdf.ClipEnd()
+
+	pdf.ClipCircle(60, y+10, 10, true)
+	pdf.RadialGradient(50, y, 20, 20, 220, 220, 250, 40, 40, 60, 0.3,
+		0.7, 0.3, 0.7, 0.5)
+	pdf.ClipEnd()
+
+	pdf.ClipPolygon([]gofpdf.PointType{{X: 80, Y: y + 20}, {X: 90, Y: y},
+		{X: 100, Y: y + 20}}, true)
+	pdf.LinearGradient(80, y, 20, 20, 250, 220, 250, 60, 40, 60, 0.5,
+		1, 0.5, 0.5)
+	pdf.ClipEnd()
+
+	y += 30
+	pdf.SetLineWidth(.1)
+	pdf.SetDrawColor(180, 180, 180)
+	pdf.ClipRoundedRect(10, y, 120, 20, 5, true)
+	pdf.RadialGradient(10, y, 120, 20, 255, 255, 255, 240, 240, 220,
+		0.25, 0.75, 0.25, 0.75, 0.5)
+	pdf.SetXY(5, y-5)
+	pdf.SetFont("Times", "", 12)
+	pdf.MultiCell(130, 5, lorem(), "", "", false)
+	pdf.ClipEnd()
+
+	y += 30
+	pdf.SetDrawColor(180, 100, 180)
+	pdf.ClipRoundedRectExt(10, y, 120, 20, 5, 10, 5, 10, true)
+	pdf.RadialGradient(10, y, 120, 20, 255, 255, 255, 240, 240, 220,
+		0.25, 0.75, 0.25, 0.75, 0.5)
+	pdf.SetXY(5, y-5)
+	pdf.SetFont("Times", "", 12)
+	pdf.MultiCell(130, 5, lorem(), "", "", false)
+	pdf.ClipEnd()
+
+	fileStr := example.Filename("Fpdf_ClipText")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_ClipText.pdf
+}
+
+// ExampleFpdf_PageSize generates a PDF document with various page sizes.
+func ExampleFpdf_PageSize() {
+	pdf := gofpdf.NewCustom(&gofpdf.InitType{
+		UnitStr:    "in",
+		Size:       gofpdf.SizeType{Wd: 6, Ht: 6},
+		FontDirStr: example.FontDir(),
+	})
+	pdf.SetMargins(0.5, 1, 0.5)
+	pdf.SetFont("Times", "", 14)
+	pdf.AddPageFormat("L", gofpdf.SizeType{Wd: 3, Ht: 12})
+	pdf.SetXY(0.5, 1.5)
+	pdf.CellFormat(11, 0.2, "12 in x 3 in", "", 0, "C", false, 0, "")
+	pdf.AddPage() // Default size established in NewCustom()
+	pdf.SetXY(0.5, 3)
+	pdf.CellFormat(5, 0.2, "6 in x 6 in", "", 0, "C", false, 0, "")
+	pdf.AddPageFormat("P", gofpdf.SizeType{Wd: 3, Ht: 12})
+	pdf.SetXY(0.5, 6)
+	pdf.CellFormat(2, 0.2, "3 in x 12 in", "", 0, "C", false, 0, "")
+	for j := 0; j <= 3; j++ {
+		wd, ht, u := pdf.PageSize(j)
+		fmt.Printf("%d: %6.2f %s, %6.2f %s\n", j, wd, u, ht, u)
+	}
+	fileStr := example.Filename("Fpdf_PageSize")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// 0:   6.00 in,   6.00 in
+	// 1:  12.00 in,   3.00 in
+	// 2:   6.00 in,   6.00 in
+	// 3:   3.00 in,  12.00 in
+	// Successfully generated pdf/Fpdf_PageSize.pdf
+}
+
+// ExampleFpdf_Bookmark demonstrates the Bookmark method.
+func ExampleFpdf_Bookmark() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Arial", "", 15)
+	pdf.Bookmark("Page 1", 0, 0)
+	pdf.Bookmark("Paragraph 1", 1, -1)
+	pdf.Cell(0, 6, "Paragraph 1")
+	pdf.Ln(50)
+	pdf.Bookmark("Paragraph 2", 1, -1)
+	pdf.Cell(0, 6, "Paragraph 2")
+	pdf.AddPage()
+	pdf.Bookmark("Page 2", 0, 0)
+	pdf.Bookmark("Paragraph 3", 1, -1)
+	pdf.Cell(0, 6, "Paragraph 3")
+	fileStr := example.Filename("Fpdf_Bookmark")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_Bookmark.pdf
+}
+
+// ExampleFpdf_TransformBegin demonstrates various transformations. It is adapted from an
+// example script by Moritz Wagner and Andreas Würmser.
+func ExampleFpdf_TransformBegin() {
+	const (
+		light = 200
+		dark  = 0
+	)
+	var refX, refY float64
+	var refStr string
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	color := func(val int) {
+		pdf.SetDrawColor(val, val, val)
+		pdf.SetTextColor(val, val, val)
+	}
+	reference := func(str string, x, y float64, val int) {
+		color(val)
+		pdf.Rect(x, y, 40, 10, "D")
+		pdf.Text(x, y-1, str)
+	}
+	refDraw := func(str string, x, y float64) {
+		refStr = str
+		refX = x
+		refY = y
+		reference(str, x, y, light)
+	}
+	refDupe := func() {
+		reference(refStr, refX, refY, dark)
+	}
+
+	titleStr := "Transformations"
+	titlePt := 36.0
+	titleHt := pdf.PointConvert(titlePt)
+	pdf.SetFont("Helvetica", "", titlePt)
+	titleWd := pdf.GetStringWidth(titleStr)
+	titleX := (210 - titleWd) / 2
+	pdf.Text(titleX, 10+titleHt, titleStr)
+	pdf.TransformBegin()
+	pdf.TransformMirrorVertical(10 + titleHt + 0.5)
+	pdf.ClipText(titleX, 10+titleHt, titleStr, false)
+	// Remember that the transform will mirror the gradient box too
+	pdf.LinearGradient(titleX, 10, titleWd, titleHt+4, 120, 120, 120,
+		255, 255, 255, 0, 0, 0, 0.6)
+	pdf.ClipEnd()
+	pdf.TransformEnd()
+
+	pdf.SetFont("Helvetica", "", 12)
+
+	// Scale by 150% centered by lower left corner of the rectangle
+	refDraw("Scale", 50, 60)
+	pdf.TransformBegin()
+	pdf.TransformScaleXY(150, 50, 70)
+	refDupe()
+	pdf.TransformEnd()
+
+	// Translate 7 to the right, 5 to the bottom
+	refDraw("Translate", 125, 60)
+	pdf.TransformBegin()
+	pdf.TransformTranslate(7, 5)
+	refDupe()
+	pdf.TransformEnd()
+
+	// Rotate 20 degrees counter-clockwise centered by the lower left corner of
+	// the rectangle
+	refDraw("Rotate", 50, 110)
+	pdf.TransformBegin()
+	pdf.TransformRotate(20, 50, 120)
+	refDupe()
+	pdf.TransformEnd()
+
+	// Skew 30 degrees along the x-axis centered by the lower left corner of the
+	// rectangle
+	refDraw("Skew", 125, 110)
+	pdf.TransformBegin()
+	pdf.TransformSkewX(30, 125, 110)
+	refDupe()
+	pdf.TransformEnd()
+
+	// Mirror horizontally with axis of reflection at left side of the rectangle
+	refDraw("Mirror horizontal", 50, 160)
+	pdf.TransformBegin()
+	pdf.TransformMirrorHorizontal(50)
+	refDupe()
+	pdf.TransformEnd()
+
+	// Mirror vertically with axis of reflection at bottom side of the rectangle
+	refDraw("Mirror vertical", 125, 160)
+	pdf.TransformBegin()
+	pdf.TransformMirrorVertical(170)
+	refDupe()
+	pdf.TransformEnd()
+
+	// Reflect against a point at the lower left point of rectangle
+	refDraw("Mirror point", 50, 210)
+	pdf.TransformBegin()
+	pdf.TransformMirrorPoint(50, 220)
+	refDupe()
+	pdf.TransformEnd()
+
+	// Mirror against a straight line described by a point and an angle
+	angle := -20.0
+	px := 120.0
+	py := 220.0
+	refDraw("Mirror line", 125, 210)
+	pdf.TransformBegin()
+	pdf.TransformRotate(angle, px, py)
+	pdf.Line(px-1, py-1, px+1, py+1)
+	pdf.Line(px-1, py+1, px+1, py-1)
+	pdf.Line(px-5, py, px+60, py)
+	pdf.TransformEnd()
+	pdf.TransformBegin()
+	pdf.TransformMirrorLine(angle, px, py)
+	refDupe()
+	pdf.TransformEnd()
+
+	fileStr := example.Filename("Fpdf_TransformBegin")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_TransformBegin.pdf
+}
+
+// ExampleFpdf_RegisterImage demonstrates Lawrence Kesteloot's image registration code.
+func ExampleFpdf_RegisterImage() {
+	const (
+		margin = 10
+		wd     = 210
+		ht     = 297
+	)
+	fileList := []string{
+		"logo-gray.png",
+		"logo.jpg",
+		"logo.png",
+		"logo-rgb.png",
+		"logo-progressive.jpg",
+	}
+	var infoPtr *gofpdf.ImageInfoType
+	var imageFileStr string
+	var imgWd, imgHt, lf, tp float64
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetMargins(10, 10, 10)
+	pdf.SetFont("Helvetica", "", 15)
+	for j, str := range fileList {
+		imageFileStr = example.ImageFile(str)
+		infoPtr = pdf.RegisterImage(imageFileStr, "")
+		imgWd, imgHt = infoPtr.Extent()
+		switch j {
+		case 0:
+			lf = margin
+			tp = margin
+		case 1:
+			lf = wd - margin - imgWd
+			tp = margin
+		case 2:
+			lf = (wd - imgWd) / 2.0
+			tp = (ht - imgHt) / 2.0
+		case 3:
+			lf = margin
+			tp = ht - imgHt - margin
+		case 4:
+			lf = wd - imgWd - margin
+			tp = ht - imgHt - margin
+		}
+		pdf.Image(imageFileStr, lf, tp, imgWd, imgHt, false, "", 0, "")
+	}
+	fileStr := example.Filename("Fpdf_RegisterImage")
+	// Test the image information retrieval method
+	infoShow := func(imageStr string) {
+		imageStr = example.ImageFile(imageStr)
+		info := pdf.GetImageInfo(imageStr)
+		if info != nil {
+			if info.Width() > 0.0 {
+				fmt.Printf("Image %s is registered\n", filepath.ToSlash(imageStr))
+			} else {
+				fmt.Printf("Incorrect information for image %s\n", filepath.ToSlash(imageStr))
+			}
+		} else {
+			fmt.Printf("Image %s is not registered\n", filepath.ToSlash(imageStr))
+		}
+	}
+	infoShow(fileList[0])
+	infoShow("foo.png")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Image image/logo-gray.png is registered
+	// Image image/foo.png is not registered
+	// Successfully generated pdf/Fpdf_RegisterImage.pdf
+}
+
+// ExampleFpdf_MeasureText demonstrates choosing the largest font size at which
+// a heading fits a given width without changing the current font.
+func ExampleFpdf_MeasureText() {
+	const (
+		wd    = 100.0
+		title = "Quarterly Report"
+	)
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Times", "", 12)
+	pdf.AddPage()
+	size := 48.0
+	for size > 6 && pdf.MeasureText("Helvetica", "B", size, title) > wd {
+		size--
+	}
+	fmt.Printf("Heading fits at %.0f points\n", size)
+	ptSize, _ := pdf.GetFontSize()
+	fmt.Printf("Current font size is still %.0f points\n", ptSize)
+	pdf.SetFont("Helvetica", "B", size)
+	pdf.CellFormat(wd, 20, title, "1", 1, "C", false, 0, "")
+	pdf.SetFont("Times", "", 12)
+	pdf.MultiCell(wd, 5, lorem(), "", "J", false)
+	fileStr := example.Filename("Fpdf_MeasureText")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Heading fits at 35 points
+	// Current font size is still 12 points
+	// Successfully generated pdf/Fpdf_MeasureText.pdf
+}
+
+// ExampleFpdf_Formula demonstrates the layout of mathematical formulas, both
+// inline with text and as centered display equations.
+func ExampleFpdf_Formula() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Times", "", 12)
+	pdf.AddPage()
+	pdf.Write(6, "The roots of the quadratic equation ")
+	pdf.WriteFormula(6, `ax^2 + bx + c = 0`)
+	pdf.Write(6, " are given by")
+	pdf.Ln(8)
+	display := func(tex string) {
+		wd, asc, dsc := pdf.FormulaSize(tex)
+		y := pdf.GetY() + asc
+		pdf.Formula((210-wd)/2, y, tex)
+		pdf.SetY(y + dsc + 4)
+	}
+	display(`x = \frac{-b \pm \sqrt{b^2 - 4ac}}{2a}`)
+	pdf.Write(6, "Euler's identity, ")
+	pdf.WriteFormula(6, `e^{i\pi} + 1 = 0`)
+	pdf.Write(6, ", relates five fundamental constants. A few more examples follow.")
+	pdf.Ln(10)
+	pdf.SetFontSize(14)
+	display(`\sum_{k=1}^{n} k^2 = \frac{n(n+1)(2n+1)}{6}`)
+	display(`\int_0^\infty e^{-x^2} dx = \frac{\sqrt{\pi}}{2}`)
+	pdf.SetTextColor(0, 0, 160)
+	display(`\Delta \Phi_B \approx \mu_0 \epsilon_0 \frac{\partial E}{\partial t}`)
+	display(`\lim_{x \to 0} \frac{\sin x}{x} = 1, \quad \alpha \ne \beta`)
+	fileStr := example.Filename("Fpdf_Formula")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_Formula.pdf
+}
+
+// ExampleFpdf_SplitLines demonstrates Bruno Michel's line splitting function.
+func ExampleFpdf_SplitLines() {
+	const (
+		fontPtSize = 18.0
+		wd         = 100.0
+	)
+	pdf := gofpdf.New("P", "mm", "A4", "") // A4 210.0 x 297.0
+	pdf.SetFont("Times", "", fontPtSize)
+	_, lineHt := pdf.GetFontSize()
+	pdf.AddPage()
+	pdf.SetMargins(10, 10, 10)
+	lines := pdf.SplitLines([]byte(lorem()), wd)
+	ht := float64(len(lines)) * lineHt
+	y := (297.0 - ht) / 2.0
+	pdf.SetDrawColor(128, 128, 128)
+	pdf.SetFillColor(255, 255, 210)
+	x := (210.0 - (wd + 40.0)) / 2.0
+	pdf.Rect(x, y-20.0, wd+40.0, ht+40.0, "FD")
+	pdf.SetY(y)
+	for _, line := range lines {
+		pdf.CellFormat(190.0, lineHt, string(line), "", 1, "C", false, 0, "")
+	}
+	fileStr := example.Filename("Fpdf_Splitlines")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_Splitlines.pdf
+}
+
+// ExampleFpdf_SVGBasicWrite demonstrates how to render a simple path-only SVG image of the
+// type generated by the jSignature web control.
+func ExampleFpdf_SVGBasicWrite() {
+	const (
+		fontPtSize = 16.0
+		wd         = 100.0
+		sigFileStr = "signature.svg"
+	)
+	var (
+		sig gofpdf.SVGBasicType
+		err error
+	)
+	pdf := gofpdf.New("P", "mm", "A4", "") // A4 210.0 x 297.0
+	pdf.SetFont("Times", "", fontPtSize)
+	lineHt := pdf.PointConvert(fontPtSize)
+	pdf.AddPage()
+	pdf.SetMargins(10, 10, 10)
+	htmlStr := `This example renders a simple ` +
+		`<a href="http://www.w3.org/TR/SVG/">SVG</a> (scalable vector graphics) ` +
+		`image that contains only basic path commands without any styling, ` +
+		`color fill, reflection or endpoint closures. In particular, the ` +
+		`type of vector graphic returned from a ` +
+		`<a href="http://willowsystems.github.io/jSignature/#/demo/">jSignature</a> ` +
+		`web control is supported and is used in this example.`
+	html := pdf.HTMLBasicNew()
+	html.Write(lineHt, htmlStr)
+	sig, err = gofpdf.SVGBasicFileParse(example.ImageFile(sigFileStr))
+	if err == nil {
+		scale := 100 / sig.Wd
+		scaleY := 30 / sig.Ht
+		if scale > scaleY {
+			scale = scaleY
+		}
+		pdf.SetLineCapStyle("round")
+		pdf.SetLineWidth(0.25)
+		pdf.SetDrawColor(0, 0, 128)
+		pdf.SetXY((210.0-scale*sig.Wd)/2.0, pdf.GetY()+10)
+		pdf.SVGBasicWrite(&sig, scale)
+	} else {
+		pdf.SetError(err)
+	}
+	fileStr := example.Filename("Fpdf_SVGBasicWrite")
+	err = pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SVGBasicWrite.pdf
+}
+
+// ExampleFpdf_CellFormat_align demonstrates Stefan Schroeder's code to control vertical
+// alignment.
+func ExampleFpdf_CellFormat_align() {
+	type recType struct {
+		align, txt string
+	}
+	recList := []recType{
+		{"TL", "top left"},
+		{"TC", "top center"},
+		{"TR", "top right"},
+		{"LM", "middle left"},
+		{"CM", "middle center"},
+		{"RM", "middle right"},
+		{"BL", "bottom left"},
+		{"BC", "bottom center"},
+		{"BR", "bottom right"},
+	}
+	recListBaseline := []recType{
+		{"AL", "baseline left"},
+		{"AC", "baseline center"},
+		{"AR", "baseline right"},
+	}
+	var formatRect = func(pdf *gofpdf.Fpdf, recList []recType) {
+		linkStr := ""
+		for pageJ := 0; pageJ < 2; pageJ++ {
+			pdf.AddPage()
+			pdf.SetMargins(10, 10, 10)
+			pdf.SetAutoPageBreak(false, 0)
+			borderStr := "1"
+			for _, rec := range recList {
+				pdf.SetXY(20, 20)
+				pdf.CellFormat(170, 257, rec.txt, borderStr, 0, rec.align, false, 0, linkStr)
+				borderStr = ""
+			}
+			linkStr = "https://github.com/headlands-org/gofpdf"
+		}
+	}
+	pdf := gofpdf.New("P", "mm", "A4", "") // A4 210.0 x 297.0
+	pdf.SetFont("Helvetica", "", 16)
+	formatRect(pdf, recList)
+	formatRect(pdf, recListBaseline)
+	var fr fontResourceType
+	pdf.SetFontLoader(fr)
+	pdf.AddFont("Calligrapher", "", "calligra.json")
+	pdf.SetFont("Calligrapher", "", 16)
+	formatRect(pdf, recListBaseline)
+	fileStr := example.Filename("Fpdf_CellFormat_align")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Generalized font loader reading calligra.json
+	// Generalized font loader reading calligra.z
+	// Successfully generated pdf/Fpdf_CellFormat_align.pdf
+}
+
+// ExampleFpdf_CellFormat_codepageescape demonstrates the use of characters in the high range of the
+// Windows-1252 code page (gofdpf default). See the example for CellFormat (4)
+// for a way to do this automatically.
+func ExampleFpdf_CellFormat_codepageescape() {
+	pdf := gofpdf.New("P", "mm", "A4", "") // A4 210.0 x 297.0
+	fontSize := 16.0
+	pdf.SetFont("Helvetica", "", fontSize)
+	ht := pdf.PointConvert(fontSize)
+	write := func(str string) {
+		pdf.CellFormat(190, ht, str, "", 1, "C", false, 0, "")
+		pdf.Ln(ht)
+	}
+	pdf.AddPage()
+	htmlStr := `Until gofpdf supports UTF-8 encoded source text, source text needs ` +
+		`to be specified with all special characters escaped to match the code page ` +
+		`layout of the currently selected font. By default, gofdpf uses code page 1252.` +
+		` See <a href="http://en.wikipedia.org/wiki/Windows-1252">Wikipedia</a> for ` +
+		`a table of this layout.`
+	html := pdf.HTMLBasicNew()
+	html.Write(ht, htmlStr)
+	pdf.Ln(2 * ht)
+	write("Voix ambigu\xeb d'un c\x9cur qui au z\xe9phyr pr\xe9f\xe8re les jattes de kiwi.")
+	write("Falsches \xdcben von Xylophonmusik qu\xe4lt jeden gr\xf6\xdferen Zwerg.")
+	write("Heiz\xf6lr\xfccksto\xdfabd\xe4mpfung")
+	write("For\xe5rsj\xe6vnd\xf8gn / Efter\xe5rsj\xe6vnd\xf8gn")
+	fileStr := example.Filename("Fpdf_CellFormat_codepageescape")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_CellFormat_codepageescape.pdf
+}
+
+// ExampleFpdf_CellFormat_codepage demonstrates the automatic conversion of UTF-8 strings to an
+// 8-bit font encoding.
+func ExampleFpdf_CellFormat_codepage() {
+	pdf := gofpdf.New("P", "mm", "A4", example.FontDir()) // A4 210.0 x 297.0
+	// See documentation for details on how to generate fonts
+	pdf.AddFont("Helvetica-1251", "", "helvetica_1251.json")
+	pdf.AddFont("Helvetica-1253", "", "helvetica_1253.json")
+	fontSize := 16.0
+	pdf.SetFont("Helvetica", "", fontSize)
+	ht := pdf.PointConvert(fontSize)
+	tr := pdf.UnicodeTranslatorFromDescriptor("") // "" defaults to "cp1252"
+	write := func(str string) {
+		// pdf.CellFormat(190, ht, tr(str), "", 1, "C", false, 0, "")
+		pdf.MultiCell(190, ht, tr(str), "", "C", false)
+		pdf.Ln(ht)
+	}
+	pdf.AddPage()
+	str := `Gofpdf provides a translator that will convert any UTF-8 code point ` +
+		`that is present in the specified code page.`
+	pdf.MultiCell(190, ht, str, "", "L", false)
+	pdf.Ln(2 * ht)
+	write("Voix ambiguë d'un cœur qui au zéphyr préfère les jattes de kiwi.")
+	write("Falsches Üben von Xylophonmusik quält jeden größeren Zwerg.")
+	write("Heizölrückstoßabdämpfung")
+	write("Forårsjævndøgn / Efterårsjævndøgn")
+	write("À noite, vovô Kowalsky vê o ímã cair no pé do pingüim queixoso e vovó" +
+		"põe açúcar no chá de tâmaras do jabuti feliz.")
+	pdf.SetFont("Helvetica-1251", "", fontSize) // Name matches one specified in AddFont()
+	tr = pdf.UnicodeTranslatorFromDescriptor("cp1251")
+	write("Съешь же ещё этих мягких французских булок, да выпей чаю.")
+
+	pdf.SetFont("Helvetica-1253", "", fontSize)
+	tr = pdf.UnicodeTranslatorFromDescriptor("cp1253")
+	write("Θέλει αρετή και τόλμη η ελευθερία. (Ανδρέας Κάλβος)")
+
+	fileStr := example.Filename("Fpdf_CellFormat_codepage")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_CellFormat_codepage.pdf
+}
+
+// ExampleFpdf_SetFont_codepage demonstrates the built-in Cyrillic and Greek
+// Helvetica families, which need no font definition files.
+func ExampleFpdf_SetFont_codepage() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	fontSize := 16.0
+	ht := pdf.PointConvert(fontSize)
+	pdf.AddPage()
+	pdf.SetFont("Helvetica-1251", "", fontSize)
+	tr := pdf.UnicodeTranslatorFromDescriptor("cp1251")
+	pdf.MultiCell(190, ht, tr("Съешь же ещё этих мягких французских булок, да выпей чаю."), "", "L", false)
+	pdf.Ln(ht)
+	pdf.SetFont("Helvetica-1253", "", fontSize)
+	tr = pdf.UnicodeTranslatorFromDescriptor("cp1253")
+	pdf.MultiCell(190, ht, tr("Θέλει αρετή και τόλμη η ελευθερία. (Ανδρέας Κάλβος)"), "", "L", false)
+	fileStr := example.Filename("Fpdf_SetFont_codepage")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetFont_codepage.pdf
+}
+
+// ExampleZapfGlyph demonstrates selecting ZapfDingbats and Symbol glyphs by
+// name rather than by byte code.
+func ExampleZapfGlyph() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	for _, item := range []struct{ glyph, label string }{
+		{"a20", "Done"},
+		{"a24", "Rejected"},
+		{"a35", "Featured"},
+	} {
+		pdf.SetFont("ZapfDingbats", "", 14)
+		pdf.Cell(8, 8, gofpdf.ZapfGlyph(item.glyph))
+		pdf.SetFont("Helvetica", "", 14)
+		pdf.Cell(40, 8, item.label)
+		pdf.Ln(-1)
+	}
+	pdf.SetFont("StandardSymbol", "", 14)
+	pdf.Cell(40, 8, gofpdf.SymbolGlyph("alpha")+" "+gofpdf.SymbolGlyph("arrowright")+
+		" "+gofpdf.SymbolGlyph("infinity"))
+	fileStr := example.Filename("ZapfGlyph")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/ZapfGlyph.pdf
+}
+
+// ExampleFpdf_SetProtection demonstrates password protection for documents.
+func ExampleFpdf_SetProtection() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetProtection(gofpdf.CnProtectPrint, "123", "abc")
+	pdf.AddPage()
+	pdf.SetFont("Arial", "", 12)
+	pdf.Write(10, "Password-protected.")
+	fileStr := example.Filename("Fpdf_SetProtection")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetProtection.pdf
+}
+
+// ExampleFpdf_SetProtectAttachmentsOnly demonstrates a public cover sheet
+// for a protected report. The report is generated as a separate document and
+// attached to the cover; the cover can be read by anyone, while the password
+// 123 is required to open the report.
+func ExampleFpdf_SetProtectAttachmentsOnly() {
+	report := gofpdf.New("P", "mm", "A4", "")
+	report.AddPage()
+	report.SetFont("Helvetica", "", 12)
+	report.MultiCell(0, 6, "Quarterly results: revenue rose by 4 percent while costs "+
+		"remained unchanged.", "", "L", false)
+	var buf bytes.Buffer
+	err := report.Output(&buf)
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetProtection(gofpdf.CnProtectPrint, "123", "abc")
+	pdf.SetProtectAttachmentsOnly(true)
+	pdf.SetAttachments([]gofpdf.Attachment{{Content: buf.Bytes(), Filename: "report.pdf",
+		Description: "Quarterly report"}})
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "B", 20)
+	pdf.Cell(0, 12, "Quarterly report")
+	pdf.Ln(14)
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.MultiCell(0, 6, "This cover sheet is public. The report is attached to this "+
+		"document and is protected by a password.", "", "L", false)
+	if err != nil {
+		pdf.SetError(err)
+	}
+	fileStr := example.Filename("Fpdf_SetProtectAttachmentsOnly")
+	err = pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetProtectAttachmentsOnly.pdf
+}
+
+// ExampleFpdf_Polygon displays equilateral polygons in a demonstration of the Polygon
+// function.
+func ExampleFpdf_Polygon() {
+	const rowCount = 5
+	const colCount = 4
+	const ptSize = 36
+	var x, y, radius, gap, advance float64
+	var rgVal int
+	var pts []gofpdf.PointType
+	vertices := func(count int) (res []gofpdf.PointType) {
+		var pt gofpdf.PointType
+		res = make([]gofpdf.PointType, 0, count)
+		mlt := 2.0 * math.Pi / float64(count)
+		for j := 0; j < count; j++ {
+			pt.Y, pt.X = math.Sincos(float64(j) * mlt)
+			res = append(res, gofpdf.PointType{
+				X: x + radius*pt.X,
+				Y: y + radius*pt.Y})
+		}
+		return
+	}
+	pdf := gofpdf.New("P", "mm", "A4", "") // A4 210.0 x 297.0
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", ptSize)
+	pdf.SetDrawColor(0, 80, 180)
+	gap = 12.0
+	pdf.SetY(gap)
+	pdf.CellFormat(190.0, gap, "Equilateral polygons", "", 1, "C", false, 0, "")
+	radius = (210.0 - float64(colCount+1)*gap) / (2.0 * float64(colCount))
+	advance = gap + 2.0*radius
+	y = 2*gap + pdf.PointConvert(ptSize) + radius
+	rgVal = 230
+	for row := 0; row < rowCount; row++ {
+		pdf.SetFillColor(rgVal, rgVal, 0)
+		rgVal -= 12
+		x = gap + radius
+		for col := 0; col < colCount; col++ {
+			pts = vertices(row*colCount + col + 3)
+			pdf.Polygon(pts, "FD")
+			x += advance
+		}
+		y += advance
+	}
+	fileStr := example.Filename("Fpdf_Polygon")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_Polygon.pdf
+}
+
+// ExampleFpdf_AddLayer demonstrates document layers. The initial visibility of a layer
+// is specified with the second parameter to AddLayer(). The layer list
+// displayed by the document reader allows layer visibility to be controlled
+// interactively.
+func ExampleFpdf_AddLayer() {
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Arial", "", 15)
+	pdf.Write(8, "This line doesn't belong to any layer.\n")
+
+	// Define layers
+	l1 := pdf.AddLayer("Layer 1", true)
+	l2 := pdf.AddLayer("Layer 2", true)
+
+	// Open layer pane in PDF viewer
+	pdf.OpenLayerPane()
+
+	// First layer
+	pdf.BeginLayer(l1)
+	pdf.Write(8, "This line belongs to layer 1.\n")
+	pdf.EndLayer()
+
+	// Second layer
+	pdf.BeginLayer(l2)
+	pdf.Write(8, "This line belongs to layer 2.\n")
+	pdf.EndLayer()
+
+	// First layer again
+	pdf.BeginLayer(l1)
+	pdf.Write(8, "This line belongs to layer 1 again.\n")
+	pdf.EndLayer()
+
+	fileStr := example.Filename("Fpdf_AddLayer")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_AddLayer.pdf
+}
+
+// ExampleFpdf_RegisterImagesOptions demonstrates the concurrent registration
+// of the images of a catalog before they are placed on the page.
+func ExampleFpdf_RegisterImagesOptions() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.AddPage()
+	var fileList []string
+	for _, str := range []string{"logo-gray.png", "logo.jpg", "logo.png", "logo-rgb.png",
+		"logo-progressive.jpg", "logo.gif", "golang-gopher.png", "mit.png", "sweden.png"} {
+		fileList = append(fileList, example.ImageFile(str))
+	}
+	infoList := pdf.RegisterImagesOptions(fileList, gofpdf.ImageOptions{ReadDpi: true}, 0)
+	const cellWd, cellHt = 60, 60
+	for j, info := range infoList {
+		if info == nil {
+			continue
+		}
+		x, y := 15+float64(j%3)*cellWd, 20+float64(j/3)*(cellHt+10)
+		// Scale each image to fit its cell
+		wd, ht := info.Width(), info.Height()
+		scale := math.Min((cellWd-10)/wd, (cellHt-10)/ht)
+		pdf.ImageOptions(fileList[j], x+(cellWd-wd*scale)/2, y, wd*scale, ht*scale, false,
+			gofpdf.ImageOptions{ReadDpi: true}, 0, "")
+		pdf.SetXY(x, y+cellHt-5)
+		pdf.CellFormat(cellWd, 5, filepath.Base(fileList[j]), "", 0, "C", false, 0, "")
+	}
+	fileStr := example.Filename("Fpdf_RegisterImagesOptions")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_RegisterImagesOptions.pdf
+}
+
+// ExampleFpdf_RegisterImageReader demonstrates the use of an image that is retrieved from a web
+// server.
+func ExampleFpdf_RegisterImageReader() {
+
+	const (
+		margin   = 10
+		wd       = 210
+		ht       = 297
+		fontSize = 15
+		urlStr   = "https://github.com/headlands-org/gofpdf/blob/master/image/gofpdf.png?raw=true"
+		msgStr   = `Images from the web can be easily embedded when a PDF document is generated.`
+	)
+
+	var (
+		rsp *http.Response
+		err error
+		tp  string
+	)
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", fontSize)
+	ln := pdf.PointConvert(fontSize)
+	pdf.MultiCell(wd-margin-margin, ln, msgStr, "", "L", false)
+	rsp, err = http.Get(urlStr)
+	if err == nil {
+		tp = pdf.ImageTypeFromMime(rsp.Header["Content-Type"][0])
+		infoPtr := pdf.RegisterImageReader(urlStr, tp, rsp.Body)
+		if pdf.Ok() {
+			imgWd, imgHt := infoPtr.Extent()
+			pdf.Image(urlStr, (wd-imgWd)/2.0, pdf.GetY()+ln,
+				imgWd, imgHt, false, tp, 0, "")
+		}
+	} else {
+		pdf.SetError(err)
+	}
+	fileStr := example.Filename("Fpdf_RegisterImageReader_url")
+	err = pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_RegisterImageReader_url.pdf
+
+}
+
+// ExampleFpdf_Beziergon demonstrates the Beziergon function.
+func ExampleFpdf_Beziergon() {
+
+	const (
+		margin      = 10
+		wd          = 210
+		unit        = (wd - 2*margin) / 6
+		ht          = 297
+		fontSize    = 15
+		msgStr      = `Demonstration of Beziergon function`
+		coefficient = 0.6
+		delta       = coefficient * unit
+		ln          = fontSize * 25.4 / 72
+		offsetX     = (wd - 4*unit) / 2.0
+		offsetY     = offsetX + 2*ln
+	)
+
+	srcList := []gofpdf.PointType{
+		{X: 0, Y: 0},
+		{X: 1, Y: 0},
+		{X: 1, Y: 1},
+		{X: 2, Y: 1},
+		{X: 2, Y: 2},
+		{X: 3, Y: 2},
+		{X: 3, Y: 3},
+		{X: 4, Y: 3},
+		{X: 4, Y: 4},
+		{X: 1, Y: 4},
+		{X: 1, Y: 3},
+		{X: 0, Y: 3},
+	}
+
+	ctrlList := []gofpdf.PointType{
+		{X: 1, Y: -1},
+		{X: 1, Y: 1},
+		{X: 1, Y: 1},
+		{X: 1, Y: 1},
+		{X: 1, Y: 1},
+		{X: 1, Y: 1},
+		{X: 1, Y: 1},
+		{X: 1, Y: 1},
+		{X: -1, Y: 1},
+		{X: -1, Y: -1},
+		{X: -1, Y: -1},
+		{X: -1, Y: -1},
+	}
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", fontSize)
+	for j, src := range srcList {
+		srcList[j].X = offsetX + src.X*unit
+		srcList[j].Y = offsetY + src.Y*unit
+	}
+	for j, ctrl := range ctrlList {
+		ctrlList[j].X = ctrl.X * delta
+		ctrlList[j].Y = ctrl.Y * delta
+	}
+	jPrev := len(srcList) - 1
+	srcPrev := srcList[jPrev]
+	curveList := []gofpdf.PointType{srcPrev} // point [, control 0, control 1, point]*
+	control := func(x, y float64) {
+		curveList = append(curveList, gofpdf.PointType{X: x, Y: y})
+	}
+	for j, src := range srcList {
+		ctrl := ctrlList[jPrev]
+		control(srcPrev.X+ctrl.X, srcPrev.Y+ctrl.Y) // Control 0
+		ctrl = ctrlList[j]
+		control(src.X-ctrl.X, src.Y-ctrl.Y) // Control 1
+		curveList = append(curveList, src)  // Destination
+		jPrev = j
+		srcPrev = src
+	}
+	pdf.MultiCell(wd-margin-margin, ln, msgStr, "", "C", false)
+	pdf.SetDashPattern([]float64{0.8, 0.8}, 0)
+	pdf.SetDrawColor(160, 160, 160)
+	pdf.Polygon(srcList, "D")
+	pdf.SetDashPattern([]float64{}, 0)
+	pdf.SetDrawColor(64, 64, 128)
+	pdf.SetLineWidth(pdf.GetLineWidth() * 3)
+	pdf.Beziergon(curveList, "D")
+	fileStr := example.Filename("Fpdf_Beziergon")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_Beziergon.pdf
+
+}
+
+// ExampleFpdf_SetFontLoader demonstrates loading a non-standard font using a generalized
+// font loader. fontResourceType implements the FontLoader interface and is
+// defined locally in the test source code.
+func ExampleFpdf_SetFontLoader() {
+	var fr fontResourceType
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFontLoader(fr)
+	pdf.AddFont("Calligrapher", "", "calligra.json")
+	pdf.AddPage()
+	pdf.SetFont("Calligrapher", "", 35)
+	pdf.Cell(0, 10, "Load fonts from any source")
+	fileStr := example.Filename("Fpdf_SetFontLoader")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Generalized font loader reading calligra.json
+	// Generalized font loader reading calligra.z
+	// Successfully generated pdf/Fpdf_SetFontLoader.pdf
+}
+
+// ExampleFpdf_MoveTo demonstrates the Path Drawing functions, such as: MoveTo,
+// LineTo, CurveTo, ..., ClosePath and DrawPath.
+func ExampleFpdf_MoveTo() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.MoveTo(20, 20)
+	pdf.LineTo(170, 20)
+	pdf.ArcTo(170, 40, 20, 20, 0, 90, 0)
+	pdf.CurveTo(190, 100, 105, 100)
+	pdf.CurveBezierCubicTo(20, 100, 105, 200, 20, 200)
+	pdf.ClosePath()
+	pdf.SetFillColor(200, 200, 200)
+	pdf.SetLineWidth(3)
+	pdf.DrawPath("DF")
+	fileStr := example.Filename("Fpdf_MoveTo_path")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_MoveTo_path.pdf
+}
+
+// ExampleFpdf_SetLineJoinStyle demonstrates various line cap and line join styles.
+func ExampleFpdf_SetLineJoinStyle() {
+	const offset = 75.0
+	pdf := gofpdf.New("L", "mm", "A4", "")
+	pdf.AddPage()
+	var draw = func(cap, join string, x0, y0, x1, y1 float64) {
+		// transform begin & end needed to isolate caps and joins
+		pdf.SetLineCapStyle(cap)
+		pdf.SetLineJoinStyle(join)
+
+		// Draw thick line
+		pdf.SetDrawColor(0x33, 0x33, 0x33)
+		pdf.SetLineWidth(30.0)
+		pdf.MoveTo(x0, y0)
+		pdf.LineTo((x0+x1)/2+offset, (y0+y1)/2)
+		pdf.LineTo(x1, y1)
+		pdf.DrawPath("D")
+
+		// Draw thin helping line
+		pdf.SetDrawColor(0xFF, 0x33, 0x33)
+		pdf.SetLineWidth(2.56)
+		pdf.MoveTo(x0, y0)
+		pdf.LineTo((x0+x1)/2+offset, (y0+y1)/2)
+		pdf.LineTo(x1, y1)
+		pdf.DrawPath("D")
+
+	}
+	x := 35.0
+	caps := []string{"butt", "square", "round"}
+	joins := []string{"bevel", "miter", "round"}
+	for i := range caps {
+		draw(caps[i], joins[i], x, 50, x, 160)
+		x += offset
+	}
+	fileStr := example.Filename("Fpdf_SetLineJoinStyle_caps")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetLineJoinStyle_caps.pdf
+}
+
+// ExampleFpdf_DrawPath demonstrates various fill modes.
+func ExampleFpdf_DrawPath() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetDrawColor(0xff, 0x00, 0x00)
+	pdf.SetFillColor(0x99, 0x99, 0x99)
+	pdf.SetFont("Helvetica", "", 15)
+	pdf.AddPage()
+	pdf.SetAlpha(1, "Multiply")
+	var (
+		polygon = func(cx, cy, r, n, dir float64) {
+			da := 2 * math.Pi / n
+			pdf.MoveTo(cx+r, cy)
+			pdf.Text(cx+r, cy, "0")
+			i := 1
+			for a := da; a < 2*math.Pi; a += da {
+				x, y := cx+r*math.Cos(dir*a), cy+r*math.Sin(dir*a)
+				pdf.LineTo(x, y)
+				pdf.Text(x, y, strconv.Itoa(i))
+				i++
+			}
+			pdf.ClosePath()
+		}
+		polygons = func(cx, cy, r, n, dir float64) {
+			d := 1.0
+			for rf := r; rf > 0; rf -= 10 {
+				polygon(cx, cy, rf, n, d)
+				d *= dir
+			}
+		}
+		star = func(cx, cy, r, n float64) {
+			da := 4 * math.Pi / n
+			pdf.MoveTo(cx+r, cy)
+			for a := da; a < 4*math.Pi+da; a += da {
+				x, y := cx+r*math.Cos(a), cy+r*math.Sin(a)
+				pdf.LineTo(x, y)
+			}
+			pdf.ClosePath()
+		}
+	)
+	// triangle
+	polygons(55, 45, 40, 3, 1)
+	pdf.DrawPath("B")
+	pdf.Text(15, 95, "B (same direction, non zero winding)")
+
+	// square
+	polygons(155, 45, 40, 4, 1)
+	pdf.DrawPath("B*")
+	pdf.Text(115, 95, "B* (same direction, even odd)")
+
+	// pentagon
+	polygons(55, 145, 40, 5, -1)
+	pdf.DrawPath("B")
+	pdf.Text(15, 195, "B (different direction, non zero winding)")
+
+	// hexagon
+	polygons(155, 145, 40, 6, -1)
+	pdf.DrawPath("B*")
+	pdf.Text(115, 195, "B* (different direction, even odd)")
+
+	// star
+	star(55, 245, 40, 5)
+	pdf.DrawPath("B")
+	pdf.Text(15, 290, "B (non zero winding)")
+
+	// star
+	star(155, 245, 40, 5)
+	pdf.DrawPath("B*")
+	pdf.Text(115, 290, "B* (even odd)")
+
+	fileStr := example.Filename("Fpdf_DrawPath_fill")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_DrawPath_fill.pdf
+}
+
+// ExampleFpdf_CreateTemplate demonstrates creating and using templates
+func ExampleFpdf_CreateTemplate() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	// pdf.SetFont("Times", "", 12)
+	template := pdf.CreateTemplate(func(tpl *gofpdf.Tpl) {
+		tpl.Image(example.ImageFile("logo.png"), 6, 6, 30, 0, false, "", 0, "")
+		tpl.SetFont("Arial", "B", 16)
+		tpl.Text(40, 20, "Template says hello")
+		tpl.SetDrawColor(0, 100, 200)
+		tpl.SetLineWidth(2.5)
+		tpl.Line(95, 12, 105, 22)
+	})
+	_, tplSize := template.Size()
+	// fmt.Println("Size:", tplSize)
+	// fmt.Println("Scaled:", tplSize.ScaleBy(1.5))
+
+	template2 := pdf.CreateTemplate(func(tpl *gofpdf.Tpl) {
+		tpl.UseTemplate(template)
+		subtemplate := tpl.CreateTemplate(func(tpl2 *gofpdf.Tpl) {
+			tpl2.Image(example.ImageFile("logo.png"), 6, 86, 30, 0, false, "", 0, "")
+			tpl2.SetFont("Arial", "B", 16)
+			tpl2.Text(40, 100, "Subtemplate says hello")
+			tpl2.SetDrawColor(0, 200, 100)
+			tpl2.SetLineWidth(2.5)
+			tpl2.Line(102, 92, 112, 102)
+		})
+		tpl.UseTemplate(subtemplate)
+	})
+
+	pdf.SetDrawColor(200, 100, 0)
+	pdf.SetLineWidth(2.5)
+	pdf.SetFont("Arial", "B", 16)
+
+	// serialize and deserialize template
+	b, _ := template2.Serialize()
+	template3, _ := gofpdf.DeserializeTemplate(b)
+
+	pdf.AddPage()
+	pdf.UseTemplate(template3)
+	pdf.UseTemplateScaled(template3, gofpdf.PointType{X: 0, Y: 30}, tplSize)
+	pdf.Line(40, 210, 60, 210)
+	pdf.Text(40, 200, "Template example page 1")
+
+	pdf.AddPage()
+	pdf.UseTemplate(template2)
+	pdf.UseTemplateScaled(template3, gofpdf.PointType{X: 0, Y: 30}, tplSize.ScaleBy(1.4))
+	pdf.Line(60, 210, 80, 210)
+	pdf.Text(40, 200, "Template example page 2")
+
+	fileStr := example.Filename("Fpdf_CreateTemplate")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_CreateTemplate.pdf
+}
+
+// ExampleFpdf_AddFontFromBytes demonstrate how to use embedded fonts from byte array
+func ExampleFpdf_AddFontFromBytes() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.AddFontFromBytes("calligra", "", files.CalligraJson, files.CalligraZ)
+	pdf.SetFont("calligra", "", 16)
+	pdf.Cell(40, 10, "Hello World With Embedded Font!")
+	fileStr := example.Filename("Fpdf_EmbeddedFont")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_EmbeddedFont.pdf
+}
+
+// This example demonstrate Clipped table cells
+func ExampleFpdf_ClipRect() {
+	marginCell := 2. // margin of top/bottom of cell
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Arial", "", 12)
+	pdf.AddPage()
+	pagew, pageh := pdf.GetPageSize()
+	mleft, mright, _, mbottom := pdf.GetMargins()
+
+	cols := []float64{60, 100, pagew - mleft - mright - 100 - 60}
+	rows := [][]string{}
+	for i := 1; i <= 50; i++ {
+		word := fmt.Sprintf("%d:%s", i, strings.Repeat("A", i%100))
+		rows = append(rows, []string{word, word, word})
+	}
+
+	for _, row := range rows {
+		_, lineHt := pdf.GetFontSize()
+		height := lineHt + marginCell
+
+		x, y := pdf.GetXY()
+		// add a new page if the height of the row doesn't fit on the page
+		if y+height >= pageh-mbottom {
+			pdf.AddPage()
+			x, y = pdf.GetXY()
+		}
+		for i, txt := range row {
+			width := cols[i]
+			pdf.Rect(x, y, width, height, "")
+			pdf.ClipRect(x, y, width, height, false)
+			pdf.Cell(width, height, txt)
+			pdf.ClipEnd()
+			x += width
+		}
+		pdf.Ln(-1)
+	}
+	fileStr := example.Filename("Fpdf_ClippedTableCells")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_ClippedTableCells.pdf
+}
+
+// This example demonstrate wrapped table cells
+func ExampleFpdf_Rect() {
+	marginCell := 2. // margin of top/bottom of cell
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Arial", "", 12)
+	pdf.AddPage()
+	pagew, pageh := pdf.GetPageSize()
+	mleft, mright, _, mbottom := pdf.GetMargins()
+
+	cols := []float64{60, 100, pagew - mleft - mright - 100 - 60}
+	rows := [][]string{}
+	for i := 1; i <= 30; i++ {
+		word := fmt.Sprintf("%d:%s", i, strings.Repeat("A", i%100))
+		rows = append(rows, []string{word, word, word})
+	}
+
+	for _, row := range rows {
+		curx, y := pdf.GetXY()
+		x := curx
+
+		height := 0.
+		_, lineHt := pdf.GetFontSize()
+
+		for i, txt := range row {
+			lines := pdf.SplitLines([]byte(txt), cols[i])
+			h := float64(len(lines))*lineHt + marginCell*float64(len(lines))
+			if h > height {
+				height = h
+			}
+		}
+		// add a new page if the height of the row doesn't fit on the page
+		if pdf.GetY()+height > pageh-mbottom {
+			pdf.AddPage()
+			y = pdf.GetY()
+		}
+		for i, txt := range row {
+			width := cols[i]
+			pdf.Rect(x, y, width, height, "")
+			pdf.MultiCell(width, lineHt+marginCell, txt, "", "", false)
+			x += width
+			pdf.SetXY(x, y)
+		}
+		pdf.SetXY(curx, y+height)
+	}
+	fileStr := example.Filename("Fpdf_WrappedTableCells")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_WrappedTableCells.pdf
+}
+
+// ExampleFpdf_SetJavascript demonstrates including JavaScript in the document.
+func ExampleFpdf_SetJavascript() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetJavascript("print(true);")
+	pdf.AddPage()
+	pdf.SetFont("Arial", "", 12)
+	pdf.Write(10, "Auto-print.")
+	fileStr := example.Filename("Fpdf_SetJavascript")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetJavascript.pdf
+}
+
+// ExampleFpdf_AddSpotColor demonstrates spot color use
+func ExampleFpdf_AddSpotColor() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddSpotColor("PANTONE 145 CVC", 0, 42, 100, 25)
+	pdf.AddPage()
+	pdf.SetFillSpotColor("PANTONE 145 CVC", 90)
+	pdf.Rect(80, 40, 50, 50, "F")
+	fileStr := example.Filename("Fpdf_AddSpotColor")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_AddSpotColor.pdf
+}
+
+// ExampleFpdf_RegisterAlias demonstrates how to use `RegisterAlias` to create a table of
+// contents.
+func ExampleFpdf_RegisterAlias() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Arial", "", 12)
+	pdf.AliasNbPages("")
+	pdf.AddPage()
+
+	// Write the table of contents. We use aliases instead of the page number
+	// because we don't know which page the section will begin on.
+	numSections := 3
+	for i := 1; i <= numSections; i++ {
+		pdf.Cell(0, 10, fmt.Sprintf("Section %d begins on page {mark %d}", i, i))
+		pdf.Ln(10)
+	}
+
+	// Write the sections. Before we start writing, we use `RegisterAlias` to
+	// ensure that the alias written in the table of contents will be replaced
+	// by the current page number.
+	for i := 1; i <= numSections; i++ {
+		pdf.AddPage()
+		pdf.RegisterAlias(fmt.Sprintf("{mark %d}", i), fmt.Sprintf("%d", pdf.PageNo()))
+		pdf.Write(10, fmt.Sprintf("Section %d, page %d of {nb}", i, pdf.PageNo()))
+	}
+
+	fileStr := example.Filename("Fpdf_RegisterAlias")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_RegisterAlias.pdf
+}
+
+// ExampleFpdf_RegisterAlias_utf8 demonstrates how to use `RegisterAlias` to
+// create a table of contents. This particular example demonstrates the use of
+// UTF-8 aliases.
+func ExampleFpdf_RegisterAlias_utf8() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.SetFont("dejavu", "", 12)
+	pdf.AliasNbPages("{entute}")
+	pdf.AddPage()
+
+	// Write the table of contents. We use aliases instead of the page number
+	// because we don't know which page the section will begin on.
+	numSections := 3
+	for i := 1; i <= numSections; i++ {
+		pdf.Cell(0, 10, fmt.Sprintf("Sekcio %d komenciĝas ĉe paĝo {ĉi tiu marko %d}", i, i))
+		pdf.Ln(10)
+	}
+
+	// Write the sections. Before we start writing, we use `RegisterAlias` to
+	// ensure that the alias written in the table of contents will be replaced
+	// by the current page number.
+	for i := 1; i <= numSections; i++ {
+		pdf.AddPage()
+		pdf.RegisterAlias(fmt.Sprintf("{ĉi tiu marko %d}", i), fmt.Sprintf("%d", pdf.PageNo()))
+		pdf.Write(10, fmt.Sprintf("Sekcio %d, paĝo %d de {entute}", i, pdf.PageNo()))
+	}
+
+	fileStr := example.Filename("Fpdf_RegisterAliasUTF8")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_RegisterAliasUTF8.pdf
+}
+
+// ExampleNewGrid demonstrates the generation of graph grids.
+func ExampleNewGrid() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Arial", "", 12)
+	pdf.AddPage()
+
+	gr := gofpdf.NewGrid(13, 10, 187, 130)
+	gr.TickmarksExtentX(0, 10, 4)
+	gr.TickmarksExtentY(0, 10, 3)
+	gr.Grid(pdf)
+
+	gr = gofpdf.NewGrid(13, 154, 187, 128)
+	gr.XLabelRotate = true
+	gr.TickmarksExtentX(0, 1, 12)
+	gr.XDiv = 5
+	gr.TickmarksContainY(0, 1.1)
+	gr.YDiv = 20
+	// Replace X label formatter with month abbreviation
+	gr.XTickStr = func(val float64, precision int) string {
+		return time.Month(math.Mod(val, 12) + 1).String()[0:3]
+	}
+	gr.Grid(pdf)
+	dot := func(x, y float64) {
+		pdf.Circle(gr.X(x), gr.Y(y), 0.5, "F")
+	}
+	pts := []float64{0.39, 0.457, 0.612, 0.84, 0.998, 1.037, 1.015, 0.918, 0.772, 0.659, 0.593, 0.164}
+	for month, val := range pts {
+		dot(float64(month)+0.5, val)
+	}
+	pdf.SetDrawColor(255, 64, 64)
+	pdf.SetAlpha(0.5, "Normal")
+	pdf.SetLineWidth(1.2)
+	gr.Plot(pdf, 0.5, 11.5, 50, func(x float64) float64 {
+		// http://www.xuru.org/rt/PR.asp
+		return 0.227 * math.Exp(-0.0373*x*x+0.471*x)
+	})
+	pdf.SetAlpha(1.0, "Normal")
+	pdf.SetXY(gr.X(0.5), gr.Y(1.35))
+	pdf.SetFontSize(14)
+	pdf.Write(0, "Solar energy (MWh) per month, 2016")
+	pdf.AddPage()
+
+	gr = gofpdf.NewGrid(13, 10, 187, 274)
+	gr.TickmarksContainX(2.3, 3.4)
+	gr.TickmarksContainY(10.4, 56.8)
+	gr.Grid(pdf)
+
+	fileStr := example.Filename("Fpdf_Grid")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_Grid.pdf
+}
+
+// ExampleNewScaleBar demonstrates the cartographic furniture of a map plot:
+// a scale bar, a legend and a north arrow.
+func ExampleNewScaleBar() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.AddPage()
+	// A map area 160 mm wide covering 8 km of ground from west to east
+	gr := gofpdf.NewGrid(25, 30, 160, 160)
+	gr.TickmarksExtentX(0, 1000, 8)
+	gr.TickmarksExtentY(0, 1000, 8)
+	gr.Grid(pdf)
+	pdf.SetFillColor(170, 210, 240)
+	pdf.Polygon([]gofpdf.PointType{{X: gr.X(0), Y: gr.Y(5200)}, {X: gr.X(3000), Y: gr.Y(4600)},
+		{X: gr.X(3600), Y: gr.Y(2800)}, {X: gr.X(1200), Y: gr.Y(2000)}, {X: gr.X(0), Y: gr.Y(2600)}}, "F")
+	pdf.SetDrawColor(200, 40, 40)
+	pdf.SetLineWidth(0.8)
+	pdf.Line(gr.X(500), gr.Y(7500), gr.X(7600), gr.Y(600))
+	pdf.SetFillColor(40, 120, 40)
+	pdf.Circle(gr.X(5500), gr.Y(5500), 1.5, "F")
+	pdf.Circle(gr.X(6300), gr.Y(3000), 1.5, "F")
+	sb := gofpdf.NewScaleBar(8000.0/160, "m")
+	sb.Draw(pdf, 25, 200, 80)
+	lg := gofpdf.NewLegend("Legend")
+	lg.Add("Lake", gofpdf.LegendBox, gofpdf.RGBType{R: 170, G: 210, B: 240})
+	lg.Add("Highway", gofpdf.LegendLine, gofpdf.RGBType{R: 200, G: 40, B: 40})
+	lg.Add("Campsite", gofpdf.LegendDot, gofpdf.RGBType{R: 40, G: 120, B: 40})
+	lg.Draw(pdf, 130, 200)
+	pdf.SetDrawColor(0, 0, 0)
+	pdf.SetFillColor(0, 0, 0)
+	gofpdf.NorthArrow(pdf, 170, 45, 15, 0)
+	fileStr := example.Filename("Fpdf_ScaleBar")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_ScaleBar.pdf
+}
+
+// ExampleNewDimension demonstrates the annotation of a simple technical
+// drawing with linear, radial and angular dimensions.
+func ExampleNewDimension() {
+	pdf := gofpdf.New("L", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.AddPage()
+	// A plate with a hole and a chamfered corner, drawn at 1:2
+	pdf.SetLineWidth(0.5)
+	pdf.Polygon([]gofpdf.PointType{{X: 60, Y: 60}, {X: 180, Y: 60}, {X: 220, Y: 100},
+		{X: 220, Y: 160}, {X: 60, Y: 160}}, "D")
+	pdf.Circle(110, 110, 25, "D")
+	dim := gofpdf.NewDimension(3)
+	dim.Scale = 2
+	dim.Precision = 0
+	dim.UnitStr = " mm"
+	dim.Linear(pdf, 60, 60, 220, 60, 15, "")
+	dim.Linear(pdf, 60, 160, 60, 60, 15, "")
+	dim.Linear(pdf, 220, 160, 220, 100, -15, "")
+	dim.Linear(pdf, 180, 60, 220, 100, 10, "")
+	dim.Clr = gofpdf.RGBType{R: 0, G: 80, B: 180}
+	dim.Radial(pdf, 110, 110, 25, 45, "")
+	pdf.SetDrawColor(160, 160, 160)
+	pdf.SetDashPattern([]float64{2, 1}, 0)
+	pdf.Line(180, 60, 180, 100)
+	pdf.Line(180, 100, 220, 100)
+	pdf.SetDashPattern([]float64{}, 0)
+	dim.Angular(pdf, 180, 100, 25, 45, 90, "")
+	fileStr := example.Filename("Fpdf_Dimension")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_Dimension.pdf
+}
+
+// ExampleNewPaper demonstrates the generation of graph paper, dot grid, ruled
+// and isometric pages.
+func ExampleNewPaper() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.AddPage()
+	gofpdf.NewPaper(gofpdf.PaperGraph, 5).DrawPage(pdf)
+	pdf.AddPage()
+	dots := gofpdf.NewPaper(gofpdf.PaperDot, 5)
+	dots.Clr = gofpdf.RGBAType{R: 120, G: 120, B: 120, Alpha: 1}
+	dots.DotSize = 0.6
+	dots.DrawPage(pdf)
+	pdf.AddPage()
+	ruled := gofpdf.NewPaper(gofpdf.PaperRuled, 8)
+	ruled.RuledMargin = 25
+	ruled.ClrMajor = gofpdf.RGBAType{R: 230, G: 100, B: 100, Alpha: 1}
+	ruled.DrawPage(pdf)
+	pdf.AddPage()
+	gofpdf.NewPaper(gofpdf.PaperIsometric, 8).Draw(pdf, 20, 20, 170, 120)
+	gofpdf.NewPaper(gofpdf.PaperGraph, 2).Draw(pdf, 20, 150, 170, 120)
+	fileStr := example.Filename("Fpdf_Paper")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_Paper.pdf
+}
+
+// ExampleNewCodeBlock demonstrates the rendering of a source code listing with
+// a simple keyword highlighter.
+func ExampleNewCodeBlock() {
+	const src = `package main
+
+import "fmt"
+
+// main prints a greeting along with a rather long comment that will not fit on a single line of the listing
+func main() {
+	for j := 0; j < 3; j++ {
+		fmt.Println("Hello, world", j)
+	}
+}
+`
+	keywords := map[string]bool{"package": true, "import": true, "func": true, "for": true}
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.AddPage()
+	pdf.Write(6, "The listing below is printed with the default code block settings.")
+	pdf.Ln(10)
+	cb := gofpdf.NewCodeBlock()
+	cb.Render(pdf, pdf.GetX(), 0, src)
+	pdf.Ln(6)
+	pdf.Write(6, "This one adds syntax highlighting by means of a tokenizer callback.")
+	pdf.Ln(10)
+	cb.Tokenize = func(line string) (list []gofpdf.CodeTokenType) {
+		if strings.HasPrefix(strings.TrimSpace(line), "//") {
+			return []gofpdf.CodeTokenType{{Str: line, Clr: gofpdf.RGBType{R: 0, G: 128, B: 0}}}
+		}
+		for j, word := range strings.SplitAfter(line, " ") {
+			clr := cb.ClrText
+			if keywords[strings.TrimSpace(word)] {
+				clr = gofpdf.RGBType{R: 0, G: 0, B: 192}
+			} else if strings.Contains(word, `"`) {
+				clr = gofpdf.RGBType{R: 160, G: 32, B: 32}
+			}
+			if j > 0 || word != "" {
+				list = append(list, gofpdf.CodeTokenType{Str: word, Clr: clr})
+			}
+		}
+		return
+	}
+	cb.FirstLine = 10
+	cb.Render(pdf, 30, 120, src)
+	fileStr := example.Filename("Fpdf_CodeBlock")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_CodeBlock.pdf
+}
+
+// ExampleFpdf_SetPageBox demonstrates the use of a page box
+func ExampleFpdf_SetPageBox() {
+	// pdfinfo (from http://www.xpdfreader.com) reports the following for this example:
+	// ~ pdfinfo -box pdf/Fpdf_PageBox.pdf
+	// Producer:       FPDF 1.7
+	// CreationDate:   Sat Jan  1 00:00:00 2000
+	// ModDate:   	   Sat Jan  1 00:00:00 2000
+	// Tagged:         no
+	// Form:           none
+	// Pages:          1
+	// Encrypted:      no
+	// Page size:      493.23 x 739.85 pts (rotated 0 degrees)
+	// MediaBox:           0.00     0.00   595.28   841.89
+	// CropBox:           51.02    51.02   544.25   790.87
+	// BleedBox:          51.02    51.02   544.25   790.87
+	// TrimBox:           51.02    51.02   544.25   790.87
+	// ArtBox:            51.02    51.02   544.25   790.87
+	// File size:      1053 bytes
+	// Optimized:      no
+	// PDF version:    1.3
+	const (
+		wd        = 210
+		ht        = 297
+		fontsize  = 6
+		boxmargin = 3 * fontsize
+	)
+	pdf := gofpdf.New("P", "mm", "A4", "") // 210mm x 297mm
+	pdf.SetPageBox("crop", boxmargin, boxmargin, wd-2*boxmargin, ht-2*boxmargin)
+	pdf.SetFont("Arial", "", pdf.UnitToPointConvert(fontsize))
+	pdf.AddPage()
+	pdf.MoveTo(fontsize, fontsize)
+	pdf.Write(fontsize, "This will be cropped from printed output")
+	pdf.MoveTo(boxmargin+fontsize, boxmargin+fontsize)
+	pdf.Write(fontsize, "This will be displayed in cropped output")
+	fileStr := example.Filename("Fpdf_PageBox")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_PageBox.pdf
+}
+
+// ExampleFpdf_SubWrite demonstrates subscripted and superscripted text
+// Adapted from http://www.fpdf.org/en/script/script61.php
+func ExampleFpdf_SubWrite() {
+
+	const (
+		fontSize = 12
+		halfX    = 105
+	)
+
+	pdf := gofpdf.New("P", "mm", "A4", "") // 210mm x 297mm
+	pdf.AddPage()
+	pdf.SetFont("Arial", "", fontSize)
+	_, lineHt := pdf.GetFontSize()
+
+	pdf.Write(lineHt, "Hello World!")
+	pdf.SetX(halfX)
+	pdf.Write(lineHt, "This is standard text.\n")
+	pdf.Ln(lineHt * 2)
+
+	pdf.SubWrite(10, "H", 33, 0, 0, "")
+	pdf.Write(10, "ello World!")
+	pdf.SetX(halfX)
+	pdf.Write(10, "This is text with a capital first letter.\n")
+	pdf.Ln(lineHt * 2)
+
+	pdf.SubWrite(lineHt, "Y", 6, 0, 0, "")
+	pdf.Write(lineHt, "ou can also begin the sentence with a small letter. And word wrap also works if the line is too long, like this one is.")
+	pdf.SetX(halfX)
+	pdf.Write(lineHt, "This is text with a small first letter.\n")
+	pdf.Ln(lineHt * 2)
+
+	pdf.Write(lineHt, "The world has a lot of km")
+	pdf.SubWrite(lineHt, "2", 6, 4, 0, "")
+	pdf.SetX(halfX)
+	pdf.Write(lineHt, "This is text with a superscripted letter.\n")
+	pdf.Ln(lineHt * 2)
+
+	pdf.Write(lineHt, "The world has a lot of H")
+	pdf.SubWrite(lineHt, "2", 6, -3, 0, "")
+	pdf.Write(lineHt, "O")
+	pdf.SetX(halfX)
+	pdf.Write(lineHt, "This is text with a subscripted letter.\n")
+
+	fileStr := example.Filename("Fpdf_SubWrite")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SubWrite.pdf
+}
+
+// ExampleFpdf_SetPage demomstrates the SetPage() method, allowing content
+// generation to be deferred until all pages have been added.
+func ExampleFpdf_SetPage() {
+	rnd := rand.New(rand.NewSource(0)) // Make reproducible documents
+	pdf := gofpdf.New("L", "cm", "A4", "")
+	pdf.SetFont("Times", "", 12)
+
+	var time []float64
+	temperaturesFromSensors := make([][]float64, 5)
+	maxs := []float64{25, 41, 89, 62, 11}
+	for i := range temperaturesFromSensors {
+		temperaturesFromSensors[i] = make([]float64, 0)
+	}
+
+	for i := 0.0; i < 100; i += 0.5 {
+		time = append(time, i)
+		for j, sensor := range temperaturesFromSensors {
+			dataValue := rnd.Float64() * maxs[j]
+			sensor = append(sensor, dataValue)
+			temperaturesFromSensors[j] = sensor
+		}
+	}
+	var graphs []gofpdf.GridType
+	var pageNums []int
+	xMax := time[len(time)-1]
+	for i := range temperaturesFromSensors {
+		//Create a new page and graph for each sensor we want to graph.
+		pdf.AddPage()
+		pdf.Ln(1)
+		//Custom label per sensor
+		pdf.WriteAligned(0, 0, "Temperature Sensor "+strconv.Itoa(i+1)+" (C) vs Time (min)", "C")
+		pdf.Ln(0.5)
+		graph := gofpdf.NewGrid(pdf.GetX(), pdf.GetY(), 20, 10)
+		graph.TickmarksContainX(0, xMax)
+		//Custom Y axis
+		graph.TickmarksContainY(0, maxs[i])
+		graph.Grid(pdf)
+		//Save references and locations.
+		graphs = append(graphs, graph)
+		pageNums = append(pageNums, pdf.PageNo())
+	}
+	// For each X, graph the Y in each sensor.
+	for i, currTime := range time {
+		for j, sensor := range temperaturesFromSensors {
+			pdf.SetPage(pageNums[j])
+			graph := graphs[j]
+			temperature := sensor[i]
+			pdf.Circle(graph.X(currTime), graph.Y(temperature), 0.04, "D")
+		}
+	}
+
+	fileStr := example.Filename("Fpdf_SetPage")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetPage.pdf
+}
+
+// ExampleFpdf_SetFillColor demonstrates how graphic attributes are properly
+// assigned within multiple transformations. See issue #234.
+func ExampleFpdf_SetFillColor() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+
+	pdf.AddPage()
+	pdf.SetFont("Arial", "", 8)
+
+	draw := func(trX, trY float64) {
+		pdf.TransformBegin()
+		pdf.TransformTranslateX(trX)
+		pdf.TransformTranslateY(trY)
+		pdf.SetLineJoinStyle("round")
+		pdf.SetLineWidth(0.5)
+		pdf.SetDrawColor(128, 64, 0)
+		pdf.SetFillColor(255, 127, 0)
+		pdf.SetAlpha(0.5, "Normal")
+		pdf.SetDashPattern([]float64{5, 10}, 0)
+		pdf.Rect(0, 0, 40, 40, "FD")
+		pdf.SetFontSize(12)
+		pdf.SetXY(5, 5)
+		pdf.Write(0, "Test")
+		pdf.TransformEnd()
+	}
+
+	draw(5, 5)
+	draw(50, 50)
+
+	fileStr := example.Filename("Fpdf_SetFillColor")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetFillColor.pdf
+}
+
+// ExampleFpdf_TransformRotate demonstrates how to rotate text within a header
+// to make a watermark that appears on each page.
+func ExampleFpdf_TransformRotate() {
+
+	loremStr := lorem() + "\n\n"
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	margin := 25.0
+	pdf.SetMargins(margin, margin, margin)
+
+	fontHt := 13.0
+	lineHt := pdf.PointToUnitConvert(fontHt)
+	markFontHt := 50.0
+	markLineHt := pdf.PointToUnitConvert(markFontHt)
+	markY := (297.0 - markLineHt) / 2.0
+	ctrX := 210.0 / 2.0
+	ctrY := 297.0 / 2.0
+
+	pdf.SetHeaderFunc(func() {
+		pdf.SetFont("Arial", "B", markFontHt)
+		pdf.SetTextColor(206, 216, 232)
+		pdf.SetXY(margin, markY)
+		pdf.TransformBegin()
+		pdf.TransformRotate(45, ctrX, ctrY)
+		pdf.CellFormat(0, markLineHt, "W A T E R M A R K   D E M O", "", 0, "C", false, 0, "")
+		pdf.TransformEnd()
+		pdf.SetXY(margin, margin)
+	})
+
+	pdf.AddPage()
+	pdf.SetFont("Arial", "", 8)
+	for j := 0; j < 25; j++ {
+		pdf.MultiCell(0, lineHt, loremStr, "", "L", false)
+	}
+
+	fileStr := example.Filename("Fpdf_RotateText")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_RotateText.pdf
+}
+
+// ExampleFpdf_AddUTF8Font demonstrates how use the font
+// with utf-8 mode
+func ExampleFpdf_AddUTF8Font() {
+	var fileStr string
+	var txtStr []byte
+	var err error
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+
+	pdf.AddPage()
+
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.AddUTF8Font("dejavu", "B", example.FontFile("DejaVuSansCondensed-Bold.ttf"))
+	pdf.AddUTF8Font("dejavu", "I", example.FontFile("DejaVuSansCondensed-Oblique.ttf"))
+	pdf.AddUTF8Font("dejavu", "BI", example.FontFile("DejaVuSansCondensed-BoldOblique.ttf"))
+
+	fileStr = example.Filename("Fpdf_AddUTF8Font")
+	txtStr, err = ioutil.ReadFile(example.TextFile("utf-8test.txt"))
+	if err == nil {
+
+		pdf.SetFont("dejavu", "B", 17)
+		pdf.MultiCell(100, 8, "Text in different languages :", "", "C", false)
+		pdf.SetFont("dejavu", "", 14)
+		pdf.MultiCell(100, 5, string(txtStr), "", "C", false)
+		pdf.Ln(15)
+
+		txtStr, err = ioutil.ReadFile(example.TextFile("utf-8test2.txt"))
+		if err == nil {
+
+			pdf.SetFont("dejavu", "BI", 17)
+			pdf.MultiCell(100, 8, "Greek text with alignStr = \"J\":", "", "C", false)
+			pdf.SetFont("dejavu", "I", 14)
+			pdf.MultiCell(100, 5, string(txtStr), "", "J", false)
+			err = pdf.OutputFileAndClose(fileStr)
+
+		}
+	}
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_AddUTF8Font.pdf
+}
+
+// ExampleFpdf_AddUTF8Font_otf demonstrates the use of an OpenType font with
+// PostScript (CFF) outlines. The small test font used here has glyphs only for
+// the digits 0 and 1, the letter Q and the character U+4E2D. The font is added
+// a second time under another family name and embedded completely.
+func ExampleFpdf_AddUTF8Font_otf() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8Font("cfftest", "", example.FontFile("CFFTest.otf"))
+	pdf.AddUTF8Font("cfffull", "", example.FontFile("CFFTest.otf"))
+	pdf.SetFontEmbedding("cfffull", "", gofpdf.FontEmbeddingType{Mode: gofpdf.FontEmbedFull})
+	pdf.AddPage()
+	for _, familyStr := range []string{"cfftest", "cfffull"} {
+		pdf.SetFont("Helvetica", "", 12)
+		pdf.CellFormat(0, 8, "Font "+familyStr+":", "", 1, "L", false, 0, "")
+		pdf.SetFont(familyStr, "", 48)
+		pdf.CellFormat(0, 24, "1001 Q \u4e2d", "", 1, "L", false, 0, "")
+		pdf.Ln(6)
+	}
+	fileStr := example.Filename("Fpdf_AddUTF8Font_otf")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_AddUTF8Font_otf.pdf
+}
+
+// ExampleUTF8CutFont demonstrates how generate a TrueType font subset.
+func ExampleUTF8CutFont() {
+	var pdfFileStr, fullFontFileStr, subFontFileStr string
+	var subFont, fullFont []byte
+	var err error
+
+	pdfFileStr = example.Filename("Fpdf_UTF8CutFont")
+	fullFontFileStr = example.FontFile("calligra.ttf")
+	fullFont, err = ioutil.ReadFile(fullFontFileStr)
+	if err == nil {
+		subFontFileStr = "calligra_abcde.ttf"
+		subFont = gofpdf.UTF8CutFont(fullFont, "abcde")
+		err = ioutil.WriteFile(subFontFileStr, subFont, 0600)
+		if err == nil {
+			y := 24.0
+			pdf := gofpdf.New("P", "mm", "A4", "")
+			fontHt := 17.0
+			lineHt := pdf.PointConvert(fontHt)
+			write := func(format string, args ...interface{}) {
+				pdf.SetXY(24.0, y)
+				pdf.Cell(200.0, lineHt, fmt.Sprintf(format, args...))
+				y += lineHt
+			}
+			writeSize := func(fileStr string) {
+				var info os.FileInfo
+				var err error
+				info, err = os.Stat(fileStr)
+				if err == nil {
+					write("%6d: size of %s", info.Size(), fileStr)
+				}
+			}
+			pdf.AddPage()
+			pdf.AddUTF8Font("calligra", "", subFontFileStr)
+			pdf.SetFont("calligra", "", fontHt)
+			write("cabbed")
+			write("vwxyz")
+			pdf.SetFont("courier", "", fontHt)
+			writeSize(fullFontFileStr)
+			writeSize(subFontFileStr)
+			err = pdf.OutputFileAndClose(pdfFileStr)
+			os.Remove(subFontFileStr)
+		}
+	}
+	example.Summary(err, pdfFileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_UTF8CutFont.pdf
+}
+
+func ExampleFpdf_RoundedRect() {
+	const (
+		wd     = 40.0
+		hgap   = 10.0
+		radius = 10.0
+		ht     = 60.0
+		vgap   = 10.0
+	)
+	corner := func(b1, b2, b3, b4 bool) (cstr string) {
+		if b1 {
+			cstr = "1"
+		}
+		if b2 {
+			cstr += "2"
+		}
+		if b3 {
+			cstr += "3"
+		}
+		if b4 {
+			cstr += "4"
+		}
+		return
+	}
+	pdf := gofpdf.New("P", "mm", "A4", "") // 210 x 297
+	pdf.AddPage()
+	pdf.SetLineWidth(0.5)
+	y := vgap
+	r := 40
+	g := 30
+	b := 20
+	for row := 0; row < 4; row++ {
+		x := hgap
+		for col := 0; col < 4; col++ {
+			pdf.SetFillColor(r, g, b)
+			pdf.RoundedRect(x, y, wd, ht, radius, corner(row&1 == 1, row&2 == 2, col&1 == 1, col&2 == 2), "FD")
+			r += 8
+			g += 10
+			b += 12
+			x += wd + hgap
+		}
+		y += ht + vgap
+	}
+	pdf.AddPage()
+	pdf.RoundedRectExt(10, 20, 40, 80, 4., 0., 20, 0., "FD")
+
+	fileStr := example.Filename("Fpdf_RoundedRect")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_RoundedRect.pdf
+}
+
+// ExampleFpdf_SetUnderlineThickness demonstrates how to adjust the text
+// underline thickness.
+func ExampleFpdf_SetUnderlineThickness() {
+	pdf := gofpdf.New("P", "mm", "A4", "") // 210mm x 297mm
+	pdf.AddPage()
+	pdf.SetFont("Arial", "U", 12)
+
+	pdf.SetUnderlineThickness(0.5)
+	pdf.CellFormat(0, 10, "Thin underline", "", 1, "", false, 0, "")
+
+	pdf.SetUnderlineThickness(1)
+	pdf.CellFormat(0, 10, "Normal underline", "", 1, "", false, 0, "")
+
+	pdf.SetUnderlineThickness(2)
+	pdf.CellFormat(0, 10, "Thicker underline", "", 1, "", false, 0, "")
+
+	fileStr := example.Filename("Fpdf_UnderlineThickness")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_UnderlineThickness.pdf
+}
+
+// ExampleFpdf_Cell_strikeout demonstrates striked-out text
+func ExampleFpdf_Cell_strikeout() {
+
+	pdf := gofpdf.New("P", "mm", "A4", "") // 210mm x 297mm
+	pdf.AddPage()
+
+	for fontSize := 4; fontSize < 40; fontSize += 10 {
+		pdf.SetFont("Arial", "S", float64(fontSize))
+		pdf.SetXY(0, float64(fontSize))
+		pdf.Cell(40, 10, "Hello World")
+	}
+
+	fileStr := example.Filename("Fpdf_Cell_strikeout")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_Cell_strikeout.pdf
+}
+
+// ExampleFpdf_SetTextRenderingMode demonstrates rendering modes in PDFs.
+func ExampleFpdf_SetTextRenderingMode() {
+
+	pdf := gofpdf.New("P", "mm", "A4", "") // 210mm x 297mm
+	pdf.AddPage()
+	fontSz := float64(16)
+	lineSz := pdf.PointToUnitConvert(fontSz)
+	pdf.SetFont("Times", "", fontSz)
+	pdf.Write(lineSz, "This document demonstrates various modes of text rendering. Search for \"Mode 3\" "+
+		"to locate text that has been rendered invisibly. This selection can be copied "+
+		"into the clipboard as usual and is useful for overlaying onto non-textual elements such "+
+		"as images to make them searchable.\n\n")
+	fontSz = float64(125)
+	lineSz = pdf.PointToUnitConvert(fontSz)
+	pdf.SetFontSize(fontSz)
+	pdf.SetTextColor(170, 170, 190)
+	pdf.SetDrawColor(50, 60, 90)
+
+	write := func(mode int) {
+		pdf.SetTextRenderingMode(mode)
+		pdf.CellFormat(210, lineSz, fmt.Sprintf("Mode %d", mode), "", 1, "", false, 0, "")
+	}
+
+	for mode := 0; mode < 4; mode++ {
+		write(mode)
+	}
+	write(0)
+
+	fileStr := example.Filename("Fpdf_TextRenderingMode")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_TextRenderingMode.pdf
+}
+
+// TestRegisterImagesOptions verifies that images registered concurrently are
+// the same as images registered one by one, and that the first error in the
+// list is reported
+func TestRegisterImagesOptions(t *testing.T) {
+	var fileList []string
+	for _, str := range []string{"logo.png", "logo.jpg", "logo.gif", "logo.png", "logo-rgb.png", "golang-gopher.png"} {
+		fileList = append(fileList, example.ImageFile(str))
+	}
+	seq := gofpdf.New("P", "mm", "A4", "")
+	for _, fileStr := range fileList {
+		seq.RegisterImageOptions(fileStr, gofpdf.ImageOptions{})
+	}
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	infoList := pdf.RegisterImagesOptions(fileList, gofpdf.ImageOptions{}, 3)
+	if pdf.Err() || seq.Err() {
+		t.Fatalf("unexpected errors %v, %v", pdf.Error(), seq.Error())
+	}
+	for j, fileStr := range fileList {
+		want, _ := seq.GetImageInfo(fileStr).GobEncode()
+		got, _ := infoList[j].GobEncode()
+		if !bytes.Equal(got, want) {
+			t.Errorf("image %s differs from sequential registration", fileStr)
+		}
+	}
+	// The alpha channel of logo-rgb.png raises the PDF version of both
+	header := func(pdf *gofpdf.Fpdf) string {
+		var buf bytes.Buffer
+		pdf.AddPage()
+		pdf.Output(&buf)
+		return buf.String()[:8]
+	}
+	if got, want := header(pdf), header(seq); got != want {
+		t.Errorf("header %s, expected %s", got, want)
+	}
+
+	pdf = gofpdf.New("P", "mm", "A4", "")
+	badList := []string{fileList[0], example.ImageFile("missing.png"), example.ImageFile("doc.svg"), fileList[1]}
+	infoList = pdf.RegisterImagesOptions(badList, gofpdf.ImageOptions{}, 0)
+	if !pdf.Err() || !strings.Contains(pdf.Error().Error(), "missing.png") {
+		t.Errorf("expected error for missing file, got %v", pdf.Error())
+	}
+	if infoList[0] == nil || infoList[3] != nil {
+		t.Errorf("expected only the images before the error to be registered")
+	}
+}
+
+func TestLineHeight(t *testing.T) {
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.AddPage()
+	if ht := pdf.GetLineHeight(); math.Abs(ht-12.5) > 1e-9 {
+		t.Errorf("default line height %.2f, expected 12.5", ht)
+	}
+	pdf.SetLineHeightFactor(2)
+	y := pdf.GetY()
+	pdf.MultiCell(100, -1, "one\ntwo\nthree", "", "L", false)
+	if dy := pdf.GetY() - y; math.Abs(dy-60) > 1e-9 {
+		t.Errorf("three lines advanced by %.2f, expected 60", dy)
+	}
+	pdf.SetLineHeight(14)
+	pdf.SetFontSize(30)
+	y = pdf.GetY()
+	pdf.Write(-1, "one\ntwo\n")
+	if dy := pdf.GetY() - y; math.Abs(dy-28) > 1e-9 {
+		t.Errorf("two lines advanced by %.2f, expected 28", dy)
+	}
+	pdf.SetLineHeightFactor(0)
+	if !pdf.Err() {
+		t.Errorf("expected error for a line height factor of zero")
+	}
+}
+
+func TestMacro(t *testing.T) {
 	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "", 10)
 	pdf.AddPage()
-	pdf.SetFont("Arial", "", 15)
-	pdf.Write(8, "This line doesn't belong to any layer.\n")
+	pdf.SetDrawColor(10, 20, 30)
+	pdf.SetXY(50, 60)
+	pdf.RecordMacro("box", func() {
+		pdf.SetDrawColor(200, 0, 0)
+		pdf.SetLineWidth(0.1)
+		pdf.SetFont("Times", "B", 20)
+		pdf.Rect(0, 0, 1, 1, "D")
+		pdf.SetXY(0, 0)
+	})
+	if r, g, b := pdf.GetDrawColor(); r != 10 || g != 20 || b != 30 {
+		t.Errorf("draw color (%d, %d, %d) after recording, expected (10, 20, 30)", r, g, b)
+	}
+	if x, y := pdf.GetXY(); x != 50 || y != 60 {
+		t.Errorf("position (%.2f, %.2f) after recording, expected (50, 60)", x, y)
+	}
+	if pt, _ := pdf.GetFontSize(); pt != 10 {
+		t.Errorf("font size %.2f after recording, expected 10", pt)
+	}
+	pdf.PlayMacro("box", 10, 20, 5)
+	pdf.PlayMacro("circle", 10, 20, 5)
+	if !pdf.Err() {
+		t.Errorf("expected error for a macro that has not been recorded")
+	}
+	// Form fields and annotations made while recording are left out
+	pdf = gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	pdf.RecordMacro("field", func() {
+		pdf.AddTextField("name", 0, 0, 10, 5, "", gofpdf.FormFieldType{})
+		pdf.AddAttachmentAnnotation(&gofpdf.Attachment{Content: []byte("note"), Filename: "note.txt"}, 0, 0, 5, 5)
+		pdf.Rect(0, 0, 1, 1, "D")
+	})
+	pdf.PlayMacro("field", 10, 20, 1)
+	pdf.PlayMacro("field", 30, 20, 1)
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, str := range []string{"/Subtype /Widget", "/Subtype /FileAttachment"} {
+		if strings.Contains(buf.String(), str) {
+			t.Errorf("%s recorded in a macro is written to the document", str)
+		}
+	}
+}
 
-	// Define layers
-	l1 := pdf.AddLayer("Layer 1", true)
-	l2 := pdf.AddLayer("Layer 2", true)
+func TestImageRecompress(t *testing.T) {
+	output := func(opt gofpdf.ImageOptions, wd float64) int {
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		pdf.AddPage()
+		pdf.ImageOptions(example.ImageFile("golang-gopher.png"), 10, 10, wd, 0, false, opt, 0, "")
+		var buf bytes.Buffer
+		if err := pdf.Output(&buf); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Len()
+	}
+	full := output(gofpdf.ImageOptions{}, 50)
+	if size := output(gofpdf.ImageOptions{JPEGQuality: 50}, 50); size >= full {
+		t.Errorf("recompressed size %d, expected less than %d", size, full)
+	}
+	if size := output(gofpdf.ImageOptions{MaxDPI: 1000}, 50); size != full {
+		t.Errorf("size %d below the resolution limit, expected %d", size, full)
+	}
+	small := output(gofpdf.ImageOptions{MaxDPI: 100}, 50)
+	if small*4 >= full {
+		t.Errorf("downsampled size %d, expected less than a quarter of %d", small, full)
+	}
+	if size := output(gofpdf.ImageOptions{MaxDPI: 100}, 100); size <= small {
+		t.Errorf("downsampled size %d at double width, expected more than %d", size, small)
+	}
+
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	info := pdf.RegisterImageOptions(example.ImageFile("golang-gopher.png"), gofpdf.ImageOptions{JPEGQuality: 50})
+	if pdf.Err() {
+		t.Fatal(pdf.Error())
+	}
+	if wd, ht := info.Extent(); wd != 1000 || ht != 1000 {
+		t.Errorf("recompressed image of %.0f by %.0f pixels, expected 1000 by 1000", wd, ht)
+	}
+}
+
+// TestElementManifest verifies that elements split across pages are recorded
+// for each page and that their pages follow the pages moved by a visual index
+func TestElementManifest(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetY(200)
+	opt := gofpdf.ImageOptions{FlowBreak: gofpdf.ImageFlowSplit, Element: "gopher"}
+	pdf.ImageOptions(example.ImageFile("golang-gopher.png"), 10, 0, 80, 0, true, opt, 0, "")
+	pdf.RecordElement("box", "note", 10, 100, 20, 10)
+	pdf.RecordElement("box", "note", 40, 90, 20, 10)
+	pdf.GenerateVisualIndex("", 4)
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if err := pdf.OutputElementManifest(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var list []gofpdf.ElementType
+	if err := json.Unmarshal(buf.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 3 {
+		t.Fatalf("%d elements, expected 3", len(list))
+	}
+	first, second, box := list[0], list[1], list[2]
+	if first.Page != 2 || second.Page != 3 || box.Page != 3 {
+		t.Errorf("elements on pages %d, %d and %d, expected 2, 3 and 3", first.Page, second.Page, box.Page)
+	}
+	if math.Abs(first.H+second.H-80) > 0.01 {
+		t.Errorf("parts of height %.2f and %.2f, expected 80 together", first.H, second.H)
+	}
+	if box.X != 10 || box.Y != 90 || box.W != 50 || box.H != 20 {
+		t.Errorf("merged rectangle %.2f, %.2f, %.2f, %.2f, expected 10, 90, 50, 20", box.X, box.Y, box.W, box.H)
+	}
+	if top := 841.89 - 90/25.4*72; math.Abs(box.Rect[3]-top) > 0.01 {
+		t.Errorf("rectangle top at %.2f pt, expected %.2f", box.Rect[3], top)
+	}
+}
+
+// TestParseFDF verifies the reading of FDF data written by other tools, with
+// nested fields, escaped and UTF-16 strings, names and object references
+func TestParseFDF(t *testing.T) {
+	fdfStr := "%FDF-1.2\n1 0 obj\n<</FDF <</Fields [<</T (address) /Kids [<</T (city) " +
+		"/V (Saint-\\351tienne \\(FR\\))>> 3 0 R]>> <</T <FEFF0067006C00FC0063006B> /V /Opt#20A>>] " +
+		"/ID [<0A1B> <0A1B>]>>>>\nendobj\ntrailer\n<</Root 1 0 R>>\n%%EOF\n"
+	values, err := gofpdf.ParseFDF(strings.NewReader(fdfStr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"address.city": "Saint-étienne (FR)", "glück": "Opt A"}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("values %q, expected %q", values, want)
+	}
+	if _, err = gofpdf.ParseFDF(strings.NewReader("%PDF-1.4\n")); err == nil {
+		t.Errorf("no error for data that is not FDF")
+	}
+	// Truncated files are reported rather than read past their end
+	for _, str := range []string{"(", "<", "(Saint", "<5361", "<</T (city) /V (Lyon"} {
+		if _, err = gofpdf.ParseFDF(strings.NewReader("%FDF-1.2\n1 0 obj <</FDF <</Fields [" + str)); err == nil {
+			t.Errorf("no error for FDF data truncated after %q", str)
+		}
+	}
+}
+
+// TestFormFormat checks the actions written for formatted text fields and
+// the errors of invalid formats
+func TestFormFormat(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	opt := gofpdf.NewFormField()
+	opt.Format = gofpdf.FormFormatType{Kind: gofpdf.FormFormatNumber, Decimals: 2,
+		Currency: "\u20ac", CurrencyAfter: true, MinSet: true, Min: 0}
+	pdf.AddTextField("price", 10, 10, 40, 8, "", opt)
+	opt.Format = gofpdf.FormFormatType{Kind: gofpdf.FormFormatDate, Mask: "dd.mm.yyyy"}
+	pdf.AddTextField("date", 10, 20, 40, 8, "", opt)
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, str := range []string{
+		`/AA <</K <</S /JavaScript /JS (AFNumber_Keystroke\(2, 0, 0, 0, "\\u20ac", false\);)>> ` +
+			`/F <</S /JavaScript /JS (AFNumber_Format\(2, 0, 0, 0, "\\u20ac", false\);)>> ` +
+			`/V <</S /JavaScript /JS (AFRange_Validate\(true, 0, false, 0\);)>>>>`,
+		`/F <</S /JavaScript /JS (AFDate_FormatEx\("dd.mm.yyyy"\);)>>>>`,
+	} {
+		if !strings.Contains(buf.String(), str) {
+			t.Errorf("document does not contain %s", str)
+		}
+	}
+	for _, ft := range []gofpdf.FormFormatType{
+		{Kind: 9},
+		{Kind: gofpdf.FormFormatDate},
+		{Kind: gofpdf.FormFormatSpecial, Special: 7},
+		{Kind: gofpdf.FormFormatPercent, SepStyle: 4},
+		{Kind: gofpdf.FormFormatDate, Mask: "yyyy", MaxSet: true},
+		{Kind: gofpdf.FormFormatNumber, MinSet: true, MaxSet: true, Min: 10, Max: 1},
+	} {
+		pdf = gofpdf.New("P", "mm", "A4", "")
+		pdf.AddPage()
+		opt.Format = ft
+		pdf.AddTextField("field", 10, 10, 40, 8, "", opt)
+		if pdf.Err() == false {
+			t.Errorf("no error for format %+v", ft)
+		}
+	}
+}
 
-	// Open layer pane in PDF viewer
-	pdf.OpenLayerPane()
+// TestSignPDF checks that signature fields are signed one after another,
+// each signature covering the revision that it signs
+func TestSignPDF(t *testing.T) {
+	sig := testSignature(t)
+	rangeRe := regexp.MustCompile(`/ByteRange \[0 (\d+) (\d+) (\d+)\] /Contents <([0-9a-f]+)>`)
+	for _, compact := range []bool{false, true} {
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		pdf.SetCompactOutput(compact)
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.AddPage()
+		sb := gofpdf.NewSignatureBlock()
+		pdf.AddSignatureField("first", 20, 20, 100, 40, &sb)
+		pdf.AddSignatureField("second", 0, 0, 0, 0, nil)
+		var buf bytes.Buffer
+		if err := pdf.Output(&buf); err != nil {
+			t.Fatal(err)
+		}
+		doc := buf.Bytes()
+		for _, nameStr := range []string{"first", "second"} {
+			signed, err := gofpdf.SignPDF(doc, nameStr, sig)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.HasPrefix(signed, doc) {
+				t.Fatalf("signing %s changed the document", nameStr)
+			}
+			doc = signed
+		}
+		list := rangeRe.FindAllSubmatch(doc, -1)
+		if len(list) != 2 {
+			t.Fatalf("%d signatures found, expected 2", len(list))
+		}
+		for j, m := range list {
+			var r [3]int
+			for k := range r {
+				r[k], _ = strconv.Atoi(string(m[k+1]))
+			}
+			if j == 1 && r[1]+r[2] != len(doc) {
+				t.Errorf("last signature does not cover the document")
+			}
+			digest := sha256.Sum256(append(append([]byte(nil), doc[:r[0]]...), doc[r[1]:r[1]+r[2]]...))
+			if !bytes.Contains(m[4], []byte(fmt.Sprintf("%x", digest))) {
+				t.Errorf("signature %d does not hold the digest of its byte range", j+1)
+			}
+		}
+		if _, err := gofpdf.SignPDF(doc, "first", sig); err == nil {
+			t.Errorf("no error for a field that has been signed")
+		}
+		if _, err := gofpdf.SignPDF(doc, "third", sig); err == nil {
+			t.Errorf("no error for a missing field")
+		}
+	}
+}
 
-	// First layer
-	pdf.BeginLayer(l1)
-	pdf.Write(8, "This line belongs to layer 1.\n")
-	pdf.EndLayer()
+// testTimestamp is a time-stamping authority that grants a time stamp to
+// every request. The token is not signed, which suffices to test its
+// placement in a document.
+func testTimestamp(req []byte) ([]byte, error) {
+	var query struct {
+		Version        int
+		MessageImprint struct {
+			HashAlgorithm pkix.AlgorithmIdentifier
+			HashedMessage []byte
+		}
+		Nonce   *big.Int
+		CertReq bool
+	}
+	if _, err := asn1.Unmarshal(req, &query); err != nil {
+		return nil, err
+	}
+	info, err := asn1.Marshal(struct {
+		Version        int
+		Policy         asn1.ObjectIdentifier
+		MessageImprint struct {
+			HashAlgorithm pkix.AlgorithmIdentifier
+			HashedMessage []byte
+		}
+		SerialNumber int
+		GenTime      time.Time `asn1:"generalized"`
+		Nonce        *big.Int
+	}{1, asn1.ObjectIdentifier{1, 2, 3, 4}, query.MessageImprint, 1,
+		time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), query.Nonce})
+	if err != nil {
+		return nil, err
+	}
+	content, _ := asn1.Marshal(info)
+	signedData, err := asn1.Marshal(struct {
+		Version          int
+		DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+		EncapContent     struct {
+			ContentType asn1.ObjectIdentifier
+			Content     asn1.RawValue
+		}
+		SignerInfos []asn1.RawValue `asn1:"set"`
+	}{Version: 3, DigestAlgorithms: []pkix.AlgorithmIdentifier{query.MessageImprint.HashAlgorithm},
+		EncapContent: struct {
+			ContentType asn1.ObjectIdentifier
+			Content     asn1.RawValue
+		}{asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 1, 4},
+			asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: content}}})
+	if err != nil {
+		return nil, err
+	}
+	token, err := asn1.Marshal(struct {
+		ContentType asn1.ObjectIdentifier
+		Content     asn1.RawValue
+	}{asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2},
+		asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: signedData}})
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(struct {
+		Status struct{ Status int }
+		Token  asn1.RawValue
+	}{Token: asn1.RawValue{FullBytes: token}})
+}
 
-	// Second layer
-	pdf.BeginLayer(l2)
-	pdf.Write(8, "This line belongs to layer 2.\n")
-	pdf.EndLayer()
+// TestTimestampPDF checks that a signed document is timestamped with a
+// token of the digest of the whole document
+func TestTimestampPDF(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.AddSignatureField("signature", 0, 0, 0, 0, nil)
+	pdf.AddSignatureField("timestamp", 0, 0, 0, 0, nil)
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	signed, err := gofpdf.SignPDF(buf.Bytes(), "signature", testSignature(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := gofpdf.TimestampPDF(signed, "timestamp", testTimestamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(doc, signed) {
+		t.Fatalf("timestamping changed the document")
+	}
+	m := regexp.MustCompile(`/Type /DocTimeStamp /Filter /Adobe.PPKLite /SubFilter /ETSI.RFC3161 ` +
+		`/ByteRange \[0 (\d+) (\d+) (\d+)\] /Contents <([0-9a-f]+)>`).FindSubmatch(doc)
+	if m == nil {
+		t.Fatalf("document timestamp not found")
+	}
+	var r [3]int
+	for k := range r {
+		r[k], _ = strconv.Atoi(string(m[k+1]))
+	}
+	if r[1]+r[2] != len(doc) {
+		t.Errorf("timestamp does not cover the document")
+	}
+	digest := sha256.Sum256(append(append([]byte(nil), doc[:r[0]]...), doc[r[1]:r[1]+r[2]]...))
+	if !bytes.Contains(m[4], []byte(fmt.Sprintf("%x", digest))) {
+		t.Errorf("timestamp does not hold the digest of its byte range")
+	}
+	if _, err = gofpdf.TimestampPDF(doc, "timestamp", testTimestamp); err == nil {
+		t.Errorf("no error for a field that has been timestamped")
+	}
+	refuse := func(req []byte) ([]byte, error) {
+		// Status 2 rejects the request
+		return asn1.Marshal(struct{ Status struct{ Status int } }{struct{ Status int }{2}})
+	}
+	if _, err = gofpdf.TimestampPDF(signed, "timestamp", refuse); err == nil {
+		t.Errorf("no error for a rejected request")
+	}
+	// A response to another request carries another nonce
+	replay := func(req []byte) ([]byte, error) {
+		var query struct {
+			Version        int
+			MessageImprint asn1.RawValue
+			Nonce          *big.Int
+			CertReq        bool
+		}
+		if _, err := asn1.Unmarshal(req, &query); err != nil {
+			return nil, err
+		}
+		query.Nonce.Add(query.Nonce, big.NewInt(1))
+		req, _ = asn1.Marshal(query)
+		return testTimestamp(req)
+	}
+	if _, err = gofpdf.TimestampPDF(signed, "timestamp", replay); err == nil {
+		t.Errorf("no error for a response with another nonce")
+	}
+}
 
-	// First layer again
-	pdf.BeginLayer(l1)
-	pdf.Write(8, "This line belongs to layer 1 again.\n")
-	pdf.EndLayer()
+// TestStreamingProtection checks that a protected streaming document is
+// written as its pages are finished and that each string is encrypted on
+// its own, so that equal strings of an object are encrypted equally
+func TestStreamingProtection(t *testing.T) {
+	var buf bytes.Buffer
+	pdf := gofpdf.NewStreaming(&buf, "P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.SetProtection(gofpdf.CnProtectPrint, "", "owner")
+	pdf.SetTitle("Report", false)
+	pdf.SetSubject("Report", false)
+	pdf.SetFont("Helvetica", "", 12)
+	for j := 0; j < 3; j++ {
+		pdf.AddPage()
+		pdf.Cell(0, 10, "Confidential")
+	}
+	if buf.Len() == 0 {
+		t.Errorf("no pages written before the document is closed")
+	}
+	pdf.Close()
+	if pdf.Err() {
+		t.Fatal(pdf.Error())
+	}
+	doc := buf.Bytes()
+	if bytes.Contains(doc, []byte("Confidential")) {
+		t.Errorf("page content is not encrypted")
+	}
+	m := regexp.MustCompile(`(?s)/Title (\(.*?\))\n/Subject (\(.*?\))\n/CreationDate`).FindSubmatch(doc)
+	if m == nil {
+		t.Fatalf("title and subject not found")
+	}
+	if !bytes.Equal(m[1], m[2]) || bytes.Contains(m[1], []byte("Report")) {
+		t.Errorf("title %q and subject %q are not encrypted equally", m[1], m[2])
+	}
+}
 
-	fileStr := example.Filename("Fpdf_AddLayer")
-	err := pdf.OutputFileAndClose(fileStr)
-	example.Summary(err, fileStr)
-	// Output:
-	// Successfully generated pdf/Fpdf_AddLayer.pdf
+// TestFlowTextWidows checks that FlowText() breaks paragraphs only where
+// the orphan and widow limits are met
+func TestFlowTextWidows(t *testing.T) {
+	// Five lines of one word each, 10 mm apart, on pages with text from 10 to
+	// 277 mm
+	runs := []gofpdf.TextRunType{{Str: "MMMMMMMM MMMMMMMM MMMMMMMM MMMMMMMM MMMMMMMM"}}
+	para := gofpdf.NewParagraph()
+	para.LineHt = 10
+	for _, tc := range []struct {
+		y             float64
+		orphans, page int
+		endY          float64
+	}{
+		{237, 2, 2, 30}, // four lines fit; two are carried over as widows
+		{257, 2, 2, 40}, // two lines fit; three are carried over
+		{265, 2, 2, 60}, // one line fits, which would be an orphan
+		{265, 1, 2, 50}, // one line fits and is left
+		{100, 2, 1, 150},
+	} {
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		pdf.SetMargins(10, 10, 10)
+		pdf.SetAutoPageBreak(true, 20)
+		pdf.SetFont("Helvetica", "", 12)
+		pdf.AddPage()
+		pdf.SetY(tc.y)
+		para.Orphans = tc.orphans
+		pdf.FlowText(30, runs, para)
+		if pdf.Err() {
+			t.Fatal(pdf.Error())
+		}
+		if page, y := pdf.PageNo(), pdf.GetY(); page != tc.page || math.Abs(y-tc.endY) > 1e-6 {
+			t.Errorf("paragraph at %g ends on page %d at %g, expected page %d at %g",
+				tc.y, page, y, tc.page, tc.endY)
+		}
+	}
 }
 
-// ExampleFpdf_RegisterImageReader demonstrates the use of an image that is retrieved from a web
-// server.
-func ExampleFpdf_RegisterImageReader() {
+// TestCellSpans checks that the lines of CellSpans() are placed and framed
+// like those of MultiCell()
+func TestCellSpans(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.AddPage()
+	pdf.SetXY(30, 50)
+	runs := []gofpdf.TextRunType{{Str: "one "}, {Str: "two\nthree", FontSize: 16, Clr: gofpdf.RGBType{R: 255}}}
+	pdf.CellSpans(40, 6, runs, "1", "L", false)
+	if left, _, _, _ := pdf.GetMargins(); pdf.GetX() != left || math.Abs(pdf.GetY()-62) > 1e-6 {
+		t.Errorf("cell ends at (%g, %g), expected (%g, 62)", pdf.GetX(), pdf.GetY(), left)
+	}
+	if size, _ := pdf.GetFontSize(); size != 12 {
+		t.Errorf("font size %g not restored", size)
+	}
+	if r, g, b := pdf.GetTextColor(); r+g+b != 0 {
+		t.Errorf("text color (%d, %d, %d) not restored", r, g, b)
+	}
+	pdf.SetY(280)
+	pdf.CellSpans(40, 6, runs, "", "", false)
+	if page, y := pdf.PageNo(), pdf.GetY(); page != 2 || math.Abs(y-22.00125) > 1e-6 {
+		t.Errorf("cell ends on page %d at %g, expected page 2 at 22.00125", page, y)
+	}
+	// Text without words makes one empty line
+	for _, empty := range [][]gofpdf.TextRunType{nil, {{Str: "   "}}} {
+		y := pdf.GetY()
+		pdf.CellSpans(40, 6, empty, "1", "", false)
+		if math.Abs(pdf.GetY()-y-6) > 1e-6 {
+			t.Errorf("empty cell ends at %g, expected %g", pdf.GetY(), y+6)
+		}
+	}
+	if pdf.Err() {
+		t.Fatal(pdf.Error())
+	}
+}
 
-	const (
-		margin   = 10
-		wd       = 210
-		ht       = 297
-		fontSize = 15
-		urlStr   = "https://github.com/headlands-org/gofpdf/blob/master/image/gofpdf.png?raw=true"
-		msgStr   = `Images from the web can be easily embedded when a PDF document is generated.`
-	)
+// TestSetColumns checks that content moves to the next column where it
+// would break the page and that balanced columns end at equal heights
+func TestSetColumns(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(10, 10, 10)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.AddPage()
+	pdf.SetY(50)
+	pdf.SetColumns(2, 10)
+	pdf.SetY(270)
+	pdf.MultiCell(0, 5, "one\ntwo\nthree", "", "", false)
+	if x, y := pdf.GetXY(); math.Abs(x-110) > 0.01 || y != 60 {
+		t.Errorf("text ends at (%g, %g), expected (110, 60)", x, y)
+	}
+	pdf.ColumnBreak()
+	if page, x, y := pdf.PageNo(), pdf.GetX(), pdf.GetY(); page != 2 || x != 10 || y != 10 {
+		t.Errorf("column break leads to (%g, %g) on page %d, expected (10, 10) on page 2", x, y, page)
+	}
+	pdf.BalanceColumns(func() {
+		for j := 0; j < 9; j++ {
+			pdf.MultiCell(0, 5, "line", "", "", false)
+		}
+	})
+	if y := pdf.GetY(); y != 35 {
+		t.Errorf("balanced columns end at %g, expected 35", y)
+	}
+	pdf.SetColumns(1, 0)
+	if left, _, right, _ := pdf.GetMargins(); left != 10 || right != 10 || pdf.GetY() != 35 {
+		t.Errorf("margins %g and %g at %g after columns, expected 10 and 10 at 35", left, right, pdf.GetY())
+	}
+	if pdf.Err() {
+		t.Fatal(pdf.Error())
+	}
+}
 
-	var (
-		rsp *http.Response
-		err error
-		tp  string
-	)
+// TestBalanceColumnsMarks checks that the bookmarks, index marks and
+// counters recorded by balanced content are recorded once, although the
+// content is printed more than once to balance it
+func TestBalanceColumnsMarks(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.NewCounter("figure")
+	pdf.AddPage()
+	pdf.SetColumns(2, 10)
+	var value int
+	pdf.BalanceColumns(func() {
+		pdf.Bookmark("Balanced", 0, -1)
+		pdf.SetNamedDest("balanced")
+		value = pdf.StepCounter("figure", "fig:balanced")
+		for j := 0; j < 9; j++ {
+			pdf.MultiCell(0, 5, "line", "", "", false)
+		}
+	})
+	if pdf.Err() {
+		t.Fatal(pdf.Error())
+	}
+	if value != 1 || pdf.RefCounter("fig:balanced") != "1" {
+		t.Errorf("counter stepped to %d and referred to as %s, expected 1", value, pdf.RefCounter("fig:balanced"))
+	}
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if count := strings.Count(buf.String(), "/Title (Balanced)"); count != 1 {
+		t.Errorf("bookmark recorded %d times, expected once", count)
+	}
+}
 
+// TestFlowTextColumns checks that FlowText() continues a paragraph in the
+// next column and on the next page in column mode, without printing below
+// the page break trigger.
+func TestFlowTextColumns(t *testing.T) {
 	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.SetMargins(10, 10, 10)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.SetFont("Helvetica", "", 12)
 	pdf.AddPage()
-	pdf.SetFont("Helvetica", "", fontSize)
-	ln := pdf.PointConvert(fontSize)
-	pdf.MultiCell(wd-margin-margin, ln, msgStr, "", "L", false)
-	rsp, err = http.Get(urlStr)
-	if err == nil {
-		tp = pdf.ImageTypeFromMime(rsp.Header["Content-Type"][0])
-		infoPtr := pdf.RegisterImageReader(urlStr, tp, rsp.Body)
-		if pdf.Ok() {
-			imgWd, imgHt := infoPtr.Extent()
-			pdf.Image(urlStr, (wd-imgWd)/2.0, pdf.GetY()+ln,
-				imgWd, imgHt, false, tp, 0, "")
+	pdf.SetColumns(2, 10)
+	str := strings.Repeat(strings.Join(loremList(), " ")+" ", 12)
+	pdf.FlowText(0, []gofpdf.TextRunType{{Str: str}}, gofpdf.ParagraphType{Align: "L"})
+	if pdf.PageNo() < 2 {
+		t.Fatalf("expecting the paragraph to continue on a second page")
+	}
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	k := 72 / 25.4
+	cols := [2]int{}
+	for _, m := range regexp.MustCompile(`BT ([\d.]+) ([\d.]+) Td`).FindAllStringSubmatch(buf.String(), -1) {
+		x, _ := strconv.ParseFloat(m[1], 64)
+		y, _ := strconv.ParseFloat(m[2], 64)
+		switch {
+		case x >= 10*k-0.01 && x < 100*k:
+			cols[0]++
+		case x >= 110*k-0.01 && x < 200*k:
+			cols[1]++
+		default:
+			t.Fatalf("text at %.2f is outside of the columns", x)
+		}
+		if y < 20*k {
+			t.Fatalf("text at %.2f is below the page break trigger", y)
 		}
-	} else {
-		pdf.SetError(err)
 	}
-	fileStr := example.Filename("Fpdf_RegisterImageReader_url")
-	err = pdf.OutputFileAndClose(fileStr)
-	example.Summary(err, fileStr)
-	// Output:
-	// Successfully generated pdf/Fpdf_RegisterImageReader_url.pdf
+	if cols[0] == 0 || cols[1] == 0 {
+		t.Fatalf("expecting text in both columns, got %d and %d fragments", cols[0], cols[1])
+	}
+}
 
+// TestAddTOCPage checks that the table of contents is moved to its place,
+// lists the final page numbers of the headings and links to them, and that
+// the footers of the pages that follow it are numbered accordingly.
+func TestAddTOCPage(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A6", "")
+	pdf.SetCompression(false)
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-10)
+		pdf.CellFormat(0, 5, fmt.Sprintf("footer %d", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.AddPage()
+	pdf.AddTOCPage("")
+	for j := 1; j <= 30; j++ {
+		if j%10 == 1 {
+			pdf.AddPage()
+		}
+		pdf.Bookmark(fmt.Sprintf("heading %d", j), 0, -1)
+		pdf.Ln(5)
+	}
+	pdf.AddTOCPage("")
+	if pdf.Err() {
+		pdf.ClearError()
+	} else {
+		t.Fatalf("expecting an error when adding a second table of contents")
+	}
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	// 30 headings with space above each take two A6 pages
+	pages := regexp.MustCompile(`/Type /Page\b`).FindAllIndex(buf.Bytes(), -1)
+	if len(pages) != 6 {
+		t.Fatalf("expecting 6 pages, got %d", len(pages))
+	}
+	data := buf.String()
+	for j := 1; j <= 6; j++ {
+		if n := strings.Count(data, fmt.Sprintf("(footer %d)", j)); n != 1 {
+			t.Fatalf("expecting one footer %d, got %d", j, n)
+		}
+	}
+	// The first heading is on the page that follows the table
+	if !strings.Contains(data, "(heading 1)") || !regexp.MustCompile(`\(4\) Tj`).MatchString(data) {
+		t.Fatalf("table of contents does not list headings on page 4")
+	}
+	if n := strings.Count(data, "/Subtype /Link"); n != 30 {
+		t.Fatalf("expecting 30 links, got %d", n)
+	}
 }
 
-// ExampleFpdf_Beziergon demonstrates the Beziergon function.
-func ExampleFpdf_Beziergon() {
+// TestSetGradientInterpolation checks that gradients are written in Lab
+// when requested or when the document has an output intent profile, with
+// the colors converted from sRGB, and in device RGB otherwise.
+func TestSetGradientInterpolation(t *testing.T) {
+	gradient := func(setup func(pdf *gofpdf.Fpdf)) string {
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		pdf.SetCompression(false)
+		setup(pdf)
+		pdf.AddPage()
+		pdf.LinearGradient(10, 10, 100, 20, 255, 255, 255, 0, 0, 255, 0, 0, 1, 0)
+		var buf bytes.Buffer
+		if err := pdf.Output(&buf); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+	data := gradient(func(pdf *gofpdf.Fpdf) {})
+	if !strings.Contains(data, "/ColorSpace /DeviceRGB") ||
+		!strings.Contains(data, "/C0 [1.000 1.000 1.000] /C1 [0.000 0.000 1.000]") {
+		t.Fatalf("expecting a device RGB gradient by default")
+	}
+	data = gradient(func(pdf *gofpdf.Fpdf) {
+		pdf.SetGradientInterpolation(gofpdf.GradientInterpolationLab)
+	})
+	// sRGB blue is L* 29.6, a* 68.3, b* -112.0 relative to D50
+	if !strings.Contains(data, "/ColorSpace [/Lab <</WhitePoint [0.9642 1 0.8249]") ||
+		!regexp.MustCompile(`/C0 \[100\.000 -?0\.0\d\d -?0\.0\d\d\] /C1 \[29\.5\d\d 68\.[23]\d\d -11[12]\.\d+\]`).MatchString(data) {
+		t.Fatalf("expecting a Lab gradient from white to blue")
+	}
+	data = gradient(func(pdf *gofpdf.Fpdf) {
+		pdf.SetPDFX(gofpdf.PDFXType{Version: gofpdf.PDFX4,
+			Intent: gofpdf.OutputIntentType{ConditionID: "sRGB", Profile: []byte("profile"), Components: 3}})
+		pdf.SetTitle("Gradient", false)
+	})
+	if !strings.Contains(data, "/ColorSpace [/Lab") {
+		t.Fatalf("expecting a Lab gradient in a document with an output intent profile")
+	}
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetGradientInterpolation("HSV")
+	if pdf.Error() == nil {
+		t.Fatalf("expecting an error for an unsupported interpolation space")
+	}
+	pdf = gofpdf.New("P", "mm", "A4", "")
+	pdf.SetErrorf("earlier error")
+	pdf.SetGradientInterpolation("HSV")
+	if err := pdf.Error(); err == nil || err.Error() != "earlier error" {
+		t.Fatalf("earlier error replaced by %v", err)
+	}
+}
 
-	const (
-		margin      = 10
-		wd          = 210
-		unit        = (wd - 2*margin) / 6
-		ht          = 297
-		fontSize    = 15
-		msgStr      = `Demonstration of Beziergon function`
-		coefficient = 0.6
-		delta       = coefficient * unit
-		ln          = fontSize * 25.4 / 72
-		offsetX     = (wd - 4*unit) / 2.0
-		offsetY     = offsetX + 2*ln
-	)
+// TestSetNamedDest checks that named destinations are written to the name
+// tree in order and follow their pages when sections are rearranged, and
+// that named and remote links refer to them by name.
+func TestSetNamedDest(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.SetNamedDest("first")
+	if !pdf.Err() {
+		t.Fatalf("expecting an error for a destination before the first page")
+	}
+	pdf.ClearError()
+	pdf.AddPage()
+	pdf.LinkNamed(10, 10, 50, 10, "second")
+	pdf.LinkRemote(10, 30, 50, 10, "other.pdf", "intro")
+	pdf.LinkRemote(10, 50, 50, 10, "other.pdf", "")
+	pdf.AddSection("second", func() {
+		pdf.SetY(50)
+		pdf.SetNamedDest("second")
+	}, "first")
+	pdf.AddSection("first", func() {
+		pdf.SetNamedDest("first")
+	})
+	pdf.RenderSections()
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.String()
+	// Page 2 is the section "second", which is rendered after "first"
+	m := regexp.MustCompile(`/Dests <</Names \[\(first\) \[(\d+) 0 R /XYZ 0 813\.54 null\] ` +
+		`\(second\) \[(\d+) 0 R /XYZ 0 700\.16 null\]\]>>`).FindStringSubmatch(data)
+	if m == nil {
+		t.Fatalf("named destinations not found in the name tree")
+	}
+	pages := regexp.MustCompile(`(\d+) 0 obj\n<</Type /Page\n`).FindAllStringSubmatch(data, -1)
+	if len(pages) != 3 || m[1] != pages[2][1] || m[2] != pages[1][1] {
+		t.Fatalf("named destinations do not follow their sections")
+	}
+	for _, str := range []string{"/Dest (second)>>",
+		"/A <</S /GoToR /F (other.pdf) /D (intro)>>>>",
+		"/A <</S /GoToR /F (other.pdf) /D [0 /Fit]>>>>"} {
+		if !strings.Contains(data, str) {
+			t.Fatalf("link %s not found", str)
+		}
+	}
+}
 
-	srcList := []gofpdf.PointType{
-		{X: 0, Y: 0},
-		{X: 1, Y: 0},
-		{X: 1, Y: 1},
-		{X: 2, Y: 1},
-		{X: 2, Y: 2},
-		{X: 3, Y: 2},
-		{X: 3, Y: 3},
-		{X: 4, Y: 3},
-		{X: 4, Y: 4},
-		{X: 1, Y: 4},
-		{X: 1, Y: 3},
-		{X: 0, Y: 3},
+// TestFloatPrecisionMatrix checks that the precision set with
+// SetFloatPrecision() rounds coordinates but not the coefficients of
+// transformation matrices.
+func TestFloatPrecisionMatrix(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.SetFloatPrecision(1)
+	pdf.AddPage()
+	pdf.TransformBegin()
+	pdf.TransformRotate(30, 50, 50)
+	pdf.Rect(40.123, 40.123, 20, 20, "D")
+	pdf.TransformEnd()
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.String()
+	if !regexp.MustCompile(`0\.86603 0\.50000 -0\.50000 0\.86603 -?\d+(\.\d)? -?\d+(\.\d)? cm`).MatchString(data) {
+		t.Errorf("rotation matrix not written with full precision")
 	}
+	if !strings.Contains(data, "113.7 728.2 56.7 -56.7 re") {
+		t.Errorf("rectangle not written with one decimal place")
+	}
+}
 
-	ctrlList := []gofpdf.PointType{
-		{X: 1, Y: -1},
-		{X: 1, Y: 1},
-		{X: 1, Y: 1},
-		{X: 1, Y: 1},
-		{X: 1, Y: 1},
-		{X: 1, Y: 1},
-		{X: 1, Y: 1},
-		{X: 1, Y: 1},
-		{X: -1, Y: 1},
-		{X: -1, Y: -1},
-		{X: -1, Y: -1},
-		{X: -1, Y: -1},
+// TestRenderSectionsPageData checks that page private data and overflow
+// reports follow their pages when sections are rearranged.
+func TestRenderSectionsPageData(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.SetOverflowReport(true)
+	pdf.AddSection("second", func() {
+		pdf.Cell(40, 10, "second")
+	}, "first")
+	pdf.AddSection("first", func() {
+		pdf.SetPagePieceInfo("Example", []byte(`{"section": 1}`))
+		pdf.Cell(5, 10, "far too wide for its cell")
+	})
+	pdf.RenderSections()
+	// The sections are bound in the order in which they are registered
+	if report := pdf.OverflowReport(); len(report) != 1 || report[0].Page != 2 {
+		t.Fatalf("expecting an overflow on page 2, got %v", report)
+	}
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	pages := regexp.MustCompile(`(?s)<</Type /Page\n.*?endobj`).FindAllString(buf.String(), -1)
+	if len(pages) != 2 || strings.Contains(pages[0], "/PieceInfo") || !strings.Contains(pages[1], "/PieceInfo") {
+		t.Fatalf("expecting private data on page 2 only")
 	}
+}
 
+// TestSeparationPreviewsOnly checks that named destinations of the pages
+// that previews replace are removed.
+func TestSeparationPreviewsOnly(t *testing.T) {
 	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.AddPage()
-	pdf.SetFont("Helvetica", "", fontSize)
-	for j, src := range srcList {
-		srcList[j].X = offsetX + src.X*unit
-		srcList[j].Y = offsetY + src.Y*unit
-	}
-	for j, ctrl := range ctrlList {
-		ctrlList[j].X = ctrl.X * delta
-		ctrlList[j].Y = ctrl.Y * delta
+	pdf.SetCompression(false)
+	pdf.SetFont("Helvetica", "", 12)
+	for j := 1; j <= 2; j++ {
+		pdf.AddPage()
+		pdf.SetNamedDest(fmt.Sprintf("page%d", j))
+		pdf.Cell(40, 10, "text")
 	}
-	jPrev := len(srcList) - 1
-	srcPrev := srcList[jPrev]
-	curveList := []gofpdf.PointType{srcPrev} // point [, control 0, control 1, point]*
-	control := func(x, y float64) {
-		curveList = append(curveList, gofpdf.PointType{X: x, Y: y})
+	pdf.GenerateSeparationPreviews(gofpdf.SeparationPreviewType{Inks: []string{gofpdf.InkBlack}, Only: true})
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
 	}
-	for j, src := range srcList {
-		ctrl := ctrlList[jPrev]
-		control(srcPrev.X+ctrl.X, srcPrev.Y+ctrl.Y) // Control 0
-		ctrl = ctrlList[j]
-		control(src.X-ctrl.X, src.Y-ctrl.Y) // Control 1
-		curveList = append(curveList, src)  // Destination
-		jPrev = j
-		srcPrev = src
+	if strings.Contains(buf.String(), "/Dests") {
+		t.Fatalf("named destinations of replaced pages are written")
 	}
-	pdf.MultiCell(wd-margin-margin, ln, msgStr, "", "C", false)
-	pdf.SetDashPattern([]float64{0.8, 0.8}, 0)
-	pdf.SetDrawColor(160, 160, 160)
-	pdf.Polygon(srcList, "D")
-	pdf.SetDashPattern([]float64{}, 0)
-	pdf.SetDrawColor(64, 64, 128)
-	pdf.SetLineWidth(pdf.GetLineWidth() * 3)
-	pdf.Beziergon(curveList, "D")
-	fileStr := example.Filename("Fpdf_Beziergon")
-	err := pdf.OutputFileAndClose(fileStr)
-	example.Summary(err, fileStr)
-	// Output:
-	// Successfully generated pdf/Fpdf_Beziergon.pdf
-
 }
 
-// ExampleFpdf_SetFontLoader demonstrates loading a non-standard font using a generalized
-// font loader. fontResourceType implements the FontLoader interface and is
-// defined locally in the test source code.
-func ExampleFpdf_SetFontLoader() {
-	var fr fontResourceType
+// TestSetTransferFunction checks that the tints of the points of a transfer
+// function are written as the additive values of PDF transfer functions.
+func TestSetTransferFunction(t *testing.T) {
 	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.SetFontLoader(fr)
-	pdf.AddFont("Calligrapher", "", "calligra.json")
+	pdf.SetCompression(false)
 	pdf.AddPage()
-	pdf.SetFont("Calligrapher", "", 35)
-	pdf.Cell(0, 10, "Load fonts from any source")
-	fileStr := example.Filename("Fpdf_SetFontLoader")
-	err := pdf.OutputFileAndClose(fileStr)
-	example.Summary(err, fileStr)
-	// Output:
-	// Generalized font loader reading calligra.json
-	// Generalized font loader reading calligra.z
-	// Successfully generated pdf/Fpdf_SetFontLoader.pdf
+	pdf.SetTransferFunction([]gofpdf.PointType{{X: 0, Y: 0}, {X: 0.2, Y: 0.1}, {X: 0.5, Y: 0.38}, {X: 1, Y: 1}})
+	pdf.Rect(10, 10, 20, 20, "F")
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	// A 50% tint, an additive value of 0.5, is printed with 38% ink, an
+	// additive value of 0.62
+	want := "/TR2 <</FunctionType 3 /Domain [0.0000 1.0000] /Functions [" +
+		"<</FunctionType 2 /Domain [0 1] /C0 [0.0000] /C1 [0.6200] /N 1>> " +
+		"<</FunctionType 2 /Domain [0 1] /C0 [0.6200] /C1 [0.9000] /N 1>> " +
+		"<</FunctionType 2 /Domain [0 1] /C0 [0.9000] /C1 [1.0000] /N 1>>] " +
+		"/Bounds [0.5000 0.8000] /Encode [0 1 0 1 0 1]>>"
+	if !strings.Contains(buf.String(), want) {
+		t.Fatalf("transfer function not found")
+	}
 }
 
-// ExampleFpdf_MoveTo demonstrates the Path Drawing functions, such as: MoveTo,
-// LineTo, CurveTo, ..., ClosePath and DrawPath.
-func ExampleFpdf_MoveTo() {
+// TestTableRowHeight checks that rows are as tall as the lines in which
+// MultiCell() prints their cells
+func TestTableRowHeight(t *testing.T) {
 	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "", 12)
 	pdf.AddPage()
-	pdf.MoveTo(20, 20)
-	pdf.LineTo(170, 20)
-	pdf.ArcTo(170, 40, 20, 20, 0, 90, 0)
-	pdf.CurveTo(190, 100, 105, 100)
-	pdf.CurveBezierCubicTo(20, 100, 105, 200, 20, 200)
-	pdf.ClosePath()
-	pdf.SetFillColor(200, 200, 200)
-	pdf.SetLineWidth(3)
-	pdf.DrawPath("DF")
-	fileStr := example.Filename("Fpdf_MoveTo_path")
-	err := pdf.OutputFileAndClose(fileStr)
-	example.Summary(err, fileStr)
-	// Output:
-	// Successfully generated pdf/Fpdf_MoveTo_path.pdf
+	y0 := pdf.GetY()
+	tbl := pdf.NewTable([]gofpdf.TableColumnType{{W: 40}, {W: 40}})
+	tbl.Padding, tbl.LineHt = 0, 5
+	// MultiCell() keeps the empty line before the final line feed
+	tbl.Row("one\n\n", "two")
+	tbl.End()
+	if pdf.Err() {
+		t.Fatal(pdf.Error())
+	}
+	if ht := pdf.GetY() - y0; math.Abs(ht-10) > 1e-9 {
+		t.Errorf("table is %g high, expected 10", ht)
+	}
 }
 
-// ExampleFpdf_SetLineJoinStyle demonstrates various line cap and line join styles.
-func ExampleFpdf_SetLineJoinStyle() {
-	const offset = 75.0
-	pdf := gofpdf.New("L", "mm", "A4", "")
-	pdf.AddPage()
-	var draw = func(cap, join string, x0, y0, x1, y1 float64) {
-		// transform begin & end needed to isolate caps and joins
-		pdf.SetLineCapStyle(cap)
-		pdf.SetLineJoinStyle(join)
-
-		// Draw thick line
-		pdf.SetDrawColor(0x33, 0x33, 0x33)
-		pdf.SetLineWidth(30.0)
-		pdf.MoveTo(x0, y0)
-		pdf.LineTo((x0+x1)/2+offset, (y0+y1)/2)
-		pdf.LineTo(x1, y1)
-		pdf.DrawPath("D")
-
-		// Draw thin helping line
-		pdf.SetDrawColor(0xFF, 0x33, 0x33)
-		pdf.SetLineWidth(2.56)
-		pdf.MoveTo(x0, y0)
-		pdf.LineTo((x0+x1)/2+offset, (y0+y1)/2)
-		pdf.LineTo(x1, y1)
-		pdf.DrawPath("D")
-
+// TestGetRegisteredFontsCore checks that core fonts are listed only in the
+// styles that can be selected
+func TestGetRegisteredFontsCore(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	for _, fnt := range pdf.GetRegisteredFonts() {
+		pdf.SetFont(fnt.Family, fnt.Style, 12)
+		if pdf.Err() {
+			t.Errorf("font %s %q is listed but cannot be selected: %s", fnt.Family, fnt.Style, pdf.Error())
+			pdf.ClearError()
+		}
 	}
-	x := 35.0
-	caps := []string{"butt", "square", "round"}
-	joins := []string{"bevel", "miter", "round"}
-	for i := range caps {
-		draw(caps[i], joins[i], x, 50, x, 160)
-		x += offset
+	pdf.SetFont("Helvetica-1251", "B", 12)
+	if _, ok := pdf.Error().(*gofpdf.FontError); !ok {
+		t.Errorf("selecting a bold Cyrillic Helvetica gives %v, expected a FontError", pdf.Error())
 	}
-	fileStr := example.Filename("Fpdf_SetLineJoinStyle_caps")
-	err := pdf.OutputFileAndClose(fileStr)
-	example.Summary(err, fileStr)
-	// Output:
-	// Successfully generated pdf/Fpdf_SetLineJoinStyle_caps.pdf
 }
 
-// ExampleFpdf_DrawPath demonstrates various fill modes.
-func ExampleFpdf_DrawPath() {
+// TestMultiCellJustifyUTF8 checks that the lines of justified text in a
+// UTF-8 font, other than the last, are stretched to the width of the cell by
+// equal adjustments after their spaces
+func TestMultiCellJustifyUTF8(t *testing.T) {
 	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.SetDrawColor(0xff, 0x00, 0x00)
-	pdf.SetFillColor(0x99, 0x99, 0x99)
-	pdf.SetFont("Helvetica", "", 15)
+	pdf.SetCompression(false)
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.SetFont("dejavu", "", 12)
 	pdf.AddPage()
-	pdf.SetAlpha(1, "Multiply")
-	var (
-		polygon = func(cx, cy, r, n, dir float64) {
-			da := 2 * math.Pi / n
-			pdf.MoveTo(cx+r, cy)
-			pdf.Text(cx+r, cy, "0")
-			i := 1
-			for a := da; a < 2*math.Pi; a += da {
-				x, y := cx+r*math.Cos(dir*a), cy+r*math.Sin(dir*a)
-				pdf.LineTo(x, y)
-				pdf.Text(x, y, strconv.Itoa(i))
-				i++
-			}
-			pdf.ClosePath()
-		}
-		polygons = func(cx, cy, r, n, dir float64) {
-			d := 1.0
-			for rf := r; rf > 0; rf -= 10 {
-				polygon(cx, cy, rf, n, d)
-				d *= dir
-			}
+	txtStr := "Größere Straßen führen über die Brücke, während schmale Gassen"
+	lines := pdf.SplitText(txtStr, 60)
+	pdf.MultiCell(60, 6, txtStr, "", "J", false)
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.String()
+	shifts := regexp.MustCompile(`\[[^\]]*\] TJ`).FindAllString(data, -1)
+	if len(lines) != 3 || len(shifts) != 2 {
+		t.Fatalf("%d lines with %d justified, expected 3 with 2", len(lines), len(shifts))
+	}
+	if !strings.Contains(data, ")Tj") {
+		t.Errorf("the last line is not printed with its natural spacing")
+	}
+	cMargin := pdf.GetCellMargin()
+	_, fontSize := pdf.GetFontSize()
+	for j, str := range shifts[:2] {
+		// Space needed after each space, in thousandths of the font size
+		ns := strings.Count(lines[j], " ")
+		want := (60 - 2*cMargin - pdf.GetStringWidth(lines[j])) / float64(ns) * 1000 / fontSize
+		list := regexp.MustCompile(`\) (-[0-9.]+) \(`).FindAllStringSubmatch(str, -1)
+		if len(list) != ns {
+			t.Fatalf("line %d has %d adjustments, expected %d", j+1, len(list), ns)
 		}
-		star = func(cx, cy, r, n float64) {
-			da := 4 * math.Pi / n
-			pdf.MoveTo(cx+r, cy)
-			for a := da; a < 4*math.Pi+da; a += da {
-				x, y := cx+r*math.Cos(a), cy+r*math.Sin(a)
-				pdf.LineTo(x, y)
+		for _, m := range list {
+			if got, _ := strconv.ParseFloat(m[1], 64); math.Abs(-got-want) > 0.01 {
+				t.Errorf("line %d is adjusted by %s after a space, expected %.3f", j+1, m[1], -want)
 			}
-			pdf.ClosePath()
 		}
-	)
-	// triangle
-	polygons(55, 45, 40, 3, 1)
-	pdf.DrawPath("B")
-	pdf.Text(15, 95, "B (same direction, non zero winding)")
-
-	// square
-	polygons(155, 45, 40, 4, 1)
-	pdf.DrawPath("B*")
-	pdf.Text(115, 95, "B* (same direction, even odd)")
-
-	// pentagon
-	polygons(55, 145, 40, 5, -1)
-	pdf.DrawPath("B")
-	pdf.Text(15, 195, "B (different direction, non zero winding)")
-
-	// hexagon
-	polygons(155, 145, 40, 6, -1)
-	pdf.DrawPath("B*")
-	pdf.Text(115, 195, "B* (different direction, even odd)")
-
-	// star
-	star(55, 245, 40, 5)
-	pdf.DrawPath("B")
-	pdf.Text(15, 290, "B (non zero winding)")
-
-	// star
-	star(155, 245, 40, 5)
-	pdf.DrawPath("B*")
-	pdf.Text(115, 290, "B* (even odd)")
-
-	fileStr := example.Filename("Fpdf_DrawPath_fill")
-	err := pdf.OutputFileAndClose(fileStr)
-	example.Summary(err, fileStr)
-	// Output:
-	// Successfully generated pdf/Fpdf_DrawPath_fill.pdf
+	}
 }
 
-// ExampleFpdf_CreateTemplate demonstrates creating and using templates
-func ExampleFpdf_CreateTemplate() {
+// TestSetFillNone checks that shapes and cells drawn after SetFillNone() and
+// SetDrawNone() leave out filling and stroking until a color is set again
+func TestSetFillNone(t *testing.T) {
 	pdf := gofpdf.New("P", "mm", "A4", "")
 	pdf.SetCompression(false)
-	// pdf.SetFont("Times", "", 12)
-	template := pdf.CreateTemplate(func(tpl *gofpdf.Tpl) {
-		tpl.Image(example.ImageFile("logo.png"), 6, 6, 30, 0, false, "", 0, "")
-		tpl.SetFont("Arial", "B", 16)
-		tpl.Text(40, 20, "Template says hello")
-		tpl.SetDrawColor(0, 100, 200)
-		tpl.SetLineWidth(2.5)
-		tpl.Line(95, 12, 105, 22)
-	})
-	_, tplSize := template.Size()
-	// fmt.Println("Size:", tplSize)
-	// fmt.Println("Scaled:", tplSize.ScaleBy(1.5))
-
-	template2 := pdf.CreateTemplate(func(tpl *gofpdf.Tpl) {
-		tpl.UseTemplate(template)
-		subtemplate := tpl.CreateTemplate(func(tpl2 *gofpdf.Tpl) {
-			tpl2.Image(example.ImageFile("logo.png"), 6, 86, 30, 0, false, "", 0, "")
-			tpl2.SetFont("Arial", "B", 16)
-			tpl2.Text(40, 100, "Subtemplate says hello")
-			tpl2.SetDrawColor(0, 200, 100)
-			tpl2.SetLineWidth(2.5)
-			tpl2.Line(102, 92, 112, 102)
-		})
-		tpl.UseTemplate(subtemplate)
-	})
-
-	pdf.SetDrawColor(200, 100, 0)
-	pdf.SetLineWidth(2.5)
-	pdf.SetFont("Arial", "B", 16)
-
-	// serialize and deserialize template
-	b, _ := template2.Serialize()
-	template3, _ := gofpdf.DeserializeTemplate(b)
-
+	pdf.SetFont("Helvetica", "", 12)
 	pdf.AddPage()
-	pdf.UseTemplate(template3)
-	pdf.UseTemplateScaled(template3, gofpdf.PointType{X: 0, Y: 30}, tplSize)
-	pdf.Line(40, 210, 60, 210)
-	pdf.Text(40, 200, "Template example page 1")
+	shape := func() {
+		pdf.Rect(10, 10, 20, 20, "FD")
+	}
+	shape()
+	pdf.SetFillNone()
+	shape()
+	pdf.CellFormat(20, 10, "", "1", 1, "", true, 0, "")
+	pdf.SetFillColor(255, 0, 0)
+	pdf.SetDrawNone()
+	shape()
+	pdf.CellFormat(20, 10, "", "1", 1, "", true, 0, "")
+	pdf.SetFillNone()
+	shape()
+	pdf.SetDrawColor(0, 0, 255)
+	shape()
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var ops []string
+	for _, m := range regexp.MustCompile(` re ([a-zA-Z*]+)`).FindAllStringSubmatch(buf.String(), -1) {
+		ops = append(ops, m[1])
+	}
+	if got, want := strings.Join(ops, " "), "B S S f f n S"; got != want {
+		t.Errorf("rectangles painted with %s, expected %s", got, want)
+	}
+}
 
+// TestSetFillLabColor checks the color operators of calibrated RGB and Lab
+// colors and the color spaces to which the page resources refer
+func TestSetFillLabColor(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.SetFont("Helvetica", "", 12)
 	pdf.AddPage()
-	pdf.UseTemplate(template2)
-	pdf.UseTemplateScaled(template3, gofpdf.PointType{X: 0, Y: 30}, tplSize.ScaleBy(1.4))
-	pdf.Line(60, 210, 80, 210)
-	pdf.Text(40, 200, "Template example page 2")
+	pdf.SetDrawCalRGBColor(255, 128, 0)
+	if r, g, b := pdf.GetDrawColor(); r != 255 || g != 128 || b != 0 {
+		t.Errorf("draw color (%d, %d, %d), expected (255, 128, 0)", r, g, b)
+	}
+	// Out of range components are bounded
+	pdf.SetFillLabColor(120, -200, 50)
+	pdf.Rect(10, 10, 20, 20, "FD")
+	pdf.SetTextLabColor(50, 60, -70)
+	pdf.Text(10, 40, "Lab")
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.String()
+	for _, str := range []string{
+		"/CSCalRGB CS 1.000 0.502 0.000 SCN",
+		"/CSLab cs 100.000 -128.000 50.000 scn",
+		"/CSLab cs 50.000 60.000 -70.000 scn",
+	} {
+		if !strings.Contains(data, str) {
+			t.Errorf("%q not found", str)
+		}
+	}
+	for nameStr, spaceStr := range map[string]string{
+		"CalRGB": "[/CalRGB <</WhitePoint [0.9505 1 1.089] /Gamma [2.2 2.2 2.2]",
+		"Lab":    "[/Lab <</WhitePoint [0.9642 1 0.8249] /Range [-128 127 -128 127]>>]",
+	} {
+		m := regexp.MustCompile(`/CS` + nameStr + ` (\d+) 0 R`).FindStringSubmatch(data)
+		if m == nil {
+			t.Errorf("color space %s is not in the resources", nameStr)
+		} else if !strings.Contains(data, "\n"+m[1]+" 0 obj\n"+spaceStr) {
+			t.Errorf("resource %s does not refer to its color space", nameStr)
+		}
+	}
+}
 
-	fileStr := example.Filename("Fpdf_CreateTemplate")
-	err := pdf.OutputFileAndClose(fileStr)
-	example.Summary(err, fileStr)
-	// Output:
-	// Successfully generated pdf/Fpdf_CreateTemplate.pdf
+// TestWillFit checks that WillFit() reports whether content would cause an
+// automatic page break
+func TestWillFit(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.AddPage()
+	pdf.SetY(250)
+	if !pdf.WillFit(27) || pdf.WillFit(28) {
+		t.Errorf("27 mm expected to fit above the bottom margin and 28 mm not to")
+	}
+	if left := pdf.SpaceLeft(); math.Abs(left-27) > 0.001 {
+		t.Errorf("%g mm left, expected 27", left)
+	}
+	pdf.SetAutoPageBreak(false, 20)
+	if !pdf.WillFit(28) {
+		t.Errorf("content expected to fit without automatic page breaks")
+	}
+	if left := pdf.SpaceLeft(); math.Abs(left-27) > 0.001 {
+		t.Errorf("%g mm left without automatic page breaks, expected 27", left)
+	}
 }
 
-// ExampleFpdf_AddFontFromBytes demonstrate how to use embedded fonts from byte array
-func ExampleFpdf_AddFontFromBytes() {
+// TestNestedHeaderState verifies that a page added from within a header
+// function leaves the outer header in effect
+func TestNestedHeaderState(t *testing.T) {
 	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "", 10)
+	nested := false
+	pdf.SetRunningHeadFunc(0, func(head gofpdf.RunningHeadType) {})
+	pdf.SetHeaderFunc(func() {
+		if !nested {
+			nested = true
+			pdf.AddPage()
+			if !pdf.WillFit(1000) {
+				t.Errorf("header state is lost after a nested page is added")
+			}
+		}
+	})
 	pdf.AddPage()
-	pdf.AddFontFromBytes("calligra", "", files.CalligraJson, files.CalligraZ)
-	pdf.SetFont("calligra", "", 16)
-	pdf.Cell(40, 10, "Hello World With Embedded Font!")
-	fileStr := example.Filename("Fpdf_EmbeddedFont")
-	err := pdf.OutputFileAndClose(fileStr)
-	example.Summary(err, fileStr)
-	// Output:
-	// Successfully generated pdf/Fpdf_EmbeddedFont.pdf
+	if pdf.WillFit(1000) {
+		t.Errorf("header state remains after the header function returns")
+	}
+	if err := pdf.Output(ioutil.Discard); err != nil {
+		t.Fatal(err)
+	}
 }
 
-// This example demonstrate Clipped table cells
-func ExampleFpdf_ClipRect() {
-	marginCell := 2. // margin of top/bottom of cell
+// TestMultiCellFloatBorder verifies that the lines of MultiCell() beside a
+// float are printed with the border and background of the paragraph
+func TestMultiCellFloatBorder(t *testing.T) {
 	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.SetFont("Arial", "", 12)
+	pdf.SetCompression(false)
 	pdf.AddPage()
-	pagew, pageh := pdf.GetPageSize()
-	mleft, mright, _, mbottom := pdf.GetMargins()
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.ImageFloat(example.ImageFile("logo.png"), "L", 30, 20, 0, gofpdf.ImageOptions{})
+	pdf.MultiCell(0, 10, "one\ntwo\nthree", "1", "L", true)
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.String()
+	// Each line is filled, the first two beside the float
+	fills := regexp.MustCompile(`([\d.]+) [\d.]+ [\d.]+ -28\.35 re f`).FindAllStringSubmatch(data, -1)
+	if len(fills) != 3 {
+		t.Fatalf("expecting 3 filled lines, got %d", len(fills))
+	}
+	for j, x := range []string{"113.39", "113.39", "28.35"} {
+		if fills[j][1] != x {
+			t.Errorf("line %d is filled from %s, expecting %s", j, fills[j][1], x)
+		}
+	}
+	// Both sides of each line and the top and bottom of the paragraph
+	if n := strings.Count(data, " l S"); n != 8 {
+		t.Errorf("expecting 8 border segments, got %d", n)
+	}
+}
 
-	cols := []float64{60, 100, pagew - mleft - mright - 100 - 60}
-	rows := [][]string{}
-	for i := 1; i <= 50; i++ {
-		word := fmt.Sprintf("%d:%s", i, strings.Repeat("A", i%100))
-		rows = append(rows, []string{word, word, word})
+// TestLegendNoFont verifies that a legend is drawn in Helvetica when no font
+// has been set
+func TestLegendNoFont(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddPage()
+	lg := gofpdf.NewLegend("Legend")
+	lg.Add("Lake", gofpdf.LegendBox, gofpdf.RGBType{R: 170, G: 210, B: 240})
+	if wd, ht := lg.Draw(pdf, 10, 10); wd <= 0 || ht <= 0 {
+		t.Errorf("legend has size %.2f x %.2f", wd, ht)
+	}
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, str := range []string{"/BaseFont /Helvetica-Bold", "(Legend) Tj", "(Lake) Tj"} {
+		if !strings.Contains(buf.String(), str) {
+			t.Errorf("%s not found in output", str)
+		}
 	}
+}
 
-	for _, row := range rows {
-		_, lineHt := pdf.GetFontSize()
-		height := lineHt + marginCell
+// TestRenderDocumentBorder verifies that the rows of a table in a document
+// description are drawn with only the sides given by its border
+func TestRenderDocumentBorder(t *testing.T) {
+	doc, err := gofpdf.ParseDocument(strings.NewReader(`{
+	"styles": {"default": {"fontFamily": "Helvetica", "fontSize": 10, "border": "LR"}},
+	"pages": [{"blocks": [
+		{"type": "table", "rows": "{{items}}", "columns": [{"header": "Item", "field": "name"}]}
+	]}]
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf := gofpdf.RenderDocument(doc, map[string]interface{}{
+		"items": []map[string]string{{"name": "bolt"}},
+	})
+	pdf.SetCompression(false)
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.String()
+	if strings.Contains(data, " re S") {
+		t.Errorf("a table with sides LR is drawn with rectangles")
+	}
+	// The left and right sides of the header and of the row
+	if n := strings.Count(data, " l S"); n != 4 {
+		t.Errorf("expecting 4 sides, got %d", n)
+	}
+}
 
-		x, y := pdf.GetXY()
-		// add a new page if the height of the row doesn't fit on the page
-		if y+height >= pageh-mbottom {
-			pdf.AddPage()
-			x, y = pdf.GetXY()
-		}
-		for i, txt := range row {
-			width := cols[i]
-			pdf.Rect(x, y, width, height, "")
-			pdf.ClipRect(x, y, width, height, false)
-			pdf.Cell(width, height, txt)
-			pdf.ClipEnd()
-			x += width
-		}
-		pdf.Ln(-1)
+// TestFormCheckboxUnknownMark verifies that the drawing state is restored
+// when a checkbox with an unknown mark is rejected
+func TestFormCheckboxUnknownMark(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetDrawColor(10, 20, 30)
+	pdf.SetLineWidth(0.4)
+	st := gofpdf.FormStyleType{Mark: 99, LineWidth: 1, ClrLine: gofpdf.RGBType{R: 200}}
+	pdf.FormCheckbox(10, 10, 5, true, "", st)
+	if !pdf.Err() {
+		t.Fatalf("expecting an error for an unknown mark")
+	}
+	if r, g, b := pdf.GetDrawColor(); r != 10 || g != 20 || b != 30 {
+		t.Errorf("draw color is %d %d %d after the checkbox", r, g, b)
+	}
+	if wd := pdf.GetLineWidth(); wd != 0.4 {
+		t.Errorf("line width is %.2f after the checkbox", wd)
 	}
-	fileStr := example.Filename("Fpdf_ClippedTableCells")
-	err := pdf.OutputFileAndClose(fileStr)
-	example.Summary(err, fileStr)
-	// Output:
-	// Successfully generated pdf/Fpdf_ClippedTableCells.pdf
 }
 
-// This example demonstrate wrapped table cells
-func ExampleFpdf_Rect() {
-	marginCell := 2. // margin of top/bottom of cell
+// TestSignatureBlockImageError verifies that the drawing state and position
+// are restored when the signature image cannot be loaded
+func TestSignatureBlockImageError(t *testing.T) {
 	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.SetFont("Arial", "", 12)
 	pdf.AddPage()
-	pagew, pageh := pdf.GetPageSize()
-	mleft, mright, _, mbottom := pdf.GetMargins()
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetDrawColor(10, 20, 30)
+	pdf.SetXY(15, 25)
+	sb := gofpdf.NewSignatureBlock()
+	sb.ImageStr = example.ImageFile("no-such-signature.png")
+	pdf.SignatureBlock(20, 30, 170, 40, sb)
+	if !pdf.Err() {
+		t.Fatalf("expecting an error for a missing signature image")
+	}
+	if r, g, b := pdf.GetDrawColor(); r != 10 || g != 20 || b != 30 {
+		t.Errorf("draw color is %d %d %d after the signature block", r, g, b)
+	}
+	if x, y := pdf.GetXY(); x != 15 || y != 25 {
+		t.Errorf("position is (%.2f, %.2f) after the signature block", x, y)
+	}
+}
 
-	cols := []float64{60, 100, pagew - mleft - mright - 100 - 60}
-	rows := [][]string{}
-	for i := 1; i <= 30; i++ {
-		word := fmt.Sprintf("%d:%s", i, strings.Repeat("A", i%100))
-		rows = append(rows, []string{word, word, word})
+// TestUnusedUTF8Font verifies that a UTF-8 font in which no text is shown,
+// whether it is never selected or its text is drawn as paths, is left out of
+// the document, and that one whose text is shown is written
+func TestUnusedUTF8Font(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", example.FontDir())
+	pdf.SetCompression(false)
+	pdf.AddUTF8Font("dejavu", "", "DejaVuSansCondensed.ttf")
+	pdf.AddUTF8Font("dejavu", "B", "DejaVuSansCondensed-Bold.ttf")
+	pdf.AddUTF8Font("dejavu", "I", "DejaVuSansCondensed-Oblique.ttf")
+	pdf.AddPage()
+	pdf.SetTextAsPaths(true)
+	pdf.SetFont("dejavu", "B", 12)
+	pdf.Cell(40, 10, "Paths")
+	pdf.SetTextAsPaths(false)
+	pdf.Cell(40, 10, "Shown")
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.String()
+	if n := strings.Count(data, "/Subtype /Type0"); n != 1 {
+		t.Errorf("expecting 1 font, got %d", n)
+	}
+	if !strings.Contains(data, "/BaseFont /utf8dejavuB") {
+		t.Errorf("font in which text is shown is left out")
+	}
+	// Every font selected in the content is in the resources
+	for _, m := range regexp.MustCompile(`/(F\d+) [\d.]+ Tf`).FindAllStringSubmatch(data, -1) {
+		if !regexp.MustCompile("/" + m[1] + ` \d+ 0 R`).MatchString(data) {
+			t.Errorf("font %s is selected but not in the resources", m[1])
+		}
 	}
 
-	for _, row := range rows {
-		curx, y := pdf.GetXY()
-		x := curx
+	// A font whose text is only drawn as paths
+	pdf = gofpdf.New("P", "mm", "A4", example.FontDir())
+	pdf.SetCompression(false)
+	pdf.AddUTF8Font("dejavu", "", "DejaVuSansCondensed.ttf")
+	pdf.AddPage()
+	pdf.SetTextAsPaths(true)
+	pdf.SetFont("dejavu", "", 12)
+	pdf.Cell(40, 10, "Paths")
+	buf.Reset()
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "/Subtype /Type0") || strings.Contains(buf.String(), " Tf") {
+		t.Errorf("font whose text is drawn as paths is written")
+	}
+}
 
-		height := 0.
-		_, lineHt := pdf.GetFontSize()
+// TestSymbolAlias verifies that the family "Symbol" selects ZapfDingbats, as
+// it always has, and that the Symbol font is selected with "StandardSymbol"
+func TestSymbolAlias(t *testing.T) {
+	for _, c := range []struct{ familyStr, fontStr string }{
+		{"Symbol", "ZapfDingbats"},
+		{"StandardSymbol", "Symbol"},
+	} {
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		pdf.SetCompression(false)
+		pdf.AddPage()
+		pdf.SetFont(c.familyStr, "", 12)
+		pdf.Cell(10, 10, "a")
+		var buf bytes.Buffer
+		if err := pdf.Output(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if str := "/BaseFont /" + c.fontStr + "\n"; !strings.Contains(buf.String(), str) {
+			t.Errorf("family %s does not select %s", c.familyStr, c.fontStr)
+		}
+	}
+}
 
-		for i, txt := range row {
-			lines := pdf.SplitLines([]byte(txt), cols[i])
-			h := float64(len(lines))*lineHt + marginCell*float64(len(lines))
-			if h > height {
-				height = h
+// TestContinuationMarkerRoom verifies that the marker below the last line
+// on a page fits above the page break threshold
+func TestContinuationMarkerRoom(t *testing.T) {
+	for _, nextHt := range []float64{0, 8} {
+		pdf := gofpdf.New("P", "mm", "A6", "")
+		pdf.SetFont("Times", "", 10)
+		_, fontSize := pdf.GetFontSize()
+		room := nextHt
+		if room == 0 {
+			room = 1.2 * 0.8 * fontSize
+		}
+		_, pageHt := pdf.GetPageSize()
+		_, margin := pdf.GetAutoPageBreak()
+		markers := gofpdf.NewContinuationMarkers()
+		markers.NextHt = nextHt
+		count := 0
+		markers.Fnc = func(txtStr string, w float64, next bool) {
+			if next {
+				count++
+				if y := pdf.GetY(); y+room > pageHt-margin+1e-9 {
+					t.Errorf("marker of height %.2f at %.2f extends below the threshold %.2f",
+						room, y, pageHt-margin)
+				}
 			}
 		}
-		// add a new page if the height of the row doesn't fit on the page
-		if pdf.GetY()+height > pageh-mbottom {
-			pdf.AddPage()
-			y = pdf.GetY()
+		pdf.SetContinuationMarkers(markers)
+		pdf.AddPage()
+		pdf.MultiCell(0, 5, strings.Repeat(lorem()+" ", 6), "", "J", false)
+		if count == 0 {
+			t.Errorf("no marker printed")
 		}
-		for i, txt := range row {
-			width := cols[i]
-			pdf.Rect(x, y, width, height, "")
-			pdf.MultiCell(width, lineHt+marginCell, txt, "", "", false)
-			x += width
-			pdf.SetXY(x, y)
+	}
+}
+
+// TestImageCaptionTopKept verifies that a caption above an image in flowing
+// mode is not left on a page without its image
+func TestImageCaptionTopKept(t *testing.T) {
+	for _, c := range []struct {
+		y, h float64
+		opt  gofpdf.ImageOptions
+		page int
+		end  float64
+	}{
+		// Too tall to fit with its caption on any page
+		{10, 115, gofpdf.ImageOptions{}, 1, 132},
+		// Cannot be scaled enough to fit below its caption
+		{60, 80, gofpdf.ImageOptions{FlowBreak: gofpdf.ImageFlowScale, MinScale: 0.9}, 2, 97},
+	} {
+		pdf := gofpdf.New("P", "mm", "A6", "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.SetImageCaptionStyle(gofpdf.ImageCaptionType{PosStr: "T", LineHt: 5, Gap: 2})
+		pdf.AddPage()
+		pdf.SetY(c.y)
+		c.opt.Caption = "Logo"
+		pdf.ImageOptions(example.ImageFile("logo.png"), 10, 0, 40, c.h, true, c.opt, 0, "")
+		if err := pdf.Error(); err != nil {
+			t.Fatal(err)
+		}
+		if page, y := pdf.PageNo(), pdf.GetY(); page != c.page || math.Abs(y-c.end) > 0.01 {
+			t.Errorf("image of height %.0f ends on page %d at %.2f, expecting page %d at %.2f",
+				c.h, page, y, c.page, c.end)
 		}
-		pdf.SetXY(curx, y+height)
 	}
-	fileStr := example.Filename("Fpdf_WrappedTableCells")
-	err := pdf.OutputFileAndClose(fileStr)
-	example.Summary(err, fileStr)
-	// Output:
-	// Successfully generated pdf/Fpdf_WrappedTableCells.pdf
 }
 
-// ExampleFpdf_SetJavascript demonstrates including JavaScript in the document.
-func ExampleFpdf_SetJavascript() {
+// TestLayoutGuidesUsage verifies that layout guides that are not kept are
+// left out when the document is printed or exported
+func TestLayoutGuidesUsage(t *testing.T) {
 	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.SetJavascript("print(true);")
+	pdf.SetCompression(false)
+	pdf.SetLayoutGuides(&gofpdf.LayoutGuidesType{Margins: true})
 	pdf.AddPage()
-	pdf.SetFont("Arial", "", 12)
-	pdf.Write(10, "Auto-print.")
-	fileStr := example.Filename("Fpdf_SetJavascript")
-	err := pdf.OutputFileAndClose(fileStr)
-	example.Summary(err, fileStr)
-	// Output:
-	// Successfully generated pdf/Fpdf_SetJavascript.pdf
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	str := buf.String()
+	for _, want := range []string{"/PrintState /OFF", "/ExportState /OFF",
+		"/Event /Print", "/Event /Export"} {
+		if !strings.Contains(str, want) {
+			t.Errorf("%s missing from layer of layout guides", want)
+		}
+	}
 }
 
-// ExampleFpdf_AddSpotColor demonstrates spot color use
-func ExampleFpdf_AddSpotColor() {
+// TestMeasureTextWidth verifies that MeasureText agrees with
+// GetStringWidth for core and UTF-8 fonts
+func TestMeasureTextWidth(t *testing.T) {
 	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.AddSpotColor("PANTONE 145 CVC", 0, 42, 100, 25)
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
 	pdf.AddPage()
-	pdf.SetFillSpotColor("PANTONE 145 CVC", 90)
-	pdf.Rect(80, 40, 50, 50, "F")
-	fileStr := example.Filename("Fpdf_AddSpotColor")
-	err := pdf.OutputFileAndClose(fileStr)
-	example.Summary(err, fileStr)
-	// Output:
-	// Successfully generated pdf/Fpdf_AddSpotColor.pdf
+	for _, family := range []string{"Helvetica", "dejavu"} {
+		pdf.SetFont(family, "", 13)
+		for _, str := range []string{"Hello, world", "Größe", ""} {
+			want := pdf.GetStringWidth(str)
+			if got := pdf.MeasureText(family, "", 13, str); math.Abs(got-want) > 1e-9 {
+				t.Errorf("%s %q: MeasureText %.4f, GetStringWidth %.4f", family, str, got, want)
+			}
+		}
+	}
+	if err := pdf.Error(); err != nil {
+		t.Fatal(err)
+	}
 }
 
-// ExampleFpdf_RegisterAlias demonstrates how to use `RegisterAlias` to create a table of
-// contents.
-func ExampleFpdf_RegisterAlias() {
+// TestAddCheckBoxMark verifies that the appearance of a checked check box
+// shows the mark drawn by FormCheckbox() and that unknown marks are rejected
+func TestAddCheckBoxMark(t *testing.T) {
 	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.SetFont("Arial", "", 12)
-	pdf.AliasNbPages("")
+	pdf.SetCompression(false)
 	pdf.AddPage()
-
-	// Write the table of contents. We use aliases instead of the page number
-	// because we don't know which page the section will begin on.
-	numSections := 3
-	for i := 1; i <= numSections; i++ {
-		pdf.Cell(0, 10, fmt.Sprintf("Section %d begins on page {mark %d}", i, i))
-		pdf.Ln(10)
+	opt := gofpdf.NewFormField()
+	opt.Mark = gofpdf.FormMarkCross
+	pdf.AddCheckBox("agree", 10, 10, 5, true, opt)
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
 	}
-
-	// Write the sections. Before we start writing, we use `RegisterAlias` to
-	// ensure that the alias written in the table of contents will be replaced
-	// by the current page number.
-	for i := 1; i <= numSections; i++ {
-		pdf.AddPage()
-		pdf.RegisterAlias(fmt.Sprintf("{mark %d}", i), fmt.Sprintf("%d", pdf.PageNo()))
-		pdf.Write(10, fmt.Sprintf("Section %d, page %d of {nb}", i, pdf.PageNo()))
+	str := buf.String()
+	if !strings.Contains(str, "/CA (8)") {
+		t.Errorf("check box does not show a cross when regenerated")
+	}
+	if !regexp.MustCompile(`q [\d.]+ w 1 J [\d. ]+RG [\d. ]+m [\d. ]+l [\d. ]+m [\d. ]+l S Q`).MatchString(str) {
+		t.Errorf("appearance of the check box does not draw a cross")
+	}
+	pdf = gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	opt.Mark = 99
+	pdf.AddCheckBox("agree", 10, 10, 5, true, opt)
+	if !pdf.Err() {
+		t.Errorf("expecting an error for an unknown mark")
 	}
-
-	fileStr := example.Filename("Fpdf_RegisterAlias")
-	err := pdf.OutputFileAndClose(fileStr)
-	example.Summary(err, fileStr)
-	// Output:
-	// Successfully generated pdf/Fpdf_RegisterAlias.pdf
 }
 
-// ExampleFpdf_RegisterAlias_utf8 demonstrates how to use `RegisterAlias` to
-// create a table of contents. This particular example demonstrates the use of
-// UTF-8 aliases.
-func ExampleFpdf_RegisterAlias_utf8() {
-	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
-	pdf.SetFont("dejavu", "", 12)
-	pdf.AliasNbPages("{entute}")
+// TestColumnTextPageBottom checks that ColumnText() begins a new page when
+// no line fits below the current position and balances the columns there
+func TestColumnTextPageBottom(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A5", "")
+	pdf.SetFont("Times", "", 10)
 	pdf.AddPage()
-
-	// Write the table of contents. We use aliases instead of the page number
-	// because we don't know which page the section will begin on.
-	numSections := 3
-	for i := 1; i <= numSections; i++ {
-		pdf.Cell(0, 10, fmt.Sprintf("Sekcio %d komenciĝas ĉe paĝo {ĉi tiu marko %d}", i, i))
-		pdf.Ln(10)
+	_, top, _, bottom := pdf.GetMargins()
+	_, ht := pdf.GetPageSize()
+	pdf.SetY(ht - bottom - 2)
+	pdf.ColumnText(3, 5, 4.5, "one\ntwo\nthree\nfour\nfive\nsix", "L")
+	if err := pdf.Error(); err != nil {
+		t.Fatal(err)
 	}
+	if pdf.PageNo() != 2 || math.Abs(pdf.GetY()-(top+2*4.5)) > 1e-6 {
+		t.Errorf("columns end on page %d at %.2f, expected page 2 at %.2f", pdf.PageNo(), pdf.GetY(), top+2*4.5)
+	}
+}
 
-	// Write the sections. Before we start writing, we use `RegisterAlias` to
-	// ensure that the alias written in the table of contents will be replaced
-	// by the current page number.
-	for i := 1; i <= numSections; i++ {
-		pdf.AddPage()
-		pdf.RegisterAlias(fmt.Sprintf("{ĉi tiu marko %d}", i), fmt.Sprintf("%d", pdf.PageNo()))
-		pdf.Write(10, fmt.Sprintf("Sekcio %d, paĝo %d de {entute}", i, pdf.PageNo()))
+// TestPourTextFrameWidths checks that text that continues in a frame of
+// another width is wrapped to the width of that frame
+func TestPourTextFrameWidths(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.AddPage()
+	pdf.DefineFrame("narrow", 20, 20, 30, 10, "wide")
+	pdf.DefineFrame("wide", 20, 50, 150, 50, "")
+	rest := pdf.PourText("narrow", 5, "L", []gofpdf.TextRunType{
+		{Str: "one two three four five six seven eight nine ten eleven twelve"}})
+	if err := pdf.Error(); err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) > 0 {
+		t.Errorf("text left over: %v", rest)
+	}
+	if x, y := pdf.GetXY(); x != 20 || y != 55 {
+		t.Errorf("text ends at (%.2f, %.2f), expected one line in the wide frame", x, y)
 	}
-
-	fileStr := example.Filename("Fpdf_RegisterAliasUTF8")
-	err := pdf.OutputFileAndClose(fileStr)
-	example.Summary(err, fileStr)
-	// Output:
-	// Successfully generated pdf/Fpdf_RegisterAliasUTF8.pdf
 }
 
-// ExampleNewGrid demonstrates the generation of graph grids.
-func ExampleNewGrid() {
+// TestMeasureTextState verifies that MeasureText leaves the document
+// unchanged: an unknown font does not set an error and a core font that is
+// only measured is not written to the document
+func TestMeasureTextState(t *testing.T) {
 	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.SetFont("Arial", "", 12)
+	pdf.SetCompression(false)
+	pdf.SetFont("Helvetica", "", 12)
 	pdf.AddPage()
+	if wd := pdf.MeasureText("NoSuchFont", "", 12, "x"); wd != 0 {
+		t.Errorf("width %.2f for an unknown font, expected 0", wd)
+	}
+	if wd := pdf.MeasureText("Courier", "BX", 12, "x"); wd != 0 {
+		t.Errorf("width %.2f for an unknown style, expected 0", wd)
+	}
+	if wd := pdf.MeasureText("Courier", "B", 10, "abc"); math.Abs(wd-18/pdf.GetConversionRatio()) > 1e-9 {
+		t.Errorf("width of Courier-Bold text is %.4f", wd)
+	}
+	if err := pdf.Error(); err != nil {
+		t.Fatalf("measuring set an error: %s", err)
+	}
+	pdf.Cell(0, 10, "Hello")
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "/BaseFont /Courier-Bold") {
+		t.Errorf("measured font written to the document")
+	}
+}
 
-	gr := gofpdf.NewGrid(13, 10, 187, 130)
-	gr.TickmarksExtentX(0, 10, 4)
-	gr.TickmarksExtentY(0, 10, 3)
-	gr.Grid(pdf)
+// TestFontErrorMessage verifies the message of a font error with and
+// without a style
+func TestFontErrorMessage(t *testing.T) {
+	for _, style := range []string{"", "B"} {
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		pdf.SetFont("NoSuchFont", style, 12)
+		want := "undefined font: nosuchfont (the family has not been added)"
+		if style != "" {
+			want = "undefined font: nosuchfont B (the family has not been added)"
+		}
+		if err := pdf.Error(); err == nil || err.Error() != want {
+			t.Errorf("error %q, expected %q", err, want)
+		}
+	}
+}
 
-	gr = gofpdf.NewGrid(13, 154, 187, 128)
-	gr.XLabelRotate = true
-	gr.TickmarksExtentX(0, 1, 12)
-	gr.XDiv = 5
-	gr.TickmarksContainY(0, 1.1)
-	gr.YDiv = 20
-	// Replace X label formatter with month abbreviation
-	gr.XTickStr = func(val float64, precision int) string {
-		return time.Month(math.Mod(val, 12) + 1).String()[0:3]
+// TestFormulaRootIndex checks that the index of a root is laid out and that
+// an unterminated index is reported
+func TestFormulaRootIndex(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Times", "", 12)
+	sqrtWd, sqrtAsc, _ := pdf.FormulaSize(`\sqrt{x}`)
+	wd, asc, _ := pdf.FormulaSize(`\sqrt[3]{x}`)
+	if err := pdf.Error(); err != nil {
+		t.Fatal(err)
 	}
-	gr.Grid(pdf)
-	dot := func(x, y float64) {
-		pdf.Circle(gr.X(x), gr.Y(y), 0.5, "F")
+	if wd < sqrtWd || asc < sqrtAsc {
+		t.Errorf("root with an index is %.2f by %.2f, square root %.2f by %.2f", wd, asc, sqrtWd, sqrtAsc)
 	}
-	pts := []float64{0.39, 0.457, 0.612, 0.84, 0.998, 1.037, 1.015, 0.918, 0.772, 0.659, 0.593, 0.164}
-	for month, val := range pts {
-		dot(float64(month)+0.5, val)
+	if wd, _, _ = pdf.FormulaSize(`\sqrt[n+1]{x}`); wd <= sqrtWd {
+		t.Errorf("wide index does not widen the root")
 	}
-	pdf.SetDrawColor(255, 64, 64)
-	pdf.SetAlpha(0.5, "Normal")
-	pdf.SetLineWidth(1.2)
-	gr.Plot(pdf, 0.5, 11.5, 50, func(x float64) float64 {
-		// http://www.xuru.org/rt/PR.asp
-		return 0.227 * math.Exp(-0.0373*x*x+0.471*x)
-	})
-	pdf.SetAlpha(1.0, "Normal")
-	pdf.SetXY(gr.X(0.5), gr.Y(1.35))
-	pdf.SetFontSize(14)
-	pdf.Write(0, "Solar energy (MWh) per month, 2016")
-	pdf.AddPage()
+	for _, tex := range []string{`\sqrt[3`, `\sqrt[3]`, `\sqrt[{3]{x}`} {
+		pdf.ClearError()
+		pdf.FormulaSize(tex)
+		if !pdf.Err() {
+			t.Errorf("no error for %s", tex)
+		}
+	}
+}
 
-	gr = gofpdf.NewGrid(13, 10, 187, 274)
-	gr.TickmarksContainX(2.3, 3.4)
-	gr.TickmarksContainY(10.4, 56.8)
-	gr.Grid(pdf)
+// TestCodeBlockBottomPadding checks that the bottom padding of a code block
+// that ends at the page break threshold moves to the next page rather than
+// extending into the bottom margin
+func TestCodeBlockBottomPadding(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	cb := gofpdf.NewCodeBlock()
+	cb.LineHt, cb.Padding = 5, 2
+	_, ht := pdf.GetPageSize()
+	_, _, _, bottom := pdf.GetMargins()
+	// The line fits above the threshold but the padding below it does not
+	pdf.SetY(ht - bottom - 8)
+	cb.Render(pdf, pdf.GetX(), 0, "x := 1")
+	if err := pdf.Error(); err != nil {
+		t.Fatal(err)
+	}
+	_, top, _, _ := pdf.GetMargins()
+	if pdf.PageNo() != 2 || math.Abs(pdf.GetY()-(top+2)) > 1e-6 {
+		t.Errorf("block ends on page %d at %.2f, expected page 2 at %.2f", pdf.PageNo(), pdf.GetY(), top+2)
+	}
+}
 
-	fileStr := example.Filename("Fpdf_Grid")
-	err := pdf.OutputFileAndClose(fileStr)
-	example.Summary(err, fileStr)
-	// Output:
-	// Successfully generated pdf/Fpdf_Grid.pdf
+// TestAddValidationData checks that validation data is added to a signed
+// document without invalidating its signature, and that data added earlier
+// is kept
+func TestAddValidationData(t *testing.T) {
+	sig := testSignature(t)
+	dssRe := regexp.MustCompile(`<</Type /DSS /Certs \[([^\]]+)\] /OCSPs \[([^\]]+)\] /CRLs \[(\d+ 0 R)\]>>`)
+	for _, compact := range []bool{false, true} {
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		pdf.SetCompactOutput(compact)
+		pdf.AddPage()
+		pdf.AddSignatureField("approval", 0, 0, 0, 0, nil)
+		var buf bytes.Buffer
+		if err := pdf.Output(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := gofpdf.AddValidationData(buf.Bytes(),
+			gofpdf.ValidationDataType{Certificates: sig.Certificates}); err == nil {
+			t.Errorf("no error for a document that has not been signed")
+		}
+		signed, err := gofpdf.SignPDF(buf.Bytes(), "approval", sig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		doc := signed
+		for _, ocsp := range []string{"first response", "second response"} {
+			vd := gofpdf.ValidationDataType{Certificates: append(sig.Certificates, sig.Certificates...),
+				OCSPs: [][]byte{[]byte(ocsp)}}
+			if ocsp == "first response" {
+				vd.CRLs = [][]byte{[]byte("revocation list")}
+			}
+			if doc, err = gofpdf.AddValidationData(doc, vd); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if !bytes.HasPrefix(doc, signed) {
+			t.Fatalf("adding validation data changed the signed document")
+		}
+		list := dssRe.FindAllSubmatch(doc, -1)
+		if len(list) != 2 {
+			t.Fatalf("%d document security stores found, expected 2", len(list))
+		}
+		// The duplicate certificate is stored once by each update
+		if m := list[1]; len(bytes.Fields(m[1])) != 6 || len(bytes.Fields(m[2])) != 6 ||
+			!bytes.Equal(m[3], list[0][3]) {
+			t.Errorf("data of the first update is not kept: %s", m[0])
+		}
+		if n := bytes.Count(doc, []byte("/Extensions <</ESIC")); n != 2 {
+			t.Errorf("extension declared in %d catalogs, expected 2", n)
+		}
+	}
 }
 
-// ExampleFpdf_SetPageBox demonstrates the use of a page box
-func ExampleFpdf_SetPageBox() {
-	// pdfinfo (from http://www.xpdfreader.com) reports the following for this example:
-	// ~ pdfinfo -box pdf/Fpdf_PageBox.pdf
-	// Producer:       FPDF 1.7
-	// CreationDate:   Sat Jan  1 00:00:00 2000
-	// ModDate:   	   Sat Jan  1 00:00:00 2000
-	// Tagged:         no
-	// Form:           none
-	// Pages:          1
-	// Encrypted:      no
-	// Page size:      493.23 x 739.85 pts (rotated 0 degrees)
-	// MediaBox:           0.00     0.00   595.28   841.89
-	// CropBox:           51.02    51.02   544.25   790.87
-	// BleedBox:          51.02    51.02   544.25   790.87
-	// TrimBox:           51.02    51.02   544.25   790.87
-	// ArtBox:            51.02    51.02   544.25   790.87
-	// File size:      1053 bytes
-	// Optimized:      no
-	// PDF version:    1.3
-	const (
-		wd        = 210
-		ht        = 297
-		fontsize  = 6
-		boxmargin = 3 * fontsize
-	)
-	pdf := gofpdf.New("P", "mm", "A4", "") // 210mm x 297mm
-	pdf.SetPageBox("crop", boxmargin, boxmargin, wd-2*boxmargin, ht-2*boxmargin)
-	pdf.SetFont("Arial", "", pdf.UnitToPointConvert(fontsize))
+// TestIssue0316 addresses issue 316 in which AddUTF8FromBytes modifies its argument
+// utf8bytes resulting in a panic if you generate two PDFs with the "same" font bytes.
+func TestIssue0316(t *testing.T) {
+	pdf := gofpdf.New(gofpdf.OrientationPortrait, "mm", "A4", "")
 	pdf.AddPage()
-	pdf.MoveTo(fontsize, fontsize)
-	pdf.Write(fontsize, "This will be cropped from printed output")
-	pdf.MoveTo(boxmargin+fontsize, boxmargin+fontsize)
-	pdf.Write(fontsize, "This will be displayed in cropped output")
-	fileStr := example.Filename("Fpdf_PageBox")
+	fontBytes, _ := ioutil.ReadFile(example.FontFile("DejaVuSansCondensed.ttf"))
+	ofontBytes := append([]byte{}, fontBytes...)
+	pdf.AddUTF8FontFromBytes("dejavu", "", fontBytes)
+	pdf.SetFont("dejavu", "", 16)
+	pdf.Cell(40, 10, "Hello World!")
+	fileStr := example.Filename("TestIssue0316")
 	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
-	// Output:
-	// Successfully generated pdf/Fpdf_PageBox.pdf
+	pdf.AddPage()
+	if !bytes.Equal(fontBytes, ofontBytes) {
+		t.Fatal("Font data changed during pdf generation")
+	}
 }
 
-// ExampleFpdf_SubWrite demonstrates subscripted and superscripted text
-// Adapted from http://www.fpdf.org/en/script/script61.php
-func ExampleFpdf_SubWrite() {
-
-	const (
-		fontSize = 12
-		halfX    = 105
-	)
-
-	pdf := gofpdf.New("P", "mm", "A4", "") // 210mm x 297mm
+func TestMultiCellUnsupportedChar(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
 	pdf.AddPage()
-	pdf.SetFont("Arial", "", fontSize)
-	_, lineHt := pdf.GetFontSize()
-
-	pdf.Write(lineHt, "Hello World!")
-	pdf.SetX(halfX)
-	pdf.Write(lineHt, "This is standard text.\n")
-	pdf.Ln(lineHt * 2)
-
-	pdf.SubWrite(10, "H", 33, 0, 0, "")
-	pdf.Write(10, "ello World!")
-	pdf.SetX(halfX)
-	pdf.Write(10, "This is text with a capital first letter.\n")
-	pdf.Ln(lineHt * 2)
-
-	pdf.SubWrite(lineHt, "Y", 6, 0, 0, "")
-	pdf.Write(lineHt, "ou can also begin the sentence with a small letter. And word wrap also works if the line is too long, like this one is.")
-	pdf.SetX(halfX)
-	pdf.Write(lineHt, "This is text with a small first letter.\n")
-	pdf.Ln(lineHt * 2)
+	fontBytes, _ := ioutil.ReadFile(example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.AddUTF8FontFromBytes("dejavu", "", fontBytes)
+	pdf.SetFont("dejavu", "", 16)
 
-	pdf.Write(lineHt, "The world has a lot of km")
-	pdf.SubWrite(lineHt, "2", 6, 4, 0, "")
-	pdf.SetX(halfX)
-	pdf.Write(lineHt, "This is text with a superscripted letter.\n")
-	pdf.Ln(lineHt * 2)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Errorf("unexpected panic: %v", r)
+		}
+	}()
 
-	pdf.Write(lineHt, "The world has a lot of H")
-	pdf.SubWrite(lineHt, "2", 6, -3, 0, "")
-	pdf.Write(lineHt, "O")
-	pdf.SetX(halfX)
-	pdf.Write(lineHt, "This is text with a subscripted letter.\n")
+	pdf.MultiCell(0, 5, "😀", "", "", false)
 
-	fileStr := example.Filename("Fpdf_SubWrite")
-	err := pdf.OutputFileAndClose(fileStr)
-	example.Summary(err, fileStr)
-	// Output:
-	// Successfully generated pdf/Fpdf_SubWrite.pdf
+	fileStr := example.Filename("TestMultiCellUnsupportedChar")
+	pdf.OutputFileAndClose(fileStr)
 }
 
-// ExampleFpdf_SetPage demomstrates the SetPage() method, allowing content
-// generation to be deferred until all pages have been added.
-func ExampleFpdf_SetPage() {
-	rnd := rand.New(rand.NewSource(0)) // Make reproducible documents
-	pdf := gofpdf.New("L", "cm", "A4", "")
-	pdf.SetFont("Times", "", 12)
-
-	var time []float64
-	temperaturesFromSensors := make([][]float64, 5)
-	maxs := []float64{25, 41, 89, 62, 11}
-	for i := range temperaturesFromSensors {
-		temperaturesFromSensors[i] = make([]float64, 0)
-	}
+// ExampleFpdf_SetTextRenderingMode demonstrates embedding files in PDFs,
+// at the top-level.
+func ExampleFpdf_SetAttachments() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
 
-	for i := 0.0; i < 100; i += 0.5 {
-		time = append(time, i)
-		for j, sensor := range temperaturesFromSensors {
-			dataValue := rnd.Float64() * maxs[j]
-			sensor = append(sensor, dataValue)
-			temperaturesFromSensors[j] = sensor
-		}
-	}
-	var graphs []gofpdf.GridType
-	var pageNums []int
-	xMax := time[len(time)-1]
-	for i := range temperaturesFromSensors {
-		//Create a new page and graph for each sensor we want to graph.
-		pdf.AddPage()
-		pdf.Ln(1)
-		//Custom label per sensor
-		pdf.WriteAligned(0, 0, "Temperature Sensor "+strconv.Itoa(i+1)+" (C) vs Time (min)", "C")
-		pdf.Ln(0.5)
-		graph := gofpdf.NewGrid(pdf.GetX(), pdf.GetY(), 20, 10)
-		graph.TickmarksContainX(0, xMax)
-		//Custom Y axis
-		graph.TickmarksContainY(0, maxs[i])
-		graph.Grid(pdf)
-		//Save references and locations.
-		graphs = append(graphs, graph)
-		pageNums = append(pageNums, pdf.PageNo())
+	// Global attachments
+	file, err := ioutil.ReadFile("grid.go")
+	if err != nil {
+		pdf.SetError(err)
 	}
-	// For each X, graph the Y in each sensor.
-	for i, currTime := range time {
-		for j, sensor := range temperaturesFromSensors {
-			pdf.SetPage(pageNums[j])
-			graph := graphs[j]
-			temperature := sensor[i]
-			pdf.Circle(graph.X(currTime), graph.Y(temperature), 0.04, "D")
-		}
+	a1 := gofpdf.Attachment{Content: file, Filename: "grid.go"}
+	file, err = ioutil.ReadFile("LICENSE")
+	if err != nil {
+		pdf.SetError(err)
 	}
+	a2 := gofpdf.Attachment{Content: file, Filename: "License"}
+	pdf.SetAttachments([]gofpdf.Attachment{a1, a2})
 
-	fileStr := example.Filename("Fpdf_SetPage")
-	err := pdf.OutputFileAndClose(fileStr)
+	fileStr := example.Filename("Fpdf_EmbeddedFiles")
+	err = pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_SetPage.pdf
+	// Successfully generated pdf/Fpdf_EmbeddedFiles.pdf
 }
 
-// ExampleFpdf_SetFillColor demonstrates how graphic attributes are properly
-// assigned within multiple transformations. See issue #234.
-func ExampleFpdf_SetFillColor() {
+func ExampleFpdf_AddAttachmentAnnotation() {
 	pdf := gofpdf.New("P", "mm", "A4", "")
-
+	pdf.SetFont("Arial", "", 12)
 	pdf.AddPage()
-	pdf.SetFont("Arial", "", 8)
 
-	draw := func(trX, trY float64) {
-		pdf.TransformBegin()
-		pdf.TransformTranslateX(trX)
-		pdf.TransformTranslateY(trY)
-		pdf.SetLineJoinStyle("round")
-		pdf.SetLineWidth(0.5)
-		pdf.SetDrawColor(128, 64, 0)
-		pdf.SetFillColor(255, 127, 0)
-		pdf.SetAlpha(0.5, "Normal")
-		pdf.SetDashPattern([]float64{5, 10}, 0)
-		pdf.Rect(0, 0, 40, 40, "FD")
-		pdf.SetFontSize(12)
-		pdf.SetXY(5, 5)
-		pdf.Write(0, "Test")
-		pdf.TransformEnd()
+	// Per page attachment
+	file, err := ioutil.ReadFile("grid.go")
+	if err != nil {
+		pdf.SetError(err)
 	}
+	a := gofpdf.Attachment{Content: file, Filename: "grid.go", Description: "Some amazing code !"}
 
-	draw(5, 5)
-	draw(50, 50)
+	pdf.SetXY(5, 10)
+	pdf.Rect(2, 10, 50, 15, "D")
+	pdf.AddAttachmentAnnotation(&a, 2, 10, 50, 15)
+	pdf.Cell(50, 15, "A first link")
 
-	fileStr := example.Filename("Fpdf_SetFillColor")
-	err := pdf.OutputFileAndClose(fileStr)
+	pdf.SetXY(5, 80)
+	pdf.Rect(2, 80, 50, 15, "D")
+	pdf.AddAttachmentAnnotation(&a, 2, 80, 50, 15)
+	pdf.Cell(50, 15, "A second link (no copy)")
+
+	fileStr := example.Filename("Fpdf_FileAnnotations")
+	err = pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_SetFillColor.pdf
+	// Successfully generated pdf/Fpdf_FileAnnotations.pdf
 }
 
-// ExampleFpdf_TransformRotate demonstrates how to rotate text within a header
-// to make a watermark that appears on each page.
-func ExampleFpdf_TransformRotate() {
-
-	loremStr := lorem() + "\n\n"
+// ExampleFpdf_CellSpans demonstrates text with inline changes of font,
+// style and color. CellSpans() wraps the runs within a framed cell and
+// WriteSpans() continues them in flowing mode.
+func ExampleFpdf_CellSpans() {
 	pdf := gofpdf.New("P", "mm", "A4", "")
-	margin := 25.0
-	pdf.SetMargins(margin, margin, margin)
-
-	fontHt := 13.0
-	lineHt := pdf.PointToUnitConvert(fontHt)
-	markFontHt := 50.0
-	markLineHt := pdf.PointToUnitConvert(markFontHt)
-	markY := (297.0 - markLineHt) / 2.0
-	ctrX := 210.0 / 2.0
-	ctrY := 297.0 / 2.0
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.AddPage()
+	red := gofpdf.RGBType{R: 192, G: 0, B: 0}
+	blue := gofpdf.RGBType{R: 0, G: 64, B: 160}
+	runs := []gofpdf.TextRunType{
+		{Str: "Styled text "},
+		{Str: "in bold, ", FontStyle: "B"},
+		{Str: "in italic, ", FontStyle: "I"},
+		{Str: "in red ", Clr: red},
+		{Str: "and in a larger Times font ", FontFamily: "Times", FontSize: 16, Clr: blue},
+		{Str: "is wrapped as one paragraph. " + loremList()[0] + "\n"},
+		{Str: "A line feed begins a new line.", FontStyle: "U"},
+	}
+	pdf.SetFillColor(240, 240, 220)
+	pdf.CellSpans(120, 0, runs, "1", "J", true)
+	pdf.Ln(10)
+	pdf.WriteSpans(6, runs)
+	fileStr := example.Filename("Fpdf_CellSpans")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_CellSpans.pdf
+}
 
+// ExampleFpdf_SetColumns demonstrates a newsletter laid out in columns.
+// Text flows from column to column and on to the columns of the next page,
+// below the page header, without an accept page break function. The closing
+// section is balanced so that its two columns end at the same height.
+func ExampleFpdf_SetColumns() {
+	pdf := gofpdf.New("P", "mm", "A5", "")
 	pdf.SetHeaderFunc(func() {
-		pdf.SetFont("Arial", "B", markFontHt)
-		pdf.SetTextColor(206, 216, 232)
-		pdf.SetXY(margin, markY)
-		pdf.TransformBegin()
-		pdf.TransformRotate(45, ctrX, ctrY)
-		pdf.CellFormat(0, markLineHt, "W A T E R M A R K   D E M O", "", 0, "C", false, 0, "")
-		pdf.TransformEnd()
-		pdf.SetXY(margin, margin)
+		pdf.SetFont("Helvetica", "B", 12)
+		pdf.CellFormat(0, 8, "The Gofpdf Gazette", "B", 1, "C", false, 0, "")
+		pdf.Ln(4)
 	})
-
+	pdf.SetFont("Times", "", 10)
 	pdf.AddPage()
-	pdf.SetFont("Arial", "", 8)
-	for j := 0; j < 25; j++ {
-		pdf.MultiCell(0, lineHt, loremStr, "", "L", false)
+	pdf.SetColumns(3, 5)
+	for j := 0; j < 14; j++ {
+		pdf.SetFont("Times", "B", 11)
+		pdf.MultiCell(0, 5, fmt.Sprintf("Story %d", j+1), "", "L", false)
+		pdf.SetFont("Times", "", 10)
+		pdf.MultiCell(0, 4.5, loremList()[j%4], "", "J", false)
+		pdf.Ln(2)
+		if j == 1 {
+			// The next story begins a new column
+			pdf.ColumnBreak()
+		}
 	}
-
-	fileStr := example.Filename("Fpdf_RotateText")
+	pdf.SetColumns(1, 0)
+	pdf.SetDrawColor(128, 128, 128)
+	pdf.Line(pdf.GetX(), pdf.GetY(), 138, pdf.GetY())
+	pdf.Ln(3)
+	pdf.SetColumns(2, 6)
+	pdf.BalanceColumns(func() {
+		pdf.MultiCell(0, 4.5, strings.Join(loremList(), " "), "", "J", false)
+	})
+	pdf.SetColumns(1, 0)
+	fmt.Printf("Columns end on page %d at %.1f mm\n", pdf.PageNo(), pdf.GetY())
+	fileStr := example.Filename("Fpdf_SetColumns")
 	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_RotateText.pdf
+	// Columns end on page 2 at 76.5 mm
+	// Successfully generated pdf/Fpdf_SetColumns.pdf
 }
 
-// ExampleFpdf_AddUTF8Font demonstrates how use the font
-// with utf-8 mode
-func ExampleFpdf_AddUTF8Font() {
-	var fileStr string
-	var txtStr []byte
-	var err error
+// ExampleFpdf_AddTOCPage demonstrates a table of contents generated from
+// the bookmarks of a document. The table is requested after the title page
+// and laid out when the document is closed; the page numbers in the footers
+// are those of the final document.
+func ExampleFpdf_AddTOCPage() {
+	pdf := gofpdf.New("P", "mm", "A5", "")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(0, 10, fmt.Sprintf("Page %d", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	pdf.SetFont("Helvetica", "B", 24)
+	pdf.AddPage()
+	pdf.CellFormat(0, 60, "Lorem Ipsum", "", 1, "C", false, 0, "")
+	pdf.AddTOCPage("Contents",
+		gofpdf.TOCLevelType{FontStyle: "B", FontSize: 12, SpaceBefore: 3},
+		gofpdf.TOCLevelType{FontSize: 10, Indent: 6})
+	for chapter := 1; chapter <= 4; chapter++ {
+		pdf.AddPage()
+		pdf.SetFont("Helvetica", "B", 16)
+		titleStr := fmt.Sprintf("Chapter %d", chapter)
+		pdf.Bookmark(titleStr, 0, -1)
+		pdf.CellFormat(0, 10, titleStr, "", 1, "L", false, 0, "")
+		for section := 1; section <= 3; section++ {
+			pdf.SetFont("Helvetica", "B", 12)
+			titleStr = fmt.Sprintf("Section %d.%d", chapter, section)
+			pdf.Bookmark(titleStr, 1, -1)
+			pdf.CellFormat(0, 8, titleStr, "", 1, "L", false, 0, "")
+			pdf.SetFont("Times", "", 10)
+			pdf.MultiCell(0, 4.5, loremList()[section], "", "J", false)
+			// Sections below this level are left out of the table
+			pdf.Bookmark("Notes", 2, -1)
+			pdf.Ln(2)
+		}
+	}
+	pdf.SetFont("Helvetica", "", 10)
+	fileStr := example.Filename("Fpdf_AddTOCPage")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_AddTOCPage.pdf
+}
 
+// ExampleFpdf_SetGradientInterpolation demonstrates gradients blended in the
+// CIE L*a*b* color space. Between distant colors such as blue and yellow,
+// device RGB blending passes through a dull gray, whereas the ramp in Lab
+// keeps an even progression of lightness.
+func ExampleFpdf_SetGradientInterpolation() {
 	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetGradientInterpolation(gofpdf.GradientInterpolationLab)
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.AddPage()
+	pdf.Cell(0, 10, "Gradients interpolated in Lab")
+	pdf.LinearGradient(20, 30, 170, 40, 0, 0, 255, 255, 255, 0, 0, 0, 1, 0)
+	pdf.LinearGradient(20, 80, 170, 40, 255, 0, 0, 0, 160, 80, 0, 0, 1, 0)
+	pdf.RadialGradient(65, 130, 80, 80, 255, 255, 255, 0, 64, 160, 0.5, 0.5, 0.5, 0.5, 0.5)
+	fileStr := example.Filename("Fpdf_SetGradientInterpolation")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetGradientInterpolation.pdf
+}
+
+// ExampleFpdf_SetNamedDest demonstrates links to named destinations. The
+// links of the contents are placed before the chapters they lead to have
+// been written, and the last link opens another document.
+func ExampleFpdf_SetNamedDest() {
+	pdf := gofpdf.New("P", "mm", "A5", "")
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.AddPage()
+	pdf.SetTextColor(0, 0, 192)
+	for j := 1; j <= 3; j++ {
+		pdf.CellFormat(0, 8, fmt.Sprintf("Chapter %d", j), "", 0, "L", false, 0, "")
+		pdf.LinkNamed(pdf.GetX()-128, pdf.GetY(), 128, 8, fmt.Sprintf("chapter%d", j))
+		pdf.Ln(8)
+	}
+	pdf.CellFormat(0, 8, "Table of contents example, in a separate document", "", 0, "L", false, 0, "")
+	pdf.LinkRemote(pdf.GetX()-128, pdf.GetY(), 128, 8, "Fpdf_AddTOCPage.pdf", "")
+	pdf.SetTextColor(0, 0, 0)
+	for j := 1; j <= 3; j++ {
+		pdf.AddPage()
+		pdf.SetNamedDest(fmt.Sprintf("chapter%d", j))
+		pdf.SetFont("Helvetica", "B", 16)
+		pdf.CellFormat(0, 10, fmt.Sprintf("Chapter %d", j), "", 1, "L", false, 0, "")
+		pdf.SetFont("Times", "", 11)
+		pdf.MultiCell(0, 5, loremList()[j], "", "J", false)
+	}
+	fileStr := example.Filename("Fpdf_SetNamedDest")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetNamedDest.pdf
+}
 
+// ExampleFpdf_FlowText demonstrates paragraphs of styled text with a
+// first-line indent and space between them. Near the bottom of each page,
+// paragraphs are broken only where at least two lines stay on either side
+// of the break.
+func ExampleFpdf_FlowText() {
+	pdf := gofpdf.New("P", "mm", "A5", "")
+	pdf.SetFont("Times", "", 11)
 	pdf.AddPage()
+	para := gofpdf.NewParagraph()
+	para.Align = "J"
+	para.Indent = 6
+	para.SpaceAfter = 2
+	var runs []gofpdf.TextRunType
+	for j := 0; j < 12; j++ {
+		runs = append(runs,
+			gofpdf.TextRunType{Str: fmt.Sprintf("Paragraph %d. ", j+1), FontStyle: "B",
+				Clr: gofpdf.RGBType{R: 0, G: 64, B: 128}},
+			gofpdf.TextRunType{Str: loremList()[j%4] + "\n"})
+	}
+	pdf.FlowText(0, runs, para)
+	fileStr := example.Filename("Fpdf_FlowText")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_FlowText.pdf
+}
 
-	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
-	pdf.AddUTF8Font("dejavu", "B", example.FontFile("DejaVuSansCondensed-Bold.ttf"))
-	pdf.AddUTF8Font("dejavu", "I", example.FontFile("DejaVuSansCondensed-Oblique.ttf"))
-	pdf.AddUTF8Font("dejavu", "BI", example.FontFile("DejaVuSansCondensed-BoldOblique.ttf"))
+// testSignature returns a signature with an ECDSA key and a self-signed
+// certificate generated for testing
+func testSignature(t *testing.T) gofpdf.DigitalSignatureType {
+	rnd := rand.New(rand.NewSource(1))
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rnd)
+	if err == nil {
+		tmpl := &x509.Certificate{SerialNumber: big.NewInt(1),
+			Subject:   pkix.Name{CommonName: "Test Signatory", Organization: []string{"gofpdf"}},
+			NotBefore: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			NotAfter:  time.Date(2040, 1, 1, 0, 0, 0, 0, time.UTC),
+			KeyUsage:  x509.KeyUsageDigitalSignature}
+		var der []byte
+		der, err = x509.CreateCertificate(rnd, tmpl, tmpl, key.Public(), key)
+		if err == nil {
+			var cert *x509.Certificate
+			cert, err = x509.ParseCertificate(der)
+			if err == nil {
+				return gofpdf.DigitalSignatureType{Signer: key, Certificates: []*x509.Certificate{cert},
+					Name: "Test Signatory", Reason: "Approval", Location: "Springfield"}
+			}
+		}
+	}
+	if t != nil {
+		t.Fatal(err)
+	}
+	return gofpdf.DigitalSignatureType{}
+}
 
-	fileStr = example.Filename("Fpdf_AddUTF8Font")
-	txtStr, err = ioutil.ReadFile(example.TextFile("utf-8test.txt"))
+// ExampleFpdf_AddSignatureField demonstrates a contract with two signature
+// fields, one visible and one invisible, that are signed one after another.
+// Each signature is appended as an incremental update. The certificate is
+// self-signed, so readers report the signatures as valid but of unknown
+// identity.
+func ExampleFpdf_AddSignatureField() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.AddPage()
+	pdf.Cell(0, 10, "Service agreement")
+	pdf.Ln(12)
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.MultiCell(0, 5, lorem(), "", "J", false)
+	sb := gofpdf.NewSignatureBlock()
+	sb.Name = "Test Signatory"
+	sb.Reason = "Approval"
+	sb.Date = time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC)
+	pdf.AddSignatureField("client", 20, 200, 120, 40, &sb)
+	pdf.AddSignatureField("witness", 0, 0, 0, 0, nil)
+	var buf bytes.Buffer
+	err := pdf.Output(&buf)
+	doc := buf.Bytes()
+	sig := testSignature(nil)
+	for _, nameStr := range []string{"client", "witness"} {
+		if err == nil {
+			doc, err = gofpdf.SignPDF(doc, nameStr, sig)
+		}
+	}
+	fileStr := example.Filename("Fpdf_AddSignatureField")
 	if err == nil {
+		err = ioutil.WriteFile(fileStr, doc, 0644)
+	}
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_AddSignatureField.pdf
+}
 
-		pdf.SetFont("dejavu", "B", 17)
-		pdf.MultiCell(100, 8, "Text in different languages :", "", "C", false)
-		pdf.SetFont("dejavu", "", 14)
-		pdf.MultiCell(100, 5, string(txtStr), "", "C", false)
-		pdf.Ln(15)
+// ExampleFpdf_AddTextField_format demonstrates text fields whose values are
+// formatted and checked by the reader as they are entered, with the presets
+// of Acrobat for amounts, percentages, dates and phone numbers.
+func ExampleFpdf_AddTextField_format() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.Cell(0, 10, "Expense claim")
+	pdf.SetFont("Helvetica", "", 11)
+	opt := gofpdf.NewFormField()
+	opt.ClrFill = gofpdf.RGBType{R: 235, G: 241, B: 255}
+	field := func(y float64, labelStr, nameStr string, ft gofpdf.FormFormatType) {
+		pdf.Text(20, y+5, labelStr)
+		opt.Format = ft
+		pdf.AddTextField(nameStr, 70, y, 60, 7, "", opt)
+	}
+	opt.Required = true
+	field(30, "Date (dd/mm/yyyy)", "date",
+		gofpdf.FormFormatType{Kind: gofpdf.FormFormatDate, Mask: "dd/mm/yyyy"})
+	field(42, "Amount", "amount", gofpdf.FormFormatType{Kind: gofpdf.FormFormatNumber,
+		Decimals: 2, Currency: "$", NegativeRed: true, MinSet: true, Min: 0.01, MaxSet: true, Max: 5000})
+	opt.Required = false
+	field(54, "Business share", "share", gofpdf.FormFormatType{Kind: gofpdf.FormFormatPercent,
+		MinSet: true, Min: 0, MaxSet: true, Max: 1})
+	field(66, "Phone", "phone", gofpdf.FormFormatType{Kind: gofpdf.FormFormatSpecial,
+		Special: gofpdf.FormSpecialPhone})
+	fileStr := example.Filename("Fpdf_AddTextField_format")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_AddTextField_format.pdf
+}
 
-		txtStr, err = ioutil.ReadFile(example.TextFile("utf-8test2.txt"))
-		if err == nil {
+// ExampleFpdf_MultiCell_justifyUTF8 demonstrates justified text printed with
+// a UTF-8 font. Since word spacing does not apply to such fonts, the words of
+// each line are spaced with adjustments of their own. Underlining spans the
+// whole line.
+func ExampleFpdf_MultiCell_justifyUTF8() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8Font("dejavu", "", example.FontFile("DejaVuSansCondensed.ttf"))
+	pdf.AddPage()
+	txtStr := "Größere Straßen führen über die Brücke, während schmale Gassen " +
+		"sich zwischen den Häusern der Altstadt verlieren. Ελληνικά και русский " +
+		"текст are justified just like the Latin words around them, and the " +
+		"last line of each paragraph keeps its natural spacing."
+	pdf.SetFont("dejavu", "", 12)
+	pdf.MultiCell(100, 6, txtStr, "1", "J", false)
+	pdf.Ln(6)
+	pdf.SetFont("dejavu", "U", 12)
+	pdf.MultiCell(100, 6, txtStr, "", "J", false)
+	fileStr := example.Filename("Fpdf_MultiCell_justifyUTF8")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_MultiCell_justifyUTF8.pdf
+}
 
-			pdf.SetFont("dejavu", "BI", 17)
-			pdf.MultiCell(100, 8, "Greek text with alignStr = \"J\":", "", "C", false)
-			pdf.SetFont("dejavu", "I", 14)
-			pdf.MultiCell(100, 5, string(txtStr), "", "J", false)
-			err = pdf.OutputFileAndClose(fileStr)
+// ExampleFpdf_SetFormValues demonstrates a round trip of form data: the
+// fields of a form are filled in with values read from an XFDF file, and
+// their values are written in FDF for other form tools.
+func ExampleFpdf_SetFormValues() {
+	const xfdfStr = `<?xml version="1.0" encoding="UTF-8"?>
+<xfdf xmlns="http://ns.adobe.com/xfdf/" xml:space="preserve">
+  <fields>
+    <field name="name"><value>Jordan Smith</value></field>
+    <field name="plan"><value>Annual</value></field>
+    <field name="newsletter"><value>Yes</value></field>
+  </fields>
+</xfdf>`
+	values, err := gofpdf.ParseXFDF(strings.NewReader(xfdfStr))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFormValues(values)
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.AddPage()
+	opt := gofpdf.NewFormField()
+	pdf.Text(20, 25, "Name")
+	pdf.AddTextField("name", 50, 19, 80, 8, "", opt)
+	pdf.Text(20, 40, "Plan")
+	pdf.AddComboBox("plan", 50, 34, 40, 8, []string{"Monthly", "Annual"}, "Monthly", opt)
+	pdf.Text(20, 55, "Newsletter")
+	pdf.AddCheckBox("newsletter", 50, 51, 5, false, opt)
+	fileStr := example.Filename("Fpdf_SetFormValues")
+	err = pdf.OutputFileAndClose(fileStr)
+	if err == nil {
+		var fdf bytes.Buffer
+		err = pdf.OutputFDF(&fdf, "Fpdf_SetFormValues.pdf")
+		lines := strings.Split(fdf.String(), "\n")
+		fmt.Println(strings.Join(lines[4:7], "\n"))
+	}
+	example.Summary(err, fileStr)
+	// Output:
+	// <</T (name) /V (Jordan Smith)>>
+	// <</T (plan) /V (Annual)>>
+	// <</T (newsletter) /V /Yes>>]>>>>
+	// Successfully generated pdf/Fpdf_SetFormValues.pdf
+}
 
+// ExampleFpdf_ElementManifest demonstrates the export of the positions of
+// named elements, here for an e-signature platform that places its fields
+// over the signature blocks of a contract.
+func ExampleFpdf_ElementManifest() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.AddPage()
+	pdf.MultiCell(0, 5, lorem(), "", "", false)
+	pdf.Ln(5)
+	tbl := pdf.NewTable([]gofpdf.TableColumnType{{Header: "Item"}, {Header: "Price", W: 30, Align: "R"}})
+	tbl.Element = "prices"
+	tbl.Row("Consulting", "1,200.00")
+	tbl.Row("Travel", "310.50")
+	tbl.End()
+	for j, nameStr := range []string{"Customer", "Contractor"} {
+		sb := gofpdf.NewSignatureBlock()
+		sb.Name = nameStr
+		sb.Element = "signature-" + strings.ToLower(nameStr)
+		pdf.SignatureBlock(10+float64(j)*95, 200, 90, 40, sb)
+	}
+	fileStr := example.Filename("Fpdf_ElementManifest")
+	err := pdf.OutputFileAndClose(fileStr)
+	if err == nil {
+		for _, e := range pdf.ElementManifest() {
+			fmt.Printf("%s (%s) on page %d at %.0f, %.0f\n", e.Name, e.Kind, e.Page, e.Rect[0], e.Rect[1])
 		}
 	}
 	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_AddUTF8Font.pdf
+	// prices (table) on page 1 at 28, 670
+	// signature-customer (signature) on page 1 at 35, 182
+	// signature-contractor (signature) on page 1 at 304, 182
+	// Successfully generated pdf/Fpdf_ElementManifest.pdf
 }
 
-// ExampleUTF8CutFont demonstrates how generate a TrueType font subset.
-func ExampleUTF8CutFont() {
-	var pdfFileStr, fullFontFileStr, subFontFileStr string
-	var subFont, fullFont []byte
-	var err error
-
-	pdfFileStr = example.Filename("Fpdf_UTF8CutFont")
-	fullFontFileStr = example.FontFile("calligra.ttf")
-	fullFont, err = ioutil.ReadFile(fullFontFileStr)
+// ExampleFpdf_GenerateSeparationPreviews demonstrates the preview pages
+// that show each ink of a page with a spot color by itself.
+func ExampleFpdf_GenerateSeparationPreviews() {
+	pdf := gofpdf.New("L", "mm", "A5", "")
+	pdf.AddSpotColor("PANTONE 145 CVC", 0, 42, 100, 25)
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "B", 28)
+	pdf.SetFillSpotColor("PANTONE 145 CVC", 100)
+	pdf.Rect(20, 20, 170, 40, "F")
+	pdf.SetTextColor(255, 255, 255)
+	pdf.Text(30, 45, "Spot color")
+	pdf.SetFillColor(40, 90, 160)
+	pdf.Circle(50, 100, 25, "F")
+	pdf.SetTextColor(0, 0, 0)
+	pdf.Text(90, 105, "Process colors")
+	pdf.GenerateSeparationPreviews(gofpdf.SeparationPreviewType{})
+	fileStr := example.Filename("Fpdf_GenerateSeparationPreviews")
+	err := pdf.OutputFileAndClose(fileStr)
 	if err == nil {
-		subFontFileStr = "calligra_abcde.ttf"
-		subFont = gofpdf.UTF8CutFont(fullFont, "abcde")
-		err = ioutil.WriteFile(subFontFileStr, subFont, 0600)
-		if err == nil {
-			y := 24.0
-			pdf := gofpdf.New("P", "mm", "A4", "")
-			fontHt := 17.0
-			lineHt := pdf.PointConvert(fontHt)
-			write := func(format string, args ...interface{}) {
-				pdf.SetXY(24.0, y)
-				pdf.Cell(200.0, lineHt, fmt.Sprintf(format, args...))
-				y += lineHt
-			}
-			writeSize := func(fileStr string) {
-				var info os.FileInfo
-				var err error
-				info, err = os.Stat(fileStr)
-				if err == nil {
-					write("%6d: size of %s", info.Size(), fileStr)
-				}
-			}
+		fmt.Printf("Pages: %d\n", pdf.PageCount())
+	}
+	example.Summary(err, fileStr)
+	// Output:
+	// Pages: 5
+	// Successfully generated pdf/Fpdf_GenerateSeparationPreviews.pdf
+}
+
+// ExampleFpdf_SetCompactOutput demonstrates the compact output of PDF 1.5,
+// which packs the dictionaries of a text-heavy document into compressed
+// object streams.
+func ExampleFpdf_SetCompactOutput() {
+	generate := func(compact bool) *gofpdf.Fpdf {
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		pdf.SetCompression(true)
+		pdf.SetCompactOutput(compact)
+		pdf.SetFont("Times", "", 12)
+		for j := 1; j <= 20; j++ {
 			pdf.AddPage()
-			pdf.AddUTF8Font("calligra", "", subFontFileStr)
-			pdf.SetFont("calligra", "", fontHt)
-			write("cabbed")
-			write("vwxyz")
-			pdf.SetFont("courier", "", fontHt)
-			writeSize(fullFontFileStr)
-			writeSize(subFontFileStr)
-			err = pdf.OutputFileAndClose(pdfFileStr)
-			os.Remove(subFontFileStr)
+			pdf.Bookmark(fmt.Sprintf("Section %d", j), 0, -1)
+			pdf.MultiCell(0, 5, lorem(), "", "", false)
+			pdf.LinkString(pdf.GetX(), pdf.GetY(), 40, 5, "https://github.com/headlands-org/gofpdf")
+		}
+		return pdf
+	}
+	var classic bytes.Buffer
+	fileStr := example.Filename("Fpdf_SetCompactOutput")
+	err := generate(false).Output(&classic)
+	if err == nil {
+		err = generate(true).OutputFileAndClose(fileStr)
+	}
+	if err == nil {
+		var fi os.FileInfo
+		if fi, err = os.Stat(fileStr); err == nil {
+			fmt.Printf("Smaller by more than a fifth: %v\n", fi.Size()*5 < int64(classic.Len())*4)
 		}
 	}
-	example.Summary(err, pdfFileStr)
+	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_UTF8CutFont.pdf
+	// Smaller by more than a fifth: true
+	// Successfully generated pdf/Fpdf_SetCompactOutput.pdf
 }
 
-func ExampleFpdf_RoundedRect() {
-	const (
-		wd     = 40.0
-		hgap   = 10.0
-		radius = 10.0
-		ht     = 60.0
-		vgap   = 10.0
-	)
-	corner := func(b1, b2, b3, b4 bool) (cstr string) {
-		if b1 {
-			cstr = "1"
-		}
-		if b2 {
-			cstr += "2"
-		}
-		if b3 {
-			cstr += "3"
-		}
-		if b4 {
-			cstr += "4"
+// ExampleFpdf_ImageOptions_maxDPI demonstrates the reduction of images that
+// have a much higher resolution than their printed size requires. Each image
+// is placed at full resolution in one document and limited to 150 dpi in
+// another, and the sizes of the documents are compared.
+func ExampleFpdf_ImageOptions_maxDPI() {
+	place := func(opt gofpdf.ImageOptions) *gofpdf.Fpdf {
+		pdf := gofpdf.New("P", "mm", "A4", "")
+		pdf.AddPage()
+		pdf.ImageOptions(example.ImageFile("logo_gofpdf.jpg"), 10, 10, 60, 0, false, opt, 0, "")
+		pdf.ImageOptions(example.ImageFile("golang-gopher.png"), 10, 50, 40, 0, false, opt, 0, "")
+		pdf.ImageOptions(example.ImageFile("golang-gopher.png"), 60, 50, 20, 0, false, opt, 0, "")
+		return pdf
+	}
+	var full bytes.Buffer
+	fileStr := example.Filename("Fpdf_ImageOptions_maxDPI")
+	err := place(gofpdf.ImageOptions{}).Output(&full)
+	if err == nil {
+		err = place(gofpdf.ImageOptions{MaxDPI: 150, JPEGQuality: 80}).OutputFileAndClose(fileStr)
+	}
+	if err == nil {
+		var fi os.FileInfo
+		if fi, err = os.Stat(fileStr); err == nil {
+			fmt.Printf("Reduced to less than a fifth: %v\n", fi.Size()*5 < int64(full.Len()))
 		}
-		return
 	}
-	pdf := gofpdf.New("P", "mm", "A4", "") // 210 x 297
+	example.Summary(err, fileStr)
+	// Output:
+	// Reduced to less than a fifth: true
+	// Successfully generated pdf/Fpdf_ImageOptions_maxDPI.pdf
+}
+
+// ExampleFpdf_SetHalftone demonstrates the halftone screens and transfer
+// function of a screen printing separation. The screens take effect when the
+// document is printed; viewers show the tints as usual.
+func ExampleFpdf_SetHalftone() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
 	pdf.AddPage()
-	pdf.SetLineWidth(0.5)
-	y := vgap
-	r := 40
-	g := 30
-	b := 20
-	for row := 0; row < 4; row++ {
-		x := hgap
-		for col := 0; col < 4; col++ {
-			pdf.SetFillColor(r, g, b)
-			pdf.RoundedRect(x, y, wd, ht, radius, corner(row&1 == 1, row&2 == 2, col&1 == 1, col&2 == 2), "FD")
-			r += 8
-			g += 10
-			b += 12
-			x += wd + hgap
+	pdf.SetFont("Helvetica", "", 10)
+	screens := []gofpdf.HalftoneType{
+		{Frequency: 45, Angle: 22.5, SpotFunction: "Round"},
+		{Frequency: 55, Angle: 45, SpotFunction: "Ellipse"},
+		{Frequency: 65, Angle: 75, SpotFunction: "Line"},
+	}
+	for j, ht := range screens {
+		y := 20 + float64(j)*30
+		pdf.SetHalftone(&ht)
+		pdf.Text(20, y, fmt.Sprintf("%s dots at %.0f lpi and %.1f degrees",
+			ht.SpotFunction, ht.Frequency, ht.Angle))
+		for k := 1; k <= 5; k++ {
+			pdf.SetFillColor(255-k*45, 255-k*45, 255-k*45)
+			pdf.Rect(20+float64(k-1)*32, y+3, 30, 20, "F")
 		}
-		y += ht + vgap
 	}
-	pdf.AddPage()
-	pdf.RoundedRectExt(10, 20, 40, 80, 4., 0., 20, 0., "FD")
-
-	fileStr := example.Filename("Fpdf_RoundedRect")
+	// Compensate for a dot gain of about 15 percent in the midtones
+	pdf.SetHalftone(nil)
+	pdf.SetTransferFunction([]gofpdf.PointType{{X: 0, Y: 0}, {X: 0.5, Y: 0.38}, {X: 1, Y: 1}})
+	pdf.Text(20, 110, "Default screen with a dot gain compensation curve")
+	for k := 1; k <= 5; k++ {
+		pdf.SetFillColor(255-k*45, 255-k*45, 255-k*45)
+		pdf.Rect(20+float64(k-1)*32, 113, 30, 20, "F")
+	}
+	pdf.SetTransferFunction(nil)
+	fileStr := example.Filename("Fpdf_SetHalftone")
 	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_RoundedRect.pdf
+	// Successfully generated pdf/Fpdf_SetHalftone.pdf
 }
 
-// ExampleFpdf_SetUnderlineThickness demonstrates how to adjust the text
-// underline thickness.
-func ExampleFpdf_SetUnderlineThickness() {
-	pdf := gofpdf.New("P", "mm", "A4", "") // 210mm x 297mm
+// ExampleFpdf_SetFillLabColor demonstrates device-independent colors: a
+// row of swatches in the CIE L*a*b* color space at increasing lightness, and
+// a row in calibrated RGB next to the same components in device RGB.
+func ExampleFpdf_SetFillLabColor() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
 	pdf.AddPage()
-	pdf.SetFont("Arial", "U", 12)
-
-	pdf.SetUnderlineThickness(0.5)
-	pdf.CellFormat(0, 10, "Thin underline", "", 1, "", false, 0, "")
-
-	pdf.SetUnderlineThickness(1)
-	pdf.CellFormat(0, 10, "Normal underline", "", 1, "", false, 0, "")
-
-	pdf.SetUnderlineThickness(2)
-	pdf.CellFormat(0, 10, "Thicker underline", "", 1, "", false, 0, "")
-
-	fileStr := example.Filename("Fpdf_UnderlineThickness")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.Text(20, 20, "Lab colors with a = 40, b = -60 at increasing lightness")
+	for j := 0; j < 6; j++ {
+		pdf.SetFillLabColor(float64(20+j*15), 40, -60)
+		pdf.Rect(20+float64(j)*28, 25, 25, 25, "F")
+	}
+	pdf.SetTextLabColor(45, 65, 50)
+	pdf.Text(20, 62, "Text in a Lab red")
+	pdf.SetTextColor(0, 0, 0)
+	pdf.Text(20, 75, "Calibrated RGB (upper) and device RGB (lower)")
+	clrs := [][3]int{{230, 60, 40}, {250, 190, 20}, {40, 160, 90}, {30, 110, 200}, {140, 60, 170}}
+	pdf.SetDrawCalRGBColor(0, 0, 0)
+	for j, clr := range clrs {
+		x := 20 + float64(j)*28
+		pdf.SetFillCalRGBColor(clr[0], clr[1], clr[2])
+		pdf.Rect(x, 80, 25, 20, "FD")
+		pdf.SetFillColor(clr[0], clr[1], clr[2])
+		pdf.Rect(x, 100, 25, 20, "FD")
+	}
+	fileStr := example.Filename("Fpdf_SetFillLabColor")
 	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_UnderlineThickness.pdf
+	// Successfully generated pdf/Fpdf_SetFillLabColor.pdf
 }
 
-// ExampleFpdf_Cell_strikeout demonstrates striked-out text
-func ExampleFpdf_Cell_strikeout() {
-
-	pdf := gofpdf.New("P", "mm", "A4", "") // 210mm x 297mm
+// ExampleFpdf_SVGWrite demonstrates the rendering of an SVG image with
+// gradients, groups with transforms, symbols, arcs, dash patterns and a style
+// sheet, at two sizes.
+func ExampleFpdf_SVGWrite() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
 	pdf.AddPage()
-
-	for fontSize := 4; fontSize < 40; fontSize += 10 {
-		pdf.SetFont("Arial", "S", float64(fontSize))
-		pdf.SetXY(0, float64(fontSize))
-		pdf.Cell(40, 10, "Hello World")
+	img, err := gofpdf.SVGFileParse(example.ImageFile("badge.svg"))
+	if err != nil {
+		pdf.SetError(err)
 	}
+	pdf.SetXY(20, 20)
+	pdf.SVGWrite(&img, 170/img.Wd)
+	pdf.SetXY(20, 150)
+	pdf.SVGWrite(&img, 25.4/96)
+	fileStr := example.Filename("Fpdf_SVGWrite")
+	err = pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SVGWrite.pdf
+}
 
-	fileStr := example.Filename("Fpdf_Cell_strikeout")
+// ExampleFpdf_RecordMacro demonstrates a checkmark and a bullet that are
+// recorded once with a size of one unit and played many times at different
+// positions and sizes.
+func ExampleFpdf_RecordMacro() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.RecordMacro("check", func() {
+		pdf.SetDrawColor(0, 140, 60)
+		pdf.SetLineWidth(0.15)
+		pdf.SetLineCapStyle("round")
+		pdf.SetLineJoinStyle("round")
+		pdf.Polygon([]gofpdf.PointType{{X: 0.1, Y: 0.55}, {X: 0.4, Y: 0.85}, {X: 0.9, Y: 0.15}}, "D")
+	})
+	pdf.RecordMacro("bullet", func() {
+		pdf.SetFillColor(0, 70, 170)
+		pdf.Circle(0.5, 0.5, 0.3, "F")
+	})
+	pdf.SetFont("Helvetica", "", 12)
+	items := []string{"Record the macro once", "Play it at any position",
+		"Scale it to the font size", "Keep the document small"}
+	y := 20.0
+	for j, item := range items {
+		pdf.PlayMacro("bullet", 15, y+1.5, 3)
+		pdf.Text(22, y+4, item)
+		if j%2 == 0 {
+			pdf.PlayMacro("check", 100, y, 5)
+		}
+		y += 8
+	}
+	for j := 0; j < 8; j++ {
+		size := float64(4 + 3*j)
+		pdf.PlayMacro("check", 15+float64(j*j)*2.5, 70+20-size, size)
+	}
+	fileStr := example.Filename("Fpdf_RecordMacro")
 	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_Cell_strikeout.pdf
+	// Successfully generated pdf/Fpdf_RecordMacro.pdf
 }
 
-// ExampleFpdf_SetTextRenderingMode demonstrates rendering modes in PDFs.
-func ExampleFpdf_SetTextRenderingMode() {
-
-	pdf := gofpdf.New("P", "mm", "A4", "") // 210mm x 297mm
+// ExampleFpdf_SetFillNone demonstrates shapes and cells that are drawn with
+// a fixed style string, "FD", and are outlined only or filled only after
+// SetFillNone() or SetDrawNone(). Setting a color turns filling or stroking
+// on again.
+func ExampleFpdf_SetFillNone() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
 	pdf.AddPage()
-	fontSz := float64(16)
-	lineSz := pdf.PointToUnitConvert(fontSz)
-	pdf.SetFont("Times", "", fontSz)
-	pdf.Write(lineSz, "This document demonstrates various modes of text rendering. Search for \"Mode 3\" "+
-		"to locate text that has been rendered invisibly. This selection can be copied "+
-		"into the clipboard as usual and is useful for overlaying onto non-textual elements such "+
-		"as images to make them searchable.\n\n")
-	fontSz = float64(125)
-	lineSz = pdf.PointToUnitConvert(fontSz)
-	pdf.SetFontSize(fontSz)
-	pdf.SetTextColor(170, 170, 190)
-	pdf.SetDrawColor(50, 60, 90)
-
-	write := func(mode int) {
-		pdf.SetTextRenderingMode(mode)
-		pdf.CellFormat(210, lineSz, fmt.Sprintf("Mode %d", mode), "", 1, "", false, 0, "")
-	}
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.SetLineWidth(0.8)
+	shapes := func(y float64, label string) {
+		pdf.SetXY(10, y)
+		pdf.CellFormat(50, 10, label, "1", 0, "L", true, 0, "")
+		pdf.Rect(70, y, 30, 20, "FD")
+		pdf.Circle(125, y+10, 10, "FD")
+		pdf.Polygon([]gofpdf.PointType{{X: 150, Y: y + 20}, {X: 165, Y: y}, {X: 180, Y: y + 20}}, "FD")
+	}
+	pdf.SetDrawColor(32, 64, 128)
+	pdf.SetFillColor(200, 220, 255)
+	shapes(20, "Filled and outlined")
+	pdf.SetFillNone()
+	shapes(50, "Outlined only")
+	pdf.SetFillColor(255, 220, 200)
+	pdf.SetDrawNone()
+	shapes(80, "Filled only")
+	pdf.SetDrawColor(128, 64, 32)
+	shapes(110, "Both again")
+	fileStr := example.Filename("Fpdf_SetFillNone")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetFillNone.pdf
+}
 
-	for mode := 0; mode < 4; mode++ {
-		write(mode)
+// ExampleFpdf_SetLineHeight demonstrates line heights that are set once for
+// the document rather than passed to each call. A negative height passed to
+// MultiCell() and Write() selects the line height, which is in proportion to
+// the font size unless an absolute height is set. The HTML renderer uses the
+// line height as well, and elements may set their own with the line-height
+// style.
+func ExampleFpdf_SetLineHeight() {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	txtStr := "The quick brown fox jumps over the lazy dog. " +
+		"Pack my box with five dozen liquor jugs. How vexingly quick daft zebras jump!"
+	for _, factor := range []float64{1, 1.5, 2} {
+		pdf.SetLineHeightFactor(factor)
+		pdf.SetFont("Helvetica", "B", 11)
+		pdf.Write(-1, fmt.Sprintf("Line height factor %.1f\n", factor))
+		pdf.SetFont("Times", "", 12)
+		pdf.MultiCell(120, -1, txtStr, "", "L", false)
+		pdf.Ln(4)
 	}
-	write(0)
-
-	fileStr := example.Filename("Fpdf_TextRenderingMode")
+	pdf.SetLineHeight(8)
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.Write(-1, "Absolute line height of 8 mm\n")
+	pdf.SetFont("Times", "", 12)
+	pdf.MultiCell(120, -1, txtStr, "", "L", false)
+	pdf.Ln(4)
+	pdf.SetLineHeight(0)
+	pdf.SetLineHeightFactor(1.25)
+	html := pdf.HTMLNew()
+	html.Write(`<p>This paragraph uses the line height of the document.</p>` +
+		`<p style="line-height: 2">This paragraph sets a line height of twice ` +
+		`its font size, which applies to each of its lines as it wraps across ` +
+		`the width of the page.</p><p style="line-height: 5mm">And this one ` +
+		`sets an absolute line height of five millimeters.</p>`)
+	fileStr := example.Filename("Fpdf_SetLineHeight")
 	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_TextRenderingMode.pdf
+	// Successfully generated pdf/Fpdf_SetLineHeight.pdf
 }
 
-// TestIssue0316 addresses issue 316 in which AddUTF8FromBytes modifies its argument
-// utf8bytes resulting in a panic if you generate two PDFs with the "same" font bytes.
-func TestIssue0316(t *testing.T) {
-	pdf := gofpdf.New(gofpdf.OrientationPortrait, "mm", "A4", "")
+// ExampleFpdf_SetOverflowReport demonstrates the report of the overflows of
+// a fixed layout, a shipping label whose fields have fixed sizes. A test of
+// the layout can fail when the report is not empty.
+func ExampleFpdf_SetOverflowReport() {
+	pdf := gofpdf.New("P", "mm", "A6", "")
+	pdf.SetAutoPageBreak(false, 10)
+	pdf.SetOverflowReport(true)
 	pdf.AddPage()
-	fontBytes, _ := ioutil.ReadFile(example.FontFile("DejaVuSansCondensed.ttf"))
-	ofontBytes := append([]byte{}, fontBytes...)
-	pdf.AddUTF8FontFromBytes("dejavu", "", fontBytes)
-	pdf.SetFont("dejavu", "", 16)
-	pdf.Cell(40, 10, "Hello World!")
-	fileStr := example.Filename("TestIssue0316")
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(40, 10, "Ship to", "1", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.CellFormat(60, 8, "Bartholomew Featherstonehaugh-Smythe", "1", 1, "L", false, 0, "")
+	pdf.SetY(128)
+	pdf.MultiCell(60, 6, "Fragile\nThis side up\nKeep dry", "", "L", false)
+	pdf.ShapeTextEllipse(80, 40, 14, 8, 5, "Priority parcel, handle with great care", "C")
+	for _, o := range pdf.OverflowReport() {
+		fmt.Printf("page %d, %s %s at (%.0f, %.0f): %.1f\n", o.Page, o.Element, o.Kind, o.X, o.Y, o.Amount)
+	}
+	fileStr := example.Filename("Fpdf_SetOverflowReport")
 	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
+	// Output:
+	// page 1, cell width at (10, 20): 20.4
+	// page 1, multicell margin at (10, 134): 1.5
+	// page 1, multicell margin at (10, 140): 7.5
+	// page 1, shape clip at (66, 32): 30.0
+	// Successfully generated pdf/Fpdf_SetOverflowReport.pdf
+}
+
+// ExampleFpdf_SetKerning demonstrates the kerning of a UTF-8 font. The
+// headline is printed without and with kerning, with a frame of the width
+// that GetStringWidth() reports for it, followed by a kerned paragraph.
+func ExampleFpdf_SetKerning() {
+	pdf := gofpdf.New("P", "mm", "A4", example.FontDir())
+	pdf.AddUTF8Font("dejavu", "", "DejaVuSansCondensed.ttf")
 	pdf.AddPage()
-	if !bytes.Equal(fontBytes, ofontBytes) {
-		t.Fatal("Font data changed during pdf generation")
+	headStr := "AVAILABLE: Yachts To Travel"
+	for _, kerning := range []bool{false, true} {
+		pdf.SetKerning(kerning)
+		pdf.SetFont("dejavu", "", 28)
+		pdf.CellFormat(pdf.GetStringWidth(headStr), 14, headStr, "1", 1, "L", false, 0, "")
+		pdf.Ln(4)
 	}
+	pdf.SetFont("dejavu", "", 12)
+	pdf.MultiCell(120, 6, "Wave after wave, Tom's yacht VAYA sailed toward the "+
+		"LAVA coast. Kerning adjusts pairs such as AV, Wa, To and Ya.", "", "L", false)
+	fileStr := example.Filename("Fpdf_SetKerning")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetKerning.pdf
 }
 
-func TestMultiCellUnsupportedChar(t *testing.T) {
-	pdf := gofpdf.New("P", "mm", "A4", "")
+// ExampleFpdf_ScrubMetadata demonstrates a document that does not reveal
+// when or with what software it was generated. Its title is still written
+// to the document information.
+func ExampleFpdf_ScrubMetadata() {
+	pdf := gofpdf.New("P", "mm", "A5", "")
+	pdf.ScrubMetadata()
+	pdf.SetTitle("Appointment confirmation", true)
 	pdf.AddPage()
-	fontBytes, _ := ioutil.ReadFile(example.FontFile("DejaVuSansCondensed.ttf"))
-	pdf.AddUTF8FontFromBytes("dejavu", "", fontBytes)
-	pdf.SetFont("dejavu", "", 16)
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.MultiCell(0, 6, "Your appointment has been confirmed. This document "+
+		"carries no producer or dates in its document information.", "", "L", false)
+	fileStr := example.Filename("Fpdf_ScrubMetadata")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_ScrubMetadata.pdf
+}
 
-	defer func() {
-		if r := recover(); r != nil {
-			t.Errorf("unexpected panic: %v", r)
+// ExampleFpdf_SetTextShaping demonstrates text that is shaped with the
+// OpenType layout tables of its font: ligatures replace letter pairs in
+// Latin text, combining accents are positioned on their letters and the tone
+// marks of Lao are stacked. The same lines are printed without shaping for
+// comparison.
+func ExampleFpdf_SetTextShaping() {
+	pdf := gofpdf.New("P", "mm", "A4", example.FontDir())
+	pdf.AddUTF8Font("dejavu", "", "DejaVuSansCondensed.ttf")
+	pdf.AddPage()
+	lines := []string{"Official offer for fluffy waffles", "Vie\u0302\u0323t Nam, s\u0323\u0301c", "ສະບາຍດີ ກ່ຳ"}
+	for _, shaping := range []bool{false, true} {
+		pdf.SetTextShaping(shaping)
+		pdf.SetFont("dejavu", "", 10)
+		pdf.Cell(0, 8, fmt.Sprintf("Shaping: %v", shaping))
+		pdf.Ln(10)
+		pdf.SetFont("dejavu", "", 20)
+		for _, line := range lines {
+			pdf.CellFormat(0, 12, line, "", 1, "L", false, 0, "")
 		}
-	}()
-
-	pdf.MultiCell(0, 5, "😀", "", "", false)
-
-	fileStr := example.Filename("TestMultiCellUnsupportedChar")
-	pdf.OutputFileAndClose(fileStr)
+		pdf.Ln(6)
+	}
+	fileStr := example.Filename("Fpdf_SetTextShaping")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetTextShaping.pdf
 }
 
-// ExampleFpdf_SetTextRenderingMode demonstrates embedding files in PDFs,
-// at the top-level.
-func ExampleFpdf_SetAttachments() {
-	pdf := gofpdf.New("P", "mm", "A4", "")
-
-	// Global attachments
-	file, err := ioutil.ReadFile("grid.go")
-	if err != nil {
-		pdf.SetError(err)
-	}
-	a1 := gofpdf.Attachment{Content: file, Filename: "grid.go"}
-	file, err = ioutil.ReadFile("LICENSE")
-	if err != nil {
-		pdf.SetError(err)
+// ExampleFpdf_SetASCIIStreams demonstrates a document that consists of
+// printable ASCII text only, so that it can be sent in the body of a
+// message. With compression off, the content of its pages can be read with a
+// text editor after decoding the base-85 data.
+func ExampleFpdf_SetASCIIStreams() {
+	pdf := gofpdf.New("P", "mm", "A5", "")
+	pdf.SetASCIIStreams(gofpdf.ASCIIStreams85)
+	pdf.SetTitle("Meter reading – März", true)
+	pdf.AddPage()
+	pdf.SetFont("Courier", "B", 14)
+	pdf.Cell(0, 10, "Meter reading report")
+	pdf.Ln(12)
+	pdf.SetFont("Courier", "", 11)
+	for j, reading := range []float64{1043.2, 1067.8, 1102.5} {
+		pdf.CellFormat(40, 7, fmt.Sprintf("Unit %d", j+1), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(40, 7, fmt.Sprintf("%.1f kWh", reading), "1", 1, "R", false, 0, "")
 	}
-	a2 := gofpdf.Attachment{Content: file, Filename: "License"}
-	pdf.SetAttachments([]gofpdf.Attachment{a1, a2})
+	pdf.Image(example.ImageFile("logo.png"), 10, 50, 30, 0, false, "", 0, "")
+	fileStr := example.Filename("Fpdf_SetASCIIStreams")
+	err := pdf.OutputFileAndClose(fileStr)
+	example.Summary(err, fileStr)
+	// Output:
+	// Successfully generated pdf/Fpdf_SetASCIIStreams.pdf
+}
 
-	fileStr := example.Filename("Fpdf_EmbeddedFiles")
-	err = pdf.OutputFileAndClose(fileStr)
+// ExampleFpdf_SetPagePieceInfo demonstrates the attachment of private JSON
+// data to the document and to its pages, so that tooling that processes the
+// document later can tell which record each page presents.
+func ExampleFpdf_SetPagePieceInfo() {
+	pdf := gofpdf.New("P", "mm", "A5", "")
+	pdf.SetFont("Helvetica", "", 14)
+	pdf.SetPieceInfo("com.example.catalog", []byte(`{"edition": "2024-06", "records": 3}`))
+	for _, rec := range []struct {
+		id   int
+		name string
+	}{{1041, "Folding chair"}, {1077, "Garden table"}, {1102, "Parasol"}} {
+		pdf.AddPage()
+		pdf.Cell(0, 10, rec.name)
+		pdf.SetPagePieceInfo("com.example.catalog", []byte(fmt.Sprintf(`{"record": %d}`, rec.id)))
+	}
+	fileStr := example.Filename("Fpdf_SetPagePieceInfo")
+	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_EmbeddedFiles.pdf
+	// Successfully generated pdf/Fpdf_SetPagePieceInfo.pdf
 }
 
-func ExampleFpdf_AddAttachmentAnnotation() {
+// ExampleFpdf_SetPortfolio demonstrates a portfolio of invoices. Each
+// invoice is a small PDF document that is attached with the customer, amount
+// and due date of the invoice, which viewers that support portfolios show in
+// columns of the list of files, sorted by due date. The page of the document
+// is the cover sheet shown by other viewers.
+func ExampleFpdf_SetPortfolio() {
 	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.SetFont("Arial", "", 12)
+	pdf.SetFont("Helvetica", "", 14)
 	pdf.AddPage()
-
-	// Per page attachment
-	file, err := ioutil.ReadFile("grid.go")
-	if err != nil {
-		pdf.SetError(err)
+	pdf.Cell(0, 10, "Invoices of March 2024")
+	invoiceList := []struct {
+		numStr, customerStr string
+		amount              float64
+		due                 time.Time
+	}{
+		{"2024-031", "Alder Logistics", 1250.00, time.Date(2024, 4, 15, 0, 0, 0, 0, time.UTC)},
+		{"2024-032", "Birchwood Bakery", 86.40, time.Date(2024, 4, 2, 0, 0, 0, 0, time.UTC)},
+		{"2024-033", "Cedar Falls Hospital", 9730.25, time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC)},
+	}
+	var list []gofpdf.Attachment
+	for _, inv := range invoiceList {
+		doc := gofpdf.New("P", "mm", "A4", "")
+		doc.SetFont("Helvetica", "", 14)
+		doc.AddPage()
+		doc.Cell(0, 10, fmt.Sprintf("Invoice %s for %s: %.2f EUR", inv.numStr, inv.customerStr, inv.amount))
+		var buf bytes.Buffer
+		if err := doc.Output(&buf); err != nil {
+			pdf.SetError(err)
+		}
+		list = append(list, gofpdf.Attachment{
+			Content:     buf.Bytes(),
+			Filename:    "invoice-" + inv.numStr + ".pdf",
+			Description: "Invoice " + inv.numStr,
+			ModTime:     time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC),
+			PortfolioValues: map[string]interface{}{
+				"customer": inv.customerStr,
+				"amount":   inv.amount,
+				"due":      inv.due,
+			},
+		})
 	}
-	a := gofpdf.Attachment{Content: file, Filename: "grid.go", Description: "Some amazing code !"}
-
-	pdf.SetXY(5, 10)
-	pdf.Rect(2, 10, 50, 15, "D")
-	pdf.AddAttachmentAnnotation(&a, 2, 10, 50, 15)
-	pdf.Cell(50, 15, "A first link")
-
-	pdf.SetXY(5, 80)
-	pdf.Rect(2, 80, 50, 15, "D")
-	pdf.AddAttachmentAnnotation(&a, 2, 80, 50, 15)
-	pdf.Cell(50, 15, "A second link (no copy)")
-
-	fileStr := example.Filename("Fpdf_FileAnnotations")
-	err = pdf.OutputFileAndClose(fileStr)
+	pdf.SetAttachments(list)
+	pdf.SetPortfolio(gofpdf.PortfolioType{
+		Fields: []gofpdf.PortfolioFieldType{
+			{Key: "file", Name: "File", Kind: gofpdf.PortfolioFilename},
+			{Key: "customer", Name: "Customer", Kind: gofpdf.PortfolioText},
+			{Key: "amount", Name: "Amount", Kind: gofpdf.PortfolioNumber},
+			{Key: "due", Name: "Due", Kind: gofpdf.PortfolioDate},
+			{Key: "size", Name: "Size", Kind: gofpdf.PortfolioSize, Hidden: true},
+		},
+		SortKey: "due",
+	})
+	fileStr := example.Filename("Fpdf_SetPortfolio")
+	err := pdf.OutputFileAndClose(fileStr)
 	example.Summary(err, fileStr)
 	// Output:
-	// Successfully generated pdf/Fpdf_FileAnnotations.pdf
+	// Successfully generated pdf/Fpdf_SetPortfolio.pdf
 }
 
 func ExampleFpdf_SetModificationDate() {
@@ -3688,6 +8497,28 @@ func TestSplitTextWithEmoji(t *testing.T) {
 	}
 }
 
+// TestSplitTextBreaks verifies that SplitTextBreaks reports the same lines as
+// SplitText together with their widths.
+func TestSplitTextBreaks(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "", 12)
+	txt := "The quick brown fox jumps over the lazy dog.\nPack my box with five dozen liquor jugs."
+	lines := pdf.SplitText(txt, 40)
+	breaks := pdf.SplitTextBreaks(txt, 40)
+	if len(lines) != len(breaks) {
+		t.Fatalf("expected %d breaks, got %d", len(lines), len(breaks))
+	}
+	for j, br := range breaks {
+		line := txt[br.Start:br.End]
+		if line != lines[j] {
+			t.Errorf("line %d: expected %q, got %q", j, lines[j], line)
+		}
+		if wd := pdf.GetStringWidth(line); math.Abs(wd-br.Width) > 1e-9 {
+			t.Errorf("line %d: expected width %f, got %f", j, wd, br.Width)
+		}
+	}
+}
+
 // TestMultiCellWithEmojiWrapping tests that MultiCell wraps text without
 // breaking emoji sequences across lines
 func TestMultiCellWithEmojiWrapping(t *testing.T) {
@@ -193,8 +193,7 @@ func (f *Fpdf) TransformSkew(angleX, angleY, x, y float64) {
 // methods such as TransformRotate() and TransformMirrorVertical() instead.
 func (f *Fpdf) Transform(tm TransformMatrix) {
 	if f.transformNest > 0 {
-		f.outf("%.5f %.5f %.5f %.5f %.5f %.5f cm",
-			tm.A, tm.B, tm.C, tm.D, tm.E, tm.F)
+		f.outf("%s %.5f %.5f cm", fixedf("%.5f %.5f %.5f %.5f", tm.A, tm.B, tm.C, tm.D), tm.E, tm.F)
 	} else if f.err == nil {
 		f.err = fmt.Errorf("transformation context is not active")
 	}
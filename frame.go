@@ -0,0 +1,257 @@
+package gofpdf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TextRunType is a run of text printed in one style by PourText(). A run
+// may contain several words, spaces and line feeds; a line feed begins a new
+// paragraph.
+type TextRunType struct {
+	Str string
+	// Font family, style and size in points; an empty family or zero size
+	// selects the family or size in effect when PourText() is called
+	FontFamily, FontStyle string
+	FontSize              float64
+	// Color of the text
+	Clr RGBType
+}
+
+// frameType is a rectangular region that text is poured into
+type frameType struct {
+	x, y, w, h float64
+	next       string
+}
+
+// DefineFrame defines a rectangular region named nameStr whose upper left
+// corner is at (x, y) and whose size is w by h. Frames are used by PourText()
+// to flow text through a chain of regions, for example the columns of a
+// newsletter. nextStr names the frame that text continues in when this one
+// is full, or is empty if the chain ends here. The next frame does not need to
+// be defined yet. Defining a frame with an existing name replaces it.
+func (f *Fpdf) DefineFrame(nameStr string, x, y, w, h float64, nextStr string) {
+	if f.frames == nil {
+		f.frames = make(map[string]frameType)
+	}
+	f.frames[nameStr] = frameType{x: x, y: y, w: w, h: h, next: nextStr}
+}
+
+// frameFragType is the part of a word that is printed with one run's style
+type frameFragType struct {
+	str string
+	run int
+}
+
+// frameWordType is a word, possibly made up of fragments in different
+// styles, together with whether it begins a new paragraph
+type frameWordType struct {
+	frags   []frameFragType
+	newPara bool
+}
+
+// PourText prints the styled runs of text in the frame named frameStr,
+// beginning at its top on the current page, and continues in the frames that
+// follow it in the chain set up by DefineFrame(). Each frame is filled line
+// by line, with words wrapped to its width. When text moves on to a frame
+// that has already been filled on the current page, as happens when a chain
+// of columns leads back to the first column, a new page is added.
+//
+// lineHt is the distance between lines; zero selects the line height of the
+// document for the largest font on each line (see SetLineHeight()).
+// alignStr is "L" (the default), "C", "R" or "J". Upon return the font and
+// text color in effect beforehand are restored. If the chain of frames ends
+// before all of the text is printed, the remainder is returned so that it
+// can be poured elsewhere.
+func (f *Fpdf) PourText(frameStr string, lineHt float64, alignStr string, runs []TextRunType) (rest []TextRunType) {
+	if f.err != nil {
+		return runs
+	}
+	fr, ok := f.frames[frameStr]
+	if !ok {
+		f.err = fmt.Errorf("frame %s has not been defined", frameStr)
+		return runs
+	}
+	if f.page == 0 {
+		f.AddPage()
+	}
+	if f.overflowReport {
+		defer func() {
+			var b strings.Builder
+			for _, run := range rest {
+				b.WriteString(run.Str)
+			}
+			if n := len([]rune(b.String())); n > 0 {
+				f.overflow(OverflowClip, OverflowElementFrame, fr.x, fr.y, float64(n), b.String())
+			}
+		}()
+	}
+	familyStr, styleStr, ptSize := f.fontFamily, f.fontStyleStr(), f.fontSizePt
+	r, g, b := f.GetTextColor()
+	runs = f.runDefaults(runs)
+	words := runWords(runs)
+	used := map[string]bool{frameStr: true}
+	y := fr.y
+	idx := 0
+	// lines holds the lines that remain of the current paragraph, laid out
+	// for the width of the current frame, and end the end of its words
+	var lines []paraLineType
+	var end int
+	for idx < len(words) && f.err == nil {
+		if len(lines) == 0 {
+			end = idx + 1
+			for end < len(words) && !words[end].newPara {
+				end++
+			}
+			lines = f.paraLines(runs, words[idx:end], fr.w, ParagraphType{LineHt: lineHt})
+			for j := range lines {
+				lines[j].start += idx
+				lines[j].end += idx
+			}
+		}
+		line := lines[0]
+		if y+line.ht > fr.y+fr.h+1e-9 {
+			if y == fr.y && fr.h < line.ht {
+				f.err = fmt.Errorf("frame %s is too small for a line of text", frameStr)
+				break
+			}
+			// Continue in the next frame
+			if fr.next == "" {
+				break
+			}
+			frameStr = fr.next
+			if fr, ok = f.frames[frameStr]; !ok {
+				f.err = fmt.Errorf("frame %s has not been defined", frameStr)
+				break
+			}
+			if used[frameStr] {
+				f.AddPage()
+				used = make(map[string]bool)
+			}
+			used[frameStr] = true
+			y = fr.y
+			// The rest of the paragraph is laid out anew for the width of
+			// the frame
+			lines = nil
+			continue
+		}
+		f.y = y
+		f.paraPutLine(fr.x, fr.w, runs, words, line, alignStr, false, line.end == end, 0)
+		y = f.y
+		idx = line.end
+		lines = lines[1:]
+	}
+	if familyStr != "" {
+		f.SetFont(familyStr, styleStr, ptSize)
+	}
+	f.SetTextColor(r, g, b)
+	f.SetXY(fr.x, y)
+
+	// Reassemble the words that were not printed
+	for k := idx; k < len(words); k++ {
+		sep := " "
+		if words[k].newPara {
+			sep = "\n"
+		}
+		if k == idx {
+			sep = ""
+		}
+		if len(words[k].frags) == 0 && len(rest) > 0 {
+			// Empty paragraph
+			rest[len(rest)-1].Str += sep
+		}
+		for j, frag := range words[k].frags {
+			str := frag.str
+			if j == 0 {
+				str = sep + str
+			}
+			if n := len(rest); n > 0 && rest[n-1].Str != "" && sameRun(runs[frag.run], rest[n-1]) {
+				rest[n-1].Str += str
+			} else {
+				run := runs[frag.run]
+				run.Str = str
+				rest = append(rest, run)
+			}
+		}
+	}
+	return
+}
+
+// sameRun reports whether runs a and b have the same style
+func sameRun(a, b TextRunType) bool {
+	return a.FontFamily == b.FontFamily && a.FontStyle == b.FontStyle &&
+		a.FontSize == b.FontSize && a.Clr == b.Clr
+}
+
+// runDefaults returns a copy of runs in which an empty font family or zero
+// size is replaced by the current one
+func (f *Fpdf) runDefaults(runs []TextRunType) []TextRunType {
+	runs = append([]TextRunType(nil), runs...)
+	for j := range runs {
+		if runs[j].FontFamily == "" {
+			runs[j].FontFamily = f.fontFamily
+		}
+		if runs[j].FontSize == 0 {
+			runs[j].FontSize = f.fontSizePt
+		}
+	}
+	return runs
+}
+
+// runWords breaks runs into words. Spaces and tabs separate words; a line
+// feed begins a new paragraph, and an empty paragraph is a word without
+// fragments.
+func runWords(runs []TextRunType) (words []frameWordType) {
+	var word frameWordType
+	newPara := false
+	endWord := func() {
+		if len(word.frags) > 0 || newPara {
+			word.newPara = newPara
+			words = append(words, word)
+			newPara = false
+		}
+		word = frameWordType{}
+	}
+	for j, run := range runs {
+		for _, ch := range strings.Replace(run.Str, "\r", "", -1) {
+			switch ch {
+			case ' ', '\t':
+				endWord()
+			case '\n':
+				endWord()
+				newPara = true
+			default:
+				n := len(word.frags)
+				if n == 0 || word.frags[n-1].run != j {
+					word.frags = append(word.frags, frameFragType{run: j})
+					n++
+				}
+				word.frags[n-1].str += string(ch)
+			}
+		}
+	}
+	endWord()
+	return
+}
+
+// runFragWidth returns the width of the fragment frag of runs
+func (f *Fpdf) runFragWidth(runs []TextRunType, frag frameFragType) float64 {
+	run := runs[frag.run]
+	return f.MeasureText(run.FontFamily, run.FontStyle, run.FontSize, frag.str)
+}
+
+// runWordWidth returns the width of the word w of runs
+func (f *Fpdf) runWordWidth(runs []TextRunType, w frameWordType) (wd float64) {
+	for _, frag := range w.frags {
+		wd += f.runFragWidth(runs, frag)
+	}
+	return
+}
+
+// runSpaceWidth returns the width of a space following the word w of runs
+func (f *Fpdf) runSpaceWidth(runs []TextRunType, w frameWordType) float64 {
+	if len(w.frags) == 0 {
+		return 0
+	}
+	return f.runFragWidth(runs, frameFragType{" ", w.frags[len(w.frags)-1].run})
+}
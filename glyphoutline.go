@@ -0,0 +1,464 @@
+package gofpdf
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// GlyphSegmentType is a segment of a glyph contour. Coordinates are in glyph
+// space: the origin is at the glyph's position on the baseline, the y axis
+// points up, and one unit is a thousandth of the font size.
+type GlyphSegmentType struct {
+	// True for a straight line from P0 to P1; otherwise the segment is a cubic
+	// Bézier curve from P0 to P1 with control points C0 and C1
+	Line bool
+	// Start and end points
+	P0, P1 PointType
+	// Control points of a curve
+	C0, C1 PointType
+}
+
+// GlyphOutlineType is the outline of the glyph that a font uses for a rune, as
+// returned by GetGlyphOutline().
+type GlyphOutlineType struct {
+	// Horizontal distance to the origin of the next glyph, in the units of
+	// GlyphSegmentType
+	Width float64
+	// Closed contours, each a sequence of segments in which a segment starts
+	// where the previous one ends. Following the TrueType convention, the
+	// outside of the glyph is to the left of the direction of travel, so the
+	// glyph is filled correctly with the nonzero winding number rule.
+	Contours [][]GlyphSegmentType
+}
+
+// GetGlyphOutline returns the outline of the glyph for rune r in the UTF-8
+// font identified by familyStr and styleStr, with the quadratic curves of
+// TrueType converted to cubic Bézier curves. This permits effects that the
+// font machinery does not provide, such as letter-shaped clipping paths or
+// single glyph logos. Use DrawGlyphOutline() to draw the outline on the page
+// and ClipGlyphOutline() to clip to it.
+//
+// ok is false if the font has no glyph for r. The outline of a glyph with no
+// visible shape, such as that of a space, has no contours. An error is set if
+// the font has not been added with AddUTF8Font() or a related method, if it
+// has PostScript (CFF) outlines, or if its glyph data cannot be read.
+func (f *Fpdf) GetGlyphOutline(familyStr, styleStr string, r rune) (outline GlyphOutlineType, ok bool) {
+	if f.err != nil {
+		return
+	}
+	familyStr = strings.ToLower(fontFamilyEscape(familyStr))
+	styleStr = f.fontStyleKey(familyStr, strings.ToUpper(styleStr))
+	if f.err != nil {
+		return
+	}
+	font, found := f.fonts[familyStr+styleStr]
+	if !found || font.utf8File == nil || font.utf8File.fileReader == nil {
+		f.fontError(familyStr, styleStr, "glyph outlines are available only for added UTF-8 fonts")
+		return
+	}
+	return f.glyphOutline(familyStr+styleStr, font, r)
+}
+
+// glyphOutline returns the outline of the glyph for r in the UTF-8 font
+// registered under key
+func (f *Fpdf) glyphOutline(key string, font fontDefType, r rune) (outline GlyphOutlineType, ok bool) {
+	if font.utf8File.isCFF {
+		familyStr, styleStr := splitFontKey(key)
+		f.fontError(familyStr, styleStr, "glyph outlines are not available for fonts with PostScript (CFF) outlines")
+		return
+	}
+	rdr := newGlyphReader(font.utf8File)
+	gid, mapped := font.utf8File.charSymbolDictionary[int(r)]
+	if !mapped || rdr == nil {
+		return
+	}
+	outline.Width = float64(font.Cw[int(r)])
+	outline.Contours, ok = rdr.contours(gid, 0)
+	if !ok {
+		familyStr, styleStr := splitFontKey(key)
+		f.err = fmt.Errorf("font %s: the glyph data for U+%04X cannot be read",
+			strings.TrimSpace(familyStr+" "+styleStr), r)
+		outline.Contours = nil
+	}
+	return
+}
+
+// DrawGlyphOutline draws the glyph outline g as a path with its origin at
+// (x, y), the left end of the baseline, for a font size of sizeUnit in the
+// units passed to New(). The path is painted with DrawPath(styleStr); the "F"
+// style, which uses the nonzero winding number rule, fills the glyph as a font
+// would. The current position is not changed.
+//
+// The GetGlyphOutline() example demonstrates this method.
+func (f *Fpdf) DrawGlyphOutline(g GlyphOutlineType, x, y, sizeUnit float64, styleStr string) {
+	var s fmtBuffer
+	f.glyphPath(&s, g, x, y, sizeUnit)
+	s.printf("%s", f.paintOp(styleStr))
+	f.out(s.String())
+}
+
+// ClipGlyphOutline begins a clipping operation in which rendering is confined
+// to the shape of the glyph outline g, placed as with DrawGlyphOutline(). If
+// outline is true, the outline is drawn with the current line width and draw
+// color. Call ClipEnd() to restore unclipped operations.
+//
+// The GetGlyphOutline() example demonstrates this method.
+func (f *Fpdf) ClipGlyphOutline(g GlyphOutlineType, x, y, sizeUnit float64, outline bool) {
+	f.clipNest++
+	var s fmtBuffer
+	s.printf("q ")
+	f.glyphPath(&s, g, x, y, sizeUnit)
+	s.printf("W %s", strIf(outline, "S", "n"))
+	f.out(s.String())
+}
+
+// glyphPath writes the path construction operators for the glyph outline g
+// to s. Each operator is followed by a space.
+func (f *Fpdf) glyphPath(s *fmtBuffer, g GlyphOutlineType, x, y, sizeUnit float64) {
+	k, h, sc := f.k, f.h, sizeUnit/1000
+	pt := func(p PointType) (float64, float64) {
+		return (x + p.X*sc) * k, (h - (y - p.Y*sc)) * k
+	}
+	for _, contour := range g.Contours {
+		for j, seg := range contour {
+			if j == 0 {
+				x0, y0 := pt(seg.P0)
+				s.printf("%.2f %.2f m ", x0, y0)
+			}
+			x1, y1 := pt(seg.P1)
+			if seg.Line {
+				s.printf("%.2f %.2f l ", x1, y1)
+			} else {
+				cx0, cy0 := pt(seg.C0)
+				cx1, cy1 := pt(seg.C1)
+				s.printf("%.2f %.2f %.2f %.2f %.2f %.2f c ", cx0, cy0, cx1, cy1, x1, y1)
+			}
+		}
+		if len(contour) > 0 {
+			s.printf("h ")
+		}
+	}
+}
+
+// glyphReader reads glyph outlines from the glyf table of a TrueType font
+type glyphReader struct {
+	buf        []byte
+	loca, glyf int     // positions of the tables
+	longLoca   bool    // loca entries are 32 bit offsets
+	numGlyphs  int     // from the maxp table
+	scale      float64 // from font units to glyph space
+}
+
+// newGlyphReader returns a reader for the outlines of utf, or nil if the font
+// lacks the required tables
+func newGlyphReader(utf *utf8FontFile) *glyphReader {
+	tbl := func(name string) (int, bool) {
+		desc, ok := utf.tableDescriptions[name]
+		if !ok || desc.position+desc.size > len(utf.fileReader.array) {
+			return 0, false
+		}
+		return desc.position, true
+	}
+	head, ok1 := tbl("head")
+	maxp, ok2 := tbl("maxp")
+	loca, ok3 := tbl("loca")
+	glyf, ok4 := tbl("glyf")
+	if !ok1 || !ok2 || !ok3 || !ok4 || utf.fontElementSize == 0 {
+		return nil
+	}
+	rdr := &glyphReader{buf: utf.fileReader.array, loca: loca, glyf: glyf,
+		scale: 1000 / float64(utf.fontElementSize)}
+	format, ok5 := rdr.uint16(head + 50)
+	rdr.longLoca = format == 1
+	rdr.numGlyphs, ok1 = rdr.uint16(maxp + 4)
+	if !ok1 || !ok5 {
+		return nil
+	}
+	return rdr
+}
+
+// uint16 returns the unsigned 16 bit value at pos, or false if pos is out of
+// range
+func (rdr *glyphReader) uint16(pos int) (int, bool) {
+	if pos < 0 || pos+2 > len(rdr.buf) {
+		return 0, false
+	}
+	return int(binary.BigEndian.Uint16(rdr.buf[pos:])), true
+}
+
+// int16 returns the signed 16 bit value at pos, or false if pos is out of
+// range
+func (rdr *glyphReader) int16(pos int) (int, bool) {
+	v, ok := rdr.uint16(pos)
+	return int(int16(v)), ok
+}
+
+// glyphRange returns the position and length of the data of glyph gid
+func (rdr *glyphReader) glyphRange(gid int) (pos, n int, ok bool) {
+	if gid < 0 || gid >= rdr.numGlyphs {
+		return
+	}
+	var start, end int
+	if rdr.longLoca {
+		p := rdr.loca + 4*gid
+		if p+8 > len(rdr.buf) {
+			return
+		}
+		start = int(binary.BigEndian.Uint32(rdr.buf[p:]))
+		end = int(binary.BigEndian.Uint32(rdr.buf[p+4:]))
+	} else {
+		var ok1, ok2 bool
+		start, ok1 = rdr.uint16(rdr.loca + 2*gid)
+		end, ok2 = rdr.uint16(rdr.loca + 2*gid + 2)
+		if !ok1 || !ok2 {
+			return
+		}
+		start, end = 2*start, 2*end
+	}
+	pos, n = rdr.glyf+start, end-start
+	ok = n >= 0 && pos+n <= len(rdr.buf)
+	return
+}
+
+// glyphPoint is a point of a TrueType contour
+type glyphPoint struct {
+	x, y    float64
+	onCurve bool
+}
+
+// contours returns the contours of glyph gid in glyph space. Composite glyphs
+// are assembled from their components; depth, the level of nesting, is
+// limited to guard against components that refer to each other.
+func (rdr *glyphReader) contours(gid, depth int) (list [][]GlyphSegmentType, ok bool) {
+	pos, n, ok := rdr.glyphRange(gid)
+	if !ok || n == 0 || depth > 8 {
+		return nil, ok && depth <= 8
+	}
+	numContours, ok := rdr.int16(pos)
+	if !ok {
+		return nil, false
+	}
+	if numContours < 0 {
+		return rdr.compositeContours(pos+10, depth)
+	}
+	pts, ends, ok := rdr.simplePoints(pos+10, numContours)
+	if !ok {
+		return nil, false
+	}
+	start := 0
+	for _, end := range ends {
+		if contour := glyphContour(pts[start : end+1]); len(contour) > 0 {
+			list = append(list, contour)
+		}
+		start = end + 1
+	}
+	return list, true
+}
+
+// simplePoints reads the points of a simple glyph whose description, after
+// the header, begins at pos. The points are returned in glyph space along with
+// the index of the last point of each contour.
+func (rdr *glyphReader) simplePoints(pos, numContours int) (pts []glyphPoint, ends []int, ok bool) {
+	count := 0
+	for j := 0; j < numContours; j++ {
+		end, ok := rdr.uint16(pos + 2*j)
+		if !ok || end < count-1 {
+			return nil, nil, false
+		}
+		ends = append(ends, end)
+		count = end + 1
+	}
+	pos += 2 * numContours
+	insLen, ok := rdr.uint16(pos)
+	if !ok {
+		return
+	}
+	pos += 2 + insLen
+	flags := make([]byte, 0, count)
+	for len(flags) < count {
+		if pos >= len(rdr.buf) {
+			return nil, nil, false
+		}
+		flag := rdr.buf[pos]
+		pos++
+		flags = append(flags, flag)
+		if flag&0x08 != 0 {
+			if pos >= len(rdr.buf) {
+				return nil, nil, false
+			}
+			for k := int(rdr.buf[pos]); k > 0 && len(flags) < count; k-- {
+				flags = append(flags, flag)
+			}
+			pos++
+		}
+	}
+	// Coordinates are stored as deltas, first all x and then all y values
+	coords := make([][2]int, count)
+	for axis := 0; axis < 2; axis++ {
+		// Flags for a one byte value and for a repeated value or its sign
+		short, same := byte(0x02)<<uint(axis), byte(0x10)<<uint(axis)
+		v := 0
+		for j, flag := range flags {
+			switch {
+			case flag&short != 0:
+				if pos >= len(rdr.buf) {
+					return nil, nil, false
+				}
+				d := int(rdr.buf[pos])
+				pos++
+				if flag&same == 0 {
+					d = -d
+				}
+				v += d
+			case flag&same == 0:
+				d, ok := rdr.int16(pos)
+				if !ok {
+					return nil, nil, false
+				}
+				pos += 2
+				v += d
+			}
+			coords[j][axis] = v
+		}
+	}
+	pts = make([]glyphPoint, count)
+	for j, c := range coords {
+		pts[j] = glyphPoint{x: float64(c[0]) * rdr.scale, y: float64(c[1]) * rdr.scale,
+			onCurve: flags[j]&0x01 != 0}
+	}
+	return pts, ends, true
+}
+
+// compositeContours assembles the contours of a composite glyph whose
+// component records begin at pos
+func (rdr *glyphReader) compositeContours(pos, depth int) (list [][]GlyphSegmentType, ok bool) {
+	for {
+		flags, ok1 := rdr.uint16(pos)
+		gid, ok2 := rdr.uint16(pos + 2)
+		if !ok1 || !ok2 {
+			return nil, false
+		}
+		pos += 4
+		var dx, dy int
+		if flags&symbolWords != 0 {
+			dx, ok1 = rdr.int16(pos)
+			dy, ok2 = rdr.int16(pos + 2)
+			pos += 4
+		} else {
+			ok1 = pos+2 <= len(rdr.buf)
+			if ok1 {
+				dx, dy = int(int8(rdr.buf[pos])), int(int8(rdr.buf[pos+1]))
+			}
+			pos += 2
+		}
+		if !ok1 || !ok2 {
+			return nil, false
+		}
+		if flags&0x0002 == 0 {
+			// Components positioned by matching points are placed unshifted
+			dx, dy = 0, 0
+		}
+		// Transformation matrix in 2.14 fixed point format
+		a, b, c, d := 1.0, 0.0, 0.0, 1.0
+		f2dot14 := func(p int) float64 {
+			v, _ := rdr.int16(p)
+			return float64(v) / 16384
+		}
+		switch {
+		case flags&symbolScale != 0:
+			a = f2dot14(pos)
+			d = a
+			pos += 2
+		case flags&symbolAllScale != 0:
+			a, d = f2dot14(pos), f2dot14(pos+2)
+			pos += 4
+		case flags&symbol2x2 != 0:
+			a, b, c, d = f2dot14(pos), f2dot14(pos+2), f2dot14(pos+4), f2dot14(pos+6)
+			pos += 8
+		}
+		if pos > len(rdr.buf) {
+			return nil, false
+		}
+		sub, ok := rdr.contours(gid, depth+1)
+		if !ok {
+			return nil, false
+		}
+		ox, oy := float64(dx)*rdr.scale, float64(dy)*rdr.scale
+		tf := func(p PointType) PointType {
+			return PointType{X: a*p.X + c*p.Y + ox, Y: b*p.X + d*p.Y + oy}
+		}
+		for _, contour := range sub {
+			for j := range contour {
+				seg := &contour[j]
+				seg.P0, seg.P1, seg.C0, seg.C1 = tf(seg.P0), tf(seg.P1), tf(seg.C0), tf(seg.C1)
+			}
+			list = append(list, contour)
+		}
+		if flags&symbolContinue == 0 {
+			return list, true
+		}
+	}
+}
+
+// glyphContour converts the points of a closed TrueType contour, in which an
+// off-curve point is the control point of a quadratic curve and two adjacent
+// off-curve points imply an on-curve point midway between them, to segments
+func glyphContour(pts []glyphPoint) (list []GlyphSegmentType) {
+	n := len(pts)
+	if n == 0 {
+		return
+	}
+	// Start at an on-curve point, or at the midpoint of the first two
+	// off-curve points if there is none
+	first := -1
+	for j, p := range pts {
+		if p.onCurve {
+			first = j
+			break
+		}
+	}
+	var start PointType
+	if first < 0 {
+		first = 0
+		start = PointType{X: (pts[0].x + pts[1%n].x) / 2, Y: (pts[0].y + pts[1%n].y) / 2}
+	} else {
+		start = PointType{X: pts[first].x, Y: pts[first].y}
+	}
+	cur := start
+	var ctrl *PointType
+	for k := 1; k <= n; k++ {
+		p := pts[(first+k)%n]
+		pt := PointType{X: p.x, Y: p.y}
+		switch {
+		case p.onCurve && ctrl == nil:
+			if pt != cur {
+				list = append(list, GlyphSegmentType{Line: true, P0: cur, P1: pt})
+			}
+			cur = pt
+		case p.onCurve:
+			list = append(list, quadSegment(cur, *ctrl, pt))
+			cur, ctrl = pt, nil
+		case ctrl == nil:
+			ctrl = &PointType{X: p.x, Y: p.y}
+		default:
+			mid := PointType{X: (ctrl.X + pt.X) / 2, Y: (ctrl.Y + pt.Y) / 2}
+			list = append(list, quadSegment(cur, *ctrl, mid))
+			cur, ctrl = mid, &PointType{X: p.x, Y: p.y}
+		}
+	}
+	if ctrl != nil {
+		list = append(list, quadSegment(cur, *ctrl, start))
+	} else if cur != start {
+		list = append(list, GlyphSegmentType{Line: true, P0: cur, P1: start})
+	}
+	return
+}
+
+// quadSegment returns the cubic Bézier segment equivalent to the quadratic
+// curve from p0 to p1 with control point q
+func quadSegment(p0, q, p1 PointType) GlyphSegmentType {
+	return GlyphSegmentType{P0: p0, P1: p1,
+		C0: PointType{X: p0.X + 2*(q.X-p0.X)/3, Y: p0.Y + 2*(q.Y-p0.Y)/3},
+		C1: PointType{X: p1.X + 2*(q.X-p1.X)/3, Y: p1.Y + 2*(q.Y-p1.Y)/3}}
+}
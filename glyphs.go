@@ -0,0 +1,146 @@
+package gofpdf
+
+import "sort"
+
+// ZapfGlyph returns the single-byte string that selects the named glyph in
+// the ZapfDingbats core font, or an empty string if name is not part of the
+// font's built-in encoding. The names are those of the Adobe glyph list for
+// the font, for example "a20" (heavy check mark), "a35" (black star) or "a73"
+// (black square). This lets check boxes, stars and other dingbats be printed
+// without memorizing byte codes:
+//
+//	pdf.SetFont("ZapfDingbats", "", 12)
+//	pdf.Cell(5, 5, gofpdf.ZapfGlyph("a20"))
+func ZapfGlyph(name string) string {
+	return glyphString(zapfGlyphMap, name)
+}
+
+// SymbolGlyph returns the single-byte string that selects the named glyph in
+// the Symbol core font, or an empty string if name is not part of the font's
+// built-in encoding. Names follow the Adobe glyph list, for example "alpha",
+// "infinity", "arrowright" or "heart". The font is selected with the family
+// "StandardSymbol", since "Symbol" is an alias of ZapfDingbats:
+//
+//	pdf.SetFont("StandardSymbol", "", 12)
+//	pdf.Cell(5, 5, gofpdf.SymbolGlyph("alpha"))
+func SymbolGlyph(name string) string {
+	return glyphString(symbolGlyphMap, name)
+}
+
+// ZapfGlyphNames returns the glyph names that ZapfGlyph recognizes, ordered by
+// byte code.
+func ZapfGlyphNames() []string {
+	return glyphNames(zapfGlyphMap)
+}
+
+// SymbolGlyphNames returns the glyph names that SymbolGlyph recognizes,
+// ordered by byte code.
+func SymbolGlyphNames() []string {
+	return glyphNames(symbolGlyphMap)
+}
+
+func glyphString(m map[string]byte, name string) string {
+	if code, ok := m[name]; ok {
+		return string([]byte{code})
+	}
+	return ""
+}
+
+func glyphNames(m map[string]byte) (list []string) {
+	list = make([]string, 0, len(m))
+	for name := range m {
+		list = append(list, name)
+	}
+	sort.Slice(list, func(i, j int) bool { return m[list[i]] < m[list[j]] })
+	return
+}
+
+// Built-in encoding of the ZapfDingbats core font
+var zapfGlyphMap = map[string]byte{
+	"space": 32, "a1": 33, "a2": 34, "a202": 35, "a3": 36, "a4": 37, "a5": 38,
+	"a119": 39, "a118": 40, "a117": 41, "a11": 42, "a12": 43, "a13": 44,
+	"a14": 45, "a15": 46, "a16": 47, "a105": 48, "a17": 49, "a18": 50,
+	"a19": 51, "a20": 52, "a21": 53, "a22": 54, "a23": 55, "a24": 56, "a25": 57,
+	"a26": 58, "a27": 59, "a28": 60, "a6": 61, "a7": 62, "a8": 63, "a9": 64,
+	"a10": 65, "a29": 66, "a30": 67, "a31": 68, "a32": 69, "a33": 70, "a34": 71,
+	"a35": 72, "a36": 73, "a37": 74, "a38": 75, "a39": 76, "a40": 77, "a41": 78,
+	"a42": 79, "a43": 80, "a44": 81, "a45": 82, "a46": 83, "a47": 84, "a48": 85,
+	"a49": 86, "a50": 87, "a51": 88, "a52": 89, "a53": 90, "a54": 91, "a55": 92,
+	"a56": 93, "a57": 94, "a58": 95, "a59": 96, "a60": 97, "a61": 98, "a62": 99,
+	"a63": 100, "a64": 101, "a65": 102, "a66": 103, "a67": 104, "a68": 105,
+	"a69": 106, "a70": 107, "a71": 108, "a72": 109, "a73": 110, "a74": 111,
+	"a203": 112, "a75": 113, "a204": 114, "a76": 115, "a77": 116, "a78": 117,
+	"a79": 118, "a81": 119, "a82": 120, "a83": 121, "a84": 122, "a97": 123,
+	"a98": 124, "a99": 125, "a100": 126, "a89": 128, "a90": 129, "a93": 130,
+	"a94": 131, "a91": 132, "a92": 133, "a205": 134, "a85": 135, "a206": 136,
+	"a86": 137, "a87": 138, "a88": 139, "a95": 140, "a96": 141, "a101": 161,
+	"a102": 162, "a103": 163, "a104": 164, "a106": 165, "a107": 166,
+	"a108": 167, "a112": 168, "a111": 169, "a110": 170, "a109": 171,
+	"a120": 172, "a121": 173, "a122": 174, "a123": 175, "a124": 176,
+	"a125": 177, "a126": 178, "a127": 179, "a128": 180, "a129": 181,
+	"a130": 182, "a131": 183, "a132": 184, "a133": 185, "a134": 186,
+	"a135": 187, "a136": 188, "a137": 189, "a138": 190, "a139": 191,
+	"a140": 192, "a141": 193, "a142": 194, "a143": 195, "a144": 196,
+	"a145": 197, "a146": 198, "a147": 199, "a148": 200, "a149": 201,
+	"a150": 202, "a151": 203, "a152": 204, "a153": 205, "a154": 206,
+	"a155": 207, "a156": 208, "a157": 209, "a158": 210, "a159": 211,
+	"a160": 212, "a161": 213, "a163": 214, "a164": 215, "a196": 216,
+	"a165": 217, "a192": 218, "a166": 219, "a167": 220, "a168": 221,
+	"a169": 222, "a170": 223, "a171": 224, "a172": 225, "a173": 226,
+	"a162": 227, "a174": 228, "a175": 229, "a176": 230, "a177": 231,
+	"a178": 232, "a179": 233, "a193": 234, "a180": 235, "a199": 236,
+	"a181": 237, "a200": 238, "a182": 239, "a201": 241, "a183": 242,
+	"a184": 243, "a197": 244, "a185": 245, "a194": 246, "a198": 247,
+	"a186": 248, "a195": 249, "a187": 250, "a188": 251, "a189": 252,
+	"a190": 253, "a191": 254,
+}
+
+// Built-in encoding of the Symbol core font
+var symbolGlyphMap = map[string]byte{
+	"space": 32, "exclam": 33, "universal": 34, "numbersign": 35,
+	"existential": 36, "percent": 37, "ampersand": 38, "suchthat": 39,
+	"parenleft": 40, "parenright": 41, "asteriskmath": 42, "plus": 43,
+	"comma": 44, "minus": 45, "period": 46, "slash": 47, "zero": 48, "one": 49,
+	"two": 50, "three": 51, "four": 52, "five": 53, "six": 54, "seven": 55,
+	"eight": 56, "nine": 57, "colon": 58, "semicolon": 59, "less": 60,
+	"equal": 61, "greater": 62, "question": 63, "congruent": 64, "Alpha": 65,
+	"Beta": 66, "Chi": 67, "Delta": 68, "Epsilon": 69, "Phi": 70, "Gamma": 71,
+	"Eta": 72, "Iota": 73, "theta1": 74, "Kappa": 75, "Lambda": 76, "Mu": 77,
+	"Nu": 78, "Omicron": 79, "Pi": 80, "Theta": 81, "Rho": 82, "Sigma": 83,
+	"Tau": 84, "Upsilon": 85, "sigma1": 86, "Omega": 87, "Xi": 88, "Psi": 89,
+	"Zeta": 90, "bracketleft": 91, "therefore": 92, "bracketright": 93,
+	"perpendicular": 94, "underscore": 95, "radicalex": 96, "alpha": 97,
+	"beta": 98, "chi": 99, "delta": 100, "epsilon": 101, "phi": 102,
+	"gamma": 103, "eta": 104, "iota": 105, "phi1": 106, "kappa": 107,
+	"lambda": 108, "mu": 109, "nu": 110, "omicron": 111, "pi": 112,
+	"theta": 113, "rho": 114, "sigma": 115, "tau": 116, "upsilon": 117,
+	"omega1": 118, "omega": 119, "xi": 120, "psi": 121, "zeta": 122,
+	"braceleft": 123, "bar": 124, "braceright": 125, "similar": 126,
+	"Euro": 160, "Upsilon1": 161, "minute": 162, "lessequal": 163,
+	"fraction": 164, "infinity": 165, "florin": 166, "club": 167,
+	"diamond": 168, "heart": 169, "spade": 170, "arrowboth": 171,
+	"arrowleft": 172, "arrowup": 173, "arrowright": 174, "arrowdown": 175,
+	"degree": 176, "plusminus": 177, "second": 178, "greaterequal": 179,
+	"multiply": 180, "proportional": 181, "partialdiff": 182, "bullet": 183,
+	"divide": 184, "notequal": 185, "equivalence": 186, "approxequal": 187,
+	"ellipsis": 188, "arrowvertex": 189, "arrowhorizex": 190,
+	"carriagereturn": 191, "aleph": 192, "Ifraktur": 193, "Rfraktur": 194,
+	"weierstrass": 195, "circlemultiply": 196, "circleplus": 197,
+	"emptyset": 198, "intersection": 199, "union": 200, "propersuperset": 201,
+	"reflexsuperset": 202, "notsubset": 203, "propersubset": 204,
+	"reflexsubset": 205, "element": 206, "notelement": 207, "angle": 208,
+	"gradient": 209, "registerserif": 210, "copyrightserif": 211,
+	"trademarkserif": 212, "product": 213, "radical": 214, "dotmath": 215,
+	"logicalnot": 216, "logicaland": 217, "logicalor": 218, "arrowdblboth": 219,
+	"arrowdblleft": 220, "arrowdblup": 221, "arrowdblright": 222,
+	"arrowdbldown": 223, "lozenge": 224, "angleleft": 225, "registersans": 226,
+	"copyrightsans": 227, "trademarksans": 228, "summation": 229,
+	"parenlefttp": 230, "parenleftex": 231, "parenleftbt": 232,
+	"bracketlefttp": 233, "bracketleftex": 234, "bracketleftbt": 235,
+	"bracelefttp": 236, "braceleftmid": 237, "braceleftbt": 238, "braceex": 239,
+	"angleright": 241, "integral": 242, "integraltp": 243, "integralex": 244,
+	"integralbt": 245, "parenrighttp": 246, "parenrightex": 247,
+	"parenrightbt": 248, "bracketrighttp": 249, "bracketrightex": 250,
+	"bracketrightbt": 251, "bracerighttp": 252, "bracerightmid": 253,
+	"bracerightbt": 254,
+}
@@ -6,6 +6,8 @@
 package gofpdf
 
 import (
+	"unicode/utf8"
+
 	"github.com/rivo/uniseg"
 )
 
@@ -28,16 +30,42 @@ import (
 // Returns:
 //   A slice of strings, where each string represents one grapheme cluster
 func graphemeClusters(s string) []string {
-	var clusters []string
-	gr := uniseg.NewGraphemes(s)
+	if isASCII(s) {
+		// Every ASCII character is a cluster of its own, except that a
+		// carriage return and a following line feed form a single cluster
+		clusters := make([]string, 0, len(s))
+		for j := 0; j < len(s); j++ {
+			if s[j] == '\r' && j+1 < len(s) && s[j+1] == '\n' {
+				clusters = append(clusters, s[j:j+2])
+				j++
+			} else {
+				clusters = append(clusters, s[j:j+1])
+			}
+		}
+		return clusters
+	}
 
-	for gr.Next() {
-		clusters = append(clusters, gr.Str())
+	var clusters []string
+	var cluster string
+	state := -1
+	for len(s) > 0 {
+		cluster, s, _, state = uniseg.FirstGraphemeClusterInString(s, state)
+		clusters = append(clusters, cluster)
 	}
 
 	return clusters
 }
 
+// isASCII reports whether s consists of ASCII characters only
+func isASCII(s string) bool {
+	for j := 0; j < len(s); j++ {
+		if s[j] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
 // graphemeClusterWidth calculates the display width of a grapheme cluster
 // in font units (typically 1/1000th of the font size).
 //
@@ -62,7 +90,7 @@ func graphemeClusterWidth(cluster string, font *fontDefType) int {
 	}
 
 	// Get the first rune (base character) of the cluster
-	baseRune := []rune(cluster)[0]
+	baseRune, _ := utf8.DecodeRuneInString(cluster)
 
 	// Look up the width in the font's character width map
 	if width, ok := font.Cw[int(baseRune)]; ok {
@@ -2,6 +2,8 @@ package gofpdf
 
 import (
 	"testing"
+
+	"github.com/rivo/uniseg"
 )
 
 // TestGraphemeClusters_BasicEmoji tests grapheme cluster splitting with basic emoji
@@ -186,6 +188,34 @@ func TestGraphemeClusters_VariationSelectors(t *testing.T) {
 	}
 }
 
+// TestGraphemeClusters_ASCII tests that the clusters of ASCII text, which are
+// found without uniseg, match those found by uniseg
+func TestGraphemeClusters_ASCII(t *testing.T) {
+	inputs := []string{
+		"Hello, World!",
+		"line one\r\nline two\n\nline three\r",
+		"\r\r\n\n\r",
+		"tab\tseparated\x00control\x7f",
+	}
+	for _, input := range inputs {
+		var expected []string
+		gr := uniseg.NewGraphemes(input)
+		for gr.Next() {
+			expected = append(expected, gr.Str())
+		}
+		result := graphemeClusters(input)
+		if len(result) != len(expected) {
+			t.Errorf("graphemeClusters(%q) returned %d clusters, expected %d", input, len(result), len(expected))
+			continue
+		}
+		for i, cluster := range result {
+			if cluster != expected[i] {
+				t.Errorf("graphemeClusters(%q)[%d] = %q, expected %q", input, i, cluster, expected[i])
+			}
+		}
+	}
+}
+
 // TestIsEmoji tests the emoji detection function
 func TestIsEmoji(t *testing.T) {
 	tests := []struct {
@@ -27,6 +27,7 @@ type StateType struct {
 	lineWd                    float64
 	fontSize                  float64
 	alpha                     float64
+	strokeAlpha, fillAlpha    float64
 	blendStr                  string
 	cellMargin                float64
 }
@@ -39,6 +40,7 @@ func StateGet(pdf *Fpdf) (st StateType) {
 	st.lineWd = pdf.GetLineWidth()
 	_, st.fontSize = pdf.GetFontSize()
 	st.alpha, st.blendStr = pdf.GetAlpha()
+	st.strokeAlpha, st.fillAlpha = pdf.GetStrokeAlpha(), pdf.GetFillAlpha()
 	st.cellMargin = pdf.GetCellMargin()
 	return
 }
@@ -52,6 +54,10 @@ func (st StateType) Put(pdf *Fpdf) {
 	pdf.SetLineWidth(st.lineWd)
 	pdf.SetFontUnitSize(st.fontSize)
 	pdf.SetAlpha(st.alpha, st.blendStr)
+	if st.strokeAlpha != st.alpha || st.fillAlpha != st.alpha {
+		pdf.strokeAlpha, pdf.fillAlpha = st.strokeAlpha, st.fillAlpha
+		pdf.putAlpha()
+	}
 	pdf.SetCellMargin(st.cellMargin)
 }
 
@@ -444,3 +450,131 @@ func (g GridType) Plot(pdf *Fpdf, xMin, xMax float64, count int, fnc func(x floa
 		}
 	}
 }
+
+// Paper styles recognized by PaperType
+const (
+	// PaperGraph is a square grid of lines
+	PaperGraph = "graph"
+	// PaperDot is a square grid of dots
+	PaperDot = "dot"
+	// PaperRuled is a set of horizontal lines for handwriting
+	PaperRuled = "ruled"
+	// PaperIsometric is a grid of equilateral triangles
+	PaperIsometric = "isometric"
+)
+
+// PaperType assists with the generation of printable graph paper, dot grids,
+// ruled notebook pages and isometric grids. Create a value with NewPaper(),
+// adjust its exported fields as needed and call Draw() or DrawPage().
+type PaperType struct {
+	// One of PaperGraph, PaperDot, PaperRuled or PaperIsometric
+	Style string
+	// Distance between lines, dots or triangle vertices in page units
+	Pitch float64
+	// Every MajorEvery-th line of graph paper and every MajorEvery-th
+	// horizontal line of ruled paper is drawn with the major attributes; zero
+	// draws all lines alike
+	MajorEvery int
+	// Distance from the left edge of ruled paper to a vertical margin line,
+	// drawn with the major attributes; zero omits the line
+	RuledMargin float64
+	// Line and dot colors
+	Clr, ClrMajor RGBAType
+	// Line thickness and dot diameter in page units
+	Wd, WdMajor, DotSize float64
+}
+
+// NewPaper returns a variable of type PaperType of the specified style with
+// lines, dots or vertices pitch page units apart. It is initialized with
+// thin light blue lines, a heavier line every fifth line of graph paper and
+// dots that are one tenth of the pitch in diameter.
+func NewPaper(styleStr string, pitch float64) (paper PaperType) {
+	paper.Style = styleStr
+	paper.Pitch = pitch
+	paper.Clr = RGBAType{R: 150, G: 190, B: 230, Alpha: 1}
+	paper.ClrMajor = RGBAType{R: 90, G: 140, B: 210, Alpha: 1}
+	if styleStr == PaperGraph {
+		paper.MajorEvery = 5
+	}
+	paper.Wd = pitch / 40
+	paper.WdMajor = pitch / 16
+	paper.DotSize = pitch / 10
+	return
+}
+
+// Draw fills the rectangle of width w and height h with its upper left corner
+// at (x, y) with the paper's pattern. Lines and dots begin at the upper left
+// corner. The drawing state in effect beforehand is restored when Draw()
+// returns.
+func (paper PaperType) Draw(pdf *Fpdf, x, y, w, h float64) {
+	if paper.Pitch <= 0 || pdf.err != nil {
+		return
+	}
+	const eps = 1e-6
+	st := StateGet(pdf)
+	major := func(j int) bool {
+		return paper.MajorEvery > 0 && j%paper.MajorEvery == 0
+	}
+	line := func(x1, y1, x2, y2 float64, heavy bool) {
+		if heavy {
+			lineAttr(pdf, paper.ClrMajor, paper.WdMajor)
+		} else {
+			lineAttr(pdf, paper.Clr, paper.Wd)
+		}
+		pdf.Line(x1, y1, x2, y2)
+	}
+	switch paper.Style {
+	case PaperGraph:
+		for j := 0; float64(j)*paper.Pitch <= w+eps; j++ {
+			lx := x + float64(j)*paper.Pitch
+			line(lx, y, lx, y+h, major(j))
+		}
+		for j := 0; float64(j)*paper.Pitch <= h+eps; j++ {
+			ly := y + float64(j)*paper.Pitch
+			line(x, ly, x+w, ly, major(j))
+		}
+	case PaperDot:
+		pdf.SetAlpha(paper.Clr.Alpha, "Normal")
+		pdf.SetFillColor(paper.Clr.R, paper.Clr.G, paper.Clr.B)
+		for j := 0; float64(j)*paper.Pitch <= h+eps; j++ {
+			for k := 0; float64(k)*paper.Pitch <= w+eps; k++ {
+				pdf.Circle(x+float64(k)*paper.Pitch, y+float64(j)*paper.Pitch, paper.DotSize/2, "F")
+			}
+		}
+	case PaperRuled:
+		for j := 1; float64(j)*paper.Pitch <= h+eps; j++ {
+			ly := y + float64(j)*paper.Pitch
+			line(x, ly, x+w, ly, major(j))
+		}
+		if paper.RuledMargin > 0 && paper.RuledMargin < w {
+			line(x+paper.RuledMargin, y, x+paper.RuledMargin, y+h, true)
+		}
+	case PaperIsometric:
+		// Vertical lines and lines at 30 degrees above and below the
+		// horizontal, clipped to the rectangle
+		dx := paper.Pitch * math.Sqrt(3) / 2
+		slope := paper.Pitch / 2 / dx
+		pdf.ClipRect(x, y, w, h, false)
+		for j := 0; float64(j)*dx <= w+eps; j++ {
+			lx := x + float64(j)*dx
+			line(lx, y, lx, y+h, false)
+		}
+		rise := w * slope
+		for ly := y - math.Ceil(rise/paper.Pitch)*paper.Pitch; ly <= y+h+rise+eps; ly += paper.Pitch {
+			line(x, ly, x+w, ly+rise, false)
+			line(x, ly, x+w, ly-rise, false)
+		}
+		pdf.ClipEnd()
+	default:
+		pdf.SetErrorf("unrecognized paper style %s", paper.Style)
+	}
+	st.Put(pdf)
+}
+
+// DrawPage fills the area of the current page that lies within the margins
+// with the paper's pattern.
+func (paper PaperType) DrawPage(pdf *Fpdf) {
+	wd, ht := pdf.GetPageSize()
+	lf, tp, rt, bt := pdf.GetMargins()
+	paper.Draw(pdf, lf, tp, wd-lf-rt, ht-tp-bt)
+}
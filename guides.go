@@ -0,0 +1,131 @@
+package gofpdf
+
+// LayoutGuidesType specifies the guides drawn on each page by
+// SetLayoutGuides()
+type LayoutGuidesType struct {
+	// Whether the area within the margins is outlined, its lower edge being
+	// the position at which automatic page breaks occur
+	Margins bool
+	// Spacing of the lines of a baseline grid that begins at the top margin;
+	// zero omits the grid
+	BaselineGrid float64
+	// Number of columns of equal width, separated by Gutter, whose edges are
+	// marked between the left and right margins; zero omits the column
+	// guides
+	Columns int
+	Gutter  float64
+	// Whether a cross marks the current position at the start of each cell
+	// printed by CellFormat() and the methods based on it, such as Cell(),
+	// MultiCell() and Write()
+	Cursor bool
+	// Whether the guides are part of the page content. If false, they are
+	// drawn in a layer that viewers display on screen but that is excluded
+	// when the document is printed or exported; see AddLayer().
+	Keep bool
+}
+
+// layoutGuidesRecType holds the state of the layout guides
+type layoutGuidesRecType struct {
+	on    bool
+	opt   LayoutGuidesType
+	layer int // layer of the guides plus one; zero if it has not been added
+	marks []PointType
+}
+
+// Colors of the layout guides
+var (
+	guideMarginClr = RGBType{0, 153, 255}
+	guideGridClr   = RGBType{153, 221, 255}
+	guideColumnClr = RGBType{255, 153, 0}
+	guideCursorClr = RGBType{255, 0, 0}
+)
+
+// SetLayoutGuides turns on a debugging aid that draws the margins, a baseline
+// grid, column guides and markers of the current position on every page, as
+// specified by guides, so that the layout of a document can be checked at a
+// glance. The guides of a page are drawn with hairlines over its content when
+// the page is finished. Unless guides.Keep is true, they are placed in a
+// layer named "Layout guides" that viewers show on screen and omit when the
+// document is printed or exported to another format, and that can be hidden
+// in the layer pane of the viewer. The guides nevertheless remain in the
+// file; to produce a document without them, generate it with the guides
+// turned off. A nil value for guides turns the guides off for the
+// pages that are finished afterwards.
+func (f *Fpdf) SetLayoutGuides(guides *LayoutGuidesType) {
+	lg := &f.layoutGuides
+	lg.on = guides != nil
+	lg.marks = lg.marks[:0]
+	if !lg.on {
+		return
+	}
+	lg.opt = *guides
+	if !lg.opt.Keep && lg.layer == 0 {
+		lg.layer = f.AddLayer("Layout guides", true) + 1
+		f.layer.list[lg.layer-1].screenOnly = true
+	}
+}
+
+// layoutGuideMark records the current position for the cursor markers of the
+// layout guides
+func (f *Fpdf) layoutGuideMark() {
+	if f.layoutGuides.on && f.layoutGuides.opt.Cursor {
+		f.layoutGuides.marks = append(f.layoutGuides.marks, PointType{f.x, f.y})
+	}
+}
+
+// putLayoutGuides draws the layout guides on the current page, which is
+// about to be finished
+func (f *Fpdf) putLayoutGuides() {
+	lg := &f.layoutGuides
+	if !lg.on || f.err != nil {
+		return
+	}
+	opt := lg.opt
+	k := f.k
+	left, right := f.lMargin, f.w-f.rMargin
+	top, bottom := f.tMargin, f.pageBreakTrigger
+	var b fmtBuffer
+	color := func(clr RGBType) {
+		b.printf("%.3f %.3f %.3f RG ", float64(clr.R)/255, float64(clr.G)/255, float64(clr.B)/255)
+	}
+	line := func(x1, y1, x2, y2 float64) {
+		b.printf("%.2f %.2f m %.2f %.2f l S ", x1*k, (f.h-y1)*k, x2*k, (f.h-y2)*k)
+	}
+	if opt.BaselineGrid > 0 {
+		color(guideGridClr)
+		for y := top + opt.BaselineGrid; y <= bottom; y += opt.BaselineGrid {
+			line(left, y, right, y)
+		}
+	}
+	if opt.Columns > 0 {
+		color(guideColumnClr)
+		colWd := (right - left - float64(opt.Columns-1)*opt.Gutter) / float64(opt.Columns)
+		for j := 0; j < opt.Columns; j++ {
+			x := left + float64(j)*(colWd+opt.Gutter)
+			line(x, top, x, bottom)
+			line(x+colWd, top, x+colWd, bottom)
+		}
+	}
+	if opt.Margins {
+		color(guideMarginClr)
+		b.printf("%.2f %.2f %.2f %.2f re S ", left*k, (f.h-top)*k, (right-left)*k, (top-bottom)*k)
+	}
+	if len(lg.marks) > 0 {
+		color(guideCursorClr)
+		sz := 1.5 / k
+		for _, pt := range lg.marks {
+			line(pt.X-sz, pt.Y, pt.X+sz, pt.Y)
+			line(pt.X, pt.Y-sz, pt.X, pt.Y+sz)
+		}
+	}
+	lg.marks = lg.marks[:0]
+	if b.Len() == 0 {
+		return
+	}
+	if !opt.Keep {
+		f.BeginLayer(lg.layer - 1)
+	}
+	// Hairlines in the initial graphics state
+	f.outf("q 0 w [] 0 d 0 J %sQ", b.String())
+	f.EndLayer()
+}
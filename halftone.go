@@ -0,0 +1,156 @@
+package gofpdf
+
+import (
+	"strings"
+)
+
+// HalftoneType describes a halftone screen, which renders tints on a printing
+// device as a pattern of dots, for SetHalftone()
+type HalftoneType struct {
+	// Screen frequency in lines per inch
+	Frequency float64
+	// Screen angle in degrees counterclockwise
+	Angle float64
+	// Name of a spot function of PDF, which determines the shape of the
+	// dots: "SimpleDot", "InvertedSimpleDot", "DoubleDot",
+	// "InvertedDoubleDot", "CosineDot", "Double", "InvertedDouble", "Line",
+	// "LineX", "LineY", "Round", "Ellipse", "EllipseA", "InvertedEllipseA",
+	// "EllipseB", "EllipseC", "InvertedEllipseC", "Square", "Cross",
+	// "Rhomboid" or "Diamond". An empty name selects "Round".
+	SpotFunction string
+}
+
+var halftoneSpotFunctions = map[string]bool{
+	"SimpleDot": true, "InvertedSimpleDot": true, "DoubleDot": true,
+	"InvertedDoubleDot": true, "CosineDot": true, "Double": true,
+	"InvertedDouble": true, "Line": true, "LineX": true, "LineY": true,
+	"Round": true, "Ellipse": true, "EllipseA": true, "InvertedEllipseA": true,
+	"EllipseB": true, "EllipseC": true, "InvertedEllipseC": true,
+	"Square": true, "Cross": true, "Rhomboid": true, "Diamond": true,
+}
+
+// SetHalftone sets the halftone screen with which the content that follows
+// is printed, such as the coarse screens at distinct angles of the
+// separations of screen printing, or restores the default screen of the
+// printing device if ht is nil. The screen applies to all colorants and is
+// retained from page to page. Halftones take effect on printing devices
+// only; viewers ignore them.
+//
+// An error occurs if the frequency is not positive or the spot function is
+// unknown.
+func (f *Fpdf) SetHalftone(ht *HalftoneType) {
+	if f.err != nil {
+		return
+	}
+	if ht == nil {
+		f.halftoneStr = ""
+	} else {
+		spotStr := ht.SpotFunction
+		if spotStr == "" {
+			spotStr = "Round"
+		}
+		if !halftoneSpotFunctions[spotStr] {
+			f.SetErrorf("unknown halftone spot function %s", spotStr)
+			return
+		}
+		if ht.Frequency <= 0 {
+			f.SetErrorf("halftone frequency must be positive: %.2f", ht.Frequency)
+			return
+		}
+		f.halftoneStr = sprintf("/HT <</Type /Halftone /HalftoneType 1 /Frequency %.2f /Angle %.2f /SpotFunction /%s>>",
+			ht.Frequency, ht.Angle, spotStr)
+	}
+	f.putPrintState()
+}
+
+// SetTransferFunction sets the transfer function with which the content that
+// follows is printed, which adjusts tints to the characteristics of the
+// printing device, for example to compensate for the dot gain of a screen
+// printing press, or restores the default of the device if points is nil. The
+// function applies to all colorants and is retained from page to page.
+//
+// points map tints from 0 (no ink) to 1 (full ink) in X to those that are
+// printed in Y, and are joined by straight lines; the first and last points
+// are extended to the ends of the range. For example, the points (0, 0),
+// (0.5, 0.38) and (1, 1) print a 50% tint with 38% ink, compensating for dot
+// gain in the midtones. An error occurs if there are fewer than two points, if values lie
+// outside the range from 0 to 1, or if the X values do not increase.
+func (f *Fpdf) SetTransferFunction(points []PointType) {
+	if f.err != nil {
+		return
+	}
+	if points == nil {
+		f.transferStr = ""
+		f.putPrintState()
+		return
+	}
+	if len(points) < 2 {
+		f.SetErrorf("transfer function requires at least two points")
+		return
+	}
+	for j, pt := range points {
+		if pt.X < 0 || pt.X > 1 || pt.Y < 0 || pt.Y > 1 {
+			f.SetErrorf("transfer function point (%.3f, %.3f) is out of range", pt.X, pt.Y)
+			return
+		}
+		if j > 0 && pt.X <= points[j-1].X {
+			f.SetErrorf("transfer function points must increase in X")
+			return
+		}
+	}
+	// Transfer functions map additive values, in which 0 is full ink, so the
+	// tints are complemented and taken in reverse order
+	add := make([]PointType, len(points))
+	for j, pt := range points {
+		add[len(points)-1-j] = PointType{X: 1 - pt.X, Y: 1 - pt.Y}
+	}
+	var fns, bounds, encode []string
+	for j := 1; j < len(add); j++ {
+		fns = append(fns, sprintf("<</FunctionType 2 /Domain [0 1] /C0 [%.4f] /C1 [%.4f] /N 1>>",
+			add[j-1].Y, add[j].Y))
+		encode = append(encode, "0 1")
+		if j < len(add)-1 {
+			bounds = append(bounds, sprintf("%.4f", add[j].X))
+		}
+	}
+	f.transferStr = sprintf("/TR2 <</FunctionType 3 /Domain [%.4f %.4f] /Functions [%s] /Bounds [%s] /Encode [%s]>>",
+		add[0].X, add[len(add)-1].X, strings.Join(fns, " "),
+		strings.Join(bounds, " "), strings.Join(encode, " "))
+	f.putPrintState()
+}
+
+// putPrintState selects the graphics state of the current halftone and
+// transfer function, registering it if it is new
+func (f *Fpdf) putPrintState() {
+	htStr, trStr := f.halftoneStr, f.transferStr
+	if htStr == "" {
+		htStr = "/HT /Default"
+	}
+	if trStr == "" {
+		trStr = "/TR2 /Default"
+	}
+	dictStr := htStr + " " + trStr
+	pos, ok := f.printStateMap[dictStr]
+	if !ok {
+		if f.printStateMap == nil {
+			f.printStateMap = make(map[string]int)
+		}
+		f.printStates = append(f.printStates, printStateType{dictStr: dictStr})
+		pos = len(f.printStates)
+		f.printStateMap[dictStr] = pos
+	}
+	if f.page > 0 {
+		f.outf("/PS%d gs", pos)
+	}
+}
+
+// putPrintStates writes the graphics states of halftones and transfer
+// functions
+func (f *Fpdf) putPrintStates() {
+	for j := range f.printStates {
+		f.newobj()
+		f.printStates[j].objNum = f.n
+		f.outf("<</Type /ExtGState %s>>", f.printStates[j].dictStr)
+		f.out("endobj")
+	}
+}
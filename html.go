@@ -0,0 +1,1149 @@
+package gofpdf
+
+import (
+	"html"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// HTMLType renders HTML with a subset of CSS, such as the content of a web
+// publishing system, into a document. Create a value with HTMLNew(), adjust
+// its exported fields as needed and print markup with Write().
+type HTMLType struct {
+	// Distance between lines as a multiple of the size of the largest font
+	// on each line, unless the document has an absolute line height (see
+	// SetLineHeight()) or an element sets the line-height style
+	LineSpacing float64
+	// Space in user units before and after paragraphs, headings, lists,
+	// block quotes and tables
+	ParaSpacing float64
+	// Indentation in user units of list items and block quotes
+	Indent float64
+	// Color of hyperlinks, which are underlined
+	ClrLink RGBType
+	// Add headings (H1 through H6) to the document outline with Bookmark(),
+	// nested by rank
+	Outline bool
+
+	pdf      *Fpdf
+	headings []int // ranks of the headings that enclose the current one
+}
+
+// HTMLNew returns an instance that renders HTML in the specified PDF file.
+// Lines are spaced as set with SetLineHeightFactor(), at 1.25 times their
+// font size by default, and paragraphs are separated by the height of the
+// current font, which also determines the indentation of lists.
+func (f *Fpdf) HTMLNew() (h HTMLType) {
+	h.pdf = f
+	h.LineSpacing = f.lineHeightFactor
+	h.ParaSpacing = f.fontSize
+	h.Indent = 2.5 * f.fontSize
+	h.ClrLink = RGBType{0, 0, 128}
+	return
+}
+
+// Write prints htmlStr, beginning at the left margin at the current vertical
+// position, in the current font, size and text color; these are restored
+// when Write returns, with the current position below the printed content.
+// Text is wrapped to the width between the margins and the page is broken
+// as needed, subject to the application's decision about content of the
+// kind PageBreakWrite, or PageBreakImage for images.
+//
+// The following elements are recognized:
+//
+// Paragraphs and blocks: P, DIV, BR, HR, BLOCKQUOTE (indented on both sides),
+// CENTER and headings H1 through H6, which are printed in bold at sizes
+// relative to the size of the current font.
+//
+// Text styles: B, STRONG, I, EM, U, INS, S, STRIKE, DEL, CODE and TT
+// (Courier), FONT with the COLOR, SIZE (1 through 7, or relative such as +1)
+// and FACE attributes, and hyperlinks (A with an HREF attribute).
+//
+// Lists: UL and OL, with the START and TYPE (1, a, A, i or I) attributes,
+// nested to any depth, and LI.
+//
+// Tables: TABLE, TR, TH and TD, laid out with NewTable(). A first row made up
+// of TH cells is the header, which is repeated on each page. Cells may span
+// rows with the ROWSPAN attribute, and the WIDTH attribute of the cells of
+// the first row sets the width of their columns. The text of the cells is
+// printed in the font of the table, without inline styles. Frames are drawn
+// unless the table has BORDER="0".
+//
+// Images: IMG with the SRC, WIDTH, HEIGHT and ALT attributes. The source is
+// a file name as accepted by Image(), or the name of an image registered
+// beforehand, such as with RegisterImageOptionsReader(). An image is printed
+// on its own line, reduced to the width of the text if necessary.
+//
+// The ALIGN attribute (left, center, right or justify) applies to blocks,
+// cells and images. The STYLE attribute of any element may set the color,
+// font-family, font-size (in pt, px, em or %, or a keyword such as large),
+// font-style, font-weight, line-height (a multiple of the font size, a
+// percentage or a length), text-align, text-decoration and, for images,
+// width and height. Other elements are printed as their content, and the
+// content of HEAD, SCRIPT, STYLE and TITLE is omitted. Character
+// references such as &amp; are decoded. With a core font, text is
+// translated to code page 1252.
+func (h *HTMLType) Write(htmlStr string) {
+	f := h.pdf
+	if f.err != nil {
+		return
+	}
+	if f.page == 0 {
+		f.AddPage()
+	}
+	familyStr, styleStr, ptSize := f.fontFamily, f.fontStyleStr(), f.fontSizePt
+	r, g, b := f.GetTextColor()
+	rd := htmlRendererType{
+		h:         h,
+		f:         f,
+		base:      ptSize,
+		headLevel: -1,
+		tr:        f.UnicodeTranslatorFromDescriptor(""),
+	}
+	rd.stack = []htmlStyleType{{
+		familyStr: familyStr,
+		bold:      strings.Contains(styleStr, "B"),
+		italic:    strings.Contains(styleStr, "I"),
+		underline: strings.Contains(styleStr, "U"),
+		strike:    strings.Contains(styleStr, "S"),
+		size:      ptSize,
+		clr:       RGBType{r, g, b},
+		alignStr:  "L",
+		lineHt:    f.lineHeight,
+	}}
+	f.SetX(f.lMargin)
+	for _, el := range htmlTokenize(htmlStr) {
+		if f.err != nil {
+			break
+		}
+		switch el.Cat {
+		case 'T':
+			rd.text(el.Str)
+		case 'O':
+			rd.open(el.Str, el.Attr)
+		case 'C':
+			rd.close(el.Str)
+		}
+	}
+	rd.flush()
+	if rd.table != nil {
+		rd.putTable()
+	}
+	if familyStr != "" {
+		f.SetFont(familyStr, styleStr, ptSize)
+	}
+	f.SetTextColor(r, g, b)
+}
+
+var (
+	htmlTagRe  = regexp.MustCompile(`(?s)<!--.*?-->|<[!?][^>]*>|<(/?)([a-zA-Z][a-zA-Z0-9]*)((?:[^>"']|"[^"]*"|'[^']*')*)>`)
+	htmlAttrRe = regexp.MustCompile(`([a-zA-Z_:][-a-zA-Z0-9_:.]*)(?:\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+)))?`)
+)
+
+// htmlTokenize returns the tags and text of htmlStr. Unlike
+// HTMLBasicTokenize(), quoted attribute values may contain spaces and
+// angle brackets, comments and declarations are dropped, character
+// references are decoded and the content of elements that are not displayed
+// is omitted.
+func htmlTokenize(htmlStr string) (list []HTMLBasicSegmentType) {
+	skipStr := ""
+	pos := 0
+	putText := func(s string) {
+		if s != "" && skipStr == "" {
+			list = append(list, HTMLBasicSegmentType{Cat: 'T', Str: html.UnescapeString(s)})
+		}
+	}
+	for _, m := range htmlTagRe.FindAllStringSubmatchIndex(htmlStr, -1) {
+		putText(htmlStr[pos:m[0]])
+		pos = m[1]
+		if m[4] < 0 {
+			// Comment or declaration
+			continue
+		}
+		tagStr := strings.ToLower(htmlStr[m[4]:m[5]])
+		if htmlStr[m[2]:m[3]] == "/" {
+			if tagStr == skipStr {
+				skipStr = ""
+			} else if skipStr == "" {
+				list = append(list, HTMLBasicSegmentType{Cat: 'C', Str: tagStr})
+			}
+			continue
+		}
+		if skipStr != "" {
+			continue
+		}
+		switch tagStr {
+		case "head", "script", "style", "title":
+			if !strings.HasSuffix(htmlStr[m[6]:m[7]], "/") {
+				skipStr = tagStr
+			}
+			continue
+		}
+		attr := make(map[string]string)
+		for _, a := range htmlAttrRe.FindAllStringSubmatch(htmlStr[m[6]:m[7]], -1) {
+			attr[strings.ToLower(a[1])] = html.UnescapeString(a[2] + a[3] + a[4])
+		}
+		list = append(list, HTMLBasicSegmentType{Cat: 'O', Str: tagStr, Attr: attr})
+	}
+	putText(htmlStr[pos:])
+	return
+}
+
+// htmlStyleType is the style in effect within an element
+type htmlStyleType struct {
+	tagStr                          string
+	familyStr                       string
+	bold, italic, underline, strike bool
+	size                            float64 // font size in points
+	clr                             RGBType
+	hrefStr                         string
+	alignStr                        string
+	left, right                     float64 // indentation in user units
+	lineHt                          float64 // line height in user units, or zero
+	spacing                         float64 // line height as a multiple of the font size, or zero for LineSpacing
+}
+
+// htmlRunType is a run of text in one style; the text of runs in a core
+// font is translated to code page 1252
+type htmlRunType struct {
+	str                 string
+	familyStr, styleStr string
+	size                float64
+	clr                 RGBType
+	hrefStr             string
+}
+
+// htmlListType is a list whose items are being printed
+type htmlListType struct {
+	ordered bool
+	n       int // number of the next item
+	typeStr string
+}
+
+// htmlCellType is a cell of a table whose rows are being gathered
+type htmlCellType struct {
+	str      string
+	header   bool
+	rowSpan  int
+	alignStr string
+	widthStr string
+}
+
+// htmlTableType is a table whose rows are being gathered
+type htmlTableType struct {
+	style  htmlStyleType
+	border bool
+	rows   [][]htmlCellType
+	cell   *htmlCellType
+}
+
+// htmlRendererType holds the state of HTMLType.Write()
+type htmlRendererType struct {
+	h         *HTMLType
+	f         *Fpdf
+	tr        func(string) string
+	base      float64 // initial font size in points
+	stack     []htmlStyleType
+	runs      []htmlRunType
+	para      htmlStyleType // style of the block that the runs belong to
+	marker    *htmlRunType  // list item marker of the paragraph
+	headLevel int           // rank of the heading being gathered, or -1
+	titleStr  string        // text of the heading being gathered
+	space     float64       // space to leave before the next block
+	lists     []htmlListType
+	table     *htmlTableType
+}
+
+// htmlBlockTags are the elements that begin and end paragraphs
+var htmlBlockTags = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true,
+	"center": true, "dd": true, "div": true, "dl": true, "dt": true,
+	"figcaption": true, "figure": true, "footer": true, "h1": true, "h2": true,
+	"h3": true, "h4": true, "h5": true, "h6": true, "header": true, "li": true,
+	"main": true, "nav": true, "ol": true, "p": true, "pre": true,
+	"section": true, "ul": true,
+}
+
+// htmlSpacedTags are the blocks that are separated from their surroundings
+// by ParaSpacing
+var htmlSpacedTags = map[string]bool{
+	"blockquote": true, "dl": true, "figure": true, "h1": true, "h2": true,
+	"h3": true, "h4": true, "h5": true, "h6": true, "ol": true, "p": true,
+	"pre": true, "ul": true,
+}
+
+// htmlHeadingScale holds the sizes of headings H1 through H6 relative to the
+// size of the text
+var htmlHeadingScale = [6]float64{2, 1.5, 1.17, 1, 0.83, 0.67}
+
+// htmlFontSizes holds the sizes, relative to size 3, of the sizes 1 through
+// 7 of the FONT element
+var htmlFontSizes = [7]float64{8.0 / 12, 10.0 / 12, 1, 14.0 / 12, 18.0 / 12, 2, 3}
+
+// cur returns the style of the innermost element
+func (r *htmlRendererType) cur() htmlStyleType {
+	return r.stack[len(r.stack)-1]
+}
+
+// text adds a text segment to the paragraph or table cell being gathered.
+// Spaces are collapsed when the paragraph is printed.
+func (r *htmlRendererType) text(s string) {
+	s = strings.Map(func(ch rune) rune {
+		switch ch {
+		case '\n', '\r', '\t', '\f':
+			return ' '
+		}
+		return ch
+	}, s)
+	if r.table != nil {
+		if r.table.cell != nil {
+			r.table.cell.str += s
+		}
+		return
+	}
+	if r.headLevel >= 0 {
+		r.titleStr += s
+	}
+	if len(r.runs) == 0 && strings.TrimSpace(s) == "" {
+		return
+	}
+	r.addRun(s)
+}
+
+// addRun adds s to the paragraph in the current style
+func (r *htmlRendererType) addRun(s string) {
+	st := r.cur()
+	if len(r.runs) == 0 && r.marker == nil {
+		r.para = st
+	}
+	r.runs = append(r.runs, r.run(st, s))
+}
+
+// run returns a run of s in style st, falling back to a style of the family
+// that has been added if the requested one has not
+func (r *htmlRendererType) run(st htmlStyleType, s string) (run htmlRunType) {
+	f := r.f
+	styleStr := ""
+	if st.bold {
+		styleStr += "B"
+	}
+	if st.italic {
+		styleStr += "I"
+	}
+	if !f.coreFonts[st.familyStr] {
+		for _, fallback := range []string{styleStr, strings.Replace(styleStr, "I", "", 1), ""} {
+			if _, ok := f.fonts[st.familyStr+fallback]; ok {
+				styleStr = fallback
+				break
+			}
+		}
+	}
+	if f.coreFonts[st.familyStr] || f.fonts[st.familyStr+styleStr].Tp != "UTF8" {
+		s = r.tr(s)
+	}
+	if st.underline {
+		styleStr += "U"
+	}
+	if st.strike {
+		styleStr += "S"
+	}
+	return htmlRunType{str: s, familyStr: st.familyStr, styleStr: styleStr,
+		size: st.size, clr: st.clr, hrefStr: st.hrefStr}
+}
+
+// open handles the open tag tagStr
+func (r *htmlRendererType) open(tagStr string, attr map[string]string) {
+	h := r.h
+	if r.table != nil {
+		r.openTable(tagStr, attr)
+		return
+	}
+	switch tagStr {
+	case "br":
+		r.addRun("\n")
+		return
+	case "hr":
+		r.flush()
+		r.rule()
+		return
+	case "img":
+		r.flush()
+		r.image(attr)
+		return
+	case "table":
+		r.flush()
+		r.gap()
+		r.table = &htmlTableType{style: r.style(tagStr, attr), border: attr["border"] != "0"}
+		return
+	case "p":
+		r.autoClose("p")
+	case "li":
+		r.autoClose("li", "ul", "ol")
+	}
+	if htmlBlockTags[tagStr] {
+		r.flush()
+		if htmlSpacedTags[tagStr] && !(len(r.lists) > 0 && (tagStr == "ul" || tagStr == "ol")) {
+			r.gap()
+		}
+	}
+	st := r.style(tagStr, attr)
+	r.stack = append(r.stack, st)
+	switch tagStr {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		if h.Outline {
+			r.headLevel = int(tagStr[1] - '1')
+			r.titleStr = ""
+		}
+	case "ul", "ol":
+		l := htmlListType{ordered: tagStr == "ol", n: 1, typeStr: attr["type"]}
+		if n, err := strconv.Atoi(attr["start"]); err == nil {
+			l.n = n
+		}
+		r.lists = append(r.lists, l)
+	case "li":
+		if len(r.lists) > 0 {
+			l := &r.lists[len(r.lists)-1]
+			run := r.run(st, htmlListMarker(*l, len(r.lists)))
+			run.styleStr = strings.Trim(run.styleStr, "US")
+			run.hrefStr = ""
+			r.marker = &run
+			r.para = st
+			l.n++
+		}
+	}
+}
+
+// style returns the style of the element tagStr with attributes attr,
+// opened within the current element
+func (r *htmlRendererType) style(tagStr string, attr map[string]string) (st htmlStyleType) {
+	h := r.h
+	st = r.cur()
+	st.tagStr = tagStr
+	switch tagStr {
+	case "b", "strong", "th", "dt":
+		st.bold = true
+	case "i", "em", "cite", "var", "dfn":
+		st.italic = true
+	case "u", "ins":
+		st.underline = true
+	case "s", "strike", "del":
+		st.strike = true
+	case "code", "tt", "kbd", "samp", "pre":
+		st.familyStr = "courier"
+	case "center":
+		st.alignStr = "C"
+	case "blockquote":
+		st.left += h.Indent
+		st.right += h.Indent
+	case "dd", "ul", "ol":
+		st.left += h.Indent
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		st.bold = true
+		st.size = r.base * htmlHeadingScale[tagStr[1]-'1']
+	case "a":
+		if hrefStr, ok := attr["href"]; ok {
+			st.hrefStr = hrefStr
+			st.clr = h.ClrLink
+			st.underline = true
+		}
+	case "font":
+		if clr, ok := htmlColor(attr["color"]); ok {
+			st.clr = clr
+		}
+		if sizeStr := strings.TrimSpace(attr["size"]); sizeStr != "" {
+			n, err := strconv.Atoi(sizeStr)
+			if err == nil {
+				if sizeStr[0] == '+' || sizeStr[0] == '-' {
+					n += 3
+				}
+				n = int(math.Max(1, math.Min(7, float64(n))))
+				st.size = r.base * htmlFontSizes[n-1]
+			}
+		}
+		if faceStr, ok := attr["face"]; ok {
+			st.familyStr = r.family(faceStr, st.familyStr)
+		}
+	}
+	switch strings.ToLower(attr["align"]) {
+	case "left":
+		st.alignStr = "L"
+	case "center", "middle":
+		st.alignStr = "C"
+	case "right":
+		st.alignStr = "R"
+	case "justify":
+		st.alignStr = "J"
+	}
+	lineHtStr := ""
+	for key, valStr := range htmlStyleMap(attr["style"]) {
+		switch key {
+		case "line-height":
+			lineHtStr = valStr
+		case "color":
+			if clr, ok := htmlColor(valStr); ok {
+				st.clr = clr
+			}
+		case "font-family":
+			st.familyStr = r.family(valStr, st.familyStr)
+		case "font-size":
+			st.size = htmlFontSize(valStr, st.size, r.base)
+		case "font-style":
+			st.italic = valStr == "italic" || valStr == "oblique"
+		case "font-weight":
+			n, err := strconv.Atoi(valStr)
+			st.bold = valStr == "bold" || valStr == "bolder" || err == nil && n >= 600
+		case "text-align":
+			switch valStr {
+			case "left", "start":
+				st.alignStr = "L"
+			case "center":
+				st.alignStr = "C"
+			case "right", "end":
+				st.alignStr = "R"
+			case "justify":
+				st.alignStr = "J"
+			}
+		case "text-decoration", "text-decoration-line":
+			st.underline = strings.Contains(valStr, "underline")
+			st.strike = strings.Contains(valStr, "line-through")
+		}
+	}
+	// Lengths relative to the font are resolved once its size is known
+	if lineHtStr == "normal" {
+		st.lineHt, st.spacing = 0, 0
+	} else if n, err := strconv.ParseFloat(lineHtStr, 64); err == nil && n > 0 {
+		st.lineHt, st.spacing = 0, n
+	} else if pt, ok := htmlLength(lineHtStr, st.size, st.size); ok {
+		st.lineHt, st.spacing = pt/r.f.k, 0
+	}
+	return
+}
+
+// lineHt returns the height of the lines of a block in style st whose
+// largest font has the size fontHt in user units
+func (r *htmlRendererType) lineHt(st htmlStyleType, fontHt float64) float64 {
+	switch {
+	case st.lineHt > 0:
+		return st.lineHt
+	case st.spacing > 0:
+		return st.spacing * fontHt
+	}
+	return r.h.LineSpacing * fontHt
+}
+
+// family returns the first font family of the CSS list listStr that is
+// available, or defStr if there is none. Generic families are mapped to
+// the core fonts.
+func (r *htmlRendererType) family(listStr, defStr string) string {
+	for _, nameStr := range strings.Split(listStr, ",") {
+		nameStr = strings.ToLower(strings.Trim(strings.TrimSpace(nameStr), `"'`))
+		switch nameStr {
+		case "serif", "times", "times new roman":
+			return "times"
+		case "sans-serif", "arial", "helvetica":
+			return "helvetica"
+		case "monospace", "courier", "courier new":
+			return "courier"
+		}
+		for _, styleStr := range coreFontStyles {
+			if _, ok := r.f.fonts[nameStr+styleStr]; ok {
+				return nameStr
+			}
+		}
+	}
+	return defStr
+}
+
+// autoClose closes an open element tagStr that is implicitly ended by the
+// opening of another, unless one of the elements in limits encloses it
+func (r *htmlRendererType) autoClose(tagStr string, limits ...string) {
+	for j := len(r.stack) - 1; j > 0; j-- {
+		if r.stack[j].tagStr == tagStr {
+			r.close(tagStr)
+			return
+		}
+		for _, limitStr := range limits {
+			if r.stack[j].tagStr == limitStr {
+				return
+			}
+		}
+	}
+}
+
+// close handles the close tag tagStr. Tags that do not match an open
+// element are ignored.
+func (r *htmlRendererType) close(tagStr string) {
+	if r.table != nil {
+		// Elements within cells are not rendered
+		r.closeTable(tagStr)
+		return
+	}
+	j := len(r.stack) - 1
+	for j > 0 && r.stack[j].tagStr != tagStr {
+		j--
+	}
+	if j == 0 {
+		return
+	}
+	if htmlBlockTags[tagStr] {
+		r.flush()
+		if htmlSpacedTags[tagStr] && !(len(r.lists) > 1 && (tagStr == "ul" || tagStr == "ol")) {
+			r.gap()
+		}
+	}
+	for _, st := range r.stack[j:] {
+		if (st.tagStr == "ul" || st.tagStr == "ol") && len(r.lists) > 0 {
+			r.lists = r.lists[:len(r.lists)-1]
+		}
+	}
+	r.stack = r.stack[:j]
+	if tagStr[0] == 'h' && len(tagStr) == 2 {
+		r.headLevel = -1
+	}
+}
+
+// gap requests that the next block be separated from the previous one by
+// ParaSpacing
+func (r *htmlRendererType) gap() {
+	r.space = math.Max(r.space, r.h.ParaSpacing)
+}
+
+// putSpace moves the current position down by the space requested before
+// the next block, unless it is at the top of the page
+func (r *htmlRendererType) putSpace() {
+	f := r.f
+	if r.space > 0 && f.y > f.tMargin+1e-9 {
+		f.SetY(f.y + r.space)
+	}
+	r.space = 0
+}
+
+// bounds returns the left edge and width of the text of the current block
+func (r *htmlRendererType) bounds(st htmlStyleType) (left, wd float64) {
+	f := r.f
+	left = f.lMargin + st.left
+	wd = f.w - f.rMargin - st.right - left
+	return
+}
+
+// breakPage adds a page if content of kind kindStr and height ht does not fit below
+// the current position
+func (r *htmlRendererType) breakPage(kindStr string, ht float64) {
+	f := r.f
+	if f.y+ht > f.pageBreakTrigger && !f.inHeader && !f.inFooter && f.acceptPageBreakFor(kindStr, f.y, ht) {
+		x := f.x
+		f.AddPageFormat(f.curOrientation, f.curPageSize)
+		f.x = x
+	}
+}
+
+// flush prints the paragraph that has been gathered. Spaces are collapsed,
+// lines are wrapped to the width of the block and a list item marker is
+// printed to the left of the first line.
+func (r *htmlRendererType) flush() {
+	f := r.f
+	runs, marker := r.runs, r.marker
+	r.runs, r.marker = nil, nil
+	if f.err != nil {
+		return
+	}
+
+	// Break the runs into words
+	var words []frameWordType
+	var word frameWordType
+	newPara := false
+	endWord := func() {
+		if len(word.frags) > 0 || newPara {
+			word.newPara = newPara
+			words = append(words, word)
+			newPara = false
+		}
+		word = frameWordType{}
+	}
+	for j, run := range runs {
+		for _, ch := range run.str {
+			switch ch {
+			case ' ':
+				endWord()
+			case '\n':
+				endWord()
+				newPara = true
+			default:
+				n := len(word.frags)
+				if n == 0 || word.frags[n-1].run != j {
+					word.frags = append(word.frags, frameFragType{run: j})
+					n++
+				}
+				word.frags[n-1].str += string(ch)
+			}
+		}
+	}
+	endWord()
+	// A line break that ends a paragraph does not add an empty line
+	if n := len(words); n > 1 && words[n-1].newPara && len(words[n-1].frags) == 0 {
+		words = words[:n-1]
+	}
+	if len(words) == 0 {
+		if marker == nil {
+			return
+		}
+		words = []frameWordType{{}}
+	}
+
+	measure := func(frag frameFragType) float64 {
+		run := runs[frag.run]
+		return f.MeasureText(run.familyStr, run.styleStr, run.size, frag.str)
+	}
+	wordWd := func(w frameWordType) (wd float64) {
+		for _, frag := range w.frags {
+			wd += measure(frag)
+		}
+		return
+	}
+	spaceWd := func(w frameWordType) float64 {
+		if len(w.frags) == 0 {
+			return 0
+		}
+		return measure(frameFragType{" ", w.frags[len(w.frags)-1].run})
+	}
+	var curRun *htmlRunType
+	setRun := func(run *htmlRunType) {
+		if curRun == nil || curRun.familyStr != run.familyStr || curRun.styleStr != run.styleStr ||
+			curRun.size != run.size {
+			f.SetFont(run.familyStr, run.styleStr, run.size)
+		}
+		if curRun == nil || curRun.clr != run.clr {
+			f.SetTextColor(run.clr.R, run.clr.G, run.clr.B)
+		}
+		curRun = run
+	}
+
+	r.putSpace()
+	left, wd := r.bounds(r.para)
+	idx := 0
+	for idx < len(words) && f.err == nil {
+		// Gather the words of one line
+		start := idx
+		var lineWd, maxSize float64
+		if start == 0 && marker != nil {
+			maxSize = marker.size
+		}
+		for idx < len(words) {
+			w := words[idx]
+			if w.newPara && idx > start {
+				break
+			}
+			ww := wordWd(w)
+			sp := 0.0
+			if idx > start {
+				sp = spaceWd(words[idx-1])
+			}
+			if idx > start && lineWd+sp+ww > wd {
+				break
+			}
+			lineWd += sp + ww
+			for _, frag := range w.frags {
+				maxSize = math.Max(maxSize, runs[frag.run].size)
+			}
+			idx++
+		}
+		if maxSize == 0 {
+			// Empty line
+			maxSize = r.para.size
+		}
+		fontHt := maxSize / f.k
+		ht := r.lineHt(r.para, fontHt)
+		r.breakPage(PageBreakWrite, ht)
+
+		// Print the line
+		last := idx == len(words) || words[idx].newPara
+		gap := 0.0
+		x := left
+		switch r.para.alignStr {
+		case "C":
+			x += (wd - lineWd) / 2
+		case "R":
+			x += wd - lineWd
+		case "J":
+			if !last && idx-start > 1 {
+				gap = (wd - lineWd) / float64(idx-start-1)
+			}
+		}
+		baseY := f.y + 0.5*ht + 0.3*fontHt
+		if start == 0 && marker != nil {
+			setRun(marker)
+			markerWd := f.MeasureText(marker.familyStr, marker.styleStr, marker.size, marker.str)
+			f.Text(left-markerWd-0.5*marker.size/f.k, baseY, marker.str)
+		}
+		for k := start; k < idx; k++ {
+			if k > start {
+				prev, next := words[k-1].frags, words[k].frags
+				sp := spaceWd(words[k-1])
+				// Spaces within an underlined or linked run are printed too
+				if len(prev) > 0 && len(next) > 0 && next[0].run == prev[len(prev)-1].run &&
+					(runs[next[0].run].styleStr != strings.Trim(runs[next[0].run].styleStr, "US") ||
+						runs[next[0].run].hrefStr != "") {
+					run := &runs[next[0].run]
+					setRun(run)
+					f.Text(x, baseY, " ")
+					if run.hrefStr != "" {
+						f.LinkString(x, f.y, sp, ht, run.hrefStr)
+					}
+				}
+				x += sp + gap
+			}
+			for _, frag := range words[k].frags {
+				run := &runs[frag.run]
+				setRun(run)
+				fragWd := measure(frag)
+				f.Text(x, baseY, frag.str)
+				if run.hrefStr != "" {
+					f.LinkString(x, f.y, fragWd, ht, run.hrefStr)
+				}
+				x += fragWd
+			}
+		}
+		if start == 0 && r.headLevel >= 0 {
+			r.bookmark()
+		}
+		f.SetXY(f.lMargin, f.y+ht)
+	}
+}
+
+// bookmark adds the heading being gathered to the document outline
+func (r *htmlRendererType) bookmark() {
+	f := r.f
+	titleStr := strings.Join(strings.Fields(r.titleStr), " ")
+	if titleStr == "" {
+		return
+	}
+	if !f.isCurrentUTF8 {
+		titleStr = r.tr(titleStr)
+	}
+	r.h.headings = f.headingBookmark(r.h.headings, r.headLevel, titleStr)
+	r.headLevel = -1
+}
+
+// rule prints a horizontal line across the width of the current block
+func (r *htmlRendererType) rule() {
+	f := r.f
+	st := r.cur()
+	left, wd := r.bounds(st)
+	r.breakPage(PageBreakWrite, r.h.ParaSpacing)
+	y := f.y + r.h.ParaSpacing/2
+	f.Line(left, y, left+wd, y)
+	f.SetXY(f.lMargin, f.y+r.h.ParaSpacing)
+	r.space = 0
+}
+
+// image prints the image of an IMG element on its own line
+func (r *htmlRendererType) image(attr map[string]string) {
+	f := r.f
+	srcStr := attr["src"]
+	if srcStr == "" {
+		return
+	}
+	options := ImageOptions{ReadDpi: true, AltText: attr["alt"]}
+	info := f.RegisterImageOptions(srcStr, options)
+	if f.err != nil {
+		return
+	}
+	st := r.style("img", attr)
+	left, avail := r.bounds(st)
+	css := htmlStyleMap(attr["style"])
+	length := func(attrStr, cssStr string) (v float64) {
+		valStr := css[cssStr]
+		if valStr == "" {
+			valStr = attr[attrStr]
+		}
+		if pt, ok := htmlLength(valStr, avail*f.k, st.size); ok {
+			v = pt / f.k
+		}
+		return
+	}
+	w, h := length("width", "width"), length("height", "height")
+	switch {
+	case w == 0 && h == 0:
+		w, h = info.Width(), info.Height()
+	case w == 0:
+		w = h * info.Width() / info.Height()
+	case h == 0:
+		h = w * info.Height() / info.Width()
+	}
+	if w > avail {
+		h *= avail / w
+		w = avail
+	}
+	r.putSpace()
+	r.breakPage(PageBreakImage, h)
+	x := left
+	switch st.alignStr {
+	case "C":
+		x += (avail - w) / 2
+	case "R":
+		x += avail - w
+	}
+	f.ImageOptions(srcStr, x, f.y, w, h, false, options, 0, st.hrefStr)
+	f.SetXY(f.lMargin, f.y+h)
+}
+
+// openTable handles an open tag within a table
+func (r *htmlRendererType) openTable(tagStr string, attr map[string]string) {
+	t := r.table
+	switch tagStr {
+	case "tr":
+		t.cell = nil
+		t.rows = append(t.rows, nil)
+	case "td", "th":
+		if len(t.rows) == 0 {
+			t.rows = append(t.rows, nil)
+		}
+		c := htmlCellType{header: tagStr == "th", widthStr: attr["width"]}
+		c.rowSpan, _ = strconv.Atoi(attr["rowspan"])
+		if st := r.style(tagStr, attr); st.alignStr != r.cur().alignStr {
+			c.alignStr = st.alignStr
+		}
+		if valStr, ok := htmlStyleMap(attr["style"])["width"]; ok {
+			c.widthStr = valStr
+		}
+		n := len(t.rows) - 1
+		t.rows[n] = append(t.rows[n], c)
+		t.cell = &t.rows[n][len(t.rows[n])-1]
+	case "br":
+		if t.cell != nil {
+			t.cell.str += "\n"
+		}
+	case "img":
+		if t.cell != nil {
+			t.cell.str += attr["alt"]
+		}
+	}
+}
+
+// closeTable handles a close tag within a table
+func (r *htmlRendererType) closeTable(tagStr string) {
+	switch tagStr {
+	case "td", "th", "tr":
+		r.table.cell = nil
+	case "table":
+		r.putTable()
+	}
+}
+
+// putTable prints the table that has been gathered
+func (r *htmlRendererType) putTable() {
+	f := r.f
+	t := r.table
+	r.table = nil
+	left, avail := r.bounds(t.style)
+	// The number of columns is that of the widest row, counting the columns
+	// occupied by cells spanning rows from above
+	var spans []int
+	ncols := 0
+	for _, row := range t.rows {
+		n := len(row) + len(spans)
+		if n > ncols {
+			ncols = n
+		}
+		var next []int
+		for _, s := range spans {
+			if s > 1 {
+				next = append(next, s-1)
+			}
+		}
+		for _, c := range row {
+			if c.rowSpan > 1 {
+				next = append(next, c.rowSpan-1)
+			}
+		}
+		spans = next
+	}
+	if ncols == 0 {
+		return
+	}
+	cellStr := func(c htmlCellType) string {
+		lines := strings.Split(c.str, "\n")
+		for j, line := range lines {
+			lines[j] = strings.Join(strings.Fields(line), " ")
+		}
+		return r.run(t.style, strings.Join(lines, "\n")).str
+	}
+	run := r.run(t.style, "")
+	rows := t.rows
+	cols := make([]TableColumnType, ncols)
+	header := len(rows) > 0
+	for _, c := range rows[0] {
+		header = header && c.header && c.rowSpan <= 1
+	}
+	for j, c := range rows[0] {
+		if pt, ok := htmlLength(c.widthStr, avail*f.k, t.style.size); ok {
+			cols[j].W = pt / f.k
+		}
+		if header {
+			cols[j].Header = cellStr(c)
+		}
+	}
+	if header {
+		rows = rows[1:]
+	}
+	r.putSpace()
+	f.SetFont(run.familyStr, strings.Trim(run.styleStr, "US"), run.size)
+	f.SetTextColor(run.clr.R, run.clr.G, run.clr.B)
+	f.SetX(left)
+	tbl := f.NewTable(cols)
+	tbl.LineHt = r.lineHt(t.style, run.size/f.k)
+	tbl.Border = t.border
+	tbl.Stripe = false
+	for _, row := range rows {
+		cells := make([]TableCellType, len(row))
+		for j, c := range row {
+			cells[j] = TableCellType{Str: cellStr(c), RowSpan: c.rowSpan, Align: c.alignStr}
+		}
+		tbl.RowCells(cells...)
+	}
+	tbl.End()
+	f.SetX(f.lMargin)
+	r.gap()
+}
+
+// htmlStyleMap returns the declarations of the STYLE attribute styleStr,
+// with lower case properties and values
+func htmlStyleMap(styleStr string) map[string]string {
+	m := make(map[string]string)
+	for _, declStr := range strings.Split(styleStr, ";") {
+		if pos := strings.Index(declStr, ":"); pos > 0 {
+			keyStr := strings.ToLower(strings.TrimSpace(declStr[:pos]))
+			valStr := strings.TrimSpace(declStr[pos+1:])
+			if keyStr != "font-family" {
+				valStr = strings.ToLower(valStr)
+			}
+			m[keyStr] = strings.TrimSpace(strings.TrimSuffix(valStr, "!important"))
+		}
+	}
+	return m
+}
+
+var htmlLengthRe = regexp.MustCompile(`^(\d*\.?\d+)\s*(pt|px|em|rem|%|mm|cm|in)?$`)
+
+// htmlLength returns the length in points of the CSS or attribute value
+// valStr. Percentages are relative to pctRef and em units to emRef, both in
+// points; a number without a unit is in pixels.
+func htmlLength(valStr string, pctRef, emRef float64) (pt float64, ok bool) {
+	m := htmlLengthRe.FindStringSubmatch(strings.ToLower(strings.TrimSpace(valStr)))
+	if m == nil {
+		return
+	}
+	pt, _ = strconv.ParseFloat(m[1], 64)
+	switch m[2] {
+	case "", "px":
+		pt *= 0.75
+	case "em", "rem":
+		pt *= emRef
+	case "%":
+		pt *= pctRef / 100
+	case "mm":
+		pt *= 72 / 25.4
+	case "cm":
+		pt *= 72 / 2.54
+	case "in":
+		pt *= 72
+	}
+	return pt, pt > 0
+}
+
+// htmlFontSizeKeywords holds the sizes of the CSS font-size keywords
+// relative to medium
+var htmlFontSizeKeywords = map[string]float64{
+	"xx-small": 9.0 / 16, "x-small": 10.0 / 16, "small": 13.0 / 16,
+	"medium": 1, "large": 18.0 / 16, "x-large": 1.5, "xx-large": 2,
+}
+
+// htmlFontSize returns the size in points set by the CSS font-size value
+// valStr within an element of size parent; keywords are relative to base
+func htmlFontSize(valStr string, parent, base float64) float64 {
+	switch valStr {
+	case "smaller":
+		return parent / 1.2
+	case "larger":
+		return parent * 1.2
+	}
+	if scale, ok := htmlFontSizeKeywords[valStr]; ok {
+		return base * scale
+	}
+	if pt, ok := htmlLength(valStr, parent, parent); ok {
+		return pt
+	}
+	return parent
+}
+
+// htmlColorNames holds the basic color keywords of CSS
+var htmlColorNames = map[string]string{
+	"black": "#000000", "silver": "#c0c0c0", "gray": "#808080", "grey": "#808080",
+	"white": "#ffffff", "maroon": "#800000", "red": "#ff0000", "purple": "#800080",
+	"fuchsia": "#ff00ff", "green": "#008000", "lime": "#00ff00", "olive": "#808000",
+	"yellow": "#ffff00", "navy": "#000080", "blue": "#0000ff", "teal": "#008080",
+	"aqua": "#00ffff", "orange": "#ffa500",
+}
+
+var htmlRGBRe = regexp.MustCompile(`^rgba?\(\s*(\d+)\s*,\s*(\d+)\s*,\s*(\d+)`)
+
+// htmlColor returns the color specified by a color keyword, a hexadecimal
+// value such as #369 or #336699, or an rgb() function
+func htmlColor(s string) (clr RGBType, ok bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if hexStr, found := htmlColorNames[s]; found {
+		s = hexStr
+	}
+	if m := htmlRGBRe.FindStringSubmatch(s); m != nil {
+		s = m[1] + "," + m[2] + "," + m[3]
+	}
+	hexStr, err := parseTemplateColor(s)
+	if err != nil {
+		return
+	}
+	n, _ := strconv.ParseUint(hexStr[1:], 16, 32)
+	return RGBType{int(n >> 16), int(n >> 8 & 0xff), int(n & 0xff)}, true
+}
+
+// htmlListMarker returns the marker of the next item of list l, which is
+// nested at depth (1 for a list that is not nested)
+func htmlListMarker(l htmlListType, depth int) string {
+	if !l.ordered {
+		if depth%2 == 1 {
+			return "•"
+		}
+		return "–"
+	}
+	n := l.n
+	switch l.typeStr {
+	case "a", "A":
+		if n < 1 {
+			break
+		}
+		var s string
+		for ; n > 0; n = (n - 1) / 26 {
+			s = string(rune('a'+(n-1)%26)) + s
+		}
+		if l.typeStr == "A" {
+			s = strings.ToUpper(s)
+		}
+		return s + "."
+	case "i", "I":
+		if n < 1 || n > 3999 {
+			break
+		}
+		var s string
+		for j, v := range []int{1000, 900, 500, 400, 100, 90, 50, 40, 10, 9, 5, 4, 1} {
+			for ; n >= v; n -= v {
+				s += []string{"m", "cm", "d", "cd", "c", "xc", "l", "xl", "x", "ix", "v", "iv", "i"}[j]
+			}
+		}
+		if l.typeStr == "I" {
+			s = strings.ToUpper(s)
+		}
+		return s + "."
+	}
+	return strconv.Itoa(n) + "."
+}
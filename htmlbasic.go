@@ -95,13 +95,17 @@ func HTMLBasicTokenize(htmlStr string) (list []HTMLBasicSegmentType) {
 // only hyperlinks and bold, italic and underscore attributes. In the Link
 // structure, the ClrR, ClrG and ClrB fields (0 through 255) define the color
 // of hyperlinks. The Bold, Italic and Underscore values define the hyperlink
-// style.
+// style. If Outline is true, headings (H1 through H6) are added to the
+// document outline with Bookmark(), nested by rank; the headings themselves
+// are printed like other text.
 type HTMLBasicType struct {
 	pdf  *Fpdf
 	Link struct {
 		ClrR, ClrG, ClrB         int
 		Bold, Italic, Underscore bool
 	}
+	Outline  bool
+	headings []int // ranks of the headings that enclose the current one
 }
 
 // HTMLBasicNew returns an instance that facilitates writing basic HTML in the
@@ -121,8 +125,12 @@ func (f *Fpdf) HTMLBasicNew() (html HTMLBasicType) {
 // break occurs and text continues from the left margin. Upon method exit, the
 // current position is left at the end of the text.
 //
-// lineHt indicates the line height in the unit of measure specified in New().
+// lineHt indicates the line height in the unit of measure specified in New();
+// a negative value selects the line height set with SetLineHeight().
 func (html *HTMLBasicType) Write(lineHt float64, htmlStr string) {
+	if lineHt < 0 {
+		lineHt = html.pdf.GetLineHeight()
+	}
 	var boldLvl, italicLvl, underscoreLvl, linkBold, linkItalic, linkUnderscore int
 	var textR, textG, textB = html.pdf.GetTextColor()
 	var hrefStr string
@@ -162,7 +170,7 @@ func (html *HTMLBasicType) Write(lineHt float64, htmlStr string) {
 	list := HTMLBasicTokenize(htmlStr)
 	var ok bool
 	alignStr := "L"
-	for _, el := range list {
+	for j, el := range list {
 		switch el.Cat {
 		case 'T':
 			if len(hrefStr) > 0 {
@@ -199,6 +207,10 @@ func (html *HTMLBasicType) Write(lineHt float64, htmlStr string) {
 				if !ok {
 					hrefStr = ""
 				}
+			case "h1", "h2", "h3", "h4", "h5", "h6":
+				if html.Outline {
+					html.bookmarkHeading(int(el.Str[1]-'1'), el.Str, list[j+1:])
+				}
 			}
 		case 'C':
 			switch el.Str {
@@ -218,3 +230,45 @@ func (html *HTMLBasicType) Write(lineHt float64, htmlStr string) {
 		}
 	}
 }
+
+// bookmarkHeading adds the heading of rank level (0 for H1) that begins with
+// the segments in list, and ends with the close tag tagStr, to the document
+// outline.
+func (html *HTMLBasicType) bookmarkHeading(level int, tagStr string, list []HTMLBasicSegmentType) {
+	var b strings.Builder
+	for _, el := range list {
+		if el.Cat == 'C' && el.Str == tagStr {
+			break
+		}
+		if el.Cat == 'T' {
+			b.WriteString(el.Str)
+		}
+	}
+	titleStr := strings.Join(strings.Fields(b.String()), " ")
+	if titleStr == "" {
+		return
+	}
+	html.headings = html.pdf.headingBookmark(html.headings, level, titleStr)
+}
+
+// headingBookmark adds titleStr, the text of a heading of rank level (0 for
+// H1), to the document outline at the current position. headings holds the
+// ranks of the headings that enclose the current one; the updated list is
+// returned. The level of the heading in the outline is one below that of the
+// nearest preceding heading of a higher rank, since the outline cannot skip
+// levels.
+func (f *Fpdf) headingBookmark(headings []int, level int, titleStr string) []int {
+	n := len(headings)
+	for n > 0 && headings[n-1] >= level {
+		n--
+	}
+	headings = append(headings[:n], level)
+	outlineLevel := n
+	if n := len(f.outlines); n == 0 {
+		outlineLevel = 0
+	} else if outlineLevel > f.outlines[n-1].level+1 {
+		outlineLevel = f.outlines[n-1].level + 1
+	}
+	f.Bookmark(titleStr, outlineLevel, -1)
+	return headings
+}
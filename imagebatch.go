@@ -0,0 +1,85 @@
+package gofpdf
+
+import (
+	"runtime"
+	"sync"
+)
+
+// RegisterImagesOptions registers the images in the files of fileList with
+// options, like successive calls of RegisterImageOptions(), but reads,
+// decodes and compresses them concurrently in up to workers goroutines; zero
+// or a negative value selects the number of CPUs. This shortens the
+// registration of many images, such as the product photos of a catalog, on
+// machines with several cores. Files that have already been registered, and
+// repeated names, are not read again.
+//
+// The images are added to the document in the order of fileList once all of
+// them have been read, so the document is the same as if they had been
+// registered one by one, however the work is scheduled. If a file cannot be
+// registered, the error of the first such file in fileList is set and the
+// images that follow it are not registered. infoList holds the information
+// of the images in the order of fileList, with nil for the images that have
+// not been registered.
+func (f *Fpdf) RegisterImagesOptions(fileList []string, options ImageOptions, workers int) (infoList []*ImageInfoType) {
+	infoList = make([]*ImageInfoType, len(fileList))
+	if f.err != nil {
+		return
+	}
+	// Each image is parsed with an instance of its own, which holds the
+	// state that parsing uses
+	type jobType struct {
+		pdf *Fpdf
+		idx int
+	}
+	var jobs []jobType
+	pending := make(map[string]bool)
+	for j, fileStr := range fileList {
+		if _, ok := f.images[fileStr]; ok || pending[fileStr] {
+			continue
+		}
+		pending[fileStr] = true
+		jobs = append(jobs, jobType{idx: j, pdf: &Fpdf{
+			k:               f.k,
+			pdfVersion:      "1.3",
+			streamThreshold: f.streamThreshold,
+			images:          make(map[string]*ImageInfoType),
+		}})
+	}
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(jobs) {
+		workers = len(jobs)
+	}
+	ch := make(chan jobType)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range ch {
+				job.pdf.RegisterImageOptions(fileList[job.idx], options)
+			}
+		}()
+	}
+	for _, job := range jobs {
+		ch <- job
+	}
+	close(ch)
+	wg.Wait()
+
+	for _, job := range jobs {
+		if job.pdf.err != nil {
+			f.err = job.pdf.err
+			break
+		}
+		f.images[fileList[job.idx]] = job.pdf.images[fileList[job.idx]]
+		if job.pdf.pdfVersion > f.pdfVersion {
+			f.pdfVersion = job.pdf.pdfVersion
+		}
+	}
+	for j, fileStr := range fileList {
+		infoList[j] = f.images[fileStr]
+	}
+	return
+}
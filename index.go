@@ -0,0 +1,247 @@
+package gofpdf
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// indexMarkType records one occurrence of an index term
+type indexMarkType struct {
+	term, sub string
+	page      int
+}
+
+// indexEntryType is an index term or sub-term together with the sorted,
+// de-duplicated pages on which it was marked
+type indexEntryType struct {
+	term, sub string
+	pages     []int
+}
+
+// MarkIndexEntry records that termStr is discussed on the current page. The
+// term and page number are collected for the back-of-book index that is
+// printed when GenerateIndex() has been called. A sub-entry can be specified
+// by separating it from its main term with an exclamation mark, for example
+// "fonts!embedding". Marking the same term several times on one page has the
+// same effect as marking it once. MarkIndexEntry has no effect before the
+// first page has been added.
+func (f *Fpdf) MarkIndexEntry(termStr string) {
+	if f.err != nil || f.page == 0 {
+		return
+	}
+	var m indexMarkType
+	if pos := strings.Index(termStr, "!"); pos >= 0 {
+		m.term, m.sub = strings.TrimSpace(termStr[:pos]), strings.TrimSpace(termStr[pos+1:])
+	} else {
+		m.term = strings.TrimSpace(termStr)
+	}
+	if m.term == "" {
+		return
+	}
+	m.page = f.page
+	f.indexMarks = append(f.indexMarks, m)
+}
+
+// GenerateIndex arranges for an index of the terms recorded with
+// MarkIndexEntry() to be printed on new pages at the end of the document.
+// Because terms may be marked up to the moment the document is finished,
+// the index is not laid out until Close() is called, explicitly or by one of
+// the Output methods. Terms are sorted alphabetically without regard to case
+// and grouped under their initial letter. The pages of each term are listed
+// after it, with runs of consecutive pages combined into ranges such as
+// "4-7". Sub-entries are indented below their main term.
+//
+// titleStr, if not empty, is printed at the top of the first index page.
+// columns specifies the number of columns in which the index is set; a value
+// less than one is treated as one. The index is printed with the font family
+// and size that are current when the document is closed; if no font has been
+// set, 10 point Helvetica is used.
+func (f *Fpdf) GenerateIndex(titleStr string, columns int) {
+	if columns < 1 {
+		columns = 1
+	}
+	f.indexPending = true
+	f.indexTitle = titleStr
+	f.indexColumns = columns
+}
+
+// indexEntries consolidates the recorded marks into sorted index entries
+func (f *Fpdf) indexEntries() (list []indexEntryType) {
+	type keyType struct{ term, sub string }
+	pageMap := make(map[keyType]map[int]bool)
+	for _, m := range f.indexMarks {
+		// A sub-entry implies its main term, even if the term has no pages
+		main := keyType{m.term, ""}
+		if pageMap[main] == nil {
+			pageMap[main] = make(map[int]bool)
+		}
+		key := keyType{m.term, m.sub}
+		if pageMap[key] == nil {
+			pageMap[key] = make(map[int]bool)
+		}
+		pageMap[key][m.page] = true
+	}
+	for key, pages := range pageMap {
+		e := indexEntryType{term: key.term, sub: key.sub}
+		for pg := range pages {
+			e.pages = append(e.pages, pg)
+		}
+		sort.Ints(e.pages)
+		list = append(list, e)
+	}
+	less := func(a, b string) bool {
+		la, lb := strings.ToLower(a), strings.ToLower(b)
+		if la != lb {
+			return la < lb
+		}
+		return a < b
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.term != b.term {
+			return less(a.term, b.term)
+		}
+		return less(a.sub, b.sub)
+	})
+	return
+}
+
+// indexPageRanges formats a sorted list of page numbers, combining runs of
+// consecutive pages into ranges
+func indexPageRanges(pages []int) string {
+	var parts []string
+	for j := 0; j < len(pages); {
+		k := j
+		for k+1 < len(pages) && pages[k+1] == pages[k]+1 {
+			k++
+		}
+		str := strconv.Itoa(pages[j])
+		if k > j {
+			str += "-" + strconv.Itoa(pages[k])
+		}
+		parts = append(parts, str)
+		j = k + 1
+	}
+	return strings.Join(parts, ", ")
+}
+
+// indexGroup returns the heading under which term is listed; terms that do
+// not begin with a letter are collected under an empty heading
+func indexGroup(term string) string {
+	for _, r := range term {
+		if unicode.IsLetter(r) {
+			return string(unicode.ToUpper(r))
+		}
+		break
+	}
+	return ""
+}
+
+// indexWrap breaks str at spaces into lines that are no wider than wd with
+// the current font
+func (f *Fpdf) indexWrap(str string, wd float64) (lines []string) {
+	line := ""
+	for _, word := range strings.Fields(str) {
+		if line != "" && f.GetStringWidth(line+" "+word) > wd {
+			lines = append(lines, line)
+			line = word
+		} else if line != "" {
+			line += " " + word
+		} else {
+			line = word
+		}
+	}
+	return append(lines, line)
+}
+
+// putIndex prints the index requested with GenerateIndex() on new pages
+func (f *Fpdf) putIndex() {
+	f.indexPending = false
+	entries := f.indexEntries()
+	if len(entries) == 0 {
+		return
+	}
+	familyStr, ptSize := f.fontFamily, f.fontSizePt
+	if familyStr == "" {
+		familyStr, ptSize = "Helvetica", 10
+	}
+	autoBreak := f.autoPageBreak
+	f.autoPageBreak = false
+	f.AddPage()
+	if f.err != nil {
+		return
+	}
+	fontHt := ptSize / f.k
+	lineHt := 1.3 * fontHt
+	gap := 2 * fontHt
+	if f.indexTitle != "" {
+		f.SetFont(familyStr, "B", ptSize*1.6)
+		f.CellFormat(0, 1.6*lineHt, f.indexTitle, "", 1, "L", false, 0, "")
+		f.Ln(lineHt)
+	}
+	cols := f.indexColumns
+	colWd := (f.w - f.lMargin - f.rMargin - float64(cols-1)*gap) / float64(cols)
+	indent := 1.5 * fontHt
+	top := f.y
+	y := top
+	col := 0
+
+	// line prints str in the current column, moving to the next column or page
+	// when the bottom margin is reached
+	line := func(styleStr string, dx float64, str string) {
+		if y+lineHt > f.pageBreakTrigger && y > top {
+			col++
+			if col == cols {
+				col = 0
+				f.AddPage()
+				top = f.y
+			}
+			y = top
+		}
+		f.SetFont(familyStr, styleStr, ptSize)
+		x := f.lMargin + float64(col)*(colWd+gap) + dx
+		f.Text(x, y+0.5*lineHt+0.3*fontHt, str)
+		y += lineHt
+	}
+	entry := func(dx float64, str string) {
+		f.SetFont(familyStr, "", ptSize)
+		for j, ln := range f.indexWrap(str, colWd-dx) {
+			if j == 0 {
+				line("", dx, ln)
+			} else {
+				line("", dx+indent, ln)
+			}
+		}
+	}
+
+	group := "\x00"
+	for _, e := range entries {
+		if e.sub == "" {
+			if g := indexGroup(e.term); g != group {
+				if group != "\x00" && y > top {
+					y += lineHt / 2
+				}
+				group = g
+				if y+2*lineHt > f.pageBreakTrigger {
+					// Keep the heading with its first entry
+					y = f.pageBreakTrigger
+				}
+				if g != "" {
+					line("B", 0, g)
+				}
+			}
+			str := e.term
+			if len(e.pages) > 0 {
+				str += ", " + indexPageRanges(e.pages)
+			}
+			entry(0, str)
+		} else {
+			entry(indent, e.sub+", "+indexPageRanges(e.pages))
+		}
+	}
+	f.autoPageBreak = autoBreak
+	f.SetFont(familyStr, "", ptSize)
+	f.SetXY(f.lMargin, y)
+}
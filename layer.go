@@ -20,9 +20,10 @@ package gofpdf
 // http://www.fpdf.org/en/script/script97.php
 
 type layerType struct {
-	name    string
-	visible bool
-	objNum  int // object number
+	name       string
+	visible    bool
+	screenOnly bool // hidden when the document is printed or exported
+	objNum     int  // object number
 }
 
 type layerRecType struct {
@@ -87,7 +88,12 @@ func (f *Fpdf) layerPutLayers() {
 	for j, l := range f.layer.list {
 		f.newobj()
 		f.layer.list[j].objNum = f.n
-		f.outf("<</Type /OCG /Name %s>>", f.textstring(utf8toutf16(l.name)))
+		if l.screenOnly {
+			f.outf("<</Type /OCG /Name %s /Usage <</Print <</PrintState /OFF>> /Export <</ExportState /OFF>>>>>>",
+				f.textstring(utf8toutf16(l.name)))
+		} else {
+			f.outf("<</Type /OCG /Name %s>>", f.textstring(utf8toutf16(l.name)))
+		}
 		f.out("endobj")
 	}
 }
@@ -107,13 +113,23 @@ func (f *Fpdf) layerPutCatalog() {
 	if len(f.layer.list) > 0 {
 		onStr := ""
 		offStr := ""
+		screenStr := ""
 		for _, layer := range f.layer.list {
 			onStr += sprintf("%d 0 R ", layer.objNum)
 			if !layer.visible {
 				offStr += sprintf("%d 0 R ", layer.objNum)
 			}
+			if layer.screenOnly {
+				screenStr += sprintf("%d 0 R ", layer.objNum)
+			}
+		}
+		if screenStr != "" {
+			// Viewers apply the print and export states of the usage
+			// dictionary of these layers when printing and exporting
+			screenStr = sprintf(" /AS [<</Event /Print /OCGs [%s] /Category [/Print]>> "+
+				"<</Event /Export /OCGs [%s] /Category [/Export]>>]", screenStr, screenStr)
 		}
-		f.outf("/OCProperties <</OCGs [%s] /D <</OFF [%s] /Order [%s]>>>>", onStr, offStr, onStr)
+		f.outf("/OCProperties <</OCGs [%s] /D <</OFF [%s] /Order [%s]%s>>>>", onStr, offStr, onStr, screenStr)
 		if f.layer.openLayerPane {
 			f.out("/PageMode /UseOC")
 		}
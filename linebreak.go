@@ -0,0 +1,403 @@
+package gofpdf
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Minimum number of letters that hyphenation leaves before the first and
+// after the last hyphen of a word
+const (
+	hyphenLeftMin  = 2
+	hyphenRightMin = 3
+)
+
+// Kinds of line break dictionaries
+const (
+	dictHyphenation  = "hyphenation"
+	dictSegmentation = "segmentation"
+)
+
+// lineBreakDictType is a registered hyphenation or segmentation dictionary.
+// Its content is read from its source the first time it is needed and may be
+// released again to keep within the memory limit.
+type lineBreakDictType struct {
+	open     func() (io.ReadCloser, error)
+	nameStr  string
+	loaded   bool
+	patterns map[string][]byte // hyphenation values keyed by letters
+	hyphens  map[string][]int  // exceptions: word and its hyphen positions
+	words    map[string]bool   // words of a segmentation dictionary
+	maxLen   int               // length in runes of the longest word
+	size     int64             // approximate memory used when loaded
+	lastUse  int64
+}
+
+// lineBreakDicts holds the dictionaries registered with RegisterHyphenation()
+// and RegisterSegmentation()
+var lineBreakDicts struct {
+	sync.Mutex
+	list  map[string]*lineBreakDictType // keyed by kind and language
+	limit int64
+	used  int64
+	clock int64
+}
+
+func init() {
+	lineBreakDicts.list = make(map[string]*lineBreakDictType)
+}
+
+// SetLineBreakMemoryLimit limits the memory, in bytes, that the hyphenation
+// and segmentation dictionaries registered with RegisterHyphenation() and
+// RegisterSegmentation() may occupy. Dictionaries are read when they are
+// first used; when reading one exceeds the limit, the dictionaries that have
+// been used least recently are released and read again if they are needed
+// later. The amounts are estimates. A dictionary that is larger than the
+// limit by itself is still read. A limit of zero, the default, lets all
+// dictionaries that have been used remain in memory.
+func SetLineBreakMemoryLimit(size int64) {
+	lineBreakDicts.Lock()
+	defer lineBreakDicts.Unlock()
+	lineBreakDicts.limit = size
+	lineBreakEvict(nil)
+}
+
+// registerLineBreakDict registers a dictionary of the given kind, replacing
+// any registered for the same language
+func registerLineBreakDict(kindStr, langStr, nameStr string, open func() (io.ReadCloser, error)) {
+	lineBreakDicts.Lock()
+	defer lineBreakDicts.Unlock()
+	key := kindStr + ":" + localeKey(langStr)
+	if d, ok := lineBreakDicts.list[key]; ok && d.loaded {
+		lineBreakDicts.used -= d.size
+	}
+	lineBreakDicts.list[key] = &lineBreakDictType{open: open, nameStr: nameStr}
+}
+
+// lineBreakDict returns a copy of the dictionary of the given kind for
+// langStr, or for its language if there is none for the region, reading it
+// if necessary. The copy remains usable if the dictionary is released. nil is
+// returned if none has been registered.
+func lineBreakDict(kindStr, langStr string) (d *lineBreakDictType, err error) {
+	lineBreakDicts.Lock()
+	defer lineBreakDicts.Unlock()
+	key := localeKey(langStr)
+	var ok bool
+	if d, ok = lineBreakDicts.list[kindStr+":"+key]; !ok {
+		if pos := strings.Index(key, "-"); pos >= 0 {
+			d = lineBreakDicts.list[kindStr+":"+key[:pos]]
+		}
+	}
+	if d == nil {
+		return
+	}
+	lineBreakDicts.clock++
+	d.lastUse = lineBreakDicts.clock
+	if !d.loaded {
+		if err = d.load(kindStr); err != nil {
+			return nil, err
+		}
+		lineBreakDicts.used += d.size
+		lineBreakEvict(d)
+	}
+	cp := *d
+	return &cp, nil
+}
+
+// lineBreakEvict releases the least recently used dictionaries other than
+// keep until the memory limit is respected
+func lineBreakEvict(keep *lineBreakDictType) {
+	for lineBreakDicts.limit > 0 && lineBreakDicts.used > lineBreakDicts.limit {
+		var old *lineBreakDictType
+		for _, d := range lineBreakDicts.list {
+			if d.loaded && d != keep && (old == nil || d.lastUse < old.lastUse) {
+				old = d
+			}
+		}
+		if old == nil {
+			return
+		}
+		lineBreakDicts.used -= old.size
+		old.loaded = false
+		old.patterns, old.hyphens, old.words, old.size = nil, nil, nil, 0
+	}
+}
+
+// load reads the dictionary from its source. A hyphenation dictionary holds
+// Liang patterns separated by white space, such as "a1b" or ".ex3", as in the
+// hyph-*.pat.txt files of the TeX hyphenation project; words containing
+// hyphens, such as "ta-ble", are exceptions that are hyphenated as given. A
+// segmentation dictionary holds one word per line. In both, text following %
+// or # on a line is a comment.
+func (d *lineBreakDictType) load(kindStr string) (err error) {
+	rdr, err := d.open()
+	if err != nil {
+		return fmt.Errorf("unable to open %s dictionary %s: %s", kindStr, d.nameStr, err)
+	}
+	defer rdr.Close()
+	if kindStr == dictHyphenation {
+		d.patterns = make(map[string][]byte)
+		d.hyphens = make(map[string][]int)
+	} else {
+		d.words = make(map[string]bool)
+	}
+	scanner := bufio.NewScanner(rdr)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if pos := strings.IndexAny(line, "%#"); pos >= 0 {
+			line = line[:pos]
+		}
+		if kindStr == dictSegmentation {
+			if word := strings.TrimSpace(line); word != "" {
+				d.words[word] = true
+				if n := len([]rune(word)); n > d.maxLen {
+					d.maxLen = n
+				}
+				d.size += int64(len(word)) + 32
+			}
+			continue
+		}
+		for _, str := range strings.Fields(line) {
+			str = strings.ToLower(str)
+			if strings.Contains(str, "-") {
+				var pos []int
+				var letters []rune
+				for _, r := range str {
+					if r == '-' {
+						pos = append(pos, len(letters))
+					} else {
+						letters = append(letters, r)
+					}
+				}
+				d.hyphens[string(letters)] = pos
+				d.size += int64(len(str)+8*len(pos)) + 48
+				continue
+			}
+			var letters []rune
+			values := []byte{0}
+			for _, r := range str {
+				if r >= '0' && r <= '9' {
+					values[len(values)-1] = byte(r - '0')
+				} else {
+					letters = append(letters, r)
+					values = append(values, 0)
+				}
+			}
+			d.patterns[string(letters)] = values
+			d.size += int64(len(str)+len(values)) + 48
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		d.patterns, d.hyphens, d.words, d.size = nil, nil, nil, 0
+		return fmt.Errorf("unable to read %s dictionary %s: %s", kindStr, d.nameStr, err)
+	}
+	d.loaded = true
+	return
+}
+
+// hyphenate returns the positions, in runes, at which word may be hyphenated
+func (d *lineBreakDictType) hyphenate(word []rune) (list []int) {
+	lower := []rune(strings.ToLower(string(word)))
+	if len(lower) != len(word) || len(word) < hyphenLeftMin+hyphenRightMin {
+		return
+	}
+	if pos, ok := d.hyphens[string(lower)]; ok {
+		return pos
+	}
+	str := append(append([]rune{'.'}, lower...), '.')
+	values := make([]byte, len(str)+1)
+	for i := range str {
+		for j := i + 1; j <= len(str); j++ {
+			if pat, ok := d.patterns[string(str[i:j])]; ok {
+				for k, v := range pat {
+					if v > values[i+k] {
+						values[i+k] = v
+					}
+				}
+			}
+		}
+	}
+	// values[k+1] applies to the position before letter k of the word
+	for k := hyphenLeftMin; k <= len(word)-hyphenRightMin; k++ {
+		if values[k+1]%2 == 1 {
+			list = append(list, k)
+		}
+	}
+	return
+}
+
+// segment returns the positions, in runes, of the word boundaries within
+// run, a sequence of letters without spaces. Letters that begin no word of
+// the dictionary are joined to the unknown text around them.
+func (d *lineBreakDictType) segment(run []rune) (list []int) {
+	unknown := false
+	for pos := 0; pos < len(run); {
+		n := d.maxLen
+		if n > len(run)-pos {
+			n = len(run) - pos
+		}
+		for ; n > 0; n-- {
+			if d.words[string(run[pos:pos+n])] {
+				break
+			}
+		}
+		if n > 0 || !unknown {
+			if pos > 0 {
+				list = append(list, pos)
+			}
+		}
+		unknown = n == 0
+		if n == 0 {
+			n = 1
+		}
+		pos += n
+		for pos < len(run) && unicode.IsMark(run[pos]) {
+			pos++
+		}
+	}
+	return
+}
+
+// Hyphenate returns the parts of word between the points at which it may be
+// hyphenated, using the patterns registered for langStr with
+// RegisterHyphenation(). An error is returned if no patterns have been
+// registered for the language or they cannot be read.
+func Hyphenate(langStr, word string) (parts []string, err error) {
+	d, err := lineBreakDict(dictHyphenation, langStr)
+	if err == nil && d == nil {
+		err = fmt.Errorf("no hyphenation patterns have been registered for %s", langStr)
+	}
+	if err != nil {
+		return
+	}
+	runes := []rune(word)
+	prev := 0
+	for _, pos := range d.hyphenate(runes) {
+		parts = append(parts, string(runes[prev:pos]))
+		prev = pos
+	}
+	return append(parts, string(runes[prev:])), nil
+}
+
+// SegmentText returns txt divided into words, using the dictionary
+// registered for langStr with RegisterSegmentation(). This is used for
+// scripts such as Thai that do not separate words with spaces. Spaces are
+// kept with the word they follow. An error is returned if no dictionary has
+// been registered for the language or it cannot be read.
+func SegmentText(langStr, txt string) (words []string, err error) {
+	d, err := lineBreakDict(dictSegmentation, langStr)
+	if err == nil && d == nil {
+		err = fmt.Errorf("no segmentation dictionary has been registered for %s", langStr)
+	}
+	if err != nil {
+		return
+	}
+	runes := []rune(txt)
+	bounds := make(map[int]bool)
+	for _, pos := range lineBreakRuns(runes, d.segment) {
+		bounds[pos] = true
+	}
+	prev := 0
+	for k, r := range runes {
+		if k > prev && (bounds[k] || unicode.IsSpace(runes[k-1]) && !unicode.IsSpace(r)) {
+			words = append(words, string(runes[prev:k]))
+			prev = k
+		}
+	}
+	if prev < len(runes) {
+		words = append(words, string(runes[prev:]))
+	}
+	return
+}
+
+// lineBreakRuns applies fnc to each run of letters and marks in runes and
+// returns the positions it reports, offset to positions in runes
+func lineBreakRuns(runes []rune, fnc func(run []rune) []int) (list []int) {
+	start := -1
+	for k := 0; k <= len(runes); k++ {
+		if k < len(runes) && (unicode.IsLetter(runes[k]) || unicode.IsMark(runes[k])) {
+			if start < 0 {
+				start = k
+			}
+			continue
+		}
+		if start >= 0 {
+			for _, pos := range fnc(runes[start:k]) {
+				list = append(list, start+pos)
+			}
+			start = -1
+		}
+	}
+	return
+}
+
+// SetLineBreakLanguage specifies the language, such as "en-US" or "th", of
+// the text wrapped by SplitText() and SplitTextBreaks(). If hyphenation
+// patterns have been registered for the language with RegisterHyphenation(),
+// a word that does not fit on a line is hyphenated if possible; the lines
+// returned by SplitText() then end with a hyphen, and the breaks returned by
+// SplitTextBreaks() are marked with Hyphen. If a segmentation dictionary has
+// been registered with RegisterSegmentation(), lines may also break between
+// the words it finds in text without spaces. The dictionaries of the
+// language are read when text is first wrapped. An empty string, the
+// default, turns these features off. MultiCell() wraps text in the same way,
+// so that the lines it prints are those returned by SplitText().
+func (f *Fpdf) SetLineBreakLanguage(langStr string) {
+	f.lineBreakLang = langStr
+}
+
+// GetLineBreakLanguage returns the language set with SetLineBreakLanguage().
+func (f *Fpdf) GetLineBreakLanguage() string {
+	return f.lineBreakLang
+}
+
+// lineBreakPoints returns, for each cluster of the wrapped text, whether a
+// line may begin with it without a hyphen, by segmentation, or with one, by
+// hyphenation. Both are nil if no dictionary applies.
+func (f *Fpdf) lineBreakPoints(clusters []string) (wordAt, hyphenAt []bool) {
+	if f.lineBreakLang == "" {
+		return
+	}
+	hyph, err := lineBreakDict(dictHyphenation, f.lineBreakLang)
+	var seg *lineBreakDictType
+	if err == nil {
+		seg, err = lineBreakDict(dictSegmentation, f.lineBreakLang)
+	}
+	if err != nil {
+		f.SetError(err)
+		return
+	}
+	// Positions of runes that begin a cluster are mapped to the cluster
+	var runes []rune
+	var index []int
+	for k, cluster := range clusters {
+		for j, r := range cluster {
+			runes = append(runes, r)
+			if j == 0 {
+				index = append(index, k)
+			} else {
+				index = append(index, -1)
+			}
+		}
+	}
+	mark := func(fnc func(run []rune) []int) (list []bool) {
+		list = make([]bool, len(clusters))
+		for _, pos := range lineBreakRuns(runes, fnc) {
+			if n := index[pos]; n >= 0 {
+				list[n] = true
+			}
+		}
+		return
+	}
+	if seg != nil {
+		wordAt = mark(seg.segment)
+	}
+	if hyph != nil {
+		hyphenAt = mark(hyph.hyphenate)
+	}
+	return
+}
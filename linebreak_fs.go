@@ -0,0 +1,39 @@
+//go:build go1.16
+// +build go1.16
+
+package gofpdf
+
+import (
+	"io"
+	"io/fs"
+)
+
+// RegisterHyphenation registers the hyphenation patterns for the language
+// langStr, such as "en-US" or "de", that are found in the file nameStr of
+// fsys. The file, which may be embedded in the program with the embed
+// package, holds Liang patterns separated by white space as in the
+// hyph-*.pat.txt files of the TeX hyphenation project; words containing
+// hyphens, such as "ta-ble", may be included as exceptions. Text following %
+// or # on a line is ignored. The file is not read until the patterns are
+// first used, so programs only pay for the languages whose text they
+// actually hyphenate. Patterns registered for a language without a region
+// apply to all its regions. See SetLineBreakLanguage(), Hyphenate() and
+// SetLineBreakMemoryLimit().
+func RegisterHyphenation(langStr string, fsys fs.FS, nameStr string) {
+	registerLineBreakDict(dictHyphenation, langStr, nameStr, func() (io.ReadCloser, error) {
+		return fsys.Open(nameStr)
+	})
+}
+
+// RegisterSegmentation registers the word list for the language langStr,
+// such as "th", that is found in the file nameStr of fsys. The file holds one
+// word per line. It is used to find the boundaries of words in scripts, such
+// as Thai, Lao, Khmer or Japanese, that do not separate words with spaces,
+// so that lines can be broken between them. Like hyphenation patterns, the
+// list is read when it is first used. See RegisterHyphenation(),
+// SegmentText() and SetLineBreakMemoryLimit().
+func RegisterSegmentation(langStr string, fsys fs.FS, nameStr string) {
+	registerLineBreakDict(dictSegmentation, langStr, nameStr, func() (io.ReadCloser, error) {
+		return fsys.Open(nameStr)
+	})
+}
@@ -0,0 +1,83 @@
+//go:build go1.16
+// +build go1.16
+
+package gofpdf_test
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/headlands-org/gofpdf"
+)
+
+// ExampleRegisterHyphenation demonstrates hyphenation and word segmentation
+// with dictionaries that are read from a file system when they are first
+// used. A program would normally embed the files of the languages it
+// supports with the embed package; this one holds a few patterns of Liang's
+// thesis and a handful of Thai words.
+func ExampleRegisterHyphenation() {
+	fsys := fstest.MapFS{
+		"hyph-en.pat.txt": {Data: []byte("% Liang's example patterns\n" +
+			"hy3ph he2n hena4 hen5at 1na n2at 1tio 2io\n" +
+			"ta-ble\n")},
+		"th.dic": {Data: []byte("สวัสดี\nครับ\nภาษา\nไทย\n")},
+	}
+	gofpdf.RegisterHyphenation("en", fsys, "hyph-en.pat.txt")
+	gofpdf.RegisterSegmentation("th", fsys, "th.dic")
+	gofpdf.SetLineBreakMemoryLimit(1 << 20)
+	parts, err := gofpdf.Hyphenate("en-US", "hyphenation")
+	fmt.Println(parts, err)
+	words, err := gofpdf.SegmentText("th", "สวัสดีครับ ภาษาไทย")
+	fmt.Printf("%q %v\n", words, err)
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.SetLineBreakLanguage("en-GB")
+	for _, line := range pdf.SplitText("On hyphenation of a table", 28) {
+		fmt.Println(line)
+	}
+	fmt.Println(pdf.Error())
+	// Output:
+	// [hy phen ation] <nil>
+	// ["สวัสดี" "ครับ " "ภาษา" "ไทย"] <nil>
+	// On hyphen-
+	// ation of a ta-
+	// ble
+	// <nil>
+}
+
+// TestMultiCellHyphenation checks that MultiCell() and MultiCellDropCap()
+// hyphenate the lines they print as SplitText() does
+func TestMultiCellHyphenation(t *testing.T) {
+	fsys := fstest.MapFS{
+		"hyph-en.pat.txt": {Data: []byte("hy3ph he2n hena4 hen5at 1na n2at 1tio 2io\nta-ble\n")},
+	}
+	gofpdf.RegisterHyphenation("en", fsys, "hyph-en.pat.txt")
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.SetLineBreakLanguage("en")
+	pdf.AddPage()
+	txtStr := "On hyphenation of a table"
+	lines := pdf.SplitText(txtStr, 28)
+	pdf.MultiCell(28, 5, txtStr, "1", "J", false)
+	dc := gofpdf.NewDropCap()
+	dc.Lines = 2
+	pdf.MultiCellDropCap(34, 5, "A hyphenation of a table", "L", dc)
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var printed []string
+	for _, m := range regexp.MustCompile(`\(([^)]*)\) ?Tj`).FindAllStringSubmatch(buf.String(), -1) {
+		printed = append(printed, m[1])
+	}
+	expected := append(lines, "A", " hyphen-", "ation of a", "table")
+	if strings.Join(printed, "|") != strings.Join(expected, "|") {
+		t.Errorf("printed lines %q, expected %q", printed, expected)
+	}
+}
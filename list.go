@@ -0,0 +1,53 @@
+package gofpdf
+
+// ListItem prints txtStr as an item of a bulleted or numbered list. bulletStr,
+// such as "•" or "3.", is printed in a column of width indent beside the
+// first line of the item, and the text is wrapped with MultiCell() in the
+// remaining width between the current horizontal position and the right
+// margin, with the line height h, so that its lines are aligned with one
+// another rather than with the bullet. alignStr is passed to MultiCell().
+// Afterwards the current position is below the item at the horizontal
+// position where it began, ready for the next item; nested lists can be
+// printed by moving the position right by the indent of the outer list.
+//
+// Normally the bullet column is on the left of the text and the bullet is
+// aligned on its right side, next to the text. In right-to-left mode (see
+// RTL()) the layout is mirrored: the bullet column is on the right of the
+// text with the bullet aligned on its left side, and text without an explicit
+// alignment is aligned on the right. SetRTLMirroring(false) keeps the
+// left-to-right layout.
+//
+// If the first line of the item does not fit on the page, the page is broken
+// before the bullet is printed, subject to the application's decision about
+// content of the kind PageBreakBlock.
+func (f *Fpdf) ListItem(indent, h float64, bulletStr, txtStr, alignStr string) {
+	if f.err != nil {
+		return
+	}
+	x := f.x
+	w := f.w - f.rMargin - x
+	if indent <= 0 || indent >= w {
+		f.SetErrorf("invalid list indent %.2f", indent)
+		return
+	}
+	if f.y+h > f.pageBreakTrigger && !f.inHeader && !f.inFooter && f.acceptPageBreakFor(PageBreakBlock, f.y, h) {
+		f.AddPageFormat(f.curOrientation, f.curPageSize)
+		if f.err != nil {
+			return
+		}
+	}
+	y := f.y
+	textX, bulletX, bulletAlignStr := x+indent, x, "R"
+	if f.rtlMirrored() {
+		textX, bulletX, bulletAlignStr = x, x+w-indent, "L"
+	}
+	f.SetXY(bulletX, y)
+	f.CellFormat(indent, h, bulletStr, "", 0, bulletAlignStr, false, 0, "")
+	// MultiCell() returns to the left margin at the start of each line
+	lMargin := f.lMargin
+	f.lMargin = textX
+	f.SetXY(textX, y)
+	f.MultiCell(w-indent, h, txtStr, "", alignStr, false)
+	f.lMargin = lMargin
+	f.x = x
+}
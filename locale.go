@@ -0,0 +1,256 @@
+package gofpdf
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LocaleType describes how numbers, amounts of money and dates are written
+// in a language and region. The predefined locales can be retrieved with
+// GetLocale(); others can be added with RegisterLocale().
+//
+// Date patterns are made up of the following fields, which may be separated
+// by other characters; text enclosed in single quotes is copied literally.
+//
+//	d     day of the month, 2
+//	dd    day of the month with two digits, 02
+//	M     month, 1
+//	MM    month with two digits, 01
+//	MMM   abbreviated month name from ShortMonths, Jan
+//	MMMM  month name from Months, January
+//	yy    year with two digits, 06
+//	yyyy  year, 2006
+type LocaleType struct {
+	// Language tag, such as "en-US" or "de-DE"
+	Tag string
+	// Decimal separator and the separator of groups of three digits
+	DecimalSep, GroupSep string
+	// Whether the currency symbol precedes the amount and whether a space
+	// separates them
+	CurrencyBefore, CurrencySpace bool
+	// Date patterns of the "short", "medium" and "long" styles
+	ShortDate, MediumDate, LongDate string
+	// Names and abbreviated names of the months, beginning with January
+	Months, ShortMonths []string
+}
+
+// currencies holds the symbols and number of decimal places of common
+// currencies keyed by ISO 4217 code
+var currencies = map[string]struct {
+	symbol   string
+	decimals int
+}{
+	"BRL": {"R$", 2},
+	"CHF": {"CHF", 2},
+	"CNY": {"CN¥", 2},
+	"EUR": {"€", 2},
+	"GBP": {"£", 2},
+	"JPY": {"¥", 0},
+	"SEK": {"kr", 2},
+	"USD": {"$", 2},
+}
+
+// locales holds the locales registered with RegisterLocale()
+var locales struct {
+	sync.Mutex
+	list map[string]LocaleType
+}
+
+func init() {
+	enMonths := []string{"January", "February", "March", "April", "May", "June", "July",
+		"August", "September", "October", "November", "December"}
+	enShort := []string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"}
+	deMonths := []string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli",
+		"August", "September", "Oktober", "November", "Dezember"}
+	deShort := []string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."}
+	frMonths := []string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet",
+		"août", "septembre", "octobre", "novembre", "décembre"}
+	frShort := []string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."}
+	esMonths := []string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio",
+		"agosto", "septiembre", "octubre", "noviembre", "diciembre"}
+	esShort := []string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"}
+	itMonths := []string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio",
+		"agosto", "settembre", "ottobre", "novembre", "dicembre"}
+	itShort := []string{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"}
+	nlMonths := []string{"januari", "februari", "maart", "april", "mei", "juni", "juli",
+		"augustus", "september", "oktober", "november", "december"}
+	nlShort := []string{"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec"}
+	ptMonths := []string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho",
+		"agosto", "setembro", "outubro", "novembro", "dezembro"}
+	ptShort := []string{"jan", "fev", "mar", "abr", "mai", "jun", "jul", "ago", "set", "out", "nov", "dez"}
+	svMonths := []string{"januari", "februari", "mars", "april", "maj", "juni", "juli",
+		"augusti", "september", "oktober", "november", "december"}
+	svShort := []string{"jan.", "feb.", "mars", "apr.", "maj", "juni", "juli", "aug.", "sep.", "okt.", "nov.", "dec."}
+	jaMonths := []string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"}
+	// Non-breaking spaces separate groups of digits where a space is used,
+	// so that numbers are not broken across lines
+	nbsp := "\u00a0"
+	locales.list = make(map[string]LocaleType)
+	for _, loc := range []LocaleType{
+		{"en-US", ".", ",", true, false, "M/d/yyyy", "MMM d, yyyy", "MMMM d, yyyy", enMonths, enShort},
+		{"en-GB", ".", ",", true, false, "dd/MM/yyyy", "d MMM yyyy", "d MMMM yyyy", enMonths, enShort},
+		{"de-DE", ",", ".", false, true, "dd.MM.yyyy", "dd.MM.yyyy", "d. MMMM yyyy", deMonths, deShort},
+		{"fr-FR", ",", nbsp, false, true, "dd/MM/yyyy", "d MMM yyyy", "d MMMM yyyy", frMonths, frShort},
+		{"es-ES", ",", ".", false, true, "d/M/yyyy", "d MMM yyyy", "d 'de' MMMM 'de' yyyy", esMonths, esShort},
+		{"it-IT", ",", ".", false, true, "dd/MM/yyyy", "d MMM yyyy", "d MMMM yyyy", itMonths, itShort},
+		{"nl-NL", ",", ".", true, true, "dd-MM-yyyy", "d MMM yyyy", "d MMMM yyyy", nlMonths, nlShort},
+		{"pt-BR", ",", ".", true, true, "dd/MM/yyyy", "d 'de' MMM 'de' yyyy", "d 'de' MMMM 'de' yyyy", ptMonths, ptShort},
+		{"sv-SE", ",", nbsp, false, true, "yyyy-MM-dd", "d MMM yyyy", "d MMMM yyyy", svMonths, svShort},
+		{"ja-JP", ".", ",", true, false, "yyyy/MM/dd", "yyyy/MM/dd", "yyyy'年'M'月'd'日'", jaMonths, jaMonths},
+	} {
+		locales.list[strings.ToLower(loc.Tag)] = loc
+		// The first locale of each language is also its default
+		lang := strings.ToLower(loc.Tag[:2])
+		if _, ok := locales.list[lang]; !ok {
+			locales.list[lang] = loc
+		}
+	}
+}
+
+// RegisterLocale makes loc available to GetLocale() under its tag,
+// replacing any locale registered under the same tag, including the
+// predefined locales en-US, en-GB, de-DE, fr-FR, es-ES, it-IT, nl-NL, pt-BR,
+// sv-SE and ja-JP. Tags are not case sensitive.
+func RegisterLocale(loc LocaleType) {
+	locales.Lock()
+	locales.list[localeKey(loc.Tag)] = loc
+	locales.Unlock()
+}
+
+// GetLocale returns the locale registered under tagStr, such as "de-DE". If
+// there is none, the default locale of the language is returned, so that
+// "de-AT" and "de" select "de-DE"; ok is false if the language has no
+// locale either. Underscores are accepted in place of hyphens.
+func GetLocale(tagStr string) (loc LocaleType, ok bool) {
+	key := localeKey(tagStr)
+	locales.Lock()
+	defer locales.Unlock()
+	if loc, ok = locales.list[key]; !ok {
+		if pos := strings.Index(key, "-"); pos > 0 {
+			loc, ok = locales.list[key[:pos]]
+		}
+	}
+	return
+}
+
+// localeKey returns the normalized form of a language tag
+func localeKey(tagStr string) string {
+	return strings.ToLower(strings.Replace(tagStr, "_", "-", -1))
+}
+
+// FormatNumber returns v written with the specified number of decimal
+// places, rounded, with the locale's decimal and group separators. A
+// negative number of decimal places writes as many as are needed to
+// represent v exactly.
+func (loc LocaleType) FormatNumber(v float64, decimals int) string {
+	abs := math.Abs(v)
+	if decimals >= 0 {
+		// Round halves away from zero
+		p := math.Pow(10, float64(decimals))
+		abs = math.Round(abs*p) / p
+	}
+	str := strconv.FormatFloat(abs, 'f', decimals, 64)
+	intStr, fracStr := str, ""
+	if pos := strings.IndexByte(str, '.'); pos >= 0 {
+		intStr, fracStr = str[:pos], str[pos+1:]
+	}
+	var b strings.Builder
+	if v < 0 && strings.Trim(str, "0.") != "" {
+		b.WriteByte('-')
+	}
+	for j := 0; j < len(intStr); j++ {
+		if j > 0 && (len(intStr)-j)%3 == 0 {
+			b.WriteString(loc.GroupSep)
+		}
+		b.WriteByte(intStr[j])
+	}
+	if fracStr != "" {
+		b.WriteString(loc.DecimalSep)
+		b.WriteString(fracStr)
+	}
+	return b.String()
+}
+
+// FormatCurrency returns the amount v in the currency with the ISO 4217 code
+// codeStr, such as "EUR", written with the currency's symbol and number of
+// decimal places as the locale places them. Currencies whose symbol is not
+// known are written with their code. The symbol is separated from the amount,
+// where the locale separates them, by a non-breaking space.
+func (loc LocaleType) FormatCurrency(v float64, codeStr string) string {
+	codeStr = strings.ToUpper(codeStr)
+	cur, ok := currencies[codeStr]
+	if !ok {
+		cur.symbol, cur.decimals = codeStr, 2
+	}
+	numStr := loc.FormatNumber(v, cur.decimals)
+	sign := ""
+	if strings.HasPrefix(numStr, "-") {
+		sign, numStr = "-", numStr[1:]
+	}
+	space := ""
+	if loc.CurrencySpace || !ok {
+		space = "\u00a0"
+	}
+	if loc.CurrencyBefore {
+		return sign + cur.symbol + space + numStr
+	}
+	return sign + numStr + space + cur.symbol
+}
+
+// FormatDate returns the date of tm written in the locale's "short",
+// "medium" or "long" style. Any other value of styleStr is used as a date
+// pattern; see LocaleType.
+func (loc LocaleType) FormatDate(tm time.Time, styleStr string) string {
+	pattern := styleStr
+	switch styleStr {
+	case "short":
+		pattern = loc.ShortDate
+	case "medium":
+		pattern = loc.MediumDate
+	case "long":
+		pattern = loc.LongDate
+	}
+	var b strings.Builder
+	month := int(tm.Month())
+	for j := 0; j < len(pattern); {
+		c := pattern[j]
+		if c == '\'' {
+			end := strings.IndexByte(pattern[j+1:], '\'')
+			if end < 0 {
+				end = len(pattern) - j - 1
+			}
+			b.WriteString(pattern[j+1 : j+1+end])
+			j += end + 2
+			continue
+		}
+		if c != 'd' && c != 'M' && c != 'y' {
+			b.WriteByte(c)
+			j++
+			continue
+		}
+		n := 1
+		for j+n < len(pattern) && pattern[j+n] == c {
+			n++
+		}
+		j += n
+		switch {
+		case c == 'd':
+			b.WriteString(fmt.Sprintf("%0*d", n, tm.Day()))
+		case c == 'M' && n == 3 && len(loc.ShortMonths) >= 12:
+			b.WriteString(loc.ShortMonths[month-1])
+		case c == 'M' && n >= 4 && len(loc.Months) >= 12:
+			b.WriteString(loc.Months[month-1])
+		case c == 'M':
+			b.WriteString(fmt.Sprintf("%0*d", n, month))
+		case n == 2:
+			b.WriteString(fmt.Sprintf("%02d", tm.Year()%100))
+		default:
+			b.WriteString(strconv.Itoa(tm.Year()))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,116 @@
+package gofpdf
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// ValidationDataType holds the data with which AddValidationData() makes
+// the signatures of a document verifiable without online lookups.
+type ValidationDataType struct {
+	// Certificates of the signatories and of the authorities that issued
+	// them, up to trusted roots, along with the certificates of the
+	// responders and issuers of the OCSP responses and CRLs
+	Certificates []*x509.Certificate
+	// DER-encoded OCSP responses and certificate revocation lists showing
+	// that the certificates had not been revoked when the document was
+	// signed
+	OCSPs, CRLs [][]byte
+}
+
+var ltvDSSRe = regexp.MustCompile(`/DSS (\d+) 0 R`)
+
+// AddValidationData appends the validation data vd to the document pdf,
+// which has been signed with SignPDF() or timestamped with TimestampPDF(),
+// and returns the updated document. The data is stored in the document
+// security store (DSS) of the document, as specified for long-term
+// validation by PAdES (PAdES-LTV): with it, a reader can verify the
+// signatures years later, after the certificates have expired or their
+// authorities have stopped answering queries. The OCSP responses and CRLs
+// are obtained by the application from the authorities that issued the
+// certificates, for example with the package golang.org/x/crypto/ocsp. The
+// store is not tied to particular signatures with validation-related
+// information (VRI) entries, which are optional.
+//
+// Like a signature, the data is appended as an incremental update, which
+// leaves the signatures of the document intact. Data stored by an earlier
+// call is kept.
+func AddValidationData(pdf []byte, vd ValidationDataType) ([]byte, error) {
+	if len(vd.Certificates)+len(vd.OCSPs)+len(vd.CRLs) == 0 {
+		return nil, fmt.Errorf("no validation data to add")
+	}
+	if !bytes.Contains(pdf, []byte("/Type /Sig ")) && !bytes.Contains(pdf, []byte("/Type /DocTimeStamp ")) {
+		return nil, fmt.Errorf("document has not been signed or timestamped")
+	}
+	u, err := newUpdate(pdf)
+	if err != nil {
+		return nil, err
+	}
+	catalog, err := updateObject(pdf, u.root)
+	if err != nil {
+		return nil, err
+	}
+	keys := []string{"Certs", "OCSPs", "CRLs"}
+	arrays := make([]fmtBuffer, len(keys))
+	if m := ltvDSSRe.FindSubmatch(catalog); m != nil {
+		// The store of an earlier update
+		num, _ := strconv.Atoi(string(m[1]))
+		dss, err := updateObject(pdf, num)
+		if err != nil {
+			return nil, err
+		}
+		for j, keyStr := range keys {
+			re := regexp.MustCompile(`/` + keyStr + ` \[([^\]]*)\]`)
+			if m = re.FindSubmatch(dss); m != nil && len(bytes.TrimSpace(m[1])) > 0 {
+				arrays[j].printf(" %s", bytes.TrimSpace(m[1]))
+			}
+		}
+	}
+	var certs [][]byte
+	for _, cert := range vd.Certificates {
+		dup := false
+		for _, raw := range certs {
+			dup = dup || bytes.Equal(raw, cert.Raw)
+		}
+		if !dup {
+			certs = append(certs, cert.Raw)
+		}
+	}
+	for j, list := range [][][]byte{certs, vd.OCSPs, vd.CRLs} {
+		for _, data := range list {
+			arrays[j].printf(" %d 0 R", u.putstream(data))
+		}
+	}
+	var dss fmtBuffer
+	dss.printf("<</Type /DSS")
+	for j, keyStr := range keys {
+		if arrays[j].Len() > 0 {
+			dss.printf(" /%s [%s]", keyStr, bytes.TrimSpace(arrays[j].Bytes()))
+		}
+	}
+	dss.printf(">>")
+	dssNum := u.newobj()
+	u.putobj(dssNum, dss.String())
+
+	// The catalog refers to the store and declares the extension of PDF 1.7
+	// that introduced it
+	dssStr := fmt.Sprintf("/DSS %d 0 R", dssNum)
+	var cat fmtBuffer
+	if ltvDSSRe.Match(catalog) {
+		cat.printf("%s", ltvDSSRe.ReplaceAll(catalog[:len(catalog)-2], []byte(dssStr)))
+	} else {
+		cat.printf("%s %s", bytes.TrimRight(catalog[:len(catalog)-2], "\r\n "), dssStr)
+	}
+	if !bytes.Contains(catalog, []byte("/Extensions")) {
+		cat.printf(" /Extensions <</ESIC <</BaseVersion /1.7 /ExtensionLevel 5>>>>")
+	}
+	if len(pdf) > 8 && string(pdf[5:8]) < "1.7" && !bytes.Contains(catalog, []byte("/Version")) {
+		cat.printf(" /Version /1.7")
+	}
+	cat.printf(">>")
+	u.putobj(u.root, cat.String())
+	return u.finish(), nil
+}
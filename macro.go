@@ -0,0 +1,135 @@
+package gofpdf
+
+import "bytes"
+
+// macroType is a graphics snippet recorded with RecordMacro()
+type macroType struct {
+	content []byte  // page content in the coordinates of the recording page
+	hPt     float64 // height of the recording page in points
+}
+
+// RecordMacro records the drawing operations performed by fn under
+// nameStr, replacing any macro recorded under the same name, so that they can
+// be replayed with PlayMacro() at any position and scale. This suits small
+// graphics that are repeated many times, such as bullets, icons and
+// checkmarks, without the overhead of a template (see CreateTemplate()): the
+// operations are written to the page content each time the macro is played,
+// so no object is added to the document.
+//
+// fn draws the macro with the methods of f, relative to the origin (0, 0) at
+// the upper left corner of the page, in the unit of measure of the document.
+// Nothing that fn draws appears on the current page, and the drawing state,
+// such as colors, font, line width and current position, is restored after
+// fn returns. fn should therefore set the colors, font and line width the
+// macro depends on; a macro that leaves them unset uses those in effect when
+// it is played. Links, annotations, form fields and named elements are not
+// recorded, and fn must not add a page. A page must have been added before a macro is recorded.
+func (f *Fpdf) RecordMacro(nameStr string, fn func()) {
+	if f.err != nil {
+		return
+	}
+	if f.page <= 0 {
+		f.SetErrorf("cannot record a macro without first adding a page")
+		return
+	}
+	page, buf := f.page, f.pages[f.page]
+	pos, linkCount, attachCount := buf.Len(), len(f.pageLinks[f.page]), len(f.pageAttachments[f.page])
+	fieldCount, elemCount := len(f.formFields), len(f.elements)
+	st, autoPageBreak := f.drawState(), f.autoPageBreak
+	f.autoPageBreak = false
+	f.out("q")
+	fn()
+	f.out("Q")
+	f.autoPageBreak = autoPageBreak
+	if f.err != nil {
+		return
+	}
+	if f.page != page {
+		f.SetErrorf("macro %s adds a page", nameStr)
+		return
+	}
+	m := macroType{content: bytes.TrimSuffix(append([]byte(nil), buf.Bytes()[pos:]...), []byte("\n")), hPt: f.hPt}
+	buf.Truncate(pos)
+	f.pageLinks[page] = f.pageLinks[page][:linkCount]
+	f.pageAttachments[page] = f.pageAttachments[page][:attachCount]
+	f.formFields = f.formFields[:fieldCount]
+	f.elements = f.elements[:elemCount]
+	f.setDrawState(st)
+	if f.macros == nil {
+		f.macros = make(map[string]macroType)
+	}
+	f.macros[nameStr] = m
+}
+
+// PlayMacro draws the macro recorded under nameStr with RecordMacro() on the
+// current page, with its origin placed at (x, y) and enlarged by the factor
+// scale, so that a macro recorded with a size of one unit and played with a
+// scale of 5 is five units in size. Line widths and font sizes are scaled
+// along with the macro. The drawing state of the document is not changed.
+func (f *Fpdf) PlayMacro(nameStr string, x, y, scale float64) {
+	if f.err != nil {
+		return
+	}
+	m, ok := f.macros[nameStr]
+	if !ok {
+		f.SetErrorf("macro %s has not been recorded", nameStr)
+		return
+	}
+	if f.page <= 0 {
+		f.SetErrorf("cannot play a macro without first adding a page")
+		return
+	}
+	// Map the point (u, v) of the recording page, in points, to
+	// (x*k + scale*u, hPt - y*k - scale*(m.hPt - v)) on the current page
+	f.outf("q %s %.2f %.2f cm", fixedf("%.5f 0 0 %.5f", scale, scale), x*f.k, f.hPt-y*f.k-scale*m.hPt)
+	f.out(string(m.content))
+	f.out("Q")
+}
+
+// drawStateType holds the drawing state of a document that is restored after
+// content has been drawn or recorded out of its usual sequence
+type drawStateType struct {
+	x, y, ws                      float64
+	color                         [3]colorType
+	colorFlag, textClrExplicit    bool
+	lineWidth                     float64
+	capStyle, joinStyle           int
+	dashArray                     []float64
+	dashPhase                     float64
+	alpha, strokeAlpha, fillAlpha float64
+	blendMode                     string
+	fontFamily, fontStyle         string
+	underline, strikeout          bool
+	currentFont                   fontDefType
+	isCurrentUTF8                 bool
+	fontSizePt, fontSize          float64
+}
+
+// drawState returns the current drawing state
+func (f *Fpdf) drawState() (st drawStateType) {
+	st.x, st.y, st.ws = f.x, f.y, f.ws
+	st.color = [3]colorType{f.color.draw, f.color.fill, f.color.text}
+	st.colorFlag, st.textClrExplicit = f.colorFlag, f.textClrExplicit
+	st.lineWidth, st.capStyle, st.joinStyle = f.lineWidth, f.capStyle, f.joinStyle
+	st.dashArray, st.dashPhase = f.dashArray, f.dashPhase
+	st.alpha, st.strokeAlpha, st.fillAlpha, st.blendMode = f.alpha, f.strokeAlpha, f.fillAlpha, f.blendMode
+	st.fontFamily, st.fontStyle, st.underline, st.strikeout = f.fontFamily, f.fontStyle, f.underline, f.strikeout
+	st.currentFont, st.isCurrentUTF8 = f.currentFont, f.isCurrentUTF8
+	st.fontSizePt, st.fontSize = f.fontSizePt, f.fontSize
+	return
+}
+
+// setDrawState restores the drawing state st without writing it to the page;
+// the content written since st was taken must be removed or enclosed in
+// "q" and "Q" operators
+func (f *Fpdf) setDrawState(st drawStateType) {
+	f.x, f.y, f.ws = st.x, st.y, st.ws
+	f.color.draw, f.color.fill, f.color.text = st.color[0], st.color[1], st.color[2]
+	f.colorFlag, f.textClrExplicit = st.colorFlag, st.textClrExplicit
+	f.lineWidth, f.capStyle, f.joinStyle = st.lineWidth, st.capStyle, st.joinStyle
+	f.dashArray, f.dashPhase = st.dashArray, st.dashPhase
+	f.alpha, f.strokeAlpha, f.fillAlpha, f.blendMode = st.alpha, st.strokeAlpha, st.fillAlpha, st.blendMode
+	f.fontFamily, f.fontStyle, f.underline, f.strikeout = st.fontFamily, st.fontStyle, st.underline, st.strikeout
+	f.currentFont, f.isCurrentUTF8 = st.currentFont, st.isCurrentUTF8
+	f.fontSizePt, f.fontSize = st.fontSizePt, st.fontSize
+}
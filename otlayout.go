@@ -0,0 +1,831 @@
+package gofpdf
+
+import "sort"
+
+// Restricted features, which are applied only to the glyphs of an Indic or
+// Khmer syllable that have the corresponding form. Features that are not
+// listed here apply to all glyphs.
+const (
+	otFeatRphf = 1 << iota
+	otFeatHalf
+	otFeatBlwf
+	otFeatPref
+	otFeatPstf
+)
+
+// otSubstFeatures are the GSUB features applied when text is shaped, with the
+// restriction each is subject to
+var otSubstFeatures = map[string]int{
+	"ccmp": 0, "locl": 0, "nukt": 0, "akhn": 0, "rphf": otFeatRphf,
+	"rkrf": 0, "pref": otFeatPref, "blwf": otFeatBlwf, "abvf": 0,
+	"half": otFeatHalf, "pstf": otFeatPstf, "vatu": 0, "cjct": 0, "cfar": 0,
+	"pres": 0, "abvs": 0, "blws": 0, "psts": 0, "haln": 0, "rlig": 0,
+	"liga": 0, "clig": 0, "calt": 0,
+}
+
+// otPosFeatures are the GPOS features applied when text is shaped
+var otPosFeatures = map[string]int{
+	"abvm": 0, "blwm": 0, "mark": 0, "mkmk": 0,
+}
+
+// otKernFeatures are the GPOS features applied when text is kerned
+var otKernFeatures = map[string]int{"kern": 0}
+
+// GDEF glyph classes
+const (
+	otClassBase     = 1
+	otClassLigature = 2
+	otClassMark     = 3
+)
+
+// otLayoutType holds the OpenType layout tables of a font: GSUB, with which
+// glyphs are substituted for sequences of characters, GPOS, with which marks
+// are positioned on the glyphs they belong to and pairs of glyphs are
+// kerned, GDEF, which classifies the glyphs, and the kerning table of
+// TrueType fonts without GPOS kerning
+type otLayoutType struct {
+	gsub, gpos, gdef []byte
+	kern             []byte
+	advances         []int // advance widths in font units, by glyph
+	unitsPerEm       int
+	cmap             map[int]int // rune -> glyph
+	lookups          map[string]*otLookupSetType
+}
+
+// otLookupRefType is a lookup that the features selected for a script
+// refer to; mask holds the restricted features among them, and all is set
+// if an unrestricted feature refers to the lookup as well
+type otLookupRefType struct {
+	index int
+	mask  int
+	all   bool
+}
+
+// otLookupSetType holds the lookups applied to the text of a script
+type otLookupSetType struct {
+	subst, pos []otLookupRefType
+	kern       []otLookupRefType
+	rphf       bool // whether the font forms a reph for the script
+}
+
+// otGlyphType is a glyph of text that is being shaped
+type otGlyphType struct {
+	gid    int
+	runes  []int // text that the glyph represents
+	mask   int   // restricted features that apply to the glyph
+	syl    int   // 1-based index of the syllable the glyph belongs to
+	base   bool  // whether the glyph includes the base consonant
+	reph   bool  // whether the glyph is a reph
+	adv    int   // advance width in font units
+	px, py int   // offset of the glyph from its position, set by kerning
+	attach int   // 1-based index of the glyph the mark is attached to
+	ax, ay int   // offset of the mark from the glyph it is attached to
+}
+
+// otShaperType applies the lookups of a layout table to a glyph buffer
+type otShaperType struct {
+	lay   *otLayoutType
+	table []byte
+	gpos  bool
+	buf   []otGlyphType
+	flag  int // flag of the current lookup
+	depth int // nesting of contextual lookups
+}
+
+// layout returns the layout tables of the font, which are read on first use,
+// or nil if the font has neither substitutions nor positioning nor kerning
+func (utf *utf8FontFile) layout() *otLayoutType {
+	if utf.otLayout != nil || utf.otLayoutDone {
+		return utf.otLayout
+	}
+	utf.otLayoutDone = true
+	lay := &otLayoutType{
+		gsub:    utf.getTableData("GSUB"),
+		gpos:    utf.getTableData("GPOS"),
+		gdef:    utf.getTableData("GDEF"),
+		kern:    utf.getTableData("kern"),
+		cmap:    utf.charSymbolDictionary,
+		lookups: make(map[string]*otLookupSetType),
+	}
+	if lay.gsub == nil && lay.gpos == nil && lay.kern == nil {
+		return nil
+	}
+	head := utf.getTableData("head")
+	hhea := utf.getTableData("hhea")
+	hmtx := utf.getTableData("hmtx")
+	lay.unitsPerEm = otU16(head, 18)
+	if lay.unitsPerEm == 0 {
+		lay.unitsPerEm = 1000
+	}
+	metrics := otU16(hhea, 34)
+	lay.advances = make([]int, metrics)
+	for gid := range lay.advances {
+		lay.advances[gid] = otU16(hmtx, gid*4)
+	}
+	utf.otLayout = lay
+	return lay
+}
+
+// advance returns the advance width of glyph gid in font units
+func (lay *otLayoutType) advance(gid int) int {
+	switch {
+	case len(lay.advances) == 0:
+		return 0
+	case gid < len(lay.advances):
+		return lay.advances[gid]
+	}
+	return lay.advances[len(lay.advances)-1]
+}
+
+// glyphClass returns the GDEF class of glyph gid
+func (lay *otLayoutType) glyphClass(gid int) int {
+	if off := otU16(lay.gdef, 4); off > 0 {
+		return otClass(lay.gdef, off, gid)
+	}
+	return 0
+}
+
+// attachClass returns the GDEF mark attachment class of glyph gid
+func (lay *otLayoutType) attachClass(gid int) int {
+	if off := otU16(lay.gdef, 10); off > 0 {
+		return otClass(lay.gdef, off, gid)
+	}
+	return 0
+}
+
+// lookupSet returns the lookups applied to text in the script with the
+// first of scriptTags that the font supports
+func (lay *otLayoutType) lookupSet(scriptTags []string) *otLookupSetType {
+	key := ""
+	for _, tag := range scriptTags {
+		key += tag
+	}
+	if set, ok := lay.lookups[key]; ok {
+		return set
+	}
+	set := &otLookupSetType{}
+	set.subst, set.rphf = otSelectLookups(lay.gsub, scriptTags, otSubstFeatures)
+	set.pos, _ = otSelectLookups(lay.gpos, scriptTags, otPosFeatures)
+	set.kern, _ = otSelectLookups(lay.gpos, scriptTags, otKernFeatures)
+	lay.lookups[key] = set
+	return set
+}
+
+// otSelectLookups returns the lookups of table that features refer to in the
+// default language system of the first of scriptTags found in the table,
+// ordered as in the lookup list, and whether the reph feature is among them
+func otSelectLookups(table []byte, scriptTags []string, features map[string]int) (refs []otLookupRefType, rphf bool) {
+	if table == nil {
+		return
+	}
+	scriptList := otU16(table, 4)
+	featureList := otU16(table, 6)
+	script := 0
+	tags := append(append([]string(nil), scriptTags...), "DFLT", "latn")
+	for _, tag := range tags {
+		for j := 0; j < otU16(table, scriptList) && script == 0; j++ {
+			rec := scriptList + 2 + j*6
+			if otTag(table, rec) == tag {
+				script = scriptList + otU16(table, rec+4)
+			}
+		}
+		if script != 0 {
+			break
+		}
+	}
+	if script == 0 || otU16(table, script) == 0 {
+		return
+	}
+	langSys := script + otU16(table, script)
+	byIndex := make(map[int]*otLookupRefType)
+	addFeature := func(fi int) {
+		if fi >= otU16(table, featureList) {
+			return
+		}
+		rec := featureList + 2 + fi*6
+		mask, ok := features[otTag(table, rec)]
+		if !ok {
+			return
+		}
+		if mask == otFeatRphf {
+			rphf = true
+		}
+		feature := featureList + otU16(table, rec+4)
+		for k := 0; k < otU16(table, feature+2); k++ {
+			li := otU16(table, feature+4+k*2)
+			ref, ok := byIndex[li]
+			if !ok {
+				ref = &otLookupRefType{index: li}
+				byIndex[li] = ref
+			}
+			ref.mask |= mask
+			ref.all = ref.all || mask == 0
+		}
+	}
+	if req := otU16(table, langSys+2); req != 0xFFFF {
+		addFeature(req)
+	}
+	for j := 0; j < otU16(table, langSys+4); j++ {
+		addFeature(otU16(table, langSys+6+j*2))
+	}
+	for _, ref := range byIndex {
+		refs = append(refs, *ref)
+	}
+	sort.Slice(refs, func(a, b int) bool { return refs[a].index < refs[b].index })
+	return
+}
+
+// applies reports whether the lookup ref applies to glyph g
+func (ref otLookupRefType) applies(g otGlyphType) bool {
+	return ref.all || ref.mask&g.mask != 0
+}
+
+// substitute applies the GSUB lookups refs to the buffer
+func (lay *otLayoutType) substitute(buf []otGlyphType, refs []otLookupRefType) []otGlyphType {
+	s := otShaperType{lay: lay, table: lay.gsub, buf: buf}
+	for _, ref := range refs {
+		for i := 0; i < len(s.buf); {
+			if !ref.applies(s.buf[i]) {
+				i++
+				continue
+			}
+			reph := ref.mask&otFeatRphf != 0 && s.buf[i].mask&otFeatRphf != 0
+			n := s.applyLookup(ref.index, i)
+			if n == 0 {
+				i++
+				continue
+			}
+			if reph {
+				s.buf[i].reph = true
+			}
+			i += n
+		}
+	}
+	return s.buf
+}
+
+// position applies the GPOS lookups refs to the buffer
+func (lay *otLayoutType) position(buf []otGlyphType, refs []otLookupRefType) {
+	s := otShaperType{lay: lay, table: lay.gpos, gpos: true, buf: buf}
+	for _, ref := range refs {
+		for i := range s.buf {
+			s.applyLookup(ref.index, i)
+		}
+	}
+}
+
+// kernTable applies the pairs of the kerning table to the buffer, adjusting
+// the advance of the first glyph of each pair by the value of the pair
+func (lay *otLayoutType) kernTable(buf []otGlyphType) {
+	t := lay.kern
+	if otU16(t, 0) != 0 {
+		return
+	}
+	sub := 4
+	for k := 0; k < otU16(t, 2); k++ {
+		length, coverage := otU16(t, sub+2), otU16(t, sub+4)
+		// Only horizontal kerning of format 0 that is not cross-stream
+		if coverage&0xFF07 == 1 {
+			count := otU16(t, sub+6)
+			for i := 0; i+1 < len(buf); i++ {
+				if buf[i+1].attach > 0 {
+					continue
+				}
+				key := buf[i].gid<<16 | buf[i+1].gid
+				pair := func(j int) int { return otU32(t, sub+14+j*6) }
+				j := sort.Search(count, func(j int) bool { return pair(j) >= key })
+				if j < count && pair(j) == key {
+					buf[i].adv += otI16(t, sub+14+j*6+4)
+				}
+			}
+		}
+		if length == 0 {
+			break
+		}
+		sub += length
+	}
+}
+
+// lookup returns the offset of lookup li, its type and its flag
+func (s *otShaperType) lookup(li int) (off, tp, flag int) {
+	list := otU16(s.table, 8)
+	if li >= otU16(s.table, list) {
+		return
+	}
+	off = list + otU16(s.table, list+2+li*2)
+	return off, otU16(s.table, off), otU16(s.table, off+2)
+}
+
+// applyLookup applies lookup li at position i of the buffer and returns the
+// number of glyphs to move on by, or zero if the lookup does not apply
+func (s *otShaperType) applyLookup(li, i int) int {
+	off, tp, flag := s.lookup(li)
+	if off == 0 || i >= len(s.buf) || s.ignored(i, flag) {
+		return 0
+	}
+	prevFlag := s.flag
+	s.flag = flag
+	defer func() { s.flag = prevFlag }()
+	for j := 0; j < otU16(s.table, off+4); j++ {
+		sub := off + otU16(s.table, off+6+j*2)
+		subTp := tp
+		if (!s.gpos && tp == 7) || (s.gpos && tp == 9) {
+			// Extension
+			subTp = otU16(s.table, sub+2)
+			sub += otU32(s.table, sub+4)
+		}
+		var n int
+		if s.gpos {
+			n = s.applyPos(subTp, sub, i)
+		} else {
+			n = s.applySubst(subTp, sub, i)
+		}
+		if n > 0 {
+			return n
+		}
+	}
+	return 0
+}
+
+// ignored reports whether the glyph at position i is skipped by a lookup
+// with flag
+func (s *otShaperType) ignored(i, flag int) bool {
+	gid := s.buf[i].gid
+	switch s.lay.glyphClass(gid) {
+	case otClassBase:
+		return flag&2 != 0
+	case otClassLigature:
+		return flag&4 != 0
+	case otClassMark:
+		if flag&8 != 0 {
+			return true
+		}
+		if t := flag >> 8; t != 0 && s.lay.attachClass(gid) != t {
+			return true
+		}
+	}
+	return false
+}
+
+// next returns the position of the glyph after position i that the current
+// lookup does not skip, or -1
+func (s *otShaperType) next(i int) int {
+	for i++; i < len(s.buf); i++ {
+		if !s.ignored(i, s.flag) {
+			return i
+		}
+	}
+	return -1
+}
+
+// prev returns the position of the glyph before position i that the current
+// lookup does not skip, or -1
+func (s *otShaperType) prev(i int) int {
+	for i--; i >= 0; i-- {
+		if !s.ignored(i, s.flag) {
+			return i
+		}
+	}
+	return -1
+}
+
+// applySubst applies the GSUB subtable at offset sub of type tp at position
+// i and returns the number of glyphs to move on by, or zero
+func (s *otShaperType) applySubst(tp, sub, i int) int {
+	t := s.table
+	g := &s.buf[i]
+	switch tp {
+	case 1: // Single
+		cov := otCoverage(t, sub+otU16(t, sub+2), g.gid)
+		if cov < 0 {
+			return 0
+		}
+		if otU16(t, sub) == 1 {
+			g.gid = (g.gid + otU16(t, sub+4)) & 0xFFFF
+		} else if cov < otU16(t, sub+4) {
+			g.gid = otU16(t, sub+6+cov*2)
+		} else {
+			return 0
+		}
+		return 1
+	case 2: // Multiple
+		cov := otCoverage(t, sub+otU16(t, sub+2), g.gid)
+		if cov < 0 || cov >= otU16(t, sub+4) {
+			return 0
+		}
+		seq := sub + otU16(t, sub+6+cov*2)
+		count := otU16(t, seq)
+		if count == 0 {
+			return 0
+		}
+		glyphs := make([]otGlyphType, count)
+		for k := range glyphs {
+			glyphs[k] = *g
+			glyphs[k].gid = otU16(t, seq+2+k*2)
+			if k > 0 {
+				glyphs[k].runes = nil
+			}
+		}
+		s.buf = append(s.buf[:i], append(glyphs, s.buf[i+1:]...)...)
+		return count
+	case 4: // Ligature
+		cov := otCoverage(t, sub+otU16(t, sub+2), g.gid)
+		if cov < 0 || cov >= otU16(t, sub+4) {
+			return 0
+		}
+		set := sub + otU16(t, sub+6+cov*2)
+		for k := 0; k < otU16(t, set); k++ {
+			lig := set + otU16(t, set+2+k*2)
+			comps := otU16(t, lig+2)
+			pos := s.matchInput(i, comps, func(c, gid int) bool {
+				return otU16(t, lig+2+c*2) == gid
+			})
+			if pos == nil {
+				continue
+			}
+			s.ligate(pos, otU16(t, lig))
+			return 1
+		}
+	case 5, 6: // Context, chaining context
+		return s.applyContext(tp == 6, sub, i)
+	}
+	return 0
+}
+
+// ligate replaces the glyphs at positions pos with glyph gid, which
+// represents their text; glyphs skipped between them follow the ligature
+func (s *otShaperType) ligate(pos []int, gid int) {
+	g := s.buf[pos[0]]
+	g.gid = gid
+	g.runes = append([]int(nil), g.runes...)
+	for _, p := range pos[1:] {
+		g.runes = append(g.runes, s.buf[p].runes...)
+		g.base = g.base || s.buf[p].base
+	}
+	for k := len(pos) - 1; k > 0; k-- {
+		s.buf = append(s.buf[:pos[k]], s.buf[pos[k]+1:]...)
+	}
+	s.buf[pos[0]] = g
+}
+
+// matchInput returns the positions of count glyphs beginning at position i,
+// skipping the glyphs that the current lookup ignores, if match accepts the
+// glyph at each position c after the first, or nil
+func (s *otShaperType) matchInput(i, count int, match func(c, gid int) bool) []int {
+	pos := []int{i}
+	for c := 1; c < count; c++ {
+		i = s.next(i)
+		if i < 0 || !match(c, s.buf[i].gid) {
+			return nil
+		}
+		pos = append(pos, i)
+	}
+	return pos
+}
+
+// matchBacktrack reports whether match accepts count glyphs before position
+// i, the glyph nearest to i being number 0
+func (s *otShaperType) matchBacktrack(i, count int, match func(c, gid int) bool) bool {
+	for c := 0; c < count; c++ {
+		i = s.prev(i)
+		if i < 0 || !match(c, s.buf[i].gid) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchLookahead reports whether match accepts count glyphs after position
+// i
+func (s *otShaperType) matchLookahead(i, count int, match func(c, gid int) bool) bool {
+	for c := 0; c < count; c++ {
+		i = s.next(i)
+		if i < 0 || !match(c, s.buf[i].gid) {
+			return false
+		}
+	}
+	return true
+}
+
+// applyContext applies a contextual subtable, of the chaining kind if chain
+// is set, at position i. The layout of these subtables is the same in GSUB
+// and GPOS.
+func (s *otShaperType) applyContext(chain bool, sub, i int) int {
+	t := s.table
+	gid := s.buf[i].gid
+	glyphMatch := func(arr int) func(c, gid int) bool {
+		return func(c, gid int) bool { return otU16(t, arr+c*2) == gid }
+	}
+	classMatch := func(classDef, arr int) func(c, gid int) bool {
+		return func(c, gid int) bool { return otClass(t, classDef, gid) == otU16(t, arr+c*2) }
+	}
+	coverageMatch := func(arr int) func(c, gid int) bool {
+		return func(c, gid int) bool { return otCoverage(t, sub+otU16(t, arr+c*2), gid) >= 0 }
+	}
+	switch otU16(t, sub) {
+	case 1, 2:
+		format2 := otU16(t, sub) == 2
+		cov := otCoverage(t, sub+otU16(t, sub+2), gid)
+		if cov < 0 {
+			return 0
+		}
+		var btDef, inDef, laDef, setList int
+		idx := cov
+		switch {
+		case format2 && chain:
+			btDef, inDef, laDef = sub+otU16(t, sub+4), sub+otU16(t, sub+6), sub+otU16(t, sub+8)
+			setList = sub + 10
+			idx = otClass(t, inDef, gid)
+		case format2:
+			inDef = sub + otU16(t, sub+4)
+			setList = sub + 6
+			idx = otClass(t, inDef, gid)
+		default:
+			setList = sub + 4
+		}
+		if idx >= otU16(t, setList) || otU16(t, setList+2+idx*2) == 0 {
+			return 0
+		}
+		set := sub + otU16(t, setList+2+idx*2)
+		for k := 0; k < otU16(t, set); k++ {
+			rule := set + otU16(t, set+2+k*2)
+			match := func(arr, classDef int) func(c, gid int) bool {
+				if format2 {
+					return classMatch(classDef, arr)
+				}
+				return glyphMatch(arr)
+			}
+			if !chain {
+				count := otU16(t, rule)
+				pos := s.matchInput(i, count, func(c, gid int) bool {
+					return match(rule+4, inDef)(c-1, gid)
+				})
+				if pos != nil {
+					return s.applyRecords(pos, rule+4+(count-1)*2, otU16(t, rule+2))
+				}
+				continue
+			}
+			bt := rule
+			in := bt + 2 + otU16(t, bt)*2
+			la := in + 2 + (otU16(t, in)-1)*2
+			recs := la + 2 + otU16(t, la)*2
+			if otU16(t, in) == 0 || !s.matchBacktrack(i, otU16(t, bt), match(bt+2, btDef)) {
+				continue
+			}
+			pos := s.matchInput(i, otU16(t, in), func(c, gid int) bool {
+				return match(in+2, inDef)(c-1, gid)
+			})
+			if pos != nil && s.matchLookahead(pos[len(pos)-1], otU16(t, la), match(la+2, laDef)) {
+				return s.applyRecords(pos, recs+2, otU16(t, recs))
+			}
+		}
+	case 3:
+		if !chain {
+			count := otU16(t, sub+2)
+			if count == 0 || !coverageMatch(sub+6)(0, gid) {
+				return 0
+			}
+			pos := s.matchInput(i, count, coverageMatch(sub+6))
+			if pos != nil {
+				return s.applyRecords(pos, sub+6+count*2, otU16(t, sub+4))
+			}
+			return 0
+		}
+		bt := sub + 2
+		in := bt + 2 + otU16(t, bt)*2
+		la := in + 2 + otU16(t, in)*2
+		recs := la + 2 + otU16(t, la)*2
+		if otU16(t, in) == 0 || !coverageMatch(in+2)(0, gid) ||
+			!s.matchBacktrack(i, otU16(t, bt), coverageMatch(bt+2)) {
+			return 0
+		}
+		pos := s.matchInput(i, otU16(t, in), coverageMatch(in+2))
+		if pos != nil && s.matchLookahead(pos[len(pos)-1], otU16(t, la), coverageMatch(la+2)) {
+			return s.applyRecords(pos, recs+2, otU16(t, recs))
+		}
+	}
+	return 0
+}
+
+// applyRecords applies the count lookups of the lookup records at offset
+// recs to the matched glyphs at positions pos and returns the number of
+// glyphs to move on by
+func (s *otShaperType) applyRecords(pos []int, recs, count int) int {
+	if s.depth > 8 {
+		return 1
+	}
+	s.depth++
+	for k := 0; k < count; k++ {
+		seq, li := otU16(s.table, recs+k*4), otU16(s.table, recs+k*4+2)
+		if seq >= len(pos) {
+			continue
+		}
+		size := len(s.buf)
+		s.applyLookup(li, pos[seq])
+		// Glyphs that follow a ligature or a multiple substitution move
+		if delta := len(s.buf) - size; delta != 0 {
+			for j := seq + 1; j < len(pos); j++ {
+				pos[j] += delta
+			}
+		}
+	}
+	s.depth--
+	return 1
+}
+
+// applyPos applies the GPOS subtable at offset sub of type tp to the glyph at
+// position i and returns one if it applies, or zero
+func (s *otShaperType) applyPos(tp, sub, i int) int {
+	t := s.table
+	g := &s.buf[i]
+	switch tp {
+	case 2: // Pair adjustment
+		first := otCoverage(t, sub+otU16(t, sub+2), g.gid)
+		j := s.next(i)
+		if first < 0 || j < 0 {
+			return 0
+		}
+		vf1, vf2 := otU16(t, sub+4), otU16(t, sub+6)
+		size1, size2 := otValueSize(vf1), otValueSize(vf2)
+		rec := 0
+		switch otU16(t, sub) {
+		case 1:
+			if first >= otU16(t, sub+8) {
+				return 0
+			}
+			set := sub + otU16(t, sub+10+first*2)
+			count, recSize := otU16(t, set), 2+size1+size2
+			gid := s.buf[j].gid
+			k := sort.Search(count, func(k int) bool { return otU16(t, set+2+k*recSize) >= gid })
+			if k >= count || otU16(t, set+2+k*recSize) != gid {
+				return 0
+			}
+			rec = set + 2 + k*recSize + 2
+		case 2:
+			c1 := otClass(t, sub+otU16(t, sub+8), g.gid)
+			c2 := otClass(t, sub+otU16(t, sub+10), s.buf[j].gid)
+			count1, count2 := otU16(t, sub+12), otU16(t, sub+14)
+			if c1 >= count1 || c2 >= count2 {
+				return 0
+			}
+			rec = sub + 16 + (c1*count2+c2)*(size1+size2)
+		default:
+			return 0
+		}
+		s.applyValue(i, vf1, rec)
+		s.applyValue(j, vf2, rec+size1)
+		return 1
+	case 4, 5, 6: // Mark to base, ligature, mark
+		mark := otCoverage(t, sub+otU16(t, sub+2), g.gid)
+		if mark < 0 {
+			return 0
+		}
+		markArray := sub + otU16(t, sub+8)
+		if mark >= otU16(t, markArray) {
+			return 0
+		}
+		class := otU16(t, markArray+2+mark*4)
+		classCount := otU16(t, sub+6)
+		j := i - 1
+		if tp == 6 {
+			j = s.prev(i)
+			if j < 0 || s.lay.glyphClass(s.buf[j].gid) != otClassMark {
+				return 0
+			}
+		} else {
+			for j >= 0 && s.lay.glyphClass(s.buf[j].gid) == otClassMark {
+				j--
+			}
+		}
+		if j < 0 || class >= classCount {
+			return 0
+		}
+		target := otCoverage(t, sub+otU16(t, sub+4), s.buf[j].gid)
+		targetArray := sub + otU16(t, sub+10)
+		if target < 0 || target >= otU16(t, targetArray) {
+			return 0
+		}
+		anchor := 0
+		if tp == 5 {
+			// The mark is placed on the last component that has an anchor
+			// for its class
+			attach := targetArray + otU16(t, targetArray+2+target*2)
+			for c := otU16(t, attach) - 1; c >= 0 && anchor == 0; c-- {
+				if off := otU16(t, attach+2+(c*classCount+class)*2); off > 0 {
+					anchor = attach + off
+				}
+			}
+		} else if off := otU16(t, targetArray+2+(target*classCount+class)*2); off > 0 {
+			anchor = targetArray + off
+		}
+		if anchor == 0 {
+			return 0
+		}
+		markAnchor := markArray + otU16(t, markArray+2+mark*4+2)
+		g.attach = j + 1
+		g.ax = otI16(t, anchor+2) - otI16(t, markAnchor+2)
+		g.ay = otI16(t, anchor+4) - otI16(t, markAnchor+4)
+		g.adv = 0
+		return 1
+	case 7, 8: // Context, chaining context
+		return s.applyContext(tp == 8, sub, i)
+	}
+	return 0
+}
+
+// applyValue applies the value record at offset rec, whose format is vf, to
+// the glyph at position i. Vertical advances and device adjustments are
+// ignored.
+func (s *otShaperType) applyValue(i, vf, rec int) {
+	g := &s.buf[i]
+	for bit := 1; bit <= 8; bit <<= 1 {
+		if vf&bit == 0 {
+			continue
+		}
+		v := otI16(s.table, rec)
+		rec += 2
+		switch bit {
+		case 1:
+			g.px += v
+		case 2:
+			g.py += v
+		case 4:
+			g.adv += v
+		}
+	}
+}
+
+// otValueSize returns the size of a value record of format vf
+func otValueSize(vf int) (size int) {
+	for ; vf != 0; vf >>= 1 {
+		if vf&1 != 0 {
+			size += 2
+		}
+	}
+	return
+}
+
+// otU16 returns the unsigned 16-bit value at offset off of b, or zero if b
+// is too short, so that damaged tables do not cause a panic
+func otU16(b []byte, off int) int {
+	if off < 0 || off+2 > len(b) {
+		return 0
+	}
+	return int(b[off])<<8 | int(b[off+1])
+}
+
+// otI16 returns the signed 16-bit value at offset off of b
+func otI16(b []byte, off int) int {
+	return int(int16(otU16(b, off)))
+}
+
+// otU32 returns the unsigned 32-bit value at offset off of b
+func otU32(b []byte, off int) int {
+	return otU16(b, off)<<16 | otU16(b, off+2)
+}
+
+// otTag returns the four-character tag at offset off of b
+func otTag(b []byte, off int) string {
+	if off < 0 || off+4 > len(b) {
+		return ""
+	}
+	return string(b[off : off+4])
+}
+
+// otCoverage returns the index of glyph gid in the coverage table at offset
+// off of b, or -1 if the table does not cover the glyph
+func otCoverage(b []byte, off, gid int) int {
+	count := otU16(b, off+2)
+	switch otU16(b, off) {
+	case 1:
+		k := sort.Search(count, func(k int) bool { return otU16(b, off+4+k*2) >= gid })
+		if k < count && otU16(b, off+4+k*2) == gid {
+			return k
+		}
+	case 2:
+		k := sort.Search(count, func(k int) bool { return otU16(b, off+4+k*6+2) >= gid })
+		if rec := off + 4 + k*6; k < count && otU16(b, rec) <= gid {
+			return otU16(b, rec+4) + gid - otU16(b, rec)
+		}
+	}
+	return -1
+}
+
+// otClass returns the class of glyph gid in the class definition table at
+// offset off of b
+func otClass(b []byte, off, gid int) int {
+	switch otU16(b, off) {
+	case 1:
+		start := otU16(b, off+2)
+		if gid >= start && gid-start < otU16(b, off+4) {
+			return otU16(b, off+6+(gid-start)*2)
+		}
+	case 2:
+		count := otU16(b, off+2)
+		k := sort.Search(count, func(k int) bool { return otU16(b, off+4+k*6+2) >= gid })
+		if rec := off + 4 + k*6; k < count && otU16(b, rec) <= gid {
+			return otU16(b, rec+4)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,110 @@
+package gofpdf
+
+// Kinds of overflow reported in OverflowType
+const (
+	// OverflowWidth is text that is wider than the cell it is printed in
+	OverflowWidth = "width"
+	// OverflowMargin is content that extends below the bottom margin of its
+	// page, where the page has not been broken
+	OverflowMargin = "margin"
+	// OverflowClip is text that has been left over because it does not fit
+	// into its shape or chain of frames
+	OverflowClip = "clip"
+)
+
+// Elements reported in OverflowType in addition to the kinds of content of
+// PageBreakContextType
+const (
+	// OverflowElementShape is text printed with ShapeText() or
+	// ShapeTextEllipse()
+	OverflowElementShape = "shape"
+	// OverflowElementFrame is text poured into frames with PourText()
+	OverflowElementFrame = "frame"
+)
+
+// OverflowType describes an instance of content that does not fit the space
+// given to it. It is an entry of the report returned by OverflowReport().
+type OverflowType struct {
+	// One of OverflowWidth, OverflowMargin and OverflowClip
+	Kind string
+	// One of PageBreakCell, PageBreakMultiCell, PageBreakWrite,
+	// PageBreakImage, OverflowElementShape and OverflowElementFrame
+	Element string
+	// Page and position of the upper left corner of the element, in the
+	// units passed to New()
+	Page int
+	X, Y float64
+	// Amount of the overflow: for OverflowWidth, the width by which the text
+	// and the cell margins exceed the cell, and for OverflowMargin, the
+	// distance the element extends below the bottom margin, in the units
+	// passed to New(); for OverflowClip, the number of characters that have
+	// not been printed
+	Amount float64
+	// The text concerned, or the text left over for OverflowClip; empty for
+	// images
+	Text string
+}
+
+// SetOverflowReport turns the recording of overflows on or off. While it is
+// on, every instance of text that is wider than its cell (see CellFormat()),
+// of a cell or image that extends below the bottom margin without a page
+// break (see SetAutoPageBreak()), and of text that is left over by
+// ShapeText() or PourText() is recorded, so that a test of a fixed layout,
+// such as a form or a label, can fail when a change of the data or of the
+// template makes content collide with its surroundings. The text of
+// MultiCell() and Write() is wrapped to its width and is only reported when
+// a single word is too wide or the text runs into the bottom margin. Content
+// printed by the header and footer functions is not checked, since it is
+// placed in the margins on purpose.
+//
+// Turning the report on discards the entries recorded before. The report is
+// retrieved with OverflowReport().
+func (f *Fpdf) SetOverflowReport(report bool) {
+	f.overflowReport = report
+	if report {
+		f.overflows = nil
+	}
+}
+
+// OverflowReport returns the overflows that have been recorded since the
+// report was turned on with SetOverflowReport(), in the order in which they
+// occurred, or nil if there are none.
+func (f *Fpdf) OverflowReport() []OverflowType {
+	return append([]OverflowType(nil), f.overflows...)
+}
+
+// overflow records an overflow of the specified kind of element at position
+// (x, y) of the current page
+func (f *Fpdf) overflow(kindStr, elemStr string, x, y, amount float64, txtStr string) {
+	if f.inHeader || f.inFooter {
+		return
+	}
+	f.overflows = append(f.overflows, OverflowType{
+		Kind:    kindStr,
+		Element: elemStr,
+		Page:    f.page,
+		X:       x,
+		Y:       y,
+		Amount:  amount,
+		Text:    txtStr,
+	})
+}
+
+// overflowCell records the overflows of a cell of width w and height h at
+// the current position that shows txtStr
+func (f *Fpdf) overflowCell(w, h float64, txtStr string) {
+	elemStr := f.cellBreakKindStr()
+	margins := 2 * f.cMargin
+	if elemStr == PageBreakWrite {
+		// The last cell of Write() is as wide as its text alone
+		margins = 0
+	}
+	if txtStr != "" {
+		if over := f.GetStringWidth(txtStr) + margins - w; over > 0.001 {
+			f.overflow(OverflowWidth, elemStr, f.x, f.y, over, txtStr)
+		}
+	}
+	if over := f.y + h - f.pageBreakTrigger; over > 0.001 {
+		f.overflow(OverflowMargin, elemStr, f.x, f.y, over, txtStr)
+	}
+}
@@ -0,0 +1,130 @@
+package gofpdf
+
+// Kinds of content reported in PageBreakContextType
+const (
+	// PageBreakCell is a cell printed with Cell(), CellFormat() or a related
+	// method
+	PageBreakCell = "cell"
+	// PageBreakMultiCell is a line of text printed with MultiCell()
+	PageBreakMultiCell = "multicell"
+	// PageBreakWrite is a line of text printed with Write() or a related
+	// method
+	PageBreakWrite = "write"
+	// PageBreakImage is an image placed in flowing mode
+	PageBreakImage = "image"
+	// PageBreakBlock is a unit of content that is kept together, such as a
+	// code block line, drop cap, formula, table row or set of columns
+	PageBreakBlock = "block"
+)
+
+// PageBreakContextType describes the content whose placement has met the
+// page break condition. It is passed to the function set with
+// SetAcceptPageBreakContextFunc().
+type PageBreakContextType struct {
+	// One of PageBreakCell, PageBreakMultiCell, PageBreakWrite,
+	// PageBreakImage and PageBreakBlock
+	Kind string
+	// Height of the content, in the units passed to New(). For text this is
+	// the height of the line that does not fit.
+	Height float64
+	// Current page and the vertical position of the content
+	Page int
+	Y    float64
+	// Space that remains between Y and the bottom margin
+	Space float64
+}
+
+// SetAcceptPageBreakContextFunc is like SetAcceptPageBreakFunc(), except that
+// fnc receives a description of the content that has triggered the page
+// break. This lets the application decide differently, for example, for an
+// image than for a line of text, or take the required height into account
+// when managing multiple columns. The break is issued if fnc returns true. The
+// default decision is the automatic page breaking mode, as returned by
+// GetAutoPageBreak().
+func (f *Fpdf) SetAcceptPageBreakContextFunc(fnc func(ctx PageBreakContextType) bool) {
+	f.acceptPageBreak = func() bool {
+		return fnc(f.pageBreakCtx)
+	}
+}
+
+// acceptPageBreakFor records the content of the specified kind and height,
+// to be placed at vertical position y, that has met the page break condition
+// and returns the application's decision whether to break the page. In
+// column mode the content moves to the next column instead, if there is
+// one.
+func (f *Fpdf) acceptPageBreakFor(kindStr string, y, h float64) bool {
+	f.pageBreakCtx = PageBreakContextType{
+		Kind:   kindStr,
+		Height: h,
+		Page:   f.page,
+		Y:      y,
+		Space:  f.pageBreakTrigger - y,
+	}
+	if accept, handled := f.columnPageBreak(); handled {
+		return accept
+	}
+	if !f.acceptPageBreak() {
+		return false
+	}
+	if f.columns != nil {
+		// Content continues in the first column of the new page
+		f.x = f.columns.lMargin
+	}
+	return true
+}
+
+// cellBreakKindStr returns the kind of content reported when CellFormat()
+// triggers a page break
+func (f *Fpdf) cellBreakKindStr() string {
+	if f.cellBreakKind == "" {
+		return PageBreakCell
+	}
+	return f.cellBreakKind
+}
+
+// imageOutSplit places an image of size w by h at horizontal position x in
+// flowing mode, printing the part that fits on the current page and the rest
+// on following pages. options supplies the alternate description and the
+// element name of the image.
+func (f *Fpdf) imageOutSplit(info *ImageInfoType, x, w, h float64, options ImageOptions, link int, linkStr string) {
+	var done float64
+	overflow, fresh := false, false
+	for done < h && f.err == nil {
+		part := h - done
+		if avail := f.pageBreakTrigger - f.y; part > avail && !overflow {
+			if avail > 0 {
+				part = avail
+			} else if fresh {
+				// Make progress even if the page has no room at all
+				overflow = true
+			} else {
+				part = 0
+			}
+		}
+		if part > 0 {
+			f.ClipRect(x, f.y, w, part, false)
+			f.altTextBegin(options.AltText)
+			f.outf("q %.5f 0 0 %.5f %.5f %.5f cm /I%s Do Q", w*f.k, h*f.k, x*f.k,
+				(f.h-(f.y-done+h))*f.k, info.i)
+			f.altTextEnd(options.AltText)
+			f.ClipEnd()
+			if link > 0 || len(linkStr) > 0 {
+				f.newLink(x, f.y, w, part, link, linkStr)
+			}
+			f.RecordElement(options.Element, ElementImage, x, f.y, w, part)
+			f.y += part
+			done += part
+		}
+		if done < h && !overflow {
+			if f.acceptPageBreakFor(PageBreakImage, f.y, h-done) {
+				x2 := f.x
+				f.AddPageFormat(f.curOrientation, f.curPageSize)
+				f.x = x2
+				fresh = true
+			} else {
+				// The rest of the image extends below the bottom margin
+				overflow = true
+			}
+		}
+	}
+}
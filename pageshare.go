@@ -0,0 +1,50 @@
+package gofpdf
+
+import (
+	"crypto/sha1"
+)
+
+// SetPageContentSharing specifies whether pages with identical content share
+// a single content stream in the output. When sharing is on, the content of
+// each page is hashed as the document is output, and a page whose content is
+// the same as that of an earlier page refers to the earlier page's content
+// stream rather than repeating it. This reduces the size of documents with
+// boilerplate pages, such as blank separator pages or repeated inserts. Links
+// and other annotations are not part of the page content and are kept for
+// each page. Sharing is off by default.
+func (f *Fpdf) SetPageContentSharing(share bool) {
+	f.shareContent = share
+}
+
+// GetPageContentSharing reports whether pages with identical content share a
+// single content stream. See SetPageContentSharing().
+func (f *Fpdf) GetPageContentSharing() bool {
+	return f.shareContent
+}
+
+// sharedPageContent returns the object number of the content stream of an
+// earlier page with the same content as page n, or zero if there is none or
+// sharing is off. Otherwise, the content of page n is recorded with object
+// number objNum for comparison with later pages.
+func (f *Fpdf) sharedPageContent(n, objNum int, contents map[[sha1.Size]byte]int) int {
+	if !f.shareContent {
+		return 0
+	}
+	sum := sha1.Sum(f.pages[n].Bytes())
+	if num, ok := contents[sum]; ok {
+		return num
+	}
+	contents[sum] = objNum
+	return 0
+}
+
+// freeobj reserves an object number that is not used, so that the numbering
+// of the objects that follow is not changed. The object is listed as free in
+// the cross-reference table.
+func (f *Fpdf) freeobj() {
+	f.n++
+	for j := len(f.offsets); j <= f.n; j++ {
+		f.offsets = append(f.offsets, 0)
+	}
+	f.offsets[f.n] = 0
+}
@@ -0,0 +1,241 @@
+package gofpdf
+
+import (
+	"math"
+)
+
+// ParagraphType specifies the layout of the paragraphs printed by
+// FlowText(). Create a value with NewParagraph() and adjust its fields as
+// needed.
+type ParagraphType struct {
+	// Alignment of the lines: "L", "C", "R" or "J". The last line of a
+	// justified paragraph is aligned left.
+	Align string
+	// Distance between lines; zero selects the line height of the document
+	// for the largest font on each line (see SetLineHeight())
+	LineHt float64
+	// Indent of the first line of each paragraph
+	Indent float64
+	// Space above and below each paragraph
+	SpaceBefore, SpaceAfter float64
+	// Minimum number of lines of a paragraph that are left at the bottom of
+	// a page (orphans) and carried over to the top of the next page (widows)
+	// when the paragraph is broken across pages; values below 1 are taken as
+	// 1
+	Orphans, Widows int
+}
+
+// NewParagraph returns a variable of type ParagraphType that is initialized
+// for left-aligned paragraphs with the line height of the document, no
+// indent or spacing, and at least two lines on either side of a page break.
+func NewParagraph() (para ParagraphType) {
+	para.Align = "L"
+	para.Orphans = 2
+	para.Widows = 2
+	return
+}
+
+// paraLineType is a line of a paragraph laid out by FlowText()
+type paraLineType struct {
+	start, end int     // range of the words of the line
+	wd, ht     float64 // width of the words and height of the line
+	fontHt     float64 // height of the largest font
+}
+
+// FlowText prints the styled runs of text as paragraphs of width w, beginning
+// at the current position. A line feed in a run begins a new paragraph; runs
+// are styled as described for PourText(). If w is zero, the paragraphs
+// extend to the right margin. The layout of the paragraphs is set by para.
+//
+// Pages are broken automatically, as for MultiCell(), but a paragraph is
+// broken only where at least para.Orphans of its lines remain at the bottom
+// of the page and at least para.Widows of them are carried over to the next
+// page. A paragraph that is too short to be split this way is moved to the
+// next page as a whole. Page breaks are reported to the function set with
+// SetAcceptPageBreakContextFunc() as breaks of kind PageBreakMultiCell.
+//
+// Upon return the current position is at the left edge of the paragraphs,
+// below the space that follows the last one, and the font and text color in
+// effect beforehand are restored.
+func (f *Fpdf) FlowText(w float64, runs []TextRunType, para ParagraphType) {
+	if f.err != nil {
+		return
+	}
+	if f.page == 0 {
+		f.AddPage()
+	}
+	x := f.x
+	if w == 0 {
+		w = f.w - f.rMargin - x
+	}
+	// The text keeps its distance from the left margin on other pages and
+	// columns
+	dx := x - f.lMargin
+	orphans, widows := para.Orphans, para.Widows
+	if orphans < 1 {
+		orphans = 1
+	}
+	if widows < 1 {
+		widows = 1
+	}
+	familyStr, styleStr, ptSize := f.fontFamily, f.fontStyleStr(), f.fontSizePt
+	r, g, b := f.GetTextColor()
+	runs = f.runDefaults(runs)
+	words := runWords(runs)
+	for start := 0; start < len(words) && f.err == nil; {
+		end := start + 1
+		for end < len(words) && !words[end].newPara {
+			end++
+		}
+		lines := f.paraLines(runs, words[start:end], w, para)
+		for j := range lines {
+			lines[j].start += start
+			lines[j].end += start
+		}
+		f.y += para.SpaceBefore
+		f.paraPut(dx, w, runs, words, lines, para.Align, para.Indent, orphans, widows)
+		f.y += para.SpaceAfter
+		start = end
+	}
+	if familyStr != "" {
+		f.SetFont(familyStr, styleStr, ptSize)
+	}
+	f.SetTextColor(r, g, b)
+	f.x = x
+}
+
+// paraLines breaks the words of a paragraph, which are styled by runs, into
+// lines of width w, the first of which is indented as specified by para
+func (f *Fpdf) paraLines(runs []TextRunType, words []frameWordType, w float64, para ParagraphType) (lines []paraLineType) {
+	// A paragraph without words makes one empty line
+	for idx := 0; ; {
+		line := paraLineType{start: idx}
+		avail := w
+		if len(lines) == 0 {
+			avail -= para.Indent
+		}
+		var maxSize float64
+		for idx < len(words) {
+			wd := f.runWordWidth(runs, words[idx])
+			sp := 0.0
+			if idx > line.start {
+				sp = f.runSpaceWidth(runs, words[idx-1])
+			}
+			if idx > line.start && line.wd+sp+wd > avail {
+				break
+			}
+			line.wd += sp + wd
+			for _, frag := range words[idx].frags {
+				maxSize = math.Max(maxSize, runs[frag.run].FontSize)
+			}
+			idx++
+		}
+		if maxSize == 0 {
+			// Empty paragraph
+			maxSize = f.fontSizePt
+		}
+		line.end = idx
+		line.fontHt = maxSize / f.k
+		line.ht = para.LineHt
+		if line.ht == 0 {
+			line.ht = f.lineHt(line.fontHt)
+		}
+		lines = append(lines, line)
+		if idx >= len(words) {
+			break
+		}
+	}
+	return
+}
+
+// paraPut prints the lines of a paragraph at horizontal distance dx from the
+// left margin, breaking pages, or moving to the next column, where the widow
+// and orphan limits allow
+func (f *Fpdf) paraPut(dx, w float64, runs []TextRunType, words []frameWordType, lines []paraLineType,
+	alignStr string, indent float64, orphans, widows int) {
+	n := len(lines)
+	// fresh is true at the top of a page or column that the paragraph has
+	// moved to
+	fresh := false
+	for j := 0; j < n && f.err == nil; {
+		// Lines that fit on the current page
+		fit, y := 0, f.y
+		for j+fit < n && y+lines[j+fit].ht <= f.pageBreakTrigger+1e-9 {
+			y += lines[j+fit].ht
+			fit++
+		}
+		count := n - j
+		if j+fit < n && !f.inHeader && !f.inFooter {
+			count = fit
+			if n-(j+count) < widows {
+				count = n - j - widows
+			}
+			if j == 0 && count < orphans {
+				count = 0
+			}
+			if count <= 0 {
+				count = 0
+				if fresh {
+					// The limits cannot be met on any page
+					count = int(math.Max(float64(fit), 1))
+				}
+			}
+		}
+		x := f.lMargin + dx
+		for k := j; k < j+count; k++ {
+			f.paraPutLine(x, w, runs, words, lines[k], alignStr, k == 0, k == n-1, indent)
+		}
+		j += count
+		if j < n {
+			page, x0, y0 := f.page, f.x, f.y
+			if f.acceptPageBreakFor(PageBreakMultiCell, f.y, lines[j].ht) {
+				f.AddPageFormat(f.curOrientation, f.curPageSize)
+			} else if f.page == page && f.x == x0 && f.y == y0 {
+				// The break has been declined: the rest of the paragraph
+				// extends below the trigger
+				x = f.lMargin + dx
+				for k := j; k < n; k++ {
+					f.paraPutLine(x, w, runs, words, lines[k], alignStr, k == 0, k == n-1, indent)
+				}
+				return
+			}
+			// The paragraph continues at the top of a new page or column
+			fresh = true
+		}
+	}
+}
+
+// paraPutLine prints a line of a paragraph at horizontal position x and the
+// current vertical position, and moves below it
+func (f *Fpdf) paraPutLine(x, w float64, runs []TextRunType, words []frameWordType, line paraLineType,
+	alignStr string, first, last bool, indent float64) {
+	if first {
+		x += indent
+		w -= indent
+	}
+	gap := 0.0
+	switch alignStr {
+	case "C":
+		x += (w - line.wd) / 2
+	case "R":
+		x += w - line.wd
+	case "J":
+		if !last && line.end-line.start > 1 {
+			gap = (w - line.wd) / float64(line.end-line.start-1)
+		}
+	}
+	baseY := f.y + 0.5*line.ht + 0.3*line.fontHt
+	for k := line.start; k < line.end; k++ {
+		if k > line.start {
+			x += f.runSpaceWidth(runs, words[k-1]) + gap
+		}
+		for _, frag := range words[k].frags {
+			run := runs[frag.run]
+			f.SetFont(run.FontFamily, run.FontStyle, run.FontSize)
+			f.SetTextColor(run.Clr.R, run.Clr.G, run.Clr.B)
+			f.Text(x, baseY, frag.str)
+			x += f.runFragWidth(runs, frag)
+		}
+	}
+	f.y += line.ht
+}
@@ -0,0 +1,77 @@
+package gofpdf
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// OutputPartial sends the PDF document to the writer specified by w, like
+// Output(), if no error has occurred. If an error has occurred while the
+// document was being generated, the pages that were completed before the
+// page on which the error occurred are sent instead, followed by a page that
+// describes the error. This lets batch processes keep and inspect documents
+// that fail rather than receiving nothing. Header and footer functions are
+// not called for the description page, since they may be the source of the
+// error.
+//
+// The error that occurred during generation is returned, so that the partial
+// document is not mistaken for a complete one; nil is returned only if the
+// document is complete. Partial output is not possible if the error occurred
+// while the document itself was being output, for example because a font
+// could not be embedded; in that case nothing is sent. After returning, f is
+// in a closed state and its methods should not be called.
+func (f *Fpdf) OutputPartial(w io.Writer) error {
+	genErr := f.err
+	if genErr == nil {
+		return f.Output(w)
+	}
+	if f.buffer.Len() > 0 {
+		return fmt.Errorf("%s; no partial output is possible once output has begun", genErr)
+	}
+	failedPage, replaced := f.page, f.state == 2
+	f.err = nil
+	f.clipNest, f.transformNest = 0, 0
+	f.inHeader, f.inFooter = false, false
+	f.headerFnc, f.footerFnc, f.footerFncLpi = nil, nil, nil
+	if replaced {
+		// Replace the incomplete page with the description of the error
+		f.pages[f.page].Reset()
+		f.pageLinks[f.page] = f.pageLinks[f.page][:0]
+		f.pageAttachments[f.page] = f.pageAttachments[f.page][:0]
+		f.fontFamily = ""
+		f.x, f.y = f.lMargin, f.tMargin
+		f.outf("%.2f w 0 G 0 g", f.lineWidth*f.k)
+	} else {
+		f.AddPage()
+	}
+	f.SetDrawColor(0, 0, 0)
+	f.SetFillColor(0, 0, 0)
+	f.SetTextColor(0, 0, 0)
+	f.SetFont("Helvetica", "B", 16)
+	f.CellFormat(0, 10, "Document generation failed", "", 1, "L", false, 0, "")
+	f.Ln(2)
+	f.SetFont("Helvetica", "", 11)
+	var str strings.Builder
+	switch {
+	case failedPage == 0:
+		str.WriteString("The error occurred before the first page was begun.")
+	case replaced:
+		fmt.Fprintf(&str, "The error occurred on page %d, which has been replaced by "+
+			"this page.", failedPage)
+		if failedPage > 1 {
+			fmt.Fprintf(&str, " Pages 1 to %d are complete.", failedPage-1)
+		}
+	default:
+		fmt.Fprintf(&str, "Pages 1 to %d are complete. The error occurred after the "+
+			"last page was completed.", failedPage)
+	}
+	f.MultiCell(0, 6, str.String(), "", "L", false)
+	f.Ln(4)
+	f.SetFont("Courier", "", 10)
+	f.MultiCell(0, 5, genErr.Error(), "", "L", false)
+	if err := f.Output(w); err != nil {
+		return fmt.Errorf("%s; partial output failed: %s", genErr, err)
+	}
+	return genErr
+}
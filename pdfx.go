@@ -0,0 +1,291 @@
+package gofpdf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PDF/X versions accepted by SetPDFX()
+const (
+	// PDFX1a is PDF/X-1a:2001, for exchange of print-ready CMYK and spot
+	// color content without transparency
+	PDFX1a = "PDF/X-1a:2001"
+	// PDFX4 is PDF/X-4, which additionally permits device RGB color and
+	// transparency
+	PDFX4 = "PDF/X-4"
+)
+
+// OutputIntentType describes the printing condition that a PDF/X document is
+// prepared for.
+type OutputIntentType struct {
+	// Identifier of the printing condition, for example "FOGRA39" or
+	// "CGATS TR 001", and an optional human readable description of it
+	ConditionID, Condition string
+	// Registry in which ConditionID is defined; empty selects
+	// "http://www.color.org"
+	RegistryName string
+	// Optional further information about the printing condition
+	Info string
+	// ICC output profile of the printing condition. It is required for
+	// PDF/X-4 and may be omitted for PDF/X-1a if ConditionID is registered.
+	Profile []byte
+	// Number of color components of Profile; zero selects 4, for CMYK
+	Components int
+}
+
+// PDFXType specifies the PDF/X conformance of a document. See SetPDFX().
+type PDFXType struct {
+	// PDFX1a or PDFX4
+	Version string
+	Intent  OutputIntentType
+	// Whether the document has been trapped
+	Trapped bool
+	// Width of the bleed around the finished page in the units passed to
+	// New()
+	Bleed float64
+}
+
+// SetPDFX prepares the document for exchange with print service providers
+// according to PDF/X-1a or PDF/X-4. The output intent identifying the
+// printing condition and the PDF/X version and trapping keys are written to
+// the document, and every page that has no trim or art box set with
+// SetPageBox() is given a trim box inset from its edges by pdfx.Bleed. If
+// the bleed is positive, the page's bleed box is set to the full page. Pages
+// should therefore be sized to include the bleed.
+//
+// When the document is closed it is checked against the requirements of
+// the version that apply to content generated by this package, and an error
+// is set if any is not met. All fonts must be embedded, so the core fonts
+// cannot be used, and the document must not be encrypted or contain
+// JavaScript, such as the actions of formatted form fields. PDF/X-1a
+// additionally forbids colors that are not gray set with SetDrawColor(),
+// SetFillColor() or SetTextColor(), color images other than CMYK images,
+// gradients and transparency; spot colors may be used. PDF/X-4 requires a
+// title set with SetTitle() and an ICC profile in the output intent, and an
+// XMP metadata packet identifying the version is generated unless one has
+// been set with SetXmpMetadata().
+func (f *Fpdf) SetPDFX(pdfx PDFXType) {
+	switch pdfx.Version {
+	case PDFX1a, PDFX4:
+	default:
+		f.err = fmt.Errorf("unsupported PDF/X version %s", pdfx.Version)
+		return
+	}
+	if pdfx.Intent.ConditionID == "" {
+		f.err = fmt.Errorf("PDF/X output intent requires a condition identifier")
+		return
+	}
+	f.pdfx = pdfx
+}
+
+// pdfxCheck sets an error if the document does not meet the requirements of
+// the PDF/X version set with SetPDFX(), and prepares the metadata
+func (f *Fpdf) pdfxCheck() {
+	if f.pdfx.Version == "" || f.err != nil {
+		return
+	}
+	x1a := f.pdfx.Version == PDFX1a
+	fail := func(reasonStr string) {
+		if f.err == nil {
+			f.err = fmt.Errorf("document does not conform to %s: %s", f.pdfx.Version, reasonStr)
+		}
+	}
+	for key, font := range f.fonts {
+		if f.fontOmitted(font) {
+			continue
+		}
+		if font.Tp == "Core" {
+			fail(fmt.Sprintf("core font %s is not embedded", font.Name))
+		}
+		if f.fontEmbedding[key].Mode == FontEmbedNone {
+			fail(fmt.Sprintf("font %s is not embedded", font.Name))
+		}
+	}
+	if f.scrubMetadata {
+		fail("the document information dates are required")
+	}
+	if f.protect.encrypted {
+		fail("encryption is not permitted")
+	}
+	if f.javascript != nil || f.formScripted() {
+		fail("JavaScript is not permitted")
+	}
+	for _, ps := range f.printStates {
+		if strings.Contains(ps.dictStr, "/TR2 <<") {
+			fail("transfer functions are not permitted")
+			break
+		}
+	}
+	if x1a {
+		if f.rgbUsed {
+			fail("RGB colors are not permitted")
+		}
+		if len(f.calSpaces) > 0 {
+			fail("calibrated colors are not permitted")
+		}
+		if len(f.gradientList) > 1 {
+			fail("gradients are not permitted")
+		}
+		for _, blend := range f.blendList[1:] {
+			if blend.fillStr != "1.000" || blend.strokeStr != "1.000" || blend.modeStr != "Normal" {
+				fail("transparency is not permitted")
+			}
+		}
+		for _, info := range f.images {
+			if info.cs == "DeviceRGB" || info.cs == "Indexed" {
+				fail("RGB images are not permitted")
+			}
+			if len(info.smask) > 0 {
+				fail("transparency is not permitted")
+			}
+		}
+		if f.compact {
+			fail("compact output is not permitted")
+		}
+		if f.pdfVersion > "1.3" {
+			fail(fmt.Sprintf("features of PDF %s are used", f.pdfVersion))
+		}
+	} else {
+		if len(f.title) == 0 {
+			fail("a title is required")
+		}
+		if len(f.pdfx.Intent.Profile) == 0 {
+			fail("an output intent profile is required")
+		}
+		if f.pdfVersion < "1.6" {
+			f.pdfVersion = "1.6"
+		}
+	}
+	if f.err != nil {
+		return
+	}
+	// The dates are fixed so that the document information and the metadata
+	// agree
+	f.creationDate = timeOrNow(f.creationDate)
+	f.modDate = timeOrNow(f.modDate)
+	if !x1a && len(f.xmp) == 0 {
+		f.xmp = f.pdfxMetadata()
+	}
+}
+
+// pdfxTrappedStr returns the value of the trapped key
+func (f *Fpdf) pdfxTrappedStr() string {
+	if f.pdfx.Trapped {
+		return "True"
+	}
+	return "False"
+}
+
+// xmlEscape escapes text for inclusion in XML
+var xmlEscape = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+// pdfxMetadata returns an XMP metadata packet for a PDF/X-4 document
+func (f *Fpdf) pdfxMetadata() []byte {
+	const dateFmt = "2006-01-02T15:04:05-07:00"
+	var b fmtBuffer
+	b.printf("<?xpacket begin=\"\xef\xbb\xbf\" id=\"W5M0MpCehiHzreSzNTczkc9d\"?>\n")
+	b.printf("<x:xmpmeta xmlns:x=\"adobe:ns:meta/\">\n")
+	b.printf("<rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\">\n")
+	b.printf("<rdf:Description rdf:about=\"\"\n")
+	b.printf(" xmlns:dc=\"http://purl.org/dc/elements/1.1/\"\n")
+	b.printf(" xmlns:xmp=\"http://ns.adobe.com/xap/1.0/\"\n")
+	b.printf(" xmlns:pdf=\"http://ns.adobe.com/pdf/1.3/\"\n")
+	b.printf(" xmlns:pdfxid=\"http://www.npes.org/pdfx/ns/id/\">\n")
+	b.printf("<dc:title><rdf:Alt><rdf:li xml:lang=\"x-default\">%s</rdf:li></rdf:Alt></dc:title>\n",
+		xmlEscape.Replace(f.title))
+	b.printf("<xmp:CreateDate>%s</xmp:CreateDate>\n", f.creationDate.Format(dateFmt))
+	b.printf("<xmp:ModifyDate>%s</xmp:ModifyDate>\n", f.modDate.Format(dateFmt))
+	b.printf("<pdf:Producer>%s</pdf:Producer>\n", xmlEscape.Replace(f.producer))
+	b.printf("<pdf:Trapped>%s</pdf:Trapped>\n", f.pdfxTrappedStr())
+	b.printf("<pdfxid:GTS_PDFXVersion>%s</pdfxid:GTS_PDFXVersion>\n", f.pdfx.Version)
+	b.printf("</rdf:Description>\n</rdf:RDF>\n</x:xmpmeta>\n")
+	b.printf("<?xpacket end=\"r\"?>")
+	return b.Bytes()
+}
+
+// pdfxPutBoxes writes the default trim and bleed boxes of page n, whose size
+// in points is wPt by hPt
+func (f *Fpdf) pdfxPutBoxes(n int, wPt, hPt float64) {
+	if f.pdfx.Version == "" {
+		return
+	}
+	boxes := f.pageBoxes[n]
+	if _, ok := boxes["TrimBox"]; ok {
+		return
+	}
+	if _, ok := boxes["ArtBox"]; ok {
+		return
+	}
+	b := f.pdfx.Bleed * f.k
+	f.outf("/TrimBox [%.2f %.2f %.2f %.2f]", b, b, wPt-b, hPt-b)
+	if _, ok := boxes["BleedBox"]; !ok && b > 0 {
+		f.outf("/BleedBox [0 0 %.2f %.2f]", wPt, hPt)
+	}
+}
+
+// pdfxPutInfo writes the PDF/X keys of the document information dictionary
+func (f *Fpdf) pdfxPutInfo() {
+	switch f.pdfx.Version {
+	case PDFX1a:
+		f.out("/GTS_PDFXVersion (PDF/X-1:2001)")
+		f.out("/GTS_PDFXConformance (PDF/X-1a:2001)")
+	case PDFX4:
+		f.out("/GTS_PDFXVersion (PDF/X-4)")
+	default:
+		return
+	}
+	f.outf("/Trapped /%s", f.pdfxTrappedStr())
+}
+
+// pdfxPutOutputIntent writes the output intent and its profile
+func (f *Fpdf) pdfxPutOutputIntent() {
+	if f.pdfx.Version == "" {
+		return
+	}
+	intent := f.pdfx.Intent
+	profileObj := 0
+	if len(intent.Profile) > 0 {
+		n := intent.Components
+		if n == 0 {
+			n = 4
+		}
+		f.newobj()
+		profileObj = f.n
+		if f.compress {
+			data := sliceCompress(intent.Profile)
+			f.outf("<</N %d /Filter /FlateDecode /Length %d>>", n, len(data))
+			f.putstream(data)
+		} else {
+			f.outf("<</N %d /Length %d>>", n, len(intent.Profile))
+			f.putstream(intent.Profile)
+		}
+		f.out("endobj")
+	}
+	registryStr := intent.RegistryName
+	if registryStr == "" {
+		registryStr = "http://www.color.org"
+	}
+	f.newobj()
+	f.outputIntentObj = f.n
+	f.out("<</Type /OutputIntent /S /GTS_PDFX")
+	f.outf("/OutputConditionIdentifier %s", f.textstring(intent.ConditionID))
+	if intent.Condition != "" {
+		f.outf("/OutputCondition %s", f.textstring(intent.Condition))
+	}
+	f.outf("/RegistryName %s", f.textstring(registryStr))
+	if intent.Info != "" {
+		f.outf("/Info %s", f.textstring(intent.Info))
+	}
+	if profileObj > 0 {
+		f.outf("/DestOutputProfile %d 0 R", profileObj)
+	}
+	f.out(">>")
+	f.out("endobj")
+}
+
+// pdfxPutCatalog writes the reference to the output intent
+func (f *Fpdf) pdfxPutCatalog() {
+	if f.outputIntentObj > 0 {
+		f.outf("/OutputIntents [%d 0 R]", f.outputIntentObj)
+	}
+}
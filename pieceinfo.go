@@ -0,0 +1,96 @@
+package gofpdf
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+)
+
+var pieceInfoAppRe = regexp.MustCompile(`^[A-Za-z0-9_.\-]+$`)
+
+// SetPieceInfo attaches data, the private data of the application appStr, to
+// the document. The data is stored in the page-piece dictionary (/PieceInfo)
+// of the document catalog, where other processors of the document leave it
+// alone, so that tooling downstream can recover structured information such
+// as the identifiers of the records the document was generated from; see
+// extract.PieceInfo(). appStr names the application, typically with a
+// prefix registered for it, and may contain letters, digits, underscores,
+// periods and hyphens. data must be valid JSON. Setting data for an
+// application that already has data replaces it; nil data removes it. The
+// data is marked as last modified at the modification date of the document
+// (see SetModificationDate()). See SetPagePieceInfo() for data that belongs
+// to a page.
+func (f *Fpdf) SetPieceInfo(appStr string, data []byte) {
+	f.setPieceInfo(0, appStr, data)
+}
+
+// SetPagePieceInfo attaches data, the private data of the application appStr,
+// to the current page, for example the identifier of the record that the page
+// presents. The data is stored in the page-piece dictionary of the page,
+// which is also marked as last modified at the modification date of the
+// document. When the document is streamed (see NewStreaming()) the data must
+// be set before the page is finished. See SetPieceInfo() for the
+// requirements on appStr and data.
+func (f *Fpdf) SetPagePieceInfo(appStr string, data []byte) {
+	if f.err != nil {
+		return
+	}
+	if f.page == 0 {
+		f.SetErrorf("page private data cannot be set before the first page")
+		return
+	}
+	f.setPieceInfo(f.page, appStr, data)
+}
+
+// setPieceInfo sets the private data of appStr for page n, or for the
+// document if n is zero
+func (f *Fpdf) setPieceInfo(n int, appStr string, data []byte) {
+	if f.err != nil {
+		return
+	}
+	if !pieceInfoAppRe.MatchString(appStr) {
+		f.SetErrorf("invalid private data application name %s", appStr)
+		return
+	}
+	if data == nil {
+		delete(f.pieceInfo[n], appStr)
+		return
+	}
+	if !json.Valid(data) {
+		f.SetErrorf("private data of application %s is not valid JSON", appStr)
+		return
+	}
+	if f.pieceInfo == nil {
+		f.pieceInfo = make(map[int]map[string][]byte)
+	}
+	if f.pieceInfo[n] == nil {
+		f.pieceInfo[n] = make(map[string][]byte)
+	}
+	f.pieceInfo[n][appStr] = append([]byte(nil), data...)
+}
+
+// pieceInfoPut writes the page-piece dictionary of page n, or of the
+// document catalog if n is zero, along with the date of last modification
+// that it requires
+func (f *Fpdf) pieceInfoPut(n int) {
+	apps := f.pieceInfo[n]
+	if len(apps) == 0 {
+		return
+	}
+	list := make([]string, 0, len(apps))
+	for appStr := range apps {
+		list = append(list, appStr)
+	}
+	sort.Strings(list)
+	dateStr := f.textstring("D:" + timeOrNow(f.modDate).Format("20060102150405"))
+	var b fmtBuffer
+	b.printf("/PieceInfo <<")
+	for _, appStr := range list {
+		b.printf(" /%s << /LastModified %s /Private %s >>", appStr, dateStr, f.textstring(string(apps[appStr])))
+	}
+	b.printf(" >>")
+	f.out(b.String())
+	if n > 0 {
+		f.outf("/LastModified %s", dateStr)
+	}
+}
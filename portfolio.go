@@ -0,0 +1,184 @@
+package gofpdf
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kinds of the fields of PortfolioFieldType
+const (
+	// PortfolioText is a field whose values are strings
+	PortfolioText = "S"
+	// PortfolioNumber is a field whose values are numbers
+	PortfolioNumber = "N"
+	// PortfolioDate is a field whose values are dates and times of type
+	// time.Time
+	PortfolioDate = "D"
+	// PortfolioFilename shows the Filename of each attachment
+	PortfolioFilename = "F"
+	// PortfolioDescription shows the Description of each attachment
+	PortfolioDescription = "Desc"
+	// PortfolioSize shows the size of the content of each attachment
+	PortfolioSize = "Size"
+	// PortfolioModDate shows the ModTime of each attachment
+	PortfolioModDate = "ModDate"
+)
+
+// Views of PortfolioType
+const (
+	// PortfolioDetails lists the files with their fields in columns
+	PortfolioDetails = "D"
+	// PortfolioTiles shows the files as tiles
+	PortfolioTiles = "T"
+	// PortfolioHidden shows the initial document, with the list of files
+	// hidden
+	PortfolioHidden = "H"
+)
+
+// PortfolioFieldType describes a field of the files of a portfolio, which
+// viewers present as a column of the list of files. See SetPortfolio().
+type PortfolioFieldType struct {
+	// Key under which the values of the field are given in the
+	// PortfolioValues of the attachments; letters, digits and underscores
+	// only
+	Key string
+	// Heading of the column
+	Name string
+	// PortfolioText, PortfolioNumber or PortfolioDate for a field whose
+	// values are given in PortfolioValues, or PortfolioFilename,
+	// PortfolioDescription, PortfolioSize or PortfolioModDate for a property
+	// of the attachments
+	Kind string
+	// Whether the column is initially hidden
+	Hidden bool
+}
+
+// PortfolioType describes the presentation of the attachments of a document
+// as a portfolio. See SetPortfolio().
+type PortfolioType struct {
+	// Fields of the files, in the order of their columns
+	Fields []PortfolioFieldType
+	// Key of the field by which the files are sorted, and whether the order
+	// is descending; an empty key leaves the order to the viewer
+	SortKey    string
+	Descending bool
+	// PortfolioDetails, PortfolioTiles or PortfolioHidden; empty selects
+	// PortfolioDetails
+	View string
+	// Filename of the attachment that is shown when the document is opened;
+	// empty shows the pages of the document, which serve as its cover sheet
+	Initial string
+}
+
+var portfolioKeyRe = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// SetPortfolio makes the document a portfolio, or collection: viewers that
+// support portfolios present the attachments set with SetAttachments() as a
+// navigable list of documents, with columns for the fields specified by pf,
+// rather than the pages of the document, which serve as its cover sheet. The
+// values of the fields of text, number and date kinds are taken from the
+// PortfolioValues of each attachment; attachments without a value for a
+// field show an empty cell. Viewers without portfolio support show the pages
+// and list the attachments as usual. The PDF version of the document is
+// raised to 1.7.
+func (f *Fpdf) SetPortfolio(pf PortfolioType) {
+	if f.err != nil {
+		return
+	}
+	keys := make(map[string]bool)
+	for _, fld := range pf.Fields {
+		if !portfolioKeyRe.MatchString(fld.Key) {
+			f.SetErrorf("invalid portfolio field key %s", fld.Key)
+			return
+		}
+		switch fld.Kind {
+		case PortfolioText, PortfolioNumber, PortfolioDate, PortfolioFilename,
+			PortfolioDescription, PortfolioSize, PortfolioModDate:
+		default:
+			f.SetErrorf("unknown kind %s of portfolio field %s", fld.Kind, fld.Key)
+			return
+		}
+		keys[fld.Key] = true
+	}
+	if pf.SortKey != "" && !keys[pf.SortKey] {
+		f.SetErrorf("portfolio sort key %s is not a field", pf.SortKey)
+		return
+	}
+	switch pf.View {
+	case "":
+		pf.View = PortfolioDetails
+	case PortfolioDetails, PortfolioTiles, PortfolioHidden:
+	default:
+		f.SetErrorf("unknown portfolio view %s", pf.View)
+		return
+	}
+	f.portfolio = &pf
+	if f.pdfVersion < "1.7" {
+		f.pdfVersion = "1.7"
+	}
+}
+
+// portfolioItem returns the collection item entry of the file specification
+// of a, which holds the values of its portfolio fields
+func (f *Fpdf) portfolioItem(a *Attachment) string {
+	if f.portfolio == nil || len(a.PortfolioValues) == 0 {
+		return ""
+	}
+	var list []string
+	for _, fld := range f.portfolio.Fields {
+		val, ok := a.PortfolioValues[fld.Key]
+		if !ok {
+			continue
+		}
+		var valStr string
+		switch v := val.(type) {
+		case string:
+			valStr = f.textstring(utf8toutf16(v))
+		case time.Time:
+			valStr = f.textstring("D:" + v.Format("20060102150405"))
+		case int:
+			valStr = strconv.Itoa(v)
+		case float64:
+			valStr = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			valStr = f.textstring(utf8toutf16(fmt.Sprint(v)))
+		}
+		list = append(list, fmt.Sprintf("/%s %s", fld.Key, valStr))
+	}
+	return fmt.Sprintf(" /CI << /Type /CollectionItem %s >>", strings.Join(list, " "))
+}
+
+// portfolioPutCatalog writes the collection dictionary of the portfolio
+func (f *Fpdf) portfolioPutCatalog() {
+	pf := f.portfolio
+	if pf == nil {
+		return
+	}
+	var b fmtBuffer
+	b.printf("/Collection << /Type /Collection /View /%s", pf.View)
+	if len(pf.Fields) > 0 {
+		b.printf(" /Schema << /Type /CollectionSchema")
+		for j, fld := range pf.Fields {
+			b.printf(" /%s << /Type /CollectionField /Subtype /%s /N %s /O %d /V %v >>",
+				fld.Key, fld.Kind, f.textstring(utf8toutf16(fld.Name)), j, !fld.Hidden)
+		}
+		b.printf(" >>")
+	}
+	if pf.SortKey != "" {
+		b.printf(" /Sort << /Type /CollectionSort /S /%s /A %v >>", pf.SortKey, !pf.Descending)
+	}
+	if pf.Initial != "" {
+		for j, a := range f.attachments {
+			if a.Filename == pf.Initial {
+				// Key of the attachment in the name tree of embedded files
+				b.printf(" /D (Attachement%d)", j+1)
+				break
+			}
+		}
+	}
+	b.printf(" >>")
+	f.out(b.String())
+}
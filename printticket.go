@@ -0,0 +1,203 @@
+package gofpdf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Duplex modes of PrintTicketType
+const (
+	// DuplexNone prints on one side of the sheet
+	DuplexNone = "simplex"
+	// DuplexLongEdge prints on both sides, turning the sheet about its long
+	// edge, as for portrait pages bound at the side
+	DuplexLongEdge = "long"
+	// DuplexShortEdge prints on both sides, turning the sheet about its short
+	// edge, as for landscape pages bound at the side
+	DuplexShortEdge = "short"
+)
+
+// PrintTicketType describes how a document is to be printed. See
+// SetPrintTicket().
+type PrintTicketType struct {
+	// Name of the print job; empty selects the document title
+	JobName string
+	// Number of copies; zero leaves it to the printer
+	Copies int
+	// DuplexNone, DuplexLongEdge or DuplexShortEdge; empty leaves it to the
+	// printer
+	Duplex string
+	// Whether the copies are collated, each copy being printed in full
+	// before the next
+	Collate bool
+	// Name of the media, for example "A4 plain", its type, for example
+	// "Paper" or "Transparency", its weight in grams per square meter and
+	// its color, for example "White". Empty and zero values are omitted.
+	MediaName, MediaType string
+	MediaWeight          float64
+	MediaColor           string
+	// Size of the media in the units passed to New(); zero selects the
+	// default page size of the document
+	MediaSize SizeType
+	// Whether the paper tray is chosen by the size of each page
+	PickTrayBySize bool
+	// Whether the viewer prints pages at their actual size rather than
+	// scaling them to the printable area
+	NoScaling bool
+	// Whether a JDF job ticket is attached to the document in addition to
+	// the viewer preferences
+	JDF bool
+	// Name of the document file referred to by the JDF job ticket; empty
+	// selects "document.pdf"
+	FileStr string
+}
+
+// SetPrintTicket specifies how the document is to be printed, for
+// workflows that send generated documents directly to production printers.
+// The number of copies, duplex mode, paper tray selection and scaling are
+// written as viewer preferences, which viewers offer as the defaults of
+// their print dialogs; viewers honor a number of copies from 2 to 5 only.
+// If pt.JDF is true, a JDF (Job Definition Format) job ticket describing the
+// copies, sides, collation and media of the job is additionally attached to
+// the document as ticket.jdf, for print servers that read job tickets
+// embedded in the documents they receive.
+func (f *Fpdf) SetPrintTicket(pt PrintTicketType) {
+	if f.err != nil {
+		return
+	}
+	switch pt.Duplex {
+	case "", DuplexNone, DuplexLongEdge, DuplexShortEdge:
+	default:
+		f.SetErrorf("unknown duplex mode %s", pt.Duplex)
+		return
+	}
+	if pt.Copies < 0 {
+		f.SetErrorf("invalid number of copies %d", pt.Copies)
+		return
+	}
+	f.printTicket = &pt
+	if f.pdfVersion < "1.7" {
+		f.pdfVersion = "1.7"
+	}
+}
+
+// GetPrintTicket returns the print ticket set with SetPrintTicket() and true,
+// or false if there is none.
+func (f *Fpdf) GetPrintTicket() (pt PrintTicketType, ok bool) {
+	if f.printTicket != nil {
+		pt, ok = *f.printTicket, true
+	}
+	return
+}
+
+// printTicketJDF returns the JDF job ticket of the print ticket
+func (f *Fpdf) printTicketJDF() []byte {
+	pt := f.printTicket
+	nameStr := pt.JobName
+	if nameStr == "" {
+		nameStr = f.title
+	}
+	fileStr := pt.FileStr
+	if fileStr == "" {
+		fileStr = "document.pdf"
+	}
+	size := pt.MediaSize
+	if size.Wd == 0 || size.Ht == 0 {
+		size = f.defPageSize
+	}
+	var b fmtBuffer
+	b.printf("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	b.printf("<JDF xmlns=\"http://www.CIP4.org/JDFSchema_1_1\" ID=\"n0001\" Type=\"Combined\"")
+	b.printf(" Types=\"LayoutPreparation DigitalPrinting\" Status=\"Waiting\" Version=\"1.3\"")
+	if nameStr != "" {
+		b.printf(" DescriptiveName=\"%s\"", xmlEscape.Replace(nameStr))
+	}
+	b.printf(">\n<ResourcePool>\n")
+	b.printf("<Media Class=\"Consumable\" ID=\"r_media\" Status=\"Available\" Dimension=\"%.2f %.2f\"",
+		size.Wd*f.k, size.Ht*f.k)
+	attr := func(nameStr, valStr string) {
+		if valStr != "" {
+			b.printf(" %s=\"%s\"", nameStr, xmlEscape.Replace(valStr))
+		}
+	}
+	attr("DescriptiveName", pt.MediaName)
+	attr("MediaType", pt.MediaType)
+	if pt.MediaWeight > 0 {
+		b.printf(" Weight=\"%.1f\"", pt.MediaWeight)
+	}
+	attr("Colour", pt.MediaColor)
+	b.printf("/>\n")
+	b.printf("<DigitalPrintingParams Class=\"Parameter\" ID=\"r_dpp\" Status=\"Available\"")
+	if pt.Collate {
+		b.printf(" Collate=\"Sheet\"")
+	} else {
+		b.printf(" Collate=\"None\"")
+	}
+	b.printf("><MediaRef rRef=\"r_media\"/></DigitalPrintingParams>\n")
+	sidesStr := ""
+	switch pt.Duplex {
+	case DuplexNone:
+		sidesStr = "OneSidedFront"
+	case DuplexLongEdge:
+		sidesStr = "TwoSidedHeadToHead"
+	case DuplexShortEdge:
+		sidesStr = "TwoSidedHeadToFoot"
+	}
+	b.printf("<LayoutPreparationParams Class=\"Parameter\" ID=\"r_lpp\" Status=\"Available\"")
+	attr("Sides", sidesStr)
+	b.printf("/>\n")
+	b.printf("<RunList Class=\"Parameter\" ID=\"r_runlist\" Status=\"Available\" NPage=\"%d\">", f.page)
+	b.printf("<LayoutElement><FileSpec MimeType=\"application/pdf\" URL=\"%s\"/></LayoutElement></RunList>\n",
+		xmlEscape.Replace(fileStr))
+	b.printf("<Component Class=\"Quantity\" ID=\"r_output\" Status=\"Unavailable\" ComponentType=\"FinalProduct\"/>\n")
+	b.printf("</ResourcePool>\n<ResourceLinkPool>\n")
+	b.printf("<RunListLink rRef=\"r_runlist\" Usage=\"Input\"/>\n")
+	b.printf("<MediaLink rRef=\"r_media\" Usage=\"Input\"/>\n")
+	b.printf("<LayoutPreparationParamsLink rRef=\"r_lpp\" Usage=\"Input\"/>\n")
+	b.printf("<DigitalPrintingParamsLink rRef=\"r_dpp\" Usage=\"Input\"/>\n")
+	b.printf("<ComponentLink rRef=\"r_output\" Usage=\"Output\"")
+	if pt.Copies > 0 {
+		b.printf(" Amount=\"%d\"", pt.Copies)
+	}
+	b.printf("/>\n</ResourceLinkPool>\n</JDF>\n")
+	return b.Bytes()
+}
+
+// printTicketPutAttachment embeds the JDF job ticket
+func (f *Fpdf) printTicketPutAttachment() {
+	if f.printTicket == nil || !f.printTicket.JDF {
+		return
+	}
+	a := Attachment{Content: f.printTicketJDF(), Filename: "ticket.jdf", Description: "JDF job ticket"}
+	f.embed(&a)
+	f.printTicketObj = a.objectNumber
+}
+
+// printTicketPutCatalog writes the viewer preferences of the print ticket
+func (f *Fpdf) printTicketPutCatalog() {
+	pt := f.printTicket
+	if pt == nil {
+		return
+	}
+	var list []string
+	switch pt.Duplex {
+	case DuplexNone:
+		list = append(list, "/Duplex /Simplex")
+	case DuplexLongEdge:
+		list = append(list, "/Duplex /DuplexFlipLongEdge")
+	case DuplexShortEdge:
+		list = append(list, "/Duplex /DuplexFlipShortEdge")
+	}
+	if pt.Copies > 0 {
+		list = append(list, fmt.Sprintf("/NumCopies %d", pt.Copies))
+	}
+	if pt.PickTrayBySize {
+		list = append(list, "/PickTrayByPDFSize true")
+	}
+	if pt.NoScaling {
+		list = append(list, "/PrintScaling /None")
+	}
+	if len(list) > 0 {
+		f.outf("/ViewerPreferences <<%s>>", strings.Join(list, " "))
+	}
+}
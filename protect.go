@@ -42,16 +42,32 @@ type protectType struct {
 	padding       []byte
 	encryptionKey []byte
 	objNum        int
-	rc4cipher     *rc4.Cipher
-	rc4n          uint32 // Object number associated with rc4 cipher
+	objKey        []byte // Key of object objKeyN, kept for its strings and streams
+	objKeyN       uint32
+	efOnly        bool   // Only embedded files are encrypted
+	actionFlag    byte   // Arguments of setProtection, kept so that the
+	userPassStr   string // keys can be generated again when efOnly changes
+	ownerPassStr  string
 }
 
+// content returns true if the content of the document, as opposed to its
+// embedded files, is encrypted
+func (p *protectType) content() bool {
+	return p.encrypted && !p.efOnly
+}
+
+// rc4 encrypts buf, a string or stream of object n, in place. Each string
+// and stream is encrypted from the start of the key stream of its object, so
+// that it can be decrypted on its own, and no state is carried from one
+// object to the next. Objects can thus be encrypted in any order as they are
+// written, which documents created with NewStreaming() rely on.
 func (p *protectType) rc4(n uint32, buf *[]byte) {
-	if p.rc4cipher == nil || p.rc4n != n {
-		p.rc4cipher, _ = rc4.NewCipher(p.objectKey(n))
-		p.rc4n = n
+	if p.objKey == nil || p.objKeyN != n {
+		p.objKey = p.objectKey(n)
+		p.objKeyN = n
 	}
-	p.rc4cipher.XORKeyStream(*buf, *buf)
+	c, _ := rc4.NewCipher(p.objKey)
+	c.XORKeyStream(*buf, *buf)
 }
 
 func (p *protectType) objectKey(n uint32) []byte {
@@ -61,7 +77,11 @@ func (p *protectType) objectKey(n uint32) []byte {
 	b = append(b, p.encryptionKey...)
 	b = append(b, nbuf[0], nbuf[1], nbuf[2], 0, 0)
 	s := md5.Sum(b)
-	return s[0:10]
+	size := len(p.encryptionKey) + 5
+	if size > 16 {
+		size = 16
+	}
+	return s[0:size]
 }
 
 func oValueGen(userPass, ownerPass []byte) (v []byte) {
@@ -83,7 +103,35 @@ func (p *protectType) uValueGen() (v []byte) {
 	return
 }
 
+// rc4Rounds encrypts buf with key and then 19 times more, each time with the
+// bytes of key exclusive-or'ed with the number of the round, as required by
+// revision 3 and later of the standard security handler
+func rc4Rounds(key, buf []byte) []byte {
+	v := make([]byte, len(buf))
+	copy(v, buf)
+	k := make([]byte, len(key))
+	for j := 0; j < 20; j++ {
+		for i := range key {
+			k[i] = key[i] ^ byte(j)
+		}
+		c, _ := rc4.NewCipher(k)
+		c.XORKeyStream(v, v)
+	}
+	return v
+}
+
+// md5Rounds returns the MD5 sum of buf hashed 50 times more, as required by
+// revision 3 and later of the standard security handler
+func md5Rounds(buf []byte) []byte {
+	sum := md5.Sum(buf)
+	for j := 0; j < 50; j++ {
+		sum = md5.Sum(sum[:])
+	}
+	return sum[:]
+}
+
 func (p *protectType) setProtection(privFlag byte, userPassStr, ownerPassStr string) {
+	p.actionFlag, p.userPassStr, p.ownerPassStr = privFlag, userPassStr, ownerPassStr
 	privFlag = 192 | (privFlag & (CnProtectCopy | CnProtectModify | CnProtectPrint | CnProtectAnnotForms))
 	p.padding = []byte{
 		0x28, 0xBF, 0x4E, 0x5E, 0x4E, 0x75, 0x8A, 0x41,
@@ -102,6 +150,21 @@ func (p *protectType) setProtection(privFlag byte, userPassStr, ownerPassStr str
 	userPass = append(userPass, p.padding...)[0:32]
 	ownerPass = append(ownerPass, p.padding...)[0:32]
 	p.encrypted = true
+	p.pValue = -(int(privFlag^255) + 1)
+	if p.efOnly {
+		// Revision 4 with 128-bit keys, which supports crypt filters. The
+		// file identifier, which is part of the key, is empty.
+		p.oValue = rc4Rounds(md5Rounds(ownerPass)[:16], userPass)
+		var buf []byte
+		buf = append(buf, userPass...)
+		buf = append(buf, p.oValue...)
+		buf = append(buf, privFlag, 0xff, 0xff, 0xff)
+		p.encryptionKey = md5Rounds(buf)[:16]
+		sum := md5.Sum(p.padding)
+		p.uValue = append(rc4Rounds(p.encryptionKey, sum[:]), p.padding[:16]...)
+		p.objKey = nil
+		return
+	}
 	p.oValue = oValueGen(userPass, ownerPass)
 	var buf []byte
 	buf = append(buf, userPass...)
@@ -110,5 +173,5 @@ func (p *protectType) setProtection(privFlag byte, userPassStr, ownerPassStr str
 	sum := md5.Sum(buf)
 	p.encryptionKey = sum[0:5]
 	p.uValue = p.uValueGen()
-	p.pValue = -(int(privFlag^255) + 1)
+	p.objKey = nil
 }
@@ -0,0 +1,528 @@
+package gofpdf
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// qrLevels maps the error correction levels of QR codes to the indexes of
+// qrBlocks
+var qrLevels = map[string]int{"L": 0, "M": 1, "Q": 2, "H": 3}
+
+// qrLevelBits holds the format information bits of the error correction
+// levels
+var qrLevelBits = [4]int{1, 0, 3, 2}
+
+// qrBlocks holds, for each version of QR code and error correction level,
+// the number of error correction codewords per block, and the number of
+// blocks and of data codewords per block of the two groups of blocks
+var qrBlocks = [40][4][5]int{
+	{{7, 1, 19, 0, 0}, {10, 1, 16, 0, 0}, {13, 1, 13, 0, 0}, {17, 1, 9, 0, 0}},
+	{{10, 1, 34, 0, 0}, {16, 1, 28, 0, 0}, {22, 1, 22, 0, 0}, {28, 1, 16, 0, 0}},
+	{{15, 1, 55, 0, 0}, {26, 1, 44, 0, 0}, {18, 2, 17, 0, 0}, {22, 2, 13, 0, 0}},
+	{{20, 1, 80, 0, 0}, {18, 2, 32, 0, 0}, {26, 2, 24, 0, 0}, {16, 4, 9, 0, 0}},
+	{{26, 1, 108, 0, 0}, {24, 2, 43, 0, 0}, {18, 2, 15, 2, 16}, {22, 2, 11, 2, 12}},
+	{{18, 2, 68, 0, 0}, {16, 4, 27, 0, 0}, {24, 4, 19, 0, 0}, {28, 4, 15, 0, 0}},
+	{{20, 2, 78, 0, 0}, {18, 4, 31, 0, 0}, {18, 2, 14, 4, 15}, {26, 4, 13, 1, 14}},
+	{{24, 2, 97, 0, 0}, {22, 2, 38, 2, 39}, {22, 4, 18, 2, 19}, {26, 4, 14, 2, 15}},
+	{{30, 2, 116, 0, 0}, {22, 3, 36, 2, 37}, {20, 4, 16, 4, 17}, {24, 4, 12, 4, 13}},
+	{{18, 2, 68, 2, 69}, {26, 4, 43, 1, 44}, {24, 6, 19, 2, 20}, {28, 6, 15, 2, 16}},
+	{{20, 4, 81, 0, 0}, {30, 1, 50, 4, 51}, {28, 4, 22, 4, 23}, {24, 3, 12, 8, 13}},
+	{{24, 2, 92, 2, 93}, {22, 6, 36, 2, 37}, {26, 4, 20, 6, 21}, {28, 7, 14, 4, 15}},
+	{{26, 4, 107, 0, 0}, {22, 8, 37, 1, 38}, {24, 8, 20, 4, 21}, {22, 12, 11, 4, 12}},
+	{{30, 3, 115, 1, 116}, {24, 4, 40, 5, 41}, {20, 11, 16, 5, 17}, {24, 11, 12, 5, 13}},
+	{{22, 5, 87, 1, 88}, {24, 5, 41, 5, 42}, {30, 5, 24, 7, 25}, {24, 11, 12, 7, 13}},
+	{{24, 5, 98, 1, 99}, {28, 7, 45, 3, 46}, {24, 15, 19, 2, 20}, {30, 3, 15, 13, 16}},
+	{{28, 1, 107, 5, 108}, {28, 10, 46, 1, 47}, {28, 1, 22, 15, 23}, {28, 2, 14, 17, 15}},
+	{{30, 5, 120, 1, 121}, {26, 9, 43, 4, 44}, {28, 17, 22, 1, 23}, {28, 2, 14, 19, 15}},
+	{{28, 3, 113, 4, 114}, {26, 3, 44, 11, 45}, {26, 17, 21, 4, 22}, {26, 9, 13, 16, 14}},
+	{{28, 3, 107, 5, 108}, {26, 3, 41, 13, 42}, {30, 15, 24, 5, 25}, {28, 15, 15, 10, 16}},
+	{{28, 4, 116, 4, 117}, {26, 17, 42, 0, 0}, {28, 17, 22, 6, 23}, {30, 19, 16, 6, 17}},
+	{{28, 2, 111, 7, 112}, {28, 17, 46, 0, 0}, {30, 7, 24, 16, 25}, {24, 34, 13, 0, 0}},
+	{{30, 4, 121, 5, 122}, {28, 4, 47, 14, 48}, {30, 11, 24, 14, 25}, {30, 16, 15, 14, 16}},
+	{{30, 6, 117, 4, 118}, {28, 6, 45, 14, 46}, {30, 11, 24, 16, 25}, {30, 30, 16, 2, 17}},
+	{{26, 8, 106, 4, 107}, {28, 8, 47, 13, 48}, {30, 7, 24, 22, 25}, {30, 22, 15, 13, 16}},
+	{{28, 10, 114, 2, 115}, {28, 19, 46, 4, 47}, {28, 28, 22, 6, 23}, {30, 33, 16, 4, 17}},
+	{{30, 8, 122, 4, 123}, {28, 22, 45, 3, 46}, {30, 8, 23, 26, 24}, {30, 12, 15, 28, 16}},
+	{{30, 3, 117, 10, 118}, {28, 3, 45, 23, 46}, {30, 4, 24, 31, 25}, {30, 11, 15, 31, 16}},
+	{{30, 7, 116, 7, 117}, {28, 21, 45, 7, 46}, {30, 1, 23, 37, 24}, {30, 19, 15, 26, 16}},
+	{{30, 5, 115, 10, 116}, {28, 19, 47, 10, 48}, {30, 15, 24, 25, 25}, {30, 23, 15, 25, 16}},
+	{{30, 13, 115, 3, 116}, {28, 2, 46, 29, 47}, {30, 42, 24, 1, 25}, {30, 23, 15, 28, 16}},
+	{{30, 17, 115, 0, 0}, {28, 10, 46, 23, 47}, {30, 10, 24, 35, 25}, {30, 19, 15, 35, 16}},
+	{{30, 17, 115, 1, 116}, {28, 14, 46, 21, 47}, {30, 29, 24, 19, 25}, {30, 11, 15, 46, 16}},
+	{{30, 13, 115, 6, 116}, {28, 14, 46, 23, 47}, {30, 44, 24, 7, 25}, {30, 59, 16, 1, 17}},
+	{{30, 12, 121, 7, 122}, {28, 12, 47, 26, 48}, {30, 39, 24, 14, 25}, {30, 22, 15, 41, 16}},
+	{{30, 6, 121, 14, 122}, {28, 6, 47, 34, 48}, {30, 46, 24, 10, 25}, {30, 2, 15, 64, 16}},
+	{{30, 17, 122, 4, 123}, {28, 29, 46, 14, 47}, {30, 49, 24, 10, 25}, {30, 24, 15, 46, 16}},
+	{{30, 4, 122, 18, 123}, {28, 13, 46, 32, 47}, {30, 48, 24, 14, 25}, {30, 42, 15, 32, 16}},
+	{{30, 20, 117, 4, 118}, {28, 40, 47, 7, 48}, {30, 43, 24, 22, 25}, {30, 10, 15, 67, 16}},
+	{{30, 19, 118, 6, 119}, {28, 18, 47, 31, 48}, {30, 34, 24, 34, 25}, {30, 20, 15, 61, 16}},
+}
+
+// Data modes of QR codes
+const (
+	qrNumeric      = 1
+	qrAlphanumeric = 2
+	qrByte         = 4
+)
+
+// qrAlphanumericChars holds the characters of the alphanumeric mode in the
+// order of their values
+const qrAlphanumericChars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+
+// qrBits accumulates the bits of the data of a QR code
+type qrBits []bool
+
+func (b *qrBits) put(val, n int) {
+	for j := n - 1; j >= 0; j-- {
+		*b = append(*b, val>>uint(j)&1 == 1)
+	}
+}
+
+// qrMode returns the most compact mode that can encode all of contentStr
+func qrMode(contentStr string) int {
+	mode := qrNumeric
+	for j := 0; j < len(contentStr); j++ {
+		c := contentStr[j]
+		switch {
+		case c >= '0' && c <= '9':
+		case strings.IndexByte(qrAlphanumericChars, c) >= 0:
+			mode = qrAlphanumeric
+		default:
+			return qrByte
+		}
+	}
+	return mode
+}
+
+// qrData returns the bits that encode contentStr in the specified mode for a
+// QR code of the specified version
+func qrData(contentStr string, mode, version int) (b qrBits) {
+	sizeIdx := 0
+	if version > 26 {
+		sizeIdx = 2
+	} else if version > 9 {
+		sizeIdx = 1
+	}
+	b.put(mode, 4)
+	switch mode {
+	case qrNumeric:
+		b.put(len(contentStr), [3]int{10, 12, 14}[sizeIdx])
+		for j := 0; j < len(contentStr); j += 3 {
+			n := len(contentStr) - j
+			if n > 3 {
+				n = 3
+			}
+			val, _ := strconv.Atoi(contentStr[j : j+n])
+			b.put(val, 3*n+1)
+		}
+	case qrAlphanumeric:
+		b.put(len(contentStr), [3]int{9, 11, 13}[sizeIdx])
+		for j := 0; j < len(contentStr); j += 2 {
+			val := strings.IndexByte(qrAlphanumericChars, contentStr[j])
+			if j+1 < len(contentStr) {
+				b.put(45*val+strings.IndexByte(qrAlphanumericChars, contentStr[j+1]), 11)
+			} else {
+				b.put(val, 6)
+			}
+		}
+	default:
+		b.put(len(contentStr), [3]int{8, 16, 16}[sizeIdx])
+		for j := 0; j < len(contentStr); j++ {
+			b.put(int(contentStr[j]), 8)
+		}
+	}
+	return
+}
+
+// qrGFMul returns the product of x and y in the Galois field used by the
+// Reed-Solomon codes of QR codes
+func qrGFMul(x, y byte) byte {
+	var z int
+	for j := 7; j >= 0; j-- {
+		z = z<<1 ^ (z>>7)*0x11d
+		z ^= int(y>>uint(j)&1) * int(x)
+	}
+	return byte(z)
+}
+
+// qrECCodewords returns the Reed-Solomon error correction codewords, n in
+// number, of the data codewords of a block
+func qrECCodewords(data []byte, n int) []byte {
+	// Coefficients of the generator polynomial, highest first and omitting
+	// the leading one
+	gen := make([]byte, n)
+	gen[n-1] = 1
+	root := byte(1)
+	for j := 0; j < n; j++ {
+		for k := range gen {
+			gen[k] = qrGFMul(gen[k], root)
+			if k+1 < n {
+				gen[k] ^= gen[k+1]
+			}
+		}
+		root = qrGFMul(root, 2)
+	}
+	rem := make([]byte, n)
+	for _, c := range data {
+		factor := c ^ rem[0]
+		copy(rem, rem[1:])
+		rem[n-1] = 0
+		for k := range rem {
+			rem[k] ^= qrGFMul(gen[k], factor)
+		}
+	}
+	return rem
+}
+
+// qrCodewords returns the data and error correction codewords of a QR code,
+// interleaved in the order in which they are placed in the symbol
+func qrCodewords(data []byte, version, level int) (cw []byte) {
+	blk := qrBlocks[version-1][level]
+	var dataBlocks, ecBlocks [][]byte
+	for g := 0; g < 2; g++ {
+		for j := 0; j < blk[1+2*g]; j++ {
+			n := blk[2+2*g]
+			dataBlocks = append(dataBlocks, data[:n])
+			ecBlocks = append(ecBlocks, qrECCodewords(data[:n], blk[0]))
+			data = data[n:]
+		}
+	}
+	for _, blocks := range [][][]byte{dataBlocks, ecBlocks} {
+		for j := 0; ; j++ {
+			more := false
+			for _, b := range blocks {
+				if j < len(b) {
+					cw = append(cw, b[j])
+					more = true
+				}
+			}
+			if !more {
+				break
+			}
+		}
+	}
+	return
+}
+
+// qrMatrixType holds the modules of a QR code under construction
+type qrMatrixType struct {
+	size     int
+	dark     [][]bool
+	function [][]bool // whether a module belongs to a function pattern
+}
+
+func (m *qrMatrixType) set(x, y int, dark bool) {
+	m.dark[y][x] = dark
+	m.function[y][x] = true
+}
+
+// putFunctionPatterns places the finder, timing and alignment patterns and
+// the version information, and reserves the modules of the format
+// information
+func (m *qrMatrixType) putFunctionPatterns(version int) {
+	n := m.size
+	for j := 0; j < n; j++ {
+		m.set(6, j, j%2 == 0)
+		m.set(j, 6, j%2 == 0)
+	}
+	pattern := func(cx, cy, r int, dark func(d int) bool) {
+		for dy := -r; dy <= r; dy++ {
+			for dx := -r; dx <= r; dx++ {
+				x, y := cx+dx, cy+dy
+				if x >= 0 && x < n && y >= 0 && y < n {
+					d := int(math.Max(math.Abs(float64(dx)), math.Abs(float64(dy))))
+					m.set(x, y, dark(d))
+				}
+			}
+		}
+	}
+	// Finder patterns, with their separators
+	finder := func(d int) bool { return d != 2 && d != 4 }
+	pattern(3, 3, 4, finder)
+	pattern(n-4, 3, 4, finder)
+	pattern(3, n-4, 4, finder)
+	if version > 1 {
+		count := version/7 + 2
+		step := 26
+		if version != 32 {
+			step = (version*4 + count*2 + 1) / (count*2 - 2) * 2
+		}
+		pos := make([]int, count)
+		pos[0] = 6
+		for j, p := count-1, version*4+10; j > 0; j, p = j-1, p-step {
+			pos[j] = p
+		}
+		for j, py := range pos {
+			for k, px := range pos {
+				// Alignment patterns do not overlap the finder patterns
+				if j == 0 && k == 0 || j == 0 && k == count-1 || j == count-1 && k == 0 {
+					continue
+				}
+				pattern(px, py, 2, func(d int) bool { return d != 1 })
+			}
+		}
+	}
+	m.putFormat(0, 0)
+	if version >= 7 {
+		rem := version
+		for j := 0; j < 12; j++ {
+			rem = rem<<1 ^ (rem>>11)*0x1f25
+		}
+		bits := version<<12 | rem
+		for j := 0; j < 18; j++ {
+			dark := bits>>uint(j)&1 == 1
+			a, b := n-11+j%3, j/3
+			m.set(a, b, dark)
+			m.set(b, a, dark)
+		}
+	}
+}
+
+// putFormat places the two copies of the format information for the
+// specified error correction level and mask
+func (m *qrMatrixType) putFormat(level, mask int) {
+	n := m.size
+	data := qrLevelBits[level]<<3 | mask
+	rem := data
+	for j := 0; j < 10; j++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(j int) bool { return bits>>uint(j)&1 == 1 }
+	for j := 0; j < 6; j++ {
+		m.set(8, j, bit(j))
+	}
+	m.set(8, 7, bit(6))
+	m.set(8, 8, bit(7))
+	m.set(7, 8, bit(8))
+	for j := 9; j < 15; j++ {
+		m.set(14-j, 8, bit(j))
+	}
+	for j := 0; j < 8; j++ {
+		m.set(n-1-j, 8, bit(j))
+	}
+	for j := 8; j < 15; j++ {
+		m.set(8, n-15+j, bit(j))
+	}
+	m.set(8, n-8, true)
+}
+
+// putCodewords places the bits of the codewords in the modules that do not
+// belong to function patterns, in pairs of columns from the lower right
+// corner upwards and downwards in turn
+func (m *qrMatrixType) putCodewords(cw []byte) {
+	n := m.size
+	j := 0
+	for right := n - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			// The vertical timing pattern is skipped
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for v := 0; v < n; v++ {
+			y := v
+			if upward {
+				y = n - 1 - v
+			}
+			for x := right; x > right-2; x-- {
+				if !m.function[y][x] && j < len(cw)*8 {
+					m.dark[y][x] = cw[j>>3]>>uint(7-j&7)&1 == 1
+					j++
+				}
+			}
+		}
+	}
+}
+
+// applyMask inverts the modules outside of the function patterns that are
+// selected by the specified mask pattern; applying a mask twice removes it
+func (m *qrMatrixType) applyMask(mask int) {
+	for y := 0; y < m.size; y++ {
+		for x := 0; x < m.size; x++ {
+			var inv bool
+			switch mask {
+			case 0:
+				inv = (x+y)%2 == 0
+			case 1:
+				inv = y%2 == 0
+			case 2:
+				inv = x%3 == 0
+			case 3:
+				inv = (x+y)%3 == 0
+			case 4:
+				inv = (x/3+y/2)%2 == 0
+			case 5:
+				inv = x*y%2+x*y%3 == 0
+			case 6:
+				inv = (x*y%2+x*y%3)%2 == 0
+			default:
+				inv = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if inv && !m.function[y][x] {
+				m.dark[y][x] = !m.dark[y][x]
+			}
+		}
+	}
+}
+
+// penalty returns the score by which the mask patterns are compared; the
+// lower, the better the symbol can be read
+func (m *qrMatrixType) penalty() (p int) {
+	n := m.size
+	finderLike := [2][11]bool{
+		{true, false, true, true, true, false, true, false, false, false, false},
+		{false, false, false, false, true, false, true, true, true, false, true},
+	}
+	// Runs and finder-like patterns in rows, then in columns
+	for pass := 0; pass < 2; pass++ {
+		at := func(j, k int) bool {
+			if pass == 0 {
+				return m.dark[j][k]
+			}
+			return m.dark[k][j]
+		}
+		for j := 0; j < n; j++ {
+			run := 1
+			for k := 1; k <= n; k++ {
+				if k < n && at(j, k) == at(j, k-1) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					p += run - 2
+				}
+				run = 1
+			}
+			for k := 0; k+11 <= n; k++ {
+				for _, pat := range finderLike {
+					match := true
+					for l, dark := range pat {
+						if at(j, k+l) != dark {
+							match = false
+							break
+						}
+					}
+					if match {
+						p += 40
+					}
+				}
+			}
+		}
+	}
+	// Blocks of two by two modules of the same color
+	darkCount := 0
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			c := m.dark[y][x]
+			if c {
+				darkCount++
+			}
+			if x+1 < n && y+1 < n && c == m.dark[y][x+1] && c == m.dark[y+1][x] && c == m.dark[y+1][x+1] {
+				p += 3
+			}
+		}
+	}
+	// Imbalance of dark and light modules, in steps of five percent
+	total := n * n
+	dev := darkCount*20 - total*10
+	if dev < 0 {
+		dev = -dev
+	}
+	p += ((dev+total-1)/total - 1) * 10
+	return
+}
+
+// qrMatrix returns the modules of a QR code, indexed by row and column, that
+// encodes contentStr with the specified error correction level, in the
+// smallest version that has room for it
+func qrMatrix(contentStr string, level int) (dark [][]bool, err error) {
+	mode := qrMode(contentStr)
+	var bits qrBits
+	version, capacity := 1, 0
+	for ; ; version++ {
+		if version > 40 {
+			return nil, fmt.Errorf("content of %d bytes is too long for a QR code", len(contentStr))
+		}
+		blk := qrBlocks[version-1][level]
+		capacity = 8 * (blk[1]*blk[2] + blk[3]*blk[4])
+		if bits = qrData(contentStr, mode, version); len(bits) <= capacity {
+			break
+		}
+	}
+	// Terminator and padding
+	for j := 0; j < 4 && len(bits) < capacity; j++ {
+		bits.put(0, 1)
+	}
+	for len(bits)%8 != 0 {
+		bits.put(0, 1)
+	}
+	for pad := 0xec; len(bits) < capacity; pad ^= 0xec ^ 0x11 {
+		bits.put(pad, 8)
+	}
+	data := make([]byte, capacity/8)
+	for j, bit := range bits {
+		if bit {
+			data[j>>3] |= 1 << uint(7-j&7)
+		}
+	}
+	m := qrMatrixType{size: 4*version + 17}
+	m.dark = make([][]bool, m.size)
+	m.function = make([][]bool, m.size)
+	for j := range m.dark {
+		m.dark[j] = make([]bool, m.size)
+		m.function[j] = make([]bool, m.size)
+	}
+	m.putFunctionPatterns(version)
+	m.putCodewords(qrCodewords(data, version, level))
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		m.applyMask(mask)
+		m.putFormat(level, mask)
+		if p := m.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		m.applyMask(mask)
+	}
+	m.applyMask(best)
+	m.putFormat(level, best)
+	return m.dark, nil
+}
+
+// QRCode draws a QR code that encodes contentStr as a square of side size
+// whose upper left corner is at (x, y). levelStr specifies the level of
+// error correction: "L", "M", "Q" or "H" restore about 7, 15, 25 and 30
+// percent of a damaged symbol, respectively; an empty string selects "M".
+// The smallest symbol with room for the content is used, in the numeric or
+// alphanumeric mode if the content consists only of digits or of the
+// characters of that mode (digits, capital letters, space and $%*+-./:),
+// and otherwise in the byte mode with the bytes of contentStr, normally
+// UTF-8 text. The symbol needs a quiet zone of four modules around it, which
+// is left to the caller. The dark modules are drawn with rectangles in the
+// current fill color. See also Barcode128().
+func (f *Fpdf) QRCode(x, y, size float64, contentStr, levelStr string) {
+	if f.err != nil {
+		return
+	}
+	if levelStr == "" {
+		levelStr = "M"
+	}
+	level, ok := qrLevels[strings.ToUpper(levelStr)]
+	if !ok {
+		f.SetErrorf("invalid QR code error correction level %s", levelStr)
+		return
+	}
+	dark, err := qrMatrix(contentStr, level)
+	if err != nil {
+		f.err = err
+		return
+	}
+	modWd := size / float64(len(dark))
+	var b fmtBuffer
+	for j, row := range dark {
+		f.barcodeModules(&b, x, y+float64(j)*modWd, modWd, modWd, row)
+	}
+	f.outf("%sf", b.String())
+}
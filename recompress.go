@@ -0,0 +1,178 @@
+package gofpdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io/ioutil"
+	"math"
+
+	"golang.org/x/image/draw"
+)
+
+// imageSourceType is an image that is placed with a resolution limit, kept
+// in its original encoding so that it can be downsampled for each size at
+// which it is printed
+type imageSourceType struct {
+	info *ImageInfoType // the image as registered without reduction
+	data []byte         // contents of the image file
+}
+
+// registerImageMaxDPI registers the image imageNameStr for printing with the
+// width and height arguments w and h, downsampled if its resolution at that
+// size exceeds options.MaxDPI. It returns the registered image and the size
+// at which to print it.
+func (f *Fpdf) registerImageMaxDPI(imageNameStr string, w, h float64, options ImageOptions) (info *ImageInfoType, wd, ht float64) {
+	if info, ok := f.images[imageNameStr]; ok {
+		// The image is embedded at full resolution already
+		wd, ht = f.imageSize(info, w, h)
+		return info, wd, ht
+	}
+	src, ok := f.imageSources[imageNameStr]
+	if !ok {
+		tp, err := imageFileType(imageNameStr, options.ImageType)
+		if err != nil {
+			f.err = err
+			return
+		}
+		src.data, f.err = ioutil.ReadFile(imageNameStr)
+		if f.err != nil {
+			return
+		}
+		src.info = f.parseImage(tp, options.ReadDpi, bytes.NewReader(src.data))
+		if f.err != nil {
+			return
+		}
+		if f.imageSources == nil {
+			f.imageSources = make(map[string]imageSourceType)
+		}
+		f.imageSources[imageNameStr] = src
+	}
+	wd, ht = f.imageSize(src.info, w, h)
+	// Ratio of the resolution limit to the resolution of the printed image
+	s := math.Min(options.MaxDPI*wd*f.k/72/src.info.w, options.MaxDPI*ht*f.k/72/src.info.h)
+	if s >= 1 || src.info.cs == "DeviceCMYK" {
+		info = &ImageInfoType{}
+		*info = *src.info
+		if options.JPEGQuality > 0 {
+			f.recompressImage(info, src.data, options.JPEGQuality)
+		}
+		f.registerImageInfo(imageNameStr, info)
+		return
+	}
+	pxWd := int(math.Max(1, math.Round(src.info.w*s)))
+	pxHt := int(math.Max(1, math.Round(src.info.h*s)))
+	keyStr := fmt.Sprintf("%s@%dx%d", imageNameStr, pxWd, pxHt)
+	if info, ok = f.images[keyStr]; ok {
+		return
+	}
+	img, _, err := image.Decode(bytes.NewReader(src.data))
+	if err != nil {
+		f.err = err
+		return
+	}
+	quality := options.JPEGQuality
+	if quality <= 0 {
+		quality = jpeg.DefaultQuality
+	}
+	info = f.imageInfoDCT(downsampleImage(img, pxWd, pxHt), quality)
+	if f.err != nil {
+		return
+	}
+	info.dpi = src.info.dpi * s
+	f.registerImageInfo(keyStr, info)
+	return
+}
+
+// registerImageInfo assigns an identifier to info and registers it under
+// imgName
+func (f *Fpdf) registerImageInfo(imgName string, info *ImageInfoType) {
+	if info.i, f.err = generateImageID(info); f.err != nil {
+		return
+	}
+	f.images[imgName] = info
+}
+
+// recompressImage re-encodes the image info, which has been parsed from the
+// file contents data, as JPEG of the specified quality if that makes it
+// smaller. CMYK images are left as they are.
+func (f *Fpdf) recompressImage(info *ImageInfoType, data []byte, quality int) {
+	if info.cs == "DeviceCMYK" {
+		return
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		f.err = err
+		return
+	}
+	dct := f.imageInfoDCT(img, quality)
+	if f.err != nil {
+		return
+	}
+	if len(dct.data)+len(dct.smask) < len(info.data)+len(info.smask) {
+		dct.dpi = info.dpi
+		*info = *dct
+	}
+}
+
+// imageInfoDCT returns img encoded as JPEG of the specified quality, with an
+// alpha channel as soft mask
+func (f *Fpdf) imageInfoDCT(img image.Image, quality int) (info *ImageInfoType) {
+	info = f.newImageInfo()
+	b := img.Bounds()
+	info.w = float64(b.Dx())
+	info.h = float64(b.Dy())
+	info.f = "DCTDecode"
+	info.bpc = 8
+	var src image.Image
+	switch img.(type) {
+	case *image.Gray, *image.Gray16:
+		gray := image.NewGray(b)
+		draw.Draw(gray, b, img, b.Min, draw.Src)
+		src = gray
+		info.cs = "DeviceGray"
+	default:
+		// Unpremultiplied colors with the alpha channel set aside
+		rgb := image.NewRGBA(b)
+		var alpha bytes.Buffer
+		opaque := true
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			alpha.WriteByte(0)
+			for x := b.Min.X; x < b.Max.X; x++ {
+				c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+				alpha.WriteByte(c.A)
+				opaque = opaque && c.A == 255
+				c.A = 255
+				rgb.SetRGBA(x, y, color.RGBA(c))
+			}
+		}
+		src = rgb
+		info.cs = "DeviceRGB"
+		if !opaque {
+			info.smask = sliceCompress(alpha.Bytes())
+			if f.pdfVersion < "1.4" {
+				f.pdfVersion = "1.4"
+			}
+		}
+	}
+	var buf bytes.Buffer
+	f.err = jpeg.Encode(&buf, src, &jpeg.Options{Quality: quality})
+	info.data = buf.Bytes()
+	return
+}
+
+// downsampleImage returns img scaled down to wd by ht pixels
+func downsampleImage(img image.Image, wd, ht int) image.Image {
+	rect := image.Rect(0, 0, wd, ht)
+	var dst draw.Image
+	switch img.(type) {
+	case *image.Gray, *image.Gray16:
+		dst = image.NewGray(rect)
+	default:
+		dst = image.NewNRGBA(rect)
+	}
+	draw.CatmullRom.Scale(dst, rect, img, img.Bounds(), draw.Src, nil)
+	return dst
+}
@@ -0,0 +1,75 @@
+package gofpdf
+
+import (
+	"sort"
+)
+
+// RegisteredImageType describes an image registered with RegisterImage(),
+// Image() or a related method.
+type RegisteredImageType struct {
+	// Name under which the image is registered
+	Name string
+	// Size of the image in pixels
+	Width, Height int
+	// Resolution used to determine the default size of the image on the page
+	DPI float64
+	// Color space, such as "DeviceRGB", "DeviceGray", "DeviceCMYK" or
+	// "Indexed", and bits per color component
+	ColorSpace       string
+	BitsPerComponent int
+	// Whether the image has an alpha channel, embedded as a soft mask
+	Alpha bool
+	// Size in bytes of the image data, including any soft mask and palette,
+	// as it is embedded in the document
+	Bytes int
+}
+
+// GetRegisteredImages returns the images that have been registered, sorted
+// by name. Images used by templates are included when the templates are
+// used in the document.
+func (f *Fpdf) GetRegisteredImages() (list []RegisteredImageType) {
+	for name, info := range f.images {
+		list = append(list, RegisteredImageType{
+			Name:             name,
+			Width:            int(info.w),
+			Height:           int(info.h),
+			DPI:              info.dpi,
+			ColorSpace:       info.cs,
+			BitsPerComponent: info.bpc,
+			Alpha:            len(info.smask) > 0,
+			Bytes:            len(info.data) + int(sectionsLen(info.sections)) + len(info.smask) + len(info.pal),
+		})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return
+}
+
+// RegisteredTemplateType describes a template used in the document.
+type RegisteredTemplateType struct {
+	// Identifier of the template, as returned by its ID() method
+	ID string
+	// Size of the template in the units of the template
+	Size SizeType
+	// Number of pages of the template
+	Pages int
+	// Number of images and nested templates used by the template
+	Images, Templates int
+}
+
+// GetRegisteredTemplates returns the templates that have been used in the
+// document with UseTemplate() or UseTemplateScaled(), including templates
+// nested within them, sorted by identifier.
+func (f *Fpdf) GetRegisteredTemplates() (list []RegisteredTemplateType) {
+	for id, tpl := range f.templates {
+		_, size := tpl.Size()
+		list = append(list, RegisteredTemplateType{
+			ID:        id,
+			Size:      size,
+			Pages:     tpl.NumPages(),
+			Images:    len(tpl.Images()),
+			Templates: len(tpl.Templates()),
+		})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return
+}
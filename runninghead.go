@@ -0,0 +1,74 @@
+package gofpdf
+
+// RunningHeadType describes the sections that appear on a completed page. It
+// is passed to the function registered with SetRunningHeadFunc() so that
+// dictionary-style running headers can be printed.
+type RunningHeadType struct {
+	// Top is the section in effect at the top of the page, that is, the last
+	// section marked on a previous page. It is empty before the first section.
+	Top string
+	// First and Last are the first and last sections marked on the page. If
+	// no section is marked on the page, both are equal to Top.
+	First, Last string
+}
+
+// sectionMarkType records a section title marked on the current page
+type sectionMarkType struct {
+	text  string
+	level int
+}
+
+// MarkSection records that a section entitled txtStr begins at the current
+// position. The titles of sections at or above the level specified in
+// SetRunningHeadFunc() are made available to the running head function of the
+// page on which they are marked. Bookmark() calls this method implicitly, so
+// only sections that are not bookmarked need to be marked explicitly. Level 0
+// is the top level, 1 is just below, and so on.
+func (f *Fpdf) MarkSection(txtStr string, level int) {
+	if f.err != nil || f.page == 0 {
+		return
+	}
+	f.sectionMarks = append(f.sectionMarks, sectionMarkType{text: txtStr, level: level})
+}
+
+// SetRunningHeadFunc sets the function that prints running headers, for
+// example the current chapter title or, as in a dictionary, the first and
+// last entries on a page. Since the sections on a page are not known until
+// the page has been filled, fnc is called when a page is complete, after all
+// of its content but before the footer function. Sections are reported from
+// calls to MarkSection() and Bookmark(); those with a level greater than
+// maxLevel are ignored. Automatic page breaks are disabled while fnc runs, so
+// it should only print in the page margin. A nil value for fnc removes the
+// running head function.
+func (f *Fpdf) SetRunningHeadFunc(maxLevel int, fnc func(head RunningHeadType)) {
+	f.runningHeadFnc = fnc
+	f.runningHeadLevel = maxLevel
+}
+
+// putRunningHead calls the running head function for the page being closed
+// and resets the section marks for the next page
+func (f *Fpdf) putRunningHead() {
+	head := RunningHeadType{Top: f.runningHeadTop}
+	first := true
+	for _, m := range f.sectionMarks {
+		if m.level > f.runningHeadLevel {
+			continue
+		}
+		if first {
+			head.First = m.text
+			first = false
+		}
+		head.Last = m.text
+	}
+	if first {
+		head.First, head.Last = head.Top, head.Top
+	}
+	f.sectionMarks = f.sectionMarks[:0]
+	f.runningHeadTop = head.Last
+	if f.runningHeadFnc != nil && f.err == nil {
+		inHeader := f.inHeader
+		f.inHeader = true
+		f.runningHeadFnc(head)
+		f.inHeader = inHeader
+	}
+}
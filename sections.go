@@ -0,0 +1,250 @@
+package gofpdf
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// sectionType is a part of the document registered with AddSection()
+type sectionType struct {
+	name  string
+	fnc   func()
+	needs []string
+	first int // first page, once rendered
+	last  int // last page, once rendered
+}
+
+// AddSection registers a section of the document named nameStr that is
+// printed by fnc when RenderSections() is called. needs lists the names of
+// the sections that must be rendered before this one, for example because
+// they compute totals that this section prints. Sections are bound in the
+// document in the order in which they are registered, regardless of the
+// order in which they are rendered.
+func (f *Fpdf) AddSection(nameStr string, fnc func(), needs ...string) {
+	if f.err != nil {
+		return
+	}
+	for _, sec := range f.sections {
+		if sec.name == nameStr {
+			f.err = fmt.Errorf("section %s has already been added", nameStr)
+			return
+		}
+	}
+	f.sections = append(f.sections, sectionType{name: nameStr, fnc: fnc, needs: needs})
+}
+
+// RenderSections prints the sections registered with AddSection(), each
+// beginning on a new page, and then arranges their pages in the order in
+// which the sections were registered. A section is rendered after the
+// sections it needs; otherwise sections are rendered in the order in which
+// they were registered. An error is set if a section needs one that has not
+// been registered or if sections need each other in a cycle. The registry is
+// cleared, so further sections can be added and rendered afterward.
+//
+// Links, bookmarks, anchors, counter references and index entries follow
+// their pages to their final positions. Footers are printed after the pages
+// have been arranged, so page numbers printed by the footer function are
+// those of the final document. Headers, running heads and page events, on the
+// other hand, are processed as the pages are rendered, and counters are
+// stepped in rendering order.
+func (f *Fpdf) RenderSections() {
+	if f.err != nil || f.streaming("rendering sections") {
+		return
+	}
+	sections := f.sections
+	f.sections = nil
+	order := sectionOrder(sections, &f.err)
+	if f.err != nil || len(order) == 0 {
+		return
+	}
+	first := len(f.pages)
+	outlineStart := len(f.outlines)
+	footerFnc, footerFncLpi := f.footerFnc, f.footerFncLpi
+	for j, n := range order {
+		f.AddPage()
+		if j == 0 {
+			// The footer of the page that precedes the sections has been
+			// printed; those of the sections are printed once the pages
+			// are in their final order
+			f.footerFnc, f.footerFncLpi = nil, nil
+		}
+		if f.err != nil {
+			break
+		}
+		sections[n].first = f.page
+		sections[n].fnc()
+		sections[n].last = len(f.pages) - 1
+	}
+	f.footerFnc, f.footerFncLpi = footerFnc, footerFncLpi
+	if f.err != nil {
+		return
+	}
+	var pageList []int
+	for _, sec := range sections {
+		for p := sec.first; p <= sec.last; p++ {
+			pageList = append(pageList, p)
+		}
+	}
+	f.reorderPages(first, pageList, outlineStart)
+	f.page = len(f.pages) - 1
+	// Print the footers of all section pages but the last, which is still
+	// open
+	for p := first; p < f.page && f.err == nil; p++ {
+		f.sectionFooter(p)
+	}
+	f.restatePage()
+}
+
+// sectionOrder returns the indexes of sections in the order in which they
+// are to be rendered, or sets *err
+func sectionOrder(sections []sectionType, err *error) (order []int) {
+	index := make(map[string]int, len(sections))
+	for j, sec := range sections {
+		index[sec.name] = j
+	}
+	for _, sec := range sections {
+		for _, nameStr := range sec.needs {
+			if _, ok := index[nameStr]; !ok {
+				*err = fmt.Errorf("section %s needs section %s, which has not been added", sec.name, nameStr)
+				return nil
+			}
+		}
+	}
+	done := make([]bool, len(sections))
+	for len(order) < len(sections) {
+		progress := false
+		for j, sec := range sections {
+			if done[j] {
+				continue
+			}
+			ready := true
+			for _, nameStr := range sec.needs {
+				ready = ready && done[index[nameStr]]
+			}
+			if ready {
+				done[j] = true
+				order = append(order, j)
+				progress = true
+				break
+			}
+		}
+		if !progress {
+			*err = fmt.Errorf("sections need each other in a cycle")
+			return nil
+		}
+	}
+	return
+}
+
+// reorderPages places the pages from first on in the order given by
+// pageList, which holds their current numbers, and updates the page numbers
+// recorded with links, bookmarks from outlineStart on, anchors, named
+// destinations, counter references, index entries, form fields, recorded
+// elements, page piece dictionaries and overflows
+func (f *Fpdf) reorderPages(first int, pageList []int, outlineStart int) {
+	newPage := make(map[int]int, len(pageList))
+	for j, p := range pageList {
+		newPage[p] = first + j
+	}
+	mapPage := func(p int) int {
+		if n, ok := newPage[p]; ok {
+			return n
+		}
+		return p
+	}
+	pages := append([]*bytes.Buffer(nil), f.pages...)
+	pageLinks := append([][]linkType(nil), f.pageLinks...)
+	pageAttachments := append([][]annotationAttach(nil), f.pageAttachments...)
+	pageSizes := make(map[int]SizeType, len(f.pageSizes))
+	pageBoxes := make(map[int]map[string]PageBox, len(f.pageBoxes))
+	for p, n := range newPage {
+		f.pages[n] = pages[p]
+		f.pageLinks[n] = pageLinks[p]
+		f.pageAttachments[n] = pageAttachments[p]
+	}
+	for p, sz := range f.pageSizes {
+		pageSizes[mapPage(p)] = sz
+	}
+	for p, boxes := range f.pageBoxes {
+		pageBoxes[mapPage(p)] = boxes
+	}
+	f.pageSizes, f.pageBoxes = pageSizes, pageBoxes
+	if f.pieceInfo != nil {
+		// Key 0 holds the data of the document
+		pieceInfo := make(map[int]map[string][]byte, len(f.pieceInfo))
+		for p, apps := range f.pieceInfo {
+			pieceInfo[mapPage(p)] = apps
+		}
+		f.pieceInfo = pieceInfo
+	}
+	for j := range f.links {
+		f.links[j].page = mapPage(f.links[j].page)
+	}
+	for j := range f.outlines {
+		f.outlines[j].p = mapPage(f.outlines[j].p)
+	}
+	list := f.outlines[outlineStart:]
+	sort.SliceStable(list, func(a, b int) bool { return list[a].p < list[b].p })
+	for nameStr, a := range f.anchors {
+		a.page = mapPage(a.page)
+		f.anchors[nameStr] = a
+	}
+	for nameStr, d := range f.namedDests {
+		d.page = mapPage(d.page)
+		f.namedDests[nameStr] = d
+	}
+	for labelStr, ref := range f.counterRefs {
+		ref.page = mapPage(ref.page)
+		f.counterRefs[labelStr] = ref
+	}
+	for j := range f.indexMarks {
+		f.indexMarks[j].page = mapPage(f.indexMarks[j].page)
+	}
+	for j := range f.elements {
+		f.elements[j].page = mapPage(f.elements[j].page)
+	}
+	for j := range f.overflows {
+		f.overflows[j].Page = mapPage(f.overflows[j].Page)
+	}
+	for j := range f.formFields {
+		for k := range f.formFields[j].widgets {
+			f.formFields[j].widgets[k].page = mapPage(f.formFields[j].widgets[k].page)
+		}
+	}
+	f.floats = f.floats[:0]
+}
+
+// sectionFooter prints the footer of page n, which is not the last page
+func (f *Fpdf) sectionFooter(n int) {
+	if f.footerFnc == nil && f.footerFncLpi == nil || f.toc != nil && n > f.toc.at {
+		return
+	}
+	curPage := f.page
+	w, h, wPt, hPt, trigger := f.w, f.h, f.wPt, f.hPt, f.pageBreakTrigger
+	f.w, f.h, _ = f.PageSize(n)
+	f.wPt, f.hPt = f.w*f.k, f.h*f.k
+	f.pageBreakTrigger = f.h - f.bMargin
+	f.page = n
+	f.restatePage()
+	f.inFooter = true
+	if f.footerFnc != nil {
+		f.footerFnc()
+	} else {
+		f.footerFncLpi(false)
+	}
+	f.inFooter = false
+	f.page = curPage
+	f.w, f.h, f.wPt, f.hPt, f.pageBreakTrigger = w, h, wPt, hPt, trigger
+}
+
+// restatePage writes the current line width, font and colors to the current
+// page, whose content may have been left in a different state
+func (f *Fpdf) restatePage() {
+	f.outf("%.2f w", f.lineWidth*f.k)
+	if f.fontFamily != "" {
+		f.outFont()
+	}
+	f.out(f.color.draw.str)
+	f.out(f.color.fill.str)
+}
@@ -0,0 +1,494 @@
+package gofpdf
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+)
+
+// Names of the process inks, which are previewed by
+// GenerateSeparationPreviews() along with the spot colors added with
+// AddSpotColor()
+const (
+	InkCyan    = "Cyan"
+	InkMagenta = "Magenta"
+	InkYellow  = "Yellow"
+	InkBlack   = "Black"
+)
+
+// SeparationPreviewType specifies the separation preview pages generated by
+// GenerateSeparationPreviews().
+type SeparationPreviewType struct {
+	// Names of the inks to preview, which are InkCyan, InkMagenta, InkYellow,
+	// InkBlack and the names of spot colors added with AddSpotColor(), in the
+	// order in which they are previewed. If empty, the process inks and then
+	// the spot colors that are used on each page are previewed.
+	Inks []string
+	// If true, the pages of the document are replaced by the previews, so
+	// that the document is a separate proof; otherwise the previews are
+	// appended to the pages of the document.
+	Only bool
+}
+
+// GenerateSeparationPreviews arranges for pages that preview the separations
+// of the document to be generated when Close() is called, explicitly or by
+// one of the Output methods. For each page of the document and each ink, a
+// page of the same size shows the amount of that ink alone as shades of gray,
+// from white for none to black for full coverage, as the plate of the ink
+// would print it. This allows the use of spot colors and the coverage of the
+// process inks to be checked without prepress software. Colors other than
+// spot colors are separated into process inks: RGB and gray colors with a
+// simple conversion that assigns the shared gray component to black, and
+// calibrated colors by way of sRGB. Overprinting and transparency are not
+// simulated, and images, templates, imported pages and gradients are left
+// out of the previews. Each preview is labeled with the ink and the number
+// of the page it shows.
+//
+// The preview pages are printed without header, footer or running head. An
+// ink in sp.Inks that is not a process ink or a spot color of the document
+// is reported as an error when the document is closed.
+func (f *Fpdf) GenerateSeparationPreviews(sp SeparationPreviewType) {
+	if f.streaming("separation previews") {
+		return
+	}
+	f.sepPreview = &SeparationPreviewType{Inks: append([]string(nil), sp.Inks...), Only: sp.Only}
+}
+
+// separationType rewrites page content for the preview of one ink
+type separationType struct {
+	spots map[string]string // ink names of spot colors by resource name
+	ink   string            // ink previewed, empty to find the inks used
+	used  map[string]bool   // inks used on the page
+	out   bytes.Buffer
+}
+
+// separationStateType is the part of the graphics state that the separation
+// tracks
+type separationStateType struct {
+	fillSpace, strokeSpace string             // current color space names
+	fill, stroke           map[string]float64 // amounts of the inks of the colors
+}
+
+// putSeparationPreviews prints the previews requested with
+// GenerateSeparationPreviews() on new pages. It is called after the last
+// page of the document has been ended.
+func (f *Fpdf) putSeparationPreviews() {
+	sp := f.sepPreview
+	f.sepPreview = nil
+	sep := separationType{spots: make(map[string]string)}
+	spotList := make([]string, len(f.spotColorMap))
+	for nameStr, clr := range f.spotColorMap {
+		sep.spots["CS"+strconv.Itoa(clr.id)] = nameStr
+		spotList[clr.id-1] = nameStr
+	}
+	for _, inkStr := range sp.Inks {
+		if _, ok := f.spotColorMap[inkStr]; !ok && inkStr != InkCyan &&
+			inkStr != InkMagenta && inkStr != InkYellow && inkStr != InkBlack {
+			f.SetErrorf("ink \"%s\" of separation preview is not a process ink or spot color", inkStr)
+			return
+		}
+	}
+	nb := f.page
+	familyStr, ptSize := f.fontFamily, f.fontSizePt
+	if familyStr == "" {
+		familyStr, ptSize = "Helvetica", 10
+	}
+	// The document is being closed, so these are not restored
+	f.headerFnc, f.footerFnc, f.footerFncLpi = nil, nil, nil
+	f.runningHeadFnc, f.pageEvents = nil, nil
+	f.autoPageBreak = false
+	f.color.draw = rgbColorValue(0, 0, 0, "G", "RG")
+	f.color.fill = rgbColorValue(0, 0, 0, "g", "rg")
+	f.color.text = rgbColorValue(0, 0, 0, "g", "rg")
+	f.colorFlag = false
+	f.dashArray = nil
+	for n := 1; n <= nb; n++ {
+		src := f.pages[n].Bytes()
+		inks := sp.Inks
+		if len(inks) == 0 {
+			sep.ink = ""
+			sep.separate(src)
+			for _, inkStr := range append([]string{InkCyan, InkMagenta, InkYellow, InkBlack}, spotList...) {
+				if sep.used[inkStr] {
+					inks = append(inks, inkStr)
+				}
+			}
+		}
+		sz := f.visualIndexPageSize(n)
+		for _, inkStr := range inks {
+			sep.ink = inkStr
+			sep.separate(src)
+			f.AddPageFormat("P", SizeType{sz.Wd / f.k, sz.Ht / f.k})
+			if f.err != nil {
+				return
+			}
+			for box, pb := range f.pageBoxes[n] {
+				f.pageBoxes[f.page][box] = pb
+			}
+			// The page content assumes the initial graphics state
+			gray := sep.gray(separationInitial(InkBlack))
+			f.outf("q %s G %s g", gray, gray)
+			f.out(sep.out.String())
+			f.out("Q")
+			f.SetFont(familyStr, "", ptSize*0.8)
+			fontHt := ptSize * 0.8 / f.k
+			f.Text(f.lMargin, f.h-math.Max(f.bMargin/2, fontHt), sprintf("Page %d, separation %s", n, inkStr))
+		}
+	}
+	f.endpage()
+	if !sp.Only || f.err != nil {
+		return
+	}
+	// The previews take the place of the pages of the document
+	count := f.page - nb
+	pageList := make([]int, 0, f.page)
+	for n := nb + 1; n <= f.page; n++ {
+		pageList = append(pageList, n)
+	}
+	for n := 1; n <= nb; n++ {
+		pageList = append(pageList, n)
+	}
+	f.reorderPages(1, pageList, len(f.outlines))
+	f.page = count
+	f.pages = f.pages[:count+1]
+	f.pageLinks = f.pageLinks[:count+1]
+	f.pageAttachments = f.pageAttachments[:count+1]
+	for n := range f.pageSizes {
+		if n > count {
+			delete(f.pageSizes, n)
+		}
+	}
+	for n := range f.pageBoxes {
+		if n > count {
+			delete(f.pageBoxes, n)
+		}
+	}
+	for n := range f.pieceInfo {
+		if n > 0 {
+			delete(f.pieceInfo, n)
+		}
+	}
+	for nameStr, a := range f.anchors {
+		if a.page > count {
+			delete(f.anchors, nameStr)
+		}
+	}
+	for nameStr, d := range f.namedDests {
+		if d.page > count {
+			delete(f.namedDests, nameStr)
+		}
+	}
+	// Bookmarks, form fields and recorded elements belong to the pages that
+	// have been removed
+	f.outlines = f.outlines[:0]
+	f.formFields = f.formFields[:0]
+	f.elements = f.elements[:0]
+}
+
+// separationInitial returns the initial color of a color space, which is
+// full coverage by inkStr
+func separationInitial(inkStr string) map[string]float64 {
+	return map[string]float64{inkStr: 1}
+}
+
+// gray returns the gray level that shows the amount of the previewed ink in
+// the color inks
+func (sep *separationType) gray(inks map[string]float64) string {
+	return strconv.FormatFloat(1-math.Max(0, math.Min(1, inks[sep.ink])), 'f', 3, 64)
+}
+
+// separate rewrites the page content src to show the amount of the ink
+// sep.ink in shades of gray, or only records the inks used on the page in
+// sep.used if sep.ink is empty
+func (sep *separationType) separate(src []byte) {
+	sep.out.Reset()
+	sep.used = make(map[string]bool)
+	st := separationStateType{
+		fill:   separationInitial(InkBlack),
+		stroke: separationInitial(InkBlack),
+	}
+	var stack []separationStateType
+	var operands []string
+	last, opStart := 0, 0
+	use := func(inks map[string]float64) {
+		for inkStr, v := range inks {
+			if v > 0.0005 {
+				sep.used[inkStr] = true
+			}
+		}
+	}
+	for pos := 0; pos < len(src); {
+		start, end := contentToken(src, pos)
+		if start == end {
+			break
+		}
+		pos = end
+		tok := string(src[start:end])
+		if !contentOperator(tok) {
+			if len(operands) == 0 {
+				opStart = start
+			}
+			if tok[0] != '%' {
+				operands = append(operands, tok)
+			}
+			continue
+		}
+		if len(operands) == 0 {
+			opStart = start
+		}
+		replaced, replStr := true, ""
+		switch tok {
+		case "q":
+			stack = append(stack, st)
+			replaced = false
+		case "Q":
+			if len(stack) > 0 {
+				st = stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+			}
+			replaced = false
+		case "cs":
+			st.fillSpace = separationSpace(operands)
+			st.fill = sep.spaceInitial(st.fillSpace)
+			replStr = sep.gray(st.fill) + " g"
+		case "CS":
+			st.strokeSpace = separationSpace(operands)
+			st.stroke = sep.spaceInitial(st.strokeSpace)
+			replStr = sep.gray(st.stroke) + " G"
+		case "g", "rg", "k", "sc", "scn":
+			st.fill = sep.colorInks(tok, st.fillSpace, operands)
+			replStr = sep.gray(st.fill) + " g"
+		case "G", "RG", "K", "SC", "SCN":
+			st.stroke = sep.colorInks(tok, st.strokeSpace, operands)
+			replStr = sep.gray(st.stroke) + " G"
+		case "sh", "Do":
+			// Gradients and external objects are left out
+		default:
+			replaced = false
+			switch tok {
+			case "f", "F", "f*", "Tj", "TJ", "'", "\"":
+				use(st.fill)
+			case "S", "s":
+				use(st.stroke)
+			case "B", "B*", "b", "b*":
+				use(st.fill)
+				use(st.stroke)
+			}
+		}
+		if replaced && sep.ink != "" {
+			sep.out.Write(src[last:opStart])
+			sep.out.WriteString(replStr)
+			last = end
+		}
+		operands = operands[:0]
+	}
+	if sep.ink != "" {
+		sep.out.Write(src[last:])
+	}
+}
+
+// separationSpace returns the name of the color space selected by the
+// operands of the cs and CS operators
+func separationSpace(operands []string) string {
+	if len(operands) == 0 || operands[0][0] != '/' {
+		return ""
+	}
+	return operands[0][1:]
+}
+
+// spaceInitial returns the inks of the initial color of color space
+// spaceStr
+func (sep *separationType) spaceInitial(spaceStr string) map[string]float64 {
+	if nameStr, ok := sep.spots[spaceStr]; ok {
+		return separationInitial(nameStr)
+	}
+	return separationInitial(InkBlack)
+}
+
+// colorInks returns the inks of the color set by the operator opStr with
+// operands in color space spaceStr, which applies to the sc and scn
+// operators
+func (sep *separationType) colorInks(opStr, spaceStr string, operands []string) map[string]float64 {
+	var vals []float64
+	for _, s := range operands {
+		if v, err := strconv.ParseFloat(s, 64); err == nil {
+			vals = append(vals, v)
+		}
+	}
+	switch opStr {
+	case "g", "G":
+		spaceStr = "DeviceGray"
+	case "rg", "RG":
+		spaceStr = "DeviceRGB"
+	case "k", "K":
+		spaceStr = "DeviceCMYK"
+	}
+	switch spaceStr {
+	case "DeviceGray":
+		if len(vals) == 1 {
+			return map[string]float64{InkBlack: 1 - vals[0]}
+		}
+	case "DeviceRGB", "CS" + calSpaceRGB:
+		if len(vals) == 3 {
+			return rgbInks(vals[0], vals[1], vals[2])
+		}
+	case "DeviceCMYK":
+		if len(vals) == 4 {
+			return map[string]float64{InkCyan: vals[0], InkMagenta: vals[1], InkYellow: vals[2], InkBlack: vals[3]}
+		}
+	case "CS" + calSpaceLab:
+		if len(vals) == 3 {
+			return rgbInks(labToRGB(vals[0], vals[1], vals[2]))
+		}
+	default:
+		if nameStr, ok := sep.spots[spaceStr]; ok && len(vals) == 1 {
+			return map[string]float64{nameStr: vals[0]}
+		}
+	}
+	return nil
+}
+
+// rgbInks separates an RGB color with components ranging from 0 to 1 into
+// process inks, assigning the gray component to black
+func rgbInks(r, g, b float64) map[string]float64 {
+	k := 1 - math.Max(r, math.Max(g, b))
+	if k >= 1 {
+		return map[string]float64{InkBlack: 1}
+	}
+	return map[string]float64{
+		InkCyan:    (1 - r - k) / (1 - k),
+		InkMagenta: (1 - g - k) / (1 - k),
+		InkYellow:  (1 - b - k) / (1 - k),
+		InkBlack:   k,
+	}
+}
+
+// labToRGB converts a CIE L*a*b* color with the D65 white point to sRGB
+// components ranging from 0 to 1
+func labToRGB(l, a, b float64) (r, g, bl float64) {
+	finv := func(t float64) float64 {
+		if t > 6.0/29 {
+			return t * t * t
+		}
+		return 3 * (6.0 / 29) * (6.0 / 29) * (t - 4.0/29)
+	}
+	// XYZ relative to the D50 white point of the Lab color space, adapted to
+	// the D65 white point of sRGB with the Bradford transform
+	fy := (l + 16) / 116
+	x50 := 0.9642 * finv(fy+a/500)
+	y50 := finv(fy)
+	z50 := 0.8249 * finv(fy-b/200)
+	x := 0.9555766*x50 - 0.0230393*y50 + 0.0631636*z50
+	y := -0.0282895*x50 + 1.0099416*y50 + 0.0210077*z50
+	z := 0.0122982*x50 - 0.0204830*y50 + 1.3299098*z50
+	gamma := func(c float64) float64 {
+		if c <= 0.0031308 {
+			c *= 12.92
+		} else {
+			c = 1.055*math.Pow(c, 1/2.4) - 0.055
+		}
+		return math.Max(0, math.Min(1, c))
+	}
+	r = gamma(3.2406*x - 1.5372*y - 0.4986*z)
+	g = gamma(-0.9689*x + 1.8758*y + 0.0415*z)
+	bl = gamma(0.0557*x - 0.2040*y + 1.0570*z)
+	return
+}
+
+// contentOperator reports whether the content stream token tok is an
+// operator rather than an operand
+func contentOperator(tok string) bool {
+	switch c := tok[0]; {
+	case c == '\'' || c == '"':
+		return true
+	case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		return tok != "true" && tok != "false" && tok != "null"
+	}
+	return false
+}
+
+// contentDelimiter reports whether c ends a name, number or operator in a
+// content stream
+func contentDelimiter(c byte) bool {
+	return bytes.IndexByte([]byte(" \t\r\n\f\x00()<>[]{}/%"), c) >= 0
+}
+
+// contentToken returns the bounds of the token of the content stream src
+// that follows pos, an array, dictionary or string being one token; start
+// equals end at the end of src
+func contentToken(src []byte, pos int) (start, end int) {
+	for pos < len(src) && bytes.IndexByte([]byte(" \t\r\n\f\x00"), src[pos]) >= 0 {
+		pos++
+	}
+	start = pos
+	if pos == len(src) {
+		return start, pos
+	}
+	switch c := src[pos]; {
+	case c == '%':
+		for pos < len(src) && src[pos] != '\r' && src[pos] != '\n' {
+			pos++
+		}
+	case c == '(' || c == '[' || c == '<':
+		pos = contentGroupEnd(src, pos)
+	case c == '/':
+		pos++
+		for pos < len(src) && !contentDelimiter(src[pos]) {
+			pos++
+		}
+	case contentDelimiter(c):
+		pos++
+	default:
+		for pos < len(src) && !contentDelimiter(src[pos]) {
+			pos++
+		}
+	}
+	return start, pos
+}
+
+// contentGroupEnd returns the position that follows the string, array or
+// dictionary that begins at pos in src
+func contentGroupEnd(src []byte, pos int) int {
+	depth := 0
+	for pos < len(src) {
+		switch {
+		case src[pos] == '(':
+			// Literal strings nest balanced parentheses
+			str := 0
+			for ; pos < len(src); pos++ {
+				if src[pos] == '\\' {
+					pos++
+				} else if src[pos] == '(' {
+					str++
+				} else if src[pos] == ')' {
+					str--
+					if str == 0 {
+						break
+					}
+				}
+			}
+		case bytes.HasPrefix(src[pos:], []byte("<<")):
+			depth++
+			pos++
+		case bytes.HasPrefix(src[pos:], []byte(">>")):
+			depth--
+			pos++
+		case src[pos] == '<':
+			for pos < len(src) && src[pos] != '>' {
+				pos++
+			}
+		case src[pos] == '[':
+			depth++
+		case src[pos] == ']':
+			depth--
+		}
+		pos++
+		if depth <= 0 {
+			break
+		}
+	}
+	if pos > len(src) {
+		pos = len(src)
+	}
+	return pos
+}
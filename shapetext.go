@@ -0,0 +1,180 @@
+package gofpdf
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// shapeSpanType is a horizontal interval inside a shape
+type shapeSpanType struct {
+	x0, x1 float64
+}
+
+// shapeSpans returns the intervals of the horizontal line at y that lie
+// inside the polygon, using the even-odd rule
+func shapeSpans(points []PointType, y float64) (spans []shapeSpanType) {
+	var xs []float64
+	n := len(points)
+	for j := 0; j < n; j++ {
+		a, b := points[j], points[(j+1)%n]
+		if (a.Y <= y && b.Y > y) || (b.Y <= y && a.Y > y) {
+			xs = append(xs, a.X+(y-a.Y)*(b.X-a.X)/(b.Y-a.Y))
+		}
+	}
+	sort.Float64s(xs)
+	for j := 0; j+1 < len(xs); j += 2 {
+		spans = append(spans, shapeSpanType{xs[j], xs[j+1]})
+	}
+	return
+}
+
+// shapeLineSpan returns the widest horizontal interval that lies inside the
+// polygon everywhere between y0 and y1. The polygon is sampled at both
+// limits and at every vertex between them, which is exact for polygons.
+func shapeLineSpan(points []PointType, y0, y1 float64) (span shapeSpanType, ok bool) {
+	const eps = 1e-6
+	ys := []float64{y0 + eps, y1 - eps}
+	for _, pt := range points {
+		if pt.Y > y0 && pt.Y < y1 {
+			ys = append(ys, pt.Y)
+		}
+	}
+	var common []shapeSpanType
+	for j, y := range ys {
+		spans := shapeSpans(points, y)
+		if j == 0 {
+			common = spans
+			continue
+		}
+		var next []shapeSpanType
+		for _, a := range common {
+			for _, b := range spans {
+				lo, hi := math.Max(a.x0, b.x0), math.Min(a.x1, b.x1)
+				if hi > lo {
+					next = append(next, shapeSpanType{lo, hi})
+				}
+			}
+		}
+		common = next
+	}
+	for _, s := range common {
+		if !ok || s.x1-s.x0 > span.x1-span.x0 {
+			span, ok = s, true
+		}
+	}
+	return
+}
+
+// ShapeText prints txtStr inside the polygon specified by points, wrapping
+// each line to the width of the polygon at that line's position. This
+// allows text to fill circles, triangles and other non-rectangular regions,
+// for example in pull-quotes. The first line is placed at the top of the
+// polygon and each subsequent line h units below the previous one. Where the
+// shape is too narrow for the next word, the line is left empty. Where the
+// shape is interrupted, such as at the waist of an hourglass, the widest
+// part is used.
+//
+// alignStr is "L" (the default), "C", "R" or "J". With "J", lines other than
+// the last of each paragraph are stretched to the full width available. Line
+// feeds in txtStr begin new paragraphs. The polygon itself is not drawn;
+// call Polygon() for that. Any text that does not fit inside the shape is
+// returned so that it can be printed elsewhere.
+func (f *Fpdf) ShapeText(points []PointType, h float64, txtStr, alignStr string) (rest string) {
+	if f.err != nil || len(points) < 3 || h <= 0 {
+		return txtStr
+	}
+	minX, minY, maxY := points[0].X, points[0].Y, points[0].Y
+	for _, pt := range points {
+		minX = math.Min(minX, pt.X)
+		minY = math.Min(minY, pt.Y)
+		maxY = math.Max(maxY, pt.Y)
+	}
+	if f.overflowReport {
+		defer func() {
+			if n := len([]rune(rest)); n > 0 {
+				f.overflow(OverflowClip, OverflowElementShape, minX, minY, float64(n), rest)
+			}
+		}()
+	}
+	var paras [][]string
+	for _, para := range strings.Split(strings.Replace(txtStr, "\r", "", -1), "\n") {
+		paras = append(paras, strings.Fields(para))
+	}
+	spaceWd := f.GetStringWidth(" ")
+	p := 0
+	for y := minY; y+h <= maxY+1e-9 && p < len(paras); y += h {
+		words := paras[p]
+		if len(words) == 0 {
+			// Empty paragraph: leave a blank line
+			p++
+			continue
+		}
+		span, ok := shapeLineSpan(points, y, y+h)
+		if !ok {
+			continue
+		}
+		x0, wd := span.x0+f.cMargin, span.x1-span.x0-2*f.cMargin
+		// Take as many words as fit
+		var n int
+		var lineWd, wordsWd float64
+		for n < len(words) {
+			wordWd := f.GetStringWidth(words[n])
+			newWd := lineWd + wordWd
+			if n > 0 {
+				newWd += spaceWd
+			}
+			if newWd > wd {
+				break
+			}
+			lineWd = newWd
+			wordsWd += wordWd
+			n++
+		}
+		if n == 0 {
+			continue
+		}
+		last := n == len(words)
+		baseY := y + 0.5*h + 0.3*f.fontSize
+		switch {
+		case alignStr == "J" && !last && n > 1:
+			gap := (wd - wordsWd) / float64(n-1)
+			x := x0
+			for _, word := range words[:n] {
+				f.Text(x, baseY, word)
+				x += f.GetStringWidth(word) + gap
+			}
+		default:
+			dx := 0.0
+			switch alignStr {
+			case "C":
+				dx = (wd - lineWd) / 2
+			case "R":
+				dx = wd - lineWd
+			}
+			f.Text(x0+dx, baseY, strings.Join(words[:n], " "))
+		}
+		paras[p] = words[n:]
+		if last {
+			p++
+		}
+	}
+	var list []string
+	for _, words := range paras[p:] {
+		list = append(list, strings.Join(words, " "))
+	}
+	return strings.Join(list, "\n")
+}
+
+// ShapeTextEllipse prints txtStr inside the ellipse centered at (x, y) with
+// horizontal and vertical radii rx and ry. It is a convenience wrapper around
+// ShapeText(), which describes the remaining arguments and the return value.
+func (f *Fpdf) ShapeTextEllipse(x, y, rx, ry, h float64, txtStr, alignStr string) (rest string) {
+	const segments = 72
+	points := make([]PointType, segments)
+	for j := range points {
+		a := 2 * math.Pi * float64(j) / segments
+		points[j] = PointType{X: x + rx*math.Cos(a), Y: y + ry*math.Sin(a)}
+	}
+	return f.ShapeText(points, h, txtStr, alignStr)
+}
@@ -0,0 +1,606 @@
+package gofpdf
+
+import (
+	"fmt"
+	"math"
+)
+
+// SetTextShaping turns the shaping of text printed in UTF-8 fonts (see
+// AddUTF8Font()) on or off. Shaping applies the OpenType layout tables of the
+// font: ligatures, conjuncts and the other forms of the glyph substitution
+// table (GSUB) replace the glyphs of the characters that they combine, and
+// marks such as vowel signs and tone marks are positioned on the glyphs they
+// belong to with the anchors of the glyph positioning table (GPOS). This is
+// required for scripts such as Devanagari and the other Indic scripts, Thai,
+// Lao and Khmer, which are unreadable when each character is shown with its
+// own glyph, and it also forms the standard ligatures of Latin fonts.
+//
+// The characters of Indic and Khmer syllables are reordered as the script
+// requires; for example, the vowel sign I of Devanagari is shown before the
+// consonant it follows, and the repha is placed after the base consonant.
+// The features that are applied are the ones that fonts enable by default;
+// scripts written from right to left, such as Arabic and Hebrew, are not
+// shaped. Glyphs that do not represent a single character are mapped back to
+// their text so that it can be extracted from the document.
+//
+// Shaping is off by default. It affects Cell(), MultiCell(), Write(), Text()
+// and ClipText(), as well as the widths returned by GetStringWidth(). Fonts
+// without layout tables, and text drawn as paths (see SetTextAsPaths()), are
+// not affected.
+func (f *Fpdf) SetTextShaping(shaping bool) {
+	f.textShaping = shaping
+}
+
+// SetKerning turns the kerning of text printed in UTF-8 fonts (see
+// AddUTF8Font()) on or off. Kerning adjusts the space between pairs of
+// letters that would otherwise look too loose or too tight, such as "AV",
+// "To" or "Ye", with the pair adjustments of the glyph positioning table
+// (GPOS) of the font, or with its kerning table (kern) if it has no kerning
+// in GPOS. The adjustments are included in the widths returned by
+// GetStringWidth(), and text is shown with TJ operators that move the glyphs
+// by them. MultiCell() and Write() break lines by the widths of the
+// characters alone, which kerning usually narrows.
+//
+// Kerning is off by default, and may be combined with text shaping (see
+// SetTextShaping()). Like shaping, it affects Cell(), MultiCell(), Write(),
+// Text() and ClipText(); fonts without kerning data, core fonts and text
+// drawn as paths (see SetTextAsPaths()) are not affected.
+func (f *Fpdf) SetKerning(kerning bool) {
+	f.kerning = kerning
+}
+
+// glyphCIDType is a glyph that shaping has substituted for text, which is
+// assigned a CID of its own
+type glyphCIDType struct {
+	gid   int   // glyph index in the font file
+	runes []int // text that the glyph represents
+	width int   // advance width in thousandths of the font size
+}
+
+// shapedGlyph is a glyph of shaped text
+type shapedGlyph struct {
+	cid    int
+	width  int // width with which the font advances the text position
+	adv    int // advance of the shaped text
+	dx, dy int // offset from the position the advances lead to
+}
+
+// Kinds of scripts, which determine the preparation of text for shaping
+const (
+	shapeKindDefault = iota
+	shapeKindNone    // not shaped
+	shapeKindIndic
+	shapeKindKhmer
+	shapeKindThai
+)
+
+// shapeScriptType describes a script for the purpose of shaping
+type shapeScriptType struct {
+	tags  []string // OpenType script tags in order of preference
+	kind  int
+	block int // first code point of the Unicode block of the script
+}
+
+var (
+	shapeScriptLatn = &shapeScriptType{tags: []string{"latn"}}
+	shapeScriptGrek = &shapeScriptType{tags: []string{"grek"}}
+	shapeScriptCyrl = &shapeScriptType{tags: []string{"cyrl"}}
+	shapeScriptArmn = &shapeScriptType{tags: []string{"armn"}}
+	shapeScriptRTL  = &shapeScriptType{kind: shapeKindNone}
+	shapeScriptSinh = &shapeScriptType{tags: []string{"sinh"}}
+	shapeScriptThai = &shapeScriptType{tags: []string{"thai"}, kind: shapeKindThai, block: 0x0E00}
+	shapeScriptLao  = &shapeScriptType{tags: []string{"lao "}, kind: shapeKindThai, block: 0x0E80}
+	shapeScriptTibt = &shapeScriptType{tags: []string{"tibt"}}
+	shapeScriptMymr = &shapeScriptType{tags: []string{"mym2", "mymr"}}
+	shapeScriptKhmr = &shapeScriptType{tags: []string{"khmr"}, kind: shapeKindKhmer, block: 0x1780}
+	// Indic scripts, by block from Devanagari (U+0900) to Malayalam (U+0D00)
+	shapeScriptsIndic = []*shapeScriptType{
+		{tags: []string{"dev2", "deva"}, kind: shapeKindIndic, block: 0x0900},
+		{tags: []string{"bng2", "beng"}, kind: shapeKindIndic, block: 0x0980},
+		{tags: []string{"gur2", "guru"}, kind: shapeKindIndic, block: 0x0A00},
+		{tags: []string{"gjr2", "gujr"}, kind: shapeKindIndic, block: 0x0A80},
+		{tags: []string{"ory2", "orya"}, kind: shapeKindIndic, block: 0x0B00},
+		{tags: []string{"tml2", "taml"}, kind: shapeKindIndic, block: 0x0B80},
+		{tags: []string{"tel2", "telu"}, kind: shapeKindIndic, block: 0x0C00},
+		{tags: []string{"knd2", "knda"}, kind: shapeKindIndic, block: 0x0C80},
+		{tags: []string{"mlm2", "mlym"}, kind: shapeKindIndic, block: 0x0D00},
+	}
+)
+
+// shapeScriptOf returns the script of rune r, or nil if r is common to
+// scripts, like spaces, digits, punctuation and combining marks
+func shapeScriptOf(r int) *shapeScriptType {
+	switch {
+	case r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= 0xC0 && r <= 0x24F && r != 0xD7 && r != 0xF7,
+		r >= 0x1E00 && r <= 0x1EFF:
+		return shapeScriptLatn
+	case r >= 0x0370 && r <= 0x03FF, r >= 0x1F00 && r <= 0x1FFF:
+		return shapeScriptGrek
+	case r >= 0x0400 && r <= 0x052F:
+		return shapeScriptCyrl
+	case r >= 0x0530 && r <= 0x058F:
+		return shapeScriptArmn
+	case r >= 0x0590 && r <= 0x08FF, r >= 0xFB1D && r <= 0xFDFF, r >= 0xFE70 && r <= 0xFEFF:
+		return shapeScriptRTL
+	case r >= 0x0900 && r <= 0x0D7F:
+		return shapeScriptsIndic[(r-0x0900)/0x80]
+	case r >= 0x0D80 && r <= 0x0DFF:
+		return shapeScriptSinh
+	case r >= 0x0E00 && r <= 0x0E7F:
+		return shapeScriptThai
+	case r >= 0x0E80 && r <= 0x0EFF:
+		return shapeScriptLao
+	case r >= 0x0F00 && r <= 0x0FFF:
+		return shapeScriptTibt
+	case r >= 0x1000 && r <= 0x109F:
+		return shapeScriptMymr
+	case r >= 0x1780 && r <= 0x17FF, r >= 0x19E0 && r <= 0x19FF:
+		return shapeScriptKhmr
+	}
+	return nil
+}
+
+// Classes of the characters of Indic and Khmer syllables
+const (
+	shapeClassOther = iota
+	shapeClassConsonant
+	shapeClassVowel // independent vowel
+	shapeClassNukta
+	shapeClassVirama // also the coeng of Khmer
+	shapeClassMatra  // dependent vowel
+	shapeClassModifier
+	shapeClassJoiner
+)
+
+// shapePreBase holds the dependent vowels that are shown before the
+// consonants of their syllable
+var shapePreBase = map[int]bool{
+	0x093F: true, 0x094E: true, 0x09BF: true, 0x09C7: true, 0x09C8: true,
+	0x0A3F: true, 0x0ABF: true, 0x0B47: true, 0x0BC6: true, 0x0BC7: true,
+	0x0BC8: true, 0x0D46: true, 0x0D47: true, 0x0D48: true, 0x17C1: true,
+	0x17C2: true, 0x17C3: true,
+}
+
+// shapeSplit holds the dependent vowels that are shown in two parts, one of
+// which precedes the consonants, and the Thai and Lao vowel signs AM
+var shapeSplit = map[int][]int{
+	0x09CB: {0x09C7, 0x09BE}, 0x09CC: {0x09C7, 0x09D7},
+	0x0B48: {0x0B47, 0x0B56}, 0x0B4B: {0x0B47, 0x0B3E}, 0x0B4C: {0x0B47, 0x0B57},
+	0x0BCA: {0x0BC6, 0x0BBE}, 0x0BCB: {0x0BC7, 0x0BBE}, 0x0BCC: {0x0BC6, 0x0BD7},
+	0x0D4A: {0x0D46, 0x0D3E}, 0x0D4B: {0x0D47, 0x0D3E}, 0x0D4C: {0x0D46, 0x0D57},
+	0x17BE: {0x17C1, 0x17BE}, 0x17BF: {0x17C1, 0x17BF}, 0x17C0: {0x17C1, 0x17C0},
+	0x17C4: {0x17C1, 0x17C4}, 0x17C5: {0x17C1, 0x17C5},
+	0x0E33: {0x0E4D, 0x0E32}, 0x0EB3: {0x0ECD, 0x0EB2},
+}
+
+// class returns the class of rune r in a syllable of the script
+func (sc *shapeScriptType) class(r int) int {
+	if r == 0x200C || r == 0x200D {
+		return shapeClassJoiner
+	}
+	o := r - sc.block
+	if o < 0 || o >= 0x80 {
+		return shapeClassOther
+	}
+	if sc.kind == shapeKindKhmer {
+		switch {
+		case o <= 0x22:
+			return shapeClassConsonant
+		case o <= 0x33:
+			return shapeClassVowel
+		case o == 0x52:
+			return shapeClassVirama
+		case o >= 0x36 && o <= 0x45:
+			return shapeClassMatra
+		case o >= 0x46 && o <= 0x53, o == 0x5D:
+			return shapeClassModifier
+		}
+		return shapeClassOther
+	}
+	switch {
+	case o >= 0x15 && o <= 0x39, o >= 0x58 && o <= 0x5F, sc.block == 0x0980 && (o == 0x70 || o == 0x71):
+		return shapeClassConsonant
+	case o >= 0x04 && o <= 0x14, o == 0x60, o == 0x61:
+		return shapeClassVowel
+	case o == 0x3C:
+		return shapeClassNukta
+	case o == 0x4D:
+		return shapeClassVirama
+	case o >= 0x3E && o <= 0x4C, o == 0x4E, o == 0x4F, o >= 0x55 && o <= 0x57, o == 0x62, o == 0x63:
+		return shapeClassMatra
+	case o <= 0x03, o >= 0x51 && o <= 0x54:
+		return shapeClassModifier
+	}
+	return shapeClassOther
+}
+
+// shapeCurrent returns the glyphs of txtStr shaped or kerned with the layout
+// tables of the current font, and false if text is neither shaped nor kerned
+// in the current font
+func (f *Fpdf) shapeCurrent(txtStr string) (glyphs []shapedGlyph, ok bool) {
+	if !f.textShaping && !f.kerning || !f.isCurrentUTF8 || f.textAsPaths || f.err != nil {
+		return
+	}
+	fontKey := getFontKey(f.fontFamily, f.fontStyle)
+	font, found := f.fonts[fontKey]
+	if !found || font.utf8File == nil {
+		return
+	}
+	lay := font.utf8File.layout()
+	if lay == nil || !f.textShaping && lay.gpos == nil && lay.kern == nil {
+		return
+	}
+	cacheKey := ""
+	if len(txtStr) <= textCacheMaxLen {
+		cacheKey = sprintf("%s\x00%t%t\x00%s", fontKey, f.textShaping, f.kerning, txtStr)
+		if glyphs, ok = f.shapeCache[cacheKey]; ok {
+			return
+		}
+	}
+	glyphs = f.shape(&font, fontKey, lay, txtStr)
+	f.fonts[fontKey] = font
+	f.currentFont = font
+	if f.err != nil {
+		return nil, false
+	}
+	if cacheKey != "" {
+		if f.shapeCache == nil || len(f.shapeCache) >= textCacheMaxEntries {
+			f.shapeCache = make(map[string][]shapedGlyph)
+		}
+		f.shapeCache[cacheKey] = glyphs
+	}
+	return glyphs, true
+}
+
+// shape returns the glyphs of txtStr in font, whose layout tables are lay,
+// assigning CIDs to the glyphs that need them. The text is shaped in runs of
+// a single script; common characters belong to the run they are in.
+func (f *Fpdf) shape(font *fontDefType, fontKey string, lay *otLayoutType, txtStr string) (glyphs []shapedGlyph) {
+	runes := []rune(txtStr)
+	scripts := make([]*shapeScriptType, len(runes))
+	sc := shapeScriptLatn
+	for _, r := range runes {
+		if s := shapeScriptOf(int(r)); s != nil {
+			sc = s
+			break
+		}
+	}
+	for j, r := range runes {
+		if s := shapeScriptOf(int(r)); s != nil {
+			sc = s
+		}
+		scripts[j] = sc
+	}
+	for start := 0; start < len(runes); {
+		end := start + 1
+		for end < len(runes) && scripts[end] == scripts[start] {
+			end++
+		}
+		buf := shapeRun(lay, scripts[start], runes[start:end], f.textShaping, f.kerning)
+		glyphs = append(glyphs, f.shapedGlyphs(font, fontKey, lay, buf)...)
+		if f.err != nil {
+			return nil
+		}
+		start = end
+	}
+	return
+}
+
+// shapeRun returns the glyphs of runes, which are written in script sc,
+// substituted and positioned with the layout tables lay if shaping is set,
+// and kerned if kerning is set
+func shapeRun(lay *otLayoutType, sc *shapeScriptType, runes []rune, shaping, kerning bool) []otGlyphType {
+	buf := make([]otGlyphType, 0, len(runes))
+	for _, r := range runes {
+		parts := []int{int(r)}
+		if split, ok := shapeSplit[int(r)]; ok && shaping && sc.kind != shapeKindNone &&
+			lay.cmap[split[0]] > 0 && lay.cmap[split[1]] > 0 {
+			parts = split
+		}
+		for _, p := range parts {
+			buf = append(buf, otGlyphType{gid: lay.cmap[p], runes: []int{p}})
+		}
+	}
+	if sc.kind == shapeKindNone {
+		for j := range buf {
+			buf[j].adv = lay.advance(buf[j].gid)
+		}
+		return buf
+	}
+	set := lay.lookupSet(sc.tags)
+	if !shaping {
+		for j := range buf {
+			buf[j].adv = lay.advance(buf[j].gid)
+		}
+		shapeKern(lay, set, buf)
+		return buf
+	}
+	switch sc.kind {
+	case shapeKindIndic, shapeKindKhmer:
+		shapeSyllables(buf, sc, set.rphf)
+	case shapeKindThai:
+		shapeThaiAm(buf, sc)
+	}
+	buf = lay.substitute(buf, set.subst)
+	if sc.kind == shapeKindIndic {
+		shapeMoveReph(buf, sc)
+	}
+	for j := range buf {
+		buf[j].adv = lay.advance(buf[j].gid)
+	}
+	if kerning {
+		shapeKern(lay, set, buf)
+	}
+	lay.position(buf, set.pos)
+	return buf
+}
+
+// shapeKern kerns the glyphs of buf with the GPOS kerning lookups of set, or
+// with the kerning table of lay if there are none
+func shapeKern(lay *otLayoutType, set *otLookupSetType, buf []otGlyphType) {
+	if len(set.kern) > 0 {
+		lay.position(buf, set.kern)
+	} else if lay.kern != nil {
+		lay.kernTable(buf)
+	}
+}
+
+// shapeSyllables marks the syllables of Indic or Khmer text in buf, which
+// holds a glyph for each character, with the forms that their characters may
+// take, and moves the dependent vowels that are shown before the consonants,
+// as well as the Khmer coeng Ro, to the beginning of the syllable. A reph is
+// formed only if rphf is set.
+func shapeSyllables(buf []otGlyphType, sc *shapeScriptType, rphf bool) {
+	class := func(j int) int { return sc.class(buf[j].runes[0]) }
+	khmer := sc.kind == shapeKindKhmer
+	ra := sc.block + 0x30
+	syl := 0
+	for i := 0; i < len(buf); {
+		cl := class(i)
+		if cl != shapeClassConsonant && cl != shapeClassVowel {
+			i++
+			continue
+		}
+		syl++
+		start, base, j := i, i, i+1
+		if cl == shapeClassConsonant {
+			for j < len(buf) && class(j) == shapeClassNukta {
+				j++
+			}
+			for j < len(buf) && class(j) == shapeClassVirama {
+				k := j + 1
+				for k < len(buf) && class(k) == shapeClassJoiner {
+					k++
+				}
+				if k == len(buf) || class(k) != shapeClassConsonant {
+					break
+				}
+				if !khmer {
+					base = k
+				}
+				j = k + 1
+				for j < len(buf) && class(j) == shapeClassNukta {
+					j++
+				}
+			}
+		}
+		consEnd := j
+		for j < len(buf) && class(j) >= shapeClassNukta {
+			j++
+		}
+		end := j
+		preStart := start
+		if !khmer {
+			// A final Ra with a virama takes a below-base or post-base form
+			if base > start+1 && buf[base].runes[0] == ra && class(base-1) == shapeClassVirama {
+				for k := base - 2; k >= start; k-- {
+					if class(k) == shapeClassConsonant {
+						base = k
+						break
+					}
+				}
+			}
+			if rphf && buf[start].runes[0] == ra && base > start+1 && class(start+1) == shapeClassVirama {
+				buf[start].mask |= otFeatRphf
+				buf[start+1].mask |= otFeatRphf
+				preStart = start + 2
+			}
+		}
+		for k := preStart; k < base; k++ {
+			buf[k].mask |= otFeatHalf
+		}
+		for k := base + 1; k < consEnd; k++ {
+			buf[k].mask |= otFeatBlwf | otFeatPref | otFeatPstf
+		}
+		buf[base].base = true
+		var pre, ro, rest []otGlyphType
+		for k := start; k < end; k++ {
+			buf[k].syl = syl
+		}
+		for k := start; k < end; k++ {
+			switch r := buf[k].runes[0]; {
+			case shapePreBase[r]:
+				pre = append(pre, buf[k])
+			case khmer && k > base && k+1 < consEnd && class(k) == shapeClassVirama && buf[k+1].runes[0] == 0x179A:
+				ro = append(ro, buf[k], buf[k+1])
+				k++
+			default:
+				rest = append(rest, buf[k])
+			}
+		}
+		copy(buf[start:], append(append(pre, ro...), rest...))
+		i = end
+	}
+}
+
+// shapeThaiAm moves the nikhahit into which the Thai or Lao vowel sign AM
+// has been decomposed before the tone marks that precede it
+func shapeThaiAm(buf []otGlyphType, sc *shapeScriptType) {
+	nikhahit := sc.block + 0x4D
+	for j := range buf {
+		if buf[j].runes[0] != nikhahit {
+			continue
+		}
+		k := j
+		for k > 0 && buf[k-1].runes[0] >= sc.block+0x48 && buf[k-1].runes[0] <= sc.block+0x4B {
+			k--
+		}
+		g := buf[j]
+		copy(buf[k+1:j+1], buf[k:j])
+		buf[k] = g
+	}
+}
+
+// shapeMoveReph moves each reph of buf after the base consonant of its
+// syllable and the below-base forms that follow it
+func shapeMoveReph(buf []otGlyphType, sc *shapeScriptType) {
+	for i := 0; i < len(buf); i++ {
+		if !buf[i].reph {
+			continue
+		}
+		buf[i].reph = false
+		syl := buf[i].syl
+		j := i + 1
+		for j < len(buf) && buf[j].syl == syl && !buf[j].base {
+			j++
+		}
+		if j == len(buf) || buf[j].syl != syl {
+			continue
+		}
+		for j++; j < len(buf) && buf[j].syl == syl && len(buf[j].runes) > 0; j++ {
+			if cl := sc.class(buf[j].runes[0]); cl != shapeClassVirama && cl != shapeClassNukta {
+				break
+			}
+		}
+		g := buf[i]
+		copy(buf[i:j-1], buf[i+1:j])
+		buf[j-1] = g
+	}
+}
+
+// shapedGlyphs returns the glyphs of buf, with the CIDs with which they are
+// shown in font and their positions in thousandths of the font size. A
+// glyph that is the glyph of a single character has the CID of the
+// character.
+func (f *Fpdf) shapedGlyphs(font *fontDefType, fontKey string, lay *otLayoutType, buf []otGlyphType) []shapedGlyph {
+	scale := func(v int) int {
+		return int(math.Round(float64(v) * 1000 / float64(lay.unitsPerEm)))
+	}
+	xs := make([]int, len(buf))
+	ys := make([]int, len(buf))
+	glyphs := make([]shapedGlyph, len(buf))
+	pen := 0
+	for j, g := range buf {
+		if g.attach > 0 && g.attach-1 < j {
+			xs[j] = xs[g.attach-1] + g.ax
+			ys[j] = ys[g.attach-1] + g.ay
+		} else {
+			xs[j] = pen + g.px
+			ys[j] = g.py
+		}
+		sg := &glyphs[j]
+		if len(g.runes) == 1 && (g.gid == 0 || g.gid == lay.cmap[g.runes[0]]) {
+			r := g.runes[0]
+			sg.cid = f.ensureCIDInternal(font, fontKey, r)
+			width, ok := font.Cw[r]
+			switch {
+			case !ok || width == 0:
+				width = font.Desc.MissingWidth
+			case width == 65535:
+				width = 0
+			}
+			sg.width = width
+		} else {
+			sg.width = scale(lay.advance(g.gid))
+			sg.cid = f.ensureGlyphCID(font, fontKey, g.gid, g.runes, sg.width)
+		}
+		if f.err != nil {
+			return nil
+		}
+		if g.attach == 0 {
+			// Kerning changes the advance of the glyph in the font
+			sg.adv = sg.width + scale(g.adv-lay.advance(g.gid))
+		}
+		sg.dx = scale(xs[j] - pen)
+		sg.dy = scale(ys[j])
+		pen += g.adv
+	}
+	return glyphs
+}
+
+// ensureGlyphCID returns the CID of glyph gid of font, which shaping has
+// substituted for the text runes and whose width is width, assigning it if
+// the glyph has not been used before. The CID is taken from the range that
+// is used for characters outside the Basic Multilingual Plane.
+func (f *Fpdf) ensureGlyphCID(font *fontDefType, fontKey string, gid int, runes []int, width int) int {
+	if cid, ok := font.glyphCIDs[gid]; ok {
+		return cid
+	}
+	if font.nextCID < cidSupplementaryFirst {
+		font.nextCID = cidSupplementaryFirst
+	}
+	if font.nextCID > cidSupplementaryLast {
+		f.err = fmt.Errorf("CID limit exceeded for font %s: more than %d characters outside the Basic Multilingual Plane and shaped glyphs",
+			fontKey, cidSupplementaryLast-cidSupplementaryFirst+1)
+		return 0
+	}
+	if font.glyphCIDs == nil {
+		font.glyphCIDs = make(map[int]int)
+		font.cidGlyphs = make(map[int]glyphCIDType)
+	}
+	cid := font.nextCID
+	font.nextCID++
+	font.glyphCIDs[gid] = cid
+	font.cidGlyphs[cid] = glyphCIDType{gid: gid, runes: runes, width: width}
+	return cid
+}
+
+// shapedWidth returns the width of shaped glyphs in thousandths of the font
+// size
+func shapedWidth(glyphs []shapedGlyph) (w int) {
+	for _, g := range glyphs {
+		w += g.adv
+	}
+	return
+}
+
+// shapedText returns the operators that show glyphs, which have been shaped
+// in the current font, at the current text position. The offsets of the
+// glyphs are applied with adjustments of the position and the text rise, and
+// the text position is left at the end of the shaped text.
+func (f *Fpdf) shapedText(glyphs []shapedGlyph) string {
+	var b fmtBuffer
+	var str []byte
+	flush := func() {
+		if len(str) > 0 {
+			b.printf("(%s)", f.escape(string(str)))
+			str = str[:0]
+		}
+	}
+	cur, pen, dy := 0, 0, 0
+	b.printf("[")
+	for _, g := range glyphs {
+		if g.dy != dy {
+			flush()
+			b.WriteString(f.contentf("] TJ %.3f Ts [", float64(g.dy)*f.fontSizePt/1000))
+			dy = g.dy
+		}
+		if x := pen + g.dx; x != cur {
+			flush()
+			b.printf(" %d ", cur-x)
+			cur = x
+		}
+		str = append(str, byte(g.cid>>8), byte(g.cid&0xFF))
+		cur += g.width
+		pen += g.adv
+	}
+	flush()
+	if cur != pen {
+		b.printf(" %d", cur-pen)
+	}
+	b.printf("] TJ")
+	if dy != 0 {
+		b.printf(" 0 Ts")
+	}
+	return b.String()
+}
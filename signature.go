@@ -0,0 +1,185 @@
+package gofpdf
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// signContentsSize is the space reserved for the signature in addition to
+// the certificates, in bytes
+const signContentsSize = 8192
+
+// DigitalSignatureType holds the key and certificates with which SignPDF()
+// signs a document, and the details recorded with the signature.
+type DigitalSignatureType struct {
+	// Private key of the signatory: an *rsa.PrivateKey, an *ecdsa.PrivateKey
+	// or another crypto.Signer with an RSA or ECDSA public key, such as a key
+	// held by a hardware token
+	Signer crypto.Signer
+	// Certificate of the signatory, followed by the intermediate
+	// certificates that lead to a root trusted by readers of the document
+	Certificates []*x509.Certificate
+	// Name of the signatory, reason for signing, location of the signatory
+	// and contact information; empty values are omitted
+	Name, Reason, Location, ContactInfo string
+	// Time of signing; the zero value selects the current time
+	Time time.Time
+}
+
+// AddSignatureField adds an empty signature field named nameStr to the
+// current page, to be signed once the document has been output with
+// SignPDF(). If w and h are positive, the signature is visible: the field
+// covers the rectangle of width w and height h with its upper left corner at
+// (x, y), in which the signature block sb is printed with SignatureBlock()
+// unless sb is nil. Otherwise the signature is invisible: the field has no
+// area on the page and readers show the signature in their signature panels
+// only. See AddTextField() for the naming of fields.
+//
+// A document may have several signature fields, which are signed one after
+// another.
+func (f *Fpdf) AddSignatureField(nameStr string, x, y, w, h float64, sb *SignatureBlockType) {
+	fld := f.newFormField(nameStr, "Sig", "", FormFieldType{})
+	if fld == nil {
+		return
+	}
+	wdg := formWidgetType{page: f.page}
+	if w > 0 && h > 0 {
+		if sb != nil {
+			f.SignatureBlock(x, y, w, h, *sb)
+			if f.err != nil {
+				return
+			}
+		}
+		wdg = f.formWidget(x, y, w, h, "")
+	}
+	fld.widgets = []formWidgetType{wdg}
+	f.addFormField(fld)
+}
+
+// SignPDF signs the signature field named fieldStr of the document pdf,
+// which has been generated by this package with a field added by
+// AddSignatureField(), and returns the signed document. The signature is
+// appended to pdf as an incremental update, which leaves the bytes of pdf
+// unchanged. A document with several signature fields is therefore signed
+// by passing the result of each call to the next, and every signature
+// continues to cover the revision of the document that it signed.
+//
+// The signature is a detached PKCS #7 signature (adbe.pkcs7.detached) of
+// a SHA-256 digest. Encrypted documents cannot be signed.
+func SignPDF(pdf []byte, fieldStr string, sig DigitalSignatureType) ([]byte, error) {
+	if sig.Signer == nil || len(sig.Certificates) == 0 {
+		return nil, fmt.Errorf("signing requires a key and a certificate")
+	}
+	tm := timeOrNow(sig.Time)
+	size := signContentsSize
+	for _, cert := range sig.Certificates {
+		size += len(cert.Raw)
+	}
+	var tail fmtBuffer
+	tail.printf(" /M %s", fdfString("D:"+tm.UTC().Format("20060102150405")+"Z"))
+	for _, entry := range []struct{ keyStr, valStr string }{
+		{"Name", sig.Name}, {"Reason", sig.Reason}, {"Location", sig.Location},
+		{"ContactInfo", sig.ContactInfo},
+	} {
+		if entry.valStr != "" {
+			tail.printf(" /%s %s", entry.keyStr, fdfString(entry.valStr))
+		}
+	}
+	return signRevision(pdf, fieldStr, "/Type /Sig /Filter /Adobe.PPKLite /SubFilter /adbe.pkcs7.detached",
+		tail.String(), size, func(digest []byte) ([]byte, error) {
+			return cmsSignedData(digest, sig, tm)
+		})
+}
+
+// signRevision appends to pdf an incremental update that sets the value of
+// the signature field named fieldStr to a signature dictionary with the
+// entries headStr and tailStr. The /Contents entry between them reserves
+// size bytes for the value that sign returns for the SHA-256 digest of the
+// signed bytes, which are all bytes of the updated document except those of
+// the /Contents value.
+func signRevision(pdf []byte, fieldStr, headStr, tailStr string, size int,
+	sign func(digest []byte) ([]byte, error)) ([]byte, error) {
+	u, err := newUpdate(pdf)
+	if err != nil {
+		return nil, err
+	}
+	num, dict, err := signField(pdf, fieldStr)
+	if err != nil {
+		return nil, err
+	}
+	sigNum := u.newobj()
+	u.putobj(num, fmt.Sprintf("%s /V %d 0 R>>", dict[:len(dict)-2], sigNum))
+	u.begin(sigNum)
+	s := &u.buf
+	s.WriteString("<<" + headStr + " /ByteRange ")
+	rangePos := s.Len()
+	s.WriteString("[0 0000000000 0000000000 0000000000] /Contents ")
+	start := s.Len()
+	s.WriteString("<" + strings.Repeat("0", 2*size) + ">")
+	end := s.Len()
+	s.WriteString(tailStr + ">>\nendobj\n")
+	b := u.finish()
+	copy(b[rangePos:], fmt.Sprintf("[0 %010d %010d %010d]", start, end, len(b)-end))
+	h := sha256.New()
+	h.Write(b[:start])
+	h.Write(b[end:])
+	contents, err := sign(h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	if len(contents) > size {
+		return nil, fmt.Errorf("signature of %d bytes exceeds the reserved %d bytes", len(contents), size)
+	}
+	hex.Encode(b[start+1:], contents)
+	return b, nil
+}
+
+// signField returns the object number and the dictionary of the latest
+// version of the unsigned signature field named fieldStr in pdf
+func signField(pdf []byte, fieldStr string) (num int, dict []byte, err error) {
+	signed := false
+	headRe := regexp.MustCompile(`(\d+) 0 obj\s*$`)
+	for pos := 0; ; {
+		j := bytes.Index(pdf[pos:], []byte("/FT /Sig"))
+		if j < 0 {
+			break
+		}
+		j += pos
+		pos = j + 1
+		head := bytes.LastIndex(pdf[:j], []byte("obj"))
+		if head < 0 {
+			continue
+		}
+		start := head + bytes.Index(pdf[head:j], []byte("<<"))
+		if start < head {
+			continue
+		}
+		m := headRe.FindSubmatch(pdf[:head+3][bytes.LastIndexAny(pdf[:head], "\r\n")+1:])
+		if m == nil {
+			continue
+		}
+		p := fdfParserType{data: pdf, pos: start}
+		val, ok := p.value().(map[string]interface{})
+		if !ok || p.err != nil || val["T"] != fieldStr {
+			continue
+		}
+		num, _ = strconv.Atoi(string(m[1]))
+		dict = pdf[start:p.pos]
+		_, signed = val["V"]
+	}
+	switch {
+	case dict == nil:
+		err = fmt.Errorf("document has no signature field %s", fieldStr)
+	case signed:
+		err = fmt.Errorf("signature field %s has already been signed", fieldStr)
+	}
+	return
+}
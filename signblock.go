@@ -0,0 +1,161 @@
+package gofpdf
+
+import (
+	"math"
+	"time"
+)
+
+// SignatureBlockType specifies the content and appearance of the block
+// printed by SignatureBlock(). Create a value with NewSignatureBlock() and
+// fill in the details of the signature.
+type SignatureBlockType struct {
+	// Handwritten signature, such as one captured with the jSignature web
+	// control and parsed with SVGBasicParse(); if nil, the image named by
+	// ImageStr is used instead
+	Signature *SVGBasicType
+	// Name of an image of the signature, as accepted by ImageOptions();
+	// ignored if Signature is set. Neither is required.
+	ImageStr string
+	// Name of the signatory, printed below the signature line
+	Name string
+	// Reason for signing and location of the signatory; empty values are
+	// omitted
+	Reason, Location string
+	// Date and time of signing; the zero value omits the date
+	Date time.Time
+	// Layout of Date as used by time.Format(); empty selects
+	// "2006-01-02 15:04 MST"
+	DateLayout string
+	// Captions preceding the reason, location and date
+	ReasonLabel, LocationLabel, DateLabel string
+	// Draw a frame around the block
+	Border bool
+	// Colors of the frame and signature line, of the handwritten signature
+	// and of the text
+	ClrLine, ClrSignature, ClrText RGBType
+	// Width of the strokes of a handwritten signature in user units; zero
+	// selects the current line width
+	SignatureLineWd float64
+	// Name under which the area of the signature, above the signature line,
+	// is recorded as an element of kind ElementSignature, so that an
+	// e-signature platform can place its signature field there; see
+	// ElementManifest(). Empty records nothing.
+	Element string
+}
+
+// NewSignatureBlock returns a variable of type SignatureBlockType that is
+// initialized to print a framed block with a dark blue signature and English
+// captions.
+func NewSignatureBlock() (sb SignatureBlockType) {
+	sb.ReasonLabel = "Reason: "
+	sb.LocationLabel = "Location: "
+	sb.DateLabel = "Date: "
+	sb.Border = true
+	sb.ClrLine = RGBType{128, 128, 128}
+	sb.ClrSignature = RGBType{0, 0, 128}
+	sb.ClrText = RGBType{0, 0, 0}
+	return
+}
+
+// SignatureBlock prints a signature block in the rectangle of width w and
+// height h with its upper left corner at (x, y). The left half of the block
+// holds the handwritten signature, scaled to fit above a line, and the name
+// of the signatory below the line. The right half lists the reason, location
+// and date of the signature. Text is printed in the current font, reduced in
+// size where necessary to fit the block. The current position, font size,
+// colors, line width and line cap style are not changed.
+func (f *Fpdf) SignatureBlock(x, y, w, h float64, sb SignatureBlockType) {
+	if f.err != nil {
+		return
+	}
+	state := StateGet(f)
+	capStyle := f.capStyle
+	curX, curY := f.GetXY()
+	_, fontSize := f.GetFontSize()
+	defer func() {
+		if f.capStyle != capStyle {
+			f.capStyle = capStyle
+			f.outf("%d J", f.capStyle)
+		}
+		state.Put(f)
+		f.SetXY(curX, curY)
+	}()
+
+	pad := math.Min(w, h) * 0.06
+	if sb.Border {
+		f.SetDrawColor(sb.ClrLine.R, sb.ClrLine.G, sb.ClrLine.B)
+		f.Rect(x, y, w, h, "D")
+	}
+	leftWd := (w - 3*pad) / 2
+	lineHt := 1.25 * fontSize
+
+	// Signature, line and name
+	nameSize := fontSize
+	if sb.Name != "" {
+		if wd := f.GetStringWidth(sb.Name); wd > leftWd {
+			nameSize = fontSize * leftWd / wd
+		}
+	}
+	lineY := y + h - pad - 1.25*nameSize
+	sigX, sigWd, sigHt := x+pad, leftWd, lineY-y-2*pad
+	if sb.Signature != nil && sb.Signature.Wd > 0 && sb.Signature.Ht > 0 && sigHt > 0 {
+		scale := math.Min(sigWd/sb.Signature.Wd, sigHt/sb.Signature.Ht)
+		f.SetLineCapStyle("round")
+		if sb.SignatureLineWd > 0 {
+			f.SetLineWidth(sb.SignatureLineWd)
+		}
+		f.SetDrawColor(sb.ClrSignature.R, sb.ClrSignature.G, sb.ClrSignature.B)
+		f.SetXY(sigX+(sigWd-scale*sb.Signature.Wd)/2, lineY-pad-scale*sb.Signature.Ht)
+		f.SVGBasicWrite(sb.Signature, scale)
+	} else if sb.ImageStr != "" && sigHt > 0 {
+		info := f.RegisterImageOptions(sb.ImageStr, ImageOptions{ReadDpi: true})
+		if f.err != nil {
+			return
+		}
+		imgWd, imgHt := info.Extent()
+		scale := math.Min(sigWd/imgWd, sigHt/imgHt)
+		f.ImageOptions(sb.ImageStr, sigX+(sigWd-scale*imgWd)/2, lineY-pad-scale*imgHt,
+			scale*imgWd, scale*imgHt, false, ImageOptions{ReadDpi: true}, 0, "")
+	}
+	f.SetLineWidth(state.lineWd)
+	f.SetDrawColor(sb.ClrLine.R, sb.ClrLine.G, sb.ClrLine.B)
+	f.Line(x+pad, lineY, x+pad+leftWd, lineY)
+	f.RecordElement(sb.Element, ElementSignature, sigX, y+pad, sigWd, lineY-y-pad)
+	f.SetTextColor(sb.ClrText.R, sb.ClrText.G, sb.ClrText.B)
+	if sb.Name != "" {
+		f.SetFontUnitSize(nameSize)
+		f.Text(x+pad+(leftWd-f.GetStringWidth(sb.Name))/2, lineY+1.1*nameSize, sb.Name)
+	}
+
+	// Details, reduced to fit the right half of the block
+	var lines []string
+	add := func(labelStr, valueStr string) {
+		if valueStr != "" {
+			lines = append(lines, labelStr+valueStr)
+		}
+	}
+	add(sb.ReasonLabel, sb.Reason)
+	add(sb.LocationLabel, sb.Location)
+	if !sb.Date.IsZero() {
+		layoutStr := sb.DateLayout
+		if layoutStr == "" {
+			layoutStr = "2006-01-02 15:04 MST"
+		}
+		add(sb.DateLabel, sb.Date.Format(layoutStr))
+	}
+	if len(lines) > 0 {
+		rightX, rightWd := x+2*pad+leftWd, w-3*pad-leftWd
+		f.SetFontUnitSize(fontSize)
+		scale := math.Min(1, (h-2*pad)/(float64(len(lines))*lineHt))
+		for _, str := range lines {
+			if wd := f.GetStringWidth(str); wd*scale > rightWd {
+				scale = rightWd / wd
+			}
+		}
+		f.SetFontUnitSize(fontSize * scale)
+		top := y + (h-float64(len(lines))*lineHt*scale)/2
+		for j, str := range lines {
+			f.Text(rightX, top+(float64(j)+0.5)*lineHt*scale+0.3*f.fontSize, str)
+		}
+	}
+}
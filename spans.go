@@ -0,0 +1,129 @@
+package gofpdf
+
+import (
+	"strings"
+)
+
+// CellSpans is like MultiCell(), except that the text is made up of styled
+// runs, as described for PourText(), which are wrapped together as if they
+// were one text. Bold, italic and colored fragments can thus be mixed within
+// a cell without tracking the position of each. A line feed in a run begins
+// a new line.
+//
+// w is the width of the cells, or zero for cells that reach to the right
+// margin. h is the height of each line; zero selects the line height of the
+// document for the largest font on each line, and a negative value the line
+// height set with SetLineHeight(). borderStr, alignStr and fill are as for
+// MultiCell(). Upon return the font and text color in effect beforehand are
+// restored.
+func (f *Fpdf) CellSpans(w, h float64, runs []TextRunType, borderStr, alignStr string, fill bool) {
+	if f.err != nil {
+		return
+	}
+	prevKind := f.cellBreakKind
+	f.cellBreakKind = PageBreakMultiCell
+	defer func() { f.cellBreakKind = prevKind }()
+	if h < 0 {
+		h = f.GetLineHeight()
+	}
+	if alignStr == "" {
+		alignStr = "J"
+	}
+	x := f.x
+	if w == 0 {
+		w = f.w - f.rMargin - x
+	}
+	familyStr, styleStr, ptSize := f.fontFamily, f.fontStyleStr(), f.fontSizePt
+	r, g, b := f.GetTextColor()
+	runs = f.runDefaults(runs)
+	words := runWords(runs)
+	para := ParagraphType{LineHt: h}
+	var lines []paraLineType
+	// last records the lines that end a paragraph
+	var last []bool
+	// Text without words, such as nil runs or runs of spaces, makes one
+	// empty line, as an empty string does for MultiCell()
+	for start := 0; ; {
+		end := start + 1
+		for end < len(words) && !words[end].newPara {
+			end++
+		}
+		if end > len(words) {
+			end = len(words)
+		}
+		for _, line := range f.paraLines(runs, words[start:end], w-2*f.cMargin, para) {
+			line.start += start
+			line.end += start
+			lines = append(lines, line)
+			last = append(last, line.end == end)
+		}
+		if end >= len(words) {
+			break
+		}
+		start = end
+	}
+
+	// Borders as drawn by MultiCell()
+	firstStr, midStr := "", ""
+	if borderStr == "1" {
+		borderStr = "LTRB"
+	}
+	for _, side := range "LR" {
+		if strings.ContainsRune(borderStr, side) {
+			midStr += string(side)
+		}
+	}
+	firstStr = midStr
+	if strings.Contains(borderStr, "T") {
+		firstStr += "T"
+	}
+	for j, line := range lines {
+		bStr := midStr
+		if j == 0 {
+			bStr = firstStr
+		}
+		if j == len(lines)-1 && strings.Contains(borderStr, "B") {
+			bStr += "B"
+		}
+		// The cell frames and fills the line and breaks the page if needed,
+		// which may move it to another column
+		f.CellFormat(w, line.ht, "", bStr, 0, "", fill, 0, "")
+		if f.err != nil {
+			return
+		}
+		x = f.x - w
+		f.paraPutLine(x+f.cMargin, w-2*f.cMargin, runs, words, line, alignStr, false, last[j], 0)
+		f.x = x
+	}
+	if familyStr != "" {
+		f.SetFont(familyStr, styleStr, ptSize)
+	}
+	f.SetTextColor(r, g, b)
+	f.x = f.lMargin
+}
+
+// WriteSpans prints the styled runs of text in flowing mode, as if each were
+// printed with Write() in its font and color: text is wrapped at the right
+// margin, continues at the left margin, and the current position is left at
+// the end of the text. h is the line height. Runs are styled as described
+// for PourText(). Upon return the font and text color in effect beforehand
+// are restored.
+func (f *Fpdf) WriteSpans(h float64, runs []TextRunType) {
+	if f.err != nil {
+		return
+	}
+	familyStr, styleStr, ptSize := f.fontFamily, f.fontStyleStr(), f.fontSizePt
+	r, g, b := f.GetTextColor()
+	for _, run := range f.runDefaults(runs) {
+		f.SetFont(run.FontFamily, run.FontStyle, run.FontSize)
+		f.SetTextColor(run.Clr.R, run.Clr.G, run.Clr.B)
+		f.Write(h, run.Str)
+		if f.err != nil {
+			return
+		}
+	}
+	if familyStr != "" {
+		f.SetFont(familyStr, styleStr, ptSize)
+	}
+	f.SetTextColor(r, g, b)
+}
@@ -14,7 +14,41 @@ import (
 // This function is grapheme-cluster aware, meaning it will not split emoji
 // sequences (e.g., "👍🏽" or "👨‍👩‍👧‍👦") across lines. Text is split at grapheme
 // cluster boundaries, ensuring that user-perceived characters remain intact.
+//
+// Words are hyphenated, and text without spaces is divided into words, if
+// dictionaries have been registered for the language set with
+// SetLineBreakLanguage(). A line that ends within a hyphenated word ends with
+// a hyphen.
 func (f *Fpdf) SplitText(txt string, w float64) (lines []string) {
+	txt = f.debugCharsText(txt, true)
+	for _, br := range f.SplitTextBreaks(txt, w) {
+		str := txt[br.Start:br.End]
+		if br.Hyphen {
+			str += "-"
+		}
+		lines = append(lines, str)
+	}
+	return lines
+}
+
+// LineBreakType describes one line of wrapped text. Start and End are byte
+// offsets into the wrapped string, so the text of the line is txt[Start:End].
+// Width is the length of that text in the unit of measure specified in New().
+// Hyphen is true if the line ends within a word that has been hyphenated, in
+// which case a hyphen is to be printed after the text and is included in
+// Width.
+type LineBreakType struct {
+	Start, End int
+	Width      float64
+	Hyphen     bool
+}
+
+// SplitTextBreaks wraps txt exactly as SplitText does but, rather than copies
+// of the lines, returns the position and width of each one. This lets custom
+// renderers, for example ones that color parts of a line differently, reuse
+// the library's wrapping and still know where each line begins and ends in
+// the original text.
+func (f *Fpdf) SplitTextBreaks(txt string, w float64) (breaks []LineBreakType) {
 	cw := f.currentFont.Cw
 	wmax := int(math.Ceil((w - 2*f.cMargin) * 1000 / f.fontSize))
 
@@ -22,53 +56,100 @@ func (f *Fpdf) SplitText(txt string, w float64) (lines []string) {
 	clusters := graphemeClusters(txt)
 	nb := len(clusters)
 
+	// Byte offset of each cluster in txt
+	offsets := make([]int, nb+1)
+	for k, cluster := range clusters {
+		offsets[k+1] = offsets[k] + len(cluster)
+	}
+
 	// Remove trailing newline clusters
 	for nb > 0 && clusters[nb-1] == "\n" {
 		nb--
 	}
 	clusters = clusters[0:nb]
 
+	// Calculate cluster widths
+	widths := make([]int, nb)
+	for k, cluster := range clusters {
+		for _, r := range cluster {
+			widths[k] += cw[int(r)]
+		}
+	}
+
+	addLine := func(start, end int, hyphen bool) {
+		lw := 0
+		for k := start; k < end; k++ {
+			lw += widths[k]
+		}
+		if hyphen {
+			lw += cw['-']
+		}
+		breaks = append(breaks, LineBreakType{
+			Start:  offsets[start],
+			End:    offsets[end],
+			Width:  float64(lw) * f.fontSize / 1000,
+			Hyphen: hyphen,
+		})
+	}
+
+	// Breaks between words found by segmentation and within hyphenated words
+	// keep the cluster at which the next line begins
+	wordAt, hyphenAt := f.lineBreakPoints(clusters)
+
 	sep := -1
+	keep := false
 	i := 0
 	j := 0
 	l := 0
 
 	for i < nb {
 		cluster := clusters[i]
-
-		// Calculate cluster width
-		clusterWidth := 0
-		for _, r := range cluster {
-			clusterWidth += cw[int(r)]
-		}
-		l += clusterWidth
+		l += widths[i]
 
 		// Check if we can break at this position
 		// We can break at spaces or after Chinese characters
 		if len(cluster) == 1 {
 			r := []rune(cluster)[0]
 			if unicode.IsSpace(r) || isChinese(r) {
-				sep = i
+				sep, keep = i, false
 			}
 		}
+		if wordAt != nil && wordAt[i] && i > j {
+			sep, keep = i, true
+		}
 
 		// Check for explicit newline or width limit
 		if cluster == "\n" || l > wmax {
+			if cluster != "\n" && hyphenAt != nil {
+				// Hyphenate the word that overflows at the last point
+				// that leaves room for the hyphen
+				hyphenated := false
+				lw := l - widths[i]
+				for k := i; k > j && k > sep+1 && !hyphenated; k-- {
+					if hyphenAt[k] && lw+cw['-'] <= wmax {
+						addLine(j, k, true)
+						sep, keep = -1, false
+						i, j, l = k, k, 0
+						hyphenated = true
+					}
+					lw -= widths[k-1]
+				}
+				if hyphenated {
+					continue
+				}
+			}
 			if sep == -1 {
 				if i == j {
 					i++
 				}
 				sep = i
+			} else if keep {
+				i = sep
 			} else {
 				i = sep + 1
 			}
-			// Join clusters back into a string for this line
-			var lineBuilder []string
-			for k := j; k < sep; k++ {
-				lineBuilder = append(lineBuilder, clusters[k])
-			}
-			lines = append(lines, joinClusters(lineBuilder))
-			sep = -1
+			addLine(j, sep, false)
+			sep, keep = -1, false
 			j = i
 			l = 0
 		} else {
@@ -78,21 +159,8 @@ func (f *Fpdf) SplitText(txt string, w float64) (lines []string) {
 
 	// Add remaining text as final line
 	if i != j {
-		var lineBuilder []string
-		for k := j; k < i; k++ {
-			lineBuilder = append(lineBuilder, clusters[k])
-		}
-		lines = append(lines, joinClusters(lineBuilder))
+		addLine(j, i, false)
 	}
 
-	return lines
-}
-
-// joinClusters joins a slice of grapheme clusters into a single string
-func joinClusters(clusters []string) string {
-	result := ""
-	for _, cluster := range clusters {
-		result += cluster
-	}
-	return result
+	return breaks
 }
@@ -75,6 +75,7 @@ func (f *Fpdf) SetDrawSpotColor(nameStr string, tint byte) {
 	if ok {
 		f.color.draw.mode = colorModeSpot
 		f.color.draw.spotStr = nameStr
+		f.color.draw.none = false
 		f.color.draw.str = sprintf("/CS%d CS %.3f SCN", clr.id, float64(byteBound(tint))/100)
 		if f.page > 0 {
 			f.out(f.color.draw.str)
@@ -94,6 +95,7 @@ func (f *Fpdf) SetFillSpotColor(nameStr string, tint byte) {
 	if ok {
 		f.color.fill.mode = colorModeSpot
 		f.color.fill.spotStr = nameStr
+		f.color.fill.none = false
 		f.color.fill.str = sprintf("/CS%d cs %.3f scn", clr.id, float64(byteBound(tint))/100)
 		f.colorFlag = f.color.fill.str != f.color.text.str
 		if f.page > 0 {
@@ -116,6 +118,7 @@ func (f *Fpdf) SetTextSpotColor(nameStr string, tint byte) {
 		f.color.text.spotStr = nameStr
 		f.color.text.str = sprintf("/CS%d cs %.3f scn", clr.id, float64(byteBound(tint))/100)
 		f.colorFlag = f.color.text.str != f.color.text.str
+		f.textClrExplicit = true
 	}
 }
 
@@ -180,5 +183,6 @@ func (f *Fpdf) spotColorPutResourceDict() {
 	for _, clr := range f.spotColorMap {
 		f.outf("/CS%d %d 0 R", clr.id, clr.objID)
 	}
+	f.calColorPutResourceDict()
 	f.out(">>")
 }
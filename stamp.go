@@ -0,0 +1,182 @@
+package gofpdf
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// StampType describes a rubber stamp: a line of text in a frame, rotated and
+// printed with transparency so that the content beneath it remains legible.
+// Dimensions other than the font size are relative to the font size, so that
+// a stamp keeps its proportions at any size and in any unit of measure.
+type StampType struct {
+	// Text of the stamp
+	Text string
+	// Font family and style; an empty family selects bold Helvetica. The font
+	// must have been added to the document unless it is a core font.
+	FontFamily, FontStyle string
+	// Font size in points
+	FontSize float64
+	// Color of the text and frame
+	Clr RGBType
+	// Opacity from 0 (invisible) to 1 (opaque)
+	Alpha float64
+	// Counterclockwise rotation in degrees
+	Angle float64
+	// Width of the frame, zero for none, and the space between the text and
+	// the frame, relative to the font size
+	BorderWidth, Padding float64
+	// Radius of the frame corners relative to the font size
+	Radius float64
+	// Draw a second, thinner frame inside the first
+	Double bool
+}
+
+// Names of the predefined stamps
+const (
+	StampApproved     = "APPROVED"
+	StampConfidential = "CONFIDENTIAL"
+	StampCopy         = "COPY"
+	StampDraft        = "DRAFT"
+	StampPaid         = "PAID"
+	StampVoid         = "VOID"
+)
+
+// stamps holds the stamps registered with RegisterStamp()
+var stamps struct {
+	sync.Mutex
+	list map[string]StampType
+}
+
+func init() {
+	red, green, blue, gray := RGBType{200, 0, 0}, RGBType{0, 140, 60},
+		RGBType{0, 70, 170}, RGBType{120, 120, 120}
+	stamps.list = map[string]StampType{
+		StampApproved:     NewStamp(StampApproved, green),
+		StampConfidential: NewStamp(StampConfidential, red),
+		StampCopy:         NewStamp(StampCopy, gray),
+		StampDraft:        NewStamp(StampDraft, gray),
+		StampPaid:         NewStamp(StampPaid, blue),
+		StampVoid:         NewStamp(StampVoid, red),
+	}
+	st := stamps.list[StampDraft]
+	st.FontSize, st.Alpha, st.BorderWidth = 96, 0.15, 0
+	stamps.list[StampDraft] = st
+	st = stamps.list[StampPaid]
+	st.Double = true
+	stamps.list[StampPaid] = st
+}
+
+// NewStamp returns a variable of type StampType that is initialized to print
+// txtStr in 36 point bold Helvetica in the specified color, in a rounded frame
+// rotated by 15 degrees, at 60 percent opacity.
+func NewStamp(txtStr string, clr RGBType) (st StampType) {
+	st.Text = txtStr
+	st.FontStyle = "B"
+	st.FontSize = 36
+	st.Clr = clr
+	st.Alpha = 0.6
+	st.Angle = 15
+	st.BorderWidth = 0.08
+	st.Padding = 0.3
+	st.Radius = 0.2
+	return
+}
+
+// RegisterStamp makes st available under nameStr to Stamp() in all documents,
+// replacing any stamp registered under the same name, including the
+// predefined stamps StampApproved, StampConfidential, StampCopy, StampDraft,
+// StampPaid and StampVoid. Names are not case sensitive.
+func RegisterStamp(nameStr string, st StampType) {
+	stamps.Lock()
+	stamps.list[strings.ToUpper(nameStr)] = st
+	stamps.Unlock()
+}
+
+// GetStamp returns the stamp registered under nameStr, or false if there is
+// none.
+func GetStamp(nameStr string) (st StampType, ok bool) {
+	stamps.Lock()
+	st, ok = stamps.list[strings.ToUpper(nameStr)]
+	stamps.Unlock()
+	return
+}
+
+// GetStampNames returns the names of the registered stamps in alphabetical
+// order.
+func GetStampNames() (list []string) {
+	stamps.Lock()
+	for nameStr := range stamps.list {
+		list = append(list, nameStr)
+	}
+	stamps.Unlock()
+	sort.Strings(list)
+	return
+}
+
+// Stamp prints the stamp registered under nameStr, centered on (x, y). An
+// error is set if no such stamp has been registered.
+func (f *Fpdf) Stamp(nameStr string, x, y float64) {
+	if f.err != nil {
+		return
+	}
+	st, ok := GetStamp(nameStr)
+	if !ok {
+		f.err = fmt.Errorf("stamp %s has not been registered", nameStr)
+		return
+	}
+	f.StampOptions(st, x, y)
+}
+
+// StampOptions prints the stamp st centered on (x, y). The current position,
+// font, colors, line width and transparency are not changed.
+func (f *Fpdf) StampOptions(st StampType, x, y float64) {
+	if f.err != nil || st.Text == "" {
+		return
+	}
+	state := StateGet(f)
+	familyStr, styleStr, ptSize := f.fontFamily, f.fontStyleStr(), f.fontSizePt
+	curX, curY := f.GetXY()
+	if st.FontFamily == "" {
+		f.SetFont("Helvetica", "B", st.FontSize)
+	} else {
+		f.SetFont(st.FontFamily, st.FontStyle, st.FontSize)
+	}
+	size := f.fontSize
+	alpha := st.Alpha
+	if alpha <= 0 || alpha > 1 {
+		alpha = 1
+	}
+	pad := st.Padding * size
+	wd := f.GetStringWidth(st.Text) + 2*pad
+	ht := size + 2*pad
+	left, top := x-wd/2, y-ht/2
+
+	f.TransformBegin()
+	f.TransformRotate(st.Angle, x, y)
+	f.SetAlpha(alpha, "Normal")
+	f.SetDrawColor(st.Clr.R, st.Clr.G, st.Clr.B)
+	f.SetTextColor(st.Clr.R, st.Clr.G, st.Clr.B)
+	if st.BorderWidth > 0 {
+		lw := st.BorderWidth * size
+		f.SetLineWidth(lw)
+		f.RoundedRect(left, top, wd, ht, st.Radius*size, "1234", "D")
+		if st.Double {
+			gap := 1.5 * lw
+			f.SetLineWidth(lw / 2)
+			f.RoundedRect(left+gap, top+gap, wd-2*gap, ht-2*gap,
+				math.Max(st.Radius*size-gap, 0), "1234", "D")
+		}
+	}
+	f.Text(left+pad, y+0.35*size, st.Text)
+	f.TransformEnd()
+
+	if familyStr != "" {
+		f.SetFont(familyStr, styleStr, ptSize)
+	}
+	state.Put(f)
+	f.SetXY(curX, curY)
+}
@@ -0,0 +1,168 @@
+package gofpdf
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"io"
+	"strings"
+)
+
+// streamType holds the state of a document created with NewStreaming()
+type streamType struct {
+	w        io.Writer
+	version  string                  // PDF version written in the header; empty until output begins
+	written  int                     // number of bytes written to w
+	count    int                     // number of pages written to w
+	contents map[[sha1.Size]byte]int // content streams that later pages can share
+}
+
+// NewStreaming returns a pointer to a new Fpdf instance that writes the
+// document to w as it is generated. The arguments other than w are those of
+// New(). Each page is written to w as soon as it is finished, that is, when
+// the next page is added or the document is closed, and its content is then
+// released, so that the memory used by documents of many thousands of pages
+// does not grow with the number of pages. Resources such as fonts and images
+// are shared by the pages and are written when the document is closed; see
+// SetFileStreamThreshold() to keep large image files out of memory as well.
+//
+// The document must be completed by calling Close(), which writes the rest of
+// it to w; the Output methods cannot be used. Once a page has been written it
+// cannot be revisited with SetPage(), and methods that rearrange pages, such
+// as RenderSections() and GenerateVisualIndex(), are not available. A page
+// that contains the alias of AliasNbPages(), a forward reference to a
+// counter or a form field is kept in memory until the document is closed,
+// since its content or annotations cannot be completed until then; aliases
+// registered with RegisterAlias() apply only to the pages finished after they
+// are registered. If the PDF version needed by the features of the document
+// rises after output has begun, the higher version is declared in the document
+// catalog rather than in the header.
+//
+// Protected documents are streamed like others: each object is encrypted
+// with a key of its own as it is written, so encryption needs neither the
+// rest of the document nor additional memory. Protection must be set with
+// SetProtection() before the first page is finished.
+//
+// w is not closed. If an error occurs while the document is written, it is
+// reported by Error() and the output is incomplete.
+func NewStreaming(w io.Writer, orientationStr, unitStr, sizeStr, fontDirStr string) (f *Fpdf) {
+	f = New(orientationStr, unitStr, sizeStr, fontDirStr)
+	f.stream = &streamType{
+		w:        w,
+		contents: make(map[[sha1.Size]byte]int),
+	}
+	return
+}
+
+// streamStarted reports whether output of a document created with
+// NewStreaming() has begun; it sets an error naming the operation opStr if
+// it has
+func (f *Fpdf) streamStarted(opStr string) bool {
+	if f.stream == nil || f.stream.version == "" {
+		return false
+	}
+	f.SetErrorf("%s is not possible once the output of a streaming document has begun", opStr)
+	return true
+}
+
+// streaming reports whether f was created with NewStreaming(); it sets an
+// error naming the operation opStr if it was
+func (f *Fpdf) streaming(opStr string) bool {
+	if f.stream == nil {
+		return false
+	}
+	f.SetErrorf("%s is not possible in a streaming document", opStr)
+	return true
+}
+
+// streamPage writes page n, which has just been finished, to the output
+// stream, unless it has to be kept until the document is closed
+func (f *Fpdf) streamPage(n int) {
+	if f.err != nil {
+		return
+	}
+	if f.stream.version == "" {
+		f.putheader()
+		f.stream.version = f.pdfVersion
+	}
+	f.replacePageAliases(n)
+	if f.streamKeepPage(n) {
+		return
+	}
+	for _, an := range f.pageAttachments[n] {
+		f.embed(an.Attachment)
+	}
+	f.putpage(n, nil, f.stream.contents)
+	f.stream.count++
+	f.pages[n] = nil
+	f.pageLinks[n] = nil
+	f.pageAttachments[n] = nil
+	delete(f.pageBoxes, n)
+	f.streamFlush()
+}
+
+// streamKeepPage returns true if page n cannot be written before the document
+// is closed
+func (f *Fpdf) streamKeepPage(n int) bool {
+	for _, fld := range f.formFields {
+		for _, wdg := range fld.widgets {
+			if wdg.page == n {
+				return true
+			}
+		}
+	}
+	aliasList := make([]string, 0, len(f.counterFwdRefs)+1)
+	if f.aliasNbPagesStr != "" {
+		aliasList = append(aliasList, f.aliasNbPagesStr)
+	}
+	for _, alias := range f.counterFwdRefs {
+		aliasList = append(aliasList, alias)
+	}
+	b := f.pages[n].Bytes()
+	for _, alias := range aliasList {
+		if bytes.Contains(b, []byte(alias)) || bytes.Contains(b, []byte(utf8toutf16(alias, false))) {
+			return true
+		}
+	}
+	return false
+}
+
+// streamFlush writes the document buffer to the output stream
+func (f *Fpdf) streamFlush() {
+	size := f.buffer.Len() + int(f.streamedLen)
+	if err := f.writeOutput(f.stream.w); err != nil {
+		f.err = err
+		return
+	}
+	f.stream.written += size
+	f.streamedLen = 0
+}
+
+// streamPutCatalog writes the catalog entries of a streaming document: the
+// PDF version, if it has risen since the header was written, and the
+// destinations of internal links, which are referred to by name because the
+// pages they point to may not have been written when the links were
+func (f *Fpdf) streamPutCatalog() {
+	if f.stream == nil {
+		return
+	}
+	// Blend modes raise the version when the header is written in documents
+	// that are not streamed
+	if len(f.blendMap) > 0 && f.pdfVersion < "1.4" {
+		f.pdfVersion = "1.4"
+	}
+	if f.compact && f.pdfVersion < "1.5" {
+		f.pdfVersion = "1.5"
+	}
+	if f.pdfVersion > f.stream.version {
+		f.outf("/Version /%s", f.pdfVersion)
+	}
+	var dests []string
+	for j := 1; j < len(f.links); j++ {
+		if f.links[j].page > 0 {
+			dests = append(dests, sprintf("/L%d %s", j, f.linkDest(j)))
+		}
+	}
+	if len(dests) > 0 {
+		f.outf("/Dests <<%s>>", strings.Join(dests, " "))
+	}
+}
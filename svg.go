@@ -0,0 +1,1308 @@
+package gofpdf
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SVGType is a scalable vector graphics (SVG) image parsed by SVGParse() for
+// rendering with SVGWrite()
+type SVGType struct {
+	// Width and height of the image in SVG user units, which are pixels of
+	// 1/96 inch
+	Wd, Ht float64
+	root   *svgNodeType
+	ids    map[string]*svgNodeType
+}
+
+// svgNodeType is an element of an SVG image with its attributes, which
+// include the properties set with style sheets
+type svgNodeType struct {
+	name     string
+	attrs    map[string]string
+	text     string
+	children []*svgNodeType
+}
+
+// SVGParse parses a scalable vector graphics (SVG) buffer for rendering with
+// SVGWrite(). Unlike SVGBasicParse(), which reads path data only, it reads
+// the elements path, rect, circle, ellipse, line, polyline and polygon, groups
+// (g) with transforms, use elements, linear and radial gradients, and the
+// presentation attributes and style properties that paint them, such as
+// fill, stroke, stroke-width, opacity and stroke-dasharray, including those
+// set with simple selectors of element names, classes and IDs in style
+// elements.
+//
+// Text, images, clipping paths, masks, patterns and filters are not rendered.
+// An error is returned if buf is not an SVG image or its size cannot be
+// determined from its width, height and viewBox attributes.
+func SVGParse(buf []byte) (img SVGType, err error) {
+	dec := xml.NewDecoder(bytes.NewReader(buf))
+	// Tolerate the entities that some design tools declare in a DOCTYPE
+	dec.Strict = false
+	img.ids = make(map[string]*svgNodeType)
+	var stack []*svgNodeType
+	var tok xml.Token
+	for {
+		tok, err = dec.Token()
+		if err == io.EOF {
+			err = nil
+			break
+		}
+		if err != nil {
+			return
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			node := &svgNodeType{name: t.Name.Local, attrs: make(map[string]string)}
+			for _, attr := range t.Attr {
+				if attr.Name.Space != "xmlns" && attr.Name.Local != "xmlns" {
+					node.attrs[attr.Name.Local] = strings.TrimSpace(attr.Value)
+				}
+			}
+			if id := node.attrs["id"]; id != "" {
+				img.ids[id] = node
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			} else if img.root == nil {
+				img.root = node
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			if len(stack) > 0 && stack[len(stack)-1].name == "style" {
+				stack[len(stack)-1].text += string(t)
+			}
+		}
+	}
+	if img.root == nil || img.root.name != "svg" {
+		err = fmt.Errorf("SVG image has no svg element")
+		return
+	}
+	svgApplyStyles(img.root, svgStyleRules(img.root))
+	img.Wd = svgLength(img.root.attrs["width"], 0)
+	img.Ht = svgLength(img.root.attrs["height"], 0)
+	if vb := svgNumbers(img.root.attrs["viewBox"]); len(vb) == 4 && vb[2] > 0 && vb[3] > 0 {
+		switch {
+		case img.Wd <= 0 && img.Ht <= 0:
+			img.Wd, img.Ht = vb[2], vb[3]
+		case img.Wd <= 0:
+			img.Wd = img.Ht * vb[2] / vb[3]
+		case img.Ht <= 0:
+			img.Ht = img.Wd * vb[3] / vb[2]
+		}
+	}
+	if img.Wd <= 0 || img.Ht <= 0 {
+		err = fmt.Errorf("unacceptable values for SVG extent: %.2f x %.2f", img.Wd, img.Ht)
+	}
+	return
+}
+
+// SVGFileParse parses a scalable vector graphics (SVG) file for rendering
+// with SVGWrite(). See SVGParse() for the parts of SVG that are supported.
+func SVGFileParse(svgFileStr string) (img SVGType, err error) {
+	var buf []byte
+	buf, err = ioutil.ReadFile(svgFileStr)
+	if err == nil {
+		img, err = SVGParse(buf)
+	}
+	return
+}
+
+// SVGWrite renders the SVG image img with its upper left corner at the
+// current position (as set with a call to SetXY()). The scale value converts
+// SVG user units to the unit of measure specified in New(); for example, a
+// scale of 25.4 / 96 renders an image at its intended size in a document
+// measured in millimeters, and a scale of wd / img.Wd renders it with a width
+// of wd. Shapes are painted with the colors, opacities, line widths and dash
+// patterns of the image rather than those of the document, which are left
+// unchanged.
+func (f *Fpdf) SVGWrite(img *SVGType, scale float64) {
+	if f.err != nil || img == nil || img.root == nil {
+		return
+	}
+	if f.page <= 0 {
+		f.SetErrorf("cannot write an SVG image without first adding a page")
+		return
+	}
+	r := svgRendererType{f: f, img: img}
+	if vb := svgNumbers(img.root.attrs["viewBox"]); len(vb) == 4 && vb[2] > 0 && vb[3] > 0 {
+		r.vw, r.vh = vb[2], vb[3]
+	} else {
+		r.vw, r.vh = img.Wd, img.Ht
+	}
+	st := f.drawState()
+	f.out("q")
+	m := svgMatrixType{scale, 0, 0, scale, f.x, f.y}
+	m = m.mul(svgViewMatrix(img.root.attrs, img.Wd, img.Ht))
+	style := r.style(img.root, svgStyleType{
+		fill:          svgPaintType{kind: svgPaintColor},
+		fillOpacity:   1,
+		strokeOpacity: 1,
+		opacity:       1,
+		strokeWidth:   1,
+	})
+	for _, child := range img.root.children {
+		r.node(child, m, style, 1)
+	}
+	f.out("Q")
+	f.setDrawState(st)
+}
+
+// svgMatrixType is an affine transformation [a b c d e f] that maps (x, y)
+// to (a*x + c*y + e, b*x + d*y + f)
+type svgMatrixType [6]float64
+
+// mul returns the transformation that applies n and then m
+func (m svgMatrixType) mul(n svgMatrixType) svgMatrixType {
+	return svgMatrixType{
+		m[0]*n[0] + m[2]*n[1], m[1]*n[0] + m[3]*n[1],
+		m[0]*n[2] + m[2]*n[3], m[1]*n[2] + m[3]*n[3],
+		m[0]*n[4] + m[2]*n[5] + m[4], m[1]*n[4] + m[3]*n[5] + m[5],
+	}
+}
+
+// apply returns the point pt transformed by m
+func (m svgMatrixType) apply(pt PointType) PointType {
+	return PointType{m[0]*pt.X + m[2]*pt.Y + m[4], m[1]*pt.X + m[3]*pt.Y + m[5]}
+}
+
+// scale returns the factor by which m scales lengths on average
+func (m svgMatrixType) scale() float64 {
+	return math.Sqrt(math.Abs(m[0]*m[3] - m[1]*m[2]))
+}
+
+var svgIdentity = svgMatrixType{1, 0, 0, 1, 0, 0}
+
+var svgTransformRe = regexp.MustCompile(`([a-zA-Z]+)\s*\(([^)]*)\)`)
+
+// svgTransform returns the transformation of the transform attribute tfStr
+func svgTransform(tfStr string) svgMatrixType {
+	m := svgIdentity
+	for _, sub := range svgTransformRe.FindAllStringSubmatch(tfStr, -1) {
+		args := svgNumbers(sub[2])
+		arg := func(j int, def float64) float64 {
+			if j < len(args) {
+				return args[j]
+			}
+			return def
+		}
+		var n svgMatrixType
+		switch sub[1] {
+		case "matrix":
+			if len(args) != 6 {
+				continue
+			}
+			copy(n[:], args)
+		case "translate":
+			n = svgMatrixType{1, 0, 0, 1, arg(0, 0), arg(1, 0)}
+		case "scale":
+			sx := arg(0, 1)
+			n = svgMatrixType{sx, 0, 0, arg(1, sx), 0, 0}
+		case "rotate":
+			a := arg(0, 0) * math.Pi / 180
+			cx, cy := arg(1, 0), arg(2, 0)
+			sin, cos := math.Sin(a), math.Cos(a)
+			n = svgMatrixType{cos, sin, -sin, cos, cx - cos*cx + sin*cy, cy - sin*cx - cos*cy}
+		case "skewX":
+			n = svgMatrixType{1, 0, math.Tan(arg(0, 0) * math.Pi / 180), 1, 0, 0}
+		case "skewY":
+			n = svgMatrixType{1, math.Tan(arg(0, 0) * math.Pi / 180), 0, 1, 0, 0}
+		default:
+			continue
+		}
+		m = m.mul(n)
+	}
+	return m
+}
+
+// svgViewMatrix returns the transformation that fits the viewBox of an svg
+// element with the attributes attrs into a viewport of width w and height h,
+// as specified by its preserveAspectRatio attribute
+func svgViewMatrix(attrs map[string]string, w, h float64) svgMatrixType {
+	vb := svgNumbers(attrs["viewBox"])
+	if len(vb) != 4 || vb[2] <= 0 || vb[3] <= 0 {
+		return svgIdentity
+	}
+	sx, sy := w/vb[2], h/vb[3]
+	fields := strings.Fields(attrs["preserveAspectRatio"])
+	alignStr := "xMidYMid"
+	if len(fields) > 0 {
+		alignStr = fields[0]
+	}
+	if alignStr == "none" {
+		return svgMatrixType{sx, 0, 0, sy, -vb[0] * sx, -vb[1] * sy}
+	}
+	s := math.Min(sx, sy)
+	if len(fields) > 1 && fields[1] == "slice" {
+		s = math.Max(sx, sy)
+	}
+	var tx, ty float64
+	switch {
+	case strings.Contains(alignStr, "xMid"):
+		tx = (w - vb[2]*s) / 2
+	case strings.Contains(alignStr, "xMax"):
+		tx = w - vb[2]*s
+	}
+	switch {
+	case strings.Contains(alignStr, "YMid"):
+		ty = (h - vb[3]*s) / 2
+	case strings.Contains(alignStr, "YMax"):
+		ty = h - vb[3]*s
+	}
+	return svgMatrixType{s, 0, 0, s, tx - vb[0]*s, ty - vb[1]*s}
+}
+
+// Kinds of paint of svgPaintType
+const (
+	svgPaintNone = iota
+	svgPaintColor
+	svgPaintGradient
+)
+
+// svgPaintType is the value of the fill or stroke property
+type svgPaintType struct {
+	kind int
+	clr  RGBType
+	ref  *svgNodeType // gradient element
+}
+
+// svgStyleType holds the properties that paint a shape, as inherited from
+// the elements that contain it
+type svgStyleType struct {
+	fill, stroke               svgPaintType
+	clr                        RGBType // color property, for currentColor
+	fillOpacity, strokeOpacity float64
+	opacity                    float64 // product of the opacities of the element and its ancestors
+	strokeWidth                float64
+	evenOdd                    bool
+	capStr, joinStr            string
+	dashArray                  []float64
+	dashOffset                 float64
+	hidden                     bool
+}
+
+// svgRendererType renders the elements of an SVG image
+type svgRendererType struct {
+	f      *Fpdf
+	img    *SVGType
+	vw, vh float64 // size of the viewport, for percentages
+}
+
+// style returns the style of node, which inherits the style st of its parent
+func (r *svgRendererType) style(node *svgNodeType, st svgStyleType) svgStyleType {
+	attr := func(key string) (string, bool) {
+		val, ok := node.attrs[key]
+		return val, ok && val != "" && val != "inherit"
+	}
+	if val, ok := attr("color"); ok {
+		if clr, ok := svgColor(val, st.clr); ok {
+			st.clr = clr
+		}
+	}
+	if val, ok := attr("fill"); ok {
+		st.fill = r.paint(val, st)
+	}
+	if val, ok := attr("stroke"); ok {
+		st.stroke = r.paint(val, st)
+	}
+	if val, ok := attr("fill-opacity"); ok {
+		st.fillOpacity = svgOpacity(val)
+	}
+	if val, ok := attr("stroke-opacity"); ok {
+		st.strokeOpacity = svgOpacity(val)
+	}
+	if val, ok := attr("opacity"); ok {
+		st.opacity *= svgOpacity(val)
+	}
+	if val, ok := attr("stroke-width"); ok {
+		st.strokeWidth = svgLength(val, math.Hypot(r.vw, r.vh)/math.Sqrt2)
+	}
+	if val, ok := attr("fill-rule"); ok {
+		st.evenOdd = val == "evenodd"
+	}
+	if val, ok := attr("stroke-linecap"); ok {
+		st.capStr = val
+	}
+	if val, ok := attr("stroke-linejoin"); ok {
+		st.joinStr = val
+	}
+	if val, ok := attr("stroke-dasharray"); ok {
+		st.dashArray = nil
+		var sum float64
+		for _, v := range svgNumbers(val) {
+			st.dashArray = append(st.dashArray, math.Abs(v))
+			sum += math.Abs(v)
+		}
+		if sum == 0 {
+			st.dashArray = nil
+		} else if len(st.dashArray)%2 == 1 {
+			st.dashArray = append(st.dashArray, st.dashArray...)
+		}
+	}
+	if val, ok := attr("stroke-dashoffset"); ok {
+		st.dashOffset = svgLength(val, 0)
+	}
+	if val, ok := attr("visibility"); ok {
+		st.hidden = val == "hidden" || val == "collapse"
+	}
+	return st
+}
+
+// paint returns the paint specified by the fill or stroke property valStr
+func (r *svgRendererType) paint(valStr string, st svgStyleType) (p svgPaintType) {
+	if strings.HasPrefix(valStr, "url(") {
+		end := strings.Index(valStr, ")")
+		if end < 0 {
+			return
+		}
+		id := strings.Trim(strings.TrimSpace(valStr[4:end]), `'"`)
+		if node := r.img.ids[strings.TrimPrefix(id, "#")]; node != nil &&
+			(node.name == "linearGradient" || node.name == "radialGradient") {
+			p.kind, p.ref = svgPaintGradient, node
+			return
+		}
+		// Fall back to the color that follows the reference, if any
+		valStr = strings.TrimSpace(valStr[end+1:])
+	}
+	if clr, ok := svgColor(valStr, st.clr); ok {
+		p.kind, p.clr = svgPaintColor, clr
+	}
+	return
+}
+
+// node renders the element node and its children
+func (r *svgRendererType) node(node *svgNodeType, m svgMatrixType, st svgStyleType, depth int) {
+	if depth > 32 || node.attrs["display"] == "none" {
+		return
+	}
+	st = r.style(node, st)
+	if tfStr, ok := node.attrs["transform"]; ok {
+		m = m.mul(svgTransform(tfStr))
+	}
+	attr := func(key string, ref float64) float64 {
+		return svgLength(node.attrs[key], ref)
+	}
+	switch node.name {
+	case "svg":
+		w, h := r.vw, r.vh
+		if _, ok := node.attrs["width"]; ok {
+			w = attr("width", r.vw)
+		}
+		if _, ok := node.attrs["height"]; ok {
+			h = attr("height", r.vh)
+		}
+		m = m.mul(svgMatrixType{1, 0, 0, 1, attr("x", r.vw), attr("y", r.vh)})
+		m = m.mul(svgViewMatrix(node.attrs, w, h))
+		fallthrough
+	case "g", "a", "switch":
+		for _, child := range node.children {
+			r.node(child, m, st, depth+1)
+		}
+	case "use":
+		href := node.attrs["href"]
+		ref := r.img.ids[strings.TrimPrefix(href, "#")]
+		if ref == nil || !strings.HasPrefix(href, "#") {
+			return
+		}
+		m = m.mul(svgMatrixType{1, 0, 0, 1, attr("x", r.vw), attr("y", r.vh)})
+		if ref.name == "symbol" {
+			st = r.style(ref, st)
+			if _, ok := ref.attrs["viewBox"]; ok {
+				w, h := r.vw, r.vh
+				if _, ok := node.attrs["width"]; ok {
+					w = attr("width", r.vw)
+				}
+				if _, ok := node.attrs["height"]; ok {
+					h = attr("height", r.vh)
+				}
+				m = m.mul(svgViewMatrix(ref.attrs, w, h))
+			}
+			for _, child := range ref.children {
+				r.node(child, m, st, depth+1)
+			}
+		} else {
+			r.node(ref, m, st, depth+1)
+		}
+	case "path", "rect", "circle", "ellipse", "line", "polyline", "polygon":
+		if !st.hidden {
+			r.shape(r.geometry(node), node.name != "line", m, st)
+		}
+	}
+}
+
+// svgSegType is a segment of a path: 'M' moves to pts[0], 'L' draws a line
+// to pts[0], 'C' draws a cubic Bézier curve with the control points pts[0]
+// and pts[1] to pts[2], and 'Z' closes the subpath
+type svgSegType struct {
+	cmd byte
+	pts [3]PointType
+}
+
+// svgPathType builds a path of segments
+type svgPathType struct {
+	segs       []svgSegType
+	start, cur PointType
+	ctrl       PointType // last control point, for smooth curves
+	ctrlCmd    byte      // command of the last segment
+}
+
+func (p *svgPathType) moveTo(pt PointType) {
+	p.segs = append(p.segs, svgSegType{cmd: 'M', pts: [3]PointType{pt}})
+	p.start, p.cur = pt, pt
+}
+
+func (p *svgPathType) lineTo(pt PointType) {
+	p.segs = append(p.segs, svgSegType{cmd: 'L', pts: [3]PointType{pt}})
+	p.cur = pt
+}
+
+func (p *svgPathType) cubicTo(c0, c1, pt PointType) {
+	p.segs = append(p.segs, svgSegType{cmd: 'C', pts: [3]PointType{c0, c1, pt}})
+	p.cur = pt
+}
+
+func (p *svgPathType) close() {
+	p.segs = append(p.segs, svgSegType{cmd: 'Z'})
+	p.cur = p.start
+}
+
+// quadTo draws a quadratic Bézier curve as the equivalent cubic curve
+func (p *svgPathType) quadTo(c, pt PointType) {
+	p0 := p.cur
+	p.cubicTo(PointType{p0.X + 2*(c.X-p0.X)/3, p0.Y + 2*(c.Y-p0.Y)/3},
+		PointType{pt.X + 2*(c.X-pt.X)/3, pt.Y + 2*(c.Y-pt.Y)/3}, pt)
+}
+
+// arcTo draws an elliptical arc to pt as specified by the arc command of SVG
+// paths, converted to cubic Bézier curves of at most 90 degrees each
+func (p *svgPathType) arcTo(rx, ry, degRotate float64, large, sweep bool, pt PointType) {
+	p0 := p.cur
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if rx == 0 || ry == 0 {
+		p.lineTo(pt)
+		return
+	}
+	if p0 == pt {
+		return
+	}
+	phi := degRotate * math.Pi / 180
+	sin, cos := math.Sin(phi), math.Cos(phi)
+	// Center parameterization; see the implementation notes of SVG
+	dx, dy := (p0.X-pt.X)/2, (p0.Y-pt.Y)/2
+	x1 := cos*dx + sin*dy
+	y1 := -sin*dx + cos*dy
+	if lambda := x1*x1/(rx*rx) + y1*y1/(ry*ry); lambda > 1 {
+		rx *= math.Sqrt(lambda)
+		ry *= math.Sqrt(lambda)
+	}
+	num := rx*rx*ry*ry - rx*rx*y1*y1 - ry*ry*x1*x1
+	den := rx*rx*y1*y1 + ry*ry*x1*x1
+	coef := math.Sqrt(math.Max(0, num/den))
+	if large == sweep {
+		coef = -coef
+	}
+	cx1 := coef * rx * y1 / ry
+	cy1 := -coef * ry * x1 / rx
+	cx := cos*cx1 - sin*cy1 + (p0.X+pt.X)/2
+	cy := sin*cx1 + cos*cy1 + (p0.Y+pt.Y)/2
+	angle := func(ux, uy, vx, vy float64) float64 {
+		return math.Atan2(ux*vy-uy*vx, ux*vx+uy*vy)
+	}
+	theta := angle(1, 0, (x1-cx1)/rx, (y1-cy1)/ry)
+	delta := angle((x1-cx1)/rx, (y1-cy1)/ry, (-x1-cx1)/rx, (-y1-cy1)/ry)
+	if !sweep && delta > 0 {
+		delta -= 2 * math.Pi
+	} else if sweep && delta < 0 {
+		delta += 2 * math.Pi
+	}
+	n := int(math.Ceil(math.Abs(delta)/(math.Pi/2) - 1e-9))
+	if n < 1 {
+		n = 1
+	}
+	step := delta / float64(n)
+	kappa := 4.0 / 3.0 * math.Tan(step/4)
+	point := func(a float64) PointType {
+		x, y := rx*math.Cos(a), ry*math.Sin(a)
+		return PointType{cos*x - sin*y + cx, sin*x + cos*y + cy}
+	}
+	deriv := func(a float64) PointType {
+		x, y := -rx*math.Sin(a), ry*math.Cos(a)
+		return PointType{cos*x - sin*y, sin*x + cos*y}
+	}
+	for j := 0; j < n; j++ {
+		a0, a1 := theta+float64(j)*step, theta+float64(j+1)*step
+		s, e := point(a0), point(a1)
+		d0, d1 := deriv(a0), deriv(a1)
+		if j == n-1 {
+			e = pt
+		}
+		p.cubicTo(PointType{s.X + kappa*d0.X, s.Y + kappa*d0.Y},
+			PointType{e.X - kappa*d1.X, e.Y - kappa*d1.Y}, e)
+	}
+}
+
+// ellipse adds the closed ellipse centered on (cx, cy) with the radii rx and
+// ry
+func (p *svgPathType) ellipse(cx, cy, rx, ry float64) {
+	p.moveTo(PointType{cx + rx, cy})
+	p.arcTo(rx, ry, 0, false, true, PointType{cx, cy + ry})
+	p.arcTo(rx, ry, 0, false, true, PointType{cx - rx, cy})
+	p.arcTo(rx, ry, 0, false, true, PointType{cx, cy - ry})
+	p.arcTo(rx, ry, 0, false, true, PointType{cx + rx, cy})
+	p.close()
+}
+
+// geometry returns the path of the shape element node
+func (r *svgRendererType) geometry(node *svgNodeType) []svgSegType {
+	var p svgPathType
+	diag := math.Hypot(r.vw, r.vh) / math.Sqrt2
+	attr := func(key string, ref float64) float64 {
+		return svgLength(node.attrs[key], ref)
+	}
+	switch node.name {
+	case "path":
+		svgPathParse(&p, node.attrs["d"])
+	case "rect":
+		x, y, w, h := attr("x", r.vw), attr("y", r.vh), attr("width", r.vw), attr("height", r.vh)
+		if w <= 0 || h <= 0 {
+			break
+		}
+		rxStr, rxOk := node.attrs["rx"]
+		ryStr, ryOk := node.attrs["ry"]
+		rx, ry := svgLength(rxStr, r.vw), svgLength(ryStr, r.vh)
+		if !rxOk || rxStr == "auto" {
+			rx = ry
+		}
+		if !ryOk || ryStr == "auto" {
+			ry = rx
+		}
+		rx, ry = math.Min(math.Abs(rx), w/2), math.Min(math.Abs(ry), h/2)
+		if rx == 0 || ry == 0 {
+			p.moveTo(PointType{x, y})
+			p.lineTo(PointType{x + w, y})
+			p.lineTo(PointType{x + w, y + h})
+			p.lineTo(PointType{x, y + h})
+			p.close()
+			break
+		}
+		p.moveTo(PointType{x + rx, y})
+		p.lineTo(PointType{x + w - rx, y})
+		p.arcTo(rx, ry, 0, false, true, PointType{x + w, y + ry})
+		p.lineTo(PointType{x + w, y + h - ry})
+		p.arcTo(rx, ry, 0, false, true, PointType{x + w - rx, y + h})
+		p.lineTo(PointType{x + rx, y + h})
+		p.arcTo(rx, ry, 0, false, true, PointType{x, y + h - ry})
+		p.lineTo(PointType{x, y + ry})
+		p.arcTo(rx, ry, 0, false, true, PointType{x + rx, y})
+		p.close()
+	case "circle":
+		if rad := attr("r", diag); rad > 0 {
+			p.ellipse(attr("cx", r.vw), attr("cy", r.vh), rad, rad)
+		}
+	case "ellipse":
+		rx, ry := attr("rx", r.vw), attr("ry", r.vh)
+		if rx > 0 && ry > 0 {
+			p.ellipse(attr("cx", r.vw), attr("cy", r.vh), rx, ry)
+		}
+	case "line":
+		p.moveTo(PointType{attr("x1", r.vw), attr("y1", r.vh)})
+		p.lineTo(PointType{attr("x2", r.vw), attr("y2", r.vh)})
+	case "polyline", "polygon":
+		vals := svgNumbers(node.attrs["points"])
+		for j := 0; j+1 < len(vals); j += 2 {
+			if j == 0 {
+				p.moveTo(PointType{vals[j], vals[j+1]})
+			} else {
+				p.lineTo(PointType{vals[j], vals[j+1]})
+			}
+		}
+		if node.name == "polygon" && len(p.segs) > 0 {
+			p.close()
+		}
+	}
+	return p.segs
+}
+
+// shape paints the path segs with the style st; the path is transformed by m
+// to the unit of measure of the document
+func (r *svgRendererType) shape(segs []svgSegType, fillable bool, m svgMatrixType, st svgStyleType) {
+	f := r.f
+	if len(segs) == 0 {
+		return
+	}
+	fill := fillable && st.fill.kind != svgPaintNone && st.fillOpacity*st.opacity > 0
+	stroke := st.stroke.kind != svgPaintNone && st.strokeWidth > 0 && st.strokeOpacity*st.opacity > 0
+	if fill && st.fill.kind == svgPaintGradient {
+		if clr, ok := r.gradientFill(segs, st, m); ok {
+			// A gradient that has a single color is painted as a color
+			st.fill = svgPaintType{kind: svgPaintColor, clr: clr}
+		} else {
+			fill = false
+		}
+	}
+	if !fill && !stroke {
+		return
+	}
+	var styleStr string
+	if fill {
+		f.setSVGAlpha(f.strokeAlpha, st.fillOpacity*st.opacity)
+		f.SetFillColor(st.fill.clr.R, st.fill.clr.G, st.fill.clr.B)
+		styleStr = "F"
+	}
+	if stroke {
+		clr := st.stroke.clr
+		if st.stroke.kind == svgPaintGradient {
+			// Strokes are painted with the first color of a gradient
+			if stops, _ := r.gradientStops(st.stroke.ref); len(stops) > 0 {
+				clr = stops[0].clr
+			}
+		}
+		f.setSVGAlpha(st.strokeOpacity*st.opacity, f.fillAlpha)
+		f.SetDrawColor(clr.R, clr.G, clr.B)
+		scale := m.scale()
+		f.SetLineWidth(st.strokeWidth * scale)
+		f.SetLineCapStyle(st.capStr)
+		f.SetLineJoinStyle(st.joinStr)
+		dashArray := make([]float64, len(st.dashArray))
+		for j, v := range st.dashArray {
+			dashArray[j] = v * scale
+		}
+		if len(dashArray) > 0 || len(f.dashArray) > 0 {
+			f.SetDashPattern(dashArray, st.dashOffset*scale)
+		}
+		styleStr += "D"
+	}
+	if st.evenOdd && fill {
+		styleStr += "*"
+	}
+	r.path(segs, m)
+	f.DrawPath(styleStr)
+}
+
+// setSVGAlpha sets the stroke and fill opacities if they have changed
+func (f *Fpdf) setSVGAlpha(strokeAlpha, fillAlpha float64) {
+	if strokeAlpha != f.strokeAlpha || fillAlpha != f.fillAlpha {
+		f.strokeAlpha, f.fillAlpha = strokeAlpha, fillAlpha
+		f.putAlpha()
+	}
+}
+
+// path adds the segments of a path, transformed by m, to the current path of
+// the page
+func (r *svgRendererType) path(segs []svgSegType, m svgMatrixType) {
+	f := r.f
+	for _, seg := range segs {
+		switch seg.cmd {
+		case 'M':
+			pt := m.apply(seg.pts[0])
+			f.MoveTo(pt.X, pt.Y)
+		case 'L':
+			pt := m.apply(seg.pts[0])
+			f.LineTo(pt.X, pt.Y)
+		case 'C':
+			c0, c1, pt := m.apply(seg.pts[0]), m.apply(seg.pts[1]), m.apply(seg.pts[2])
+			f.CurveBezierCubicTo(c0.X, c0.Y, c1.X, c1.Y, pt.X, pt.Y)
+		case 'Z':
+			f.ClosePath()
+		}
+	}
+}
+
+// svgStopType is a color stop of a gradient
+type svgStopType struct {
+	offset float64
+	clr    RGBType
+}
+
+// gradientChain returns the gradient element node followed by the gradients
+// it refers to with its href attribute, from which it inherits attributes
+// and stops
+func (r *svgRendererType) gradientChain(node *svgNodeType) (chain []*svgNodeType) {
+	for node != nil && len(chain) < 8 {
+		chain = append(chain, node)
+		href := node.attrs["href"]
+		if !strings.HasPrefix(href, "#") {
+			break
+		}
+		node = r.img.ids[href[1:]]
+	}
+	return
+}
+
+// gradientStops returns the color stops of the gradient element node and the
+// attributes it has or inherits
+func (r *svgRendererType) gradientStops(node *svgNodeType) (stops []svgStopType, attrs map[string]string) {
+	attrs = make(map[string]string)
+	for _, g := range r.gradientChain(node) {
+		for key, val := range g.attrs {
+			if _, ok := attrs[key]; !ok {
+				attrs[key] = val
+			}
+		}
+		if len(stops) > 0 {
+			continue
+		}
+		for _, child := range g.children {
+			if child.name != "stop" {
+				continue
+			}
+			var stop svgStopType
+			offStr := child.attrs["offset"]
+			if strings.HasSuffix(offStr, "%") {
+				stop.offset = svgNumber(strings.TrimSuffix(offStr, "%")) / 100
+			} else {
+				stop.offset = svgNumber(offStr)
+			}
+			stop.offset = math.Max(0, math.Min(1, stop.offset))
+			if len(stops) > 0 {
+				stop.offset = math.Max(stop.offset, stops[len(stops)-1].offset)
+			}
+			stop.clr, _ = svgColor(child.attrs["stop-color"], RGBType{})
+			stops = append(stops, stop)
+		}
+	}
+	return
+}
+
+// gradientFill fills the path segs with the gradient of the style st. If the
+// gradient has a single color, it returns the color for the path to be
+// filled with instead; it returns false if the path is not to be filled.
+func (r *svgRendererType) gradientFill(segs []svgSegType, st svgStyleType, m svgMatrixType) (clr RGBType, single bool) {
+	f := r.f
+	stops, attrs := r.gradientStops(st.fill.ref)
+	if len(stops) == 0 {
+		return
+	}
+	if stops[0].offset == stops[len(stops)-1].offset {
+		return stops[len(stops)-1].clr, true
+	}
+	userSpace := attrs["gradientUnits"] == "userSpaceOnUse"
+	coord := func(key, defStr string, ref float64) float64 {
+		valStr, ok := attrs[key]
+		if !ok {
+			valStr = defStr
+		}
+		if !userSpace {
+			// Fractions of the bounding box
+			if strings.HasSuffix(valStr, "%") {
+				return svgNumber(strings.TrimSuffix(valStr, "%")) / 100
+			}
+			return svgNumber(valStr)
+		}
+		return svgLength(valStr, ref)
+	}
+	gm := svgIdentity
+	if !userSpace {
+		x0, y0, x1, y1 := svgBounds(segs)
+		if x1-x0 <= 0 || y1-y0 <= 0 {
+			return
+		}
+		gm = svgMatrixType{x1 - x0, 0, 0, y1 - y0, x0, y0}
+	}
+	gm = gm.mul(svgTransform(attrs["gradientTransform"]))
+	pm := svgMatrixType{f.k, 0, 0, -f.k, 0, f.k * f.h}.mul(m).mul(gm)
+	list := make([]gradientStopType, len(stops))
+	for j, stop := range stops {
+		list[j] = gradientStopType{stop.offset, rgbColorValue(stop.clr.R, stop.clr.G, stop.clr.B, "", "")}
+	}
+	f.setSVGAlpha(f.strokeAlpha, st.fillOpacity*st.opacity)
+	f.out("q")
+	r.path(segs, m)
+	f.out(strIf(st.evenOdd, "W* n", "W n"))
+	f.outf("%s %.5f %.5f cm", fixedf("%.5f %.5f %.5f %.5f", pm[0], pm[1], pm[2], pm[3]), pm[4], pm[5])
+	diag := math.Hypot(r.vw, r.vh) / math.Sqrt2
+	if st.fill.ref.name == "radialGradient" {
+		cx, cy := coord("cx", "50%", r.vw), coord("cy", "50%", r.vh)
+		fx, fy := cx, cy
+		if _, ok := attrs["fx"]; ok {
+			fx = coord("fx", "", r.vw)
+		}
+		if _, ok := attrs["fy"]; ok {
+			fy = coord("fy", "", r.vh)
+		}
+		f.gradientStops(3, list, fx, fy, cx, cy, coord("r", "50%", diag))
+	} else {
+		f.gradientStops(2, list, coord("x1", "0%", r.vw), coord("y1", "0%", r.vh),
+			coord("x2", "100%", r.vw), coord("y2", "0%", r.vh), 0)
+	}
+	f.out("Q")
+	return
+}
+
+// svgBounds returns the bounding box of the points of the path segs, which
+// contains the path
+func svgBounds(segs []svgSegType) (x0, y0, x1, y1 float64) {
+	x0, y0 = math.Inf(1), math.Inf(1)
+	x1, y1 = math.Inf(-1), math.Inf(-1)
+	for _, seg := range segs {
+		n := 1
+		switch seg.cmd {
+		case 'C':
+			n = 3
+		case 'Z':
+			n = 0
+		}
+		for _, pt := range seg.pts[:n] {
+			x0, y0 = math.Min(x0, pt.X), math.Min(y0, pt.Y)
+			x1, y1 = math.Max(x1, pt.X), math.Max(y1, pt.Y)
+		}
+	}
+	return
+}
+
+// svgPathParse adds the path data pathStr to p. Parsing stops at the first
+// error, leaving the path that precedes it, as SVG renderers do.
+func svgPathParse(p *svgPathType, pathStr string) {
+	s := svgScannerType{str: pathStr}
+	var cmd byte
+	for {
+		s.skipSpace()
+		if s.pos >= len(s.str) {
+			return
+		}
+		if c := s.str[s.pos]; strings.IndexByte("MmLlHhVvCcSsQqTtAaZz", c) >= 0 {
+			cmd = c
+			s.pos++
+		} else if cmd == 0 || cmd == 'Z' || cmd == 'z' {
+			return
+		}
+		rel := cmd >= 'a'
+		cur := p.cur
+		pt := func(x, y float64) PointType {
+			if rel {
+				return PointType{cur.X + x, cur.Y + y}
+			}
+			return PointType{x, y}
+		}
+		args, ok := s.args(cmd)
+		if !ok {
+			return
+		}
+		reflect := func(cmds string) PointType {
+			if strings.IndexByte(cmds, p.ctrlCmd) >= 0 {
+				return PointType{2*cur.X - p.ctrl.X, 2*cur.Y - p.ctrl.Y}
+			}
+			return cur
+		}
+		ctrlCmd := byte(0)
+		switch cmd {
+		case 'M', 'm':
+			p.moveTo(pt(args[0], args[1]))
+			// Further coordinate pairs are lines
+			cmd = 'L' + cmd - 'M'
+		case 'L', 'l':
+			p.lineTo(pt(args[0], args[1]))
+		case 'H':
+			p.lineTo(PointType{args[0], cur.Y})
+		case 'h':
+			p.lineTo(PointType{cur.X + args[0], cur.Y})
+		case 'V':
+			p.lineTo(PointType{cur.X, args[0]})
+		case 'v':
+			p.lineTo(PointType{cur.X, cur.Y + args[0]})
+		case 'C', 'c':
+			c1 := pt(args[2], args[3])
+			p.cubicTo(pt(args[0], args[1]), c1, pt(args[4], args[5]))
+			p.ctrl, ctrlCmd = c1, 'C'
+		case 'S', 's':
+			c1 := pt(args[0], args[1])
+			p.cubicTo(reflect("C"), c1, pt(args[2], args[3]))
+			p.ctrl, ctrlCmd = c1, 'C'
+		case 'Q', 'q':
+			c := pt(args[0], args[1])
+			p.quadTo(c, pt(args[2], args[3]))
+			p.ctrl, ctrlCmd = c, 'Q'
+		case 'T', 't':
+			c := reflect("Q")
+			p.quadTo(c, pt(args[0], args[1]))
+			p.ctrl, ctrlCmd = c, 'Q'
+		case 'A', 'a':
+			p.arcTo(args[0], args[1], args[2], args[3] != 0, args[4] != 0, pt(args[5], args[6]))
+		case 'Z', 'z':
+			p.close()
+		}
+		p.ctrlCmd = ctrlCmd
+	}
+}
+
+// svgScannerType reads the numbers of SVG path data and attributes
+type svgScannerType struct {
+	str string
+	pos int
+}
+
+// skipSpace skips white space and a comma
+func (s *svgScannerType) skipSpace() {
+	comma := false
+	for s.pos < len(s.str) {
+		switch c := s.str[s.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+		case c == ',' && !comma:
+			comma = true
+		default:
+			return
+		}
+		s.pos++
+	}
+}
+
+// number reads a number; numbers need not be separated where a sign or a
+// second decimal point begins the next one
+func (s *svgScannerType) number() (val float64, ok bool) {
+	s.skipSpace()
+	start := s.pos
+	digits := func() {
+		for s.pos < len(s.str) && s.str[s.pos] >= '0' && s.str[s.pos] <= '9' {
+			s.pos++
+		}
+	}
+	if s.pos < len(s.str) && (s.str[s.pos] == '+' || s.str[s.pos] == '-') {
+		s.pos++
+	}
+	digits()
+	if s.pos < len(s.str) && s.str[s.pos] == '.' {
+		s.pos++
+		digits()
+	}
+	if s.pos < len(s.str) && (s.str[s.pos] == 'e' || s.str[s.pos] == 'E') {
+		exp := s.pos
+		s.pos++
+		if s.pos < len(s.str) && (s.str[s.pos] == '+' || s.str[s.pos] == '-') {
+			s.pos++
+		}
+		if s.pos < len(s.str) && s.str[s.pos] >= '0' && s.str[s.pos] <= '9' {
+			digits()
+		} else {
+			s.pos = exp
+		}
+	}
+	val, err := strconv.ParseFloat(s.str[start:s.pos], 64)
+	if err != nil {
+		s.pos = start
+		return 0, false
+	}
+	return val, true
+}
+
+// flag reads a flag of an arc, which need not be separated from what
+// follows it
+func (s *svgScannerType) flag() (val float64, ok bool) {
+	s.skipSpace()
+	if s.pos < len(s.str) && (s.str[s.pos] == '0' || s.str[s.pos] == '1') {
+		s.pos++
+		return float64(s.str[s.pos-1] - '0'), true
+	}
+	return 0, false
+}
+
+// args reads the arguments of the path command cmd
+func (s *svgScannerType) args(cmd byte) (args [7]float64, ok bool) {
+	var n int
+	switch cmd {
+	case 'Z', 'z':
+		return args, true
+	case 'H', 'h', 'V', 'v':
+		n = 1
+	case 'M', 'm', 'L', 'l', 'T', 't':
+		n = 2
+	case 'S', 's', 'Q', 'q':
+		n = 4
+	case 'C', 'c':
+		n = 6
+	case 'A', 'a':
+		n = 7
+	}
+	for j := 0; j < n; j++ {
+		if (cmd == 'A' || cmd == 'a') && (j == 3 || j == 4) {
+			args[j], ok = s.flag()
+		} else {
+			args[j], ok = s.number()
+		}
+		if !ok {
+			return
+		}
+	}
+	return args, true
+}
+
+// svgNumbers returns the numbers of a list separated by white space or
+// commas, up to the first item that is not a number
+func svgNumbers(listStr string) (list []float64) {
+	s := svgScannerType{str: listStr}
+	for {
+		val, ok := s.number()
+		if !ok {
+			return
+		}
+		list = append(list, val)
+	}
+}
+
+// svgNumber returns the number valStr, or zero if it is not a number
+func svgNumber(valStr string) float64 {
+	val, _ := strconv.ParseFloat(strings.TrimSpace(valStr), 64)
+	return val
+}
+
+// svgLength returns the length valStr in user units. A percentage is taken of
+// ref.
+func svgLength(valStr string, ref float64) float64 {
+	s := svgScannerType{str: valStr}
+	val, ok := s.number()
+	if !ok {
+		return 0
+	}
+	switch strings.TrimSpace(valStr[s.pos:]) {
+	case "%":
+		val *= ref / 100
+	case "pt":
+		val *= 96.0 / 72
+	case "pc":
+		val *= 16
+	case "mm":
+		val *= 96 / 25.4
+	case "cm":
+		val *= 96 / 2.54
+	case "in":
+		val *= 96
+	case "em":
+		val *= 16
+	}
+	return val
+}
+
+// svgOpacity returns the opacity valStr, bounded to the range from 0 to 1
+func svgOpacity(valStr string) float64 {
+	val := svgNumber(strings.TrimSuffix(valStr, "%"))
+	if strings.HasSuffix(valStr, "%") {
+		val /= 100
+	}
+	return math.Max(0, math.Min(1, val))
+}
+
+// svgColor returns the color valStr; currentColor is given the value of cur
+func svgColor(valStr string, cur RGBType) (clr RGBType, ok bool) {
+	valStr = strings.TrimSpace(valStr)
+	lower := strings.ToLower(valStr)
+	switch {
+	case lower == "currentcolor":
+		return cur, true
+	case strings.HasPrefix(valStr, "#"):
+		hex := valStr[1:]
+		if len(hex) == 3 || len(hex) == 4 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+		if len(hex) != 6 && len(hex) != 8 {
+			return
+		}
+		v, err := strconv.ParseUint(hex[:6], 16, 32)
+		if err != nil {
+			return
+		}
+		return RGBType{int(v >> 16), int(v >> 8 & 0xff), int(v & 0xff)}, true
+	case strings.HasPrefix(lower, "rgb"):
+		open, end := strings.Index(valStr, "("), strings.Index(valStr, ")")
+		if open < 0 || end < open {
+			return
+		}
+		fields := strings.FieldsFunc(valStr[open+1:end], func(r rune) bool {
+			return r == ',' || r == ' ' || r == '/'
+		})
+		if len(fields) < 3 {
+			return
+		}
+		var comps [3]int
+		for j := range comps {
+			v := svgNumber(strings.TrimSuffix(fields[j], "%"))
+			if strings.HasSuffix(fields[j], "%") {
+				v = v * 255 / 100
+			}
+			comps[j] = int(math.Max(0, math.Min(255, math.Floor(v+0.5))))
+		}
+		return RGBType{comps[0], comps[1], comps[2]}, true
+	}
+	clr, ok = svgNamedColors[lower]
+	return
+}
+
+// svgStyleRuleType is a rule of a style sheet with a simple selector
+type svgStyleRuleType struct {
+	elemStr     string
+	classes     []string
+	id          string
+	specificity int
+	decls       string
+}
+
+// svgStyleRules returns the rules of the style elements of the image with
+// the root element root, in the order in which they apply
+func svgStyleRules(root *svgNodeType) (rules []svgStyleRuleType) {
+	var collect func(node *svgNodeType)
+	collect = func(node *svgNodeType) {
+		if node.name == "style" {
+			rules = append(rules, svgParseStyleSheet(node.text)...)
+		}
+		for _, child := range node.children {
+			collect(child)
+		}
+	}
+	collect(root)
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].specificity < rules[j].specificity
+	})
+	return
+}
+
+var svgCommentRe = regexp.MustCompile(`(?s)/\*.*?\*/`)
+
+// svgParseStyleSheet returns the rules of the style sheet cssStr that have
+// simple selectors; other rules are ignored
+func svgParseStyleSheet(cssStr string) (rules []svgStyleRuleType) {
+	cssStr = svgCommentRe.ReplaceAllString(cssStr, "")
+	cssStr = strings.Replace(strings.Replace(cssStr, "<![CDATA[", "", -1), "]]>", "", -1)
+	for _, block := range strings.Split(cssStr, "}") {
+		pos := strings.Index(block, "{")
+		if pos < 0 {
+			continue
+		}
+		decls := block[pos+1:]
+		for _, sel := range strings.Split(block[:pos], ",") {
+			sel = strings.TrimSpace(sel)
+			if sel == "" || strings.ContainsAny(sel, " >+~:[@*") {
+				continue
+			}
+			var rule svgStyleRuleType
+			rule.decls = decls
+			parts := strings.FieldsFunc(strings.Replace(strings.Replace(sel, ".", " .", -1), "#", " #", -1),
+				func(r rune) bool { return r == ' ' })
+			for _, part := range parts {
+				switch part[0] {
+				case '.':
+					rule.classes = append(rule.classes, part[1:])
+					rule.specificity += 10
+				case '#':
+					rule.id = part[1:]
+					rule.specificity += 100
+				default:
+					rule.elemStr = part
+					rule.specificity++
+				}
+			}
+			rules = append(rules, rule)
+		}
+	}
+	return
+}
+
+// svgApplyStyles sets the properties of the style sheet rules and of the
+// style attributes of node and its descendants as attributes, which they
+// take precedence over
+func svgApplyStyles(node *svgNodeType, rules []svgStyleRuleType) {
+	classes := strings.Fields(node.attrs["class"])
+	hasClass := func(cls string) bool {
+		for _, c := range classes {
+			if c == cls {
+				return true
+			}
+		}
+		return false
+	}
+	for _, rule := range rules {
+		match := (rule.elemStr == "" || rule.elemStr == node.name) &&
+			(rule.id == "" || rule.id == node.attrs["id"])
+		for _, cls := range rule.classes {
+			match = match && hasClass(cls)
+		}
+		if match {
+			svgApplyDecls(node, rule.decls)
+		}
+	}
+	svgApplyDecls(node, node.attrs["style"])
+	for _, child := range node.children {
+		svgApplyStyles(child, rules)
+	}
+}
+
+// svgApplyDecls sets the properties of the declarations declStr as
+// attributes of node
+func svgApplyDecls(node *svgNodeType, declStr string) {
+	for _, decl := range strings.Split(declStr, ";") {
+		pos := strings.Index(decl, ":")
+		if pos < 0 {
+			continue
+		}
+		key := strings.TrimSpace(decl[:pos])
+		val := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(decl[pos+1:]), "!important"))
+		if key != "" {
+			node.attrs[key] = strings.TrimSpace(val)
+		}
+	}
+}
+
+// svgNamedColors holds the color keywords of CSS
+var svgNamedColors = map[string]RGBType{
+	"aliceblue": {240, 248, 255}, "antiquewhite": {250, 235, 215}, "aqua": {0, 255, 255},
+	"aquamarine": {127, 255, 212}, "azure": {240, 255, 255}, "beige": {245, 245, 220},
+	"bisque": {255, 228, 196}, "black": {0, 0, 0}, "blanchedalmond": {255, 235, 205},
+	"blue": {0, 0, 255}, "blueviolet": {138, 43, 226}, "brown": {165, 42, 42},
+	"burlywood": {222, 184, 135}, "cadetblue": {95, 158, 160}, "chartreuse": {127, 255, 0},
+	"chocolate": {210, 105, 30}, "coral": {255, 127, 80}, "cornflowerblue": {100, 149, 237},
+	"cornsilk": {255, 248, 220}, "crimson": {220, 20, 60}, "cyan": {0, 255, 255},
+	"darkblue": {0, 0, 139}, "darkcyan": {0, 139, 139}, "darkgoldenrod": {184, 134, 11},
+	"darkgray": {169, 169, 169}, "darkgreen": {0, 100, 0}, "darkgrey": {169, 169, 169},
+	"darkkhaki": {189, 183, 107}, "darkmagenta": {139, 0, 139}, "darkolivegreen": {85, 107, 47},
+	"darkorange": {255, 140, 0}, "darkorchid": {153, 50, 204}, "darkred": {139, 0, 0},
+	"darksalmon": {233, 150, 122}, "darkseagreen": {143, 188, 143}, "darkslateblue": {72, 61, 139},
+	"darkslategray": {47, 79, 79}, "darkslategrey": {47, 79, 79}, "darkturquoise": {0, 206, 209},
+	"darkviolet": {148, 0, 211}, "deeppink": {255, 20, 147}, "deepskyblue": {0, 191, 255},
+	"dimgray": {105, 105, 105}, "dimgrey": {105, 105, 105}, "dodgerblue": {30, 144, 255},
+	"firebrick": {178, 34, 34}, "floralwhite": {255, 250, 240}, "forestgreen": {34, 139, 34},
+	"fuchsia": {255, 0, 255}, "gainsboro": {220, 220, 220}, "ghostwhite": {248, 248, 255},
+	"gold": {255, 215, 0}, "goldenrod": {218, 165, 32}, "gray": {128, 128, 128},
+	"green": {0, 128, 0}, "greenyellow": {173, 255, 47}, "grey": {128, 128, 128},
+	"honeydew": {240, 255, 240}, "hotpink": {255, 105, 180}, "indianred": {205, 92, 92},
+	"indigo": {75, 0, 130}, "ivory": {255, 255, 240}, "khaki": {240, 230, 140},
+	"lavender": {230, 230, 250}, "lavenderblush": {255, 240, 245}, "lawngreen": {124, 252, 0},
+	"lemonchiffon": {255, 250, 205}, "lightblue": {173, 216, 230}, "lightcoral": {240, 128, 128},
+	"lightcyan": {224, 255, 255}, "lightgoldenrodyellow": {250, 250, 210}, "lightgray": {211, 211, 211},
+	"lightgreen": {144, 238, 144}, "lightgrey": {211, 211, 211}, "lightpink": {255, 182, 193},
+	"lightsalmon": {255, 160, 122}, "lightseagreen": {32, 178, 170}, "lightskyblue": {135, 206, 250},
+	"lightslategray": {119, 136, 153}, "lightslategrey": {119, 136, 153}, "lightsteelblue": {176, 196, 222},
+	"lightyellow": {255, 255, 224}, "lime": {0, 255, 0}, "limegreen": {50, 205, 50},
+	"linen": {250, 240, 230}, "magenta": {255, 0, 255}, "maroon": {128, 0, 0},
+	"mediumaquamarine": {102, 205, 170}, "mediumblue": {0, 0, 205}, "mediumorchid": {186, 85, 211},
+	"mediumpurple": {147, 112, 219}, "mediumseagreen": {60, 179, 113}, "mediumslateblue": {123, 104, 238},
+	"mediumspringgreen": {0, 250, 154}, "mediumturquoise": {72, 209, 204}, "mediumvioletred": {199, 21, 133},
+	"midnightblue": {25, 25, 112}, "mintcream": {245, 255, 250}, "mistyrose": {255, 228, 225},
+	"moccasin": {255, 228, 181}, "navajowhite": {255, 222, 173}, "navy": {0, 0, 128},
+	"oldlace": {253, 245, 230}, "olive": {128, 128, 0}, "olivedrab": {107, 142, 35},
+	"orange": {255, 165, 0}, "orangered": {255, 69, 0}, "orchid": {218, 112, 214},
+	"palegoldenrod": {238, 232, 170}, "palegreen": {152, 251, 152}, "paleturquoise": {175, 238, 238},
+	"palevioletred": {219, 112, 147}, "papayawhip": {255, 239, 213}, "peachpuff": {255, 218, 185},
+	"peru": {205, 133, 63}, "pink": {255, 192, 203}, "plum": {221, 160, 221},
+	"powderblue": {176, 224, 230}, "purple": {128, 0, 128}, "rebeccapurple": {102, 51, 153},
+	"red": {255, 0, 0}, "rosybrown": {188, 143, 143}, "royalblue": {65, 105, 225},
+	"saddlebrown": {139, 69, 19}, "salmon": {250, 128, 114}, "sandybrown": {244, 164, 96},
+	"seagreen": {46, 139, 87}, "seashell": {255, 245, 238}, "sienna": {160, 82, 45},
+	"silver": {192, 192, 192}, "skyblue": {135, 206, 235}, "slateblue": {106, 90, 205},
+	"slategray": {112, 128, 144}, "slategrey": {112, 128, 144}, "snow": {255, 250, 250},
+	"springgreen": {0, 255, 127}, "steelblue": {70, 130, 180}, "tan": {210, 180, 140},
+	"teal": {0, 128, 128}, "thistle": {216, 191, 216}, "tomato": {255, 99, 71},
+	"turquoise": {64, 224, 208}, "violet": {238, 130, 238}, "wheat": {245, 222, 179},
+	"white": {255, 255, 255}, "whitesmoke": {245, 245, 245}, "yellow": {255, 255, 0},
+	"yellowgreen": {154, 205, 50},
+}
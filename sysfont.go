@@ -0,0 +1,256 @@
+package gofpdf
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// SystemFontDirs returns the directories in which fonts are installed on the
+// current operating system. These are the directories searched by
+// LoadSystemFont() unless others are specified with SetSystemFontDirs().
+// Directories that do not exist are included.
+func SystemFontDirs() (dirs []string) {
+	home, _ := os.UserHomeDir()
+	switch runtime.GOOS {
+	case "windows":
+		winDir := os.Getenv("WINDIR")
+		if winDir == "" {
+			winDir = `C:\Windows`
+		}
+		dirs = append(dirs, filepath.Join(winDir, "Fonts"))
+		if appData := os.Getenv("LOCALAPPDATA"); appData != "" {
+			dirs = append(dirs, filepath.Join(appData, "Microsoft", "Windows", "Fonts"))
+		}
+	case "darwin":
+		dirs = append(dirs, "/System/Library/Fonts", "/Library/Fonts")
+		if home != "" {
+			dirs = append(dirs, filepath.Join(home, "Library", "Fonts"))
+		}
+	default:
+		dirs = append(dirs, "/usr/share/fonts", "/usr/local/share/fonts")
+		if home != "" {
+			dirs = append(dirs, filepath.Join(home, ".local", "share", "fonts"),
+				filepath.Join(home, ".fonts"))
+		}
+	}
+	return
+}
+
+// SetSystemFontDirs sets the directories that LoadSystemFont() searches for
+// fonts, replacing the directories returned by SystemFontDirs(). Only font
+// files within these directories and their subdirectories are read; symbolic
+// links that lead elsewhere are ignored. Calling SetSystemFontDirs() with no
+// arguments restores the default directories.
+func (f *Fpdf) SetSystemFontDirs(dirs ...string) {
+	f.sysFontDirs = dirs
+	f.sysFonts = nil
+}
+
+// LoadSystemFont locates an installed TrueType font of the specified family
+// and style and makes it available with AddUTF8FontFromBytes(), so that it
+// can be selected with SetFont(familyStr, styleStr). This is convenient for
+// internal tools for which bundling font files is not worthwhile; documents
+// that must look the same wherever they are generated should use fonts
+// distributed with the application.
+//
+// familyStr is matched against the family name recorded in the font files,
+// such as "DejaVu Sans" or "Liberation Serif", ignoring case, spaces, hyphens
+// and underscores. styleStr is "", "B", "I" or "BI". Files with the
+// extensions .ttf and .otf are considered, with either TrueType or PostScript
+// (CFF) outlines; font collections are not supported. The directories
+// searched are those of SetSystemFontDirs(), or by default those of
+// SystemFontDirs(). If no matching font is found, a FontError, listing any
+// families with similar names that are installed, is set.
+func (f *Fpdf) LoadSystemFont(familyStr, styleStr string) {
+	if f.err != nil {
+		return
+	}
+	familyStr = strings.ToLower(familyStr)
+	styleStr = f.fontStyleKey(familyStr, strings.ToUpper(styleStr))
+	if f.err != nil {
+		return
+	}
+	if f.sysFonts == nil {
+		dirs := f.sysFontDirs
+		if len(dirs) == 0 {
+			dirs = SystemFontDirs()
+		}
+		f.sysFonts = scanSystemFonts(dirs)
+	}
+	font, ok := f.sysFonts[sysFontKey(familyStr, styleStr)]
+	if !ok {
+		e := &FontError{Family: familyStr, Style: styleStr,
+			Reason: "not found in the system font directories"}
+		list := make([]string, 0, len(f.sysFonts))
+		for _, fnt := range f.sysFonts {
+			if similarNames(sysFontName(fnt.family), sysFontName(familyStr)) {
+				list = append(list, strings.TrimSpace(fnt.family+" "+fnt.style))
+			}
+		}
+		sort.Strings(list)
+		e.Suggestions = list
+		f.err = e
+		return
+	}
+	buf, err := ioutil.ReadFile(font.pathStr)
+	if err != nil {
+		f.SetError(err)
+		return
+	}
+	f.AddUTF8FontFromBytes(familyStr, styleStr, buf)
+}
+
+// sysFontType describes an installed font file
+type sysFontType struct {
+	pathStr       string
+	family, style string // family as recorded in the file, style as in SetFont()
+	standard      bool   // subfamily is a basic name such as "Regular" or "Bold"
+}
+
+// sysFontName returns familyStr in lower case without spaces, hyphens and
+// underscores
+func sysFontName(familyStr string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '_':
+			return -1
+		}
+		return r
+	}, strings.ToLower(familyStr))
+}
+
+// sysFontKey returns the key of the index of installed fonts
+func sysFontKey(familyStr, styleStr string) string {
+	return sysFontName(familyStr) + "/" + styleStr
+}
+
+// scanSystemFonts returns an index of the supported font files in dirs
+func scanSystemFonts(dirs []string) map[string]sysFontType {
+	list := make(map[string]sysFontType)
+	for _, dir := range dirs {
+		root, err := filepath.EvalSymlinks(dir)
+		if err != nil {
+			continue
+		}
+		filepath.Walk(root, func(pathStr string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return nil
+			}
+			switch strings.ToLower(filepath.Ext(pathStr)) {
+			case ".ttf", ".otf":
+			default:
+				return nil
+			}
+			if info.Mode()&os.ModeSymlink != 0 {
+				target, err := filepath.EvalSymlinks(pathStr)
+				if err != nil || !strings.HasPrefix(target, root+string(filepath.Separator)) {
+					return nil
+				}
+			}
+			family, sub, ok := readFontNames(pathStr)
+			if !ok {
+				return nil
+			}
+			lower := strings.ToLower(sub)
+			font := sysFontType{pathStr: pathStr, family: family}
+			if strings.Contains(lower, "bold") {
+				font.style += "B"
+			}
+			if strings.Contains(lower, "italic") || strings.Contains(lower, "oblique") {
+				font.style += "I"
+			}
+			switch lower {
+			case "regular", "book", "normal", "roman", "bold", "italic", "oblique",
+				"bold italic", "bold oblique":
+				font.standard = true
+			}
+			key := sysFontKey(family, font.style)
+			if prev, ok := list[key]; !ok || (font.standard && !prev.standard) {
+				list[key] = font
+			}
+			return nil
+		})
+	}
+	return list
+}
+
+// readFontNames returns the family and subfamily names recorded in the
+// OpenType font file pathStr, or false if the file cannot be read or is not a
+// TrueType or OpenType font
+func readFontNames(pathStr string) (family, sub string, ok bool) {
+	fl, err := os.Open(pathStr)
+	if err != nil {
+		return
+	}
+	defer fl.Close()
+	read := func(off int64, n int) []byte {
+		buf := make([]byte, n)
+		if _, err := fl.ReadAt(buf, off); err != nil {
+			return nil
+		}
+		return buf
+	}
+	hdr := read(0, 12)
+	if hdr == nil {
+		return
+	}
+	switch binary.BigEndian.Uint32(hdr) {
+	case 0x00010000, 0x74727565, 0x4F54544F: // TrueType or CFF outlines
+	default:
+		return
+	}
+	numTables := int(binary.BigEndian.Uint16(hdr[4:]))
+	dir := read(12, 16*numTables)
+	if dir == nil {
+		return
+	}
+	var nameOff, nameLen int64
+	for j := 0; j < numTables; j++ {
+		rec := dir[16*j:]
+		if string(rec[:4]) == "name" {
+			nameOff = int64(binary.BigEndian.Uint32(rec[8:]))
+			nameLen = int64(binary.BigEndian.Uint32(rec[12:]))
+		}
+	}
+	if nameLen < 6 || nameLen > 1<<20 {
+		return
+	}
+	tbl := read(nameOff, int(nameLen))
+	if tbl == nil {
+		return
+	}
+	count := int(binary.BigEndian.Uint16(tbl[2:]))
+	strOff := int(binary.BigEndian.Uint16(tbl[4:]))
+	names := make(map[int]string)
+	for j := 0; j < count && 6+12*(j+1) <= len(tbl); j++ {
+		rec := tbl[6+12*j:]
+		platform, encoding := binary.BigEndian.Uint16(rec), binary.BigEndian.Uint16(rec[2:])
+		lang, id := binary.BigEndian.Uint16(rec[4:]), int(binary.BigEndian.Uint16(rec[6:]))
+		n, off := int(binary.BigEndian.Uint16(rec[8:])), strOff+int(binary.BigEndian.Uint16(rec[10:]))
+		if (id != 1 && id != 2) || off+n > len(tbl) {
+			continue
+		}
+		str := tbl[off : off+n]
+		switch {
+		case platform == 3 && (encoding == 1 || encoding == 0) && lang == 0x409:
+			u := make([]uint16, n/2)
+			for k := range u {
+				u[k] = binary.BigEndian.Uint16(str[2*k:])
+			}
+			names[id] = string(utf16.Decode(u))
+		case platform == 1 && encoding == 0 && lang == 0:
+			if _, ok := names[id]; !ok {
+				names[id] = string(str)
+			}
+		}
+	}
+	family, sub = names[1], names[2]
+	ok = family != ""
+	return
+}
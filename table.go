@@ -0,0 +1,393 @@
+package gofpdf
+
+import "math"
+
+// TableColumnType describes a column of a table created with NewTable().
+type TableColumnType struct {
+	// Text of the column heading
+	Header string
+	// Width in user units; columns with zero width share the width that the
+	// other columns leave between the starting position and the right margin
+	W float64
+	// Alignment of the cells of the column, as used by MultiCell(); empty
+	// aligns text with the start of the line
+	Align string
+}
+
+// TableCellType is a cell of a table row added with RowCells().
+type TableCellType struct {
+	Str string
+	// Number of rows the cell occupies in its column; zero or one for a cell
+	// of a single row
+	RowSpan int
+	// Alignment of the cell; empty selects the alignment of the column
+	Align string
+}
+
+// tableCellRec is a cell of a pending table row
+type tableCellRec struct {
+	col, span     int
+	str, alignStr string
+}
+
+// TableType assists with the layout of tables whose cells wrap their text.
+// Create a value with NewTable(), adjust its exported fields as needed, add
+// rows with Row() or RowCells() and finish the table with End().
+type TableType struct {
+	// Line height in user units; zero selects the line height of the
+	// document (see SetLineHeight())
+	LineHt float64
+	// Space between the cell edges and the text in user units
+	Padding float64
+	// Draw a frame around each cell
+	Border bool
+	// Font style of the header, such as "B"
+	HeaderStyle string
+	// Paint the background of the header, and of every other row
+	HeaderFill, Stripe bool
+	// Print the header again at the top of each page the table continues on
+	RepeatHeader bool
+	// Colors of the frames, the header background and the striped rows
+	ClrBorder, ClrHeader, ClrStripe RGBType
+	// Name under which the rectangle the table occupies on each page is
+	// recorded as an element of kind ElementTable; see ElementManifest().
+	// Empty records nothing.
+	Element string
+
+	f          *Fpdf
+	cols       []TableColumnType
+	widths     []float64
+	x          float64
+	rows       [][]tableCellRec // rows waiting for the end of a row span
+	covered    []int            // rows of each column still occupied by a span
+	headerDone bool
+	noHeader   bool // whether the columns have no headings
+	rowCount   int
+}
+
+// NewTable returns a table with the specified columns that begins at the
+// current position. The table is initialized to print a bold header on a
+// gray background, frames around the cells, a padding equal to the cell
+// margin (see SetCellMargin()) and every other row on a light gray
+// background.
+//
+// The cells of each row wrap their text like MultiCell(), and each row is as
+// tall as its tallest cell. A cell added with RowCells() may span several
+// rows of its column; the rows it spans are kept together on one page and, if
+// its text needs more height than they have, the last of them is made
+// taller. The rows that follow take the remaining columns in order. Before a
+// row that does not fit on the page, the page is broken, subject to the
+// application's decision about content of the kind PageBreakBlock, and the
+// header is repeated; a row or group of spanned rows that is taller than a
+// page is not split. The header is never left alone at the bottom of a page.
+// A table whose columns all have empty headings has no header.
+//
+// The text of the cells is printed in the current font when the rows are
+// laid out. In right-to-left mode (see RTL()) the order of the columns is
+// mirrored and cells without an explicit alignment are aligned on the right.
+func (f *Fpdf) NewTable(columns []TableColumnType) (t *TableType) {
+	t = &TableType{
+		Padding:      f.cMargin,
+		Border:       true,
+		HeaderStyle:  "B",
+		HeaderFill:   true,
+		Stripe:       true,
+		RepeatHeader: true,
+		ClrBorder:    RGBType{128, 128, 128},
+		ClrHeader:    RGBType{210, 210, 210},
+		ClrStripe:    RGBType{240, 240, 240},
+		f:            f,
+		cols:         columns,
+		x:            f.x,
+		covered:      make([]int, len(columns)),
+	}
+	if len(columns) == 0 {
+		f.SetErrorf("table has no columns")
+		return
+	}
+	t.noHeader = true
+	for _, col := range columns {
+		if col.Header != "" {
+			t.noHeader = false
+		}
+	}
+	t.headerDone = t.noHeader
+	// Distribute the remaining width among columns that have none
+	t.widths = make([]float64, len(columns))
+	rest, count := f.w-f.rMargin-f.x, 0
+	for j, col := range columns {
+		t.widths[j] = col.W
+		rest -= col.W
+		if col.W == 0 {
+			count++
+		}
+	}
+	for j := range t.widths {
+		if t.widths[j] == 0 {
+			t.widths[j] = rest / float64(count)
+		}
+	}
+	return
+}
+
+// Row adds a row whose cells contain the strings in strList, one for each
+// column that is not occupied by a cell spanning rows from above.
+func (t *TableType) Row(strList ...string) {
+	cells := make([]TableCellType, len(strList))
+	for j, str := range strList {
+		cells[j].Str = str
+	}
+	t.RowCells(cells...)
+}
+
+// RowCells adds a row of cells, which take the columns that are not occupied
+// by a cell spanning rows from above in order. Columns left without a cell
+// are empty. Rows are printed as soon as no span from them or from earlier
+// rows remains open.
+func (t *TableType) RowCells(cells ...TableCellType) {
+	f := t.f
+	if f.err != nil {
+		return
+	}
+	prev := append([]int(nil), t.covered...)
+	row := make([]tableCellRec, 0, len(t.cols))
+	spans := make([]int, len(t.cols))
+	col := 0
+	for _, c := range cells {
+		for col < len(t.cols) && prev[col] > 0 {
+			col++
+		}
+		if col >= len(t.cols) {
+			f.SetErrorf("table row has more cells than free columns")
+			return
+		}
+		spans[col] = c.RowSpan
+		if spans[col] < 1 {
+			spans[col] = 1
+		}
+		row = append(row, tableCellRec{col: col, span: spans[col], str: c.Str, alignStr: c.Align})
+		col++
+	}
+	for j := range t.cols {
+		switch {
+		case prev[j] > 0:
+			t.covered[j] = prev[j] - 1
+		case spans[j] > 0:
+			t.covered[j] = spans[j] - 1
+		default:
+			row = append(row, tableCellRec{col: j, span: 1})
+		}
+	}
+	t.rows = append(t.rows, row)
+	for _, n := range t.covered {
+		if n > 0 {
+			return
+		}
+	}
+	t.flush()
+}
+
+// End prints the rows that are waiting for the end of a row span, cutting the
+// span short, and the header if no row has been added. The current position
+// is then below the table at the horizontal position where it began.
+func (t *TableType) End() {
+	for j := range t.covered {
+		t.covered[j] = 0
+	}
+	t.flush()
+}
+
+// order returns the indexes of the columns in the order of the writing
+// direction
+func (t *TableType) order() []int {
+	order := make([]int, len(t.cols))
+	for j := range order {
+		order[j] = j
+		if t.f.rtlMirrored() {
+			order[j] = len(order) - 1 - j
+		}
+	}
+	return order
+}
+
+// heights returns the heights of rows, extending the last row spanned by a
+// cell if the cell needs more height than the rows it spans; spans that
+// reach past the last row are cut short
+func (t *TableType) heights(rows [][]tableCellRec, lineHt float64) (hts []float64) {
+	f := t.f
+	pad := t.Padding
+	cellHt := func(c tableCellRec) float64 {
+		return float64(f.multiCellLineCount(c.str, t.widths[c.col]))*lineHt + 2*pad
+	}
+	hts = make([]float64, len(rows))
+	for i, row := range rows {
+		hts[i] = lineHt + 2*pad
+		for k := range row {
+			if row[k].span > len(rows)-i {
+				row[k].span = len(rows) - i
+			}
+			if row[k].span == 1 {
+				hts[i] = math.Max(hts[i], cellHt(row[k]))
+			}
+		}
+	}
+	for i, row := range rows {
+		for _, c := range row {
+			if c.span == 1 {
+				continue
+			}
+			var sum float64
+			for _, ht := range hts[i : i+c.span] {
+				sum += ht
+			}
+			if need := cellHt(c); need > sum {
+				hts[i+c.span-1] += need - sum
+			}
+		}
+	}
+	return
+}
+
+// draw prints rows, whose heights are hts, at the current vertical position
+func (t *TableType) draw(rows [][]tableCellRec, hts []float64, header bool) {
+	f := t.f
+	xs := make([]float64, len(t.cols))
+	cx := t.x
+	for _, j := range t.order() {
+		xs[j] = cx
+		cx += t.widths[j]
+	}
+	top := f.y
+	y := top
+	for i, row := range rows {
+		for _, c := range row {
+			var h float64
+			for _, ht := range hts[i : i+c.span] {
+				h += ht
+			}
+			alignStr := c.alignStr
+			if alignStr == "" {
+				alignStr = t.cols[c.col].Align
+			}
+			switch {
+			case header:
+				alignStr = "C"
+			case alignStr == "" && f.rtlMirrored():
+				alignStr = "R"
+			case alignStr == "":
+				alignStr = "L"
+			}
+			fill := false
+			switch {
+			case header:
+				fill = t.HeaderFill
+				f.SetFillColor(int(t.ClrHeader.R), int(t.ClrHeader.G), int(t.ClrHeader.B))
+			case t.Stripe && (t.rowCount+i)%2 == 1:
+				fill = true
+				f.SetFillColor(int(t.ClrStripe.R), int(t.ClrStripe.G), int(t.ClrStripe.B))
+			}
+			if fill {
+				f.Rect(xs[c.col], y, t.widths[c.col], h, "F")
+			}
+			if t.Border {
+				f.Rect(xs[c.col], y, t.widths[c.col], h, "D")
+			}
+			f.SetXY(xs[c.col], y+t.Padding)
+			f.MultiCell(t.widths[c.col], t.lineHt(), c.str, "", alignStr, false)
+		}
+		y += hts[i]
+	}
+	var wd float64
+	for _, w := range t.widths {
+		wd += w
+	}
+	f.RecordElement(t.Element, ElementTable, t.x, top, wd, y-top)
+	f.SetXY(t.x, y)
+}
+
+// lineHt returns the line height of the cells
+func (t *TableType) lineHt() float64 {
+	if t.LineHt > 0 {
+		return t.LineHt
+	}
+	return t.f.GetLineHeight()
+}
+
+// header returns the header row and its height, which is measured in the
+// style of the header
+func (t *TableType) header() (row [][]tableCellRec, ht float64) {
+	f := t.f
+	styleStr := f.fontStyleStr()
+	f.SetFontStyle(t.HeaderStyle)
+	row = [][]tableCellRec{make([]tableCellRec, len(t.cols))}
+	for j, col := range t.cols {
+		row[0][j] = tableCellRec{col: j, span: 1, str: col.Header}
+	}
+	ht = t.heights(row, t.lineHt())[0]
+	f.SetFontStyle(styleStr)
+	return
+}
+
+// putHeader prints the header row
+func (t *TableType) putHeader(row [][]tableCellRec, ht float64) {
+	f := t.f
+	styleStr := f.fontStyleStr()
+	f.SetFontStyle(t.HeaderStyle)
+	t.draw(row, []float64{ht}, true)
+	f.SetFontStyle(styleStr)
+	t.headerDone = true
+}
+
+// flush prints the pending rows, preceded by the header if it has not been
+// printed or if the rows begin a new page
+func (t *TableType) flush() {
+	f := t.f
+	if f.err != nil || len(t.rows) == 0 && t.headerDone {
+		return
+	}
+	if f.page == 0 {
+		f.SetErrorf("table rows are printed before the first page")
+		return
+	}
+	drawR, drawG, drawB := f.GetDrawColor()
+	fillR, fillG, fillB := f.GetFillColor()
+	cMargin, autoPageBreak := f.cMargin, f.autoPageBreak
+	f.SetDrawColor(int(t.ClrBorder.R), int(t.ClrBorder.G), int(t.ClrBorder.B))
+	f.cMargin = t.Padding
+	var hdrRow [][]tableCellRec
+	var hdrHt float64
+	if !t.noHeader {
+		hdrRow, hdrHt = t.header()
+	}
+	hts := t.heights(t.rows, t.lineHt())
+	var total float64
+	for _, ht := range hts {
+		total += ht
+	}
+	// The header stays with the first rows
+	need := total
+	if !t.headerDone {
+		need += hdrHt
+	}
+	if f.y+need > f.pageBreakTrigger && !f.inHeader && !f.inFooter && f.acceptPageBreakFor(PageBreakBlock, f.y, need) {
+		f.AddPageFormat(f.curOrientation, f.curPageSize)
+		if t.RepeatHeader && !t.noHeader {
+			t.headerDone = false
+		}
+	}
+	// Cells are laid out within the computed heights
+	f.autoPageBreak = false
+	if !t.headerDone && f.err == nil {
+		f.SetX(t.x)
+		t.putHeader(hdrRow, hdrHt)
+	}
+	if f.err == nil {
+		t.draw(t.rows, hts, false)
+	}
+	f.autoPageBreak = autoPageBreak
+	f.cMargin = cMargin
+	f.SetDrawColor(drawR, drawG, drawB)
+	f.SetFillColor(fillR, fillG, fillB)
+	t.rowCount += len(t.rows)
+	t.rows = t.rows[:0]
+}
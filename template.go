@@ -107,7 +107,7 @@ func (f *Fpdf) UseTemplateScaled(t Template, corner PointType, size SizeType) {
 	tx := corner.X * f.k
 	ty := (f.curPageSize.Ht - corner.Y - size.Ht) * f.k
 
-	f.outf("q %.4f 0 0 %.4f %.4f %.4f cm", scaleX, scaleY, tx, ty) // Translate
+	f.outf("q %s %.4f %.4f cm", fixedf("%.4f 0 0 %.4f", scaleX, scaleY), tx, ty) // Translate
 	f.outf("/TPL%s Do Q", t.ID())
 }
 
@@ -132,7 +132,9 @@ func (f *Fpdf) templateFontCatalog() {
 	var key string
 	f.out("/Font <<")
 	for key = range f.fonts {
-		keyList = append(keyList, key)
+		if !f.fontOmitted(f.fonts[key]) {
+			keyList = append(keyList, key)
+		}
 	}
 	if f.catalogSort {
 		sort.Strings(keyList)
@@ -290,12 +290,14 @@ func (t *Tpl) loadParamsFromFpdf(f *Fpdf) {
 	t.Fpdf.color.text = f.color.text
 
 	t.Fpdf.fonts = f.fonts
+	t.Fpdf.fontsSelected = f.fontsSelected
 	t.Fpdf.currentFont = f.currentFont
 	t.Fpdf.fontFamily = f.fontFamily
 	t.Fpdf.fontSize = f.fontSize
 	t.Fpdf.fontSizePt = f.fontSizePt
 	t.Fpdf.fontStyle = f.fontStyle
 	t.Fpdf.ws = f.ws
+	t.Fpdf.floatPrecision = f.floatPrecision
 
 	for key, value := range f.images {
 		t.Fpdf.images[key] = value
@@ -0,0 +1,55 @@
+package gofpdf
+
+// SetTextAsPaths specifies whether text printed in a UTF-8 font is drawn as
+// filled paths, built from the glyph outlines of the font, instead of being
+// shown with the font. The appearance of such text does not depend on the
+// viewer's font handling and survives processing that discards or replaces
+// fonts. A UTF-8 font in which no text is shown is left out of the document,
+// so this also permits the use of fonts whose license does not allow embedding.
+// The text can no longer be selected, searched or extracted, and the document
+// is larger, so this should be reserved for short passages such as headings
+// and logos.
+//
+// The setting applies to text printed with Cell(), MultiCell(), Write(),
+// Text() and related methods while a UTF-8 font is selected; text in other
+// fonts is not affected. The paths are filled with the text color; the text
+// rendering mode set with SetTextRenderingMode() does not apply.
+func (f *Fpdf) SetTextAsPaths(on bool) {
+	f.textAsPaths = on
+}
+
+// GetTextAsPaths reports whether text printed in a UTF-8 font is drawn as
+// filled paths. See SetTextAsPaths().
+func (f *Fpdf) GetTextAsPaths() bool {
+	return f.textAsPaths
+}
+
+// textPath writes to s the operators that fill the glyph outlines of txtStr
+// in the current UTF-8 font, beginning at the left end (x, y) of the
+// baseline. wordSpace is added to the advance of each space.
+func (f *Fpdf) textPath(s *fmtBuffer, x, y float64, txtStr string, wordSpace float64) {
+	key := getFontKey(f.fontFamily, f.fontStyle)
+	font, ok := f.fonts[key]
+	if !ok || font.utf8File == nil {
+		return
+	}
+	n := s.Len()
+	for _, r := range txtStr {
+		glyph, ok := f.glyphOutline(key, font, r)
+		if f.err != nil {
+			return
+		}
+		wd := glyph.Width
+		if !ok {
+			wd = float64(font.Desc.MissingWidth)
+		}
+		f.glyphPath(s, glyph, x, y, f.fontSize)
+		x += wd * f.fontSize / 1000
+		if r == ' ' {
+			x += wordSpace
+		}
+	}
+	if s.Len() > n {
+		s.printf("f")
+	}
+}
@@ -0,0 +1,138 @@
+package gofpdf
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Text transforms recognized by TransformText() and the Transform field of
+// DocStyleType
+const (
+	// TransformUpper converts text to uppercase
+	TransformUpper = "uppercase"
+	// TransformLower converts text to lowercase
+	TransformLower = "lowercase"
+	// TransformTitle converts the first letter of each word to titlecase
+	// and leaves the others unchanged, so that acronyms keep their capitals
+	TransformTitle = "titlecase"
+)
+
+// caseSpecialUpper holds the characters whose uppercase form is more than
+// one character; their titlecase form is the first of these followed by the
+// lowercase form of the rest
+var caseSpecialUpper = map[rune]string{
+	'ß': "SS",
+	'ŉ': "ʼN",
+	'ﬀ': "FF",
+	'ﬁ': "FI",
+	'ﬂ': "FL",
+	'ﬃ': "FFI",
+	'ﬄ': "FFL",
+	'ﬅ': "ST",
+	'ﬆ': "ST",
+}
+
+// TransformText returns s converted by transformStr, which is one of
+// TransformUpper, TransformLower or TransformTitle; an empty transformStr
+// returns s unchanged. langStr is the language tag of the text, such as
+// "tr-TR", and selects the casing rules of the language where they differ
+// from the general ones: in Turkish and Azerbaijani the dotted and dotless
+// i are distinct letters, and in Dutch the digraph ij is capitalized as a
+// whole at the beginning of a word. Characters such as ß whose uppercase
+// form has more than one letter are expanded, and a lowercase Greek sigma
+// takes its final form at the end of a word. Words are sequences of letters,
+// digits and marks, including apostrophes between letters.
+func TransformText(s, transformStr, langStr string) (string, error) {
+	lang := strings.ToLower(langStr)
+	if pos := strings.IndexAny(lang, "-_"); pos >= 0 {
+		lang = lang[:pos]
+	}
+	var special unicode.SpecialCase
+	if lang == "tr" || lang == "az" {
+		special = unicode.TurkishCase
+	}
+	switch transformStr {
+	case "":
+		return s, nil
+	case TransformUpper:
+		return caseUpper(s, special), nil
+	case TransformLower:
+		return caseLower(s, special), nil
+	case TransformTitle:
+		return caseTitle(s, special, lang == "nl"), nil
+	}
+	return s, fmt.Errorf("unknown text transform %s", transformStr)
+}
+
+// caseUpper returns s in uppercase
+func caseUpper(s string, special unicode.SpecialCase) string {
+	var b strings.Builder
+	for _, r := range s {
+		if str, ok := caseSpecialUpper[r]; ok {
+			b.WriteString(str)
+		} else if special != nil {
+			b.WriteRune(special.ToUpper(r))
+		} else {
+			b.WriteRune(unicode.ToUpper(r))
+		}
+	}
+	return b.String()
+}
+
+// caseLower returns s in lowercase
+func caseLower(s string, special unicode.SpecialCase) string {
+	list := []rune(s)
+	var b strings.Builder
+	for j, r := range list {
+		switch {
+		case r == 'Σ' && j > 0 && caseWordRune(list, j-1) && (j+1 == len(list) || !caseWordRune(list, j+1)):
+			b.WriteRune('ς')
+		case special != nil:
+			b.WriteRune(special.ToLower(r))
+		default:
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
+
+// caseTitle returns s with the first letter of each word in titlecase. If
+// dutch is true, a word that begins with ij begins with IJ.
+func caseTitle(s string, special unicode.SpecialCase, dutch bool) string {
+	list := []rune(s)
+	var b strings.Builder
+	for j := 0; j < len(list); j++ {
+		r := list[j]
+		if j > 0 && caseWordRune(list, j-1) || !unicode.IsLetter(r) {
+			b.WriteRune(r)
+			continue
+		}
+		switch {
+		case dutch && (r == 'i' || r == 'I') && j+1 < len(list) && (list[j+1] == 'j' || list[j+1] == 'J'):
+			b.WriteString("IJ")
+			j++
+		case caseSpecialUpper[r] != "":
+			str := []rune(caseSpecialUpper[r])
+			b.WriteRune(str[0])
+			b.WriteString(strings.ToLower(string(str[1:])))
+		case special != nil:
+			b.WriteRune(special.ToTitle(r))
+		default:
+			b.WriteRune(unicode.ToTitle(r))
+		}
+	}
+	return b.String()
+}
+
+// caseWordRune returns true if list[j] belongs to a word
+func caseWordRune(list []rune, j int) bool {
+	r := list[j]
+	if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) {
+		return true
+	}
+	if r == '\'' || r == '’' {
+		return j > 0 && j+1 < len(list) && unicode.IsLetter(list[j-1]) && unicode.IsLetter(list[j+1])
+	}
+	return false
+}
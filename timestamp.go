@@ -0,0 +1,151 @@
+package gofpdf
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+)
+
+// timestampContentsSize is the space reserved for a time-stamp token, in
+// bytes
+const timestampContentsSize = 16384
+
+// Object identifiers of time-stamp tokens (RFC 3161)
+var oidTSTInfo = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 1, 4}
+
+// TimestampFunc obtains a time stamp from a time-stamping authority (TSA) for
+// TimestampPDF(). It is passed the DER-encoded RFC 3161 time-stamp request
+// req and returns the DER-encoded time-stamp response of the authority.
+// Typically it posts req to the authority over HTTP with the content type
+// "application/timestamp-query" and returns the body of the reply, which has
+// the content type "application/timestamp-reply".
+type TimestampFunc func(req []byte) (resp []byte, err error)
+
+// TimestampPDF timestamps the document pdf with an RFC 3161 document
+// timestamp and returns the timestamped document. The timestamp is stored in
+// the signature field named fieldStr, which has been added with
+// AddSignatureField(), typically as an invisible field. stamp obtains the
+// timestamp from a time-stamping authority.
+//
+// Unlike a signature made with SignPDF(), a document timestamp requires no
+// certificate of its own: it is signed by the authority and proves that the
+// document existed, in its current form, at the time stated by the
+// authority. Like a signature, it is appended as an incremental update
+// (ETSI.RFC3161), so a signed document can be timestamped to extend the
+// validity of its signatures, optionally after adding validation data with
+// AddValidationData(). Encrypted documents cannot be timestamped.
+func TimestampPDF(pdf []byte, fieldStr string, stamp TimestampFunc) ([]byte, error) {
+	if stamp == nil {
+		return nil, fmt.Errorf("timestamping requires a time-stamping authority")
+	}
+	return signRevision(pdf, fieldStr, "/Type /DocTimeStamp /Filter /Adobe.PPKLite /SubFilter /ETSI.RFC3161",
+		"", timestampContentsSize, func(digest []byte) ([]byte, error) {
+			nonce, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 64))
+			if err != nil {
+				return nil, err
+			}
+			resp, err := stamp(timestampRequest(digest, nonce))
+			if err != nil {
+				return nil, err
+			}
+			return timestampToken(resp, digest, nonce)
+		})
+}
+
+// timestampRequest returns the DER encoding of a time-stamp request for the
+// SHA-256 digest digest, which asks for the certificate of the authority to
+// be included in the token
+func timestampRequest(digest []byte, nonce *big.Int) []byte {
+	return derSeq(derValue(1), derSeq(derSeq(derValue(oidSHA256)), derValue(digest)),
+		derValue(nonce), derValue(true))
+}
+
+// timestampToken returns the time-stamp token of the time-stamp response
+// resp after checking that the response grants a time stamp of the SHA-256
+// digest digest in answer to the request with the nonce nonce
+func timestampToken(resp, digest []byte, nonce *big.Int) ([]byte, error) {
+	var tsResp struct {
+		Status struct {
+			Status       int
+			StatusString []string       `asn1:"optional,utf8"`
+			FailInfo     asn1.BitString `asn1:"optional"`
+		}
+		Token asn1.RawValue `asn1:"optional"`
+	}
+	if _, err := asn1.Unmarshal(resp, &tsResp); err != nil {
+		return nil, fmt.Errorf("invalid time-stamp response: %s", err)
+	}
+	// Status 0 grants the request and 1 grants it with modifications
+	if st := tsResp.Status; st.Status > 1 || len(tsResp.Token.FullBytes) == 0 {
+		return nil, fmt.Errorf("time stamp not granted: status %d %q", st.Status, st.StatusString)
+	}
+	token := tsResp.Token.FullBytes
+	var info struct {
+		ContentType asn1.ObjectIdentifier
+		SignedData  struct {
+			Version          int
+			DigestAlgorithms asn1.RawValue
+			EncapContent     struct {
+				ContentType asn1.ObjectIdentifier
+				Content     []byte `asn1:"explicit,tag:0"`
+			}
+		} `asn1:"explicit,tag:0"`
+	}
+	var tst struct {
+		Version        int
+		Policy         asn1.ObjectIdentifier
+		MessageImprint struct {
+			HashAlgorithm pkix.AlgorithmIdentifier
+			HashedMessage []byte
+		}
+	}
+	if _, err := asn1.Unmarshal(token, &info); err != nil || !info.ContentType.Equal(oidSignedData) ||
+		!info.SignedData.EncapContent.ContentType.Equal(oidTSTInfo) {
+		return nil, fmt.Errorf("invalid time-stamp token")
+	}
+	if _, err := asn1.Unmarshal(info.SignedData.EncapContent.Content, &tst); err != nil {
+		return nil, fmt.Errorf("invalid time-stamp token: %s", err)
+	}
+	if imp := tst.MessageImprint; !imp.HashAlgorithm.Algorithm.Equal(oidSHA256) ||
+		!bytes.Equal(imp.HashedMessage, digest) {
+		return nil, fmt.Errorf("time stamp is not of the document")
+	}
+	// A token without the nonce of the request may be replayed from an
+	// earlier response
+	if n := tstNonce(info.SignedData.EncapContent.Content); n == nil || n.Cmp(nonce) != 0 {
+		return nil, fmt.Errorf("time stamp does not answer the request: nonce mismatch")
+	}
+	return token, nil
+}
+
+// tstNonce returns the nonce of the DER-encoded TSTInfo content, or nil if
+// it has none. The nonce is the only integer that follows the generation
+// time; the optional accuracy and ordering fields that may precede it are
+// skipped.
+func tstNonce(content []byte) *big.Int {
+	var seq asn1.RawValue
+	if _, err := asn1.Unmarshal(content, &seq); err != nil {
+		return nil
+	}
+	rest := seq.Bytes
+	// Version, policy, message imprint, serial number and generation time
+	// precede the optional fields
+	for j := 0; len(rest) > 0; j++ {
+		var el asn1.RawValue
+		var err error
+		if rest, err = asn1.Unmarshal(rest, &el); err != nil {
+			return nil
+		}
+		if j >= 5 && el.Class == asn1.ClassUniversal && el.Tag == asn1.TagInteger {
+			var n *big.Int
+			if _, err = asn1.Unmarshal(el.FullBytes, &n); err != nil {
+				return nil
+			}
+			return n
+		}
+	}
+	return nil
+}
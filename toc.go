@@ -0,0 +1,227 @@
+package gofpdf
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// TOCLevelType specifies the appearance of the entries of one level of the
+// table of contents printed by AddTOCPage().
+type TOCLevelType struct {
+	// Font of the entries; an empty family selects the font family of the
+	// table, and a zero size its font size, in points
+	FontFamily, FontStyle string
+	FontSize              float64
+	// Distance of the entries from the left margin
+	Indent float64
+	// Space above each entry
+	SpaceBefore float64
+}
+
+// tocType is the table of contents requested with AddTOCPage()
+type tocType struct {
+	at     int // number of the page after which the table is inserted
+	title  string
+	styles []TOCLevelType
+}
+
+// tocLineType is a line of an entry of the table of contents
+type tocLineType struct {
+	str   string
+	style TOCLevelType
+	dx    float64 // indent
+	ht    float64 // line height, including space before the entry
+	entry int     // index of the outline of the entry
+	last  bool    // the line ends its entry and carries the page number
+}
+
+// AddTOCPage arranges for a table of contents to be inserted after the
+// current page, or at the beginning of the document if no page has been
+// added yet. The table lists the headings recorded with Bookmark(), with the
+// text of each heading linked to its position and followed by dot leaders
+// and its page number. Because headings may be recorded up to the moment the
+// document is finished, the table is laid out when Close() is called,
+// explicitly or by one of the Output methods, and then moved to its place,
+// taking as many pages as it needs. The page numbers that it lists are those
+// of the final document.
+//
+// titleStr, if not empty, is printed at the top of the table. styles[j]
+// specifies the entries of level j; headings at levels for which no style
+// is given are left out, which limits the depth of the table. If no styles
+// are given, all levels are listed, indented by level, with the entries of
+// level 0 in bold. The table is printed with the font family and size that
+// are current when the document is closed, unless a style specifies
+// otherwise; if no font has been set, 10 point Helvetica is used. Text in a
+// UTF-8 font requires a UTF-8 font for the table.
+//
+// The footers of the pages that follow the table are printed once the table
+// is in place, so that page numbers printed by the footer function are those
+// of the final document. Headers, on the other hand, are printed as the
+// pages are generated, and the pages of the table are assumed to begin at
+// the same position below their headers. A table of contents cannot be
+// added to a document created with NewStreaming().
+func (f *Fpdf) AddTOCPage(titleStr string, styles ...TOCLevelType) {
+	if f.err != nil || f.streaming("adding a table of contents") {
+		return
+	}
+	if f.toc != nil {
+		f.SetErrorf("a table of contents has already been added")
+		return
+	}
+	f.toc = &tocType{at: len(f.pages) - 1, title: titleStr, styles: styles}
+}
+
+// tocDefersFooter returns true if the footer of the current page is printed
+// after the table of contents has been moved to its place
+func (f *Fpdf) tocDefersFooter() bool {
+	return f.toc != nil && f.page > f.toc.at
+}
+
+// putTOC prints the table of contents requested with AddTOCPage() on new
+// pages, moves them to their place and prints the footers that have been
+// deferred
+func (f *Fpdf) putTOC() {
+	toc := f.toc
+	prevFamilyStr, prevStyleStr := f.fontFamily, f.fontStyleStr()
+	familyStr, ptSize := f.fontFamily, f.fontSizePt
+	if familyStr == "" {
+		familyStr, ptSize = "Helvetica", 10
+	}
+	style := func(level int) (st TOCLevelType, ok bool) {
+		if len(toc.styles) == 0 {
+			st.Indent = float64(level) * 2 * ptSize / f.k
+			if level == 0 {
+				st.FontStyle = "B"
+				st.SpaceBefore = 0.5 * ptSize / f.k
+			}
+		} else if level < len(toc.styles) {
+			st = toc.styles[level]
+		} else {
+			return
+		}
+		if st.FontFamily == "" {
+			st.FontFamily = familyStr
+		}
+		if st.FontSize == 0 {
+			st.FontSize = ptSize
+		}
+		return st, true
+	}
+	last := len(f.pages) - 1
+	var lines []tocLineType
+	for j, o := range f.outlines {
+		st, ok := style(o.level)
+		if !ok {
+			continue
+		}
+		f.SetFont(st.FontFamily, st.FontStyle, st.FontSize)
+		if f.err != nil {
+			return
+		}
+		fontHt := st.FontSize / f.k
+		wd := f.w - f.lMargin - f.rMargin - st.Indent - 2*fontHt - f.GetStringWidth("0000")
+		list := f.indexWrap(o.str, wd)
+		for k, str := range list {
+			ln := tocLineType{str: str, style: st, dx: st.Indent, ht: 1.3 * fontHt, entry: j, last: k == len(list)-1}
+			if k == 0 {
+				ln.ht += st.SpaceBefore
+			} else {
+				ln.dx += 2 * fontHt
+			}
+			lines = append(lines, ln)
+		}
+	}
+	if len(lines) > 0 {
+		autoBreak := f.autoPageBreak
+		f.autoPageBreak = false
+		f.AddPage()
+		if f.err != nil {
+			return
+		}
+		top := f.y
+		if toc.title != "" {
+			f.SetFont(familyStr, "B", ptSize*1.6)
+			lineHt := 1.3 * ptSize / f.k
+			f.CellFormat(0, 1.6*lineHt, toc.title, "", 1, "L", false, 0, "")
+			f.Ln(lineHt)
+		}
+		// The first line of each page of the table, assuming that each page
+		// begins at top
+		y := f.y
+		pageStarts := []int{0}
+		for j, ln := range lines {
+			if y+ln.ht > f.pageBreakTrigger && j > pageStarts[len(pageStarts)-1] {
+				pageStarts = append(pageStarts, j)
+				y = top
+			}
+			y += ln.ht
+		}
+		n := len(pageStarts)
+		pageStr := func(p int) string {
+			if p > toc.at {
+				p += n
+			}
+			return strconv.Itoa(p)
+		}
+		links := make(map[int]int)
+		for j, ln := range lines {
+			if j > 0 && len(pageStarts) > 1 && j == pageStarts[1] {
+				pageStarts = pageStarts[1:]
+				f.AddPage()
+			}
+			o := f.outlines[ln.entry]
+			link, ok := links[ln.entry]
+			if !ok {
+				link = f.AddLink()
+				f.SetLink(link, o.y, o.p)
+				links[ln.entry] = link
+			}
+			st := ln.style
+			f.SetFont(st.FontFamily, st.FontStyle, st.FontSize)
+			fontHt := st.FontSize / f.k
+			if ln.ht > 1.3*fontHt {
+				f.y += ln.ht - 1.3*fontHt
+			}
+			lineHt := 1.3 * fontHt
+			x := f.lMargin + ln.dx
+			baseY := f.y + 0.5*lineHt + 0.3*fontHt
+			f.Text(x, baseY, ln.str)
+			right := f.w - f.rMargin
+			if ln.last {
+				numStr := pageStr(o.p)
+				numWd := f.GetStringWidth(numStr)
+				f.Text(right-numWd, baseY, numStr)
+				// Dot leaders aligned on a common grid, so that the dots of
+				// the entries form columns
+				dotWd := f.GetStringWidth(". ")
+				from := x + f.GetStringWidth(ln.str) + 0.5*fontHt
+				to := right - numWd - 0.5*fontHt
+				start := f.lMargin + math.Ceil((from-f.lMargin)/dotWd)*dotWd
+				if count := int((to - start) / dotWd); count > 0 {
+					f.Text(start, baseY, strings.Repeat(". ", count))
+				}
+			}
+			f.Link(x, f.y, right-x, lineHt, link)
+			f.y += lineHt
+		}
+		f.autoPageBreak = autoBreak
+		var pageList []int
+		for p := last + 1; p < len(f.pages); p++ {
+			pageList = append(pageList, p)
+		}
+		for p := toc.at + 1; p <= last; p++ {
+			pageList = append(pageList, p)
+		}
+		f.reorderPages(toc.at+1, pageList, len(f.outlines))
+		f.page = len(f.pages) - 1
+	}
+	f.toc = nil
+	for p := toc.at + 1; p < f.page && f.err == nil; p++ {
+		f.sectionFooter(p)
+	}
+	if prevFamilyStr != "" {
+		f.SetFont(prevFamilyStr, prevStyleStr, ptSize)
+	}
+	f.restatePage()
+}
@@ -0,0 +1,182 @@
+package gofpdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// updateType is an incremental update that is appended to a document
+// generated by this package, as SignPDF() and AddValidationData() do. The
+// objects of the update are written to buf, which begins with the document,
+// and the cross-reference section and trailer are written by finish().
+type updateType struct {
+	buf      bytes.Buffer
+	xref     int         // offset of the last cross-reference section
+	stream   bool        // the section is a cross-reference stream
+	size     int         // number of objects, including those of the update
+	root     int         // object number of the catalog
+	entryStr string      // root, information dictionary and file identifier
+	offsets  map[int]int // offsets of the objects of the update
+}
+
+var (
+	updateSizeRe  = regexp.MustCompile(`/Size (\d+)`)
+	updateRefRe   = regexp.MustCompile(`/(Root|Info) (\d+) \d+ R`)
+	updateIDRe    = regexp.MustCompile(`/ID\s*\[[^\]]*\]`)
+	updateStartRe = regexp.MustCompile(`startxref\s+(\d+)\s+%%EOF\s*$`)
+)
+
+// newUpdate returns an incremental update of the document pdf
+func newUpdate(pdf []byte) (u *updateType, err error) {
+	m := updateStartRe.FindSubmatch(pdf)
+	if m == nil {
+		return nil, fmt.Errorf("unable to update document: no cross-reference offset found")
+	}
+	u = &updateType{offsets: make(map[int]int)}
+	u.xref, _ = strconv.Atoi(string(m[1]))
+	if u.xref >= len(pdf) {
+		return nil, fmt.Errorf("unable to update document: invalid cross-reference offset")
+	}
+	dict := pdf[u.xref : len(pdf)-len(m[0])]
+	u.stream = !bytes.HasPrefix(dict, []byte("xref"))
+	if u.stream {
+		if pos := bytes.Index(dict, []byte("stream")); pos >= 0 {
+			dict = dict[:pos]
+		}
+	}
+	if bytes.Contains(dict, []byte("/Encrypt")) {
+		return nil, fmt.Errorf("encrypted documents cannot be updated")
+	}
+	if m = updateSizeRe.FindSubmatch(dict); m != nil {
+		u.size, _ = strconv.Atoi(string(m[1]))
+	}
+	refs := updateRefRe.FindAllSubmatch(dict, -1)
+	for _, ref := range refs {
+		if string(ref[1]) == "Root" {
+			u.root, _ = strconv.Atoi(string(ref[2]))
+		}
+		u.entryStr += " " + string(ref[0])
+	}
+	if u.size == 0 || u.root == 0 {
+		return nil, fmt.Errorf("unable to update document: invalid trailer")
+	}
+	if id := updateIDRe.Find(dict); id != nil {
+		u.entryStr += " " + string(id)
+	}
+	u.buf.Write(pdf)
+	if pdf[len(pdf)-1] != '\n' {
+		u.buf.WriteByte('\n')
+	}
+	return
+}
+
+// newobj returns the number of a new object of the update
+func (u *updateType) newobj() int {
+	u.size++
+	return u.size - 1
+}
+
+// begin records that object num begins at the current position and writes
+// its header
+func (u *updateType) begin(num int) {
+	u.offsets[num] = u.buf.Len()
+	fmt.Fprintf(&u.buf, "%d 0 obj\n", num)
+}
+
+// putobj writes object num with the value valStr
+func (u *updateType) putobj(num int, valStr string) {
+	u.begin(num)
+	fmt.Fprintf(&u.buf, "%s\nendobj\n", valStr)
+}
+
+// putstream writes a new stream object with the contents data, compressed,
+// and returns its number
+func (u *updateType) putstream(data []byte) int {
+	num := u.newobj()
+	data = sliceCompress(data)
+	u.begin(num)
+	fmt.Fprintf(&u.buf, "<</Filter /FlateDecode /Length %d>>\nstream\n", len(data))
+	u.buf.Write(data)
+	u.buf.WriteString("\nendstream\nendobj\n")
+	return num
+}
+
+// finish writes the cross-reference section and trailer of the update and
+// returns the updated document
+func (u *updateType) finish() []byte {
+	o := u.buf.Len()
+	if u.stream {
+		// A document that has a cross-reference stream is updated with one,
+		// which lists itself
+		u.offsets[u.newobj()] = o
+	}
+	nums := make([]int, 0, len(u.offsets))
+	for num := range u.offsets {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+	// Runs of consecutive object numbers
+	var index []int
+	for j, num := range nums {
+		if j > 0 && num == nums[j-1]+1 {
+			index[len(index)-1]++
+		} else {
+			index = append(index, num, 1)
+		}
+	}
+	if !u.stream {
+		u.buf.WriteString("xref\n")
+		k := 0
+		for j := 0; j < len(index); j += 2 {
+			fmt.Fprintf(&u.buf, "%d %d\n", index[j], index[j+1])
+			for end := k + index[j+1]; k < end; k++ {
+				fmt.Fprintf(&u.buf, "%010d 00000 n \n", u.offsets[nums[k]])
+			}
+		}
+		fmt.Fprintf(&u.buf, "trailer\n<</Size %d%s /Prev %d>>\n", u.size, u.entryStr, u.xref)
+	} else {
+		w := 1
+		for max := o; max > 0xff; max >>= 8 {
+			w++
+		}
+		var data bytes.Buffer
+		for _, num := range nums {
+			data.WriteByte(1)
+			for j := w - 1; j >= 0; j-- {
+				data.WriteByte(byte(u.offsets[num] >> uint(8*j)))
+			}
+			data.WriteByte(0)
+		}
+		var idx fmtBuffer
+		for j, v := range index {
+			if j > 0 {
+				idx.printf(" ")
+			}
+			idx.printf("%d", v)
+		}
+		fmt.Fprintf(&u.buf, "%d 0 obj\n<</Type /XRef /Size %d /Index [%s] /W [1 %d 1]%s /Prev %d /Length %d>>\n",
+			nums[len(nums)-1], u.size, idx.String(), w, u.entryStr, u.xref, data.Len())
+		fmt.Fprintf(&u.buf, "stream\n%s\nendstream\nendobj\n", data.Bytes())
+	}
+	fmt.Fprintf(&u.buf, "startxref\n%d\n%%%%EOF\n", o)
+	return u.buf.Bytes()
+}
+
+// updateObject returns the dictionary of the latest version of object num
+// of the document pdf, which must not be in an object stream
+func updateObject(pdf []byte, num int) ([]byte, error) {
+	re := regexp.MustCompile(`(?m)^` + strconv.Itoa(num) + ` 0 obj\s*<<`)
+	list := re.FindAllIndex(pdf, -1)
+	if len(list) > 0 {
+		start := list[len(list)-1][1] - 2
+		p := fdfParserType{data: pdf, pos: start}
+		p.value()
+		if p.err == nil {
+			return pdf[start:p.pos], nil
+		}
+	}
+	return nil, fmt.Errorf("unable to update document: object %d not found", num)
+}
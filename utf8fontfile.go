@@ -34,6 +34,11 @@ const symbol2x2 = 1 << 7
 type utf8FontFile struct {
 	fileReader           *fileReader
 	LastRune             int
+	fsType               int    // embedding permissions from the OS/2 table
+	numSymbols           int    // number of glyphs in the font
+	postScriptName       string // from the name table
+	isCFF                bool   // glyphs are PostScript (CFF) outlines
+	err                  error  // first problem found in the font file
 	tableDescriptions    map[string]*tableDescription
 	outTablesData        map[string][]byte
 	symbolPosition       []int
@@ -52,7 +57,10 @@ type utf8FontFile struct {
 	DefaultWidth         float64
 	symbolData           map[int]map[string][]int
 	CodeSymbolDictionary map[int]int
-	ToUnicodeCMap        string // Dynamic ToUnicode CMap for PDF embedding
+	ToUnicodeCMap        string               // Dynamic ToUnicode CMap for PDF embedding
+	glyphCIDs            map[int]glyphCIDType // CID -> glyph substituted by text shaping, set before cutting
+	otLayout             *otLayoutType        // layout tables for text shaping, read on first use
+	otLayoutDone         bool                 // whether the layout tables have been read
 }
 
 type tableDescription struct {
@@ -84,6 +92,14 @@ func (fr *fileReader) seek(shift int64, flag int) (int64, error) {
 	return int64(fr.readerPosition), nil
 }
 
+// setErrorf records a problem found in the font file, unless one has been
+// recorded already
+func (utf *utf8FontFile) setErrorf(fmtStr string, args ...interface{}) {
+	if utf.err == nil {
+		utf.err = fmt.Errorf(fmtStr, args...)
+	}
+}
+
 func newUTF8Font(reader *fileReader) *utf8FontFile {
 	utf := utf8FontFile{
 		fileReader: reader,
@@ -99,19 +115,18 @@ func (utf *utf8FontFile) parseFile() error {
 	utf.outTablesData = make(map[string][]byte)
 	utf.Ascent = 0
 	utf.Descent = 0
+	utf.err = nil
 	codeType := uint32(utf.readUint32())
-	if codeType == 0x4F54544F {
-		return fmt.Errorf("not supported\n ")
-	}
+	utf.isCFF = codeType == 0x4F54544F
 	if codeType == 0x74746366 {
-		return fmt.Errorf("not supported\n ")
+		return fmt.Errorf("font collections are not supported")
 	}
-	if codeType != 0x00010000 && codeType != 0x74727565 {
-		return fmt.Errorf("Not a TrueType font: codeType=%v\n ", codeType)
+	if codeType != 0x00010000 && codeType != 0x74727565 && !utf.isCFF {
+		return fmt.Errorf("not a TrueType font: codeType=%v", codeType)
 	}
 	utf.generateTableDescriptions()
 	utf.parseTables()
-	return nil
+	return utf.err
 }
 
 func (utf *utf8FontFile) generateTableDescriptions() {
@@ -278,7 +293,7 @@ func (utf *utf8FontFile) parseNAMETable() int {
 	namePosition := utf.SeekTable("name")
 	format := utf.readUint16()
 	if format != 0 {
-		fmt.Printf("Illegal format %d\n", format)
+		// The names are informational only
 		return format
 	}
 	nameCount := utf.readUint16()
@@ -301,7 +316,7 @@ func (utf *utf8FontFile) parseNAMETable() int {
 			oldPos := utf.fileReader.readerPosition
 			utf.seek(stringDataPosition + position)
 			if size%2 != 0 {
-				fmt.Printf("name is not binar byte format\n")
+				// Malformed UTF-16 name
 				return format
 			}
 			size /= 2
@@ -327,6 +342,7 @@ func (utf *utf8FontFile) parseNAMETable() int {
 			}
 		}
 	}
+	utf.postScriptName = names[6]
 	return format
 }
 
@@ -345,7 +361,7 @@ func (utf *utf8FontFile) parseHEADTable() {
 	_ = utf.readUint16()
 	symbolDataFormat := utf.readUint16()
 	if symbolDataFormat != 0 {
-		fmt.Printf("Unknown symbol data format %d\n", symbolDataFormat)
+		utf.setErrorf("unknown glyph data format %d", symbolDataFormat)
 		return
 	}
 }
@@ -363,12 +379,12 @@ func (utf *utf8FontFile) parseHHEATable() int {
 		utf.skip(24)
 		metricDataFormat := utf.readUint16()
 		if metricDataFormat != 0 {
-			fmt.Printf("Unknown horizontal metric data format %d\n", metricDataFormat)
+			utf.setErrorf("unknown horizontal metric data format %d", metricDataFormat)
 			return 0
 		}
 		metricsCount = utf.readUint16()
 		if metricsCount == 0 {
-			fmt.Printf("Number of horizontal metrics is 0\n")
+			utf.setErrorf("number of horizontal metrics is 0")
 			return 0
 		}
 	}
@@ -384,11 +400,8 @@ func (utf *utf8FontFile) parseOS2Table() int {
 		utf.skip(2)
 		weightType = utf.readUint16()
 		utf.skip(2)
-		fsType := utf.readUint16()
-		if fsType == 0x0002 || (fsType&0x0300) != 0 {
-			fmt.Printf("ERROR - copyright restrictions.\n")
-			return 0
-		}
+		// Embedding restrictions are applied when the font is output
+		utf.fsType = utf.readUint16()
 		utf.skip(20)
 		_ = utf.readInt16()
 
@@ -474,27 +487,58 @@ func (utf *utf8FontFile) parseCMAPTable(format int) int {
 		return format12Position
 	}
 	if cidCMAPPosition == 0 {
-		fmt.Printf("Font does not have cmap for Unicode\n")
+		utf.setErrorf("font does not have cmap for Unicode")
 		return cidCMAPPosition
 	}
 	return cidCMAPPosition
 }
 
+// requiredTables are the tables that a font must have to be embedded
+var requiredTables = []string{"name", "head", "hhea", "post", "cmap", "maxp", "hmtx", "loca", "glyf"}
+
+// requiredCFFTables are the tables that a font with PostScript outlines must
+// have to be embedded
+var requiredCFFTables = []string{"name", "head", "hhea", "post", "cmap", "maxp", "hmtx", "CFF "}
+
+// hasRequiredTables returns true if the font has all of the required tables,
+// and otherwise records an error
+func (utf *utf8FontFile) hasRequiredTables() bool {
+	list := requiredTables
+	if utf.isCFF {
+		list = requiredCFFTables
+	}
+	for _, name := range list {
+		if _, ok := utf.tableDescriptions[name]; !ok {
+			utf.setErrorf("font does not have a %s table", name)
+			return false
+		}
+	}
+	return true
+}
+
 func (utf *utf8FontFile) parseTables() {
+	if !utf.hasRequiredTables() {
+		return
+	}
 	f := utf.parseNAMETable()
 	utf.parseHEADTable()
 	n := utf.parseHHEATable()
 	w := utf.parseOS2Table()
 	utf.parsePOSTTable(w)
 	runeCMAPPosition := utf.parseCMAPTable(f)
+	if utf.err != nil {
+		return
+	}
 
 	utf.SeekTable("maxp")
 	utf.skip(4)
 	numSymbols := utf.readUint16()
+	utf.numSymbols = numSymbols
 
 	symbolCharDictionary := make(map[int][]int)
 	charSymbolDictionary := make(map[int]int)
 	utf.generateSCCSDictionaries(runeCMAPPosition, symbolCharDictionary, charSymbolDictionary)
+	utf.charSymbolDictionary = charSymbolDictionary
 
 	scale := 1000.0 / float64(utf.fontElementSize)
 	utf.parseHMTXTable(n, numSymbols, symbolCharDictionary, scale)
@@ -531,7 +575,7 @@ func (utf *utf8FontFile) generateCMAP() map[int][]int {
 		runeCmapPosition = format12Position
 	}
 	if runeCmapPosition == 0 {
-		fmt.Printf("Font does not have cmap for Unicode\n")
+		utf.setErrorf("font does not have cmap for Unicode")
 		return nil
 	}
 
@@ -555,6 +599,11 @@ func (utf *utf8FontFile) parseSymbols(usedRunes map[int]int) (map[int]int, map[i
 		}
 		utf.LastRune = max(utf.LastRune, char)
 	}
+	for _, g := range utf.glyphCIDs {
+		if _, OK := symbolCollection[g.gid]; !OK && g.gid < utf.numSymbols {
+			symbolCollection[g.gid] = 0
+		}
+	}
 
 	begin := utf.tableDescriptions["glyf"].position
 
@@ -623,6 +672,12 @@ func (utf *utf8FontFile) GenerateCutFont(usedRunes map[int]int) []byte {
 	utf.skip(4)
 	utf.LastRune = 0
 	utf.generateTableDescriptions()
+	if !utf.hasRequiredTables() {
+		return nil
+	}
+	if utf.isCFF {
+		return utf.generateCutCFF(usedRunes, false)
+	}
 
 	utf.SeekTable("head")
 	utf.skip(50)
@@ -663,7 +718,13 @@ func (utf *utf8FontFile) GenerateCutFont(usedRunes map[int]int) []byte {
 			maxCID = cid
 		}
 	}
-	utf.ToUnicodeCMap = generateToUnicodeCMap(cidToUnicode)
+	for cid, g := range utf.glyphCIDs {
+		if glyph, ok := symbolArray[g.gid]; ok {
+			cidToGlyph[cid] = glyph
+		}
+		maxCID = max(maxCID, cid)
+	}
+	utf.ToUnicodeCMap = generateToUnicodeCMapText(cidToUnicode, utf.glyphText())
 	utf.CodeSymbolDictionary = cidToGlyph
 	utf.LastRune = maxCID
 
@@ -782,6 +843,9 @@ func (utf *utf8FontFile) GenerateCutFont(usedRunes map[int]int) []byte {
 	os2Data := utf.getTableData("OS/2")
 	utf.setOutTable("OS/2", os2Data)
 
+	if utf.err != nil {
+		return nil
+	}
 	return utf.assembleTables()
 }
 
@@ -907,7 +971,7 @@ func (utf *utf8FontFile) parseLOCATable(format, numSymbols int) {
 			utf.symbolPosition = append(utf.symbolPosition, arr[n+1])
 		}
 	} else {
-		fmt.Printf("Unknown loca table format %d\n", format)
+		utf.setErrorf("unknown loca table format %d", format)
 		return
 	}
 }
@@ -922,7 +986,7 @@ func (utf *utf8FontFile) parseCmapFormat12(offset int) (map[int][]int, map[int]i
 	// Read Format 12 header
 	format := utf.readUint16()
 	if format != 12 {
-		fmt.Printf("Expected CMAP format 12, got %d\n", format)
+		utf.setErrorf("expected cmap format 12, got %d", format)
 		return symbolCharDictionary, charSymbolDictionary
 	}
 
@@ -941,7 +1005,7 @@ func (utf *utf8FontFile) parseCmapFormat12(offset int) (map[int][]int, map[int]i
 	// Validate length
 	expectedLength := 16 + 12*numGroups
 	if length != expectedLength {
-		fmt.Printf("Invalid CMAP Format 12 length: got %d, expected %d\n", length, expectedLength)
+		utf.setErrorf("invalid cmap format 12 length: got %d, expected %d", length, expectedLength)
 		return symbolCharDictionary, charSymbolDictionary
 	}
 
@@ -1032,7 +1096,7 @@ func (utf *utf8FontFile) generateSCCSDictionaries(runeCmapPosition int, symbolCh
 			}
 		}
 	} else {
-		fmt.Printf("Unsupported CMAP format: %d\n", format)
+		utf.setErrorf("unsupported cmap format %d", format)
 	}
 }
 
@@ -1203,7 +1267,8 @@ func keySortArrayRangeMap(s map[int][]int) []int {
 
 // UTF8CutFont is a utility function that generates a TrueType font composed
 // only of the runes included in cutset. The rune glyphs are copied from This
-// function is demonstrated in ExampleUTF8CutFont().
+// function is demonstrated in ExampleUTF8CutFont(). Nil is returned if inBuf
+// is not a font that can be processed.
 func UTF8CutFont(inBuf []byte, cutset string) (outBuf []byte) {
 	f := newUTF8Font(&fileReader{readerPosition: 0, array: inBuf})
 	runes := map[int]int{}
@@ -1315,7 +1380,24 @@ func writeCmapFormat12Header(numGroups uint32) []byte {
 // For efficiency, consecutive character codes are combined into ranges using bfrange.
 // Identity mapping is used (CID == Unicode value).
 func generateToUnicodeCMap(cidToUnicode map[int]int) string {
-	if len(cidToUnicode) == 0 {
+	return generateToUnicodeCMapText(cidToUnicode, nil)
+}
+
+// glyphText returns the text of the glyphs substituted by text shaping,
+// keyed by their CIDs
+func (utf *utf8FontFile) glyphText() map[int][]int {
+	cidText := make(map[int][]int, len(utf.glyphCIDs))
+	for cid, g := range utf.glyphCIDs {
+		cidText[cid] = g.runes
+	}
+	return cidText
+}
+
+// generateToUnicodeCMapText is generateToUnicodeCMap for a font with glyphs
+// that represent several characters, whose text cidText maps by CID.
+// Glyphs without text are left out.
+func generateToUnicodeCMapText(cidToUnicode map[int]int, cidText map[int][]int) string {
+	if len(cidToUnicode)+len(cidText) == 0 {
 		return `/CIDInit /ProcSet findresource begin
 12 dict begin
 begincmap
@@ -1343,16 +1425,24 @@ end`
 			maxCID = cid
 		}
 	}
+	for cid := range cidText {
+		maxCID = max(maxCID, cid)
+	}
 
 	use4Byte := maxCID > 0xFFFF
 
-	cids := make([]int, 0, len(cidToUnicode))
+	cids := make([]int, 0, len(cidToUnicode)+len(cidText))
 	for cid := range cidToUnicode {
 		if cid == 0 {
 			continue
 		}
 		cids = append(cids, cid)
 	}
+	for cid, text := range cidText {
+		if _, ok := cidToUnicode[cid]; !ok && cid > 0 && len(text) > 0 {
+			cids = append(cids, cid)
+		}
+	}
 	sort.Ints(cids)
 
 	total := len(cids)
@@ -1407,6 +1497,14 @@ begincmap
 		}
 		cmap.WriteString(fmt.Sprintf("%d beginbfchar\n", end-i))
 		for _, cid := range cids[i:end] {
+			if text, ok := cidText[cid]; ok {
+				var hexStr string
+				for _, r := range text {
+					hexStr += formatUnicodeHex(r)
+				}
+				cmap.WriteString(fmt.Sprintf("<%s> <%s>\n", formatCIDHex(cid, use4Byte), hexStr))
+				continue
+			}
 			unicode := cidToUnicode[cid]
 			cmap.WriteString(fmt.Sprintf("<%s> <%s>\n", formatCIDHex(cid, use4Byte), formatUnicodeHex(unicode)))
 		}
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"io/ioutil"
 	"testing"
 )
 
@@ -796,3 +797,201 @@ func BenchmarkGenerateToUnicodeCMapLarge(b *testing.B) {
 		generateToUnicodeCMap(usedRunes)
 	}
 }
+
+// TestGenerateCutCFF tests the conversion of a font with PostScript outlines
+// to a CID-keyed CFF subset
+func TestGenerateCutCFF(t *testing.T) {
+	data, err := ioutil.ReadFile("font/CFFTest.otf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	utf := newUTF8Font(&fileReader{array: data})
+	if err = utf.parseFile(); err != nil {
+		t.Fatal(err)
+	}
+	orig, err := parseCFF(utf.getTableData("CFF "))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The font has no glyph for 'A'
+	usedRunes := map[int]int{0x30: '0', 0x41: 'A', 0x51: 'Q', 0x4E2D: 0x4E2D}
+	for _, full := range []bool{false, true} {
+		cffData := utf.generateCutCFF(usedRunes, full)
+		sub, err := parseCFF(cffData)
+		if err != nil || utf.err != nil {
+			t.Fatalf("full %v: %v %v", full, err, utf.err)
+		}
+		if sub.fontDicts == nil {
+			t.Fatalf("full %v: subset is not CID-keyed", full)
+		}
+		count := 4
+		if full {
+			count = len(orig.charStrings)
+		}
+		if len(sub.charStrings) != count {
+			t.Fatalf("full %v: %d glyphs, expected %d", full, len(sub.charStrings), count)
+		}
+		charset, _ := cffDictInt(sub.top, cffOpCharset, 0)
+		if cffData[charset] != 0 {
+			t.Fatalf("full %v: charset format %d, expected 0", full, cffData[charset])
+		}
+		for cid, r := range usedRunes {
+			gid, ok := utf.CodeSymbolDictionary[cid]
+			if r == 'A' {
+				if ok {
+					t.Errorf("full %v: unexpected glyph for U+0041", full)
+				}
+				continue
+			}
+			if !ok || gid == 0 {
+				t.Fatalf("full %v: no glyph for U+%04X", full, r)
+			}
+			pos := charset + 1 + 2*(gid-1)
+			if got := int(binary.BigEndian.Uint16(cffData[pos:])); got != cid {
+				t.Errorf("full %v: glyph %d has CID %d, expected %d", full, gid, got, cid)
+			}
+			origGid := utf.charSymbolDictionary[r]
+			if !bytes.Equal(sub.charStrings[gid], orig.charStrings[origGid]) {
+				t.Errorf("full %v: charstring of U+%04X differs", full, r)
+			}
+		}
+	}
+}
+
+// TestTextShaping tests the substitution of ligatures and the positioning of
+// marks with the layout tables of a font, and the mapping of ligatures back
+// to their text
+func TestTextShaping(t *testing.T) {
+	pdf := New("P", "mm", "A4", "")
+	pdf.SetCompression(false)
+	pdf.AddUTF8Font("dejavu", "", "font/DejaVuSansCondensed.ttf")
+	pdf.SetFont("dejavu", "", 12)
+	pdf.AddPage()
+	plainWd := pdf.GetStringWidth("office")
+	pdf.SetTextShaping(true)
+	if wd := pdf.GetStringWidth("office"); wd >= plainWd {
+		t.Errorf("shaped width %.2f, expected less than %.2f", wd, plainWd)
+	}
+	glyphs, ok := pdf.shapeCurrent("office")
+	if !ok || len(glyphs) != 4 {
+		t.Fatalf("shaped %d glyphs, expected 4 with the ffi ligature", len(glyphs))
+	}
+	glyphs, _ = pdf.shapeCurrent("e\u0301")
+	if len(glyphs) != 2 || glyphs[1].adv != 0 || glyphs[1].dx == 0 {
+		t.Errorf("combining acute accent is not positioned: %v", glyphs)
+	}
+	pdf.Cell(0, 10, "office e\u0301")
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("<D800> <006600660069>")) {
+		t.Errorf("ToUnicode CMap does not map the ffi ligature to its text")
+	}
+}
+
+// TestKerning checks that kerning narrows pairs such as "AV" with the GPOS
+// pair adjustments of the font, and that the kerning table agrees with them
+func TestKerning(t *testing.T) {
+	pdf := New("P", "mm", "A4", "")
+	pdf.AddUTF8Font("dejavu", "", "font/DejaVuSansCondensed.ttf")
+	pdf.SetFont("dejavu", "", 12)
+	pdf.AddPage()
+	plainWd := pdf.GetStringWidth("AV To")
+	pdf.SetKerning(true)
+	if wd := pdf.GetStringWidth("AV To"); wd >= plainWd {
+		t.Errorf("kerned width %.2f, expected less than %.2f", wd, plainWd)
+	}
+	lay := pdf.fonts[getFontKey("dejavu", "")].utf8File.layout()
+	runes := []rune("AVAT To Ye")
+	kerned := shapeRun(lay, shapeScriptLatn, runes, false, true)
+	table := make([]otGlyphType, len(runes))
+	for j, r := range runes {
+		table[j] = otGlyphType{gid: lay.cmap[int(r)], adv: lay.advance(lay.cmap[int(r)])}
+	}
+	lay.kernTable(table)
+	for j := range runes {
+		if kerned[j].adv != table[j].adv {
+			t.Errorf("advance of %q is %d with GPOS and %d with the kerning table", runes[j], kerned[j].adv, table[j].adv)
+		}
+	}
+	if kerned[0].adv >= lay.advance(kerned[0].gid) {
+		t.Errorf("pair AV is not kerned")
+	}
+	pdf.Cell(0, 10, "AV To")
+	if err := pdf.Output(ioutil.Discard); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestShapeSyllables tests the reordering of the characters of Indic and
+// Khmer syllables before substitution and the placement of the reph after it
+func TestShapeSyllables(t *testing.T) {
+	glyphs := func(runes ...int) (buf []otGlyphType) {
+		for _, r := range runes {
+			buf = append(buf, otGlyphType{runes: []int{r}})
+		}
+		return
+	}
+	order := func(buf []otGlyphType) (list []int) {
+		for _, g := range buf {
+			list = append(list, g.runes...)
+		}
+		return
+	}
+	for _, c := range []struct {
+		str      string
+		in, out  []int
+		rphf     bool
+		rephMask bool
+	}{
+		// Devanagari KA with vowel sign I, which is shown before it
+		{"ki", []int{0x915, 0x93F}, []int{0x93F, 0x915}, true, false},
+		// RA and virama before KA form a reph; vowel sign I moves to the
+		// front of the syllable
+		{"rki", []int{0x930, 0x94D, 0x915, 0x93F}, []int{0x93F, 0x930, 0x94D, 0x915}, true, true},
+		// Without the rphf feature RA takes its half form
+		{"rk", []int{0x930, 0x94D, 0x915}, []int{0x930, 0x94D, 0x915}, false, false},
+		// Khmer KA with coeng Ro and vowel sign E: the vowel comes first,
+		// then the coeng Ro
+		{"kre", []int{0x1780, 0x17D2, 0x179A, 0x17C1}, []int{0x17C1, 0x17D2, 0x179A, 0x1780}, true, false},
+		// Two syllables are reordered separately
+		{"ki ki", []int{0x915, 0x93F, ' ', 0x915, 0x93F}, []int{0x93F, 0x915, ' ', 0x93F, 0x915}, true, false},
+	} {
+		buf := glyphs(c.in...)
+		shapeSyllables(buf, shapeScriptOf(c.in[0]), c.rphf)
+		if got := order(buf); fmt.Sprint(got) != fmt.Sprint(c.out) {
+			t.Errorf("%s: order %X, expected %X", c.str, got, c.out)
+		}
+		var reph bool
+		for _, g := range buf {
+			if g.runes[0] == 0x930 && g.mask&otFeatRphf != 0 {
+				reph = true
+			}
+		}
+		if reph != c.rephMask {
+			t.Errorf("%s: RA marked for reph %v, expected %v", c.str, reph, c.rephMask)
+		}
+	}
+
+	// The reph formed by substitution moves after the base consonant and
+	// its virama
+	sc := shapeScriptOf(0x915)
+	buf := glyphs(0x930, 0x94D, 0x915, 0x94D, 0x937, 0x93F)
+	shapeSyllables(buf, sc, true)
+	var base int
+	for _, g := range buf {
+		if g.base {
+			base = g.runes[0]
+		}
+	}
+	if base != 0x937 {
+		t.Fatalf("base consonant %X, expected 937", base)
+	}
+	// Substitution leaves the vowel sign, the reph, KA, virama and SSA
+	buf = []otGlyphType{buf[0], {runes: []int{0x930, 0x94D}, reph: true, syl: 1}, buf[3], buf[4], buf[5]}
+	shapeMoveReph(buf, sc)
+	if got, want := order(buf), []int{0x93F, 0x915, 0x94D, 0x937, 0x930, 0x94D}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("reph moved to %X, expected %X", got, want)
+	}
+}
@@ -477,3 +477,19 @@ func fontFamilyEscape(familyStr string) (escStr string) {
 	// Additional replacements can take place here
 	return
 }
+
+// unitScale returns the number of points in one unitStr, the unit of measure
+// accepted by New()
+func unitScale(unitStr string) (k float64, ok bool) {
+	switch unitStr {
+	case "pt", "point":
+		return 1.0, true
+	case "mm":
+		return 72.0 / 25.4, true
+	case "cm":
+		return 72.0 / 2.54, true
+	case "in", "inch":
+		return 72.0, true
+	}
+	return 0, false
+}
@@ -17,8 +17,10 @@
 package gofpdf
 
 import (
+	"bytes"
 	"encoding/hex"
 	"fmt"
+	"math"
 	"strings"
 	"testing"
 )
@@ -372,3 +374,189 @@ func TestDynamicToUnicodeCMap(t *testing.T) {
 		})
 	}
 }
+
+// TestFloatPrecisionArgs tests the reformatting of real numbers in page
+// content by SetFloatPrecision
+func TestFloatPrecisionArgs(t *testing.T) {
+	tests := []struct {
+		fmtStr   string
+		args     []interface{}
+		decimals int
+		expected string
+	}{
+		{"%.2f %.2f m", []interface{}{10.0, 20.456}, 1, "10 20.5 m"},
+		{"%.5f %.5f l", []interface{}{1.23456, -0.00001}, 3, "1.235 0 l"},
+		{"%.2f w", []interface{}{0.5}, 4, "0.5 w"},
+		{"BT /F%s %.2f Tf ET", []interface{}{"1", 12.0}, 2, "BT /F1 12 Tf ET"},
+		{"%d %.3f%% (%s) %.2f", []interface{}{3, 50.25, "x", 7.0}, 0, "3 50% (x) 7"},
+		{"%5.2f %.2f", []interface{}{1.5, 2.25}, 1, " 1.50 2.2"},
+	}
+	for _, tt := range tests {
+		fmtStr, args := floatPrecisionArgs(tt.fmtStr, tt.args, tt.decimals)
+		if got := fmt.Sprintf(fmtStr, args...); got != tt.expected {
+			t.Errorf("floatPrecisionArgs(%q, %d) formats %q, expected %q", tt.fmtStr, tt.decimals, got, tt.expected)
+		}
+	}
+}
+
+// TestSVGPathParse tests the parsing of SVG path data into absolute segments
+func TestSVGPathParse(t *testing.T) {
+	tests := []struct {
+		pathStr string
+		cmds    string
+		end     PointType
+	}{
+		{"M10 20L30 40", "ML", PointType{30, 40}},
+		{"m10,20 5,5 h10 v-5z", "MLLLZ", PointType{10, 20}},
+		{"M0 0c1.5.5-1-2 3 4s2 2 2 2", "MCC", PointType{5, 6}},
+		{"M0 0Q5 5 10 0T20 0", "MCC", PointType{20, 0}},
+		{"M0 0a10 10 0 0 1 20 0", "MCC", PointType{20, 0}},
+		{"M0 0a10 10 0 1120 0", "MCC", PointType{20, 0}},
+		{"M0 0L1e1 1E-1 x 5", "ML", PointType{10, 0.1}},
+	}
+	for _, tt := range tests {
+		var p svgPathType
+		svgPathParse(&p, tt.pathStr)
+		var cmds []byte
+		for _, seg := range p.segs {
+			cmds = append(cmds, seg.cmd)
+		}
+		if string(cmds) != tt.cmds {
+			t.Errorf("path %q has segments %s, expected %s", tt.pathStr, cmds, tt.cmds)
+		}
+		if dx, dy := p.cur.X-tt.end.X, p.cur.Y-tt.end.Y; dx*dx+dy*dy > 1e-12 {
+			t.Errorf("path %q ends at (%g, %g), expected (%g, %g)", tt.pathStr, p.cur.X, p.cur.Y, tt.end.X, tt.end.Y)
+		}
+	}
+	for valStr, expected := range map[string]RGBType{
+		"#1a2B3c":          {0x1a, 0x2b, 0x3c},
+		"#f80":             {0xff, 0x88, 0x00},
+		"rgb(10, 20, 30)":  {10, 20, 30},
+		"rgb(100%,50%,0%)": {255, 128, 0},
+		"CornflowerBlue":   {100, 149, 237},
+	} {
+		if clr, ok := svgColor(valStr, RGBType{}); !ok || clr != expected {
+			t.Errorf("color %q is %v, expected %v", valStr, clr, expected)
+		}
+	}
+}
+
+// TestLabToRGB checks that labToRGB() inverts srgbLab(), both of which use
+// the D50 white point of the Lab color space
+func TestLabToRGB(t *testing.T) {
+	for _, rgb := range [][3]float64{{1, 1, 1}, {0, 0, 1}, {1, 0, 0}, {0.2, 0.6, 0.3}, {1, 1, 0}} {
+		l, a, b := srgbLab(rgb[0], rgb[1], rgb[2])
+		r, g, bl := labToRGB(l, a, b)
+		if math.Abs(r-rgb[0]) > 0.002 || math.Abs(g-rgb[1]) > 0.002 || math.Abs(bl-rgb[2]) > 0.002 {
+			t.Errorf("%v converts to Lab (%.2f, %.2f, %.2f) and back to (%.4f, %.4f, %.4f)", rgb, l, a, b, r, g, bl)
+		}
+	}
+}
+
+// TestBarcodeEncodings checks the symbols of Code 128 and EAN-13 barcodes
+// and the codewords and format information of QR codes against known
+// answers
+func TestBarcodeEncodings(t *testing.T) {
+	for _, c := range []struct {
+		codeStr string
+		vals    []int
+	}{
+		// Code set B with the check symbol 88
+		{"Wikipedia", []int{104, 55, 73, 75, 73, 80, 69, 68, 73, 65, 88, 106}},
+		// Digits in pairs with code set C
+		{"1234", []int{105, 12, 34, 82, 106}},
+		// An odd run of digits begins in code set B
+		{"AB12345", []int{104, 33, 34, 17, 99, 23, 45, 7, 106}},
+		// Control characters in code set A
+		{"\tA", []int{103, 73, 33, 36, 106}},
+	} {
+		vals, err := code128Values(c.codeStr)
+		if err != nil || fmt.Sprint(vals) != fmt.Sprint(c.vals) {
+			t.Errorf("Code 128 %q: %v %v, expected %v", c.codeStr, vals, err, c.vals)
+		}
+	}
+
+	// 4006381333931, with the first digit 4 encoded in the parities LGLLGG
+	want := "101" + "0001101" + "0100111" + "0101111" + "0111101" + "0001001" + "0110011" +
+		"01010" + "1000010" + "1000010" + "1000010" + "1110100" + "1000010" + "1100110" + "101"
+	for _, codeStr := range []string{"400638133393", "4006381333931"} {
+		modules, err := ean13Modules(codeStr)
+		var b strings.Builder
+		for _, dark := range modules {
+			if dark {
+				b.WriteByte('1')
+			} else {
+				b.WriteByte('0')
+			}
+		}
+		if err != nil || b.String() != want {
+			t.Errorf("EAN-13 %s: %s %v, expected %s", codeStr, b.String(), err, want)
+		}
+	}
+	if _, err := ean13Modules("4006381333932"); err == nil {
+		t.Errorf("EAN-13 with an invalid check digit accepted")
+	}
+
+	// HELLO WORLD in a version 1 QR code with error correction level Q
+	bits := qrData("HELLO WORLD", qrMode("HELLO WORLD"), 1)
+	var b strings.Builder
+	for _, bit := range bits {
+		if bit {
+			b.WriteByte('1')
+		} else {
+			b.WriteByte('0')
+		}
+	}
+	if want := "0010" + "000001011" + "01100001011" + "01111000110" + "10001011100" +
+		"10110111000" + "10011010100" + "001101"; b.String() != want {
+		t.Errorf("QR data bits %s, expected %s", b.String(), want)
+	}
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236}
+	ec := []byte{168, 72, 22, 82, 217, 54, 156, 0, 46, 15, 180, 122, 16}
+	if cw := qrCodewords(data, 1, qrLevels["Q"]); !bytes.Equal(cw, append(append([]byte{}, data...), ec...)) {
+		t.Errorf("QR codewords %v, expected %v followed by %v", cw, data, ec)
+	}
+
+	// Format information, read from the modules beside the upper left finder
+	// pattern, most significant bit first
+	format := func(dark [][]bool) (bits int) {
+		var list [][2]int
+		for x := 0; x < 6; x++ {
+			list = append(list, [2]int{x, 8})
+		}
+		list = append(list, [2]int{7, 8}, [2]int{8, 8}, [2]int{8, 7})
+		for y := 5; y >= 0; y-- {
+			list = append(list, [2]int{8, y})
+		}
+		for _, p := range list {
+			bits <<= 1
+			if dark[p[1]][p[0]] {
+				bits |= 1
+			}
+		}
+		return
+	}
+	for _, c := range []struct {
+		level, mask, bits int
+	}{
+		{qrLevels["L"], 0, 0x77C4}, // 111011111000100
+		{qrLevels["M"], 0, 0x5412}, // 101010000010010
+		{qrLevels["H"], 0, 0x1689}, // 001011010001001
+	} {
+		m := qrMatrixType{size: 21, dark: make([][]bool, 21), function: make([][]bool, 21)}
+		for j := range m.dark {
+			m.dark[j], m.function[j] = make([]bool, 21), make([]bool, 21)
+		}
+		m.putFormat(c.level, c.mask)
+		if bits := format(m.dark); bits != c.bits {
+			t.Errorf("QR format of level %d and mask %d: %015b, expected %015b", c.level, c.mask, bits, c.bits)
+		}
+	}
+	dark, err := qrMatrix("HELLO WORLD", qrLevels["Q"])
+	if err != nil || len(dark) != 21 {
+		t.Fatalf("HELLO WORLD in a QR code of %d modules: %v, expected 21", len(dark), err)
+	}
+	if level := (format(dark) ^ 0x5412) >> 13; level != qrLevelBits[qrLevels["Q"]] {
+		t.Errorf("QR code of level Q has level bits %02b", level)
+	}
+}
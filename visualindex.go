@@ -0,0 +1,183 @@
+package gofpdf
+
+import (
+	"math"
+	"strconv"
+)
+
+// visualIndexType holds the state of the visual index requested with
+// GenerateVisualIndex()
+type visualIndexType struct {
+	pending bool
+	title   string
+	columns int
+	pages   []int // final numbers of the pages shown as thumbnails
+	objs    []int // object numbers of the thumbnail form XObjects
+}
+
+// GenerateVisualIndex arranges for a visual index, a contact sheet of
+// scaled-down images of the pages of the document, to be placed at the start
+// of the document. Like the index of GenerateIndex(), which appears in the
+// visual index if both are requested, it is laid out when Close() is called,
+// explicitly or by one of the Output methods. The pages are shown in a grid
+// of the given number of columns, a value less than one being treated as
+// four, each with its page number below it, and each is a link to the page
+// it shows.
+//
+// The index pages have the default size and orientation of the document and
+// are printed without header, footer or running head. They are front matter:
+// the captions give the numbers that the pages had before the index was
+// inserted, which are the numbers printed by footers that use PageNo(). The
+// number of pages substituted for the alias set with AliasNbPages(), on the
+// other hand, includes the index pages. titleStr, if not empty, is printed
+// at the top of the first index page. The index is printed with the font
+// family and size that are current when the document is closed; if no font
+// has been set, 10 point Helvetica is used.
+func (f *Fpdf) GenerateVisualIndex(titleStr string, columns int) {
+	if f.streaming("a visual index") {
+		return
+	}
+	if columns < 1 {
+		columns = 4
+	}
+	f.visualIndex = visualIndexType{pending: true, title: titleStr, columns: columns}
+}
+
+// visualIndexPageSize returns the size of page n in points
+func (f *Fpdf) visualIndexPageSize(n int) SizeType {
+	if sz, ok := f.pageSizes[n]; ok {
+		return sz
+	}
+	if f.defOrientation == "P" {
+		return SizeType{f.defPageSize.Wd * f.k, f.defPageSize.Ht * f.k}
+	}
+	return SizeType{f.defPageSize.Ht * f.k, f.defPageSize.Wd * f.k}
+}
+
+// putVisualIndex prints the visual index requested with GenerateVisualIndex()
+// on new pages, which are then moved to the start of the document. It is
+// called after the last page of the document has been ended.
+func (f *Fpdf) putVisualIndex() {
+	vi := &f.visualIndex
+	vi.pending = false
+	nb := f.page
+	familyStr, ptSize := f.fontFamily, f.fontSizePt
+	if familyStr == "" {
+		familyStr, ptSize = "Helvetica", 10
+	}
+	// The document is being closed, so these are not restored
+	f.headerFnc, f.footerFnc, f.footerFncLpi = nil, nil, nil
+	f.runningHeadFnc, f.pageEvents = nil, nil
+	f.autoPageBreak = false
+	f.AddPage()
+	if f.err != nil {
+		return
+	}
+	first := f.page
+	fontHt := ptSize / f.k
+	lineHt := 1.3 * fontHt
+	gap := 2 * fontHt
+	cols := vi.columns
+	cellWd := (f.w - f.lMargin - f.rMargin - float64(cols-1)*gap) / float64(cols)
+	ratio := 0.0
+	for n := 1; n <= nb; n++ {
+		sz := f.visualIndexPageSize(n)
+		ratio = math.Max(ratio, sz.Ht/sz.Wd)
+	}
+	boxHt := cellWd * ratio
+	rowHt := boxHt + lineHt + gap
+	titleHt := 0.0
+	if vi.title != "" {
+		titleHt = 2.6 * lineHt
+	}
+	// The number of index pages determines the final page numbers
+	rowsPerPage := func(avail float64) int {
+		return int(math.Max(1, math.Floor((avail+gap)/rowHt)))
+	}
+	firstRows := rowsPerPage(f.pageBreakTrigger - f.tMargin - titleHt)
+	rows := rowsPerPage(f.pageBreakTrigger - f.tMargin)
+	count := 1
+	for left := (nb+cols-1)/cols - firstRows; left > 0; left -= rows {
+		count++
+	}
+	if vi.title != "" {
+		f.SetFont(familyStr, "B", ptSize*1.6)
+		f.CellFormat(0, 1.6*lineHt, vi.title, "", 1, "L", false, 0, "")
+		f.Ln(lineHt)
+	}
+	f.SetFont(familyStr, "", ptSize)
+	f.SetDrawColor(128, 128, 128)
+	f.SetLineWidth(0.5 / f.k)
+	top := f.y
+	row, pageRows := 0, firstRows
+	vi.pages = vi.pages[:0]
+	for n := 1; n <= nb; n++ {
+		col := (n - 1) % cols
+		if col == 0 && n > 1 {
+			row++
+			if row == pageRows {
+				f.AddPage()
+				top, row, pageRows = f.y, 0, rows
+			}
+		}
+		x := f.lMargin + float64(col)*(cellWd+gap)
+		y := top + float64(row)*rowHt
+		sz := f.visualIndexPageSize(n)
+		scale := math.Min(cellWd*f.k/sz.Wd, boxHt*f.k/sz.Ht)
+		wd, ht := sz.Wd*scale/f.k, sz.Ht*scale/f.k
+		x0, y0 := x+(cellWd-wd)/2, y+(boxHt-ht)/2
+		// The page content assumes the initial graphics state
+		f.outf("q 0 G 0 g %s %.2f %.2f cm /VI%d Do Q", fixedf("%.5f 0 0 %.5f", scale, scale),
+			x0*f.k, (f.h-y0-ht)*f.k, n)
+		f.Rect(x0, y0, wd, ht, "D")
+		f.SetXY(x, y+boxHt)
+		f.CellFormat(cellWd, lineHt, strconv.Itoa(n), "", 0, "C", false, 0, "")
+		link := f.AddLink()
+		f.SetLink(link, 0, n)
+		f.Link(x0, y0, wd, ht, link)
+		vi.pages = append(vi.pages, n+count)
+	}
+	f.endpage()
+	if f.page-first+1 != count {
+		f.SetErrorf("visual index occupies %d pages rather than %d", f.page-first+1, count)
+		return
+	}
+	pageList := make([]int, 0, f.page)
+	for n := first; n <= f.page; n++ {
+		pageList = append(pageList, n)
+	}
+	for n := 1; n <= nb; n++ {
+		pageList = append(pageList, n)
+	}
+	f.reorderPages(1, pageList, len(f.outlines))
+}
+
+// visualIndexPutPages writes the form XObjects that show the pages of the
+// document in the visual index
+func (f *Fpdf) visualIndexPutPages() {
+	vi := &f.visualIndex
+	vi.objs = vi.objs[:0]
+	for _, n := range vi.pages {
+		sz := f.visualIndexPageSize(n)
+		buf := f.pages[n].Bytes()
+		filter := ""
+		if f.compress {
+			filter = "/Filter /FlateDecode "
+			buf = sliceCompress(buf)
+		}
+		f.newobj()
+		vi.objs = append(vi.objs, f.n)
+		f.outf("<</Type /XObject /Subtype /Form /BBox [0 0 %.2f %.2f] /Resources 2 0 R %s/Length %d>>",
+			sz.Wd, sz.Ht, filter, len(buf))
+		f.putstream(buf)
+		f.out("endobj")
+	}
+}
+
+// visualIndexPutDict writes the names of the visual index XObjects to the
+// resource dictionary
+func (f *Fpdf) visualIndexPutDict() {
+	for j, obj := range f.visualIndex.objs {
+		f.outf("/VI%d %d 0 R", j+1, obj)
+	}
+}